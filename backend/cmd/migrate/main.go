@@ -0,0 +1,115 @@
+// Command migrate applies and reverts the database schema migrations, for running from
+// CI or by hand. It reads the database settings the same way the server does.
+//
+//	migrate up          apply every pending migration
+//	migrate down N      revert the last N migrations
+//	migrate version     print the current schema version
+//	migrate force V     record V as applied without running it
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/radhi1991/aran-mcp-sentinel/internal/config"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"go.uber.org/zap"
+)
+
+const usage = `usage: migrate <command>
+
+commands:
+  up          apply every pending migration
+  down N      revert the last N migrations
+  version     print the current schema version
+  force V     record V as applied without running it, clearing a dirty schema`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Fatal("Failed to load configuration", zap.Error(err))
+	}
+
+	dbConn, err := database.NewConnection(database.Config{
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		User:     cfg.Database.User,
+		Password: cfg.Database.Password,
+		DBName:   cfg.Database.Name,
+		SSLMode:  cfg.Database.SSLMode,
+	}, logger)
+	if err != nil {
+		logger.Fatal("Failed to connect to database", zap.Error(err))
+	}
+	defer dbConn.Close()
+
+	if err := run(dbConn, os.Args[1:]); err != nil {
+		logger.Error("Migration failed", zap.Error(err))
+		dbConn.Close()
+		logger.Sync()
+		os.Exit(1)
+	}
+}
+
+// run executes a migrate command
+func run(dbConn *database.Connection, args []string) error {
+	switch args[0] {
+	case "up":
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return dbConn.MigrateUp(ctx)
+
+	case "down":
+		n, err := intArg(args)
+		if err != nil {
+			return err
+		}
+		return dbConn.MigrateDown(n)
+
+	case "version":
+		version, dirty, err := dbConn.MigrationVersion()
+		if err != nil {
+			return err
+		}
+		if dirty {
+			fmt.Printf("%d (dirty)\n", version)
+		} else {
+			fmt.Println(version)
+		}
+		return nil
+
+	case "force":
+		version, err := intArg(args)
+		if err != nil {
+			return err
+		}
+		return dbConn.ForceMigrationVersion(version)
+
+	default:
+		return fmt.Errorf("unknown command %q\n%s", args[0], usage)
+	}
+}
+
+// intArg parses the number following a command
+func intArg(args []string) (int, error) {
+	if len(args) != 2 {
+		return 0, fmt.Errorf("%s takes one number\n%s", args[0], usage)
+	}
+	n, err := strconv.Atoi(args[1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q: %w", args[1], err)
+	}
+	return n, nil
+}
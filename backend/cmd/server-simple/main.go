@@ -16,10 +16,10 @@ import (
 	"github.com/radhi1991/aran-mcp-sentinel/internal/discovery"
 	"github.com/radhi1991/aran-mcp-sentinel/internal/mcp"
 	"github.com/radhi1991/aran-mcp-sentinel/internal/monitoring"
-	"github.com/radhi1991/aran-mcp-sentinel/internal/registry"
 	"github.com/radhi1991/aran-mcp-sentinel/internal/repository"
 	"github.com/radhi1991/aran-mcp-sentinel/internal/security"
 	"github.com/radhi1991/aran-mcp-sentinel/internal/supabase"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/wellknown"
 	"go.uber.org/zap"
 )
 
@@ -49,6 +49,10 @@ func main() {
 		Password: cfg.Database.Password,
 		DBName:   cfg.Database.Name,
 		SSLMode:  cfg.Database.SSLMode,
+
+		MaxOpenConns:    cfg.Database.MaxOpenConns,
+		MaxIdleConns:    cfg.Database.MaxIdleConns,
+		ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
 	}
 
 	dbConn, err := database.NewConnection(dbConfig, logger)
@@ -57,6 +61,11 @@ func main() {
 	}
 	defer dbConn.Close()
 
+	// Bring the schema up to date before serving requests
+	if err := dbConn.MigrateUp(context.Background()); err != nil {
+		logger.Fatal("Failed to migrate database", zap.Error(err))
+	}
+
 	// Initialize repository
 	repo := database.NewRepository(dbConn.DB, logger)
 
@@ -104,6 +113,28 @@ func main() {
 		})
 	})
 
+	// Connection pool statistics, for spotting pool exhaustion
+	r.GET("/health/db-pool", func(c *gin.Context) {
+		stats := dbConn.PoolStats()
+		c.JSON(http.StatusOK, gin.H{
+			"max_open_connections": stats.MaxOpenConnections,
+			"open_connections":     stats.OpenConnections,
+			"idle":                 stats.Idle,
+			"in_use":               stats.InUse,
+			"wait_count":           stats.WaitCount,
+			"wait_duration":        stats.WaitDuration.String(),
+			"wait_duration_ms":     stats.WaitDuration.Milliseconds(),
+		})
+	})
+
+	// Public discovery documents for other systems
+	wellKnownHandler := wellknown.NewHandler(repo, logger)
+	wellKnownHandler.SetPublishServers(cfg.Discovery.PublishServers)
+	if err := wellKnownHandler.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		logger.Fatal("Invalid trusted proxies", zap.Error(err))
+	}
+	wellKnownHandler.RegisterRoutes(r)
+
 	// API v1 routes
 	api := r.Group("/api/v1")
 	{
@@ -141,9 +172,8 @@ func main() {
 		discoveryHandler := discovery.NewDiscoveryHandler(logger, legacyRepo)
 		discoveryHandler.RegisterRoutes(api)
 
-		// Registry endpoints (no auth required for testing)
-		registryHandler := registry.NewRegistryHandler(logger, legacyRepo)
-		registryHandler.RegisterRoutes(api)
+		// Registry endpoints need an authenticated organization and are served by
+		// cmd/server only
 	}
 
 	// Create HTTP server
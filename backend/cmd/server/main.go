@@ -7,21 +7,35 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
-	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/grandcat/zeroconf"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/apikeys"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/auditlogs"
 	"github.com/radhi1991/aran-mcp-sentinel/internal/auth"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/cache"
 	"github.com/radhi1991/aran-mcp-sentinel/internal/config"
 	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/discovery"
 	"github.com/radhi1991/aran-mcp-sentinel/internal/mcp"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/mcpapi"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/metrics"
 	"github.com/radhi1991/aran-mcp-sentinel/internal/middleware"
 	"github.com/radhi1991/aran-mcp-sentinel/internal/monitoring"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/notifications"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/onboarding"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/organizations"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/registry"
 	"github.com/radhi1991/aran-mcp-sentinel/internal/repository"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/scheduler"
 	"github.com/radhi1991/aran-mcp-sentinel/internal/security"
 	"github.com/radhi1991/aran-mcp-sentinel/internal/supabase"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/users"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/wellknown"
 	"go.uber.org/zap"
 )
 
@@ -29,6 +43,8 @@ func main() {
 	// Initialize logger
 	logger, _ := zap.NewProduction()
 	defer logger.Sync()
+	// Code without a request-scoped logger logs through the global one
+	zap.ReplaceGlobals(logger)
 
 	// Load configuration
 	cfg, err := config.Load()
@@ -51,6 +67,10 @@ func main() {
 		Password: cfg.Database.Password,
 		DBName:   cfg.Database.Name,
 		SSLMode:  cfg.Database.SSLMode,
+
+		MaxOpenConns:    cfg.Database.MaxOpenConns,
+		MaxIdleConns:    cfg.Database.MaxIdleConns,
+		ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
 	}
 
 	dbConn, err := database.NewConnection(dbConfig, logger)
@@ -59,9 +79,46 @@ func main() {
 	}
 	defer dbConn.Close()
 
+	// Bring the schema up to date before serving requests
+	if err := dbConn.MigrateUp(context.Background()); err != nil {
+		logger.Fatal("Failed to migrate database", zap.Error(err))
+	}
+
 	// Initialize repository
 	repo := database.NewRepository(dbConn.DB, logger)
 
+	// Use a read replica for analytics queries when configured
+	metricsRepo := repo
+	if cfg.Database.ReadReplicaHost != "" {
+		replicaConfig := dbConfig
+		replicaConfig.Host = cfg.Database.ReadReplicaHost
+		replicaConfig.Port = cfg.Database.ReadReplicaPort
+
+		replicaConn, err := database.NewConnection(replicaConfig, logger)
+		if err != nil {
+			logger.Fatal("Failed to connect to read replica", zap.Error(err))
+		}
+		defer replicaConn.Close()
+
+		metricsRepo = database.NewRepository(replicaConn.DB, logger)
+	}
+
+	// Initialize Redis for quota counters; executions are counted in the database without it
+	var quotaCounter mcp.QuotaCounter
+	if cfg.Redis.Host != "" {
+		redisClient := cache.NewRedisClient(cache.RedisConfig{
+			Addr:     fmt.Sprintf("%s:%d", cfg.Redis.Host, cfg.Redis.Port),
+			Password: cfg.Redis.Password,
+			DB:       cfg.Redis.DB,
+		})
+		defer redisClient.Close()
+
+		if err := redisClient.Ping(context.Background()); err != nil {
+			logger.Warn("Redis unavailable, falling back to database quota counts", zap.Error(err))
+		}
+		quotaCounter = redisClient
+	}
+
 	// JWT manager removed - using Authelia for authentication
 
 	// Initialize Supabase client (for legacy compatibility)
@@ -73,56 +130,76 @@ func main() {
 	// Initialize legacy MCP repository
 	legacyRepo := repository.NewMCPServerRepository(supabaseClient)
 
+	// Configure MCP connection pooling
+	if cfg.MCP.MaxConnsPerMCPServer > 0 {
+		mcp.MaxConnsPerMCPServer = cfg.MCP.MaxConnsPerMCPServer
+	}
+	mcpProtocol := mcp.NewMCPProtocolWithTransport(logger, cfg.MCP.MaxIdleConnsPerServer, cfg.MCP.ResponseHeaderTimeoutSeconds)
+
+	// Configure real-time alert streaming
+	if cfg.Monitoring.MaxAlertBuffer > 0 {
+		monitoring.MaxAlertBuffer = cfg.Monitoring.MaxAlertBuffer
+	}
+
+	// Behavioral profiles of tool callers, persisted so anomaly history survives restarts
+	var behavioralAnalyzer *security.BehavioralAnalyzer
+	var enhancedHandler *mcpapi.EnhancedHandler
+	var nightlyOWASPScan *security.NightlyOWASPScan
+
 	// Initialize Gin router
 	r := gin.New()
 
+	metricsRegistry := metrics.NewRegistry()
+
+	// In-flight tool executions and health check probes may finish during shutdown
+	shutdownCoordinator := middleware.NewShutdownCoordinator()
+
+	// Latest health score of each server, kept up to date by the health checks and read
+	// by the registry
+	healthScoreCache := registry.NewHealthScoreCache(repo, logger)
+
+	// Periodic health checks of every server, started once the server is listening
+	healthChecker := monitoring.NewHealthChecker(repo, logger)
+	healthChecker.SetHealthScoreCache(healthScoreCache)
+
+	// Health scores of every check are buffered in memory and flushed periodically
+	healthScores := monitoring.NewHealthScoreBuffer(cfg.Monitoring.HealthScoreBufferCapacity)
+
 	// Add security middleware
+	r.Use(metricsRegistry.Middleware())
+	r.Use(shutdownCoordinator.Middleware())
 	r.Use(middleware.ErrorHandler(logger))
 	r.Use(middleware.RequestLogger(logger))
 	r.Use(middleware.SecurityHeaders())
-	r.Use(middleware.RequestValidator())
-
-	// Add rate limiting (100 requests per minute by default)
-	rateLimit := 100
-	if rateLimitStr := os.Getenv("RATE_LIMIT_REQUESTS_PER_MINUTE"); rateLimitStr != "" {
-		if parsed, err := strconv.Atoi(rateLimitStr); err == nil {
-			rateLimit = parsed
-		}
+	maxBodyBytes := cfg.Server.MaxRequestBodyBytes
+	if maxBodyBytes <= 0 {
+		maxBodyBytes = middleware.DefaultMaxBodyBytes
 	}
-	r.Use(middleware.RateLimiter(rateLimit, logger))
-
-	// Add secure CORS middleware
-	allowedOrigins := os.Getenv("CORS_ALLOWED_ORIGINS")
-	if allowedOrigins == "" {
-		allowedOrigins = "http://localhost:3000"
-	}
-
-	r.Use(func(c *gin.Context) {
-		origin := c.Request.Header.Get("Origin")
-
-		// Check if origin is allowed
-		allowed := false
-		for _, allowedOrigin := range strings.Split(allowedOrigins, ",") {
-			if strings.TrimSpace(allowedOrigin) == origin {
-				allowed = true
-				break
-			}
+	r.Use(middleware.BodySizeLimit(maxBodyBytes))
+	// Ansible inventories are uploaded as multipart forms
+	r.Use(middleware.EnforceContentType("application/json", "multipart/form-data"))
+	r.Use(middleware.PaginationMiddleware())
+
+	// Add secure CORS middleware; CORS_ALLOWED_ORIGINS is used when no origins are configured
+	corsOrigins := cfg.CORS.AllowedOrigins
+	if len(corsOrigins) == 0 {
+		allowedOrigins := os.Getenv("CORS_ALLOWED_ORIGINS")
+		if allowedOrigins == "" {
+			allowedOrigins = "http://localhost:3000"
 		}
-
-		if allowed {
-			c.Header("Access-Control-Allow-Origin", origin)
+		for _, origin := range strings.Split(allowedOrigins, ",") {
+			corsOrigins = append(corsOrigins, strings.TrimSpace(origin))
 		}
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
-		c.Header("Access-Control-Allow-Credentials", "true")
+	}
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
+	// Runtime config shared by all instances; stored values override the environment
+	configSync := config.NewConfigSync(repo, dbConfig.DSN(), corsOrigins, logger)
+	if err := configSync.Load(context.Background()); err != nil {
+		logger.Warn("Failed to load runtime config", zap.Error(err))
+	}
 
-		c.Next()
-	})
+	corsViolations := monitoring.NewCORSViolationLogger(repo, metricsRegistry, logger)
+	r.Use(middleware.CORSMiddleware(cfg.CORS, configSync.AllowsOrigin, corsViolations))
 
 	// Health check endpoint
 	r.GET("/health", func(c *gin.Context) {
@@ -141,8 +218,41 @@ func main() {
 		})
 	})
 
+	// Connection pool statistics, for spotting pool exhaustion
+	r.GET("/health/db-pool", func(c *gin.Context) {
+		stats := dbConn.PoolStats()
+		c.JSON(http.StatusOK, gin.H{
+			"max_open_connections": stats.MaxOpenConnections,
+			"open_connections":     stats.OpenConnections,
+			"idle":                 stats.Idle,
+			"in_use":               stats.InUse,
+			"wait_count":           stats.WaitCount,
+			"wait_duration":        stats.WaitDuration.String(),
+			"wait_duration_ms":     stats.WaitDuration.Milliseconds(),
+		})
+	})
+
+	// Public discovery documents for other systems
+	wellKnownHandler := wellknown.NewHandler(repo, logger)
+	wellKnownHandler.SetPublishServers(cfg.Discovery.PublishServers)
+	if err := wellKnownHandler.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		logger.Fatal("Invalid trusted proxies", zap.Error(err))
+	}
+	wellKnownHandler.SetAuthEndpoints(wellknown.AuthEndpoints{
+		JWKSURI:               cfg.Clerk.JWKSURL,
+		IntrospectionEndpoint: cfg.OIDC.IntrospectionURL,
+	})
+	wellKnownHandler.RegisterRoutes(r)
+
 	// API v1 routes
 	api := r.Group("/api/v1")
+
+	// Add rate limiting per client IP (100 req/min by default)
+	ipRateLimit := cfg.Security.RateLimit
+	if ipRateLimit <= 0 {
+		ipRateLimit = 100
+	}
+	api.Use(middleware.RateLimiter(ipRateLimit, cfg.Security.RateLimitBurst))
 	{
 		// Authentication endpoints (no auth required)
 		authHandler := auth.NewAutheliaHandler(logger)
@@ -151,22 +261,41 @@ func main() {
 		// Protected routes (require authentication)
 		protected := api.Group("/")
 		// Choose authentication middleware based on configuration
+		introspectionCacheTTL := time.Minute
+		if cfg.OIDC.CacheTTLSeconds > 0 {
+			introspectionCacheTTL = time.Duration(cfg.OIDC.CacheTTLSeconds) * time.Second
+		}
 		useClerk := false
 		if os.Getenv("USE_CLERK_AUTH") == "true" || cfg.Clerk.JWKSURL != "" {
 			useClerk = true
 		}
+		var authMiddleware gin.HandlerFunc
 		if useClerk {
 			logger.Info("Using Clerk middleware for authentication")
-			protected.Use(func(c *gin.Context) {
-				// placeholder - actual Clerk middleware is registered below
-				c.Next()
-			})
-			// Register Clerk middleware with proper settings
-			protected.Use(auth.ClerkMiddleware(cfg.Clerk.JWKSURL, cfg.Clerk.Issuer, cfg.Clerk.Audience, logger))
+			var introspector *auth.TokenIntrospector
+			if cfg.OIDC.IntrospectionURL != "" {
+				introspector = auth.NewTokenIntrospector(cfg.OIDC.IntrospectionURL, cfg.OIDC.ClientID, cfg.OIDC.ClientSecret, introspectionCacheTTL, logger)
+			}
+			authMiddleware = auth.ClerkMiddlewareWithIntrospection(cfg.Clerk.JWKSURL, cfg.Clerk.Issuer, cfg.Clerk.Audience, introspector, logger)
+		} else if cfg.OIDC.IntrospectionURL != "" {
+			logger.Info("Using OIDC token introspection for authentication")
+			authMiddleware = auth.OIDCIntrospectionMiddleware(cfg.OIDC.IntrospectionURL, cfg.OIDC.ClientID, cfg.OIDC.ClientSecret, introspectionCacheTTL, logger)
 		} else {
 			// Use Authelia middleware for authentication
-			protected.Use(auth.AutheliaMiddleware(logger))
+			authMiddleware = auth.AutheliaMiddleware(logger)
+		}
+		// Requests with an X-API-Key header authenticate against the stored key hashes instead
+		productionMiddleware := middleware.NewProductionMiddleware(logger)
+		productionMiddleware.SetAPIKeyVerifier(apikeys.NewVerifier(repo, logger))
+		protected.Use(productionMiddleware.APIKeyOr(authMiddleware))
+		// Limit each authenticated key or user (1000 req/min by default)
+		keyRateLimit := cfg.Security.APIKeyRateLimit
+		if keyRateLimit <= 0 {
+			keyRateLimit = 1000
 		}
+		protected.Use(middleware.APIKeyRateLimiter(keyRateLimit, cfg.Security.APIKeyRateLimitBurst))
+		// Scope every protected request to the caller's organization
+		protected.Use(auth.OrgContextMiddleware(repo, logger))
 		{
 			// MCP endpoints
 			mcpGroup := protected.Group("/mcp")
@@ -176,16 +305,111 @@ func main() {
 			mcpHandler.RegisterRoutes(mcpGroup)
 
 			// Initialize enhanced MCP handler with real functionality
-			enhancedHandler := mcp.NewEnhancedHandler(dbConn.DB, logger)
+			enhancedHandler = mcpapi.NewEnhancedHandler(dbConn.DB.DB, logger, monitoring.CircuitBreakerConfig{
+				FailureThreshold: cfg.Monitoring.CircuitBreakerFailureThreshold,
+				OpenDuration:     time.Duration(cfg.Monitoring.CircuitBreakerOpenMinutes) * time.Minute,
+			})
+			enhancedHandler.SetProtocol(mcpProtocol)
+			enhancedHandler.SetExecutionQuota(quotaCounter, repo)
+			enhancedHandler.SetMetrics(metricsRegistry)
+			enhancedHandler.SetShutdownCoordinator(shutdownCoordinator)
+			enhancedHandler.SetHealthScoreBuffer(healthScores)
+			enhancedHandler.SetHealthScoreCache(healthScoreCache)
+			enhancedHandler.SetDefaultToolQuota(cfg.Tools.DefaultHourlyQuota)
+			enhancedHandler.SetMaxConcurrentExecutions(cfg.Tools.MaxConcurrentExecutions)
+			if err := enhancedHandler.SetToolPolicy(mcp.ToolPolicy{
+				AllowedPatterns: cfg.Tools.AllowedPatterns,
+				DeniedPatterns:  cfg.Tools.DeniedPatterns,
+			}); err != nil {
+				logger.Fatal("Invalid tool policy", zap.Error(err))
+			}
+			enhancedHandler.SetHealthChecker(healthChecker)
+			if cfg.Discovery.EnableMDNS {
+				enhancedHandler.EnableMDNSDiscovery()
+			}
+			if cfg.MCP.ToolCacheTTLSeconds > 0 {
+				enhancedHandler.SetToolCacheTTL(time.Duration(cfg.MCP.ToolCacheTTLSeconds) * time.Second)
+			}
+			if cfg.Monitoring.AlertDedupMinutes > 0 {
+				enhancedHandler.SetAlertDedupWindow(time.Duration(cfg.Monitoring.AlertDedupMinutes) * time.Minute)
+			}
+			behavioralAnalyzer = enhancedHandler.BehavioralAnalyzer()
+			behavioralAnalyzer.SetProfileStore(security.NewPostgresProfileStore(dbConn.DB.DB), logger)
+			behavioralAnalyzer.RetentionPeriod = time.Duration(cfg.Security.ProfileRetentionDays) * 24 * time.Hour
 			enhancedHandler.RegisterEnhancedRoutes(mcpGroup)
+			enhancedHandler.RegisterToolRoutes(protected)
+
+			// Tool cache hit rate, alongside the other unauthenticated health endpoints
+			r.GET("/health/cache-stats", func(c *gin.Context) {
+				c.JSON(http.StatusOK, enhancedHandler.ToolCacheStats())
+			})
+
+			// Security scorecards, shown on their own and on the dashboard
+			scorecardService := security.NewSecurityScorecardService(repo, logger)
+			scorecardHandler := security.NewScorecardHandler(scorecardService, logger)
+			scorecardHandler.RegisterRoutes(protected)
 
 			// Monitoring endpoints
 			monitoringHandler := monitoring.NewHandler(repo, logger)
+			monitoringHandler.SetScorecardService(scorecardService)
 			monitoringHandler.RegisterRoutes(protected)
 
+			// Registry endpoints
+			registryHandler := registry.NewRegistryHandler(logger, legacyRepo, repo, metricsRepo)
+			registryHandler.SetHealthScoreCache(healthScoreCache)
+			registryHandler.SetServerProbe(func(ctx context.Context, serverURL string) error {
+				if err := mcpProtocol.Ping(ctx, serverURL); err != nil {
+					return err
+				}
+				_, err := mcpProtocol.Initialize(ctx, serverURL)
+				return err
+			}, cfg.Registry.ValidateOnRegister == nil || *cfg.Registry.ValidateOnRegister)
+			registryHandler.RegisterRoutes(protected)
+
 			// Security testing endpoints
 			securityHandler := security.NewHandler(logger)
+			securityHandler.SetRepository(repo)
+			if cfg.Security.PromptInjectionThreshold > 0 {
+				securityHandler.PromptDetector().Threshold = cfg.Security.PromptInjectionThreshold
+			}
+			if cfg.Security.PluginDir != "" {
+				if err := securityHandler.LoadOWASPPlugins(cfg.Security.PluginDir); err != nil {
+					logger.Error("Failed to load security test plugins", zap.Error(err))
+				}
+			}
 			securityHandler.RegisterRoutes(protected)
+
+			nightlyOWASPScan = security.NewNightlyOWASPScan(securityHandler.OWASPManager(), repo, enhancedHandler.Monitor(), logger)
+			if cfg.Security.OWASPScoreThreshold > 0 {
+				nightlyOWASPScan.Threshold = cfg.Security.OWASPScoreThreshold
+			}
+
+			scheduledScanHandler := security.NewScheduledScanHandler(repo, logger)
+			scheduledScanHandler.RegisterRoutes(protected)
+
+			// Onboarding endpoints
+			onboardingHandler := onboarding.NewHandler(repo, logger)
+			onboardingHandler.RegisterRoutes(protected)
+
+			// Organization settings endpoints
+			organizationsHandler := organizations.NewHandler(repo, logger)
+			organizationsHandler.RegisterRoutes(protected)
+
+			// User preference endpoints
+			usersHandler := users.NewHandler(repo, logger)
+			usersHandler.RegisterRoutes(protected)
+
+			// API key endpoints
+			apiKeysHandler := apikeys.NewHandler(repo, logger)
+			apiKeysHandler.RegisterRoutes(protected)
+
+			// Audit log endpoints (admin only)
+			auditLogsHandler := auditlogs.NewHandler(repo, logger)
+			auditLogsHandler.RegisterRoutes(protected)
+
+			// Runtime config endpoints (admin only)
+			configHandler := config.NewHandler(configSync, logger)
+			configHandler.RegisterRoutes(protected.Group("/", auth.RBACMiddleware(auth.RoleAdmin, logger)))
 		}
 	}
 
@@ -206,14 +430,120 @@ func main() {
 		}
 	}()
 
+	// Advertise the sentinel on the local network
+	var mdnsServer *zeroconf.Server
+	if cfg.Discovery.EnableMDNS {
+		instance := cfg.Discovery.MDNSInstanceName
+		if instance == "" {
+			instance, _ = os.Hostname()
+		}
+		if instance == "" {
+			instance = "aran-mcp-sentinel"
+		}
+		txt := []string{"path=/api/v1"}
+		if cfg.Discovery.PublishServers {
+			txt = append(txt, "wellknown=/.well-known/mcp-servers")
+		}
+		mdnsServer, err = discovery.AdvertiseSentinel(instance, cfg.Server.Port, txt)
+		if err != nil {
+			logger.Warn("Failed to advertise sentinel over mDNS", zap.Error(err))
+		} else {
+			logger.Info("Advertising sentinel over mDNS", zap.String("instance", instance), zap.String("service", discovery.SentinelServiceType))
+		}
+	}
+
+	// Start the metrics server on its own port so telemetry is not exposed with the API
+	var metricsServer *http.Server
+	if cfg.Server.MetricsPort > 0 {
+		metricsHost := cfg.Server.MetricsHost
+		if metricsHost == "" {
+			metricsHost = "127.0.0.1"
+		}
+		metricsServer = metrics.NewServer(fmt.Sprintf("%s:%d", metricsHost, cfg.Server.MetricsPort), metricsRegistry)
+
+		go func() {
+			logger.Info("Starting metrics server", zap.String("address", metricsServer.Addr))
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Error("Metrics server error", zap.Error(err))
+			}
+		}()
+	}
+
 	// Start periodic health checks
-	healthChecker := monitoring.NewHealthChecker(repo, logger)
 	healthCtx, healthCancel := context.WithCancel(context.Background())
 	defer healthCancel()
 
 	go healthChecker.StartPeriodicHealthChecks(healthCtx, 30*time.Second)
 	logger.Info("Started periodic health checks", zap.Duration("interval", 30*time.Second))
 
+	smtpChannel := notifications.NewSMTPNotificationChannel(notifications.SMTPConfig{
+		Host:     cfg.Notifications.SMTPHost,
+		Port:     cfg.Notifications.SMTPPort,
+		Username: cfg.Notifications.SMTPUsername,
+		Password: cfg.Notifications.SMTPPassword,
+		From:     cfg.Notifications.SMTPFrom,
+	})
+
+	// Start alert digest notifications
+	if cfg.Notifications.DigestEnabled {
+		digestJob := notifications.NewDigestJob(repo, smtpChannel, cfg.Notifications.DigestHour, logger)
+		go digestJob.Start(healthCtx)
+	}
+
+	// Start scheduled security scans
+	scanScheduler := security.NewScanScheduler(repo, smtpChannel, logger)
+	go scanScheduler.Start(healthCtx)
+
+	// Listen for runtime config changes made on any instance
+	go configSync.Start(healthCtx)
+
+	// Write counted CORS violations to the audit log
+	go corsViolations.Start(healthCtx)
+
+	// Remove behavioral profiles of inactive agents
+	go behavioralAnalyzer.StartCleanup(healthCtx, time.Hour)
+
+	// Start alert escalation
+	pager := monitoring.NewOnCallPager(monitoring.OnCallPagerConfig{
+		TwilioAccountSID: cfg.Notifications.TwilioAccountSID,
+		TwilioAuthToken:  cfg.Notifications.TwilioAuthToken,
+		TwilioFromNumber: cfg.Notifications.TwilioFromNumber,
+	})
+	escalationJob := monitoring.NewEscalationJob(repo, pager, logger)
+	go escalationJob.Start(healthCtx)
+
+	// Run cron-scheduled background jobs
+	jobScheduler := scheduler.New(logger)
+	nightlyScanCron := cfg.Security.NightlyOWASPScanCron
+	if nightlyScanCron == "" {
+		nightlyScanCron = "0 2 * * *"
+	}
+	if err := jobScheduler.AddCronJob("nightly-owasp-scan", nightlyScanCron, nightlyOWASPScan.Run); err != nil {
+		logger.Fatal("Failed to schedule nightly OWASP scan", zap.Error(err))
+	}
+	jobScheduler.Start(healthCtx)
+
+	// Write buffered health scores to the database
+	healthScoreFlush := monitoring.NewHealthScoreFlushJob(healthScores, repo, logger)
+	go healthScoreFlush.Start(healthCtx)
+
+	// Detect tool usage anomalies across each organization's agents
+	orgAnomalyDetector := monitoring.NewOrgAnomalyDetector(repo, logger)
+	go orgAnomalyDetector.Start(healthCtx)
+
+	// Sync the server registry from an external catalog
+	if cfg.Registry.CatalogURL != "" && cfg.Registry.SyncIntervalMinutes > 0 {
+		catalogOrgID, err := uuid.Parse(cfg.Registry.CatalogOrganizationID)
+		if err != nil {
+			logger.Fatal("Invalid registry catalog organization ID", zap.Error(err))
+		}
+		serverRegistry := registry.NewServerRegistry(logger, legacyRepo)
+		serverRegistry.PruneAbsent = cfg.Registry.PruneAbsent
+		catalogSyncJob := registry.NewCatalogSyncJob(serverRegistry, cfg.Registry.CatalogURL, catalogOrgID,
+			time.Duration(cfg.Registry.SyncIntervalMinutes)*time.Minute, logger)
+		go catalogSyncJob.Start(healthCtx)
+	}
+
 	// Wait for interrupt signal to gracefully shut down the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
@@ -221,11 +551,46 @@ func main() {
 
 	logger.Info("Shutting down server...")
 
+	// Let in-flight tool executions, health check probes and a running scheduled job
+	// finish, cancelling them once the graceful shutdown timeout has passed
+	shutdownTimeout := time.Duration(cfg.Server.GracefulShutdownTimeout) * time.Second
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = 30 * time.Second
+	}
+	jobsCtx, jobsCancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer jobsCancel()
+	jobsStopped := make(chan error, 1)
+	go func() { jobsStopped <- jobScheduler.Stop(jobsCtx) }()
+
+	if err := shutdownCoordinator.Shutdown(shutdownTimeout); err != nil {
+		logger.Warn("Cancelled in-flight work at shutdown", zap.Duration("timeout", shutdownTimeout), zap.Error(err))
+	}
+	if err := <-jobsStopped; err != nil {
+		logger.Warn("Cancelled running scheduled jobs at shutdown", zap.Error(err))
+	}
+
 	// The context is used to inform the server it has 5 seconds to finish
 	// the request it is currently handling
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	// Keep the health scores of the final checks
+	healthScoreFlush.Flush(ctx)
+
+	if mdnsServer != nil {
+		mdnsServer.Shutdown()
+	}
+
+	if enhancedHandler != nil {
+		enhancedHandler.CloseResourceSubscriptions()
+	}
+
+	if metricsServer != nil {
+		if err := metricsServer.Shutdown(ctx); err != nil {
+			logger.Error("Metrics server forced to shutdown", zap.Error(err))
+		}
+	}
+
 	if err := server.Shutdown(ctx); err != nil {
 		logger.Fatal("Server forced to shutdown:", zap.Error(err))
 	}
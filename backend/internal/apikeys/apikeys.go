@@ -0,0 +1,121 @@
+package apikeys
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/radhi1991/aran-mcp-sentinel/internal/auth"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"github.com/zeebo/blake3"
+	"go.uber.org/zap"
+)
+
+// PrefixLength is how many leading characters of a key are stored and shown in clear
+const PrefixLength = 8
+
+// keyBytes is the amount of randomness in a key
+const keyBytes = 32
+
+// ErrInvalidKey is returned when a key does not match an active, unexpired API key
+var ErrInvalidKey = errors.New("invalid API key")
+
+// rolePermission is the permission naming the role a key acts with
+const rolePermission = "role"
+
+// Role returns the role an API key acts with, from its "role" permission. Keys without
+// one get the user role, as users without a role do; keys naming an unknown role get
+// the viewer role.
+func Role(permissions database.JSONB) string {
+	value, ok := permissions[rolePermission]
+	if !ok {
+		return auth.RoleUser
+	}
+	role, ok := value.(string)
+	if !ok || !auth.RoleAtLeast(role, auth.RoleViewer) {
+		return auth.RoleViewer
+	}
+	return role
+}
+
+// ValidatePermissions checks that a key's "role" permission, if set, names a known role
+func ValidatePermissions(permissions database.JSONB) error {
+	value, ok := permissions[rolePermission]
+	if !ok {
+		return nil
+	}
+	if role, ok := value.(string); !ok || !auth.RoleAtLeast(role, auth.RoleViewer) {
+		return fmt.Errorf("role must be one of %s, %s or %s", auth.RoleViewer, auth.RoleUser, auth.RoleAdmin)
+	}
+	return nil
+}
+
+// Generate creates a new API key from 32 random bytes encoded as base64url, and returns
+// it along with the hash that is stored in its place
+func Generate() (plaintext, hash string) {
+	key := make([]byte, keyBytes)
+	// crypto/rand.Read never returns an error
+	rand.Read(key)
+
+	plaintext = base64.RawURLEncoding.EncodeToString(key)
+	return plaintext, Hash(plaintext)
+}
+
+// Hash returns the hex-encoded 256-bit BLAKE3 hash of a key
+func Hash(plaintext string) string {
+	sum := blake3.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+// Prefix returns the leading characters of a key that identify it in listings
+func Prefix(plaintext string) string {
+	if len(plaintext) < PrefixLength {
+		return plaintext
+	}
+	return plaintext[:PrefixLength]
+}
+
+// Verifier checks API keys against the stored hashes
+type Verifier struct {
+	repo   *database.Repository
+	logger *zap.Logger
+}
+
+// NewVerifier creates an API key verifier
+func NewVerifier(repo *database.Repository, logger *zap.Logger) *Verifier {
+	return &Verifier{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// VerifyAPIKey returns the active, unexpired API key matching plaintext, or ErrInvalidKey.
+// Candidates are found by prefix and their hashes compared in constant time.
+func (v *Verifier) VerifyAPIKey(ctx context.Context, plaintext string) (*database.APIKey, error) {
+	candidates, err := v.repo.ListActiveAPIKeysByPrefix(ctx, Prefix(plaintext))
+	if err != nil {
+		return nil, err
+	}
+
+	hash := []byte(Hash(plaintext))
+	for _, key := range candidates {
+		if subtle.ConstantTimeCompare(hash, []byte(key.KeyHash)) != 1 {
+			continue
+		}
+		if key.ExpiresAt != nil && time.Now().After(*key.ExpiresAt) {
+			return nil, ErrInvalidKey
+		}
+
+		if err := v.repo.TouchAPIKey(ctx, key.ID); err != nil {
+			v.logger.Warn("Failed to record API key usage", zap.String("api_key_id", key.ID.String()), zap.Error(err))
+		}
+		return key, nil
+	}
+
+	return nil, ErrInvalidKey
+}
@@ -0,0 +1,131 @@
+//go:build integration
+
+package apikeys
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database/dbtest"
+	"go.uber.org/zap"
+)
+
+// createKey stores a new key for the organization and returns it with its plaintext
+func createKey(t *testing.T, repo *database.Repository, org *database.Organization, expiresAt *time.Time) (*database.APIKey, string) {
+	t.Helper()
+
+	plaintext, hash := Generate()
+	prefix := Prefix(plaintext)
+	key := &database.APIKey{
+		OrganizationID: org.ID,
+		Name:           "integration",
+		KeyHash:        hash,
+		KeyPrefix:      &prefix,
+		ExpiresAt:      expiresAt,
+	}
+	if err := repo.CreateAPIKey(context.Background(), key); err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+	return key, plaintext
+}
+
+func TestVerifyAPIKey(t *testing.T) {
+	ctx := context.Background()
+	repo := dbtest.Repository(t)
+	org := dbtest.CreateOrganization(t, repo)
+	verifier := NewVerifier(repo, zap.NewNop())
+
+	key, plaintext := createKey(t, repo, org, nil)
+
+	got, err := verifier.VerifyAPIKey(ctx, plaintext)
+	if err != nil {
+		t.Fatalf("VerifyAPIKey() error = %v", err)
+	}
+	if got.ID != key.ID || got.OrganizationID != org.ID {
+		t.Errorf("VerifyAPIKey() = key %s of org %s, want key %s of org %s", got.ID, got.OrganizationID, key.ID, org.ID)
+	}
+
+	stored, err := repo.GetAPIKey(ctx, org.ID, key.ID)
+	if err != nil {
+		t.Fatalf("GetAPIKey() error = %v", err)
+	}
+	if stored.KeyHash == plaintext {
+		t.Error("key was stored in plaintext")
+	}
+	if stored.LastUsedAt == nil {
+		t.Error("last_used_at not recorded after verification")
+	}
+
+	// Same prefix, different secret
+	if _, err := verifier.VerifyAPIKey(ctx, plaintext[:PrefixLength]+"wrong"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("VerifyAPIKey(wrong key) error = %v, want ErrInvalidKey", err)
+	}
+}
+
+func TestVerifyAPIKeyExpired(t *testing.T) {
+	repo := dbtest.Repository(t)
+	org := dbtest.CreateOrganization(t, repo)
+	verifier := NewVerifier(repo, zap.NewNop())
+
+	expired := time.Now().Add(-time.Minute)
+	_, plaintext := createKey(t, repo, org, &expired)
+
+	if _, err := verifier.VerifyAPIKey(context.Background(), plaintext); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("VerifyAPIKey(expired key) error = %v, want ErrInvalidKey", err)
+	}
+}
+
+func TestRotateAPIKey(t *testing.T) {
+	ctx := context.Background()
+	repo := dbtest.Repository(t)
+	org := dbtest.CreateOrganization(t, repo)
+	verifier := NewVerifier(repo, zap.NewNop())
+
+	key, oldPlaintext := createKey(t, repo, org, nil)
+
+	newPlaintext, newHash := Generate()
+	if err := repo.RotateAPIKey(ctx, org.ID, key.ID, newHash, Prefix(newPlaintext)); err != nil {
+		t.Fatalf("RotateAPIKey() error = %v", err)
+	}
+
+	if _, err := verifier.VerifyAPIKey(ctx, oldPlaintext); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("VerifyAPIKey(old key) error = %v, want ErrInvalidKey", err)
+	}
+	got, err := verifier.VerifyAPIKey(ctx, newPlaintext)
+	if err != nil {
+		t.Fatalf("VerifyAPIKey(new key) error = %v", err)
+	}
+	if got.ID != key.ID {
+		t.Errorf("rotated key ID = %s, want %s", got.ID, key.ID)
+	}
+
+	other := dbtest.CreateOrganization(t, repo)
+	if err := repo.RotateAPIKey(ctx, other.ID, key.ID, newHash, Prefix(newPlaintext)); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("RotateAPIKey(other organization) error = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestDeleteAPIKey(t *testing.T) {
+	ctx := context.Background()
+	repo := dbtest.Repository(t)
+	org := dbtest.CreateOrganization(t, repo)
+	verifier := NewVerifier(repo, zap.NewNop())
+
+	key, plaintext := createKey(t, repo, org, nil)
+
+	other := dbtest.CreateOrganization(t, repo)
+	if err := repo.DeleteAPIKey(ctx, other.ID, key.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("DeleteAPIKey(other organization) error = %v, want sql.ErrNoRows", err)
+	}
+
+	if err := repo.DeleteAPIKey(ctx, org.ID, key.ID); err != nil {
+		t.Fatalf("DeleteAPIKey() error = %v", err)
+	}
+	if _, err := verifier.VerifyAPIKey(ctx, plaintext); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("VerifyAPIKey(deleted key) error = %v, want ErrInvalidKey", err)
+	}
+}
@@ -0,0 +1,137 @@
+package apikeys
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/auth"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"go.uber.org/zap"
+)
+
+func TestGenerate(t *testing.T) {
+	plaintext, hash := Generate()
+
+	raw, err := base64.RawURLEncoding.DecodeString(plaintext)
+	if err != nil {
+		t.Fatalf("key %q is not base64url: %v", plaintext, err)
+	}
+	if len(raw) != keyBytes {
+		t.Errorf("key has %d bytes of randomness, want %d", len(raw), keyBytes)
+	}
+	if hash != Hash(plaintext) {
+		t.Errorf("Generate() hash = %q, want Hash(key) = %q", hash, Hash(plaintext))
+	}
+
+	other, _ := Generate()
+	if other == plaintext {
+		t.Error("Generate() returned the same key twice")
+	}
+}
+
+func TestHash(t *testing.T) {
+	// BLAKE3 of the empty input, from the reference test vectors
+	const emptyHash = "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262"
+	if got := Hash(""); got != emptyHash {
+		t.Errorf("Hash(\"\") = %q, want %q", got, emptyHash)
+	}
+
+	if Hash("key-a") == Hash("key-b") {
+		t.Error("different keys produced the same hash")
+	}
+	if len(Hash("key-a")) != 64 {
+		t.Errorf("hash length = %d, want 64 hex characters", len(Hash("key-a")))
+	}
+}
+
+func TestPrefix(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"abcdefghijkl", "abcdefgh"},
+		{"abcdefgh", "abcdefgh"},
+		{"abc", "abc"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Prefix(tt.key); got != tt.want {
+			t.Errorf("Prefix(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestRole(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions database.JSONB
+		want        string
+		wantValid   bool
+	}{
+		{"no permissions", nil, auth.RoleUser, true},
+		{"no role", database.JSONB{"scopes": []interface{}{"read"}}, auth.RoleUser, true},
+		{"viewer", database.JSONB{"role": "viewer"}, auth.RoleViewer, true},
+		{"admin", database.JSONB{"role": "admin"}, auth.RoleAdmin, true},
+		{"unknown role", database.JSONB{"role": "owner"}, auth.RoleViewer, false},
+		{"role not a string", database.JSONB{"role": 3}, auth.RoleViewer, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Role(tt.permissions); got != tt.want {
+				t.Errorf("Role(%v) = %q, want %q", tt.permissions, got, tt.want)
+			}
+			if err := ValidatePermissions(tt.permissions); (err == nil) != tt.wantValid {
+				t.Errorf("ValidatePermissions(%v) = %v, want valid %v", tt.permissions, err, tt.wantValid)
+			}
+		})
+	}
+}
+
+func TestKeyManagementRequiresAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	keyPath := "/api/v1/api-keys/" + uuid.New().String()
+
+	tests := []struct {
+		name       string
+		role       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{"create as viewer", auth.RoleViewer, http.MethodPost, "/api/v1/api-keys", `{"name":"ci"}`, http.StatusForbidden},
+		{"create as user", auth.RoleUser, http.MethodPost, "/api/v1/api-keys", `{"name":"ci"}`, http.StatusForbidden},
+		{"rotate as user", auth.RoleUser, http.MethodPost, keyPath + "/rotate", "", http.StatusForbidden},
+		{"delete as user", auth.RoleUser, http.MethodDelete, keyPath, "", http.StatusForbidden},
+		{"create with an unknown role as admin", auth.RoleAdmin, http.MethodPost, "/api/v1/api-keys", `{"name":"ci","permissions":{"role":"owner"}}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			api := router.Group("/api/v1", func(c *gin.Context) {
+				c.Set("user_role", tt.role)
+				c.Set("organization_id", uuid.New())
+				c.Next()
+			})
+			NewHandler(nil, zap.NewNop()).RegisterRoutes(api)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package apikeys
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/auth"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"go.uber.org/zap"
+)
+
+// Handler handles API key endpoints
+type Handler struct {
+	repo   *database.Repository
+	logger *zap.Logger
+}
+
+// NewHandler creates a new API keys handler
+func NewHandler(repo *database.Repository, logger *zap.Logger) *Handler {
+	return &Handler{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// RegisterRoutes registers API key routes
+func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
+	keys := router.Group("/api-keys")
+	{
+		keys.POST("", auth.RBACMiddleware(auth.RoleAdmin, h.logger), h.CreateAPIKey)
+		keys.GET("", h.ListAPIKeys)
+		keys.DELETE("/:id", auth.RBACMiddleware(auth.RoleAdmin, h.logger), h.DeleteAPIKey)
+		keys.POST("/:id/rotate", auth.RBACMiddleware(auth.RoleAdmin, h.logger), h.RotateAPIKey)
+	}
+}
+
+// CreateAPIKeyRequest represents the request to create an API key
+type CreateAPIKeyRequest struct {
+	Name          string         `json:"name" binding:"required"`
+	Permissions   database.JSONB `json:"permissions"`     // "role" sets the role the key acts with
+	ExpiresInDays int            `json:"expires_in_days"` // 0 never expires
+}
+
+// CreateAPIKey creates an API key. The response holds the full key, which is not stored
+// and cannot be retrieved again.
+func (h *Handler) CreateAPIKey(c *gin.Context) {
+	orgID, ok := currentOrganizationID(c)
+	if !ok {
+		return
+	}
+
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if req.ExpiresInDays < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expires_in_days must not be negative"})
+		return
+	}
+	if err := ValidatePermissions(req.Permissions); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	plaintext, hash := Generate()
+	prefix := Prefix(plaintext)
+
+	key := &database.APIKey{
+		OrganizationID: orgID,
+		Name:           req.Name,
+		KeyHash:        hash,
+		KeyPrefix:      &prefix,
+		Permissions:    req.Permissions,
+	}
+	if userID, exists := c.Get("user_id"); exists {
+		if id, ok := userID.(uuid.UUID); ok {
+			key.UserID = &id
+		}
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		key.ExpiresAt = &expiresAt
+	}
+
+	if err := h.repo.CreateAPIKey(c.Request.Context(), key); err != nil {
+		h.logger.Error("Failed to create API key", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create API key"})
+		return
+	}
+
+	h.logger.Info("API key created", zap.String("api_key_id", key.ID.String()), zap.String("organization_id", orgID.String()))
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data": gin.H{
+			"api_key": key,
+			"key":     plaintext,
+		},
+	})
+}
+
+// ListAPIKeys lists the organization's API keys, identified by their prefix only
+func (h *Handler) ListAPIKeys(c *gin.Context) {
+	orgID, ok := currentOrganizationID(c)
+	if !ok {
+		return
+	}
+
+	keys, err := h.repo.ListAPIKeys(c.Request.Context(), orgID)
+	if err != nil {
+		h.logger.Error("Failed to list API keys", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list API keys"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    keys,
+	})
+}
+
+// DeleteAPIKey revokes an API key by deleting it
+func (h *Handler) DeleteAPIKey(c *gin.Context) {
+	orgID, ok := currentOrganizationID(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid API key ID"})
+		return
+	}
+
+	if err := h.repo.DeleteAPIKey(c.Request.Context(), orgID, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+			return
+		}
+		h.logger.Error("Failed to delete API key", zap.String("api_key_id", id.String()), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete API key"})
+		return
+	}
+
+	h.logger.Info("API key deleted", zap.String("api_key_id", id.String()))
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// RotateAPIKey replaces an API key's secret. The old key stops working immediately and
+// the response holds the new key, which cannot be retrieved again.
+func (h *Handler) RotateAPIKey(c *gin.Context) {
+	orgID, ok := currentOrganizationID(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid API key ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	plaintext, hash := Generate()
+
+	if err := h.repo.RotateAPIKey(ctx, orgID, id, hash, Prefix(plaintext)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+			return
+		}
+		h.logger.Error("Failed to rotate API key", zap.String("api_key_id", id.String()), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rotate API key"})
+		return
+	}
+
+	key, err := h.repo.GetAPIKey(ctx, orgID, id)
+	if err != nil {
+		h.logger.Error("Failed to get rotated API key", zap.String("api_key_id", id.String()), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rotate API key"})
+		return
+	}
+
+	h.logger.Info("API key rotated", zap.String("api_key_id", id.String()))
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"api_key": key,
+			"key":     plaintext,
+		},
+	})
+}
+
+// currentOrganizationID returns the caller's organization, writing an error response if
+// it is missing
+func currentOrganizationID(c *gin.Context) (uuid.UUID, bool) {
+	orgID, exists := c.Get("organization_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return uuid.Nil, false
+	}
+
+	orgUUID, ok := orgID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID type"})
+		return uuid.Nil, false
+	}
+
+	return orgUUID, true
+}
@@ -0,0 +1,137 @@
+package auditlogs
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/auth"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/middleware"
+	"go.uber.org/zap"
+)
+
+// Handler handles audit log endpoints
+type Handler struct {
+	repo   *database.Repository
+	logger *zap.Logger
+}
+
+// NewHandler creates a new audit logs handler
+func NewHandler(repo *database.Repository, logger *zap.Logger) *Handler {
+	return &Handler{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// RegisterRoutes registers audit log routes. Audit logs are only visible to admins.
+func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
+	logs := router.Group("/audit-logs", auth.RBACMiddleware(auth.RoleAdmin, h.logger))
+	{
+		logs.GET("", h.ListAuditLogs)
+	}
+}
+
+// ListAuditLogs lists the organization's audit logs, newest first. Logs can be filtered
+// by user_id, action, resource_type, resource_id, ip_address and an RFC 3339 since/until
+// time range.
+func (h *Handler) ListAuditLogs(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	query := database.AuditLogQuery{
+		OrganizationID: orgID,
+		Action:         c.Query("action"),
+		ResourceType:   c.Query("resource_type"),
+		Limit:          database.DefaultAuditLogPageSize,
+	}
+
+	if value := c.Query("user_id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id"})
+			return
+		}
+		query.UserID = &id
+	}
+	if value := c.Query("resource_id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resource_id"})
+			return
+		}
+		query.ResourceID = &id
+	}
+	if value := c.Query("ip_address"); value != "" {
+		if net.ParseIP(value) == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ip_address"})
+			return
+		}
+		query.IPAddress = value
+	}
+	if value := c.Query("since"); value != "" {
+		since, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "since must be an RFC 3339 time"})
+			return
+		}
+		query.Since = &since
+	}
+	if value := c.Query("until"); value != "" {
+		until, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "until must be an RFC 3339 time"})
+			return
+		}
+		query.Until = &until
+	}
+	if query.Since != nil && query.Until != nil && !query.Since.Before(*query.Until) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "since must be before until"})
+		return
+	}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		if limit > database.MaxAuditLogPageSize {
+			limit = database.MaxAuditLogPageSize
+		}
+		query.Limit = limit
+	}
+	if value := c.Query("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "offset must be a non-negative integer"})
+			return
+		}
+		query.Offset = offset
+	}
+
+	logs, total, err := h.repo.QueryAuditLogs(c.Request.Context(), query)
+	if err != nil {
+		h.logger.Error("Failed to query audit logs", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to query audit logs"})
+		return
+	}
+
+	page := middleware.NewPage(logs, query.Limit, query.Offset)
+	page.Total = int(total)
+	middleware.SetPage(c, page)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"data":        page.Items,
+		"pagination":  page.Meta(),
+		"total_count": total,
+	})
+}
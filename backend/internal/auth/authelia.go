@@ -55,6 +55,7 @@ func AutheliaMiddleware(logger *zap.Logger) gin.HandlerFunc {
 		c.Set("user_id", username)
 		c.Set("user_email", email)
 		c.Set("user_groups", groups)
+		c.Set("user_role", autheliaRole(groups))
 
 		logger.Info("User authenticated via Authelia",
 			zap.String("username", username),
@@ -65,6 +66,33 @@ func AutheliaMiddleware(logger *zap.Logger) gin.HandlerFunc {
 	}
 }
 
+// autheliaGroupRoles maps Authelia groups to user roles
+var autheliaGroupRoles = map[string]string{
+	"admins":  RoleAdmin,
+	"admin":   RoleAdmin,
+	"users":   RoleUser,
+	"user":    RoleUser,
+	"viewers": RoleViewer,
+	"viewer":  RoleViewer,
+}
+
+// autheliaRole returns the most privileged role granted by the user's groups. Users in
+// none of the role groups get the user role.
+func autheliaRole(groups []string) string {
+	role := ""
+	for _, group := range groups {
+		if groupRole, ok := autheliaGroupRoles[strings.ToLower(group)]; ok {
+			if role == "" || RoleAtLeast(groupRole, role) {
+				role = groupRole
+			}
+		}
+	}
+	if role == "" {
+		return RoleUser
+	}
+	return role
+}
+
 // GetAutheliaUserFromContext retrieves Authelia user from context
 func GetAutheliaUserFromContext(c *gin.Context) (AutheliaUser, bool) {
 	user, exists := c.Get("authelia_user")
@@ -14,6 +14,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
@@ -107,11 +108,17 @@ func (c *jwksCache) fetch() error {
 // ClerkMiddleware returns a Gin middleware that validates Clerk JWTs.
 // It expects an Authorization: Bearer <token> header.
 func ClerkMiddleware(jwksURL, expectedIssuer, expectedAudience string, logger *zap.Logger) gin.HandlerFunc {
+	return ClerkMiddlewareWithIntrospection(jwksURL, expectedIssuer, expectedAudience, nil, logger)
+}
+
+// ClerkMiddlewareWithIntrospection is ClerkMiddleware that falls back to the
+// introspector when the JWKS cannot be fetched or a token cannot be parsed or verified
+// with it, such as an opaque access token. introspector may be nil.
+func ClerkMiddlewareWithIntrospection(jwksURL, expectedIssuer, expectedAudience string, introspector *TokenIntrospector, logger *zap.Logger) gin.HandlerFunc {
 	cache := newJwksCache(jwksURL, 5*time.Minute)
 
 	return func(c *gin.Context) {
-		// Skip public endpoints
-		if c.Request.Method == "OPTIONS" || c.Request.URL.Path == "/health" || strings.HasPrefix(c.Request.URL.Path, "/api/v1/auth/") {
+		if isPublicAuthPath(c) {
 			c.Next()
 			return
 		}
@@ -136,6 +143,10 @@ func ClerkMiddleware(jwksURL, expectedIssuer, expectedAudience string, logger *z
 		if time.Since(cache.fetched) > cache.ttl || len(cache.keys) == 0 {
 			if err := cache.fetch(); err != nil {
 				logger.Error("failed to fetch JWKS", zap.Error(err))
+				if introspector != nil {
+					authenticateByIntrospection(c, introspector, tokenStr, logger)
+					return
+				}
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate token"})
 				c.Abort()
 				return
@@ -172,6 +183,11 @@ func ClerkMiddleware(jwksURL, expectedIssuer, expectedAudience string, logger *z
 		claims := jwt.MapClaims{}
 		token, err := jwt.ParseWithClaims(tokenStr, claims, keyFunc)
 		if err != nil || !token.Valid {
+			if introspector != nil {
+				logger.Debug("JWT validation failed, introspecting token", zap.Error(err))
+				authenticateByIntrospection(c, introspector, tokenStr, logger)
+				return
+			}
 			logger.Warn("invalid token", zap.Error(err))
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 			c.Abort()
@@ -226,9 +242,11 @@ func ClerkMiddleware(jwksURL, expectedIssuer, expectedAudience string, logger *z
 			c.Set("user_id", userID)
 		}
 
-		// Optionally extract role or other custom claims
-		if role, ok := claims["role"].(string); ok {
-			c.Set("user_role", role)
+		c.Set("user_role", clerkRole(claims))
+		if orgID, ok := claims["org_id"].(string); ok {
+			if parsed, err := uuid.Parse(orgID); err == nil {
+				c.Set("organization_id", parsed)
+			}
 		}
 
 		c.Set("authenticated", true)
@@ -237,3 +255,33 @@ func ClerkMiddleware(jwksURL, expectedIssuer, expectedAudience string, logger *z
 		c.Next()
 	}
 }
+
+// isPublicAuthPath reports whether a request may skip authentication
+func isPublicAuthPath(c *gin.Context) bool {
+	return c.Request.Method == "OPTIONS" || c.Request.URL.Path == "/health" || strings.HasPrefix(c.Request.URL.Path, "/api/v1/auth/")
+}
+
+// clerkOrgRoles maps Clerk organization roles to user roles
+var clerkOrgRoles = map[string]string{
+	"org:admin":  RoleAdmin,
+	"admin":      RoleAdmin,
+	"org:member": RoleUser,
+	"member":     RoleUser,
+	"org:viewer": RoleViewer,
+	"viewer":     RoleViewer,
+}
+
+// clerkRole returns the role from a custom role claim, and otherwise from the Clerk
+// organization role in org_role. Users with neither get the user role, as Authelia
+// users in no role group do.
+func clerkRole(claims jwt.MapClaims) string {
+	if role, ok := claims["role"].(string); ok && role != "" {
+		return role
+	}
+	if orgRole, ok := claims["org_role"].(string); ok {
+		if role, known := clerkOrgRoles[strings.ToLower(orgRole)]; known {
+			return role
+		}
+	}
+	return RoleUser
+}
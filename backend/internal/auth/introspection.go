@@ -0,0 +1,249 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ErrTokenInactive is returned when the introspection endpoint reports a token as not active
+var ErrTokenInactive = errors.New("token is not active")
+
+// IntrospectionResult holds the claims of an active token from an RFC 7662 introspection response
+type IntrospectionResult struct {
+	Active    bool   `json:"active"`
+	Subject   string `json:"sub"`
+	Email     string `json:"email"`
+	Scope     string `json:"scope"`
+	ClientID  string `json:"client_id"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// Scopes returns the space-separated scope claim as a list
+func (r *IntrospectionResult) Scopes() []string {
+	return strings.Fields(r.Scope)
+}
+
+// cachedIntrospection is an active introspection result and when it stops being trusted
+type cachedIntrospection struct {
+	result  *IntrospectionResult
+	expires time.Time
+}
+
+// TokenIntrospector validates access tokens with an OAuth2/OIDC token introspection
+// endpoint (RFC 7662), caching active tokens for up to cacheTTL
+type TokenIntrospector struct {
+	endpoint     string
+	clientID     string
+	clientSecret string
+	cacheTTL     time.Duration
+	client       *http.Client
+	logger       *zap.Logger
+
+	cache     sync.Map // sha256 of the token -> *cachedIntrospection
+	lastSweep atomic.Int64
+}
+
+// NewTokenIntrospector creates an introspector that authenticates to the endpoint with
+// the client credentials. A cacheTTL of zero disables caching.
+func NewTokenIntrospector(endpoint, clientID, clientSecret string, cacheTTL time.Duration, logger *zap.Logger) *TokenIntrospector {
+	return &TokenIntrospector{
+		endpoint:     endpoint,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		cacheTTL:     cacheTTL,
+		client:       &http.Client{Timeout: 10 * time.Second},
+		logger:       logger,
+	}
+}
+
+// Introspect returns the claims of an active token, or ErrTokenInactive
+func (i *TokenIntrospector) Introspect(ctx context.Context, token string) (*IntrospectionResult, error) {
+	key := tokenCacheKey(token)
+	now := time.Now()
+
+	if value, ok := i.cache.Load(key); ok {
+		entry := value.(*cachedIntrospection)
+		if now.Before(entry.expires) {
+			return entry.result, nil
+		}
+		i.cache.Delete(key)
+	}
+
+	result, err := i.introspect(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if i.cacheTTL > 0 {
+		// Never trust a cached token past its own expiry
+		expires := now.Add(i.cacheTTL)
+		if result.ExpiresAt > 0 {
+			if tokenExpiry := time.Unix(result.ExpiresAt, 0); tokenExpiry.Before(expires) {
+				expires = tokenExpiry
+			}
+		}
+		i.cache.Store(key, &cachedIntrospection{result: result, expires: expires})
+		i.evictExpired(now)
+	}
+
+	return result, nil
+}
+
+// introspect asks the endpoint about a token
+func (i *TokenIntrospector) introspect(ctx context.Context, token string) (*IntrospectionResult, error) {
+	form := url.Values{}
+	form.Set("token", token)
+	form.Set("token_type_hint", "access_token")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, i.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create introspection request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if i.clientID != "" {
+		req.SetBasicAuth(url.QueryEscape(i.clientID), url.QueryEscape(i.clientSecret))
+	}
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call introspection endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("introspection endpoint returned status %d", resp.StatusCode)
+	}
+
+	var result IntrospectionResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode introspection response: %w", err)
+	}
+	if !result.Active {
+		return nil, ErrTokenInactive
+	}
+	if result.ExpiresAt > 0 && time.Now().Unix() >= result.ExpiresAt {
+		return nil, ErrTokenInactive
+	}
+
+	return &result, nil
+}
+
+// evictExpired drops expired cache entries, at most once per cache TTL
+func (i *TokenIntrospector) evictExpired(now time.Time) {
+	last := i.lastSweep.Load()
+	if now.UnixNano()-last < int64(i.cacheTTL) || !i.lastSweep.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+
+	i.cache.Range(func(key, value interface{}) bool {
+		if !now.Before(value.(*cachedIntrospection).expires) {
+			i.cache.Delete(key)
+		}
+		return true
+	})
+}
+
+// tokenCacheKey hashes a token so the cache does not hold bearer tokens
+func tokenCacheKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// OIDCIntrospectionMiddleware returns a Gin middleware that validates bearer tokens with
+// an RFC 7662 token introspection endpoint instead of a JWKS
+func OIDCIntrospectionMiddleware(introspectionEndpoint, clientID, clientSecret string, cacheTTL time.Duration, logger *zap.Logger) gin.HandlerFunc {
+	introspector := NewTokenIntrospector(introspectionEndpoint, clientID, clientSecret, cacheTTL, logger)
+
+	return func(c *gin.Context) {
+		if isPublicAuthPath(c) {
+			c.Next()
+			return
+		}
+
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+			c.Abort()
+			return
+		}
+
+		parts := strings.SplitN(authHeader, " ", 2)
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
+			c.Abort()
+			return
+		}
+
+		authenticateByIntrospection(c, introspector, parts[1], logger)
+	}
+}
+
+// authenticateByIntrospection sets the user of an active token on the context and
+// continues, or aborts the request
+func authenticateByIntrospection(c *gin.Context, introspector *TokenIntrospector, token string, logger *zap.Logger) {
+	result, err := introspector.Introspect(c.Request.Context(), token)
+	if err != nil {
+		if errors.Is(err, ErrTokenInactive) {
+			logger.Warn("inactive token")
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		} else {
+			logger.Error("failed to introspect token", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate token"})
+		}
+		c.Abort()
+		return
+	}
+
+	if result.Subject != "" {
+		c.Set("user_id", result.Subject)
+	}
+	if result.Email != "" {
+		c.Set("user_email", result.Email)
+	}
+	c.Set("user_scopes", result.Scopes())
+	c.Set("user_role", introspectionRole(result.Scopes()))
+
+	c.Set("authenticated", true)
+	logger.Debug("User authenticated via token introspection", zap.String("user_id", result.Subject))
+
+	c.Next()
+}
+
+// introspectionScopeRoles maps token scopes to user roles
+var introspectionScopeRoles = map[string]string{
+	"role:admin":  RoleAdmin,
+	"role:user":   RoleUser,
+	"role:viewer": RoleViewer,
+}
+
+// introspectionRole returns the most privileged role granted by the token's scopes.
+// Tokens with none of the role scopes get the user role, as Authelia and Clerk users
+// without a role do.
+func introspectionRole(scopes []string) string {
+	role := ""
+	for _, scope := range scopes {
+		if scopeRole, ok := introspectionScopeRoles[strings.ToLower(scope)]; ok {
+			if role == "" || RoleAtLeast(scopeRole, role) {
+				role = scopeRole
+			}
+		}
+	}
+	if role == "" {
+		return RoleUser
+	}
+	return role
+}
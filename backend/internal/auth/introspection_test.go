@@ -0,0 +1,314 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// introspectionServer is an RFC 7662 introspection endpoint answering from fixed
+// responses by token. Unknown tokens are reported as inactive.
+type introspectionServer struct {
+	*httptest.Server
+	calls atomic.Int32
+}
+
+func newIntrospectionServer(t *testing.T, responses map[string]map[string]interface{}) *introspectionServer {
+	t.Helper()
+
+	s := &introspectionServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.calls.Add(1)
+
+		if r.Method != http.MethodPost {
+			t.Errorf("introspection method = %s, want POST", r.Method)
+		}
+		if id, secret, ok := r.BasicAuth(); !ok || id != "sentinel" || secret != "s3cret" {
+			t.Errorf("introspection client credentials = %q, %q, want sentinel, s3cret", id, secret)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("invalid introspection form: %v", err)
+		}
+		if hint := r.PostForm.Get("token_type_hint"); hint != "access_token" {
+			t.Errorf("token_type_hint = %q, want access_token", hint)
+		}
+
+		token := r.PostForm.Get("token")
+		if token == "fail" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		response, ok := responses[token]
+		if !ok {
+			response = map[string]interface{}{"active": false}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func TestTokenIntrospectorCachesActiveTokens(t *testing.T) {
+	server := newIntrospectionServer(t, map[string]map[string]interface{}{
+		"active-token": {"active": true, "sub": "user-1", "scope": "read write"},
+	})
+	introspector := NewTokenIntrospector(server.URL, "sentinel", "s3cret", time.Minute, zap.NewNop())
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		result, err := introspector.Introspect(ctx, "active-token")
+		if err != nil {
+			t.Fatalf("Introspect() error = %v", err)
+		}
+		if result.Subject != "user-1" || len(result.Scopes()) != 2 {
+			t.Errorf("Introspect() = %+v, want user-1 with two scopes", result)
+		}
+	}
+	if calls := server.calls.Load(); calls != 1 {
+		t.Errorf("endpoint called %d times for a cached token, want 1", calls)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := introspector.Introspect(ctx, "revoked-token"); !errors.Is(err, ErrTokenInactive) {
+			t.Fatalf("Introspect() of an inactive token error = %v, want ErrTokenInactive", err)
+		}
+	}
+	if calls := server.calls.Load(); calls != 3 {
+		t.Errorf("endpoint called %d times, want inactive tokens not to be cached", calls)
+	}
+}
+
+func TestTokenIntrospectorCacheExpiry(t *testing.T) {
+	exp := time.Now().Add(30 * time.Second).Unix()
+	server := newIntrospectionServer(t, map[string]map[string]interface{}{
+		"short-lived": {"active": true, "sub": "user-1", "exp": exp},
+		"long-lived":  {"active": true, "sub": "user-2"},
+		"expired":     {"active": true, "sub": "user-3", "exp": time.Now().Add(-time.Second).Unix()},
+	})
+	introspector := NewTokenIntrospector(server.URL, "sentinel", "s3cret", time.Hour, zap.NewNop())
+	ctx := context.Background()
+
+	if _, err := introspector.Introspect(ctx, "short-lived"); err != nil {
+		t.Fatalf("Introspect() error = %v", err)
+	}
+	value, ok := introspector.cache.Load(tokenCacheKey("short-lived"))
+	if !ok {
+		t.Fatal("active token was not cached")
+	}
+	if expires := value.(*cachedIntrospection).expires; expires.After(time.Unix(exp, 0)) {
+		t.Errorf("cache entry expires at %v, after the token's exp %v", expires, time.Unix(exp, 0))
+	}
+
+	if _, err := introspector.Introspect(ctx, "expired"); !errors.Is(err, ErrTokenInactive) {
+		t.Errorf("Introspect() of a token past its exp error = %v, want ErrTokenInactive", err)
+	}
+
+	// An entry past its expiry is introspected again
+	if _, err := introspector.Introspect(ctx, "long-lived"); err != nil {
+		t.Fatalf("Introspect() error = %v", err)
+	}
+	value, _ = introspector.cache.Load(tokenCacheKey("long-lived"))
+	value.(*cachedIntrospection).expires = time.Now().Add(-time.Second)
+	calls := server.calls.Load()
+	if _, err := introspector.Introspect(ctx, "long-lived"); err != nil {
+		t.Fatalf("Introspect() error = %v", err)
+	}
+	if server.calls.Load() != calls+1 {
+		t.Error("expired cache entry was used instead of introspecting the token")
+	}
+}
+
+func TestTokenIntrospectorWithoutCache(t *testing.T) {
+	server := newIntrospectionServer(t, map[string]map[string]interface{}{
+		"active-token": {"active": true, "sub": "user-1"},
+	})
+	introspector := NewTokenIntrospector(server.URL, "sentinel", "s3cret", 0, zap.NewNop())
+
+	for i := 0; i < 2; i++ {
+		if _, err := introspector.Introspect(context.Background(), "active-token"); err != nil {
+			t.Fatalf("Introspect() error = %v", err)
+		}
+	}
+	if calls := server.calls.Load(); calls != 2 {
+		t.Errorf("endpoint called %d times with caching disabled, want 2", calls)
+	}
+}
+
+func TestOIDCIntrospectionMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	server := newIntrospectionServer(t, map[string]map[string]interface{}{
+		"active-token": {"active": true, "sub": "user-1", "email": "ada@example.com", "scope": "servers:read"},
+	})
+
+	tests := []struct {
+		name       string
+		path       string
+		header     string
+		wantStatus int
+	}{
+		{"active token", "/api/v1/servers", "Bearer active-token", http.StatusOK},
+		{"inactive token", "/api/v1/servers", "Bearer revoked-token", http.StatusUnauthorized},
+		{"endpoint failure", "/api/v1/servers", "Bearer fail", http.StatusInternalServerError},
+		{"missing header", "/api/v1/servers", "", http.StatusUnauthorized},
+		{"basic credentials", "/api/v1/servers", "Basic dXNlcjpwYXNz", http.StatusUnauthorized},
+		{"public path", "/health", "", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var userID, email string
+			var scopes []string
+
+			router := gin.New()
+			router.Use(OIDCIntrospectionMiddleware(server.URL, "sentinel", "s3cret", time.Minute, zap.NewNop()))
+			handler := func(c *gin.Context) {
+				userID = c.GetString("user_id")
+				email = c.GetString("user_email")
+				scopes = c.GetStringSlice("user_scopes")
+				c.Status(http.StatusOK)
+			}
+			router.GET("/api/v1/servers", handler)
+			router.GET("/health", handler)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.name == "active token" {
+				if userID != "user-1" || email != "ada@example.com" || len(scopes) != 1 || scopes[0] != "servers:read" {
+					t.Errorf("context user = %q, %q, %v, want the token's claims", userID, email, scopes)
+				}
+			}
+		})
+	}
+}
+
+func TestClerkMiddlewareFallsBackToIntrospection(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	introspection := newIntrospectionServer(t, map[string]map[string]interface{}{
+		"opaque-token": {"active": true, "sub": "user-1"},
+	})
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"keys": []}`))
+	}))
+	t.Cleanup(jwks.Close)
+
+	tests := []struct {
+		name       string
+		jwksURL    string
+		token      string
+		wantStatus int
+	}{
+		{"opaque token", jwks.URL, "opaque-token", http.StatusOK},
+		{"inactive opaque token", jwks.URL, "revoked-token", http.StatusUnauthorized},
+		{"jwks unavailable", "", "opaque-token", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			introspector := NewTokenIntrospector(introspection.URL, "sentinel", "s3cret", time.Minute, zap.NewNop())
+
+			var userID string
+			router := gin.New()
+			router.Use(ClerkMiddlewareWithIntrospection(tt.jwksURL, "", "", introspector, zap.NewNop()))
+			router.GET("/api/v1/servers", func(c *gin.Context) {
+				userID = c.GetString("user_id")
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/servers", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusOK && userID != "user-1" {
+				t.Errorf("user_id = %q, want user-1", userID)
+			}
+		})
+	}
+}
+
+func TestIntrospectionRole(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []string
+		want   string
+	}{
+		{"no scopes", nil, RoleUser},
+		{"no role scope", []string{"servers:read"}, RoleUser},
+		{"viewer", []string{"servers:read", "role:viewer"}, RoleViewer},
+		{"admin", []string{"ROLE:ADMIN"}, RoleAdmin},
+		{"most privileged wins", []string{"role:viewer", "role:admin", "role:user"}, RoleAdmin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := introspectionRole(tt.scopes); got != tt.want {
+				t.Errorf("introspectionRole(%v) = %q, want %q", tt.scopes, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntrospectedTokensPassRBAC(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	server := newIntrospectionServer(t, map[string]map[string]interface{}{
+		"user-token":   {"active": true, "sub": "user-1", "scope": "servers:read"},
+		"viewer-token": {"active": true, "sub": "user-2", "scope": "role:viewer"},
+		"admin-token":  {"active": true, "sub": "user-3", "scope": "role:admin"},
+	})
+
+	tests := []struct {
+		name         string
+		token        string
+		requiredRole string
+		wantStatus   int
+	}{
+		{"user on a user route", "user-token", RoleUser, http.StatusOK},
+		{"user on an admin route", "user-token", RoleAdmin, http.StatusForbidden},
+		{"viewer on a user route", "viewer-token", RoleUser, http.StatusForbidden},
+		{"viewer on a viewer route", "viewer-token", RoleViewer, http.StatusOK},
+		{"admin on an admin route", "admin-token", RoleAdmin, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(OIDCIntrospectionMiddleware(server.URL, "sentinel", "s3cret", time.Minute, zap.NewNop()))
+			router.POST("/api/v1/servers", RBACMiddleware(tt.requiredRole, zap.NewNop()), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/servers", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+}
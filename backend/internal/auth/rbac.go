@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// User roles, from least to most privileged
+const (
+	RoleViewer = "viewer"
+	RoleUser   = "user"
+	RoleAdmin  = "admin"
+)
+
+// roleLevels orders the roles; a role grants everything granted to lower levels
+var roleLevels = map[string]int{
+	RoleViewer: 1,
+	RoleUser:   2,
+	RoleAdmin:  3,
+}
+
+// RoleAtLeast reports whether role grants the permissions of requiredRole. Unknown roles
+// grant nothing.
+func RoleAtLeast(role, requiredRole string) bool {
+	level, known := roleLevels[role]
+	required, requiredKnown := roleLevels[requiredRole]
+	return known && requiredKnown && level >= required
+}
+
+// RBACMiddleware allows a request only if the user_role set by the authentication
+// middleware is at least requiredRole in the viewer < user < admin hierarchy
+func RBACMiddleware(requiredRole string, logger *zap.Logger) gin.HandlerFunc {
+	if _, known := roleLevels[requiredRole]; !known {
+		logger.Error("RBAC middleware configured with unknown role; denying all requests",
+			zap.String("required_role", requiredRole))
+	}
+
+	return func(c *gin.Context) {
+		role, exists := GetUserRoleFromContext(c)
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "User role not found",
+				"code":  "MISSING_ROLE",
+			})
+			c.Abort()
+			return
+		}
+
+		if !RoleAtLeast(role, requiredRole) {
+			logger.Warn("Insufficient role",
+				zap.String("role", role),
+				zap.String("required_role", requiredRole),
+				zap.String("method", c.Request.Method),
+				zap.String("path", c.Request.URL.Path),
+			)
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":         "Insufficient permissions",
+				"code":          "INSUFFICIENT_ROLE",
+				"role":          role,
+				"required_role": requiredRole,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+func TestRBACMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		role         string // empty leaves user_role unset
+		requiredRole string
+		wantStatus   int
+	}{
+		{"viewer reads viewer route", RoleViewer, RoleViewer, http.StatusOK},
+		{"viewer denied user route", RoleViewer, RoleUser, http.StatusForbidden},
+		{"viewer denied admin route", RoleViewer, RoleAdmin, http.StatusForbidden},
+		{"user reads viewer route", RoleUser, RoleViewer, http.StatusOK},
+		{"user on user route", RoleUser, RoleUser, http.StatusOK},
+		{"user denied admin route", RoleUser, RoleAdmin, http.StatusForbidden},
+		{"admin reads viewer route", RoleAdmin, RoleViewer, http.StatusOK},
+		{"admin on user route", RoleAdmin, RoleUser, http.StatusOK},
+		{"admin on admin route", RoleAdmin, RoleAdmin, http.StatusOK},
+		{"unknown role denied", "superuser", RoleViewer, http.StatusForbidden},
+		{"missing role", "", RoleViewer, http.StatusUnauthorized},
+		{"unknown required role denies admin", RoleAdmin, "owner", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				if tt.role != "" {
+					c.Set("user_role", tt.role)
+				}
+			})
+			router.DELETE("/servers/:id", RBACMiddleware(tt.requiredRole, zap.NewNop()), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/servers/1", nil))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestClerkRole(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		want   string
+	}{
+		{"custom role claim", jwt.MapClaims{"role": RoleAdmin, "org_role": "org:member"}, RoleAdmin},
+		{"organization admin", jwt.MapClaims{"org_role": "org:admin"}, RoleAdmin},
+		{"organization member", jwt.MapClaims{"org_role": "org:member"}, RoleUser},
+		{"organization viewer", jwt.MapClaims{"org_role": "org:viewer"}, RoleViewer},
+		{"unknown organization role", jwt.MapClaims{"org_role": "org:billing"}, RoleUser},
+		{"no role claims", jwt.MapClaims{"sub": "user_2abc"}, RoleUser},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clerkRole(tt.claims); got != tt.want {
+				t.Errorf("clerkRole() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
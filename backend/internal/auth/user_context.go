@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AuthenticatedUserID returns the caller's users.id. Tokens issued by this server carry
+// it in user_id as a uuid.UUID. Clerk, Authelia and introspected tokens set user_id to
+// the provider's subject string instead, so the user is looked up by user_email, the
+// same way OrgContextMiddleware resolves the organization. It writes 401 or 500 and
+// returns false if no user matches.
+func AuthenticatedUserID(c *gin.Context, resolver OrganizationResolver) (uuid.UUID, bool) {
+	if value, exists := c.Get("user_id"); exists {
+		if id, ok := value.(uuid.UUID); ok && id != uuid.Nil {
+			return id, true
+		}
+	}
+
+	email := c.GetString("user_email")
+	if email == "" || resolver == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found"})
+		return uuid.Nil, false
+	}
+
+	user, err := resolver.GetUserByEmail(c.Request.Context(), email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+			return uuid.Nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to look up user"})
+		return uuid.Nil, false
+	}
+
+	return user.ID, true
+}
@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+)
+
+// stubResolver returns the users it holds by email
+type stubResolver map[string]*database.User
+
+func (r stubResolver) GetUserByEmail(ctx context.Context, email string) (*database.User, error) {
+	if user, ok := r[email]; ok {
+		return user, nil
+	}
+	return nil, fmt.Errorf("failed to get user by email: %w", sql.ErrNoRows)
+}
+
+func TestAuthenticatedUserID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tokenUserID := uuid.New()
+	storedUser := &database.User{ID: uuid.New(), Email: "ada@example.com"}
+	resolver := stubResolver{storedUser.Email: storedUser}
+
+	tests := []struct {
+		name       string
+		keys       map[string]any
+		wantID     uuid.UUID
+		wantOK     bool
+		wantStatus int
+	}{
+		{
+			name:   "token issued by this server",
+			keys:   map[string]any{"user_id": tokenUserID, "user_email": storedUser.Email},
+			wantID: tokenUserID,
+			wantOK: true,
+		},
+		{
+			name:   "provider subject resolved by email",
+			keys:   map[string]any{"user_id": "user_2abcDEF", "user_email": storedUser.Email},
+			wantID: storedUser.ID,
+			wantOK: true,
+		},
+		{
+			name:       "unknown email",
+			keys:       map[string]any{"user_id": "user_2abcDEF", "user_email": "eve@example.com"},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "no identity",
+			keys:       map[string]any{},
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			for key, value := range tt.keys {
+				c.Set(key, value)
+			}
+
+			id, ok := AuthenticatedUserID(c, resolver)
+			if ok != tt.wantOK || id != tt.wantID {
+				t.Fatalf("AuthenticatedUserID() = %v, %v, want %v, %v", id, ok, tt.wantID, tt.wantOK)
+			}
+			if !tt.wantOK && w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
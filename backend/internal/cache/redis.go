@@ -0,0 +1,205 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RedisConfig holds Redis connection settings
+type RedisConfig struct {
+	Addr     string
+	Password string
+	DB       int
+	Timeout  time.Duration
+}
+
+// RedisClient is a minimal Redis client supporting the counter commands used for quotas.
+// Commands are serialized over a single connection that is re-dialed after errors.
+type RedisClient struct {
+	config RedisConfig
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewRedisClient creates a new Redis client. The connection is opened lazily.
+func NewRedisClient(config RedisConfig) *RedisClient {
+	if config.Timeout == 0 {
+		config.Timeout = 500 * time.Millisecond
+	}
+
+	return &RedisClient{
+		config: config,
+	}
+}
+
+// Ping checks that Redis is reachable
+func (r *RedisClient) Ping(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reply, err := r.do(ctx, "PING")
+	if err != nil {
+		return err
+	}
+	if reply != "PONG" {
+		return fmt.Errorf("unexpected PING reply: %v", reply)
+	}
+
+	return nil
+}
+
+// IncrWithExpiry increments key and sets its expiry when the key is first created.
+// It returns the value after the increment.
+func (r *RedisClient) IncrWithExpiry(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reply, err := r.do(ctx, "INCR", key)
+	if err != nil {
+		return 0, err
+	}
+
+	count, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected INCR reply: %v", reply)
+	}
+
+	if count == 1 {
+		if _, err := r.do(ctx, "EXPIRE", key, strconv.Itoa(int(ttl.Seconds()))); err != nil {
+			return 0, err
+		}
+	}
+
+	return count, nil
+}
+
+// Close closes the connection to Redis
+func (r *RedisClient) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.reset()
+}
+
+// do sends a command and reads its reply, reconnecting if needed. Callers must hold mu.
+func (r *RedisClient) do(ctx context.Context, args ...string) (interface{}, error) {
+	if r.conn == nil {
+		if err := r.connect(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	reply, err := r.roundTrip(ctx, args...)
+	if err != nil {
+		r.reset()
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+// connect dials Redis, authenticates and selects the configured database
+func (r *RedisClient) connect(ctx context.Context) error {
+	dialer := net.Dialer{Timeout: r.config.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", r.config.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to redis: %w", err)
+	}
+
+	r.conn = conn
+	r.reader = bufio.NewReader(conn)
+
+	if r.config.Password != "" {
+		if _, err := r.roundTrip(ctx, "AUTH", r.config.Password); err != nil {
+			r.reset()
+			return fmt.Errorf("failed to authenticate with redis: %w", err)
+		}
+	}
+
+	if r.config.DB != 0 {
+		if _, err := r.roundTrip(ctx, "SELECT", strconv.Itoa(r.config.DB)); err != nil {
+			r.reset()
+			return fmt.Errorf("failed to select redis database: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// reset drops the current connection
+func (r *RedisClient) reset() error {
+	if r.conn == nil {
+		return nil
+	}
+
+	err := r.conn.Close()
+	r.conn = nil
+	r.reader = nil
+	return err
+}
+
+// roundTrip writes a command in RESP format and reads a single reply
+func (r *RedisClient) roundTrip(ctx context.Context, args ...string) (interface{}, error) {
+	deadline := time.Now().Add(r.config.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	r.conn.SetDeadline(deadline)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+
+	if _, err := r.conn.Write([]byte(b.String())); err != nil {
+		return nil, fmt.Errorf("failed to write redis command: %w", err)
+	}
+
+	return r.readReply()
+}
+
+// readReply reads a simple string, error, integer or bulk string reply
+func (r *RedisClient) readReply() (interface{}, error) {
+	line, err := r.reader.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("failed to read redis reply: %w", err)
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("empty redis reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, fmt.Errorf("redis error: %s", line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		length, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis bulk length: %w", err)
+		}
+		if length < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, length+2)
+		if _, err := io.ReadFull(r.reader, buf); err != nil {
+			return nil, fmt.Errorf("failed to read redis bulk reply: %w", err)
+		}
+		return string(buf[:length]), nil
+	default:
+		return nil, fmt.Errorf("unsupported redis reply type: %q", line[0])
+	}
+}
@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 type Config struct {
 	Server   ServerConfig   `mapstructure:"server"`
 	Database DatabaseConfig `mapstructure:"database"`
@@ -7,7 +9,17 @@ type Config struct {
 	Logging  LoggingConfig  `mapstructure:"logging"`
 	Security SecurityConfig `mapstructure:"security"`
 	Clerk    ClerkConfig    `mapstructure:"clerk"`
+	OIDC     OIDCConfig     `mapstructure:"oidc"`
 	Supabase SupabaseConfig `mapstructure:"supabase"`
+	MCP      MCPConfig      `mapstructure:"mcp"`
+	Tools    ToolsConfig    `mapstructure:"tools"`
+	Redis    RedisConfig    `mapstructure:"redis"`
+	Registry RegistryConfig `mapstructure:"registry"`
+	CORS     CORSConfig     `mapstructure:"cors"`
+
+	Notifications NotificationsConfig `mapstructure:"notifications"`
+	Monitoring    MonitoringConfig    `mapstructure:"monitoring"`
+	Discovery     DiscoveryConfig     `mapstructure:"discovery"`
 }
 
 type ServerConfig struct {
@@ -15,6 +27,19 @@ type ServerConfig struct {
 	Environment  string `mapstructure:"environment" default:"development"`
 	ReadTimeout  int    `mapstructure:"read_timeout" default:"30"`
 	WriteTimeout int    `mapstructure:"write_timeout" default:"30"`
+	MetricsPort  int    `mapstructure:"metrics_port" default:"9091"`      // 0 disables the metrics server
+	MetricsHost  string `mapstructure:"metrics_host" default:"127.0.0.1"` // bind address for the metrics server
+
+	// GracefulShutdownTimeout is how long in-flight tool executions and health check
+	// probes may run after a shutdown signal before they are cancelled, in seconds
+	GracefulShutdownTimeout int `mapstructure:"graceful_shutdown_timeout" default:"30"`
+
+	// MaxRequestBodyBytes caps the size of request bodies; larger requests get 413
+	MaxRequestBodyBytes int64 `mapstructure:"max_request_body_bytes" default:"1048576"`
+
+	// TrustedProxies are the IP addresses or CIDR ranges of reverse proxies whose
+	// X-Forwarded-Proto header is used when building public URLs
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
 
 type DatabaseConfig struct {
@@ -24,6 +49,24 @@ type DatabaseConfig struct {
 	Password string `mapstructure:"password" default:"password"`
 	Name     string `mapstructure:"name" default:"aran_mcp"`
 	SSLMode  string `mapstructure:"ssl_mode" default:"disable"`
+
+	// ReadReplicaHost points analytics queries at a read replica. Empty uses the primary.
+	ReadReplicaHost string `mapstructure:"read_replica_host"`
+	ReadReplicaPort int    `mapstructure:"read_replica_port" default:"5432"`
+
+	// Connection pool limits, applied to the primary and the read replica
+	MaxOpenConns    int           `mapstructure:"max_open_conns" default:"25"`
+	MaxIdleConns    int           `mapstructure:"max_idle_conns" default:"5"`
+	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime" default:"5m"`
+}
+
+// RedisConfig configures the optional Redis instance used for quota counters.
+// An empty host disables Redis and quotas are counted in the database instead.
+type RedisConfig struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port" default:"6379"`
+	Password string `mapstructure:"password"`
+	DB       int    `mapstructure:"db" default:"0"`
 }
 
 type JWTConfig struct {
@@ -37,8 +80,105 @@ type LoggingConfig struct {
 }
 
 type SecurityConfig struct {
-	RateLimit   int  `mapstructure:"rate_limit" default:"100"`
-	EnableHTTPS bool `mapstructure:"enable_https" default:"false"`
+	RateLimit                int     `mapstructure:"rate_limit" default:"100"`              // requests per minute per client IP
+	RateLimitBurst           int     `mapstructure:"rate_limit_burst" default:"20"`         // of which at most this many within one second
+	APIKeyRateLimit          int     `mapstructure:"api_key_rate_limit" default:"1000"`     // requests per minute per API key or user
+	APIKeyRateLimitBurst     int     `mapstructure:"api_key_rate_limit_burst" default:"50"` // of which at most this many within one second
+	EnableHTTPS              bool    `mapstructure:"enable_https" default:"false"`
+	PluginDir                string  `mapstructure:"plugin_dir"`                               // directory of OWASP test plugins (.so)
+	ProfileRetentionDays     int     `mapstructure:"profile_retention_days" default:"90"`      // 0 keeps behavioral profiles forever
+	PromptInjectionThreshold float64 `mapstructure:"prompt_injection_threshold" default:"0.7"` // score above which prompts are rejected
+
+	// Every registered server is scanned with the OWASP MCP Top 10 tests on this cron
+	// schedule; servers averaging below OWASPScoreThreshold raise an alert
+	NightlyOWASPScanCron string `mapstructure:"nightly_owasp_scan_cron" default:"0 2 * * *"`
+	OWASPScoreThreshold  int    `mapstructure:"owasp_score_threshold" default:"70"`
+}
+
+// DiscoveryConfig configures zero-configuration discovery on the local network
+type DiscoveryConfig struct {
+	// EnableMDNS browses for _mcp._tcp.local. services during discovery and advertises
+	// the sentinel as _mcp-sentinel._tcp.local.
+	EnableMDNS bool `mapstructure:"enable_mdns" default:"false"`
+	// MDNSInstanceName is the advertised instance name; empty uses the hostname
+	MDNSInstanceName string `mapstructure:"mdns_instance_name"`
+	// PublishServers serves /.well-known/mcp-servers, which lists the online servers of
+	// every organization without authentication. Enable it only on single-tenant
+	// deployments.
+	PublishServers bool `mapstructure:"publish_servers" default:"false"`
+}
+
+// MonitoringConfig configures real-time alert streaming
+type MonitoringConfig struct {
+	MaxAlertBuffer int `mapstructure:"max_alert_buffer" default:"64"` // alerts queued per stream client before it is dropped
+
+	// Health checks of a server stop for CircuitBreakerOpenMinutes after this many
+	// consecutive failures; 0 disables the circuit breaker
+	CircuitBreakerFailureThreshold int `mapstructure:"circuit_breaker_failure_threshold" default:"5"`
+	CircuitBreakerOpenMinutes      int `mapstructure:"circuit_breaker_open_minutes" default:"5"`
+
+	// Repeats of an unresolved alert within this many minutes are counted on it
+	// instead of being stored as new alerts
+	AlertDedupMinutes int `mapstructure:"alert_dedup_minutes" default:"5"`
+
+	// Health scores are buffered in memory, up to this many, and flushed to the
+	// database every 5 minutes
+	HealthScoreBufferCapacity int `mapstructure:"health_score_buffer_capacity" default:"10080"`
+}
+
+type MCPConfig struct {
+	MaxConnsPerMCPServer int `mapstructure:"max_conns_per_server" default:"10"`
+
+	// MaxIdleConnsPerServer is how many keep-alive connections are kept open to each
+	// server; 0 keeps up to MaxConnsPerMCPServer
+	MaxIdleConnsPerServer int `mapstructure:"max_idle_conns_per_server" default:"0"`
+	// ResponseHeaderTimeoutSeconds fails requests whose response headers take longer;
+	// 0 leaves only the overall request timeout
+	ResponseHeaderTimeoutSeconds int `mapstructure:"response_header_timeout_seconds" default:"0"`
+
+	// ToolCacheTTLSeconds is how long tools and their usage stats are cached in memory
+	ToolCacheTTLSeconds int `mapstructure:"tool_cache_ttl_seconds" default:"60"`
+}
+
+// ToolsConfig contains settings for executing MCP tools
+type ToolsConfig struct {
+	// DefaultHourlyQuota limits the executions per hour of tools without their own hourly
+	// quota. 0 means unlimited.
+	DefaultHourlyQuota int `mapstructure:"default_hourly_quota" default:"0"`
+	// AllowedPatterns and DeniedPatterns are glob patterns of tool names. Denied tools are
+	// never executed; when AllowedPatterns is set, only tools matching it are.
+	AllowedPatterns []string `mapstructure:"allowed_patterns"`
+	DeniedPatterns  []string `mapstructure:"denied_patterns"`
+	// MaxConcurrentExecutions limits how many tools of a batch run at once
+	MaxConcurrentExecutions int `mapstructure:"max_concurrent_executions" default:"10"`
+}
+
+// RegistryConfig configures periodic syncing of one organization's server registry from
+// an external catalog. An empty CatalogURL disables the sync.
+type RegistryConfig struct {
+	CatalogURL            string `mapstructure:"catalog_url"`
+	CatalogOrganizationID string `mapstructure:"catalog_organization_id"`
+	SyncIntervalMinutes   int    `mapstructure:"sync_interval_minutes" default:"60"`
+	PruneAbsent           bool   `mapstructure:"prune_absent" default:"false"` // remove servers the catalog no longer lists
+
+	// ValidateOnRegister checks in the background that newly registered servers answer,
+	// storing them as reachability_pending until the check finishes. Unset means true.
+	ValidateOnRegister *bool `mapstructure:"validate_on_register"`
+}
+
+type NotificationsConfig struct {
+	DigestEnabled bool   `mapstructure:"digest_enabled" default:"false"`
+	DigestHour    int    `mapstructure:"digest_hour" default:"9"` // local hour of day per organization
+	SMTPHost      string `mapstructure:"smtp_host" default:"localhost"`
+	SMTPPort      int    `mapstructure:"smtp_port" default:"587"`
+	SMTPUsername  string `mapstructure:"smtp_username"`
+	SMTPPassword  string `mapstructure:"smtp_password"`
+	SMTPFrom      string `mapstructure:"smtp_from" default:"alerts@aran-mcp-sentinel.local"`
+
+	// Twilio credentials used to page sms: escalation contacts
+	TwilioAccountSID string `mapstructure:"twilio_account_sid"`
+	TwilioAuthToken  string `mapstructure:"twilio_auth_token"`
+	TwilioFromNumber string `mapstructure:"twilio_from_number"`
 }
 
 type SupabaseConfig struct {
@@ -62,3 +202,19 @@ type ClerkConfig struct {
 	// (server-side secret). Do NOT commit this to source control.
 	SecretKey string `mapstructure:"secret_key"`
 }
+
+// OIDCConfig contains settings used to validate access tokens with an OAuth2/OIDC
+// token introspection endpoint (RFC 7662)
+type OIDCConfig struct {
+	// IntrospectionURL enables introspection. With Clerk configured it is the fallback
+	// for tokens the JWKS cannot validate; otherwise it replaces Authelia.
+	IntrospectionURL string `mapstructure:"introspection_url"`
+
+	// ClientID and ClientSecret authenticate this backend to the introspection endpoint.
+	// Do NOT commit the secret to source control.
+	ClientID     string `mapstructure:"client_id"`
+	ClientSecret string `mapstructure:"client_secret"`
+
+	// CacheTTLSeconds is how long an active token is trusted without asking again
+	CacheTTLSeconds int `mapstructure:"cache_ttl_seconds" default:"60"`
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"net/url"
+	"strings"
+)
+
+// CORSConfig configures which origins may make cross-origin requests
+type CORSConfig struct {
+	// AllowedOrigins are origins such as https://app.example.com. A host of the form
+	// *.example.com matches any subdomain, and * matches every origin.
+	AllowedOrigins   []string `mapstructure:"allowed_origins"`
+	AllowCredentials bool     `mapstructure:"allow_credentials"`
+	MaxAge           int      `mapstructure:"max_age" default:"600"` // seconds browsers may cache preflight responses; 0 omits the header
+}
+
+// MatchOrigin reports whether origin matches an allowed origin pattern. A pattern
+// without a scheme matches any scheme, and *.example.com matches subdomains of
+// example.com but not example.com itself.
+func MatchOrigin(pattern, origin string) bool {
+	if pattern == "*" {
+		return origin != ""
+	}
+	if !strings.Contains(pattern, "*") {
+		return strings.EqualFold(pattern, origin)
+	}
+
+	parsed, err := url.Parse(origin)
+	if err != nil || parsed.Host == "" {
+		return false
+	}
+
+	host := pattern
+	if scheme, rest, ok := strings.Cut(pattern, "://"); ok {
+		if !strings.EqualFold(scheme, parsed.Scheme) {
+			return false
+		}
+		host = rest
+	}
+
+	suffix, ok := strings.CutPrefix(host, "*.")
+	if !ok || strings.Contains(suffix, "*") {
+		return false
+	}
+
+	originHost := strings.ToLower(parsed.Host)
+	suffix = "." + strings.ToLower(suffix)
+	return strings.HasSuffix(originHost, suffix) && len(originHost) > len(suffix)
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestMatchOrigin(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		origin  string
+		want    bool
+	}{
+		{"exact", "https://app.example.com", "https://app.example.com", true},
+		{"exact ignores case", "https://App.Example.com", "https://app.example.com", true},
+		{"exact other host", "https://app.example.com", "https://api.example.com", false},
+		{"exact other scheme", "https://app.example.com", "http://app.example.com", false},
+		{"exact other port", "https://app.example.com", "https://app.example.com:8443", false},
+		{"any", "*", "https://anything.test", true},
+		{"any without origin", "*", "", false},
+		{"subdomain", "https://*.example.com", "https://app.example.com", true},
+		{"nested subdomain", "https://*.example.com", "https://a.b.example.com", true},
+		{"subdomain ignores case", "https://*.example.com", "https://APP.Example.COM", true},
+		{"subdomain excludes bare domain", "https://*.example.com", "https://example.com", false},
+		{"subdomain other scheme", "https://*.example.com", "http://app.example.com", false},
+		{"subdomain without scheme", "*.example.com", "http://app.example.com", true},
+		{"subdomain with port", "https://*.example.com:8443", "https://app.example.com:8443", true},
+		{"subdomain other port", "https://*.example.com:8443", "https://app.example.com", false},
+		{"subdomain with unlisted port", "https://*.example.com", "https://app.example.com:8443", false},
+		{"subdomain lookalike", "https://*.example.com", "https://app.example.com.evil.test", false},
+		{"subdomain suffix lookalike", "https://*.example.com", "https://appexample.com", false},
+		{"subdomain without dot", "https://*example.com", "https://app.example.com", false},
+		{"inner wildcard", "https://app.*.example.com", "https://app.eu.example.com", false},
+		{"invalid origin", "https://*.example.com", "://app.example.com", false},
+		{"null origin", "https://*.example.com", "null", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchOrigin(tt.pattern, tt.origin); got != tt.want {
+				t.Errorf("MatchOrigin(%q, %q) = %v, want %v", tt.pattern, tt.origin, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigSyncAllowsOrigin(t *testing.T) {
+	s := NewConfigSync(nil, "", []string{"https://app.example.com", "https://*.preview.example.com"}, zap.NewNop())
+
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"https://app.example.com", true},
+		{"https://pr-12.preview.example.com", true},
+		{"https://preview.example.com", false},
+		{"https://evil.test", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := s.AllowsOrigin(tt.origin); got != tt.want {
+			t.Errorf("AllowsOrigin(%q) = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Handler handles runtime config endpoints
+type Handler struct {
+	configSync *ConfigSync
+	logger     *zap.Logger
+}
+
+// NewHandler creates a new runtime config handler
+func NewHandler(configSync *ConfigSync, logger *zap.Logger) *Handler {
+	return &Handler{
+		configSync: configSync,
+		logger:     logger,
+	}
+}
+
+// RegisterRoutes registers runtime config routes
+func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
+	router.PUT("/config", h.UpdateConfig)
+}
+
+// UpdateConfigRequest represents a request to change a runtime config value
+type UpdateConfigRequest struct {
+	Key   string          `json:"key" binding:"required"`
+	Value json.RawMessage `json:"value" binding:"required"`
+}
+
+// UpdateConfig stores a runtime config value and applies it on every instance
+func (h *Handler) UpdateConfig(c *gin.Context) {
+	var req UpdateConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if _, err := parseRuntimeValue(req.Key, req.Value); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var updatedBy *string
+	if userID, exists := c.Get("user_id"); exists {
+		id := fmt.Sprint(userID)
+		updatedBy = &id
+	}
+
+	entry, err := h.configSync.Set(c.Request.Context(), req.Key, req.Value, updatedBy)
+	if err != nil {
+		h.logger.Error("Failed to update runtime config", zap.String("key", req.Key), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update runtime config"})
+		return
+	}
+
+	h.logger.Info("Updated runtime config", zap.String("key", req.Key))
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    entry,
+	})
+}
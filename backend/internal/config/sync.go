@@ -0,0 +1,196 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"go.uber.org/zap"
+)
+
+// Runtime config keys shared by all instances
+const (
+	RuntimeKeyCORSAllowedOrigins = "cors_allowed_origins"
+)
+
+// ConfigSync keeps runtime config in step across instances. Values live in the
+// runtime_config table and every change is announced with NOTIFY so all instances
+// reload the key.
+type ConfigSync struct {
+	repo   *database.Repository
+	dsn    string
+	logger *zap.Logger
+
+	mu                 sync.RWMutex
+	corsAllowedOrigins []string
+}
+
+// NewConfigSync creates a config sync. corsAllowedOrigins is used until a value is stored.
+func NewConfigSync(repo *database.Repository, dsn string, corsAllowedOrigins []string, logger *zap.Logger) *ConfigSync {
+	return &ConfigSync{
+		repo:               repo,
+		dsn:                dsn,
+		logger:             logger,
+		corsAllowedOrigins: corsAllowedOrigins,
+	}
+}
+
+// CORSAllowedOrigins returns the origins allowed to make cross-origin requests
+func (s *ConfigSync) CORSAllowedOrigins() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]string(nil), s.corsAllowedOrigins...)
+}
+
+// AllowsOrigin reports whether origin may make cross-origin requests
+func (s *ConfigSync) AllowsOrigin(origin string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, allowed := range s.corsAllowedOrigins {
+		if MatchOrigin(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// Load applies every stored runtime config value
+func (s *ConfigSync) Load(ctx context.Context) error {
+	entries, err := s.repo.ListRuntimeConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := s.apply(entry.Key, entry.Value); err != nil {
+			s.logger.Error("Failed to apply runtime config", zap.String("key", entry.Key), zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// Set validates and stores a runtime config value, applies it locally and notifies the
+// other instances
+func (s *ConfigSync) Set(ctx context.Context, key string, value json.RawMessage, updatedBy *string) (*database.RuntimeConfigEntry, error) {
+	if _, err := parseRuntimeValue(key, value); err != nil {
+		return nil, err
+	}
+
+	entry := &database.RuntimeConfigEntry{
+		Key:       key,
+		Value:     value,
+		UpdatedBy: updatedBy,
+	}
+	if err := s.repo.SetRuntimeConfig(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	if err := s.apply(key, value); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// Start listens for config changes until the context is cancelled
+func (s *ConfigSync) Start(ctx context.Context) {
+	listener := pq.NewListener(s.dsn, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			s.logger.Warn("Runtime config listener event", zap.Error(err))
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(database.RuntimeConfigChannel); err != nil {
+		s.logger.Error("Failed to listen for runtime config changes", zap.Error(err))
+		return
+	}
+
+	s.logger.Info("Listening for runtime config changes", zap.String("channel", database.RuntimeConfigChannel))
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Stopping runtime config listener")
+			return
+		case notification := <-listener.Notify:
+			// A nil notification means the connection was re-established and changes
+			// may have been missed, so reload everything
+			if notification == nil {
+				if err := s.Load(ctx); err != nil {
+					s.logger.Error("Failed to reload runtime config", zap.Error(err))
+				}
+				continue
+			}
+			s.reload(ctx, notification.Extra)
+		case <-time.After(90 * time.Second):
+			go listener.Ping()
+		}
+	}
+}
+
+// reload reads a changed key from the database and applies it
+func (s *ConfigSync) reload(ctx context.Context, key string) {
+	entry, err := s.repo.GetRuntimeConfig(ctx, key)
+	if err != nil {
+		s.logger.Error("Failed to reload runtime config", zap.String("key", key), zap.Error(err))
+		return
+	}
+
+	if err := s.apply(entry.Key, entry.Value); err != nil {
+		s.logger.Error("Failed to apply runtime config", zap.String("key", key), zap.Error(err))
+		return
+	}
+
+	fields := []zap.Field{zap.String("key", key)}
+	if entry.UpdatedBy != nil {
+		fields = append(fields, zap.String("updated_by", *entry.UpdatedBy))
+	}
+	s.logger.Info("Reloaded runtime config", fields...)
+}
+
+// apply updates the in-memory value of a key
+func (s *ConfigSync) apply(key string, value json.RawMessage) error {
+	parsed, err := parseRuntimeValue(key, value)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch key {
+	case RuntimeKeyCORSAllowedOrigins:
+		s.corsAllowedOrigins = parsed.([]string)
+	}
+
+	return nil
+}
+
+// parseRuntimeValue validates a value for a supported key
+func parseRuntimeValue(key string, value json.RawMessage) (interface{}, error) {
+	switch key {
+	case RuntimeKeyCORSAllowedOrigins:
+		var origins []string
+		if err := json.Unmarshal(value, &origins); err != nil {
+			return nil, fmt.Errorf("%s must be a list of origins", key)
+		}
+		for i, origin := range origins {
+			origins[i] = strings.TrimSpace(origin)
+			if origins[i] == "" {
+				return nil, fmt.Errorf("%s must not contain empty origins", key)
+			}
+		}
+		return origins, nil
+	default:
+		return nil, fmt.Errorf("unsupported runtime config key: %s", key)
+	}
+}
@@ -0,0 +1,149 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit log page sizes
+const (
+	DefaultAuditLogPageSize = 50
+	MaxAuditLogPageSize     = 500
+)
+
+// sensitiveAuditDetailKeys are detail keys that are never stored in an audit log
+var sensitiveAuditDetailKeys = map[string]bool{
+	"password": true,
+	"key_hash": true,
+}
+
+// AuditLogQuery holds the filters for reviewing an organization's audit logs
+type AuditLogQuery struct {
+	OrganizationID uuid.UUID
+	UserID         *uuid.UUID
+	Action         string
+	ResourceType   string
+	ResourceID     *uuid.UUID
+	IPAddress      string
+	Since          *time.Time // inclusive
+	Until          *time.Time // exclusive
+	Limit          int
+	Offset         int
+}
+
+// conditions returns the query's WHERE conditions with their arguments
+func (q AuditLogQuery) conditions() ([]string, []interface{}) {
+	args := []interface{}{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	conditions := []string{"organization_id = " + arg(q.OrganizationID)}
+	if q.UserID != nil {
+		conditions = append(conditions, "user_id = "+arg(*q.UserID))
+	}
+	if q.Action != "" {
+		conditions = append(conditions, "action = "+arg(q.Action))
+	}
+	if q.ResourceType != "" {
+		conditions = append(conditions, "resource_type = "+arg(q.ResourceType))
+	}
+	if q.ResourceID != nil {
+		conditions = append(conditions, "resource_id = "+arg(*q.ResourceID))
+	}
+	if q.IPAddress != "" {
+		conditions = append(conditions, "host(ip_address) = host("+arg(q.IPAddress)+"::inet)")
+	}
+	if q.Since != nil {
+		conditions = append(conditions, "created_at >= "+arg(*q.Since))
+	}
+	if q.Until != nil {
+		conditions = append(conditions, "created_at < "+arg(*q.Until))
+	}
+
+	return conditions, args
+}
+
+// QueryAuditLogs returns a page of an organization's audit logs matching the query,
+// newest first, with the total number of matches
+func (r *Repository) QueryAuditLogs(ctx context.Context, q AuditLogQuery) ([]*AuditLog, int64, error) {
+	if q.Limit <= 0 {
+		q.Limit = DefaultAuditLogPageSize
+	}
+	if q.Limit > MaxAuditLogPageSize {
+		q.Limit = MaxAuditLogPageSize
+	}
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+
+	conditions, args := q.conditions()
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	var total int64
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM audit_logs"+where, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit logs: %w", err)
+	}
+
+	query := `
+		SELECT id, organization_id, user_id, action, resource_type, resource_id, details,
+		       host(ip_address) AS ip_address, user_agent, created_at
+		FROM audit_logs` + where + fmt.Sprintf(`
+		ORDER BY created_at DESC, id
+		LIMIT $%d OFFSET $%d
+	`, len(args)+1, len(args)+2)
+	args = append(args, q.Limit, q.Offset)
+
+	logs := []*AuditLog{}
+	if err := r.db.SelectContext(ctx, &logs, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to query audit logs: %w", err)
+	}
+
+	return logs, total, nil
+}
+
+// sanitizeAuditDetails returns a copy of details without sensitive keys, at any depth
+func sanitizeAuditDetails(details JSONB) JSONB {
+	if details == nil {
+		return nil
+	}
+	return sanitizeAuditValue(map[string]interface{}(details)).(map[string]interface{})
+}
+
+// sanitizeAuditValue removes sensitive keys from the maps within a JSON value
+func sanitizeAuditValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		clean := make(map[string]interface{}, len(v))
+		for key, nested := range v {
+			if sensitiveAuditDetailKeys[strings.ToLower(key)] {
+				continue
+			}
+			clean[key] = sanitizeAuditValue(nested)
+		}
+		return clean
+	case JSONB:
+		return sanitizeAuditValue(map[string]interface{}(v))
+	case JSONBArray:
+		return sanitizeAuditValue([]interface{}(v))
+	case []interface{}:
+		clean := make([]interface{}, len(v))
+		for i, nested := range v {
+			clean[i] = sanitizeAuditValue(nested)
+		}
+		return clean
+	case []map[string]interface{}:
+		clean := make([]interface{}, len(v))
+		for i, nested := range v {
+			clean[i] = sanitizeAuditValue(nested)
+		}
+		return clean
+	default:
+		return value
+	}
+}
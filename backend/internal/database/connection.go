@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"time"
 
@@ -10,6 +11,13 @@ import (
 	"go.uber.org/zap"
 )
 
+// Connection pool defaults, used for Config fields left at zero
+const (
+	DefaultMaxOpenConns    = 25
+	DefaultMaxIdleConns    = 5
+	DefaultConnMaxLifetime = 5 * time.Minute
+)
+
 // Config holds database configuration
 type Config struct {
 	Host     string
@@ -18,18 +26,31 @@ type Config struct {
 	Password string
 	DBName   string
 	SSLMode  string
+
+	// Connection pool limits; zero values use the defaults above
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
 }
 
 // Connection wraps the database connection
 type Connection struct {
 	DB     *sqlx.DB
 	Logger *zap.Logger
+
+	// dsn connects migrations, which run on their own connection
+	dsn string
+}
+
+// DSN returns the connection string for the configuration
+func (cfg Config) DSN() string {
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode)
 }
 
 // NewConnection creates a new database connection
 func NewConnection(cfg Config, logger *zap.Logger) (*Connection, error) {
-	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode)
+	dsn := cfg.DSN()
 	
 	logger.Info("Database connection string", zap.String("dsn", dsn))
 
@@ -38,10 +59,7 @@ func NewConnection(cfg Config, logger *zap.Logger) (*Connection, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	// Configure connection pool
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(5 * time.Minute)
+	configurePool(db, cfg)
 
 	// Test the connection
 	if err := db.Ping(); err != nil {
@@ -53,9 +71,38 @@ func NewConnection(cfg Config, logger *zap.Logger) (*Connection, error) {
 	return &Connection{
 		DB:     db,
 		Logger: logger,
+		dsn:    dsn,
 	}, nil
 }
 
+// configurePool applies the pool limits of cfg, or the defaults for those left at zero
+func configurePool(db *sqlx.DB, cfg Config) {
+	maxOpen := cfg.MaxOpenConns
+	if maxOpen <= 0 {
+		maxOpen = DefaultMaxOpenConns
+	}
+	maxIdle := cfg.MaxIdleConns
+	if maxIdle <= 0 {
+		maxIdle = DefaultMaxIdleConns
+	}
+	if maxIdle > maxOpen {
+		maxIdle = maxOpen
+	}
+	lifetime := cfg.ConnMaxLifetime
+	if lifetime <= 0 {
+		lifetime = DefaultConnMaxLifetime
+	}
+
+	db.SetMaxOpenConns(maxOpen)
+	db.SetMaxIdleConns(maxIdle)
+	db.SetConnMaxLifetime(lifetime)
+}
+
+// PoolStats returns the connection pool statistics
+func (c *Connection) PoolStats() sql.DBStats {
+	return c.DB.Stats()
+}
+
 // Close closes the database connection
 func (c *Connection) Close() error {
 	if c.DB != nil {
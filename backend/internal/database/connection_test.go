@@ -0,0 +1,142 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// poolTestDriver hands out connections that count how many are open at once
+type poolTestDriver struct {
+	open    atomic.Int32
+	maxOpen atomic.Int32
+}
+
+func (d *poolTestDriver) Open(name string) (driver.Conn, error) {
+	open := d.open.Add(1)
+	for {
+		max := d.maxOpen.Load()
+		if open <= max || d.maxOpen.CompareAndSwap(max, open) {
+			break
+		}
+	}
+	return &poolTestConn{driver: d}, nil
+}
+
+// poolTestConn answers every statement after a short delay, so concurrent callers
+// contend for connections
+type poolTestConn struct {
+	driver *poolTestDriver
+}
+
+func (c *poolTestConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	time.Sleep(2 * time.Millisecond)
+	return driver.RowsAffected(1), nil
+}
+
+func (c *poolTestConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *poolTestConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *poolTestConn) Close() error {
+	c.driver.open.Add(-1)
+	return nil
+}
+
+// openPoolTestDB opens a database on a fresh poolTestDriver
+func openPoolTestDB(t *testing.T) (*sqlx.DB, *poolTestDriver) {
+	t.Helper()
+
+	d := &poolTestDriver{}
+	db := sqlx.NewDb(sql.OpenDB(poolTestConnector{d}), "postgres")
+	t.Cleanup(func() { db.Close() })
+	return db, d
+}
+
+// poolTestConnector connects to a poolTestDriver
+type poolTestConnector struct {
+	driver *poolTestDriver
+}
+
+func (c poolTestConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return c.driver.Open("")
+}
+
+func (c poolTestConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+func TestConfigurePoolLimitsConcurrentQueries(t *testing.T) {
+	db, d := openPoolTestDB(t)
+	configurePool(db, Config{MaxOpenConns: 10, MaxIdleConns: 4})
+
+	const requests = 200
+	var wg sync.WaitGroup
+	errs := make(chan error, requests)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := db.ExecContext(context.Background(), "SELECT 1"); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatalf("ExecContext() error = %v", err)
+	}
+
+	if max := d.maxOpen.Load(); max > 10 {
+		t.Errorf("%d connections were open at once, want at most 10", max)
+	}
+	stats := db.Stats()
+	if stats.MaxOpenConnections != 10 {
+		t.Errorf("MaxOpenConnections = %d, want 10", stats.MaxOpenConnections)
+	}
+	if stats.WaitCount == 0 {
+		t.Error("WaitCount = 0, want queries to have waited for a connection")
+	}
+	if stats.Idle > 4 {
+		t.Errorf("Idle = %d, want at most 4", stats.Idle)
+	}
+	if stats.InUse != 0 {
+		t.Errorf("InUse = %d after every query finished, want 0", stats.InUse)
+	}
+}
+
+func TestConfigurePoolDefaults(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         Config
+		wantMaxOpen int
+	}{
+		{"defaults", Config{}, DefaultMaxOpenConns},
+		{"configured", Config{MaxOpenConns: 40, MaxIdleConns: 10, ConnMaxLifetime: time.Minute}, 40},
+		{"negative", Config{MaxOpenConns: -1}, DefaultMaxOpenConns},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, _ := openPoolTestDB(t)
+			configurePool(db, tt.cfg)
+
+			if got := db.Stats().MaxOpenConnections; got != tt.wantMaxOpen {
+				t.Errorf("MaxOpenConnections = %d, want %d", got, tt.wantMaxOpen)
+			}
+		})
+	}
+}
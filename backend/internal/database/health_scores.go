@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// HealthScoreSample is a server's health score at one health check
+type HealthScoreSample struct {
+	ServerID   uuid.UUID `db:"server_id" json:"server_id"`
+	Score      int       `db:"score" json:"score"`
+	RecordedAt time.Time `db:"recorded_at" json:"recorded_at"`
+}
+
+// InsertHealthScores stores health score samples in a single statement
+func (r *Repository) InsertHealthScores(ctx context.Context, samples []HealthScoreSample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	serverIDs := make([]string, len(samples))
+	scores := make([]int64, len(samples))
+	recordedAt := make([]string, len(samples))
+	for i, sample := range samples {
+		serverIDs[i] = sample.ServerID.String()
+		scores[i] = int64(sample.Score)
+		recordedAt[i] = sample.RecordedAt.Format(time.RFC3339Nano)
+	}
+
+	query := `
+		INSERT INTO health_score_history (server_id, score, recorded_at)
+		SELECT * FROM unnest($1::uuid[], $2::int[], $3::timestamptz[])
+	`
+	if _, err := r.db.ExecContext(ctx, query, pq.Array(serverIDs), pq.Array(scores), pq.Array(recordedAt)); err != nil {
+		return fmt.Errorf("failed to insert health scores: %w", err)
+	}
+
+	return nil
+}
+
+// GetHealthScoreHistory returns a server's health scores recorded since a time, oldest first
+func (r *Repository) GetHealthScoreHistory(ctx context.Context, serverID uuid.UUID, since time.Time) ([]HealthScoreSample, error) {
+	samples := []HealthScoreSample{}
+	query := `
+		SELECT server_id, score, recorded_at FROM health_score_history
+		WHERE server_id = $1 AND recorded_at >= $2
+		ORDER BY recorded_at ASC
+	`
+	if err := r.db.SelectContext(ctx, &samples, query, serverID, since); err != nil {
+		return nil, fmt.Errorf("failed to get health score history: %w", err)
+	}
+
+	return samples, nil
+}
+
+// LatestHealthScores returns the most recent health score of each of the servers that
+// has one, in a single query
+func (r *Repository) LatestHealthScores(ctx context.Context, serverIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	ids := make([]string, len(serverIDs))
+	for i, serverID := range serverIDs {
+		ids[i] = serverID.String()
+	}
+
+	samples := []HealthScoreSample{}
+	query := `
+		SELECT DISTINCT ON (server_id) server_id, score, recorded_at FROM health_score_history
+		WHERE server_id = ANY($1::uuid[])
+		ORDER BY server_id, recorded_at DESC
+	`
+	if err := r.db.SelectContext(ctx, &samples, query, pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("failed to get latest health scores: %w", err)
+	}
+
+	scores := make(map[uuid.UUID]int, len(samples))
+	for _, sample := range samples {
+		scores[sample.ServerID] = sample.Score
+	}
+	return scores, nil
+}
@@ -0,0 +1,157 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"go.uber.org/zap"
+)
+
+// migrationFiles holds the schema migrations, named NNN_title.up.sql and
+// NNN_title.down.sql
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// MigrateUp applies every migration not yet applied to the database. It stops after
+// the running migration if ctx is cancelled.
+func (c *Connection) MigrateUp(ctx context.Context) error {
+	m, err := c.newMigrate()
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			m.GracefulStop <- true
+		case <-done:
+		}
+	}()
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+
+	c.logMigrationVersion(m)
+	return nil
+}
+
+// MigrateDown reverts the last n applied migrations
+func (c *Connection) MigrateDown(n int) error {
+	if n <= 0 {
+		return fmt.Errorf("number of migrations to revert must be positive, got %d", n)
+	}
+
+	m, err := c.newMigrate()
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	if err := m.Steps(-n); err != nil {
+		return fmt.Errorf("failed to revert migrations: %w", err)
+	}
+
+	c.logMigrationVersion(m)
+	return nil
+}
+
+// MigrationVersion returns the version of the last applied migration, and whether it
+// failed part way and left the schema dirty. The version is 0 if none was applied.
+func (c *Connection) MigrationVersion() (version uint, dirty bool, err error) {
+	m, err := c.newMigrate()
+	if err != nil {
+		return 0, false, err
+	}
+	defer m.Close()
+
+	version, dirty, err = m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get migration version: %w", err)
+	}
+	return version, dirty, nil
+}
+
+// ForceMigrationVersion records version as the last applied migration without running
+// any, clearing the dirty flag. Use it to adopt a database whose schema was applied by
+// hand, or after fixing a migration that failed part way.
+func (c *Connection) ForceMigrationVersion(version int) error {
+	m, err := c.newMigrate()
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	if err := m.Force(version); err != nil {
+		return fmt.Errorf("failed to force migration version: %w", err)
+	}
+	return nil
+}
+
+// newMigrate opens a migrator over the embedded migrations. It uses its own pgx
+// connection, which closing the migrator closes, so the pool in DB is unaffected.
+func (c *Connection) newMigrate() (*migrate.Migrate, error) {
+	source, err := iofs.New(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	db, err := sql.Open("pgx/v5", c.dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open migration connection: %w", err)
+	}
+
+	driver, err := pgx.WithInstance(db, &pgx.Config{})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create migration driver: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", source, "pgx5", driver)
+	if err != nil {
+		driver.Close()
+		return nil, fmt.Errorf("failed to create migrator: %w", err)
+	}
+	m.Log = migrateLogger{logger: c.Logger}
+
+	return m, nil
+}
+
+// logMigrationVersion logs the schema version after migrating
+func (c *Connection) logMigrationVersion(m *migrate.Migrate) {
+	version, dirty, err := m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		c.Logger.Warn("Failed to get migration version", zap.Error(err))
+		return
+	}
+	c.Logger.Info("Database schema migrated", zap.Uint("version", version), zap.Bool("dirty", dirty))
+}
+
+// migrateLogger passes golang-migrate's progress messages to zap
+type migrateLogger struct {
+	logger *zap.Logger
+}
+
+func (l migrateLogger) Printf(format string, v ...interface{}) {
+	l.logger.Info("Migration", zap.String("message", fmt.Sprintf(format, v...)))
+}
+
+func (l migrateLogger) Verbose() bool {
+	return false
+}
@@ -0,0 +1,13 @@
+-- Revert: Initial schema for Aran MCP Sentinel
+-- Created: 2024-01-01
+
+DROP TABLE IF EXISTS audit_logs;
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS security_tests;
+DROP TABLE IF EXISTS alerts;
+DROP TABLE IF EXISTS server_status_history;
+DROP TABLE IF EXISTS mcp_servers;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS organizations;
+
+DROP FUNCTION IF EXISTS update_updated_at_column();
@@ -0,0 +1,10 @@
+-- Revert: MCP Tools and Executions Tables
+-- Created: 2024-01-02
+
+DROP TABLE IF EXISTS incident_responses;
+DROP TABLE IF EXISTS compliance_reports;
+DROP TABLE IF EXISTS security_scans;
+DROP TABLE IF EXISTS mcp_prompts;
+DROP TABLE IF EXISTS mcp_resources;
+DROP TABLE IF EXISTS tool_executions;
+DROP TABLE IF EXISTS mcp_tools;
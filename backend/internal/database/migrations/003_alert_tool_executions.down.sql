@@ -0,0 +1,5 @@
+-- Revert: Link alerts to the tool executions that caused them
+-- Created: 2024-01-03
+
+DROP INDEX IF EXISTS idx_alerts_tool_execution_id;
+ALTER TABLE alerts DROP COLUMN IF EXISTS tool_execution_id;
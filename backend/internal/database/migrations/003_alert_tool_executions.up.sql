@@ -0,0 +1,7 @@
+-- Link alerts to the tool executions that caused them
+-- Created: 2024-01-03
+
+ALTER TABLE alerts
+    ADD COLUMN tool_execution_id UUID REFERENCES tool_executions(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_alerts_tool_execution_id ON alerts(tool_execution_id);
@@ -0,0 +1,4 @@
+-- Revert: Per-server health check configuration
+-- Created: 2024-01-04
+
+ALTER TABLE mcp_servers DROP COLUMN IF EXISTS health_check_config;
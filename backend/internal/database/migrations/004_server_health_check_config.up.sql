@@ -0,0 +1,7 @@
+-- Per-server health check configuration
+-- Created: 2024-01-04
+
+-- method: jsonrpc_ping (default), http_get or jsonrpc_initialize
+-- path: request path used by http_get
+ALTER TABLE mcp_servers
+    ADD COLUMN health_check_config JSONB DEFAULT '{"method": "jsonrpc_ping"}';
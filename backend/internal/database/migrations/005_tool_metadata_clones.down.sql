@@ -0,0 +1,4 @@
+-- Revert: Track tool metadata copied between servers
+-- Created: 2024-01-05
+
+DROP TABLE IF EXISTS tool_metadata_clones;
@@ -0,0 +1,16 @@
+-- Track tool metadata copied between servers
+-- Created: 2024-01-05
+
+CREATE TABLE tool_metadata_clones (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    source_tool_id UUID NOT NULL REFERENCES mcp_tools(id) ON DELETE CASCADE,
+    target_tool_id UUID NOT NULL REFERENCES mcp_tools(id) ON DELETE CASCADE,
+    category VARCHAR(50),
+    tags JSONB DEFAULT '[]',
+    risk_level VARCHAR(20),
+    cloned_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_tool_metadata_clones_source_tool_id ON tool_metadata_clones(source_tool_id);
+CREATE INDEX idx_tool_metadata_clones_target_tool_id ON tool_metadata_clones(target_tool_id);
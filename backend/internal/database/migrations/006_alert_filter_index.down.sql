@@ -0,0 +1,4 @@
+-- Revert: Support filtering alerts by server, level and time
+-- Created: 2024-01-06
+
+DROP INDEX IF EXISTS idx_alerts_server_severity_created_at;
@@ -0,0 +1,4 @@
+-- Support filtering alerts by server, level and time
+-- Created: 2024-01-06
+
+CREATE INDEX idx_alerts_server_severity_created_at ON alerts(server_id, severity, created_at DESC);
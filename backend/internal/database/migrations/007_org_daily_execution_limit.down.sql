@@ -0,0 +1,4 @@
+-- Revert: Per-organization daily tool execution limit
+-- Created: 2024-01-07
+
+ALTER TABLE organizations DROP COLUMN IF EXISTS daily_execution_limit;
@@ -0,0 +1,5 @@
+-- Per-organization daily tool execution limit
+-- Created: 2024-01-07
+
+-- 0 means unlimited
+ALTER TABLE organizations ADD COLUMN daily_execution_limit INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,5 @@
+-- Revert: Server groups for rolling up health across a collection of servers
+-- Created: 2024-01-08
+
+DROP TABLE IF EXISTS server_group_members;
+DROP TABLE IF EXISTS server_groups;
@@ -0,0 +1,22 @@
+-- Server groups for rolling up health across a collection of servers
+-- Created: 2024-01-08
+
+CREATE TABLE server_groups (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(organization_id, name)
+);
+
+CREATE TABLE server_group_members (
+    group_id UUID NOT NULL REFERENCES server_groups(id) ON DELETE CASCADE,
+    server_id UUID NOT NULL REFERENCES mcp_servers(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (group_id, server_id)
+);
+
+CREATE INDEX idx_server_groups_organization_id ON server_groups(organization_id);
+CREATE INDEX idx_server_group_members_server_id ON server_group_members(server_id);
@@ -0,0 +1,4 @@
+-- Revert: Recurring security scans
+-- Created: 2024-01-09
+
+DROP TABLE IF EXISTS scheduled_scans;
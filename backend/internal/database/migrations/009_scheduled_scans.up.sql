@@ -0,0 +1,19 @@
+-- Recurring security scans
+-- Created: 2024-01-09
+
+CREATE TABLE scheduled_scans (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    server_id UUID NOT NULL REFERENCES mcp_servers(id) ON DELETE CASCADE,
+    test_types TEXT[] NOT NULL DEFAULT '{}', -- empty runs every test type
+    cron_expression VARCHAR(100) NOT NULL,
+    notify_on_failure BOOLEAN DEFAULT false,
+    last_run_at TIMESTAMP WITH TIME ZONE,
+    next_run_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_scheduled_scans_organization_id ON scheduled_scans(organization_id);
+CREATE INDEX idx_scheduled_scans_next_run_at ON scheduled_scans(next_run_at);
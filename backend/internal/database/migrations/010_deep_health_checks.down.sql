@@ -0,0 +1,5 @@
+-- Revert: Deep health checks: tool count tracking and per-step timing history
+-- Created: 2024-01-10
+
+DROP TABLE IF EXISTS server_health_check_steps;
+ALTER TABLE mcp_servers DROP COLUMN IF EXISTS known_tool_count;
@@ -0,0 +1,15 @@
+-- Deep health checks: tool count tracking and per-step timing history
+-- Created: 2024-01-10
+
+ALTER TABLE mcp_servers ADD COLUMN known_tool_count INTEGER;
+
+CREATE TABLE server_health_check_steps (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    server_url TEXT NOT NULL,
+    step VARCHAR(50) NOT NULL,
+    duration_ms INTEGER NOT NULL,
+    success BOOLEAN NOT NULL,
+    checked_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_server_health_check_steps_url_step ON server_health_check_steps(server_url, step, checked_at DESC);
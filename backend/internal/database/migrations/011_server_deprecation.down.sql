@@ -0,0 +1,7 @@
+-- Revert: Server deprecation workflow
+-- Created: 2024-01-11
+
+ALTER TABLE mcp_servers DROP COLUMN IF EXISTS replacement_server_id;
+ALTER TABLE mcp_servers DROP COLUMN IF EXISTS deprecation_message;
+ALTER TABLE mcp_servers DROP COLUMN IF EXISTS deprecated_at;
+ALTER TABLE mcp_servers DROP COLUMN IF EXISTS is_deprecated;
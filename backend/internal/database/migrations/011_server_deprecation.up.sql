@@ -0,0 +1,7 @@
+-- Server deprecation workflow
+-- Created: 2024-01-11
+
+ALTER TABLE mcp_servers ADD COLUMN is_deprecated BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE mcp_servers ADD COLUMN deprecated_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE mcp_servers ADD COLUMN deprecation_message TEXT NOT NULL DEFAULT '';
+ALTER TABLE mcp_servers ADD COLUMN replacement_server_id UUID REFERENCES mcp_servers(id) ON DELETE SET NULL;
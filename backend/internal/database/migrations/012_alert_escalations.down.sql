@@ -0,0 +1,5 @@
+-- Revert: Alert escalation policies and escalation state
+-- Created: 2024-01-12
+
+DROP TABLE IF EXISTS alert_escalations;
+DROP TABLE IF EXISTS escalation_policies;
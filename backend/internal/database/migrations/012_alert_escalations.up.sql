@@ -0,0 +1,23 @@
+-- Alert escalation policies and escalation state
+-- Created: 2024-01-12
+
+CREATE TABLE escalation_policies (
+    organization_id UUID PRIMARY KEY REFERENCES organizations(id) ON DELETE CASCADE,
+    level1_delay_seconds INTEGER NOT NULL,
+    level1_contacts TEXT[] NOT NULL DEFAULT '{}',
+    level2_delay_seconds INTEGER NOT NULL DEFAULT 0,
+    level2_contacts TEXT[] NOT NULL DEFAULT '{}',
+    level3_contacts TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE alert_escalations (
+    alert_id UUID PRIMARY KEY REFERENCES alerts(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    level INTEGER NOT NULL,
+    last_escalated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_alert_escalations_organization_id ON alert_escalations(organization_id);
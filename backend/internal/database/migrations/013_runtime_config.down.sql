@@ -0,0 +1,4 @@
+-- Revert: Runtime configuration shared by all instances
+-- Created: 2024-01-13
+
+DROP TABLE IF EXISTS runtime_config;
@@ -0,0 +1,9 @@
+-- Runtime configuration shared by all instances
+-- Created: 2024-01-13
+
+CREATE TABLE runtime_config (
+    key VARCHAR(100) PRIMARY KEY,
+    value JSONB NOT NULL,
+    updated_by VARCHAR(255),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
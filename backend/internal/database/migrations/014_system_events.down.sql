@@ -0,0 +1,9 @@
+-- Revert: Allow audit logs and alerts for system-wide events
+-- Created: 2024-01-14
+
+-- System-wide events have no organization and cannot be kept
+DELETE FROM audit_logs WHERE organization_id IS NULL;
+DELETE FROM alerts WHERE organization_id IS NULL;
+
+ALTER TABLE audit_logs ALTER COLUMN organization_id SET NOT NULL;
+ALTER TABLE alerts ALTER COLUMN organization_id SET NOT NULL;
@@ -0,0 +1,7 @@
+-- Allow audit logs and alerts for system-wide events
+-- Created: 2024-01-14
+
+-- Events such as CORS violations happen before a request is authenticated and
+-- belong to no organization
+ALTER TABLE audit_logs ALTER COLUMN organization_id DROP NOT NULL;
+ALTER TABLE alerts ALTER COLUMN organization_id DROP NOT NULL;
@@ -0,0 +1,5 @@
+-- Revert: Tool output schemas
+-- Created: 2024-01-15
+
+ALTER TABLE tool_executions DROP COLUMN IF EXISTS result_schema_invalid;
+ALTER TABLE mcp_tools DROP COLUMN IF EXISTS output_schema;
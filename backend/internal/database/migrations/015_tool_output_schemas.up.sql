@@ -0,0 +1,5 @@
+-- Tool output schemas
+-- Created: 2024-01-15
+
+ALTER TABLE mcp_tools ADD COLUMN output_schema JSONB;
+ALTER TABLE tool_executions ADD COLUMN result_schema_invalid BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,4 @@
+-- Revert: Persisted behavioral analysis profiles
+-- Created: 2024-01-16
+
+DROP TABLE IF EXISTS agent_profiles;
@@ -0,0 +1,11 @@
+-- Persisted behavioral analysis profiles
+-- Created: 2024-01-16
+
+CREATE TABLE agent_profiles (
+    agent_id VARCHAR(255) PRIMARY KEY,
+    profile JSONB NOT NULL,
+    last_seen TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_agent_profiles_last_seen ON agent_profiles(last_seen);
@@ -0,0 +1,4 @@
+-- Revert: Last successful server initialization
+-- Created: 2024-01-17
+
+ALTER TABLE mcp_servers DROP COLUMN IF EXISTS last_successful_init_at;
@@ -0,0 +1,4 @@
+-- Last successful server initialization
+-- Created: 2024-01-17
+
+ALTER TABLE mcp_servers ADD COLUMN last_successful_init_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,5 @@
+-- Revert: TCP latency measurements
+-- Created: 2024-01-18
+
+ALTER TABLE mcp_servers DROP COLUMN IF EXISTS tcp_latency_ms;
+ALTER TABLE server_status_history DROP COLUMN IF EXISTS tcp_latency_ms;
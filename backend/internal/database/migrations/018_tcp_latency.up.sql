@@ -0,0 +1,5 @@
+-- TCP latency measurements
+-- Created: 2024-01-18
+
+ALTER TABLE server_status_history ADD COLUMN tcp_latency_ms INTEGER;
+ALTER TABLE mcp_servers ADD COLUMN tcp_latency_ms INTEGER;
@@ -0,0 +1,5 @@
+-- Revert: Support listing a tool's execution history by status and time
+-- Created: 2024-01-19
+
+DROP INDEX IF EXISTS idx_tool_executions_tool_executed_at;
+DROP INDEX IF EXISTS idx_tool_executions_tool_status;
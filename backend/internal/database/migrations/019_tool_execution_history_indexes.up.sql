@@ -0,0 +1,6 @@
+-- Support listing a tool's execution history by status and time
+-- Created: 2024-01-19
+
+-- Most executions complete, so only failed and running ones are indexed by status
+CREATE INDEX idx_tool_executions_tool_status ON tool_executions(tool_id, status) WHERE status <> 'completed';
+CREATE INDEX idx_tool_executions_tool_executed_at ON tool_executions(tool_id, executed_at DESC) WHERE executed_at IS NOT NULL;
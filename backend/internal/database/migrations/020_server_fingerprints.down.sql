@@ -0,0 +1,6 @@
+-- Revert: Server identity fingerprints
+-- Created: 2024-01-20
+
+ALTER TABLE mcp_servers DROP COLUMN IF EXISTS identity_changed_at;
+ALTER TABLE mcp_servers DROP COLUMN IF EXISTS previous_fingerprint;
+ALTER TABLE mcp_servers DROP COLUMN IF EXISTS fingerprint;
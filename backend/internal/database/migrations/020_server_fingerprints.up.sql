@@ -0,0 +1,6 @@
+-- Server identity fingerprints
+-- Created: 2024-01-20
+
+ALTER TABLE mcp_servers ADD COLUMN fingerprint VARCHAR(64);
+ALTER TABLE mcp_servers ADD COLUMN previous_fingerprint VARCHAR(64);
+ALTER TABLE mcp_servers ADD COLUMN identity_changed_at TIMESTAMP WITH TIME ZONE;
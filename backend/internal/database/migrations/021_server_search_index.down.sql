@@ -0,0 +1,5 @@
+-- Revert: Full-text search over MCP servers
+-- Created: 2024-01-21
+
+DROP INDEX IF EXISTS idx_mcp_servers_tsv;
+ALTER TABLE mcp_servers DROP COLUMN IF EXISTS tsv;
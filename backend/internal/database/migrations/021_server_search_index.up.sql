@@ -0,0 +1,9 @@
+-- Full-text search over MCP servers
+-- Created: 2024-01-21
+
+ALTER TABLE mcp_servers ADD COLUMN tsv tsvector
+    GENERATED ALWAYS AS (
+        to_tsvector('english', coalesce(name, '') || ' ' || coalesce(description, '') || ' ' || coalesce(type, ''))
+    ) STORED;
+
+CREATE INDEX idx_mcp_servers_tsv ON mcp_servers USING GIN (tsv);
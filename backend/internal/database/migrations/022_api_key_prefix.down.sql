@@ -0,0 +1,5 @@
+-- Revert: API key prefixes
+-- Created: 2024-01-22
+
+DROP INDEX IF EXISTS idx_api_keys_key_prefix;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS key_prefix;
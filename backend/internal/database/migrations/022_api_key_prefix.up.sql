@@ -0,0 +1,8 @@
+-- API key prefixes
+-- Created: 2024-01-22
+
+-- The first characters of a key are stored in clear so keys can be listed and looked up
+-- without keeping the key itself
+ALTER TABLE api_keys ADD COLUMN key_prefix VARCHAR(8);
+
+CREATE INDEX idx_api_keys_key_prefix ON api_keys(key_prefix);
@@ -0,0 +1,5 @@
+-- Revert: Tool list keyset pagination
+-- Created: 2024-01-23
+
+CREATE INDEX IF NOT EXISTS idx_mcp_tools_usage_count ON mcp_tools(usage_count DESC);
+DROP INDEX IF EXISTS idx_mcp_tools_usage_count_id;
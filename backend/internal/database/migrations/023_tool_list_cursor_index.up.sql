@@ -0,0 +1,8 @@
+-- Tool list keyset pagination
+-- Created: 2024-01-23
+
+-- Tools are listed most used first and paged with a (usage_count, id) cursor
+CREATE INDEX idx_mcp_tools_usage_count_id ON mcp_tools(usage_count DESC, id ASC) WHERE deleted_at IS NULL;
+
+-- Superseded by the compound index
+DROP INDEX IF EXISTS idx_mcp_tools_usage_count;
@@ -0,0 +1,7 @@
+-- Revert: Persisted discovery results
+-- Created: 2024-01-24
+
+-- Duplicate servers soft-deleted by the up migration stay deleted
+DROP INDEX IF EXISTS idx_mcp_servers_org_url_unique;
+ALTER TABLE mcp_servers DROP COLUMN IF EXISTS discovery_method;
+ALTER TABLE mcp_servers DROP COLUMN IF EXISTS discovered_at;
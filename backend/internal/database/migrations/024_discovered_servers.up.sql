@@ -0,0 +1,21 @@
+-- Persisted discovery results
+-- Created: 2024-01-24
+
+ALTER TABLE mcp_servers ADD COLUMN discovered_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE mcp_servers ADD COLUMN discovery_method VARCHAR(50);
+
+-- Discovery upserts servers by organization and URL, so each URL may belong to one
+-- live server per organization. Keep the most recently updated of any existing
+-- duplicates within an organization; servers of other organizations are untouched.
+UPDATE mcp_servers SET deleted_at = NOW()
+WHERE deleted_at IS NULL
+  AND id IN (
+    SELECT id FROM (
+      SELECT id, ROW_NUMBER() OVER (PARTITION BY organization_id, url ORDER BY updated_at DESC, created_at DESC) AS rank
+      FROM mcp_servers
+      WHERE deleted_at IS NULL
+    ) ranked
+    WHERE rank > 1
+  );
+
+CREATE UNIQUE INDEX idx_mcp_servers_org_url_unique ON mcp_servers(organization_id, url) WHERE deleted_at IS NULL;
@@ -0,0 +1,4 @@
+-- Revert: OWASP MCP Top 10 test results, kept per run for score history
+-- Created: 2024-01-25
+
+DROP TABLE IF EXISTS owasp_test_results;
@@ -0,0 +1,17 @@
+-- OWASP MCP Top 10 test results, kept per run for score history
+-- Created: 2024-01-25
+
+CREATE TABLE owasp_test_results (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    run_id UUID NOT NULL, -- shared by the results of one run of all tests
+    server_id UUID NOT NULL REFERENCES mcp_servers(id) ON DELETE CASCADE,
+    test_id VARCHAR(255) NOT NULL,
+    category VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL, -- PASS, FAIL, WARN, ERROR
+    score INTEGER NOT NULL DEFAULT 0,
+    details JSONB DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_owasp_test_results_server_created ON owasp_test_results(server_id, created_at DESC);
+CREATE INDEX idx_owasp_test_results_run_id ON owasp_test_results(run_id);
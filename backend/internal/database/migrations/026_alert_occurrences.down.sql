@@ -0,0 +1,5 @@
+-- Revert: Alert deduplication
+-- Created: 2024-01-26
+
+DROP INDEX IF EXISTS idx_alerts_unresolved_dedup;
+ALTER TABLE alerts DROP COLUMN IF EXISTS occurrences;
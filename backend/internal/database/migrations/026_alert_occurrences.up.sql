@@ -0,0 +1,8 @@
+-- Alert deduplication
+-- Created: 2024-01-26
+
+-- Repeats of an unresolved monitoring alert within the dedup window are counted on the
+-- existing alert instead of being inserted
+ALTER TABLE alerts ADD COLUMN occurrences INT NOT NULL DEFAULT 1;
+
+CREATE INDEX idx_alerts_unresolved_dedup ON alerts(server_id, title, created_at DESC) WHERE resolved_at IS NULL;
@@ -0,0 +1,4 @@
+-- Revert: Health score history
+-- Created: 2024-01-27
+
+DROP TABLE IF EXISTS health_score_history;
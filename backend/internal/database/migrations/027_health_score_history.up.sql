@@ -0,0 +1,12 @@
+-- Health score history
+-- Created: 2024-01-27
+
+-- Health scores computed on each health check, flushed from memory in batches
+CREATE TABLE health_score_history (
+    id BIGSERIAL PRIMARY KEY,
+    server_id UUID NOT NULL REFERENCES mcp_servers(id) ON DELETE CASCADE,
+    score INT NOT NULL CHECK (score BETWEEN 0 AND 100),
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_health_score_history_server_recorded ON health_score_history(server_id, recorded_at DESC);
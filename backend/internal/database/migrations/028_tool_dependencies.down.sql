@@ -0,0 +1,4 @@
+-- Revert: Tool dependencies
+-- Created: 2024-01-28
+
+ALTER TABLE mcp_tools DROP COLUMN IF EXISTS dependencies;
@@ -0,0 +1,5 @@
+-- Tool dependencies
+-- Created: 2024-01-28
+
+-- Names of the tools on the same server that must succeed before a tool runs
+ALTER TABLE mcp_tools ADD COLUMN dependencies JSONB NOT NULL DEFAULT '[]';
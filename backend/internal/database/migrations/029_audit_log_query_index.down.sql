@@ -0,0 +1,4 @@
+-- Revert: Audit log queries
+-- Created: 2024-01-29
+
+DROP INDEX IF EXISTS idx_audit_logs_org_created;
@@ -0,0 +1,5 @@
+-- Audit log queries
+-- Created: 2024-01-29
+
+-- Audit log reviews list one organization's entries newest first
+CREATE INDEX idx_audit_logs_org_created ON audit_logs(organization_id, created_at DESC);
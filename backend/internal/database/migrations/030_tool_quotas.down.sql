@@ -0,0 +1,4 @@
+-- Revert: Tool execution quotas
+-- Created: 2024-01-30
+
+DROP TABLE IF EXISTS tool_quotas;
@@ -0,0 +1,12 @@
+-- Tool execution quotas
+-- Created: 2024-01-30
+
+-- Hard limits on how often a tool may run. A NULL hourly limit falls back to the
+-- configured default; 0, or a NULL daily limit, is unlimited.
+CREATE TABLE tool_quotas (
+    tool_id UUID PRIMARY KEY REFERENCES mcp_tools(id) ON DELETE CASCADE,
+    max_executions_per_hour INTEGER CHECK (max_executions_per_hour >= 0),
+    max_executions_per_day INTEGER CHECK (max_executions_per_day >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
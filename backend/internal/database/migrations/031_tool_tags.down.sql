@@ -0,0 +1,6 @@
+-- Revert: Tool tags
+-- Created: 2024-01-31
+
+-- mcp_tools.tags is kept in sync with tool_tags, so no tags are lost
+DROP TABLE IF EXISTS tool_tags;
+DROP FUNCTION IF EXISTS sync_mcp_tool_tags();
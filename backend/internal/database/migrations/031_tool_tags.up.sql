@@ -0,0 +1,47 @@
+-- Tool tags
+-- Created: 2024-01-31
+
+-- btree_gin lets a GIN index cover the scalar tag column; tags repeat across many tools,
+-- which GIN's posting lists store compactly
+CREATE EXTENSION IF NOT EXISTS btree_gin;
+
+-- One row per tag of a tool. mcp_tools.tags is kept as a denormalized copy for
+-- existing readers and is maintained by the trigger below.
+CREATE TABLE tool_tags (
+    tool_id UUID NOT NULL REFERENCES mcp_tools(id) ON DELETE CASCADE,
+    tag VARCHAR(100) NOT NULL,
+    PRIMARY KEY (tool_id, tag)
+);
+
+CREATE INDEX idx_tool_tags_tag ON tool_tags USING GIN (tag);
+
+INSERT INTO tool_tags (tool_id, tag)
+SELECT t.id, LEFT(tag, 100)
+FROM mcp_tools t, jsonb_array_elements_text(t.tags) AS tag
+WHERE jsonb_typeof(t.tags) = 'array' AND tag <> ''
+ON CONFLICT DO NOTHING;
+
+CREATE OR REPLACE FUNCTION sync_mcp_tool_tags() RETURNS TRIGGER AS $$
+DECLARE
+    changed_tool_id UUID;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        changed_tool_id := OLD.tool_id;
+    ELSE
+        changed_tool_id := NEW.tool_id;
+    END IF;
+
+    UPDATE mcp_tools SET tags = (
+        SELECT COALESCE(jsonb_agg(tag ORDER BY tag), '[]'::jsonb)
+        FROM tool_tags
+        WHERE tool_id = changed_tool_id
+    )
+    WHERE id = changed_tool_id;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tool_tags_sync
+    AFTER INSERT OR DELETE ON tool_tags
+    FOR EACH ROW EXECUTE FUNCTION sync_mcp_tool_tags();
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // JSONB represents a JSONB field that can be marshaled/unmarshaled
@@ -34,6 +35,32 @@ func (j *JSONB) Scan(value interface{}) error {
 	return json.Unmarshal(bytes, j)
 }
 
+// HealthCheckConfig holds how an MCP server's health is checked
+type HealthCheckConfig struct {
+	Method string `json:"method"`         // jsonrpc_ping, http_get or jsonrpc_initialize
+	Path   string `json:"path,omitempty"` // request path for http_get
+}
+
+// Value implements the driver.Valuer interface
+func (h HealthCheckConfig) Value() (driver.Value, error) {
+	return json.Marshal(h)
+}
+
+// Scan implements the sql.Scanner interface
+func (h *HealthCheckConfig) Scan(value interface{}) error {
+	if value == nil {
+		*h = HealthCheckConfig{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return json.Unmarshal([]byte(value.(string)), h)
+	}
+
+	return json.Unmarshal(bytes, h)
+}
+
 // JSONBArray represents a JSONB field that can handle both arrays and objects
 type JSONBArray []interface{}
 
@@ -62,15 +89,16 @@ func (j *JSONBArray) Scan(value interface{}) error {
 
 // Organization represents an organization in the system
 type Organization struct {
-	ID          uuid.UUID `db:"id" json:"id"`
-	Name        string    `db:"name" json:"name"`
-	Slug        string    `db:"slug" json:"slug"`
-	Email       string    `db:"email" json:"email"`
-	Description *string   `db:"description" json:"description"`
-	Settings    JSONB     `db:"settings" json:"settings"`
-	CreatedAt   time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
-	DeletedAt   *time.Time `db:"deleted_at" json:"deleted_at,omitempty"`
+	ID                  uuid.UUID  `db:"id" json:"id"`
+	Name                string     `db:"name" json:"name"`
+	Slug                string     `db:"slug" json:"slug"`
+	Email               string     `db:"email" json:"email"`
+	Description         *string    `db:"description" json:"description"`
+	Settings            JSONB      `db:"settings" json:"settings"`
+	DailyExecutionLimit int        `db:"daily_execution_limit" json:"daily_execution_limit"` // 0 means unlimited
+	CreatedAt           time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt           time.Time  `db:"updated_at" json:"updated_at"`
+	DeletedAt           *time.Time `db:"deleted_at" json:"deleted_at,omitempty"`
 }
 
 // User represents a user in the system
@@ -91,24 +119,67 @@ type User struct {
 
 // MCPServer represents an MCP server in the system
 type MCPServer struct {
-	ID               uuid.UUID  `db:"id" json:"id"`
-	OrganizationID   uuid.UUID  `db:"organization_id" json:"organization_id"`
-	Name             string     `db:"name" json:"name"`
-	URL              string     `db:"url" json:"url"`
-	Description      *string    `db:"description" json:"description"`
-	Type             string     `db:"type" json:"type"`
-	Status           string     `db:"status" json:"status"`
-	Version          *string    `db:"version" json:"version"`
-	Capabilities     JSONBArray `db:"capabilities" json:"capabilities"`
-	Metadata         JSONB      `db:"metadata" json:"metadata"`
-	LastCheckedAt    *time.Time `db:"last_checked_at" json:"last_checked_at,omitempty"`
-	ResponseTimeMs   *int       `db:"response_time_ms" json:"response_time_ms,omitempty"`
-	UptimePercentage *float64   `db:"uptime_percentage" json:"uptime_percentage,omitempty"`
-	ErrorRate        *float64   `db:"error_rate" json:"error_rate,omitempty"`
-	CreatedBy        *uuid.UUID `db:"created_by" json:"created_by,omitempty"`
-	CreatedAt        time.Time  `db:"created_at" json:"created_at"`
-	UpdatedAt        time.Time  `db:"updated_at" json:"updated_at"`
-	DeletedAt        *time.Time `db:"deleted_at" json:"deleted_at,omitempty"`
+	ID                   uuid.UUID         `db:"id" json:"id"`
+	OrganizationID       uuid.UUID         `db:"organization_id" json:"organization_id"`
+	Name                 string            `db:"name" json:"name"`
+	URL                  string            `db:"url" json:"url"`
+	Description          *string           `db:"description" json:"description"`
+	Type                 string            `db:"type" json:"type"`
+	Status               string            `db:"status" json:"status"`
+	Version              *string           `db:"version" json:"version"`
+	Capabilities         JSONBArray        `db:"capabilities" json:"capabilities"`
+	Metadata             JSONB             `db:"metadata" json:"metadata"`
+	HealthCheck          HealthCheckConfig `db:"health_check_config" json:"health_check"`
+	LastCheckedAt        *time.Time        `db:"last_checked_at" json:"last_checked_at,omitempty"`
+	LastSuccessfulInitAt *time.Time        `db:"last_successful_init_at" json:"last_successful_init_at,omitempty"`
+	ResponseTimeMs       *int              `db:"response_time_ms" json:"response_time_ms,omitempty"`
+	TCPLatencyMs         *int              `db:"tcp_latency_ms" json:"tcp_latency_ms,omitempty"`
+	UptimePercentage     *float64          `db:"uptime_percentage" json:"uptime_percentage,omitempty"`
+	ErrorRate            *float64          `db:"error_rate" json:"error_rate,omitempty"`
+	KnownToolCount       *int              `db:"known_tool_count" json:"known_tool_count,omitempty"`
+	IsDeprecated         bool              `db:"is_deprecated" json:"is_deprecated"`
+	DeprecatedAt         *time.Time        `db:"deprecated_at" json:"deprecated_at,omitempty"`
+	DeprecationMessage   string            `db:"deprecation_message" json:"deprecation_message,omitempty"`
+	ReplacementServerID  *uuid.UUID        `db:"replacement_server_id" json:"replacement_server_id,omitempty"`
+	Fingerprint          *string           `db:"fingerprint" json:"fingerprint,omitempty"`
+	PreviousFingerprint  *string           `db:"previous_fingerprint" json:"previous_fingerprint,omitempty"`
+	IdentityChangedAt    *time.Time        `db:"identity_changed_at" json:"identity_changed_at,omitempty"`
+	SearchVector         *string           `db:"tsv" json:"-"`
+	DiscoveredAt         *time.Time        `db:"discovered_at" json:"discovered_at,omitempty"`
+	DiscoveryMethod      *string           `db:"discovery_method" json:"discovery_method,omitempty"`
+	CreatedBy            *uuid.UUID        `db:"created_by" json:"created_by,omitempty"`
+	CreatedAt            time.Time         `db:"created_at" json:"created_at"`
+	UpdatedAt            time.Time         `db:"updated_at" json:"updated_at"`
+	DeletedAt            *time.Time        `db:"deleted_at" json:"deleted_at,omitempty"`
+
+	// CheckInterval is how often the server's health is checked; nil uses the default.
+	// It is stored in metadata["check_interval_seconds"].
+	CheckInterval *time.Duration `db:"-" json:"check_interval,omitempty"`
+}
+
+// CheckIntervalMetadataKey is the metadata key holding a server's health check interval
+const CheckIntervalMetadataKey = "check_interval_seconds"
+
+// loadCheckInterval sets CheckInterval from the server's metadata
+func (s *MCPServer) loadCheckInterval() {
+	s.CheckInterval = nil
+	switch seconds := s.Metadata[CheckIntervalMetadataKey].(type) {
+	case float64:
+		if seconds > 0 {
+			interval := time.Duration(seconds * float64(time.Second))
+			s.CheckInterval = &interval
+		}
+	}
+}
+
+// ServerGroup represents a named collection of MCP servers
+type ServerGroup struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Name           string    `db:"name" json:"name"`
+	Description    *string   `db:"description" json:"description"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
 }
 
 // ServerStatusHistory represents the status history of an MCP server
@@ -117,6 +188,7 @@ type ServerStatusHistory struct {
 	ServerID       uuid.UUID `db:"server_id" json:"server_id"`
 	Status         string    `db:"status" json:"status"`
 	ResponseTimeMs *int      `db:"response_time_ms" json:"response_time_ms,omitempty"`
+	TCPLatencyMs   *int      `db:"tcp_latency_ms" json:"tcp_latency_ms,omitempty"`
 	ErrorMessage   *string   `db:"error_message" json:"error_message,omitempty"`
 	CheckedAt      time.Time `db:"checked_at" json:"checked_at"`
 }
@@ -126,6 +198,7 @@ type Alert struct {
 	ID             uuid.UUID  `db:"id" json:"id"`
 	OrganizationID uuid.UUID  `db:"organization_id" json:"organization_id"`
 	ServerID       *uuid.UUID `db:"server_id" json:"server_id,omitempty"`
+	ExecutionID    *uuid.UUID `db:"tool_execution_id" json:"execution_id,omitempty"`
 	Type           string     `db:"type" json:"type"`
 	Severity       string     `db:"severity" json:"severity"`
 	Title          string     `db:"title" json:"title"`
@@ -134,10 +207,29 @@ type Alert struct {
 	ResolvedAt     *time.Time `db:"resolved_at" json:"resolved_at,omitempty"`
 	ResolvedBy     *uuid.UUID `db:"resolved_by" json:"resolved_by,omitempty"`
 	Metadata       JSONB      `db:"metadata" json:"metadata"`
+	Occurrences    int        `db:"occurrences" json:"occurrences"` // repeats coalesced into this alert
 	CreatedAt      time.Time  `db:"created_at" json:"created_at"`
 	UpdatedAt      time.Time  `db:"updated_at" json:"updated_at"`
 }
 
+// ServerAlertCount represents the number of alerts raised for a server
+type ServerAlertCount struct {
+	ServerID   uuid.UUID `db:"server_id" json:"server_id"`
+	ServerName string    `db:"server_name" json:"server_name"`
+	AlertCount int       `db:"alert_count" json:"alert_count"`
+}
+
+// AlertDigest represents an aggregated summary of alerts for notification digests
+type AlertDigest struct {
+	OrganizationID     uuid.UUID          `json:"organization_id"`
+	Since              time.Time          `json:"since"`
+	TotalAlerts        int                `json:"total_alerts"`
+	BySeverity         map[string]int     `json:"by_severity"`
+	ByType             map[string]int     `json:"by_type"`
+	TopServers         []ServerAlertCount `json:"top_servers"`
+	UnresolvedCritical []*Alert           `json:"unresolved_critical"`
+}
+
 // SecurityTest represents a security test in the system
 type SecurityTest struct {
 	ID             uuid.UUID  `db:"id" json:"id"`
@@ -156,6 +248,48 @@ type SecurityTest struct {
 	UpdatedAt      time.Time  `db:"updated_at" json:"updated_at"`
 }
 
+// ScheduledScan represents a recurring security scan of an MCP server
+type ScheduledScan struct {
+	ID              uuid.UUID      `db:"id" json:"id"`
+	OrganizationID  uuid.UUID      `db:"organization_id" json:"organization_id"`
+	ServerID        uuid.UUID      `db:"server_id" json:"server_id"`
+	TestTypes       pq.StringArray `db:"test_types" json:"test_types"` // empty runs every test type
+	CronExpression  string         `db:"cron_expression" json:"cron_expression"`
+	NotifyOnFailure bool           `db:"notify_on_failure" json:"notify_on_failure"`
+	LastRunAt       *time.Time     `db:"last_run_at" json:"last_run_at,omitempty"`
+	NextRunAt       time.Time      `db:"next_run_at" json:"next_run_at"`
+	CreatedBy       *uuid.UUID     `db:"created_by" json:"created_by,omitempty"`
+	CreatedAt       time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+// EscalationPolicy represents an organization's on-call escalation policy for critical alerts
+type EscalationPolicy struct {
+	OrganizationID     uuid.UUID      `db:"organization_id" json:"organization_id"`
+	Level1DelaySeconds int            `db:"level1_delay_seconds" json:"level1_delay_seconds"`
+	Level1Contacts     pq.StringArray `db:"level1_contacts" json:"level1_contacts"`
+	Level2DelaySeconds int            `db:"level2_delay_seconds" json:"level2_delay_seconds"`
+	Level2Contacts     pq.StringArray `db:"level2_contacts" json:"level2_contacts"`
+	Level3Contacts     pq.StringArray `db:"level3_contacts" json:"level3_contacts"`
+	CreatedAt          time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt          time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+// PendingEscalation is an unresolved critical alert with its current escalation level
+type PendingEscalation struct {
+	Alert
+	EscalationLevel int        `db:"escalation_level"` // 0 when the alert has not been escalated
+	LastEscalatedAt *time.Time `db:"last_escalated_at"`
+}
+
+// RuntimeConfigEntry represents a runtime configuration value shared by all instances
+type RuntimeConfigEntry struct {
+	Key       string          `db:"key" json:"key"`
+	Value     json.RawMessage `db:"value" json:"value"`
+	UpdatedBy *string         `db:"updated_by" json:"updated_by,omitempty"`
+	UpdatedAt time.Time       `db:"updated_at" json:"updated_at"`
+}
+
 // APIKey represents an API key in the system
 type APIKey struct {
 	ID             uuid.UUID  `db:"id" json:"id"`
@@ -163,6 +297,7 @@ type APIKey struct {
 	UserID         *uuid.UUID `db:"user_id" json:"user_id,omitempty"`
 	Name           string     `db:"name" json:"name"`
 	KeyHash        string     `db:"key_hash" json:"-"`
+	KeyPrefix      *string    `db:"key_prefix" json:"key_prefix"`
 	Permissions    JSONB      `db:"permissions" json:"permissions"`
 	LastUsedAt     *time.Time `db:"last_used_at" json:"last_used_at,omitempty"`
 	ExpiresAt      *time.Time `db:"expires_at" json:"expires_at,omitempty"`
@@ -204,12 +339,14 @@ type CreateUserRequest struct {
 
 // CreateMCPServerRequest represents a request to create an MCP server
 type CreateMCPServerRequest struct {
-	OrganizationID uuid.UUID `json:"organization_id" validate:"required"`
-	Name           string    `json:"name" validate:"required,min=1,max=255"`
-	URL            string    `json:"url" validate:"required,url"`
-	Description    *string   `json:"description,omitempty"`
-	Type           string    `json:"type" validate:"required,oneof=filesystem database api custom"`
-	CreatedBy      uuid.UUID `json:"created_by" validate:"required"`
+	OrganizationID uuid.UUID          `json:"organization_id" validate:"required"`
+	Name           string             `json:"name" validate:"required,min=1,max=255"`
+	URL            string             `json:"url" validate:"required,url"`
+	Description    *string            `json:"description,omitempty"`
+	Type           string             `json:"type" validate:"required,oneof=filesystem database api custom"`
+	Metadata       JSONB              `json:"metadata,omitempty"`
+	HealthCheck    *HealthCheckConfig `json:"health_check,omitempty"`
+	CreatedBy      uuid.UUID          `json:"created_by" validate:"required"`
 }
 
 // UpdateMCPServerRequest represents a request to update an MCP server
@@ -243,3 +380,72 @@ type ServerStatus struct {
 	Version          *string   `json:"version,omitempty"`
 	Capabilities     JSONBArray `json:"capabilities"`
 }
+
+// MetricsBucket represents aggregated server metrics for a single time bucket
+type MetricsBucket struct {
+	Timestamp       time.Time `db:"bucket" json:"timestamp"`
+	Label           string    `db:"-" json:"label"`
+	AvgResponseTime float64   `db:"avg_response_time" json:"avg_response_time_ms"`
+	P95ResponseTime float64   `db:"p95_response_time" json:"p95_response_time_ms"`
+	Availability    float64   `db:"availability" json:"availability"`
+	Checks          int       `db:"checks" json:"checks"`
+}
+
+// ToolExecutionBucket represents the number of executions of a tool in one time bucket
+type ToolExecutionBucket struct {
+	ToolID   uuid.UUID `db:"tool_id" json:"tool_id"`
+	ToolName string    `db:"tool_name" json:"tool_name"`
+	Bucket   int       `db:"bucket" json:"bucket"`
+	Count    int64     `db:"count" json:"count"`
+}
+
+// ServerHealthSummary represents the health of an organization's servers
+type ServerHealthSummary struct {
+	TotalServers      int      `db:"total_servers" json:"total_servers"`
+	OnlineServers     int      `db:"online_servers" json:"online_servers"`
+	OfflineServers    int      `db:"offline_servers" json:"offline_servers"`
+	UnknownServers    int      `db:"unknown_servers" json:"unknown_servers"`
+	AvgResponseTimeMs *float64 `db:"avg_response_time_ms" json:"avg_response_time_ms,omitempty"`
+	AvgUptime         *float64 `db:"avg_uptime" json:"avg_uptime,omitempty"`
+}
+
+// ToolUsageCount represents the number of executions of a tool
+type ToolUsageCount struct {
+	ToolID     uuid.UUID `db:"tool_id" json:"tool_id"`
+	ToolName   string    `db:"tool_name" json:"tool_name"`
+	Executions int64     `db:"executions" json:"executions"`
+}
+
+// ToolExecutionStats represents an organization's tool executions since a point in time
+type ToolExecutionStats struct {
+	Since     time.Time         `json:"since"`
+	Total     int64             `json:"total"`
+	Completed int64             `json:"completed"`
+	Failed    int64             `json:"failed"`
+	TopTools  []*ToolUsageCount `json:"top_tools"`
+}
+
+// SecurityScore represents the latest completed security test score of a server
+type SecurityScore struct {
+	ServerID    uuid.UUID  `db:"server_id" json:"server_id"`
+	ServerName  string     `db:"server_name" json:"server_name"`
+	Score       *int       `db:"score" json:"score,omitempty"`
+	Result      *string    `db:"result" json:"result,omitempty"`
+	CompletedAt *time.Time `db:"completed_at" json:"completed_at,omitempty"`
+}
+
+// ToolDefinition is the name, description and input schema a server advertises for a tool
+type ToolDefinition struct {
+	Name        string  `db:"name" json:"name"`
+	Description *string `db:"description" json:"description,omitempty"`
+	InputSchema JSONB   `db:"input_schema" json:"input_schema"`
+}
+
+// MetricsSeries represents a time series of server metrics
+type MetricsSeries struct {
+	ServerID   uuid.UUID        `json:"server_id"`
+	Resolution string           `json:"resolution"`
+	Start      time.Time        `json:"start"`
+	End        time.Time        `json:"end"`
+	Buckets    []*MetricsBucket `json:"buckets"`
+}
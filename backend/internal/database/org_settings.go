@@ -0,0 +1,91 @@
+package database
+
+import (
+	"fmt"
+)
+
+// ToolCategories are the categories tools can be assigned to
+var ToolCategories = []string{"filesystem", "database", "network", "system", "security", "data", "ai", "utility", "other"}
+
+// MaxToolExecTimeoutLimit is the largest tool execution timeout an organization may set, in seconds
+const MaxToolExecTimeoutLimit = 600
+
+// OrgSettings holds per-organization feature flags stored in Organization.Settings
+type OrgSettings struct {
+	EnableBehavioralAnalysis bool     `json:"enable_behavioral_analysis"`
+	MaxToolExecTimeout       int      `json:"max_tool_exec_timeout"`    // seconds
+	AllowedToolCategories    []string `json:"allowed_tool_categories"`  // empty allows all
+	StrictOutputValidation   bool     `json:"strict_output_validation"` // fail executions whose result does not match the output schema
+}
+
+// DefaultOrgSettings returns the settings used when an organization has not configured any
+func DefaultOrgSettings() OrgSettings {
+	return OrgSettings{
+		EnableBehavioralAnalysis: true,
+		MaxToolExecTimeout:       60,
+	}
+}
+
+// Validate checks that the settings are within supported bounds
+func (s OrgSettings) Validate() error {
+	if s.MaxToolExecTimeout < 1 || s.MaxToolExecTimeout > MaxToolExecTimeoutLimit {
+		return fmt.Errorf("max_tool_exec_timeout must be between 1 and %d seconds", MaxToolExecTimeoutLimit)
+	}
+
+	for _, category := range s.AllowedToolCategories {
+		if !isToolCategory(category) {
+			return fmt.Errorf("unknown tool category: %s", category)
+		}
+	}
+
+	return nil
+}
+
+// AllowsToolCategory reports whether tools in category may be executed
+func (s OrgSettings) AllowsToolCategory(category string) bool {
+	if len(s.AllowedToolCategories) == 0 {
+		return true
+	}
+
+	for _, allowed := range s.AllowedToolCategories {
+		if allowed == category {
+			return true
+		}
+	}
+
+	return false
+}
+
+// OrgSettingsFromJSONB reads typed settings from an organization's settings, filling in defaults
+func OrgSettingsFromJSONB(settings JSONB) OrgSettings {
+	result := DefaultOrgSettings()
+
+	if enabled, ok := settings["enable_behavioral_analysis"].(bool); ok {
+		result.EnableBehavioralAnalysis = enabled
+	}
+	if timeout, ok := settings["max_tool_exec_timeout"].(float64); ok && timeout > 0 {
+		result.MaxToolExecTimeout = int(timeout)
+	}
+	if strict, ok := settings["strict_output_validation"].(bool); ok {
+		result.StrictOutputValidation = strict
+	}
+	if categories, ok := settings["allowed_tool_categories"].([]interface{}); ok {
+		for _, category := range categories {
+			if name, ok := category.(string); ok {
+				result.AllowedToolCategories = append(result.AllowedToolCategories, name)
+			}
+		}
+	}
+
+	return result
+}
+
+// isToolCategory reports whether category is a known tool category
+func isToolCategory(category string) bool {
+	for _, known := range ToolCategories {
+		if known == category {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// OWASPTestResult represents a stored OWASP MCP Top 10 test result
+type OWASPTestResult struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	RunID     uuid.UUID `db:"run_id" json:"run_id"`
+	ServerID  uuid.UUID `db:"server_id" json:"server_id"`
+	TestID    string    `db:"test_id" json:"test_id"`
+	Category  string    `db:"category" json:"category"`
+	Status    string    `db:"status" json:"status"`
+	Score     int       `db:"score" json:"score"`
+	Details   JSONB     `db:"details" json:"details"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// OWASPTestRun represents one run of the OWASP MCP Top 10 tests against a server
+type OWASPTestRun struct {
+	RunID          uuid.UUID      `json:"run_id"`
+	ServerID       uuid.UUID      `json:"server_id"`
+	RunAt          time.Time      `json:"run_at"`
+	Score          int            `json:"score"`           // average of all test scores
+	CategoryScores map[string]int `json:"category_scores"` // average test score per category
+}
+
+// StoreOWASPResults stores the results of an OWASP MCP Top 10 run in a single transaction
+func (r *Repository) StoreOWASPResults(ctx context.Context, results []*OWASPTestResult) error {
+	return r.WithTransaction(ctx, func(tx *Repository) error {
+		query := `
+			INSERT INTO owasp_test_results (id, run_id, server_id, test_id, category, status, score, details, created_at)
+			VALUES (:id, :run_id, :server_id, :test_id, :category, :status, :score, :details, :created_at)
+		`
+
+		for _, result := range results {
+			if result.ID == uuid.Nil {
+				result.ID = uuid.New()
+			}
+			if result.CreatedAt.IsZero() {
+				result.CreatedAt = time.Now()
+			}
+
+			if _, err := tx.db.NamedExecContext(ctx, query, result); err != nil {
+				return fmt.Errorf("failed to store OWASP test result: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetOWASPResultHistory returns a server's last limit OWASP MCP Top 10 runs, oldest first
+func (r *Repository) GetOWASPResultHistory(ctx context.Context, serverID uuid.UUID, limit int) ([]*OWASPTestRun, error) {
+	var runIDs []uuid.UUID
+	query := `
+		SELECT run_id FROM owasp_test_results
+		WHERE server_id = $1
+		GROUP BY run_id
+		ORDER BY MAX(created_at) DESC
+		LIMIT $2
+	`
+	if err := r.db.SelectContext(ctx, &runIDs, query, serverID, limit); err != nil {
+		return nil, fmt.Errorf("failed to list OWASP test runs: %w", err)
+	}
+	if len(runIDs) == 0 {
+		return []*OWASPTestRun{}, nil
+	}
+
+	var results []*OWASPTestResult
+	query = `SELECT * FROM owasp_test_results WHERE run_id = ANY($1) ORDER BY created_at ASC`
+	if err := r.db.SelectContext(ctx, &results, query, pq.Array(runIDs)); err != nil {
+		return nil, fmt.Errorf("failed to get OWASP test results: %w", err)
+	}
+
+	return summarizeOWASPRuns(results), nil
+}
+
+// summarizeOWASPRuns groups results by run and averages their scores, ordering runs by time
+func summarizeOWASPRuns(results []*OWASPTestResult) []*OWASPTestRun {
+	type totals struct {
+		sum   int
+		count int
+	}
+
+	runs := make(map[uuid.UUID]*OWASPTestRun)
+	overall := make(map[uuid.UUID]*totals)
+	categories := make(map[uuid.UUID]map[string]*totals)
+
+	for _, result := range results {
+		run, exists := runs[result.RunID]
+		if !exists {
+			run = &OWASPTestRun{
+				RunID:          result.RunID,
+				ServerID:       result.ServerID,
+				RunAt:          result.CreatedAt,
+				CategoryScores: make(map[string]int),
+			}
+			runs[result.RunID] = run
+			overall[result.RunID] = &totals{}
+			categories[result.RunID] = make(map[string]*totals)
+		}
+		if result.CreatedAt.After(run.RunAt) {
+			run.RunAt = result.CreatedAt
+		}
+
+		overall[result.RunID].sum += result.Score
+		overall[result.RunID].count++
+
+		category, exists := categories[result.RunID][result.Category]
+		if !exists {
+			category = &totals{}
+			categories[result.RunID][result.Category] = category
+		}
+		category.sum += result.Score
+		category.count++
+	}
+
+	history := make([]*OWASPTestRun, 0, len(runs))
+	for runID, run := range runs {
+		run.Score = overall[runID].sum / overall[runID].count
+		for name, category := range categories[runID] {
+			run.CategoryScores[name] = category.sum / category.count
+		}
+		history = append(history, run)
+	}
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].RunAt.Before(history[j].RunAt)
+	})
+
+	return history
+}
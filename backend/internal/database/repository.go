@@ -2,17 +2,28 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 )
 
+// dbExecutor is the subset of sqlx.DB and sqlx.Tx used by the repository
+type dbExecutor interface {
+	sqlx.ExtContext
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error)
+}
+
 // Repository handles database operations
 type Repository struct {
-	db     *sqlx.DB
+	db     dbExecutor
 	logger *zap.Logger
 }
 
@@ -24,6 +35,37 @@ func NewRepository(db *sqlx.DB, logger *zap.Logger) *Repository {
 	}
 }
 
+// WithTransaction runs fn with a repository bound to a single transaction. The
+// transaction is committed if fn returns nil and rolled back otherwise. Calls made
+// on a repository that is already in a transaction join it.
+func (r *Repository) WithTransaction(ctx context.Context, fn func(tx *Repository) error) error {
+	db, ok := r.db.(*sqlx.DB)
+	if !ok {
+		return fn(r)
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	txRepo := *r
+	txRepo.db = tx
+
+	if err := fn(&txRepo); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			r.logger.Error("Failed to roll back transaction", zap.Error(rbErr))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
 // Organization operations
 
 // CreateOrganization creates a new organization
@@ -45,7 +87,7 @@ func (r *Repository) CreateOrganization(ctx context.Context, req *CreateOrganiza
 		RETURNING *
 	`
 
-	rows, err := r.db.NamedQueryContext(ctx, query, org)
+	rows, err := sqlx.NamedQueryContext(ctx, r.db, query, org)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create organization: %w", err)
 	}
@@ -86,6 +128,88 @@ func (r *Repository) GetOrganizationBySlug(ctx context.Context, slug string) (*O
 	return &org, nil
 }
 
+// GetOrgSettings retrieves an organization's typed settings
+func (r *Repository) GetOrgSettings(ctx context.Context, orgID uuid.UUID) (*OrgSettings, error) {
+	org, err := r.GetOrganizationByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := OrgSettingsFromJSONB(org.Settings)
+	return &settings, nil
+}
+
+// UpdateOrgSettings validates and stores an organization's typed settings. Other
+// keys in the settings document are preserved.
+func (r *Repository) UpdateOrgSettings(ctx context.Context, orgID uuid.UUID, settings OrgSettings) error {
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+
+	settingsJSON, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("failed to marshal organization settings: %w", err)
+	}
+
+	query := `
+		UPDATE organizations
+		SET settings = COALESCE(settings, '{}'::jsonb) || $2::jsonb, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, orgID, settingsJSON)
+	if err != nil {
+		return fmt.Errorf("failed to update organization settings: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("failed to update organization settings: %w", sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+// ListOrganizations retrieves all active organizations
+func (r *Repository) ListOrganizations(ctx context.Context) ([]*Organization, error) {
+	var orgs []*Organization
+	query := `SELECT * FROM organizations WHERE deleted_at IS NULL ORDER BY created_at ASC`
+
+	err := r.db.SelectContext(ctx, &orgs, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+
+	return orgs, nil
+}
+
+// CreateOrganizationWithOwner creates an organization and its first user atomically,
+// so a failed user insert does not leave an organization without members
+func (r *Repository) CreateOrganizationWithOwner(ctx context.Context, orgReq *CreateOrganizationRequest, userReq *CreateUserRequest, passwordHash string) (*Organization, *User, error) {
+	var org *Organization
+	var user *User
+
+	err := r.WithTransaction(ctx, func(tx *Repository) error {
+		var err error
+		org, err = tx.CreateOrganization(ctx, orgReq)
+		if err != nil {
+			return err
+		}
+
+		userReq.OrganizationID = org.ID
+		user, err = tx.CreateUser(ctx, userReq, passwordHash)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return org, user, nil
+}
+
 // User operations
 
 // CreateUser creates a new user
@@ -109,7 +233,7 @@ func (r *Repository) CreateUser(ctx context.Context, req *CreateUserRequest, pas
 		RETURNING *
 	`
 
-	rows, err := r.db.NamedQueryContext(ctx, query, user)
+	rows, err := sqlx.NamedQueryContext(ctx, r.db, query, user)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
@@ -162,6 +286,51 @@ func (r *Repository) UpdateUserLastLogin(ctx context.Context, id uuid.UUID) erro
 	return nil
 }
 
+// GetUserPreferences retrieves a user's typed preferences
+func (r *Repository) GetUserPreferences(ctx context.Context, userID uuid.UUID) (*UserPreferences, error) {
+	user, err := r.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences := UserPreferencesFromJSONB(user.Preferences)
+	return &preferences, nil
+}
+
+// UpdateUserPreferences validates and stores a user's typed preferences. Other
+// keys in the preferences document are preserved.
+func (r *Repository) UpdateUserPreferences(ctx context.Context, userID uuid.UUID, preferences UserPreferences) error {
+	if err := preferences.Validate(); err != nil {
+		return err
+	}
+
+	preferencesJSON, err := json.Marshal(preferences)
+	if err != nil {
+		return fmt.Errorf("failed to marshal user preferences: %w", err)
+	}
+
+	query := `
+		UPDATE users
+		SET preferences = COALESCE(preferences, '{}'::jsonb) || $2::jsonb, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, userID, preferencesJSON)
+	if err != nil {
+		return fmt.Errorf("failed to update user preferences: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("failed to update user preferences: %w", sql.ErrNoRows)
+	}
+
+	return nil
+}
+
 // MCP Server operations
 
 // CreateMCPServer creates a new MCP server
@@ -180,14 +349,21 @@ func (r *Repository) CreateMCPServer(ctx context.Context, req *CreateMCPServerRe
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
 	}
+	if req.Metadata != nil {
+		server.Metadata = req.Metadata
+	}
+	server.HealthCheck = HealthCheckConfig{Method: "jsonrpc_ping"}
+	if req.HealthCheck != nil && req.HealthCheck.Method != "" {
+		server.HealthCheck = *req.HealthCheck
+	}
 
 	query := `
-		INSERT INTO mcp_servers (id, organization_id, name, url, description, type, status, capabilities, metadata, created_by, created_at, updated_at)
-		VALUES (:id, :organization_id, :name, :url, :description, :type, :status, :capabilities, :metadata, :created_by, :created_at, :updated_at)
+		INSERT INTO mcp_servers (id, organization_id, name, url, description, type, status, capabilities, metadata, health_check_config, created_by, created_at, updated_at)
+		VALUES (:id, :organization_id, :name, :url, :description, :type, :status, :capabilities, :metadata, :health_check_config, :created_by, :created_at, :updated_at)
 		RETURNING *
 	`
 
-	rows, err := r.db.NamedQueryContext(ctx, query, server)
+	rows, err := sqlx.NamedQueryContext(ctx, r.db, query, server)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create MCP server: %w", err)
 	}
@@ -202,12 +378,12 @@ func (r *Repository) CreateMCPServer(ctx context.Context, req *CreateMCPServerRe
 	return server, nil
 }
 
-// GetMCPServerByID retrieves an MCP server by ID
-func (r *Repository) GetMCPServerByID(ctx context.Context, id uuid.UUID) (*MCPServer, error) {
+// GetMCPServerByID retrieves one of an organization's MCP servers by ID
+func (r *Repository) GetMCPServerByID(ctx context.Context, organizationID, id uuid.UUID) (*MCPServer, error) {
 	var server MCPServer
-	query := `SELECT * FROM mcp_servers WHERE id = $1 AND deleted_at IS NULL`
+	query := `SELECT * FROM mcp_servers WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL`
 	
-	err := r.db.GetContext(ctx, &server, query, id)
+	err := r.db.GetContext(ctx, &server, query, id, organizationID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get MCP server: %w", err)
 	}
@@ -233,29 +409,93 @@ func (r *Repository) ListMCPServers(ctx context.Context, organizationID uuid.UUI
 	return servers, nil
 }
 
-// UpdateMCPServerStatus updates the status of an MCP server
-func (r *Repository) UpdateMCPServerStatus(ctx context.Context, id uuid.UUID, status string, responseTimeMs *int, errorMessage *string) error {
+// ListOnlineMCPServers lists the online MCP servers of every organization, by name
+func (r *Repository) ListOnlineMCPServers(ctx context.Context) ([]*MCPServer, error) {
+	servers := []*MCPServer{}
+	query := `
+		SELECT * FROM mcp_servers
+		WHERE status = 'online' AND deleted_at IS NULL
+		ORDER BY name, id
+	`
+
+	err := r.db.SelectContext(ctx, &servers, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list online MCP servers: %w", err)
+	}
+
+	return servers, nil
+}
+
+// CountMCPServers counts the active MCP servers in an organization
+func (r *Repository) CountMCPServers(ctx context.Context, organizationID uuid.UUID) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM mcp_servers WHERE organization_id = $1 AND deleted_at IS NULL`
+
+	err := r.db.GetContext(ctx, &count, query, organizationID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count MCP servers: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetServerHealthSummary counts an organization's servers by status and averages their health metrics
+func (r *Repository) GetServerHealthSummary(ctx context.Context, organizationID uuid.UUID) (*ServerHealthSummary, error) {
+	var summary ServerHealthSummary
+	query := `
+		SELECT COUNT(*) AS total_servers,
+			COUNT(*) FILTER (WHERE status = 'online') AS online_servers,
+			COUNT(*) FILTER (WHERE status IN ('offline', 'error')) AS offline_servers,
+			COUNT(*) FILTER (WHERE status NOT IN ('online', 'offline', 'error')) AS unknown_servers,
+			AVG(response_time_ms) AS avg_response_time_ms,
+			AVG(uptime_percentage) AS avg_uptime
+		FROM mcp_servers
+		WHERE organization_id = $1 AND deleted_at IS NULL
+	`
+
+	err := r.db.GetContext(ctx, &summary, query, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize server health: %w", err)
+	}
+
+	return &summary, nil
+}
+
+// UpdateMCPServerStatus updates the status of an MCP server. tcpLatencyMs is the raw TCP
+// connection time, recorded separately from the HTTP response time when it was measured.
+// protocolVersion, when set, is the MCP protocol version negotiated with the server and
+// is stored as the server's version. sql.ErrNoRows is returned if the server belongs to
+// another organization.
+func (r *Repository) UpdateMCPServerStatus(ctx context.Context, organizationID, id uuid.UUID, status string, responseTimeMs, tcpLatencyMs *int, errorMessage, protocolVersion *string) error {
 	now := time.Now()
 	
 	// Update server status
 	query := `
 		UPDATE mcp_servers 
-		SET status = $2, last_checked_at = $3, response_time_ms = $4, updated_at = $3
-		WHERE id = $1
+		SET status = $2, last_checked_at = $3, response_time_ms = $4, tcp_latency_ms = $5, updated_at = $3,
+		    version = COALESCE($6, version)
+		WHERE id = $1 AND organization_id = $7
 	`
 	
-	_, err := r.db.ExecContext(ctx, query, id, status, now, responseTimeMs)
+	result, err := r.db.ExecContext(ctx, query, id, status, now, responseTimeMs, tcpLatencyMs, protocolVersion, organizationID)
 	if err != nil {
 		return fmt.Errorf("failed to update MCP server status: %w", err)
 	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("failed to update MCP server status: %w", sql.ErrNoRows)
+	}
 
 	// Add to status history
 	historyQuery := `
-		INSERT INTO server_status_history (id, server_id, status, response_time_ms, error_message, checked_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO server_status_history (id, server_id, status, response_time_ms, tcp_latency_ms, error_message, checked_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
 	
-	_, err = r.db.ExecContext(ctx, historyQuery, uuid.New(), id, status, responseTimeMs, errorMessage, now)
+	_, err = r.db.ExecContext(ctx, historyQuery, uuid.New(), id, status, responseTimeMs, tcpLatencyMs, errorMessage, now)
 	if err != nil {
 		r.logger.Error("Failed to insert status history", zap.Error(err))
 		// Don't fail the main operation for history logging
@@ -264,11 +504,11 @@ func (r *Repository) UpdateMCPServerStatus(ctx context.Context, id uuid.UUID, st
 	return nil
 }
 
-// DeleteMCPServer soft deletes an MCP server
-func (r *Repository) DeleteMCPServer(ctx context.Context, id uuid.UUID) error {
-	query := `UPDATE mcp_servers SET deleted_at = NOW(), updated_at = NOW() WHERE id = $1`
+// DeleteMCPServer soft deletes one of an organization's MCP servers
+func (r *Repository) DeleteMCPServer(ctx context.Context, organizationID, id uuid.UUID) error {
+	query := `UPDATE mcp_servers SET deleted_at = NOW(), updated_at = NOW() WHERE id = $1 AND organization_id = $2`
 	
-	_, err := r.db.ExecContext(ctx, query, id)
+	_, err := r.db.ExecContext(ctx, query, id, organizationID)
 	if err != nil {
 		return fmt.Errorf("failed to delete MCP server: %w", err)
 	}
@@ -276,6 +516,84 @@ func (r *Repository) DeleteMCPServer(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// DeprecateMCPServer marks an MCP server as deprecated, optionally pointing at its replacement
+func (r *Repository) DeprecateMCPServer(ctx context.Context, organizationID, id uuid.UUID, message string, replacementID *uuid.UUID) error {
+	query := `
+		UPDATE mcp_servers
+		SET is_deprecated = true, deprecated_at = NOW(), deprecation_message = $2, replacement_server_id = $3, updated_at = NOW()
+		WHERE id = $1 AND organization_id = $4 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, message, replacementID, organizationID)
+	if err != nil {
+		return fmt.Errorf("failed to deprecate MCP server: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("failed to deprecate MCP server: %w", sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+// AcknowledgeServerIdentityChange clears a server's pending identity change and resolves
+// its open identity change alerts. It returns sql.ErrNoRows if the server has no pending
+// change or belongs to another organization.
+func (r *Repository) AcknowledgeServerIdentityChange(ctx context.Context, organizationID, id uuid.UUID, userID *uuid.UUID) error {
+	query := `
+		UPDATE mcp_servers
+		SET previous_fingerprint = NULL, identity_changed_at = NULL, updated_at = NOW()
+		WHERE id = $1 AND organization_id = $2 AND previous_fingerprint IS NOT NULL AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, organizationID)
+	if err != nil {
+		return fmt.Errorf("failed to acknowledge server identity change: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("failed to acknowledge server identity change: %w", sql.ErrNoRows)
+	}
+
+	query = `
+		UPDATE alerts
+		SET resolved_by = $2, resolved_at = NOW(), updated_at = NOW()
+		WHERE server_id = $1 AND organization_id = $3 AND type = 'server_identity_changed' AND resolved_at IS NULL
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, id, userID, organizationID); err != nil {
+		return fmt.Errorf("failed to resolve identity change alerts: %w", err)
+	}
+
+	return nil
+}
+
+// ListRecentToolUsers returns the users who executed tools on one of an organization's
+// servers since the given time
+func (r *Repository) ListRecentToolUsers(ctx context.Context, organizationID, serverID uuid.UUID, since time.Time) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	query := `
+		SELECT DISTINCT e.user_id FROM tool_executions e
+		JOIN mcp_servers s ON s.id = e.server_id
+		WHERE e.server_id = $1 AND s.organization_id = $3 AND e.user_id IS NOT NULL AND e.executed_at >= $2
+	`
+
+	err := r.db.SelectContext(ctx, &userIDs, query, serverID, since, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recent tool users: %w", err)
+	}
+
+	return userIDs, nil
+}
+
 // Alert operations
 
 // CreateAlert creates a new alert
@@ -285,8 +603,8 @@ func (r *Repository) CreateAlert(ctx context.Context, alert *Alert) error {
 	alert.UpdatedAt = time.Now()
 
 	query := `
-		INSERT INTO alerts (id, organization_id, server_id, type, severity, title, message, is_read, metadata, created_at, updated_at)
-		VALUES (:id, :organization_id, :server_id, :type, :severity, :title, :message, :is_read, :metadata, :created_at, :updated_at)
+		INSERT INTO alerts (id, organization_id, server_id, tool_execution_id, type, severity, title, message, is_read, metadata, created_at, updated_at)
+		VALUES (:id, :organization_id, :server_id, :tool_execution_id, :type, :severity, :title, :message, :is_read, :metadata, :created_at, :updated_at)
 	`
 
 	_, err := r.db.NamedExecContext(ctx, query, alert)
@@ -297,6 +615,39 @@ func (r *Repository) CreateAlert(ctx context.Context, alert *Alert) error {
 	return nil
 }
 
+// CreateSystemAlert creates an alert that belongs to no organization
+func (r *Repository) CreateSystemAlert(ctx context.Context, alert *Alert) error {
+	alert.ID = uuid.New()
+	alert.OrganizationID = uuid.Nil
+	alert.CreatedAt = time.Now()
+	alert.UpdatedAt = time.Now()
+
+	query := `
+		INSERT INTO alerts (id, organization_id, type, severity, title, message, is_read, metadata, created_at, updated_at)
+		VALUES (:id, NULL, :type, :severity, :title, :message, :is_read, :metadata, :created_at, :updated_at)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, alert)
+	if err != nil {
+		return fmt.Errorf("failed to create system alert: %w", err)
+	}
+
+	return nil
+}
+
+// GetAlertByID retrieves one of an organization's alerts by ID
+func (r *Repository) GetAlertByID(ctx context.Context, organizationID, id uuid.UUID) (*Alert, error) {
+	var alert Alert
+	query := `SELECT * FROM alerts WHERE id = $1 AND organization_id = $2`
+
+	err := r.db.GetContext(ctx, &alert, query, id, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert: %w", err)
+	}
+
+	return &alert, nil
+}
+
 // ListAlerts retrieves alerts for an organization
 func (r *Repository) ListAlerts(ctx context.Context, organizationID uuid.UUID, limit, offset int) ([]*Alert, error) {
 	var alerts []*Alert
@@ -315,12 +666,81 @@ func (r *Repository) ListAlerts(ctx context.Context, organizationID uuid.UUID, l
 	return alerts, nil
 }
 
+// GetAggregatedAlerts summarizes an organization's alerts created since the given time
+func (r *Repository) GetAggregatedAlerts(ctx context.Context, orgID uuid.UUID, since time.Time) (*AlertDigest, error) {
+	digest := &AlertDigest{
+		OrganizationID: orgID,
+		Since:          since,
+		BySeverity:     make(map[string]int),
+		ByType:         make(map[string]int),
+	}
+
+	var severityCounts []struct {
+		Key   string `db:"key"`
+		Count int    `db:"count"`
+	}
+	query := `
+		SELECT severity AS key, COUNT(*) AS count FROM alerts
+		WHERE organization_id = $1 AND created_at >= $2
+		GROUP BY severity
+	`
+	if err := r.db.SelectContext(ctx, &severityCounts, query, orgID, since); err != nil {
+		return nil, fmt.Errorf("failed to count alerts by severity: %w", err)
+	}
+	for _, row := range severityCounts {
+		digest.BySeverity[row.Key] = row.Count
+		digest.TotalAlerts += row.Count
+	}
+
+	var typeCounts []struct {
+		Key   string `db:"key"`
+		Count int    `db:"count"`
+	}
+	query = `
+		SELECT type AS key, COUNT(*) AS count FROM alerts
+		WHERE organization_id = $1 AND created_at >= $2
+		GROUP BY type
+	`
+	if err := r.db.SelectContext(ctx, &typeCounts, query, orgID, since); err != nil {
+		return nil, fmt.Errorf("failed to count alerts by type: %w", err)
+	}
+	for _, row := range typeCounts {
+		digest.ByType[row.Key] = row.Count
+	}
+
+	query = `
+		SELECT a.server_id, s.name AS server_name, COUNT(*) AS alert_count
+		FROM alerts a
+		JOIN mcp_servers s ON s.id = a.server_id
+		WHERE a.organization_id = $1 AND a.created_at >= $2
+		GROUP BY a.server_id, s.name
+		ORDER BY alert_count DESC
+		LIMIT 5
+	`
+	if err := r.db.SelectContext(ctx, &digest.TopServers, query, orgID, since); err != nil {
+		return nil, fmt.Errorf("failed to get most impacted servers: %w", err)
+	}
+
+	query = `
+		SELECT * FROM alerts
+		WHERE organization_id = $1 AND severity = 'critical' AND resolved_at IS NULL
+		ORDER BY created_at DESC
+	`
+	if err := r.db.SelectContext(ctx, &digest.UnresolvedCritical, query, orgID); err != nil {
+		return nil, fmt.Errorf("failed to get unresolved critical alerts: %w", err)
+	}
+
+	return digest, nil
+}
+
 // Audit log operations
 
-// CreateAuditLog creates a new audit log entry
+// CreateAuditLog creates a new audit log entry. Passwords and key hashes are removed
+// from its details.
 func (r *Repository) CreateAuditLog(ctx context.Context, log *AuditLog) error {
 	log.ID = uuid.New()
 	log.CreatedAt = time.Now()
+	log.Details = sanitizeAuditDetails(log.Details)
 
 	query := `
 		INSERT INTO audit_logs (id, organization_id, user_id, action, resource_type, resource_id, details, ip_address, user_agent, created_at)
@@ -335,19 +755,39 @@ func (r *Repository) CreateAuditLog(ctx context.Context, log *AuditLog) error {
 	return nil
 }
 
+// CreateSystemAuditLog creates an audit log entry that belongs to no organization
+func (r *Repository) CreateSystemAuditLog(ctx context.Context, log *AuditLog) error {
+	log.ID = uuid.New()
+	log.OrganizationID = uuid.Nil
+	log.CreatedAt = time.Now()
+	log.Details = sanitizeAuditDetails(log.Details)
+
+	query := `
+		INSERT INTO audit_logs (id, organization_id, user_id, action, resource_type, resource_id, details, ip_address, user_agent, created_at)
+		VALUES (:id, NULL, :user_id, :action, :resource_type, :resource_id, :details, :ip_address, :user_agent, :created_at)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, log)
+	if err != nil {
+		return fmt.Errorf("failed to create system audit log: %w", err)
+	}
+
+	return nil
+}
+
 // MCP Server operations
 
-// GetMCPServer retrieves an MCP server by ID
-func (r *Repository) GetMCPServer(ctx context.Context, serverID string) (*MCPServer, error) {
+// GetMCPServer retrieves one of an organization's MCP servers by ID
+func (r *Repository) GetMCPServer(ctx context.Context, organizationID uuid.UUID, serverID string) (*MCPServer, error) {
 	query := `
 		SELECT id, organization_id, name, url, description, type, version, capabilities, 
-		       status, last_checked_at, response_time_ms, created_at, updated_at
+		       status, last_checked_at, last_successful_init_at, response_time_ms, created_at, updated_at
 		FROM mcp_servers 
-		WHERE id = $1 AND deleted_at IS NULL
+		WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL
 	`
 
 	var server MCPServer
-	err := r.db.GetContext(ctx, &server, query, serverID)
+	err := r.db.GetContext(ctx, &server, query, serverID, organizationID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get MCP server: %w", err)
 	}
@@ -358,8 +798,8 @@ func (r *Repository) GetMCPServer(ctx context.Context, serverID string) (*MCPSer
 // ListActiveMCPServers retrieves all active MCP servers
 func (r *Repository) ListActiveMCPServers(ctx context.Context) ([]*MCPServer, error) {
 	query := `
-		SELECT id, organization_id, name, url, description, type, version, capabilities, 
-		       status, last_checked_at, response_time_ms, created_at, updated_at
+		SELECT id, organization_id, name, url, description, type, version, capabilities, metadata,
+		       status, last_checked_at, last_successful_init_at, response_time_ms, created_at, updated_at
 		FROM mcp_servers 
 		WHERE deleted_at IS NULL
 		ORDER BY created_at DESC
@@ -370,23 +810,752 @@ func (r *Repository) ListActiveMCPServers(ctx context.Context) ([]*MCPServer, er
 	if err != nil {
 		return nil, fmt.Errorf("failed to list MCP servers: %w", err)
 	}
+	for _, server := range servers {
+		server.loadCheckInterval()
+	}
 
 	return servers, nil
 }
 
+// SetMCPServerCheckInterval stores how often a server's health is checked. An interval
+// of zero removes it, so the default is used. sql.ErrNoRows is returned if the server
+// does not exist or belongs to another organization.
+func (r *Repository) SetMCPServerCheckInterval(ctx context.Context, organizationID, serverID uuid.UUID, interval time.Duration) error {
+	query := `
+		UPDATE mcp_servers
+		SET metadata = COALESCE(metadata, '{}'::jsonb) || jsonb_build_object($3::text, $4::int),
+		    updated_at = NOW()
+		WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL
+	`
+	args := []interface{}{serverID, organizationID, CheckIntervalMetadataKey, int(interval / time.Second)}
+	if interval <= 0 {
+		query = `
+			UPDATE mcp_servers
+			SET metadata = COALESCE(metadata, '{}'::jsonb) - $3::text, updated_at = NOW()
+			WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL
+		`
+		args = args[:3]
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to set server check interval: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("failed to set server check interval: %w", sql.ErrNoRows)
+	}
+
+	return nil
+}
+
 
-// ResolveAlert resolves an alert
-func (r *Repository) ResolveAlert(ctx context.Context, alertID, userID string) error {
+// ResolveAlert resolves an alert in an organization, returning sql.ErrNoRows if the
+// organization has no such alert
+func (r *Repository) ResolveAlert(ctx context.Context, organizationID uuid.UUID, alertID, userID string) error {
 	query := `
 		UPDATE alerts 
-		SET is_resolved = true, resolved_by = $2, resolved_at = $3, updated_at = $3
-		WHERE id = $1
+		SET resolved_by = $2, resolved_at = $3, updated_at = $3
+		WHERE id = $1 AND organization_id = $4
 	`
 
-	_, err := r.db.ExecContext(ctx, query, alertID, userID, time.Now())
+	result, err := r.db.ExecContext(ctx, query, alertID, userID, time.Now(), organizationID)
+	if err != nil {
+		return fmt.Errorf("failed to resolve alert: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
 	if err != nil {
 		return fmt.Errorf("failed to resolve alert: %w", err)
 	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// Escalation operations
+
+// UpsertEscalationPolicy creates or replaces an organization's escalation policy
+func (r *Repository) UpsertEscalationPolicy(ctx context.Context, policy *EscalationPolicy) error {
+	now := time.Now()
+	policy.CreatedAt = now
+	policy.UpdatedAt = now
+
+	query := `
+		INSERT INTO escalation_policies (organization_id, level1_delay_seconds, level1_contacts, level2_delay_seconds,
+			level2_contacts, level3_contacts, created_at, updated_at)
+		VALUES (:organization_id, :level1_delay_seconds, :level1_contacts, :level2_delay_seconds,
+			:level2_contacts, :level3_contacts, :created_at, :updated_at)
+		ON CONFLICT (organization_id) DO UPDATE
+		SET level1_delay_seconds = EXCLUDED.level1_delay_seconds, level1_contacts = EXCLUDED.level1_contacts,
+			level2_delay_seconds = EXCLUDED.level2_delay_seconds, level2_contacts = EXCLUDED.level2_contacts,
+			level3_contacts = EXCLUDED.level3_contacts, updated_at = EXCLUDED.updated_at
+		RETURNING created_at
+	`
+
+	rows, err := sqlx.NamedQueryContext(ctx, r.db, query, policy)
+	if err != nil {
+		return fmt.Errorf("failed to save escalation policy: %w", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&policy.CreatedAt); err != nil {
+			return fmt.Errorf("failed to scan escalation policy: %w", err)
+		}
+	}
+
+	return rows.Err()
+}
+
+// ListEscalationPolicies lists the escalation policies of every organization
+func (r *Repository) ListEscalationPolicies(ctx context.Context) ([]*EscalationPolicy, error) {
+	var policies []*EscalationPolicy
+	query := `SELECT * FROM escalation_policies`
+
+	err := r.db.SelectContext(ctx, &policies, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list escalation policies: %w", err)
+	}
+
+	return policies, nil
+}
+
+// ListPendingEscalations lists an organization's unresolved critical alerts that have not
+// reached the final escalation level, with their current level
+func (r *Repository) ListPendingEscalations(ctx context.Context, orgID uuid.UUID, maxLevel int) ([]*PendingEscalation, error) {
+	var pending []*PendingEscalation
+	query := `
+		SELECT a.*, COALESCE(e.level, 0) AS escalation_level, e.last_escalated_at
+		FROM alerts a
+		LEFT JOIN alert_escalations e ON e.alert_id = a.id
+		WHERE a.organization_id = $1 AND a.severity = 'critical' AND a.resolved_at IS NULL
+			AND COALESCE(e.level, 0) < $2
+		ORDER BY a.created_at ASC
+	`
+
+	err := r.db.SelectContext(ctx, &pending, query, orgID, maxLevel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending escalations: %w", err)
+	}
+
+	return pending, nil
+}
+
+// RecordAlertEscalation stores the level an alert has been escalated to
+func (r *Repository) RecordAlertEscalation(ctx context.Context, alertID, orgID uuid.UUID, level int, escalatedAt time.Time) error {
+	query := `
+		INSERT INTO alert_escalations (alert_id, organization_id, level, last_escalated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (alert_id) DO UPDATE SET level = EXCLUDED.level, last_escalated_at = EXCLUDED.last_escalated_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query, alertID, orgID, level, escalatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record alert escalation: %w", err)
+	}
+
+	return nil
+}
+
+// Runtime config operations
+
+// RuntimeConfigChannel is the channel notified with the key of a changed runtime config value
+const RuntimeConfigChannel = "config_changed"
+
+// ListRuntimeConfig lists every runtime config value
+func (r *Repository) ListRuntimeConfig(ctx context.Context) ([]*RuntimeConfigEntry, error) {
+	var entries []*RuntimeConfigEntry
+	query := `SELECT * FROM runtime_config ORDER BY key`
+
+	err := r.db.SelectContext(ctx, &entries, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runtime config: %w", err)
+	}
+
+	return entries, nil
+}
+
+// GetRuntimeConfig retrieves a runtime config value by key
+func (r *Repository) GetRuntimeConfig(ctx context.Context, key string) (*RuntimeConfigEntry, error) {
+	var entry RuntimeConfigEntry
+	query := `SELECT * FROM runtime_config WHERE key = $1`
+
+	err := r.db.GetContext(ctx, &entry, query, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get runtime config: %w", err)
+	}
+
+	return &entry, nil
+}
+
+// SetRuntimeConfig stores a runtime config value and notifies every listening instance.
+// The notification is only delivered once the transaction commits.
+func (r *Repository) SetRuntimeConfig(ctx context.Context, entry *RuntimeConfigEntry) error {
+	entry.UpdatedAt = time.Now()
+
+	return r.WithTransaction(ctx, func(tx *Repository) error {
+		query := `
+			INSERT INTO runtime_config (key, value, updated_by, updated_at)
+			VALUES ($1, $2::jsonb, $3, $4)
+			ON CONFLICT (key) DO UPDATE
+			SET value = EXCLUDED.value, updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at
+		`
+		if _, err := tx.db.ExecContext(ctx, query, entry.Key, string(entry.Value), entry.UpdatedBy, entry.UpdatedAt); err != nil {
+			return fmt.Errorf("failed to set runtime config: %w", err)
+		}
+
+		if _, err := tx.db.ExecContext(ctx, `SELECT pg_notify($1, $2)`, RuntimeConfigChannel, entry.Key); err != nil {
+			return fmt.Errorf("failed to notify runtime config change: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// Server group operations
+
+// GetServerGroup retrieves one of an organization's server groups by ID
+func (r *Repository) GetServerGroup(ctx context.Context, organizationID, id uuid.UUID) (*ServerGroup, error) {
+	var group ServerGroup
+	query := `SELECT * FROM server_groups WHERE id = $1 AND organization_id = $2`
+
+	err := r.db.GetContext(ctx, &group, query, id, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server group: %w", err)
+	}
+
+	return &group, nil
+}
+
+// ListServerGroups lists the server groups in an organization
+func (r *Repository) ListServerGroups(ctx context.Context, organizationID uuid.UUID) ([]*ServerGroup, error) {
+	var groups []*ServerGroup
+	query := `SELECT * FROM server_groups WHERE organization_id = $1 ORDER BY name ASC`
+
+	err := r.db.SelectContext(ctx, &groups, query, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list server groups: %w", err)
+	}
+
+	return groups, nil
+}
+
+// ListServerGroupMembers lists the active servers of an organization in a server group
+func (r *Repository) ListServerGroupMembers(ctx context.Context, organizationID, groupID uuid.UUID) ([]*MCPServer, error) {
+	var servers []*MCPServer
+	query := `
+		SELECT s.* FROM mcp_servers s
+		JOIN server_group_members m ON m.server_id = s.id
+		WHERE m.group_id = $1 AND s.organization_id = $2 AND s.deleted_at IS NULL
+		ORDER BY s.name ASC
+	`
+
+	err := r.db.SelectContext(ctx, &servers, query, groupID, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list server group members: %w", err)
+	}
+
+	return servers, nil
+}
+
+// Security test operations
+
+// CreateSecurityTest stores a security test result
+func (r *Repository) CreateSecurityTest(ctx context.Context, test *SecurityTest) error {
+	test.ID = uuid.New()
+	test.CreatedAt = time.Now()
+	test.UpdatedAt = time.Now()
+
+	query := `
+		INSERT INTO security_tests (id, organization_id, server_id, name, type, status, result, score, details, started_at, completed_at, created_by, created_at, updated_at)
+		VALUES (:id, :organization_id, :server_id, :name, :type, :status, :result, :score, :details, :started_at, :completed_at, :created_by, :created_at, :updated_at)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, test)
+	if err != nil {
+		return fmt.Errorf("failed to create security test: %w", err)
+	}
+
+	return nil
+}
+
+// ListLatestSecurityScores returns the latest completed security test score of each server in an organization
+func (r *Repository) ListLatestSecurityScores(ctx context.Context, organizationID uuid.UUID) ([]*SecurityScore, error) {
+	var scores []*SecurityScore
+	query := `
+		SELECT DISTINCT ON (t.server_id) t.server_id, s.name AS server_name, t.score, t.result, t.completed_at
+		FROM security_tests t
+		JOIN mcp_servers s ON s.id = t.server_id
+		WHERE t.organization_id = $1 AND t.status = 'completed' AND s.deleted_at IS NULL
+		ORDER BY t.server_id, t.completed_at DESC
+	`
+
+	err := r.db.SelectContext(ctx, &scores, query, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list security scores: %w", err)
+	}
+
+	return scores, nil
+}
+
+// ListLatestServerSecurityTests returns the latest completed security test of each type run against a server
+func (r *Repository) ListLatestServerSecurityTests(ctx context.Context, organizationID, serverID uuid.UUID) ([]*SecurityTest, error) {
+	var tests []*SecurityTest
+	query := `
+		SELECT DISTINCT ON (type) *
+		FROM security_tests
+		WHERE server_id = $1 AND organization_id = $2 AND status = 'completed'
+		ORDER BY type, completed_at DESC
+	`
+
+	err := r.db.SelectContext(ctx, &tests, query, serverID, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list server security tests: %w", err)
+	}
+
+	return tests, nil
+}
+
+// CountServerAlertsSince counts the alerts of a type raised for a server since a time
+func (r *Repository) CountServerAlertsSince(ctx context.Context, organizationID, serverID uuid.UUID, alertType string, since time.Time) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM alerts WHERE server_id = $1 AND organization_id = $4 AND type = $2 AND created_at >= $3`
+
+	err := r.db.GetContext(ctx, &count, query, serverID, alertType, since, organizationID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count server alerts: %w", err)
+	}
+
+	return count, nil
+}
+
+// ListServerToolDefinitions returns the definitions of the tools discovered on a server
+func (r *Repository) ListServerToolDefinitions(ctx context.Context, organizationID, serverID uuid.UUID) ([]*ToolDefinition, error) {
+	var tools []*ToolDefinition
+	query := `
+		SELECT t.name, t.description, t.input_schema FROM mcp_tools t
+		JOIN mcp_servers s ON s.id = t.server_id
+		WHERE t.server_id = $1 AND s.organization_id = $2 AND t.deleted_at IS NULL
+		ORDER BY t.name ASC
+	`
+
+	err := r.db.SelectContext(ctx, &tools, query, serverID, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list server tool definitions: %w", err)
+	}
+
+	return tools, nil
+}
+
+// CreateScheduledScan creates a new scheduled security scan
+func (r *Repository) CreateScheduledScan(ctx context.Context, scan *ScheduledScan) error {
+	scan.ID = uuid.New()
+	scan.CreatedAt = time.Now()
+	scan.UpdatedAt = time.Now()
+
+	query := `
+		INSERT INTO scheduled_scans (id, organization_id, server_id, test_types, cron_expression, notify_on_failure, next_run_at, created_by, created_at, updated_at)
+		VALUES (:id, :organization_id, :server_id, :test_types, :cron_expression, :notify_on_failure, :next_run_at, :created_by, :created_at, :updated_at)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, scan)
+	if err != nil {
+		return fmt.Errorf("failed to create scheduled scan: %w", err)
+	}
+
+	return nil
+}
+
+// GetScheduledScan retrieves a scheduled scan in an organization by ID
+func (r *Repository) GetScheduledScan(ctx context.Context, organizationID, id uuid.UUID) (*ScheduledScan, error) {
+	var scan ScheduledScan
+	query := `SELECT * FROM scheduled_scans WHERE id = $1 AND organization_id = $2`
+
+	err := r.db.GetContext(ctx, &scan, query, id, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled scan: %w", err)
+	}
+
+	return &scan, nil
+}
+
+// ListScheduledScans lists the scheduled scans in an organization
+func (r *Repository) ListScheduledScans(ctx context.Context, organizationID uuid.UUID) ([]*ScheduledScan, error) {
+	var scans []*ScheduledScan
+	query := `SELECT * FROM scheduled_scans WHERE organization_id = $1 ORDER BY next_run_at ASC`
+
+	err := r.db.SelectContext(ctx, &scans, query, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled scans: %w", err)
+	}
+
+	return scans, nil
+}
+
+// ListDueScheduledScans lists the scheduled scans across all organizations that are due at now
+func (r *Repository) ListDueScheduledScans(ctx context.Context, now time.Time) ([]*ScheduledScan, error) {
+	var scans []*ScheduledScan
+	query := `SELECT * FROM scheduled_scans WHERE next_run_at <= $1 ORDER BY next_run_at ASC`
+
+	err := r.db.SelectContext(ctx, &scans, query, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due scheduled scans: %w", err)
+	}
+
+	return scans, nil
+}
+
+// UpdateScheduledScan updates a scheduled scan's schedule and options
+func (r *Repository) UpdateScheduledScan(ctx context.Context, scan *ScheduledScan) error {
+	scan.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE scheduled_scans
+		SET test_types = :test_types, cron_expression = :cron_expression, notify_on_failure = :notify_on_failure,
+			next_run_at = :next_run_at, updated_at = :updated_at
+		WHERE id = :id AND organization_id = :organization_id
+	`
+
+	result, err := r.db.NamedExecContext(ctx, query, scan)
+	if err != nil {
+		return fmt.Errorf("failed to update scheduled scan: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("failed to update scheduled scan: %w", sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+// MarkScheduledScanRun records that a scheduled scan ran and when it runs next
+func (r *Repository) MarkScheduledScanRun(ctx context.Context, id uuid.UUID, lastRunAt, nextRunAt time.Time) error {
+	query := `UPDATE scheduled_scans SET last_run_at = $2, next_run_at = $3, updated_at = NOW() WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, id, lastRunAt, nextRunAt)
+	if err != nil {
+		return fmt.Errorf("failed to mark scheduled scan run: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteScheduledScan deletes a scheduled scan in an organization
+func (r *Repository) DeleteScheduledScan(ctx context.Context, organizationID, id uuid.UUID) error {
+	query := `DELETE FROM scheduled_scans WHERE id = $1 AND organization_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, id, organizationID)
+	if err != nil {
+		return fmt.Errorf("failed to delete scheduled scan: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("failed to delete scheduled scan: %w", sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+// Tool operations
+
+// ListToolNamesByServer returns the names of the enabled tools discovered on each of the
+// organization's servers
+func (r *Repository) ListToolNamesByServer(ctx context.Context, organizationID uuid.UUID, serverIDs []uuid.UUID) (map[uuid.UUID][]string, error) {
+	ids := make([]string, len(serverIDs))
+	for i, id := range serverIDs {
+		ids[i] = id.String()
+	}
+
+	var rows []struct {
+		ServerID uuid.UUID `db:"server_id"`
+		Name     string    `db:"name"`
+	}
+	query := `
+		SELECT t.server_id, t.name FROM mcp_tools t
+		JOIN mcp_servers s ON s.id = t.server_id
+		WHERE t.server_id = ANY($1::uuid[]) AND s.organization_id = $2 AND t.deleted_at IS NULL AND t.is_enabled = true
+		ORDER BY t.name ASC
+	`
+
+	err := r.db.SelectContext(ctx, &rows, query, pq.Array(ids), organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tools by server: %w", err)
+	}
+
+	tools := make(map[uuid.UUID][]string, len(serverIDs))
+	for _, row := range rows {
+		tools[row.ServerID] = append(tools[row.ServerID], row.Name)
+	}
+
+	return tools, nil
+}
+
+// CountToolExecutionsByOrg counts the tool executions in an organization between start and end
+func (r *Repository) CountToolExecutionsByOrg(ctx context.Context, orgID uuid.UUID, start, end time.Time) (int64, error) {
+	var count int64
+	query := `
+		SELECT COUNT(*) FROM tool_executions te
+		JOIN mcp_tools t ON te.tool_id = t.id
+		JOIN mcp_servers s ON t.server_id = s.id
+		WHERE s.organization_id = $1 AND te.executed_at >= $2 AND te.executed_at < $3
+	`
+
+	err := r.db.GetContext(ctx, &count, query, orgID, start, end)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count tool executions: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetToolExecutionStats summarizes an organization's tool executions since a point in time
+func (r *Repository) GetToolExecutionStats(ctx context.Context, orgID uuid.UUID, since time.Time) (*ToolExecutionStats, error) {
+	stats := &ToolExecutionStats{Since: since}
+
+	var counts struct {
+		Total     int64 `db:"total"`
+		Completed int64 `db:"completed"`
+		Failed    int64 `db:"failed"`
+	}
+	query := `
+		SELECT COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE te.status = 'completed') AS completed,
+			COUNT(*) FILTER (WHERE te.status = 'failed') AS failed
+		FROM tool_executions te
+		JOIN mcp_servers s ON te.server_id = s.id
+		WHERE s.organization_id = $1 AND te.executed_at >= $2
+	`
+	if err := r.db.GetContext(ctx, &counts, query, orgID, since); err != nil {
+		return nil, fmt.Errorf("failed to count tool executions: %w", err)
+	}
+	stats.Total, stats.Completed, stats.Failed = counts.Total, counts.Completed, counts.Failed
+
+	query = `
+		SELECT te.tool_id, t.name AS tool_name, COUNT(*) AS executions
+		FROM tool_executions te
+		JOIN mcp_tools t ON te.tool_id = t.id
+		JOIN mcp_servers s ON te.server_id = s.id
+		WHERE s.organization_id = $1 AND te.executed_at >= $2
+		GROUP BY te.tool_id, t.name
+		ORDER BY executions DESC
+		LIMIT 5
+	`
+	if err := r.db.SelectContext(ctx, &stats.TopTools, query, orgID, since); err != nil {
+		return nil, fmt.Errorf("failed to get most used tools: %w", err)
+	}
+
+	return stats, nil
+}
+
+// ListToolExecutionBuckets counts an organization's executions of each tool in consecutive
+// buckets of the given width ending at end. Bucket 0 is the most recent; empty buckets are omitted.
+func (r *Repository) ListToolExecutionBuckets(ctx context.Context, orgID uuid.UUID, end time.Time, width time.Duration, buckets int) ([]*ToolExecutionBucket, error) {
+	start := end.Add(-width * time.Duration(buckets))
+
+	var rows []*ToolExecutionBucket
+	query := `
+		SELECT te.tool_id, t.name AS tool_name,
+			floor(extract(epoch FROM ($2::timestamptz - te.executed_at)) / $3)::int AS bucket,
+			COUNT(*) AS count
+		FROM tool_executions te
+		JOIN mcp_tools t ON te.tool_id = t.id
+		JOIN mcp_servers s ON t.server_id = s.id
+		WHERE s.organization_id = $1 AND te.executed_at >= $4 AND te.executed_at < $2
+		GROUP BY te.tool_id, t.name, bucket
+	`
+
+	err := r.db.SelectContext(ctx, &rows, query, orgID, end, width.Seconds(), start)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tool execution buckets: %w", err)
+	}
+
+	return rows, nil
+}
+
+// Metrics operations
+
+// metricsResolutions maps supported resolutions to their bucket expression and label format
+var metricsResolutions = map[string]struct {
+	bucket string
+	layout string
+}{
+	"1m": {bucket: "date_trunc('minute', checked_at)", layout: "2006-01-02 15:04"},
+	"5m": {bucket: "date_trunc('hour', checked_at) + floor(extract(minute FROM checked_at) / 5) * interval '5 minutes'", layout: "2006-01-02 15:04"},
+	"1h": {bucket: "date_trunc('hour', checked_at)", layout: "2006-01-02 15:00"},
+	"1d": {bucket: "date_trunc('day', checked_at)", layout: "2006-01-02"},
+}
+
+// GetServerMetricsSeries aggregates the status history of one of an organization's
+// servers into time buckets
+func (r *Repository) GetServerMetricsSeries(ctx context.Context, organizationID, serverID uuid.UUID, resolution string, start, end time.Time) (*MetricsSeries, error) {
+	res, ok := metricsResolutions[resolution]
+	if !ok {
+		return nil, fmt.Errorf("unsupported resolution: %s", resolution)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			%s AS bucket,
+			COALESCE(AVG(response_time_ms), 0) AS avg_response_time,
+			COALESCE(percentile_cont(0.95) WITHIN GROUP (ORDER BY response_time_ms), 0) AS p95_response_time,
+			AVG(CASE WHEN status = 'online' THEN 1.0 ELSE 0.0 END) * 100 AS availability,
+			COUNT(*) AS checks
+		FROM server_status_history
+		WHERE server_id = $1 AND checked_at >= $2 AND checked_at < $3
+		  AND server_id IN (SELECT id FROM mcp_servers WHERE organization_id = $4)
+		GROUP BY bucket
+		ORDER BY bucket ASC
+	`, res.bucket)
+
+	var buckets []*MetricsBucket
+	err := r.db.SelectContext(ctx, &buckets, query, serverID, start, end, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server metrics: %w", err)
+	}
+
+	for _, bucket := range buckets {
+		bucket.Label = bucket.Timestamp.UTC().Format(res.layout)
+	}
+
+	return &MetricsSeries{
+		ServerID:   serverID,
+		Resolution: resolution,
+		Start:      start,
+		End:        end,
+		Buckets:    buckets,
+	}, nil
+}
+
+// API key operations
+
+// CreateAPIKey stores a new API key
+func (r *Repository) CreateAPIKey(ctx context.Context, key *APIKey) error {
+	key.ID = uuid.New()
+	key.IsActive = true
+	key.CreatedAt = time.Now()
+	key.UpdatedAt = key.CreatedAt
+	if key.Permissions == nil {
+		key.Permissions = JSONB{}
+	}
+
+	query := `
+		INSERT INTO api_keys (id, organization_id, user_id, name, key_hash, key_prefix, permissions, expires_at, is_active, created_at, updated_at)
+		VALUES (:id, :organization_id, :user_id, :name, :key_hash, :key_prefix, :permissions, :expires_at, :is_active, :created_at, :updated_at)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, key)
+	if err != nil {
+		return fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	return nil
+}
+
+// GetAPIKey retrieves an organization's API key
+func (r *Repository) GetAPIKey(ctx context.Context, orgID, id uuid.UUID) (*APIKey, error) {
+	var key APIKey
+	query := `SELECT * FROM api_keys WHERE id = $1 AND organization_id = $2`
+
+	err := r.db.GetContext(ctx, &key, query, id, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+
+	return &key, nil
+}
+
+// ListAPIKeys retrieves an organization's API keys, newest first
+func (r *Repository) ListAPIKeys(ctx context.Context, orgID uuid.UUID) ([]*APIKey, error) {
+	keys := []*APIKey{}
+	query := `SELECT * FROM api_keys WHERE organization_id = $1 ORDER BY created_at DESC`
+
+	err := r.db.SelectContext(ctx, &keys, query, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// ListActiveAPIKeysByPrefix retrieves the active API keys whose key starts with prefix
+func (r *Repository) ListActiveAPIKeysByPrefix(ctx context.Context, prefix string) ([]*APIKey, error) {
+	var keys []*APIKey
+	query := `SELECT * FROM api_keys WHERE key_prefix = $1 AND is_active = true`
+
+	err := r.db.SelectContext(ctx, &keys, query, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up API key: %w", err)
+	}
+
+	return keys, nil
+}
+
+// RotateAPIKey replaces an organization's API key secret, keeping its name and permissions
+func (r *Repository) RotateAPIKey(ctx context.Context, orgID, id uuid.UUID, keyHash, keyPrefix string) error {
+	query := `
+		UPDATE api_keys
+		SET key_hash = $3, key_prefix = $4, last_used_at = NULL, updated_at = NOW()
+		WHERE id = $1 AND organization_id = $2 AND is_active = true
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, orgID, keyHash, keyPrefix)
+	if err != nil {
+		return fmt.Errorf("failed to rotate API key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("failed to rotate API key: %w", sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+// DeleteAPIKey deletes an organization's API key
+func (r *Repository) DeleteAPIKey(ctx context.Context, orgID, id uuid.UUID) error {
+	query := `DELETE FROM api_keys WHERE id = $1 AND organization_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, id, orgID)
+	if err != nil {
+		return fmt.Errorf("failed to delete API key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("failed to delete API key: %w", sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+// TouchAPIKey records that an API key was used
+func (r *Repository) TouchAPIKey(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE api_keys SET last_used_at = NOW() WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to update API key usage: %w", err)
+	}
 
 	return nil
 }
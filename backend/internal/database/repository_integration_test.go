@@ -0,0 +1,115 @@
+//go:build integration
+
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database/dbtest"
+)
+
+// TestRepositoryOrganizationIsolation checks that a server and alert of one organization
+// cannot be read or changed through another organization
+func TestRepositoryOrganizationIsolation(t *testing.T) {
+	ctx := context.Background()
+	repo := dbtest.Repository(t)
+
+	owner := dbtest.CreateOrganization(t, repo)
+	other := dbtest.CreateOrganization(t, repo)
+	user := dbtest.CreateUser(t, repo, owner.ID, "admin")
+	server := dbtest.CreateServer(t, repo, owner.ID, user.ID, "isolation")
+
+	alert := &database.Alert{
+		OrganizationID: owner.ID,
+		ServerID:       &server.ID,
+		Type:           "security",
+		Severity:       "high",
+		Title:          "isolation",
+		Message:        "isolation",
+	}
+	if err := repo.CreateAlert(ctx, alert); err != nil {
+		t.Fatalf("CreateAlert() error = %v", err)
+	}
+
+	if _, err := repo.GetMCPServerByID(ctx, owner.ID, server.ID); err != nil {
+		t.Fatalf("GetMCPServerByID() by owner error = %v", err)
+	}
+
+	notFound := []struct {
+		name string
+		call func() error
+	}{
+		{"GetMCPServerByID", func() error {
+			_, err := repo.GetMCPServerByID(ctx, other.ID, server.ID)
+			return err
+		}},
+		{"GetMCPServer", func() error {
+			_, err := repo.GetMCPServer(ctx, other.ID, server.ID.String())
+			return err
+		}},
+		{"UpdateMCPServerStatus", func() error {
+			return repo.UpdateMCPServerStatus(ctx, other.ID, server.ID, "offline", nil, nil, nil, nil)
+		}},
+		{"DeprecateMCPServer", func() error {
+			return repo.DeprecateMCPServer(ctx, other.ID, server.ID, "", nil)
+		}},
+		{"SetMCPServerCheckInterval", func() error {
+			return repo.SetMCPServerCheckInterval(ctx, other.ID, server.ID, time.Minute)
+		}},
+		{"GetAlertByID", func() error {
+			_, err := repo.GetAlertByID(ctx, other.ID, alert.ID)
+			return err
+		}},
+		{"ResolveAlert", func() error {
+			return repo.ResolveAlert(ctx, other.ID, alert.ID.String(), user.ID.String())
+		}},
+	}
+	for _, tt := range notFound {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, sql.ErrNoRows) {
+				t.Errorf("%s() by other organization error = %v, want sql.ErrNoRows", tt.name, err)
+			}
+		})
+	}
+
+	servers, err := repo.ListMCPServers(ctx, other.ID, 100, 0)
+	if err != nil {
+		t.Fatalf("ListMCPServers() error = %v", err)
+	}
+	if len(servers) != 0 {
+		t.Errorf("ListMCPServers() by other organization returned %d servers, want 0", len(servers))
+	}
+
+	alerts, err := repo.ListAlerts(ctx, other.ID, 100, 0)
+	if err != nil {
+		t.Fatalf("ListAlerts() error = %v", err)
+	}
+	if len(alerts) != 0 {
+		t.Errorf("ListAlerts() by other organization returned %d alerts, want 0", len(alerts))
+	}
+
+	count, err := repo.CountServerAlertsSince(ctx, other.ID, server.ID, "security", time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("CountServerAlertsSince() error = %v", err)
+	}
+	if count != 0 {
+		t.Errorf("CountServerAlertsSince() by other organization = %d, want 0", count)
+	}
+
+	// The owner's server is untouched by the other organization's attempts
+	stored, err := repo.GetMCPServerByID(ctx, owner.ID, server.ID)
+	if err != nil {
+		t.Fatalf("GetMCPServerByID() by owner error = %v", err)
+	}
+	if stored.Status != server.Status || stored.IsDeprecated {
+		t.Errorf("server status = %q, deprecated = %v, want %q, not deprecated", stored.Status, stored.IsDeprecated, server.Status)
+	}
+	if err := repo.ResolveAlert(ctx, owner.ID, alert.ID.String(), user.ID.String()); err != nil {
+		t.Errorf("ResolveAlert() by owner error = %v", err)
+	}
+}
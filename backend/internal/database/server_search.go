@@ -0,0 +1,147 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// MCPServerSearch holds the filters for searching MCP servers
+type MCPServerSearch struct {
+	// TextQuery is matched against the full-text index over name, description and type
+	TextQuery string
+	// Query is a case-insensitive substring of the name, description, URL or type
+	Query          string
+	Type           string
+	Status         string
+	OrganizationID *uuid.UUID
+	Capabilities   []string // servers having any of them
+	Tags           []string // servers tagged with any of them
+	SortBy         string   // name, last_seen or created_at; text searches default to relevance
+	SortOrder      string   // asc or desc
+	Limit          int
+	Offset         int
+
+	// MinHealthScore matches servers whose latest recorded health score is at least it;
+	// servers without one count as DefaultHealthScore
+	MinHealthScore     int
+	DefaultHealthScore int
+}
+
+// searchSortColumns maps MCPServerSearch sort keys to columns
+var searchSortColumns = map[string]string{
+	"name":       "name",
+	"last_seen":  "last_checked_at",
+	"created_at": "created_at",
+}
+
+// conditions returns the search's WHERE conditions with their arguments, and the
+// placeholder of the text query, if any, for ranking
+func (search MCPServerSearch) conditions() ([]string, []interface{}, string) {
+	conditions := []string{"deleted_at IS NULL"}
+	args := []interface{}{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	textQuery := ""
+	if search.TextQuery != "" {
+		textQuery = arg(search.TextQuery)
+		conditions = append(conditions, "tsv @@ plainto_tsquery('english', "+textQuery+")")
+	}
+	if search.Query != "" {
+		pattern := arg("%" + escapeLike(search.Query) + "%")
+		conditions = append(conditions, fmt.Sprintf(
+			"(name ILIKE %[1]s OR description ILIKE %[1]s OR url ILIKE %[1]s OR type ILIKE %[1]s)", pattern))
+	}
+	if search.Type != "" {
+		conditions = append(conditions, "type = "+arg(search.Type))
+	}
+	if search.Status != "" {
+		conditions = append(conditions, "status = "+arg(search.Status))
+	}
+	if search.OrganizationID != nil {
+		conditions = append(conditions, "organization_id = "+arg(*search.OrganizationID))
+	}
+	if len(search.Capabilities) > 0 {
+		conditions = append(conditions, "capabilities ?| "+arg(pq.Array(search.Capabilities)))
+	}
+	if len(search.Tags) > 0 {
+		conditions = append(conditions, "metadata->'tags' ?| "+arg(pq.Array(search.Tags)))
+	}
+	if search.MinHealthScore > 0 {
+		conditions = append(conditions, fmt.Sprintf(`COALESCE((
+			SELECT score FROM health_score_history h
+			WHERE h.server_id = mcp_servers.id
+			ORDER BY h.recorded_at DESC LIMIT 1
+		), %s) >= %s`, arg(search.DefaultHealthScore), arg(search.MinHealthScore)))
+	}
+
+	return conditions, args, textQuery
+}
+
+// SearchMCPServers finds MCP servers matching the search in a single query. Text queries
+// use the GIN-indexed tsv column and are ranked by relevance unless a sort is given.
+func (r *Repository) SearchMCPServers(ctx context.Context, search MCPServerSearch) ([]*MCPServer, error) {
+	conditions, args, textQuery := search.conditions()
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	order := "created_at DESC"
+	if column, ok := searchSortColumns[search.SortBy]; ok {
+		direction := "ASC"
+		if search.SortOrder == "desc" {
+			direction = "DESC"
+		}
+		order = column + " " + direction + " NULLS LAST"
+	} else if textQuery != "" {
+		order = "ts_rank(tsv, plainto_tsquery('english', " + textQuery + ")) DESC"
+	}
+
+	limit := search.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	offset := search.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	query := fmt.Sprintf(`
+		SELECT * FROM mcp_servers
+		WHERE %s
+		ORDER BY %s, id
+		LIMIT %s OFFSET %s
+	`, strings.Join(conditions, " AND "), order, arg(limit), arg(offset))
+
+	var servers []*MCPServer
+	if err := r.db.SelectContext(ctx, &servers, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to search MCP servers: %w", err)
+	}
+
+	return servers, nil
+}
+
+// CountMCPServerSearch counts the MCP servers matching the search, ignoring its sort and page
+func (r *Repository) CountMCPServerSearch(ctx context.Context, search MCPServerSearch) (int, error) {
+	conditions, args, _ := search.conditions()
+
+	var count int
+	query := "SELECT COUNT(*) FROM mcp_servers WHERE " + strings.Join(conditions, " AND ")
+	if err := r.db.GetContext(ctx, &count, query, args...); err != nil {
+		return 0, fmt.Errorf("failed to count MCP servers: %w", err)
+	}
+
+	return count, nil
+}
+
+// escapeLike escapes the LIKE wildcards in a literal search term
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(term)
+}
@@ -0,0 +1,66 @@
+//go:build integration
+
+package database_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database/dbtest"
+	"go.uber.org/zap"
+)
+
+// BenchmarkSearchMCPServers searches an organization with 10,000 servers. A tenth of the
+// servers are file servers, so text and substring queries match 1,000 rows.
+func BenchmarkSearchMCPServers(b *testing.B) {
+	ctx := context.Background()
+	conn := dbtest.Connect(b)
+	repo := database.NewRepository(conn.DB, zap.NewNop())
+
+	org := dbtest.CreateOrganization(b, repo)
+	user := dbtest.CreateUser(b, repo, org.ID, "admin")
+	if _, err := conn.DB.ExecContext(ctx, `
+		INSERT INTO mcp_servers (organization_id, name, url, description, type, status, created_by)
+		SELECT $1,
+		       CASE WHEN i % 10 = 0 THEN 'filesystem-' ELSE 'service-' END || i,
+		       'http://server-' || i || '.invalid',
+		       CASE WHEN i % 10 = 0 THEN 'Reads and writes local files' ELSE 'Answers questions about tickets' END,
+		       CASE WHEN i % 3 = 0 THEN 'filesystem' ELSE 'custom' END,
+		       CASE WHEN i % 2 = 0 THEN 'online' ELSE 'offline' END,
+		       $2
+		FROM generate_series(1, 10000) AS i
+	`, org.ID, user.ID); err != nil {
+		b.Fatalf("failed to insert servers: %v", err)
+	}
+	if _, err := conn.DB.ExecContext(ctx, "ANALYZE mcp_servers"); err != nil {
+		b.Fatalf("failed to analyze mcp_servers: %v", err)
+	}
+
+	searches := []struct {
+		name   string
+		search database.MCPServerSearch
+	}{
+		{"text query", database.MCPServerSearch{TextQuery: "local files"}},
+		{"substring query", database.MCPServerSearch{Query: "filesystem-"}},
+		{"filters sorted by name", database.MCPServerSearch{Type: "filesystem", Status: "online", SortBy: "name"}},
+		{"deep page", database.MCPServerSearch{Offset: 9000}},
+	}
+
+	for _, s := range searches {
+		search := s.search
+		search.OrganizationID = &org.ID
+		search.Limit = 50
+
+		b.Run(s.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := repo.SearchMCPServers(ctx, search); err != nil {
+					b.Fatalf("SearchMCPServers() error = %v", err)
+				}
+				if _, err := repo.CountMCPServerSearch(ctx, search); err != nil {
+					b.Fatalf("CountMCPServerSearch() error = %v", err)
+				}
+			}
+		})
+	}
+}
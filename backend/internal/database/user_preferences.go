@@ -0,0 +1,90 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationChannels are the channels a user can receive notifications on
+var NotificationChannels = []string{"email", "slack", "webhook", "in_app"}
+
+// UserPreferences holds per-user preferences stored in User.Preferences
+type UserPreferences struct {
+	DefaultToolTimeout   int         `json:"default_tool_timeout"` // seconds, 0 uses the organization default
+	FavoriteToolIDs      []uuid.UUID `json:"favorite_tool_ids"`
+	NotificationChannels []string    `json:"notification_channels"`
+	Timezone             string      `json:"timezone"`
+}
+
+// DefaultUserPreferences returns the preferences used when a user has not set any
+func DefaultUserPreferences() UserPreferences {
+	return UserPreferences{
+		FavoriteToolIDs:      []uuid.UUID{},
+		NotificationChannels: []string{"email"},
+		Timezone:             "UTC",
+	}
+}
+
+// Validate checks that the preferences are within supported bounds
+func (p UserPreferences) Validate() error {
+	if p.DefaultToolTimeout < 0 || p.DefaultToolTimeout > MaxToolExecTimeoutLimit {
+		return fmt.Errorf("default_tool_timeout must be between 0 and %d seconds", MaxToolExecTimeoutLimit)
+	}
+
+	for _, channel := range p.NotificationChannels {
+		if !isNotificationChannel(channel) {
+			return fmt.Errorf("unknown notification channel: %s", channel)
+		}
+	}
+
+	if p.Timezone != "" {
+		if _, err := time.LoadLocation(p.Timezone); err != nil {
+			return fmt.Errorf("unknown timezone: %s", p.Timezone)
+		}
+	}
+
+	return nil
+}
+
+// UserPreferencesFromJSONB reads typed preferences from a user's preferences, filling in defaults
+func UserPreferencesFromJSONB(preferences JSONB) UserPreferences {
+	result := DefaultUserPreferences()
+
+	if timeout, ok := preferences["default_tool_timeout"].(float64); ok && timeout > 0 {
+		result.DefaultToolTimeout = int(timeout)
+	}
+	if toolIDs, ok := preferences["favorite_tool_ids"].([]interface{}); ok {
+		for _, toolID := range toolIDs {
+			if s, ok := toolID.(string); ok {
+				if id, err := uuid.Parse(s); err == nil {
+					result.FavoriteToolIDs = append(result.FavoriteToolIDs, id)
+				}
+			}
+		}
+	}
+	if channels, ok := preferences["notification_channels"].([]interface{}); ok {
+		result.NotificationChannels = []string{}
+		for _, channel := range channels {
+			if name, ok := channel.(string); ok {
+				result.NotificationChannels = append(result.NotificationChannels, name)
+			}
+		}
+	}
+	if timezone, ok := preferences["timezone"].(string); ok && timezone != "" {
+		result.Timezone = timezone
+	}
+
+	return result
+}
+
+// isNotificationChannel reports whether channel is a known notification channel
+func isNotificationChannel(channel string) bool {
+	for _, known := range NotificationChannels {
+		if known == channel {
+			return true
+		}
+	}
+	return false
+}
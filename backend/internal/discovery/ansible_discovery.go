@@ -0,0 +1,349 @@
+package discovery
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AnsibleMCPGroup is the inventory group whose hosts, including those of its child
+// groups, are imported as MCP servers
+const AnsibleMCPGroup = "mcp_servers"
+
+// DefaultAnsibleInventories are the inventory files checked during discovery
+var DefaultAnsibleInventories = []string{"/etc/ansible/hosts"}
+
+// Host variables read from the inventory. mcp_port and mcp_path may be a single value or
+// a list; every port and path combination is probed.
+const (
+	ansibleHostVar   = "ansible_host"
+	ansiblePortVar   = "mcp_port"
+	ansiblePathVar   = "mcp_path"
+	ansibleSchemeVar = "mcp_scheme"
+)
+
+// defaultAnsibleMCPPort is probed for hosts without an mcp_port variable
+const defaultAnsibleMCPPort = "3000"
+
+// ansibleGroup is a group parsed from an inventory
+type ansibleGroup struct {
+	hosts    map[string]map[string]interface{}
+	vars     map[string]interface{}
+	children []string
+}
+
+// ansibleInventory maps group names to groups
+type ansibleInventory map[string]*ansibleGroup
+
+// group returns the named group, creating it if needed
+func (inv ansibleInventory) group(name string) *ansibleGroup {
+	g, exists := inv[name]
+	if !exists {
+		g = &ansibleGroup{
+			hosts: make(map[string]map[string]interface{}),
+			vars:  make(map[string]interface{}),
+		}
+		inv[name] = g
+	}
+	return g
+}
+
+// AnsibleInventoryDiscovery imports MCP servers from Ansible inventories
+type AnsibleInventoryDiscovery struct {
+	service *MCPDiscoveryService
+}
+
+// NewAnsibleInventoryDiscovery creates an Ansible inventory importer that probes hosts
+// with the given discovery service
+func NewAnsibleInventoryDiscovery(service *MCPDiscoveryService) *AnsibleInventoryDiscovery {
+	return &AnsibleInventoryDiscovery{service: service}
+}
+
+// Discover parses an INI or YAML Ansible inventory, builds a URL for every port and path
+// of each host in the mcp_servers group and returns the URLs that respond as MCP servers
+func (a *AnsibleInventoryDiscovery) Discover(ctx context.Context, inventoryPath string) ([]*DiscoveredServer, error) {
+	data, err := os.ReadFile(inventoryPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read inventory: %w", err)
+	}
+
+	inventory, err := parseAnsibleInventory(data)
+	if err != nil {
+		return nil, err
+	}
+
+	servers := a.service.probeURLs(ctx, inventory.mcpURLs(), "ansible:"+inventoryPath)
+	a.service.cacheServers(ctx, servers)
+
+	return servers, nil
+}
+
+// parseAnsibleInventory parses an inventory, detecting whether it is INI or YAML
+func parseAnsibleInventory(data []byte) (ansibleInventory, error) {
+	if isINIInventory(data) {
+		return parseINIInventory(data)
+	}
+	return parseYAMLInventory(data)
+}
+
+// isINIInventory reports whether an inventory is in INI format: its first entry is a
+// [group] header or a host line rather than a YAML mapping
+func isINIInventory(data []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") || line == "---" {
+			continue
+		}
+		return strings.HasPrefix(line, "[") || !strings.HasSuffix(strings.Fields(line)[0], ":")
+	}
+	return false
+}
+
+// parseINIInventory parses an INI inventory with [group], [group:vars] and
+// [group:children] sections. Hosts before the first section are ungrouped.
+func parseINIInventory(data []byte) (ansibleInventory, error) {
+	inventory := make(ansibleInventory)
+	section, kind := "ungrouped", "hosts"
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("invalid inventory section on line %d", lineNumber)
+			}
+			section, kind = strings.TrimSuffix(strings.TrimPrefix(line, "["), "]"), "hosts"
+			if name, suffix, ok := strings.Cut(section, ":"); ok {
+				section, kind = name, suffix
+			}
+			inventory.group(section)
+			continue
+		}
+
+		group := inventory.group(section)
+		switch kind {
+		case "hosts":
+			fields := strings.Fields(line)
+			vars := make(map[string]interface{})
+			for _, field := range fields[1:] {
+				if key, value, ok := strings.Cut(field, "="); ok {
+					vars[key] = strings.Trim(value, `"'`)
+				}
+			}
+			group.hosts[fields[0]] = vars
+		case "vars":
+			key, value, ok := strings.Cut(line, "=")
+			if !ok {
+				return nil, fmt.Errorf("invalid group variable on line %d", lineNumber)
+			}
+			group.vars[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"'`)
+		case "children":
+			group.children = append(group.children, line)
+			inventory.group(line)
+		default:
+			return nil, fmt.Errorf("unknown inventory section type %q on line %d", kind, lineNumber)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read inventory: %w", err)
+	}
+
+	return inventory, nil
+}
+
+// parseYAMLInventory parses a YAML inventory, where each group maps to its hosts, vars
+// and children, and child groups are defined inline
+func parseYAMLInventory(data []byte) (ansibleInventory, error) {
+	var root map[string]interface{}
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML inventory: %w", err)
+	}
+
+	inventory := make(ansibleInventory)
+	for name, definition := range root {
+		inventory.addYAMLGroup(name, definition)
+	}
+
+	return inventory, nil
+}
+
+// addYAMLGroup adds a group definition and, recursively, its children
+func (inv ansibleInventory) addYAMLGroup(name string, definition interface{}) {
+	group := inv.group(name)
+
+	fields, ok := definition.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if hosts, ok := fields["hosts"].(map[string]interface{}); ok {
+		for host, hostVars := range hosts {
+			vars := make(map[string]interface{})
+			if values, ok := hostVars.(map[string]interface{}); ok {
+				for key, value := range values {
+					vars[key] = value
+				}
+			}
+			group.hosts[host] = vars
+		}
+	}
+
+	if vars, ok := fields["vars"].(map[string]interface{}); ok {
+		for key, value := range vars {
+			group.vars[key] = value
+		}
+	}
+
+	if children, ok := fields["children"].(map[string]interface{}); ok {
+		for child, childDefinition := range children {
+			group.children = append(group.children, child)
+			inv.addYAMLGroup(child, childDefinition)
+		}
+	}
+}
+
+// mcpURLs returns the URLs to probe for every host in the MCP group and its children.
+// Host variables override the variables of the groups the host was found through.
+func (inv ansibleInventory) mcpURLs() []string {
+	seen := make(map[string]bool)
+	visited := make(map[string]bool)
+	var urls []string
+
+	var walk func(name string, inherited map[string]interface{})
+	walk = func(name string, inherited map[string]interface{}) {
+		group, exists := inv[name]
+		if !exists || visited[name] {
+			return
+		}
+		visited[name] = true
+
+		groupVars := mergeAnsibleVars(inherited, group.vars)
+		for host, hostVars := range group.hosts {
+			for _, serverURL := range ansibleHostURLs(host, mergeAnsibleVars(groupVars, hostVars)) {
+				if !seen[serverURL] {
+					seen[serverURL] = true
+					urls = append(urls, serverURL)
+				}
+			}
+		}
+
+		for _, child := range group.children {
+			walk(child, groupVars)
+		}
+	}
+	walk(AnsibleMCPGroup, nil)
+
+	return urls
+}
+
+// ansibleHostURLs builds a URL for every combination of a host's ports and paths
+func ansibleHostURLs(host string, vars map[string]interface{}) []string {
+	address := host
+	if value := ansibleVarValues(vars[ansibleHostVar]); len(value) > 0 {
+		address = value[0]
+	}
+
+	scheme := "http"
+	if value := ansibleVarValues(vars[ansibleSchemeVar]); len(value) > 0 {
+		scheme = value[0]
+	}
+
+	ports := ansibleVarValues(vars[ansiblePortVar])
+	if len(ports) == 0 {
+		ports = []string{defaultAnsibleMCPPort}
+	}
+	paths := ansibleVarValues(vars[ansiblePathVar])
+	if len(paths) == 0 {
+		paths = []string{""}
+	}
+
+	var urls []string
+	for _, port := range ports {
+		if _, err := strconv.Atoi(port); err != nil {
+			continue
+		}
+		for _, path := range paths {
+			if path != "" && !strings.HasPrefix(path, "/") {
+				path = "/" + path
+			}
+			urls = append(urls, fmt.Sprintf("%s://%s%s", scheme, net.JoinHostPort(address, port), path))
+		}
+	}
+
+	return urls
+}
+
+// ansibleVarValues returns a variable as a list of strings. INI values may hold several
+// comma-separated values; YAML values may be scalars or lists.
+func ansibleVarValues(value interface{}) []string {
+	var values []string
+	switch v := value.(type) {
+	case nil:
+	case string:
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				values = append(values, part)
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			values = append(values, ansibleVarValues(item)...)
+		}
+	default:
+		values = append(values, fmt.Sprint(v))
+	}
+	return values
+}
+
+// mergeAnsibleVars returns base overridden by overrides
+func mergeAnsibleVars(base, overrides map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(overrides))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range overrides {
+		merged[key] = value
+	}
+	return merged
+}
+
+// ansibleInventoryPlugin discovers servers from Ansible inventory files
+type ansibleInventoryPlugin struct {
+	discovery *AnsibleInventoryDiscovery
+	paths     []string
+}
+
+// Name returns the plugin name
+func (p *ansibleInventoryPlugin) Name() string {
+	return "ansible_inventory"
+}
+
+// Discover probes the MCP hosts of the configured inventories, skipping files that don't exist
+func (p *ansibleInventoryPlugin) Discover(ctx context.Context) ([]*DiscoveredServer, error) {
+	var servers []*DiscoveredServer
+	for _, path := range p.paths {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+
+		found, err := p.discovery.Discover(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		servers = append(servers, found...)
+	}
+
+	return servers, nil
+}
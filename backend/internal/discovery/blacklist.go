@@ -0,0 +1,79 @@
+package discovery
+
+import (
+	"fmt"
+	"net"
+)
+
+// DefaultBlacklistedCIDRs are Kubernetes service and pod ranges that discovery never scans
+var DefaultBlacklistedCIDRs = []string{"10.96.0.0/12", "172.17.0.0/12"}
+
+// DefaultBlacklistedPorts are infrastructure ports (SSH, HTTPS, etcd, Kubernetes API, kubelet)
+// that discovery never probes
+var DefaultBlacklistedPorts = []int{22, 443, 2379, 2380, 6443, 10250}
+
+// Blacklist decides which addresses discovery must not probe
+type Blacklist struct {
+	networks []*net.IPNet
+	ports    map[int]bool
+}
+
+// NewBlacklist creates a blacklist from CIDR ranges and ports
+func NewBlacklist(cidrs []string, ports []int) (*Blacklist, error) {
+	b := &Blacklist{
+		ports: make(map[int]bool, len(ports)),
+	}
+
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blacklisted CIDR %q: %w", cidr, err)
+		}
+		b.networks = append(b.networks, ipNet)
+	}
+
+	for _, port := range ports {
+		b.ports[port] = true
+	}
+
+	return b, nil
+}
+
+// NewDefaultBlacklist creates a blacklist with the default CIDRs and ports
+func NewDefaultBlacklist() *Blacklist {
+	b, _ := NewBlacklist(DefaultBlacklistedCIDRs, DefaultBlacklistedPorts)
+	return b
+}
+
+// NewBlacklistWithDefaults creates a blacklist from the default CIDRs and ports plus the
+// given ones. The defaults cannot be removed, so callers can only block more addresses.
+func NewBlacklistWithDefaults(cidrs []string, ports []int) (*Blacklist, error) {
+	allCIDRs := append(append([]string(nil), DefaultBlacklistedCIDRs...), cidrs...)
+	allPorts := append(append([]int(nil), DefaultBlacklistedPorts...), ports...)
+	return NewBlacklist(allCIDRs, allPorts)
+}
+
+// IsBlocked reports whether host:port must not be probed. Hostnames that are not
+// IP literals are only checked against the port list.
+func (b *Blacklist) IsBlocked(host string, port int) bool {
+	if b == nil {
+		return false
+	}
+
+	if b.ports[port] {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range b.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
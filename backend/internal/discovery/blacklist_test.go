@@ -0,0 +1,70 @@
+package discovery
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBlacklistKeepsDefaults(t *testing.T) {
+	tests := []struct {
+		name    string
+		request string
+	}{
+		{"no blacklist fields", `{}`},
+		{"null lists", `{"blacklisted_cidrs": null, "blacklisted_ports": null}`},
+		{"empty lists", `{"blacklisted_cidrs": [], "blacklisted_ports": []}`},
+		{"extra entries", `{"blacklisted_cidrs": ["192.168.50.0/24"], "blacklisted_ports": [8443]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config DiscoveryConfig
+			if err := json.Unmarshal([]byte(tt.request), &config); err != nil {
+				t.Fatalf("invalid request: %v", err)
+			}
+
+			blacklist, err := NewBlacklistWithDefaults(config.BlacklistedCIDRs, config.BlacklistedPorts)
+			if err != nil {
+				t.Fatalf("NewBlacklistWithDefaults() error = %v", err)
+			}
+
+			for _, addr := range []struct {
+				host string
+				port int
+			}{
+				{"10.96.0.1", 8080},
+				{"172.17.0.5", 3000},
+				{"192.168.1.10", 22},
+				{"192.168.1.10", 443},
+				{"192.168.1.10", 6443},
+				{"192.168.1.10", 10250},
+			} {
+				if !blacklist.IsBlocked(addr.host, addr.port) {
+					t.Errorf("IsBlocked(%s, %d) = false, want the default blacklist to apply", addr.host, addr.port)
+				}
+			}
+			if blacklist.IsBlocked("192.168.1.10", 3000) {
+				t.Error("IsBlocked(192.168.1.10, 3000) = true, want false")
+			}
+		})
+	}
+}
+
+func TestBlacklistAddsRequestedEntries(t *testing.T) {
+	blacklist, err := NewBlacklistWithDefaults([]string{"192.168.50.0/24"}, []int{8443})
+	if err != nil {
+		t.Fatalf("NewBlacklistWithDefaults() error = %v", err)
+	}
+	if !blacklist.IsBlocked("192.168.50.7", 3000) || !blacklist.IsBlocked("192.168.1.10", 8443) {
+		t.Error("requested CIDR or port is not blocked")
+	}
+
+	if _, err := NewBlacklistWithDefaults([]string{"not-a-cidr"}, nil); err == nil {
+		t.Error("NewBlacklistWithDefaults() with an invalid CIDR = nil error, want an error")
+	}
+
+	// Merging does not change the package defaults
+	if len(DefaultBlacklistedCIDRs) != 2 || len(DefaultBlacklistedPorts) != 6 {
+		t.Errorf("defaults changed to %v and %v", DefaultBlacklistedCIDRs, DefaultBlacklistedPorts)
+	}
+}
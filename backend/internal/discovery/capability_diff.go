@@ -0,0 +1,206 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/mcp"
+	"go.uber.org/zap"
+)
+
+// ErrPersistenceDisabled is returned when stored tools are needed but SetPersistence was
+// not called
+var ErrPersistenceDisabled = errors.New("discovery persistence is not configured")
+
+// ErrServerUnavailable is returned when a server's live tools cannot be listed
+var ErrServerUnavailable = errors.New("server capabilities unavailable")
+
+// CapabilityChangeHandler is called when a server's live tools differ from its stored ones
+type CapabilityChangeHandler func(ctx context.Context, serverID uuid.UUID, diff *CapabilityDiff)
+
+// CapabilityDiff lists how a server's live tools differ from the tools stored for it
+type CapabilityDiff struct {
+	ServerID uuid.UUID     `json:"server_id"`
+	Added    []mcp.MCPTool `json:"added"`
+	Removed  []mcp.MCPTool `json:"removed"`
+	Modified []MCPToolDiff `json:"modified"`
+}
+
+// MCPToolDiff is a tool whose input schema changed
+type MCPToolDiff struct {
+	Name                string                 `json:"name"`
+	PreviousInputSchema map[string]interface{} `json:"previous_input_schema"`
+	InputSchema         map[string]interface{} `json:"input_schema"`
+}
+
+// Empty reports whether the live tools match the stored ones
+func (d *CapabilityDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+// OnCapabilityChange registers a handler called when DiffCapabilities finds a change
+func (d *MCPDiscoveryService) OnCapabilityChange(handler CapabilityChangeHandler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.capabilityHandlers = append(d.capabilityHandlers, handler)
+}
+
+// DiffCapabilities compares the tools a registered server offers now with the tools last
+// stored for it in mcp_tools. The stored tools are not updated. If the context carries an
+// organization, the server must belong to it; sql.ErrNoRows is returned if it does not
+// exist.
+func (d *MCPDiscoveryService) DiffCapabilities(ctx context.Context, serverID uuid.UUID) (*CapabilityDiff, error) {
+	if d.db == nil {
+		return nil, ErrPersistenceDisabled
+	}
+
+	var orgFilter *uuid.UUID
+	if orgID, ok := organizationFromContext(ctx); ok {
+		orgFilter = &orgID
+	}
+
+	var serverURL string
+	err := d.db.QueryRowContext(ctx, `
+		SELECT url FROM mcp_servers
+		WHERE id = $1 AND deleted_at IS NULL AND ($2::uuid IS NULL OR organization_id = $2)
+	`, serverID, orgFilter).Scan(&serverURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server: %w", err)
+	}
+
+	stored, err := d.storedTools(ctx, serverID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := d.protocol.Initialize(ctx, serverURL); err != nil {
+		return nil, fmt.Errorf("%w: failed to initialize server: %v", ErrServerUnavailable, err)
+	}
+	live, err := d.protocol.ListTools(ctx, serverURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to list tools: %v", ErrServerUnavailable, err)
+	}
+
+	diff := diffTools(stored, live)
+	diff.ServerID = serverID
+
+	if !diff.Empty() {
+		d.logger.Warn("MCP server capabilities changed",
+			zap.String("server_id", serverID.String()),
+			zap.Int("added", len(diff.Added)),
+			zap.Int("removed", len(diff.Removed)),
+			zap.Int("modified", len(diff.Modified)),
+		)
+
+		d.mu.RLock()
+		handlers := append([]CapabilityChangeHandler(nil), d.capabilityHandlers...)
+		d.mu.RUnlock()
+		for _, handler := range handlers {
+			handler(ctx, serverID, diff)
+		}
+	}
+
+	return diff, nil
+}
+
+// storedTools returns the tools stored for a server
+func (d *MCPDiscoveryService) storedTools(ctx context.Context, serverID uuid.UUID) ([]mcp.MCPTool, error) {
+	rows, err := d.db.QueryContext(ctx, `
+		SELECT name, COALESCE(description, ''), input_schema, output_schema
+		FROM mcp_tools
+		WHERE server_id = $1 AND deleted_at IS NULL
+	`, serverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stored tools: %w", err)
+	}
+	defer rows.Close()
+
+	var tools []mcp.MCPTool
+	for rows.Next() {
+		var tool mcp.MCPTool
+		var inputSchemaJSON, outputSchemaJSON []byte
+		if err := rows.Scan(&tool.Name, &tool.Description, &inputSchemaJSON, &outputSchemaJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan stored tool: %w", err)
+		}
+		json.Unmarshal(inputSchemaJSON, &tool.InputSchema)
+		if len(outputSchemaJSON) > 0 {
+			json.Unmarshal(outputSchemaJSON, &tool.OutputSchema)
+		}
+		tools = append(tools, tool)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get stored tools: %w", err)
+	}
+
+	return tools, nil
+}
+
+// diffTools compares stored and live tools by name. Each list in the result is sorted
+// by name.
+func diffTools(stored, live []mcp.MCPTool) *CapabilityDiff {
+	diff := &CapabilityDiff{
+		Added:    []mcp.MCPTool{},
+		Removed:  []mcp.MCPTool{},
+		Modified: []MCPToolDiff{},
+	}
+
+	storedByName := make(map[string]mcp.MCPTool, len(stored))
+	for _, tool := range stored {
+		storedByName[tool.Name] = tool
+	}
+	liveNames := make(map[string]bool, len(live))
+
+	for _, tool := range live {
+		liveNames[tool.Name] = true
+		previous, ok := storedByName[tool.Name]
+		if !ok {
+			diff.Added = append(diff.Added, tool)
+			continue
+		}
+		if !sameSchema(previous.InputSchema, tool.InputSchema) {
+			diff.Modified = append(diff.Modified, MCPToolDiff{
+				Name:                tool.Name,
+				PreviousInputSchema: previous.InputSchema,
+				InputSchema:         tool.InputSchema,
+			})
+		}
+	}
+
+	for _, tool := range stored {
+		if !liveNames[tool.Name] {
+			diff.Removed = append(diff.Removed, tool)
+		}
+	}
+
+	sort.Slice(diff.Added, func(i, j int) bool { return diff.Added[i].Name < diff.Added[j].Name })
+	sort.Slice(diff.Removed, func(i, j int) bool { return diff.Removed[i].Name < diff.Removed[j].Name })
+	sort.Slice(diff.Modified, func(i, j int) bool { return diff.Modified[i].Name < diff.Modified[j].Name })
+
+	return diff
+}
+
+// sameSchema compares two JSON schemas, treating a missing schema as an empty one.
+// Both are compared in their JSON-decoded form so number types match.
+func sameSchema(a, b map[string]interface{}) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return len(a) == len(b)
+	}
+
+	normalize := func(schema map[string]interface{}) interface{} {
+		data, err := json.Marshal(schema)
+		if err != nil {
+			return schema
+		}
+		var decoded interface{}
+		json.Unmarshal(data, &decoded)
+		return decoded
+	}
+
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
@@ -14,14 +14,16 @@ import (
 )
 
 type DiscoveryService struct {
-	logger *zap.Logger
-	repo   *repository.MCPServerRepository
+	logger    *zap.Logger
+	repo      *repository.MCPServerRepository
+	Blacklist *Blacklist
 }
 
 func NewDiscoveryService(logger *zap.Logger, repo *repository.MCPServerRepository) *DiscoveryService {
 	return &DiscoveryService{
-		logger: logger,
-		repo:   repo,
+		logger:    logger,
+		repo:      repo,
+		Blacklist: NewDefaultBlacklist(),
 	}
 }
 
@@ -105,6 +107,15 @@ func (d *DiscoveryService) scanIP(ctx context.Context, ip string, ports []int, o
 	for _, port := range ports {
 		address := fmt.Sprintf("%s:%d", ip, port)
 
+		host := ip
+		if host == "localhost" {
+			host = "127.0.0.1"
+		}
+		if d.Blacklist.IsBlocked(host, port) {
+			d.logger.Debug("Skipping blacklisted address", zap.String("address", address))
+			continue
+		}
+
 		// Check if port is open and responds to MCP protocol
 		if d.isMCPServer(ctx, address) {
 			server := &models.MCPServer{
@@ -0,0 +1,194 @@
+package discovery
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultEnvironmentFiles are the files checked for MCP server URLs during discovery
+var DefaultEnvironmentFiles = []string{".env.mcp", "mcprc"}
+
+// environmentProbeTimeout bounds the probe of each URL found in the environment
+const environmentProbeTimeout = 10 * time.Second
+
+// DiscoveryPlugin is an additional source of MCP servers run during discovery
+type DiscoveryPlugin interface {
+	Name() string
+	Discover(ctx context.Context) ([]*DiscoveredServer, error)
+}
+
+// RegisterPlugin adds a discovery plugin that runs on every DiscoverServers call
+func (d *MCPDiscoveryService) RegisterPlugin(plugin DiscoveryPlugin) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.plugins = append(d.plugins, plugin)
+}
+
+// runPlugins runs all registered plugins, logging and skipping failures
+func (d *MCPDiscoveryService) runPlugins(ctx context.Context) []*DiscoveredServer {
+	d.mu.RLock()
+	plugins := make([]DiscoveryPlugin, len(d.plugins))
+	copy(plugins, d.plugins)
+	d.mu.RUnlock()
+
+	var servers []*DiscoveredServer
+	for _, plugin := range plugins {
+		found, err := plugin.Discover(ctx)
+		if err != nil {
+			d.logger.Warn("Discovery plugin failed",
+				zap.String("plugin", plugin.Name()),
+				zap.Error(err),
+			)
+			continue
+		}
+		servers = append(servers, found...)
+	}
+
+	return servers
+}
+
+// DiscoverFromEnvironmentFile reads a KEY=VALUE file such as .env.mcp or mcprc and
+// probes every URL whose key ends in _MCP_URL or _MCP_SERVER
+func (d *MCPDiscoveryService) DiscoverFromEnvironmentFile(filePath string) ([]*DiscoveredServer, error) {
+	return d.discoverFromEnvironmentFile(context.Background(), filePath)
+}
+
+// DiscoverFromEnvironmentVariables probes every URL in environment variables whose
+// name ends in _MCP_URL or _MCP_SERVER
+func (d *MCPDiscoveryService) DiscoverFromEnvironmentVariables() []*DiscoveredServer {
+	return d.discoverFromEnvironmentVariables(context.Background())
+}
+
+// discoverFromEnvironmentFile parses filePath and probes the MCP URLs it contains
+func (d *MCPDiscoveryService) discoverFromEnvironmentFile(ctx context.Context, filePath string) ([]*DiscoveredServer, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open environment file: %w", err)
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read environment file: %w", err)
+	}
+
+	return d.probeURLs(ctx, mcpURLsFromEnv(lines), "env_file:"+filePath), nil
+}
+
+// discoverFromEnvironmentVariables probes the MCP URLs found in the process environment
+func (d *MCPDiscoveryService) discoverFromEnvironmentVariables(ctx context.Context) []*DiscoveredServer {
+	return d.probeURLs(ctx, mcpURLsFromEnv(os.Environ()), "env")
+}
+
+// probeURLs probes each URL and returns the ones that respond as MCP servers
+func (d *MCPDiscoveryService) probeURLs(ctx context.Context, urls []string, source string) []*DiscoveredServer {
+	var servers []*DiscoveredServer
+	for _, serverURL := range urls {
+		server, err := d.probeServer(ctx, serverURL, environmentProbeTimeout, source)
+		if err != nil {
+			d.logger.Debug("Configured MCP server did not respond",
+				zap.String("url", serverURL),
+				zap.String("source", source),
+				zap.Error(err),
+			)
+			continue
+		}
+		servers = append(servers, server)
+	}
+
+	return servers
+}
+
+// mcpURLsFromEnv extracts URLs from KEY=VALUE lines whose key ends in _MCP_URL or
+// _MCP_SERVER. Values may hold several comma-separated URLs.
+func mcpURLsFromEnv(lines []string) []string {
+	seen := make(map[string]bool)
+	var urls []string
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.ToUpper(strings.TrimSpace(key))
+		if !strings.HasSuffix(key, "_MCP_URL") && !strings.HasSuffix(key, "_MCP_SERVER") {
+			continue
+		}
+
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		for _, serverURL := range strings.Split(value, ",") {
+			serverURL = strings.TrimSpace(serverURL)
+			if serverURL == "" || seen[serverURL] {
+				continue
+			}
+			if !strings.HasPrefix(serverURL, "http://") && !strings.HasPrefix(serverURL, "https://") {
+				continue
+			}
+			seen[serverURL] = true
+			urls = append(urls, serverURL)
+		}
+	}
+
+	return urls
+}
+
+// environmentVariablesPlugin discovers servers from the process environment
+type environmentVariablesPlugin struct {
+	service *MCPDiscoveryService
+}
+
+// Name returns the plugin name
+func (p *environmentVariablesPlugin) Name() string {
+	return "environment_variables"
+}
+
+// Discover probes MCP URLs found in environment variables
+func (p *environmentVariablesPlugin) Discover(ctx context.Context) ([]*DiscoveredServer, error) {
+	return p.service.discoverFromEnvironmentVariables(ctx), nil
+}
+
+// environmentFilePlugin discovers servers from .env.mcp and mcprc files
+type environmentFilePlugin struct {
+	service *MCPDiscoveryService
+	paths   []string
+}
+
+// Name returns the plugin name
+func (p *environmentFilePlugin) Name() string {
+	return "environment_files"
+}
+
+// Discover probes MCP URLs found in the configured files, skipping files that don't exist
+func (p *environmentFilePlugin) Discover(ctx context.Context) ([]*DiscoveredServer, error) {
+	var servers []*DiscoveredServer
+	for _, path := range p.paths {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+
+		found, err := p.service.discoverFromEnvironmentFile(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		servers = append(servers, found...)
+	}
+
+	return servers, nil
+}
@@ -0,0 +1,91 @@
+package discovery
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/radhi1991/aran-mcp-sentinel/internal/mcp"
+	"go.uber.org/zap"
+)
+
+// IdentityChangeHandler is called when a re-probed server's fingerprint differs from the
+// one seen on its previous probe. The server's PreviousFingerprint holds the old value.
+type IdentityChangeHandler func(ctx context.Context, server *DiscoveredServer)
+
+// ServerFingerprint identifies a server by its name, version, tool names and capability
+// keys. It is the hex-encoded SHA-256 of those values, with tools and capabilities sorted
+// so the order a server lists them in does not matter.
+func ServerFingerprint(name, version string, tools []mcp.MCPTool, capabilities mcp.MCPCapabilities) string {
+	toolNames := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		toolNames = append(toolNames, tool.Name)
+	}
+	sort.Strings(toolNames)
+
+	hash := sha256.New()
+	for _, part := range []string{name, version, strings.Join(toolNames, ","), strings.Join(capabilityKeys(capabilities), ",")} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// capabilityKeys returns the sorted names of the capabilities a server declares
+func capabilityKeys(capabilities mcp.MCPCapabilities) []string {
+	data, err := json.Marshal(capabilities)
+	if err != nil {
+		return nil
+	}
+
+	var declared map[string]json.RawMessage
+	if err := json.Unmarshal(data, &declared); err != nil {
+		return nil
+	}
+
+	keys := make([]string, 0, len(declared))
+	for key, value := range declared {
+		if string(value) != "null" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// OnIdentityChange registers a handler called when a server's fingerprint changes
+func (d *MCPDiscoveryService) OnIdentityChange(handler IdentityChangeHandler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.identityHandlers = append(d.identityHandlers, handler)
+}
+
+// notifyIdentityChanges logs each changed server and passes it to the registered handlers
+func (d *MCPDiscoveryService) notifyIdentityChanges(ctx context.Context, changed []*DiscoveredServer) {
+	if len(changed) == 0 {
+		return
+	}
+
+	d.mu.RLock()
+	handlers := append([]IdentityChangeHandler(nil), d.identityHandlers...)
+	d.mu.RUnlock()
+
+	for _, server := range changed {
+		d.logger.Warn("MCP server identity changed",
+			zap.String("url", server.URL),
+			zap.String("name", server.Name),
+			zap.String("previous_fingerprint", server.PreviousFingerprint),
+			zap.String("fingerprint", server.Fingerprint),
+		)
+
+		for _, handler := range handlers {
+			handler(ctx, server)
+		}
+	}
+}
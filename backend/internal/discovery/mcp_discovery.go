@@ -2,11 +2,11 @@ package discovery
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
-	"net/url"
-	"strings"
 	"sync"
 	"time"
 
@@ -20,6 +20,14 @@ type MCPDiscoveryService struct {
 	protocol *mcp.MCPProtocol
 	mu       sync.RWMutex
 	servers  map[string]*DiscoveredServer
+	plugins  []DiscoveryPlugin
+
+	// db and tools persist identified servers; see SetPersistence
+	db    *sql.DB
+	tools *mcp.ToolManager
+
+	identityHandlers   []IdentityChangeHandler
+	capabilityHandlers []CapabilityChangeHandler
 }
 
 // DiscoveredServer represents a discovered MCP server
@@ -36,6 +44,18 @@ type DiscoveredServer struct {
 	LastSeen     time.Time              `json:"last_seen"`
 	ResponseTime time.Duration          `json:"response_time"`
 	Metadata     map[string]interface{} `json:"metadata"`
+
+	// DiscoveryMethod is how the server was found: local_scan, network_scan, env,
+	// env_file, ansible, mdns or refresh
+	DiscoveryMethod string `json:"discovery_method"`
+
+	// ProtocolVersion is the MCP protocol version negotiated with the server
+	ProtocolVersion string `json:"protocol_version"`
+
+	// Fingerprint identifies the server's name, version, tools and capabilities.
+	// PreviousFingerprint is set when it differs from the previous probe's.
+	Fingerprint         string `json:"fingerprint"`
+	PreviousFingerprint string `json:"previous_fingerprint,omitempty"`
 }
 
 // DiscoveryConfig holds configuration for MCP discovery
@@ -45,6 +65,11 @@ type DiscoveryConfig struct {
 	KnownPorts    []int       `json:"known_ports"`
 	Timeout       time.Duration `json:"timeout"`
 	MaxConcurrent int         `json:"max_concurrent"`
+
+	// BlacklistedCIDRs and BlacklistedPorts are never probed, in addition to
+	// DefaultBlacklistedCIDRs and DefaultBlacklistedPorts
+	BlacklistedCIDRs []string `json:"blacklisted_cidrs"`
+	BlacklistedPorts []int    `json:"blacklisted_ports"`
 }
 
 // PortRange represents a range of ports to scan
@@ -55,11 +80,17 @@ type PortRange struct {
 
 // NewMCPDiscoveryService creates a new MCP discovery service
 func NewMCPDiscoveryService(logger *zap.Logger) *MCPDiscoveryService {
-	return &MCPDiscoveryService{
+	d := &MCPDiscoveryService{
 		logger:   logger,
 		protocol: mcp.NewMCPProtocol(logger),
 		servers:  make(map[string]*DiscoveredServer),
 	}
+
+	d.RegisterPlugin(&environmentVariablesPlugin{service: d})
+	d.RegisterPlugin(&environmentFilePlugin{service: d, paths: DefaultEnvironmentFiles})
+	d.RegisterPlugin(&ansibleInventoryPlugin{discovery: NewAnsibleInventoryDiscovery(d), paths: DefaultAnsibleInventories})
+
+	return d
 }
 
 // DiscoverServers performs comprehensive MCP server discovery
@@ -70,10 +101,15 @@ func (d *MCPDiscoveryService) DiscoverServers(ctx context.Context, config Discov
 		zap.Duration("timeout", config.Timeout),
 	)
 
+	blacklist, err := NewBlacklistWithDefaults(config.BlacklistedCIDRs, config.BlacklistedPorts)
+	if err != nil {
+		return nil, err
+	}
+
 	var allServers []*DiscoveredServer
 	
 	// Discover on localhost first (most common)
-	localServers, err := d.discoverLocalServers(ctx, config)
+	localServers, err := d.discoverLocalServers(ctx, config, blacklist)
 	if err != nil {
 		d.logger.Warn("Failed to discover local servers", zap.Error(err))
 	} else {
@@ -82,7 +118,7 @@ func (d *MCPDiscoveryService) DiscoverServers(ctx context.Context, config Discov
 
 	// Discover on network ranges
 	for _, networkRange := range config.NetworkRanges {
-		networkServers, err := d.discoverNetworkServers(ctx, networkRange, config)
+		networkServers, err := d.discoverNetworkServers(ctx, networkRange, config, blacklist)
 		if err != nil {
 			d.logger.Warn("Failed to discover network servers", 
 				zap.String("network", networkRange),
@@ -93,12 +129,11 @@ func (d *MCPDiscoveryService) DiscoverServers(ctx context.Context, config Discov
 		allServers = append(allServers, networkServers...)
 	}
 
+	// Discover from registered plugins
+	allServers = append(allServers, d.runPlugins(ctx)...)
+
 	// Update internal cache
-	d.mu.Lock()
-	for _, server := range allServers {
-		d.servers[server.URL] = server
-	}
-	d.mu.Unlock()
+	d.cacheServers(ctx, allServers)
 
 	d.logger.Info("MCP discovery completed",
 		zap.Int("servers_found", len(allServers)),
@@ -107,8 +142,26 @@ func (d *MCPDiscoveryService) DiscoverServers(ctx context.Context, config Discov
 	return allServers, nil
 }
 
+// cacheServers records discovered servers so GetDiscoveredServers returns them, and
+// reports servers whose fingerprint changed since they were last cached
+func (d *MCPDiscoveryService) cacheServers(ctx context.Context, servers []*DiscoveredServer) {
+	var changed []*DiscoveredServer
+
+	d.mu.Lock()
+	for _, server := range servers {
+		if previous, exists := d.servers[server.URL]; exists && previous.Fingerprint != server.Fingerprint {
+			server.PreviousFingerprint = previous.Fingerprint
+			changed = append(changed, server)
+		}
+		d.servers[server.URL] = server
+	}
+	d.mu.Unlock()
+
+	d.notifyIdentityChanges(ctx, changed)
+}
+
 // discoverLocalServers discovers MCP servers on localhost
-func (d *MCPDiscoveryService) discoverLocalServers(ctx context.Context, config DiscoveryConfig) ([]*DiscoveredServer, error) {
+func (d *MCPDiscoveryService) discoverLocalServers(ctx context.Context, config DiscoveryConfig, blacklist *Blacklist) ([]*DiscoveredServer, error) {
 	var servers []*DiscoveredServer
 	
 	// Common MCP server ports
@@ -130,13 +183,18 @@ func (d *MCPDiscoveryService) discoverLocalServers(ctx context.Context, config D
 	var mu sync.Mutex
 
 	for _, port := range commonPorts {
+		if blacklist.IsBlocked("127.0.0.1", port) {
+			d.logger.Debug("Skipping blacklisted address", zap.String("host", "localhost"), zap.Int("port", port))
+			continue
+		}
+
 		wg.Add(1)
 		go func(p int) {
 			defer wg.Done()
 			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
 
-			server, err := d.probeServer(ctx, fmt.Sprintf("http://localhost:%d", p), config.Timeout)
+			server, err := d.probeServer(ctx, fmt.Sprintf("http://localhost:%d", p), config.Timeout, "local_scan")
 			if err != nil {
 				return // Server not found or not MCP
 			}
@@ -152,7 +210,7 @@ func (d *MCPDiscoveryService) discoverLocalServers(ctx context.Context, config D
 }
 
 // discoverNetworkServers discovers MCP servers on a network range
-func (d *MCPDiscoveryService) discoverNetworkServers(ctx context.Context, networkRange string, config DiscoveryConfig) ([]*DiscoveredServer, error) {
+func (d *MCPDiscoveryService) discoverNetworkServers(ctx context.Context, networkRange string, config DiscoveryConfig, blacklist *Blacklist) ([]*DiscoveredServer, error) {
 	var servers []*DiscoveredServer
 
 	// Parse network range (e.g., "192.168.1.0/24")
@@ -171,6 +229,11 @@ func (d *MCPDiscoveryService) discoverNetworkServers(ctx context.Context, networ
 
 	for _, ip := range ips {
 		for _, port := range config.KnownPorts {
+			if blacklist.IsBlocked(ip.String(), port) {
+				d.logger.Debug("Skipping blacklisted address", zap.String("host", ip.String()), zap.Int("port", port))
+				continue
+			}
+
 			wg.Add(1)
 			go func(ipAddr string, p int) {
 				defer wg.Done()
@@ -178,7 +241,7 @@ func (d *MCPDiscoveryService) discoverNetworkServers(ctx context.Context, networ
 				defer func() { <-semaphore }()
 
 				serverURL := fmt.Sprintf("http://%s:%d", ipAddr, p)
-				server, err := d.probeServer(ctx, serverURL, config.Timeout)
+				server, err := d.probeServer(ctx, serverURL, config.Timeout, "network_scan")
 				if err != nil {
 					return
 				}
@@ -194,8 +257,9 @@ func (d *MCPDiscoveryService) discoverNetworkServers(ctx context.Context, networ
 	return servers, nil
 }
 
-// probeServer attempts to connect to and identify an MCP server
-func (d *MCPDiscoveryService) probeServer(ctx context.Context, serverURL string, timeout time.Duration) (*DiscoveredServer, error) {
+// probeServer attempts to connect to and identify an MCP server. Identified servers are
+// persisted when SetPersistence was called and ctx carries an organization.
+func (d *MCPDiscoveryService) probeServer(ctx context.Context, serverURL string, timeout time.Duration, source string) (*DiscoveredServer, error) {
 	probeCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
@@ -224,7 +288,10 @@ func (d *MCPDiscoveryService) probeServer(ctx context.Context, serverURL string,
 		Status:       "online",
 		LastSeen:     time.Now(),
 		ResponseTime: responseTime,
-		Metadata:     make(map[string]interface{}),
+		Metadata:     map[string]interface{}{"discovery_source": source},
+
+		DiscoveryMethod: discoveryMethod(source),
+		ProtocolVersion: serverInfo.ProtocolVersion,
 	}
 
 	// Get tools if supported
@@ -257,6 +324,8 @@ func (d *MCPDiscoveryService) probeServer(ctx context.Context, serverURL string,
 		}
 	}
 
+	server.Fingerprint = ServerFingerprint(server.Name, server.Version, server.Tools, server.Capabilities)
+
 	d.logger.Info("Discovered MCP server",
 		zap.String("url", serverURL),
 		zap.String("name", server.Name),
@@ -266,6 +335,12 @@ func (d *MCPDiscoveryService) probeServer(ctx context.Context, serverURL string,
 		zap.Duration("response_time", responseTime),
 	)
 
+	if d.db != nil {
+		if err := d.PersistDiscoveredServer(ctx, server); err != nil && !errors.Is(err, ErrNoOrganization) {
+			d.logger.Error("Failed to persist discovered server", zap.String("url", serverURL), zap.Error(err))
+		}
+	}
+
 	return server, nil
 }
 
@@ -302,37 +377,16 @@ func (d *MCPDiscoveryService) GetDiscoveredServers() []*DiscoveredServer {
 
 // RefreshServer updates information for a specific server
 func (d *MCPDiscoveryService) RefreshServer(ctx context.Context, serverURL string) (*DiscoveredServer, error) {
-	server, err := d.probeServer(ctx, serverURL, 10*time.Second)
+	server, err := d.probeServer(ctx, serverURL, 10*time.Second, "refresh")
 	if err != nil {
 		return nil, err
 	}
 
-	d.mu.Lock()
-	d.servers[serverURL] = server
-	d.mu.Unlock()
+	d.cacheServers(ctx, []*DiscoveredServer{server})
 
 	return server, nil
 }
 
-// DiscoverFromEnvironment discovers MCP servers from environment variables
-func (d *MCPDiscoveryService) DiscoverFromEnvironment() []*DiscoveredServer {
-	// Common environment variables that might contain MCP server URLs
-	envVars := []string{
-		"MCP_SERVER_URL",
-		"MCP_SERVERS",
-		"MODEL_CONTEXT_PROTOCOL_URL",
-	}
-
-	var servers []*DiscoveredServer
-	
-	for _, envVar := range envVars {
-		// This would be implemented to read from environment
-		// For now, return empty slice
-	}
-
-	return servers
-}
-
 // generateIPsInRange generates all IP addresses in a CIDR range
 func generateIPsInRange(ipNet *net.IPNet) []net.IP {
 	var ips []net.IP
@@ -0,0 +1,153 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grandcat/zeroconf"
+	"go.uber.org/zap"
+)
+
+// DNS-SD service types browsed for MCP servers and advertised by the sentinel
+const (
+	MCPServiceType      = "_mcp._tcp"
+	SentinelServiceType = "_mcp-sentinel._tcp"
+	MDNSDomain          = "local."
+)
+
+// DefaultMDNSBrowseTimeout is how long mDNS answers are collected when the context has
+// no deadline of its own
+const DefaultMDNSBrowseTimeout = 5 * time.Second
+
+// mdnsPlugin runs DNS-SD discovery as part of every DiscoverServers call
+type mdnsPlugin struct {
+	service *MCPDiscoveryService
+}
+
+func (p *mdnsPlugin) Name() string { return "mdns" }
+
+func (p *mdnsPlugin) Discover(ctx context.Context) ([]*DiscoveredServer, error) {
+	return p.service.discoverFromDNSSD(ctx)
+}
+
+// EnableMDNS adds DNS-SD browsing for _mcp._tcp.local. services to every
+// DiscoverServers call
+func (d *MCPDiscoveryService) EnableMDNS() {
+	d.RegisterPlugin(&mdnsPlugin{service: d})
+}
+
+// DiscoverFromDNSSD browses the local network for _mcp._tcp.local. services, probes
+// each one and caches those that respond as MCP servers
+func (d *MCPDiscoveryService) DiscoverFromDNSSD(ctx context.Context) ([]*DiscoveredServer, error) {
+	servers, err := d.discoverFromDNSSD(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	d.cacheServers(ctx, servers)
+	return servers, nil
+}
+
+// discoverFromDNSSD browses for MCP services and probes them without caching the result
+func (d *MCPDiscoveryService) discoverFromDNSSD(ctx context.Context) ([]*DiscoveredServer, error) {
+	entries, err := browseDNSSD(ctx, MCPServiceType)
+	if err != nil {
+		return nil, err
+	}
+
+	var servers []*DiscoveredServer
+	for _, entry := range entries {
+		serverURL, ok := serviceEntryURL(entry)
+		if !ok {
+			continue
+		}
+		server, err := d.probeServer(ctx, serverURL, environmentProbeTimeout, "mdns:"+entry.Instance)
+		if err != nil {
+			d.logger.Debug("Advertised MCP server did not respond",
+				zap.String("instance", entry.Instance),
+				zap.String("url", serverURL),
+				zap.Error(err),
+			)
+			continue
+		}
+		servers = append(servers, server)
+	}
+
+	return servers, nil
+}
+
+// browseDNSSD collects the entries answering for a service type until ctx is done, or
+// for DefaultMDNSBrowseTimeout if it has no deadline
+func browseDNSSD(ctx context.Context, service string) ([]*zeroconf.ServiceEntry, error) {
+	resolver, err := zeroconf.NewResolver(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mDNS resolver: %w", err)
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultMDNSBrowseTimeout)
+		defer cancel()
+	}
+
+	found := make(chan *zeroconf.ServiceEntry)
+	if err := resolver.Browse(ctx, service, MDNSDomain, found); err != nil {
+		return nil, fmt.Errorf("failed to browse for %s: %w", service, err)
+	}
+
+	// The resolver closes the channel once ctx is done
+	var entries []*zeroconf.ServiceEntry
+	for entry := range found {
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// serviceEntryURL builds the MCP endpoint of an mDNS entry. The TXT record may give the
+// scheme and path as scheme=https and path=/mcp.
+func serviceEntryURL(entry *zeroconf.ServiceEntry) (string, bool) {
+	var host string
+	switch {
+	case len(entry.AddrIPv4) > 0:
+		host = entry.AddrIPv4[0].String()
+	case len(entry.AddrIPv6) > 0:
+		host = entry.AddrIPv6[0].String()
+	case entry.HostName != "":
+		host = strings.TrimSuffix(entry.HostName, ".")
+	default:
+		return "", false
+	}
+	if entry.Port <= 0 {
+		return "", false
+	}
+
+	u := url.URL{Scheme: "http", Host: net.JoinHostPort(host, strconv.Itoa(entry.Port))}
+	for _, txt := range entry.Text {
+		key, value, _ := strings.Cut(txt, "=")
+		switch strings.ToLower(key) {
+		case "scheme":
+			if value == "http" || value == "https" {
+				u.Scheme = value
+			}
+		case "path":
+			u.Path = "/" + strings.TrimPrefix(value, "/")
+		}
+	}
+
+	return u.String(), true
+}
+
+// AdvertiseSentinel registers the sentinel as an _mcp-sentinel._tcp.local. service on
+// port so other tools can find it. Call Shutdown on the returned server to withdraw it.
+func AdvertiseSentinel(instance string, port int, text []string) (*zeroconf.Server, error) {
+	server, err := zeroconf.Register(instance, SentinelServiceType, MDNSDomain, port, text, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register mDNS service: %w", err)
+	}
+	return server, nil
+}
@@ -0,0 +1,107 @@
+package discovery
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/mcp"
+	"go.uber.org/zap"
+)
+
+// ErrNoOrganization is returned when a discovered server is persisted without an
+// organization on the context
+var ErrNoOrganization = errors.New("no organization for discovered server")
+
+// organizationKey is the context key holding the organization that owns a discovery run
+type organizationKey struct{}
+
+// WithOrganization returns a context whose discovered servers are persisted for orgID
+func WithOrganization(ctx context.Context, orgID uuid.UUID) context.Context {
+	return context.WithValue(ctx, organizationKey{}, orgID)
+}
+
+// organizationFromContext returns the organization set by WithOrganization
+func organizationFromContext(ctx context.Context) (uuid.UUID, bool) {
+	orgID, ok := ctx.Value(organizationKey{}).(uuid.UUID)
+	return orgID, ok && orgID != uuid.Nil
+}
+
+// SetPersistence makes the service write every server it identifies, and the server's
+// tools, to the database when the probe's context carries an organization
+func (d *MCPDiscoveryService) SetPersistence(db *sql.DB, tools *mcp.ToolManager) {
+	d.db = db
+	d.tools = tools
+}
+
+// discoveryMethod returns the method part of a discovery source such as "env_file:/path"
+func discoveryMethod(source string) string {
+	method, _, _ := strings.Cut(source, ":")
+	return method
+}
+
+// PersistDiscoveredServer upserts a discovered server into mcp_servers, matching a
+// server of the same organization by URL so re-scans update it rather than adding a
+// duplicate, and stores its tools in mcp_tools. Servers of other organizations with the
+// same URL are left alone.
+func (d *MCPDiscoveryService) PersistDiscoveredServer(ctx context.Context, ds *DiscoveredServer) error {
+	orgID, ok := organizationFromContext(ctx)
+	if !ok {
+		return ErrNoOrganization
+	}
+
+	capabilities, err := json.Marshal(capabilityKeys(ds.Capabilities))
+	if err != nil {
+		return fmt.Errorf("failed to encode capabilities: %w", err)
+	}
+	metadata, err := json.Marshal(ds.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to encode metadata: %w", err)
+	}
+
+	query := `
+		INSERT INTO mcp_servers (organization_id, name, url, description, status, version,
+		                         capabilities, metadata, fingerprint, last_checked_at, response_time_ms,
+		                         discovered_at, discovery_method)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), $10, NOW(), $11)
+		ON CONFLICT (organization_id, url) WHERE deleted_at IS NULL DO UPDATE SET
+			name = EXCLUDED.name,
+			version = EXCLUDED.version,
+			status = EXCLUDED.status,
+			last_checked_at = NOW(),
+			response_time_ms = EXCLUDED.response_time_ms
+		RETURNING id
+	`
+
+	var serverID uuid.UUID
+	err = d.db.QueryRowContext(ctx, query,
+		orgID,
+		ds.Name,
+		ds.URL,
+		ds.Description,
+		ds.Status,
+		ds.Version,
+		capabilities,
+		metadata,
+		ds.Fingerprint,
+		int(ds.ResponseTime.Milliseconds()),
+		ds.DiscoveryMethod,
+	).Scan(&serverID)
+	if err != nil {
+		return fmt.Errorf("failed to persist discovered server: %w", err)
+	}
+
+	if len(ds.Tools) > 0 {
+		stored := d.tools.RecordTools(serverID, ds.URL, ds.Tools)
+		d.logger.Debug("Stored discovered tools",
+			zap.String("url", ds.URL),
+			zap.Int("tools", len(stored)),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+// Package errors defines error types shared by the MCP, tool and registry packages so
+// callers can tell failures apart with errors.As instead of matching messages. It is
+// usually imported as apperrors.
+package errors
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// ErrQuotaExceeded matches every QuotaError with errors.Is
+var ErrQuotaExceeded = errors.New("tool execution quota exceeded")
+
+// NotFoundError is returned when a resource does not exist. It matches sql.ErrNoRows
+// with errors.Is so callers checking for that keep working.
+type NotFoundError struct {
+	Resource string
+	ID       string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s not found: %s", e.Resource, e.ID)
+}
+
+// Is reports whether target is sql.ErrNoRows
+func (e *NotFoundError) Is(target error) bool {
+	return target == sql.ErrNoRows
+}
+
+// ValidationError is returned when an input is invalid
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Message)
+}
+
+// QuotaError is returned when a tool has used up its executions for a window
+type QuotaError struct {
+	ToolID uuid.UUID
+	Limit  int
+	Window string // hour or day
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("%s: %d executions per %s", ErrQuotaExceeded, e.Limit, e.Window)
+}
+
+// Is reports whether target is ErrQuotaExceeded
+func (e *QuotaError) Is(target error) bool {
+	return target == ErrQuotaExceeded
+}
+
+// ProtocolError is a JSON-RPC error returned by an MCP server
+type ProtocolError struct {
+	Code    int
+	Message string
+}
+
+func (e *ProtocolError) Error() string {
+	return fmt.Sprintf("MCP server returned error %d: %s", e.Code, e.Message)
+}
+
+// ConnectionError is returned when an MCP server or other upstream cannot be reached or
+// does not answer successfully
+type ConnectionError struct {
+	URL   string
+	Cause error
+}
+
+func (e *ConnectionError) Error() string {
+	return fmt.Sprintf("connection to %s failed: %v", e.URL, e.Cause)
+}
+
+func (e *ConnectionError) Unwrap() error {
+	return e.Cause
+}
+
+// HTTPStatus returns the HTTP status for an error of one of this package's types, and
+// false for any other error
+func HTTPStatus(err error) (int, bool) {
+	var notFound *NotFoundError
+	var validation *ValidationError
+	var quota *QuotaError
+	var protocol *ProtocolError
+	var connection *ConnectionError
+
+	switch {
+	case errors.As(err, &notFound):
+		return http.StatusNotFound, true
+	case errors.As(err, &validation):
+		return http.StatusBadRequest, true
+	case errors.As(err, &quota):
+		return http.StatusTooManyRequests, true
+	case errors.As(err, &protocol), errors.As(err, &connection):
+		return http.StatusBadGateway, true
+	default:
+		return 0, false
+	}
+}
+
+// StatusCode returns the HTTP status for an error of one of this package's types, or
+// fallback for any other error
+func StatusCode(err error, fallback int) int {
+	if status, ok := HTTPStatus(err); ok {
+		return status
+	}
+	return fallback
+}
@@ -0,0 +1,64 @@
+package errors
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestHTTPStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantTyped  bool
+	}{
+		{"not found", &NotFoundError{Resource: "tool", ID: "1"}, http.StatusNotFound, true},
+		{"validation", &ValidationError{Field: "url", Message: "must be absolute"}, http.StatusBadRequest, true},
+		{"quota", &QuotaError{ToolID: uuid.New(), Limit: 10, Window: "hour"}, http.StatusTooManyRequests, true},
+		{"protocol", &ProtocolError{Code: -32601, Message: "method not found"}, http.StatusBadGateway, true},
+		{"connection", &ConnectionError{URL: "http://mcp.invalid", Cause: context.DeadlineExceeded}, http.StatusBadGateway, true},
+		{"wrapped not found", fmt.Errorf("failed to get tool: %w", &NotFoundError{Resource: "tool"}), http.StatusNotFound, true},
+		{"wrapped protocol", fmt.Errorf("tool execution failed: %w", &ProtocolError{Code: 1}), http.StatusBadGateway, true},
+		{"plain error", errors.New("boom"), 0, false},
+		{"sql no rows", sql.ErrNoRows, 0, false},
+		{"nil", nil, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, typed := HTTPStatus(tt.err)
+			if status != tt.wantStatus || typed != tt.wantTyped {
+				t.Errorf("HTTPStatus(%v) = %d, %v, want %d, %v", tt.err, status, typed, tt.wantStatus, tt.wantTyped)
+			}
+
+			fallback := tt.wantStatus
+			if !tt.wantTyped {
+				fallback = http.StatusInternalServerError
+			}
+			if got := StatusCode(tt.err, http.StatusInternalServerError); got != fallback {
+				t.Errorf("StatusCode(%v) = %d, want %d", tt.err, got, fallback)
+			}
+		})
+	}
+}
+
+func TestErrorMatching(t *testing.T) {
+	if !errors.Is(fmt.Errorf("lookup: %w", &NotFoundError{Resource: "server"}), sql.ErrNoRows) {
+		t.Error("NotFoundError does not match sql.ErrNoRows")
+	}
+	if !errors.Is(&QuotaError{Limit: 5, Window: "day"}, ErrQuotaExceeded) {
+		t.Error("QuotaError does not match ErrQuotaExceeded")
+	}
+	if errors.Is(&ValidationError{}, sql.ErrNoRows) {
+		t.Error("ValidationError matches sql.ErrNoRows")
+	}
+	if err := (&ConnectionError{URL: "http://mcp.invalid", Cause: context.DeadlineExceeded}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("ConnectionError does not unwrap to its cause")
+	}
+}
@@ -0,0 +1,484 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// maxSchemaRefDepth bounds how many $refs are followed in a row, so cyclic references fail
+// instead of recursing forever
+const maxSchemaRefDepth = 32
+
+// ValidationError is one constraint a tool argument violates
+type ValidationError struct {
+	Path       string `json:"path"`
+	Constraint string `json:"constraint"`
+	Message    string `json:"message"`
+}
+
+// ValidationErrors lists every constraint a tool's arguments violate
+type ValidationErrors []ValidationError
+
+// Error joins the violations into one message
+func (e ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, violation := range e {
+		messages = append(messages, violation.Path+": "+violation.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// SchemaCache holds the input schemas seen during tool discovery, keyed by their $id, so
+// $refs between them can be resolved when arguments are validated
+type SchemaCache struct {
+	mu      sync.RWMutex
+	schemas map[string]map[string]interface{}
+}
+
+// NewSchemaCache creates an empty schema cache
+func NewSchemaCache() *SchemaCache {
+	return &SchemaCache{schemas: make(map[string]map[string]interface{})}
+}
+
+// Add caches a schema under its $id and the $id of each of its definitions. Schemas
+// without an $id are not cached; their local references resolve against themselves.
+func (c *SchemaCache) Add(schema map[string]interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if id, ok := schema["$id"].(string); ok && id != "" {
+		c.schemas[strings.TrimSuffix(id, "#")] = schema
+	}
+	for _, key := range []string{"definitions", "$defs"} {
+		definitions, _ := schema[key].(map[string]interface{})
+		for _, definition := range definitions {
+			if nested, ok := definition.(map[string]interface{}); ok {
+				if id, ok := nested["$id"].(string); ok && id != "" {
+					c.schemas[strings.TrimSuffix(id, "#")] = nested
+				}
+			}
+		}
+	}
+}
+
+// Get returns the schema cached under an $id
+func (c *SchemaCache) Get(id string) (map[string]interface{}, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	schema, ok := c.schemas[id]
+	return schema, ok
+}
+
+// ValidateArguments checks tool arguments against a JSON Schema draft 7 input schema and
+// returns every violation, or nil if the arguments are valid. cache resolves $refs to
+// other schemas and may be nil.
+func ValidateArguments(arguments map[string]interface{}, schema map[string]interface{}, cache *SchemaCache) ValidationErrors {
+	if len(schema) == 0 {
+		return nil
+	}
+	if arguments == nil {
+		arguments = map[string]interface{}{}
+	}
+
+	v := &argumentValidator{cache: cache}
+	v.validate(arguments, schema, schema, "arguments", 0)
+	return v.errors
+}
+
+// argumentValidator collects the violations found while walking a schema
+type argumentValidator struct {
+	cache  *SchemaCache
+	errors ValidationErrors
+}
+
+// fail records a violation
+func (v *argumentValidator) fail(path, constraint, format string, args ...interface{}) {
+	v.errors = append(v.errors, ValidationError{
+		Path:       path,
+		Constraint: constraint,
+		Message:    fmt.Sprintf(format, args...),
+	})
+}
+
+// validate checks value against schema. root is the document local $refs resolve against.
+func (v *argumentValidator) validate(value interface{}, schema, root map[string]interface{}, path string, depth int) {
+	if ref, ok := schema["$ref"].(string); ok {
+		// In draft 7 a $ref replaces the rest of the schema
+		if depth >= maxSchemaRefDepth {
+			v.fail(path, "$ref", "schema references nest too deeply at %s", ref)
+			return
+		}
+		resolved, resolvedRoot, err := v.resolve(ref, root)
+		if err != nil {
+			v.fail(path, "$ref", "%v", err)
+			return
+		}
+		v.validate(value, resolved, resolvedRoot, path, depth+1)
+		return
+	}
+
+	if schemaType, ok := schema["type"]; ok && !matchesSchemaType(value, schemaType) {
+		v.fail(path, "type", "expected %s", schemaTypeList(schemaType))
+		// The remaining keywords assume the right type
+		return
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && !containsJSON(enum, value) {
+		v.fail(path, "enum", "must be one of %s", formatJSONValues(enum))
+	}
+	if constant, ok := schema["const"]; ok && !jsonEqual(value, constant) {
+		v.fail(path, "const", "must equal %s", formatJSONValues([]interface{}{constant}))
+	}
+
+	switch typed := value.(type) {
+	case string:
+		v.validateString(typed, schema, path)
+	case float64:
+		v.validateNumber(typed, schema, path)
+	case map[string]interface{}:
+		v.validateObject(typed, schema, root, path, depth)
+	case []interface{}:
+		v.validateArray(typed, schema, root, path, depth)
+	}
+
+	v.validateCombinators(value, schema, root, path, depth)
+}
+
+// validateString checks minLength, maxLength and pattern
+func (v *argumentValidator) validateString(value string, schema map[string]interface{}, path string) {
+	length := utf8.RuneCountInString(value)
+	if minLength, ok := schemaNumber(schema, "minLength"); ok && float64(length) < minLength {
+		v.fail(path, "minLength", "must be at least %s characters long", formatSchemaNumber(minLength))
+	}
+	if maxLength, ok := schemaNumber(schema, "maxLength"); ok && float64(length) > maxLength {
+		v.fail(path, "maxLength", "must be at most %s characters long", formatSchemaNumber(maxLength))
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			v.fail(path, "pattern", "schema pattern %q is invalid", pattern)
+		} else if !re.MatchString(value) {
+			v.fail(path, "pattern", "must match pattern %s", pattern)
+		}
+	}
+}
+
+// validateNumber checks minimum, maximum, their exclusive forms and multipleOf
+func (v *argumentValidator) validateNumber(value float64, schema map[string]interface{}, path string) {
+	if minimum, ok := schemaNumber(schema, "minimum"); ok && value < minimum {
+		v.fail(path, "minimum", "must be at least %s", formatSchemaNumber(minimum))
+	}
+	if maximum, ok := schemaNumber(schema, "maximum"); ok && value > maximum {
+		v.fail(path, "maximum", "must be at most %s", formatSchemaNumber(maximum))
+	}
+	if minimum, ok := schemaNumber(schema, "exclusiveMinimum"); ok && value <= minimum {
+		v.fail(path, "exclusiveMinimum", "must be greater than %s", formatSchemaNumber(minimum))
+	}
+	if maximum, ok := schemaNumber(schema, "exclusiveMaximum"); ok && value >= maximum {
+		v.fail(path, "exclusiveMaximum", "must be less than %s", formatSchemaNumber(maximum))
+	}
+	if divisor, ok := schemaNumber(schema, "multipleOf"); ok && divisor > 0 {
+		quotient := value / divisor
+		if math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+			v.fail(path, "multipleOf", "must be a multiple of %s", formatSchemaNumber(divisor))
+		}
+	}
+}
+
+// validateObject checks required, property counts, properties, patternProperties and
+// additionalProperties
+func (v *argumentValidator) validateObject(value map[string]interface{}, schema, root map[string]interface{}, path string, depth int) {
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			if field, ok := name.(string); ok {
+				if _, exists := value[field]; !exists {
+					v.fail(path+"."+field, "required", "is required")
+				}
+			}
+		}
+	}
+
+	if minProperties, ok := schemaNumber(schema, "minProperties"); ok && float64(len(value)) < minProperties {
+		v.fail(path, "minProperties", "must have at least %s properties", formatSchemaNumber(minProperties))
+	}
+	if maxProperties, ok := schemaNumber(schema, "maxProperties"); ok && float64(len(value)) > maxProperties {
+		v.fail(path, "maxProperties", "must have at most %s properties", formatSchemaNumber(maxProperties))
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	patternProperties, _ := schema["patternProperties"].(map[string]interface{})
+
+	for _, field := range sortedKeys(value) {
+		fieldValue := value[field]
+		fieldPath := path + "." + field
+		matched := false
+
+		if fieldSchema, ok := properties[field].(map[string]interface{}); ok {
+			matched = true
+			v.validate(fieldValue, fieldSchema, root, fieldPath, depth)
+		}
+		for pattern, patternSchema := range patternProperties {
+			re, err := regexp.Compile(pattern)
+			if err != nil || !re.MatchString(field) {
+				continue
+			}
+			matched = true
+			if fieldSchema, ok := patternSchema.(map[string]interface{}); ok {
+				v.validate(fieldValue, fieldSchema, root, fieldPath, depth)
+			}
+		}
+		if matched {
+			continue
+		}
+
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				v.fail(fieldPath, "additionalProperties", "is not an allowed property")
+			}
+		case map[string]interface{}:
+			v.validate(fieldValue, additional, root, fieldPath, depth)
+		}
+	}
+}
+
+// validateArray checks minItems, maxItems, uniqueItems, items and contains
+func (v *argumentValidator) validateArray(value []interface{}, schema, root map[string]interface{}, path string, depth int) {
+	if minItems, ok := schemaNumber(schema, "minItems"); ok && float64(len(value)) < minItems {
+		v.fail(path, "minItems", "must have at least %s items", formatSchemaNumber(minItems))
+	}
+	if maxItems, ok := schemaNumber(schema, "maxItems"); ok && float64(len(value)) > maxItems {
+		v.fail(path, "maxItems", "must have at most %s items", formatSchemaNumber(maxItems))
+	}
+	if unique, ok := schema["uniqueItems"].(bool); ok && unique {
+		for i := 1; i < len(value); i++ {
+			if containsJSON(value[:i], value[i]) {
+				v.fail(fmt.Sprintf("%s[%d]", path, i), "uniqueItems", "duplicates an earlier item")
+			}
+		}
+	}
+
+	switch items := schema["items"].(type) {
+	case map[string]interface{}:
+		for i, item := range value {
+			v.validate(item, items, root, fmt.Sprintf("%s[%d]", path, i), depth)
+		}
+	case []interface{}:
+		// Tuple validation: each position has its own schema
+		for i, item := range value {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			if i < len(items) {
+				if itemSchema, ok := items[i].(map[string]interface{}); ok {
+					v.validate(item, itemSchema, root, itemPath, depth)
+				}
+				continue
+			}
+			switch additional := schema["additionalItems"].(type) {
+			case bool:
+				if !additional {
+					v.fail(itemPath, "additionalItems", "is beyond the %d allowed items", len(items))
+				}
+			case map[string]interface{}:
+				v.validate(item, additional, root, itemPath, depth)
+			}
+		}
+	}
+
+	if contains, ok := schema["contains"].(map[string]interface{}); ok {
+		found := false
+		for _, item := range value {
+			if v.matches(item, contains, root, path, depth) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.fail(path, "contains", "must contain an item matching the schema")
+		}
+	}
+}
+
+// validateCombinators checks allOf, anyOf, oneOf and not
+func (v *argumentValidator) validateCombinators(value interface{}, schema, root map[string]interface{}, path string, depth int) {
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		for _, subschema := range allOf {
+			if sub, ok := subschema.(map[string]interface{}); ok {
+				v.validate(value, sub, root, path, depth)
+			}
+		}
+	}
+
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		matched := false
+		for _, subschema := range anyOf {
+			if sub, ok := subschema.(map[string]interface{}); ok && v.matches(value, sub, root, path, depth) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.fail(path, "anyOf", "must match at least one of the allowed schemas")
+		}
+	}
+
+	if oneOf, ok := schema["oneOf"].([]interface{}); ok {
+		matches := 0
+		for _, subschema := range oneOf {
+			if sub, ok := subschema.(map[string]interface{}); ok && v.matches(value, sub, root, path, depth) {
+				matches++
+			}
+		}
+		if matches != 1 {
+			v.fail(path, "oneOf", "must match exactly one of the allowed schemas, matched %d", matches)
+		}
+	}
+
+	if not, ok := schema["not"].(map[string]interface{}); ok && v.matches(value, not, root, path, depth) {
+		v.fail(path, "not", "must not match the disallowed schema")
+	}
+}
+
+// matches reports whether value satisfies schema without recording its violations
+func (v *argumentValidator) matches(value interface{}, schema, root map[string]interface{}, path string, depth int) bool {
+	sub := &argumentValidator{cache: v.cache}
+	sub.validate(value, schema, root, path, depth)
+	return len(sub.errors) == 0
+}
+
+// resolve finds the schema a $ref points to and the document it belongs to. Fragments
+// are JSON pointers; other references are looked up in the schema cache by $id.
+func (v *argumentValidator) resolve(ref string, root map[string]interface{}) (map[string]interface{}, map[string]interface{}, error) {
+	document, fragment, _ := strings.Cut(ref, "#")
+
+	target := root
+	if document != "" {
+		if id, ok := root["$id"].(string); !ok || strings.TrimSuffix(id, "#") != document {
+			if v.cache == nil {
+				return nil, nil, fmt.Errorf("unresolved schema reference %s", ref)
+			}
+			cached, ok := v.cache.Get(document)
+			if !ok {
+				return nil, nil, fmt.Errorf("unresolved schema reference %s", ref)
+			}
+			target = cached
+		}
+	}
+
+	resolved, err := resolveJSONPointer(target, fragment)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unresolved schema reference %s: %w", ref, err)
+	}
+
+	return resolved, target, nil
+}
+
+// resolveJSONPointer follows a JSON pointer such as /definitions/address within a schema
+func resolveJSONPointer(schema map[string]interface{}, pointer string) (map[string]interface{}, error) {
+	if pointer == "" || pointer == "/" {
+		return schema, nil
+	}
+	if unescaped, err := url.PathUnescape(pointer); err == nil {
+		pointer = unescaped
+	}
+
+	var current interface{} = schema
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+
+		switch node := current.(type) {
+		case map[string]interface{}:
+			next, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("%s not found", token)
+			}
+			current = next
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, fmt.Errorf("index %s out of range", token)
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("%s not found", token)
+		}
+	}
+
+	resolved, ok := current.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("does not point to a schema")
+	}
+	return resolved, nil
+}
+
+// schemaNumber reads a numeric schema keyword
+func schemaNumber(schema map[string]interface{}, keyword string) (float64, bool) {
+	switch n := schema[keyword].(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+// formatSchemaNumber prints a schema number without a trailing .0
+func formatSchemaNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// schemaTypeList describes a type keyword, which may be a name or a list of names
+func schemaTypeList(schemaType interface{}) string {
+	if names, ok := schemaType.([]interface{}); ok {
+		parts := make([]string, 0, len(names))
+		for _, name := range names {
+			parts = append(parts, fmt.Sprint(name))
+		}
+		return strings.Join(parts, " or ")
+	}
+	return fmt.Sprint(schemaType)
+}
+
+// sortedKeys returns an object's keys in order, so violations are reported consistently
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// containsJSON reports whether values contains value
+func containsJSON(values []interface{}, value interface{}) bool {
+	for _, candidate := range values {
+		if jsonEqual(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatJSONValues prints values as a comma-separated list of JSON
+func formatJSONValues(values []interface{}) string {
+	parts := make([]string, 0, len(values))
+	for _, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			data = []byte(fmt.Sprint(value))
+		}
+		parts = append(parts, string(data))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,224 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestValidateArguments(t *testing.T) {
+	tests := []struct {
+		name      string
+		schema    string
+		arguments string
+		want      []string // path and constraint of each violation
+	}{
+		{
+			name:      "string within length",
+			schema:    `{"properties": {"name": {"type": "string", "minLength": 2, "maxLength": 5}}}`,
+			arguments: `{"name": "ada"}`,
+		},
+		{
+			name:      "string too short",
+			schema:    `{"properties": {"name": {"type": "string", "minLength": 2}}}`,
+			arguments: `{"name": "a"}`,
+			want:      []string{"arguments.name minLength"},
+		},
+		{
+			name:      "string length counts characters",
+			schema:    `{"properties": {"name": {"type": "string", "maxLength": 2}}}`,
+			arguments: `{"name": "éé"}`,
+		},
+		{
+			name:      "string pattern",
+			schema:    `{"properties": {"id": {"type": "string", "pattern": "^[a-z]+-[0-9]+$"}}}`,
+			arguments: `{"id": "ABC"}`,
+			want:      []string{"arguments.id pattern"},
+		},
+		{
+			name:      "string of wrong type",
+			schema:    `{"properties": {"name": {"type": "string", "minLength": 10}}}`,
+			arguments: `{"name": 3}`,
+			want:      []string{"arguments.name type"},
+		},
+		{
+			name:      "integer",
+			schema:    `{"properties": {"count": {"type": "integer", "minimum": 1, "maximum": 10}}}`,
+			arguments: `{"count": 5}`,
+		},
+		{
+			name:      "integer with fraction",
+			schema:    `{"properties": {"count": {"type": "integer"}}}`,
+			arguments: `{"count": 1.5}`,
+			want:      []string{"arguments.count type"},
+		},
+		{
+			name:      "integer out of range",
+			schema:    `{"properties": {"count": {"type": "integer", "minimum": 1, "exclusiveMaximum": 10}}}`,
+			arguments: `{"count": 10}`,
+			want:      []string{"arguments.count exclusiveMaximum"},
+		},
+		{
+			name:      "integer multiple of",
+			schema:    `{"properties": {"step": {"type": "integer", "multipleOf": 5}}}`,
+			arguments: `{"step": 12}`,
+			want:      []string{"arguments.step multipleOf"},
+		},
+		{
+			name:      "enum value",
+			schema:    `{"properties": {"mode": {"enum": ["read", "write"]}}}`,
+			arguments: `{"mode": "write"}`,
+		},
+		{
+			name:      "enum mismatch",
+			schema:    `{"properties": {"mode": {"enum": ["read", "write"]}}}`,
+			arguments: `{"mode": "delete"}`,
+			want:      []string{"arguments.mode enum"},
+		},
+		{
+			name:      "enum of numbers",
+			schema:    `{"properties": {"level": {"type": "integer", "enum": [1, 2, 3]}}}`,
+			arguments: `{"level": 4}`,
+			want:      []string{"arguments.level enum"},
+		},
+		{
+			name: "nested object",
+			schema: `{"properties": {"address": {"type": "object", "required": ["city"],
+				"properties": {"city": {"type": "string"}, "zip": {"type": "string", "pattern": "^[0-9]{5}$"}}}}}`,
+			arguments: `{"address": {"city": "Paris", "zip": "75001"}}`,
+		},
+		{
+			name: "nested object violations",
+			schema: `{"properties": {"address": {"type": "object", "required": ["city"], "additionalProperties": false,
+				"properties": {"city": {"type": "string"}, "zip": {"type": "string", "pattern": "^[0-9]{5}$"}}}}}`,
+			arguments: `{"address": {"zip": "7500", "street": "Rue"}}`,
+			want: []string{
+				"arguments.address.city required",
+				"arguments.address.street additionalProperties",
+				"arguments.address.zip pattern",
+			},
+		},
+		{
+			name:      "missing required argument",
+			schema:    `{"required": ["path"], "properties": {"path": {"type": "string"}}}`,
+			arguments: `{}`,
+			want:      []string{"arguments.path required"},
+		},
+		{
+			name:      "array of strings",
+			schema:    `{"properties": {"tags": {"type": "array", "items": {"type": "string"}, "minItems": 1}}}`,
+			arguments: `{"tags": ["a", "b"]}`,
+		},
+		{
+			name:      "array item of wrong type",
+			schema:    `{"properties": {"tags": {"type": "array", "items": {"type": "string"}}}}`,
+			arguments: `{"tags": ["a", 2]}`,
+			want:      []string{"arguments.tags[1] type"},
+		},
+		{
+			name:      "array size and uniqueness",
+			schema:    `{"properties": {"ids": {"type": "array", "maxItems": 2, "uniqueItems": true}}}`,
+			arguments: `{"ids": [1, 2, 1]}`,
+			want:      []string{"arguments.ids maxItems", "arguments.ids[2] uniqueItems"},
+		},
+		{
+			name: "array of objects",
+			schema: `{"properties": {"users": {"type": "array",
+				"items": {"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}}}}}`,
+			arguments: `{"users": [{"name": "ada"}, {}]}`,
+			want:      []string{"arguments.users[1].name required"},
+		},
+		{
+			name:      "tuple with extra item",
+			schema:    `{"properties": {"point": {"type": "array", "items": [{"type": "number"}, {"type": "number"}], "additionalItems": false}}}`,
+			arguments: `{"point": [1, 2, 3]}`,
+			want:      []string{"arguments.point[2] additionalItems"},
+		},
+		{
+			name: "local reference",
+			schema: `{"definitions": {"port": {"type": "integer", "minimum": 1, "maximum": 65535}},
+				"properties": {"port": {"$ref": "#/definitions/port"}}}`,
+			arguments: `{"port": 70000}`,
+			want:      []string{"arguments.port maximum"},
+		},
+		{
+			name:      "unresolved reference",
+			schema:    `{"properties": {"port": {"$ref": "#/definitions/missing"}}}`,
+			arguments: `{"port": 80}`,
+			want:      []string{"arguments.port $ref"},
+		},
+		{
+			name:      "one of",
+			schema:    `{"properties": {"id": {"oneOf": [{"type": "string"}, {"type": "integer"}]}}}`,
+			arguments: `{"id": true}`,
+			want:      []string{"arguments.id oneOf"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := ValidateArguments(decodeObject(t, tt.arguments), decodeObject(t, tt.schema), nil)
+
+			var got []string
+			for _, violation := range violations {
+				got = append(got, violation.Path+" "+violation.Constraint)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateArguments() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateArgumentsCachedReference(t *testing.T) {
+	cache := NewSchemaCache()
+	cache.Add(decodeObject(t, `{"$id": "https://example.com/address.json", "type": "object", "required": ["city"]}`))
+
+	schema := decodeObject(t, `{"properties": {"home": {"$ref": "https://example.com/address.json"}}}`)
+
+	if violations := ValidateArguments(decodeObject(t, `{"home": {"city": "Oslo"}}`), schema, cache); violations != nil {
+		t.Errorf("ValidateArguments() = %v, want no violations", violations)
+	}
+
+	violations := ValidateArguments(decodeObject(t, `{"home": {}}`), schema, cache)
+	if len(violations) != 1 || violations[0].Path != "arguments.home.city" || violations[0].Constraint != "required" {
+		t.Errorf("ValidateArguments() = %v, want arguments.home.city required", violations)
+	}
+
+	violations = ValidateArguments(decodeObject(t, `{"home": {}}`), schema, nil)
+	if len(violations) != 1 || violations[0].Constraint != "$ref" {
+		t.Errorf("ValidateArguments() without cache = %v, want an unresolved $ref", violations)
+	}
+}
+
+func TestToolManagerValidateArgumentsRejectsUndeclared(t *testing.T) {
+	tm := NewToolManager(nil, zap.NewNop())
+	schema := decodeObject(t, `{"properties": {"path": {"type": "string"}}}`)
+
+	err := tm.validateArguments(decodeObject(t, `{"path": "/tmp", "mode": "rw"}`), schema)
+
+	var violations ValidationErrors
+	if !errors.As(err, &violations) {
+		t.Fatalf("validateArguments() error = %v, want ValidationErrors", err)
+	}
+	if len(violations) != 1 || violations[0].Path != "arguments.mode" || violations[0].Constraint != "additionalProperties" {
+		t.Errorf("validateArguments() = %v, want arguments.mode additionalProperties", violations)
+	}
+	if _, declared := schema["additionalProperties"]; declared {
+		t.Error("validateArguments() modified the tool's schema")
+	}
+}
+
+// decodeObject parses a JSON object
+func decodeObject(t *testing.T, data string) map[string]interface{} {
+	t.Helper()
+
+	var object map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &object); err != nil {
+		t.Fatalf("invalid JSON %s: %v", data, err)
+	}
+	return object
+}
@@ -0,0 +1,121 @@
+package mcp
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+)
+
+// Server metadata keys holding the PEM files used for mutual TLS with the server
+const (
+	TLSCertPathMetadataKey = "tls_cert_path"
+	TLSKeyPathMetadataKey  = "tls_key_path"
+	TLSCAPathMetadataKey   = "tls_ca_path"
+)
+
+// ServerTLSFiles are the PEM files used to connect to a server over mutual TLS. The
+// client certificate and key must be given together; the CA bundle is optional and
+// replaces the system roots when set.
+type ServerTLSFiles struct {
+	CertFile string
+	KeyFile  string
+	CAFile   string
+}
+
+// IsZero reports whether no TLS files are set
+func (f ServerTLSFiles) IsZero() bool {
+	return f == ServerTLSFiles{}
+}
+
+// LoadClientTLSConfig builds a TLS configuration from a client certificate and key and
+// an optional CA bundle
+func LoadClientTLSConfig(files ServerTLSFiles) (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if (files.CertFile == "") != (files.KeyFile == "") {
+		return nil, errors.New("client certificate and key must be set together")
+	}
+	if files.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(files.CertFile, files.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	if files.CAFile != "" {
+		pem, err := os.ReadFile(files.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", files.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	return config, nil
+}
+
+// WithClientCert configures m to present the client certificate, and trust the CA
+// bundle, when connecting to any server without its own TLS configuration. It returns
+// m so it can be chained after the constructor.
+func (m *MCPProtocol) WithClientCert(certFile, keyFile, caFile string) (*MCPProtocol, error) {
+	config, err := LoadClientTLSConfig(ServerTLSFiles{CertFile: certFile, KeyFile: keyFile, CAFile: caFile})
+	if err != nil {
+		return nil, err
+	}
+
+	m.pool.SetDefaultTLSConfig(config)
+	return m, nil
+}
+
+// ConfigureServerTLS sets the TLS files used for connections to serverURL. Servers on
+// the same scheme and host share a configuration. The files are reloaded only when
+// they differ from the ones last configured for the server.
+func (m *MCPProtocol) ConfigureServerTLS(serverURL string, files ServerTLSFiles) error {
+	current, ok := m.serverTLS.Load(serverURL)
+	if ok && current.(ServerTLSFiles) == files || !ok && files.IsZero() {
+		return nil
+	}
+
+	var config *tls.Config
+	if !files.IsZero() {
+		var err error
+		if config, err = LoadClientTLSConfig(files); err != nil {
+			return err
+		}
+	}
+
+	if err := m.pool.SetTLSConfig(serverURL, config); err != nil {
+		return fmt.Errorf("failed to set TLS configuration: %w", err)
+	}
+	m.serverTLS.Store(serverURL, files)
+
+	return nil
+}
+
+// LoadServerTLSFiles reads a server's TLS files from its tls_cert_path, tls_key_path
+// and tls_ca_path metadata
+func LoadServerTLSFiles(ctx context.Context, db *sql.DB, serverID uuid.UUID) (ServerTLSFiles, error) {
+	var files ServerTLSFiles
+	query := `
+		SELECT COALESCE(metadata->>$2, ''), COALESCE(metadata->>$3, ''), COALESCE(metadata->>$4, '')
+		FROM mcp_servers WHERE id = $1
+	`
+	err := db.QueryRowContext(ctx, query, serverID,
+		TLSCertPathMetadataKey, TLSKeyPathMetadataKey, TLSCAPathMetadataKey,
+	).Scan(&files.CertFile, &files.KeyFile, &files.CAFile)
+	if err != nil {
+		return ServerTLSFiles{}, fmt.Errorf("failed to get server TLS settings: %w", err)
+	}
+
+	return files, nil
+}
@@ -0,0 +1,398 @@
+package mcp
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MaxConnsPerMCPServer is the default number of connections pooled per MCP server.
+// It is read whenever a new MCPProtocol is created.
+var MaxConnsPerMCPServer = 10
+
+// Default transport settings of pooled connections
+const (
+	DefaultIdleConnTimeout = 90 * time.Second
+	DefaultDialTimeout     = 10 * time.Second
+)
+
+// maxPoolTargets bounds how many servers the pool keeps clients for. Server URLs come
+// from users, so without a bound every distinct host would keep a transport forever.
+const maxPoolTargets = 256
+
+// TransportConfig tunes the HTTP transport used for each MCP server. Zero values use
+// the defaults; a zero ResponseHeaderTimeout leaves only the client timeout.
+type TransportConfig struct {
+	// MaxIdleConnsPerHost is how many keep-alive connections are kept open per server.
+	// It defaults to the pool's connection limit.
+	MaxIdleConnsPerHost   int
+	IdleConnTimeout       time.Duration
+	ResponseHeaderTimeout time.Duration
+	DialTimeout           time.Duration
+}
+
+// ConnectionPool keeps a warmed-up HTTP client per MCP server so that
+// repeated calls reuse keep-alive connections instead of dialing again.
+// HTTP/2 targets share a single multiplexed connection per client.
+type ConnectionPool struct {
+	mu        sync.RWMutex
+	maxConns  int
+	timeout   time.Duration
+	transport TransportConfig
+	targets   map[string]*poolTarget
+
+	// maxTargets is how many servers are pooled before the least recently used is evicted
+	maxTargets int
+
+	// defaultTLS is used for servers without their own entry in tlsConfigs
+	defaultTLS *tls.Config
+	tlsConfigs sync.Map // target key -> *tls.Config
+}
+
+// ConnectionPoolStats represents connection pool usage for a single MCP server
+type ConnectionPoolStats struct {
+	Target            string        `json:"target"`
+	ActiveConnections int64         `json:"active_connections"`
+	IdleConnections   int64         `json:"idle_connections"`
+	OpenConnections   int64         `json:"open_connections"`
+	MaxConnections    int           `json:"max_connections"`
+	TotalRequests     int64         `json:"total_requests"`
+	ReusedConnections int64         `json:"reused_connections"`
+	AverageWaitTime   time.Duration `json:"average_wait_time"`
+}
+
+// poolTarget holds the client and counters for one server
+type poolTarget struct {
+	client    *http.Client
+	transport *http.Transport
+	active    int64
+	open      int64
+	requests  int64
+	reused    int64
+	waitNanos int64
+	lastUsed  atomic.Int64 // unix nanoseconds
+
+	// stream shares the transport but has no timeout, so long-lived streams are
+	// bounded only by their request context
+	stream *http.Client
+}
+
+// trackedConn decrements the open connection counter when closed
+type trackedConn struct {
+	net.Conn
+	target *poolTarget
+	once   sync.Once
+}
+
+// Close closes the underlying connection
+func (c *trackedConn) Close() error {
+	c.once.Do(func() {
+		atomic.AddInt64(&c.target.open, -1)
+	})
+	return c.Conn.Close()
+}
+
+// NewConnectionPool creates a new connection pool
+func NewConnectionPool(maxConnsPerServer int, timeout time.Duration) *ConnectionPool {
+	return NewConnectionPoolWithTransport(maxConnsPerServer, timeout, TransportConfig{})
+}
+
+// NewConnectionPoolWithTransport creates a new connection pool whose connections use
+// the given transport settings
+func NewConnectionPoolWithTransport(maxConnsPerServer int, timeout time.Duration, transport TransportConfig) *ConnectionPool {
+	if maxConnsPerServer <= 0 {
+		maxConnsPerServer = MaxConnsPerMCPServer
+	}
+	if transport.MaxIdleConnsPerHost <= 0 {
+		transport.MaxIdleConnsPerHost = maxConnsPerServer
+	}
+	if transport.IdleConnTimeout <= 0 {
+		transport.IdleConnTimeout = DefaultIdleConnTimeout
+	}
+	if transport.DialTimeout <= 0 {
+		transport.DialTimeout = DefaultDialTimeout
+	}
+
+	return &ConnectionPool{
+		maxConns:   maxConnsPerServer,
+		timeout:    timeout,
+		transport:  transport,
+		targets:    make(map[string]*poolTarget),
+		maxTargets: maxPoolTargets,
+	}
+}
+
+// Do sends the request using the pooled client for its target server
+func (p *ConnectionPool) Do(req *http.Request) (*http.Response, error) {
+	target := p.target(req.URL)
+	return p.do(target, target.client, req)
+}
+
+// DoStream sends a request whose response is streamed. It shares the target's
+// connections but is not subject to the pool's timeout; the request context must
+// bound it instead.
+func (p *ConnectionPool) DoStream(req *http.Request) (*http.Response, error) {
+	target := p.target(req.URL)
+	return p.do(target, target.stream, req)
+}
+
+// do sends the request with the given client, recording it in the target's counters
+func (p *ConnectionPool) do(target *poolTarget, client *http.Client, req *http.Request) (*http.Response, error) {
+
+	start := time.Now()
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			atomic.AddInt64(&target.waitNanos, int64(time.Since(start)))
+			if info.Reused {
+				atomic.AddInt64(&target.reused, 1)
+			}
+		},
+	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+
+	atomic.AddInt64(&target.requests, 1)
+	atomic.AddInt64(&target.active, 1)
+	defer atomic.AddInt64(&target.active, -1)
+
+	return client.Do(req)
+}
+
+// Warm pre-establishes up to count keep-alive connections to the server. It returns
+// the dial errors joined, so an unreachable server is not reported as warmed.
+func (p *ConnectionPool) Warm(ctx context.Context, serverURL string, count int) error {
+	parsed, err := url.Parse(serverURL)
+	if err != nil {
+		return err
+	}
+
+	if count > p.maxConns {
+		count = p.maxConns
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := http.NewRequestWithContext(ctx, http.MethodOptions, parsed.String(), nil)
+			if err == nil {
+				var resp *http.Response
+				if resp, err = p.Do(req); err == nil {
+					resp.Body.Close()
+					return
+				}
+			}
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// Stats returns connection pool statistics for every known server
+func (p *ConnectionPool) Stats() []ConnectionPoolStats {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	stats := make([]ConnectionPoolStats, 0, len(p.targets))
+	for key, target := range p.targets {
+		active := atomic.LoadInt64(&target.active)
+		open := atomic.LoadInt64(&target.open)
+		requests := atomic.LoadInt64(&target.requests)
+
+		idle := open - active
+		if idle < 0 {
+			idle = 0
+		}
+
+		var avgWait time.Duration
+		if requests > 0 {
+			avgWait = time.Duration(atomic.LoadInt64(&target.waitNanos) / requests)
+		}
+
+		stats = append(stats, ConnectionPoolStats{
+			Target:            key,
+			ActiveConnections: active,
+			IdleConnections:   idle,
+			OpenConnections:   open,
+			MaxConnections:    p.maxConns,
+			TotalRequests:     requests,
+			ReusedConnections: atomic.LoadInt64(&target.reused),
+			AverageWaitTime:   avgWait,
+		})
+	}
+
+	return stats
+}
+
+// Close closes all idle connections held by the pool
+func (p *ConnectionPool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for key, target := range p.targets {
+		target.transport.CloseIdleConnections()
+		delete(p.targets, key)
+	}
+}
+
+// SetTLSConfig sets the TLS configuration for connections to the server's scheme and
+// host; a nil config reverts to the default. Pooled connections to the server are
+// dropped so the next request uses the new configuration.
+func (p *ConnectionPool) SetTLSConfig(serverURL string, config *tls.Config) error {
+	parsed, err := url.Parse(serverURL)
+	if err != nil {
+		return err
+	}
+	key := targetKey(parsed)
+
+	if config == nil {
+		p.tlsConfigs.Delete(key)
+	} else {
+		p.tlsConfigs.Store(key, config)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if target, exists := p.targets[key]; exists {
+		target.transport.CloseIdleConnections()
+		delete(p.targets, key)
+	}
+
+	return nil
+}
+
+// SetDefaultTLSConfig sets the TLS configuration for servers without their own and
+// drops all pooled connections
+func (p *ConnectionPool) SetDefaultTLSConfig(config *tls.Config) {
+	p.mu.Lock()
+	p.defaultTLS = config
+	p.mu.Unlock()
+
+	p.Close()
+}
+
+// targetKey identifies the pool entry for a URL
+func targetKey(u *url.URL) string {
+	return u.Scheme + "://" + u.Host
+}
+
+// target returns the pool entry for the URL's scheme and host, creating it if needed
+func (p *ConnectionPool) target(u *url.URL) *poolTarget {
+	key := targetKey(u)
+
+	now := time.Now()
+
+	p.mu.RLock()
+	target, exists := p.targets[key]
+	p.mu.RUnlock()
+	if exists {
+		target.lastUsed.Store(now.UnixNano())
+		return target
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if target, exists := p.targets[key]; exists {
+		target.lastUsed.Store(now.UnixNano())
+		return target
+	}
+	p.evictLocked(now)
+
+	tlsConfig := p.defaultTLS
+	if config, ok := p.tlsConfigs.Load(key); ok {
+		tlsConfig = config.(*tls.Config)
+	}
+	if tlsConfig != nil {
+		tlsConfig = tlsConfig.Clone()
+	}
+
+	target = &poolTarget{}
+	dialer := &net.Dialer{
+		Timeout:   p.transport.DialTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+	target.transport = &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := dialer.DialContext(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+			atomic.AddInt64(&target.open, 1)
+			return &trackedConn{Conn: conn, target: target}, nil
+		},
+		TLSClientConfig:       tlsConfig,
+		ForceAttemptHTTP2:     true,
+		DisableKeepAlives:     false,
+		MaxIdleConns:          p.transport.MaxIdleConnsPerHost,
+		MaxIdleConnsPerHost:   p.transport.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       p.maxConns,
+		IdleConnTimeout:       p.transport.IdleConnTimeout,
+		ResponseHeaderTimeout: p.transport.ResponseHeaderTimeout,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+	target.client = &http.Client{
+		Transport: target.transport,
+		Timeout:   p.timeout,
+	}
+	target.stream = &http.Client{Transport: target.transport}
+	target.lastUsed.Store(now.UnixNano())
+	p.targets[key] = target
+
+	return target
+}
+
+// evictLocked makes room for a new target. It drops targets unused for longer than
+// the idle connection timeout, then the least recently used ones while the pool is
+// full. Targets with requests in flight are kept. Evicted transports have their idle
+// connections closed. The caller must hold p.mu.
+func (p *ConnectionPool) evictLocked(now time.Time) {
+	evict := func(key string, target *poolTarget) {
+		target.transport.CloseIdleConnections()
+		delete(p.targets, key)
+	}
+
+	cutoff := now.Add(-p.transport.IdleConnTimeout).UnixNano()
+	for key, target := range p.targets {
+		if atomic.LoadInt64(&target.active) == 0 && target.lastUsed.Load() < cutoff {
+			evict(key, target)
+		}
+	}
+
+	for len(p.targets) >= p.maxTargets {
+		var (
+			oldestKey    string
+			oldestTarget *poolTarget
+		)
+		for key, target := range p.targets {
+			if atomic.LoadInt64(&target.active) > 0 {
+				continue
+			}
+			if oldestTarget == nil || target.lastUsed.Load() < oldestTarget.lastUsed.Load() {
+				oldestKey, oldestTarget = key, target
+			}
+		}
+		if oldestTarget == nil {
+			// Every target is busy; let the pool grow rather than break requests
+			return
+		}
+		evict(oldestKey, oldestTarget)
+	}
+}
@@ -0,0 +1,164 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestConnectionPoolWarm(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	pool := NewConnectionPool(4, 5*time.Second)
+	if err := pool.Warm(context.Background(), server.URL, 2); err != nil {
+		t.Fatalf("Warm() against a live server = %v, want nil", err)
+	}
+}
+
+func TestConnectionPoolWarmReportsDialErrors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	deadURL := server.URL
+	server.Close()
+
+	pool := NewConnectionPool(4, 5*time.Second)
+	if err := pool.Warm(context.Background(), deadURL, 2); err == nil {
+		t.Fatal("Warm() against a closed server = nil, want an error")
+	}
+}
+
+func TestConnectionPoolEviction(t *testing.T) {
+	urlOf := func(host string) *url.URL {
+		return &url.URL{Scheme: "http", Host: host}
+	}
+
+	tests := []struct {
+		name     string
+		prepare  func(p *ConnectionPool)
+		wantKept []string
+		wantGone []string
+	}{
+		{
+			name: "least recently used",
+			prepare: func(p *ConnectionPool) {
+				p.target(urlOf("a"))
+				p.target(urlOf("b")).lastUsed.Store(time.Now().Add(-time.Second).UnixNano())
+			},
+			wantKept: []string{"http://a"},
+			wantGone: []string{"http://b"},
+		},
+		{
+			name: "busy target kept",
+			prepare: func(p *ConnectionPool) {
+				atomic.AddInt64(&p.target(urlOf("a")).active, 1)
+				p.target(urlOf("b"))
+			},
+			wantKept: []string{"http://a"},
+			wantGone: []string{"http://b"},
+		},
+		{
+			name: "idle target",
+			prepare: func(p *ConnectionPool) {
+				p.maxTargets = 10
+				p.target(urlOf("a")).lastUsed.Store(time.Now().Add(-time.Hour).UnixNano())
+				p.target(urlOf("b"))
+			},
+			wantKept: []string{"http://b"},
+			wantGone: []string{"http://a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := NewConnectionPool(4, 5*time.Second)
+			pool.maxTargets = 2
+			tt.prepare(pool)
+			pool.target(urlOf("c"))
+
+			for _, key := range tt.wantKept {
+				if _, ok := pool.targets[key]; !ok {
+					t.Errorf("%s was evicted, want kept", key)
+				}
+			}
+			for _, key := range tt.wantGone {
+				if _, ok := pool.targets[key]; ok {
+					t.Errorf("%s was kept, want evicted", key)
+				}
+			}
+		})
+	}
+}
+
+func TestConnectionPoolEvictionClosesIdleConnections(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	pool := NewConnectionPool(4, 5*time.Second)
+	pool.maxTargets = 1
+	if err := pool.Warm(context.Background(), server.URL, 1); err != nil {
+		t.Fatalf("Warm() = %v", err)
+	}
+	parsed, _ := url.Parse(server.URL)
+	target := pool.target(parsed)
+	if open := atomic.LoadInt64(&target.open); open == 0 {
+		t.Fatal("no connection open after Warm()")
+	}
+
+	pool.target(&url.URL{Scheme: "http", Host: "other"})
+
+	if open := atomic.LoadInt64(&target.open); open != 0 {
+		t.Errorf("evicted target has %d open connections, want 0", open)
+	}
+}
+
+// BenchmarkSerialPings sends 100 serial pings to a local MCP server, reusing keep-alive
+// connections and with keep-alives disabled, and reports how many connections were reused
+func BenchmarkSerialPings(b *testing.B) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":99,"result":{}}`))
+	}))
+	defer server.Close()
+
+	const pings = 100
+
+	for _, keepAlive := range []bool{true, false} {
+		name := "keep-alive"
+		if !keepAlive {
+			name = "no keep-alive"
+		}
+
+		b.Run(name, func(b *testing.B) {
+			ctx := context.Background()
+			var reused int64
+
+			for i := 0; i < b.N; i++ {
+				m := NewMCPProtocol(zap.NewNop())
+				u, _ := url.Parse(server.URL)
+				m.pool.target(u).transport.DisableKeepAlives = !keepAlive
+
+				for j := 0; j < pings; j++ {
+					if err := m.Ping(ctx, server.URL); err != nil {
+						b.Fatalf("Ping() error = %v", err)
+					}
+				}
+				for _, stats := range m.ConnectionPoolStats() {
+					reused += stats.ReusedConnections
+				}
+				m.pool.Close()
+			}
+
+			b.ReportMetric(float64(reused)/float64(b.N), "reused/op")
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Deep health check steps
+const (
+	DeepHealthStepPing          = "ping"
+	DeepHealthStepListTools     = "list_tools"
+	DeepHealthStepListResources = "list_resources"
+)
+
+// deepHealthSlowFactor marks a step slow when it takes longer than this multiple of its P95
+const deepHealthSlowFactor = 3
+
+// DeepHealthStep is the outcome of a single deep health check step
+type DeepHealthStep struct {
+	Name       string `json:"name"`
+	Success    bool   `json:"success"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+	P95Ms      int64  `json:"p95_ms,omitempty"` // 0 when there is not enough history
+	Slow       bool   `json:"slow"`
+}
+
+// DeepHealthResult is the outcome of a deep health check
+type DeepHealthResult struct {
+	ServerURL        string           `json:"server_url"`
+	Healthy          bool             `json:"healthy"`
+	Degraded         bool             `json:"degraded"`
+	ToolCount        int              `json:"tool_count"`
+	KnownToolCount   *int             `json:"known_tool_count,omitempty"`
+	ToolCountDropped bool             `json:"tool_count_dropped"`
+	ResourceCount    int              `json:"resource_count"`
+	Steps            []DeepHealthStep `json:"steps"`
+	CheckedAt        time.Time        `json:"checked_at"`
+}
+
+// HealthBaseline is the historical state a deep health check is compared against
+type HealthBaseline struct {
+	KnownToolCount *int
+	StepP95        map[string]time.Duration
+}
+
+// HealthBaselineStore loads and records deep health check history
+type HealthBaselineStore interface {
+	LoadHealthBaseline(ctx context.Context, serverURL string) (*HealthBaseline, error)
+	RecordDeepHealth(ctx context.Context, result *DeepHealthResult) error
+}
+
+// SetHealthBaselineStore sets where deep health check history is kept
+func (m *MCPProtocol) SetHealthBaselineStore(store HealthBaselineStore) {
+	m.baselines = store
+}
+
+// DeepHealthCheck pings the server, lists its tools and resources and times each step.
+// A drop in tool count against the last known count, or any step slower than 3x its
+// historical P95, marks the server degraded. Step failures are reported in the result.
+func (m *MCPProtocol) DeepHealthCheck(ctx context.Context, serverURL string) (*DeepHealthResult, error) {
+	baseline := &HealthBaseline{}
+	if m.baselines != nil {
+		loaded, err := m.baselines.LoadHealthBaseline(ctx, serverURL)
+		if err != nil {
+			m.logger.Warn("Failed to load health baseline", zap.String("server_url", serverURL), zap.Error(err))
+		} else {
+			baseline = loaded
+		}
+	}
+
+	result := &DeepHealthResult{
+		ServerURL:      serverURL,
+		Healthy:        true,
+		KnownToolCount: baseline.KnownToolCount,
+		CheckedAt:      time.Now(),
+	}
+
+	m.runDeepHealthStep(ctx, result, baseline, DeepHealthStepPing, func() error {
+		return m.Ping(ctx, serverURL)
+	})
+
+	toolsOK := m.runDeepHealthStep(ctx, result, baseline, DeepHealthStepListTools, func() error {
+		tools, err := m.ListTools(ctx, serverURL)
+		result.ToolCount = len(tools)
+		return err
+	})
+	if toolsOK && baseline.KnownToolCount != nil && result.ToolCount < *baseline.KnownToolCount {
+		result.ToolCountDropped = true
+		result.Degraded = true
+	}
+
+	m.runDeepHealthStep(ctx, result, baseline, DeepHealthStepListResources, func() error {
+		resources, err := m.ListResources(ctx, serverURL)
+		result.ResourceCount = len(resources)
+		return err
+	})
+
+	if m.baselines != nil {
+		if err := m.baselines.RecordDeepHealth(ctx, result); err != nil {
+			m.logger.Warn("Failed to record deep health check", zap.String("server_url", serverURL), zap.Error(err))
+		}
+	}
+
+	return result, nil
+}
+
+// runDeepHealthStep times a step, appends it to the result and reports whether it succeeded
+func (m *MCPProtocol) runDeepHealthStep(ctx context.Context, result *DeepHealthResult, baseline *HealthBaseline, name string, fn func() error) bool {
+	start := time.Now()
+	err := fn()
+	duration := time.Since(start)
+
+	step := DeepHealthStep{
+		Name:       name,
+		Success:    err == nil,
+		DurationMs: duration.Milliseconds(),
+	}
+	if err != nil {
+		step.Error = err.Error()
+		result.Healthy = false
+	}
+
+	if p95, ok := baseline.StepP95[name]; ok && p95 > 0 {
+		step.P95Ms = p95.Milliseconds()
+		if duration > deepHealthSlowFactor*p95 {
+			step.Slow = true
+			result.Degraded = true
+		}
+	}
+
+	result.Steps = append(result.Steps, step)
+	return err == nil
+}
+
+// dbHealthBaselineStore keeps deep health check history in the database
+type dbHealthBaselineStore struct {
+	db *sql.DB
+}
+
+// NewDBHealthBaselineStore creates a baseline store backed by mcp_servers and
+// server_health_check_steps
+func NewDBHealthBaselineStore(db *sql.DB) HealthBaselineStore {
+	return &dbHealthBaselineStore{db: db}
+}
+
+// LoadHealthBaseline loads the last known tool count and the P95 of each step over the
+// past week. Steps with fewer than 10 successful samples have no P95.
+func (s *dbHealthBaselineStore) LoadHealthBaseline(ctx context.Context, serverURL string) (*HealthBaseline, error) {
+	baseline := &HealthBaseline{
+		StepP95: make(map[string]time.Duration),
+	}
+
+	var knownToolCount sql.NullInt64
+	err := s.db.QueryRowContext(ctx, `
+		SELECT MAX(known_tool_count) FROM mcp_servers WHERE url = $1 AND deleted_at IS NULL
+	`, serverURL).Scan(&knownToolCount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load known tool count: %w", err)
+	}
+	if knownToolCount.Valid {
+		count := int(knownToolCount.Int64)
+		baseline.KnownToolCount = &count
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT step, percentile_cont(0.95) WITHIN GROUP (ORDER BY duration_ms)
+		FROM server_health_check_steps
+		WHERE server_url = $1 AND success = true AND checked_at > NOW() - INTERVAL '7 days'
+		GROUP BY step
+		HAVING COUNT(*) >= 10
+	`, serverURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load step percentiles: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var step string
+		var p95Ms float64
+		if err := rows.Scan(&step, &p95Ms); err != nil {
+			return nil, fmt.Errorf("failed to scan step percentile: %w", err)
+		}
+		baseline.StepP95[step] = time.Duration(p95Ms * float64(time.Millisecond))
+	}
+
+	return baseline, rows.Err()
+}
+
+// RecordDeepHealth stores step timings and, when tools were listed, the current tool count
+func (s *dbHealthBaselineStore) RecordDeepHealth(ctx context.Context, result *DeepHealthResult) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, step := range result.Steps {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO server_health_check_steps (server_url, step, duration_ms, success, checked_at)
+			VALUES ($1, $2, $3, $4, $5)
+		`, result.ServerURL, step.Name, step.DurationMs, step.Success, result.CheckedAt)
+		if err != nil {
+			return fmt.Errorf("failed to record health check step: %w", err)
+		}
+
+		if step.Name == DeepHealthStepListTools && step.Success {
+			_, err := tx.ExecContext(ctx, `
+				UPDATE mcp_servers SET known_tool_count = $2 WHERE url = $1 AND deleted_at IS NULL
+			`, result.ServerURL, result.ToolCount)
+			if err != nil {
+				return fmt.Errorf("failed to update known tool count: %w", err)
+			}
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,81 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"go.uber.org/zap"
+)
+
+// ErrDailyLimitExceeded is returned when an organization has used up its daily tool executions
+var ErrDailyLimitExceeded = errors.New("daily tool execution limit exceeded")
+
+// dailyCounterTTL keeps a day's counter slightly longer than the day itself
+const dailyCounterTTL = 25 * time.Hour
+
+// QuotaCounter atomically increments a counter that expires after ttl
+type QuotaCounter interface {
+	IncrWithExpiry(ctx context.Context, key string, ttl time.Duration) (int64, error)
+}
+
+// SetExecutionQuota enables daily execution limits. counter may be nil, in which case
+// executions are counted in the database.
+func (tm *ToolManager) SetExecutionQuota(counter QuotaCounter, repo *database.Repository) {
+	tm.quotaCounter = counter
+	tm.repo = repo
+}
+
+// checkDailyLimit counts an execution against the owning organization's daily limit.
+// The Redis counter is preferred; if it is unavailable the day's executions are counted
+// in the database instead.
+func (tm *ToolManager) checkDailyLimit(ctx context.Context, serverID uuid.UUID) error {
+	if tm.repo == nil {
+		return nil
+	}
+
+	query := `
+		SELECT o.id, o.daily_execution_limit
+		FROM organizations o
+		JOIN mcp_servers s ON s.organization_id = o.id
+		WHERE s.id = $1
+	`
+
+	var orgID uuid.UUID
+	var limit int
+	if err := tm.db.QueryRowContext(ctx, query, serverID).Scan(&orgID, &limit); err != nil {
+		return fmt.Errorf("failed to load organization limit: %w", err)
+	}
+	if limit <= 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var count int64
+	var err error
+	if tm.quotaCounter != nil {
+		key := fmt.Sprintf("exec:daily:%s:%s", orgID, dayStart.Format("2006-01-02"))
+		count, err = tm.quotaCounter.IncrWithExpiry(ctx, key, dailyCounterTTL)
+		if err != nil {
+			tm.logger.Warn("Quota counter unavailable, counting executions in database", zap.Error(err))
+		}
+	}
+	if tm.quotaCounter == nil || err != nil {
+		count, err = tm.repo.CountToolExecutionsByOrg(ctx, orgID, dayStart, now)
+		if err != nil {
+			return err
+		}
+		count++ // include this execution
+	}
+
+	if count > int64(limit) {
+		return ErrDailyLimitExceeded
+	}
+
+	return nil
+}
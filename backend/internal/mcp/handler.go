@@ -2,9 +2,13 @@ package mcp
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/auth"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/log"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/middleware"
 	"github.com/radhi1991/aran-mcp-sentinel/internal/models"
 	"github.com/radhi1991/aran-mcp-sentinel/internal/repository"
 	"go.uber.org/zap"
@@ -28,10 +32,10 @@ func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
 	serverGroup := router.Group("/servers")
 	{
 		serverGroup.GET("", h.ListServers)
-		serverGroup.GET("/:id", h.GetServer)
+		serverGroup.GET("/:id", middleware.ETagMiddleware(time.Minute), h.GetServer)
 		serverGroup.POST("", h.CreateServer)
 		serverGroup.PUT("/:id", h.UpdateServer)
-		serverGroup.DELETE("/:id", h.DeleteServer)
+		serverGroup.DELETE("/:id", auth.RBACMiddleware(auth.RoleAdmin, h.logger), h.DeleteServer)
 		serverGroup.GET("/:id/status", h.GetServerStatus)
 	}
 
@@ -55,7 +59,7 @@ func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
 func (h *Handler) ListServers(c *gin.Context) {
 	servers, err := h.repo.ListActiveServers(c.Request.Context())
 	if err != nil {
-		h.logger.Error("Failed to list servers", zap.Error(err))
+		log.FromCtx(c.Request.Context()).Error("Failed to list servers", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve servers"})
 		return
 	}
@@ -75,11 +79,18 @@ func (h *Handler) GetServer(c *gin.Context) {
 
 	server, err := h.repo.GetServer(c.Request.Context(), id)
 	if err != nil {
-		h.logger.Error("Failed to get server", zap.Error(err), zap.String("server_id", id.String()))
+		log.FromCtx(c.Request.Context()).Error("Failed to get server", zap.Error(err), zap.String("server_id", id.String()))
 		c.JSON(http.StatusNotFound, gin.H{"error": "Server not found"})
 		return
 	}
 
+	etag, err := middleware.ComputeETag(server, server.UpdatedAt)
+	if err != nil {
+		log.FromCtx(c.Request.Context()).Warn("Failed to compute server ETag", zap.Error(err))
+	} else if middleware.SetETag(c, etag) {
+		return
+	}
+
 	c.JSON(http.StatusOK, server)
 }
 
@@ -92,7 +103,7 @@ func (h *Handler) CreateServer(c *gin.Context) {
 	}
 
 	if err := h.repo.CreateServer(c.Request.Context(), &server); err != nil {
-		h.logger.Error("Failed to create server", zap.Error(err))
+		log.FromCtx(c.Request.Context()).Error("Failed to create server", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create server"})
 		return
 	}
@@ -110,7 +121,7 @@ func (h *Handler) GetServerStatus(c *gin.Context) {
 
 	status, err := h.repo.GetServerStatus(c.Request.Context(), id)
 	if err != nil {
-		h.logger.Error("Failed to get server status",
+		log.FromCtx(c.Request.Context()).Error("Failed to get server status",
 			zap.Error(err),
 			zap.String("server_id", id.String()))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get server status"})
@@ -139,7 +150,7 @@ func (h *Handler) UpdateServer(c *gin.Context) {
 	// Parse server ID
 	id, err := uuid.Parse(serverID)
 	if err != nil {
-		h.logger.Error("Invalid server ID", zap.String("server_id", serverID), zap.Error(err))
+		log.FromCtx(c.Request.Context()).Error("Invalid server ID", zap.String("server_id", serverID), zap.Error(err))
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid server ID"})
 		return
 	}
@@ -155,7 +166,7 @@ func (h *Handler) UpdateServer(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&updateReq); err != nil {
-		h.logger.Error("Failed to bind update request", zap.Error(err))
+		log.FromCtx(c.Request.Context()).Error("Failed to bind update request", zap.Error(err))
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
 		return
 	}
@@ -172,12 +183,12 @@ func (h *Handler) UpdateServer(c *gin.Context) {
 	}
 
 	if err := h.repo.UpdateServer(c.Request.Context(), server); err != nil {
-		h.logger.Error("Failed to update server", zap.String("server_id", serverID), zap.Error(err))
+		log.FromCtx(c.Request.Context()).Error("Failed to update server", zap.String("server_id", serverID), zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update server"})
 		return
 	}
 
-	h.logger.Info("Server updated successfully", zap.String("server_id", serverID))
+	log.FromCtx(c.Request.Context()).Info("Server updated successfully", zap.String("server_id", serverID))
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Server updated successfully",
 		"server":  server,
@@ -191,7 +202,7 @@ func (h *Handler) DeleteServer(c *gin.Context) {
 	// Parse server ID
 	id, err := uuid.Parse(serverID)
 	if err != nil {
-		h.logger.Error("Invalid server ID", zap.String("server_id", serverID), zap.Error(err))
+		log.FromCtx(c.Request.Context()).Error("Invalid server ID", zap.String("server_id", serverID), zap.Error(err))
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid server ID"})
 		return
 	}
@@ -199,19 +210,19 @@ func (h *Handler) DeleteServer(c *gin.Context) {
 	// Check if server exists
 	server, err := h.repo.GetServerByID(id)
 	if err != nil {
-		h.logger.Error("Failed to get server", zap.String("server_id", serverID), zap.Error(err))
+		log.FromCtx(c.Request.Context()).Error("Failed to get server", zap.String("server_id", serverID), zap.Error(err))
 		c.JSON(http.StatusNotFound, gin.H{"error": "Server not found"})
 		return
 	}
 
 	// Delete server from database
 	if err := h.repo.DeleteServer(c.Request.Context(), id); err != nil {
-		h.logger.Error("Failed to delete server", zap.String("server_id", serverID), zap.Error(err))
+		log.FromCtx(c.Request.Context()).Error("Failed to delete server", zap.String("server_id", serverID), zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete server"})
 		return
 	}
 
-	h.logger.Info("Server deleted successfully",
+	log.FromCtx(c.Request.Context()).Info("Server deleted successfully",
 		zap.String("server_id", serverID),
 		zap.String("server_name", server.Name))
 
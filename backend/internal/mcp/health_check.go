@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// Health check methods supported for MCP servers
+const (
+	HealthCheckJSONRPCPing       = "jsonrpc_ping"
+	HealthCheckHTTPGet           = "http_get"
+	HealthCheckJSONRPCInitialize = "jsonrpc_initialize"
+)
+
+// HealthCheckConfig controls how a server's health is checked
+type HealthCheckConfig struct {
+	Method string `json:"method"`
+	Path   string `json:"path,omitempty"` // used by http_get
+}
+
+// Validate checks that the health check method is supported
+func (c HealthCheckConfig) Validate() error {
+	switch c.Method {
+	case "", HealthCheckJSONRPCPing, HealthCheckJSONRPCInitialize:
+		return nil
+	case HealthCheckHTTPGet:
+		if c.Path == "" {
+			return fmt.Errorf("path is required for %s health checks", HealthCheckHTTPGet)
+		}
+		if _, err := url.Parse(c.Path); err != nil {
+			return fmt.Errorf("invalid health check path: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported health check method: %s", c.Method)
+	}
+}
+
+// HealthCheck checks whether an MCP server is healthy using the configured method.
+// An empty method defaults to a JSON-RPC ping.
+func (m *MCPProtocol) HealthCheck(ctx context.Context, serverURL string, config HealthCheckConfig) error {
+	switch config.Method {
+	case "", HealthCheckJSONRPCPing:
+		return m.Ping(ctx, serverURL)
+	case HealthCheckJSONRPCInitialize:
+		if _, err := m.Initialize(ctx, serverURL); err != nil {
+			return fmt.Errorf("initialize health check failed: %w", err)
+		}
+		return nil
+	case HealthCheckHTTPGet:
+		return m.httpHealthCheck(ctx, serverURL, config.Path)
+	default:
+		return fmt.Errorf("unsupported health check method: %s", config.Method)
+	}
+}
+
+// httpHealthCheck sends a GET request to path on the server and accepts any 2xx response
+func (m *MCPProtocol) httpHealthCheck(ctx context.Context, serverURL, path string) error {
+	base, err := url.Parse(serverURL)
+	if err != nil {
+		return fmt.Errorf("invalid server URL: %w", err)
+	}
+	ref, err := url.Parse(path)
+	if err != nil {
+		return fmt.Errorf("invalid health check path: %w", err)
+	}
+	target := base.ResolveReference(ref).String()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	httpReq.Header.Set("User-Agent", "Aran-MCP-Sentinel/1.0.0")
+
+	resp, err := m.pool.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("HTTP health check failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("HTTP health check failed with status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// mockMethod answers one JSON-RPC method of a mock MCP server. Returning a non-nil
+// MCPError sends it as the JSON-RPC error.
+type mockMethod func(t *testing.T, params json.RawMessage) (interface{}, *MCPError)
+
+// mockMCPServer is an MCP server answering JSON-RPC over HTTP from fixed handlers
+type mockMCPServer struct {
+	*httptest.Server
+
+	mu    sync.Mutex
+	calls map[string][]json.RawMessage
+}
+
+// newMockMCPServer starts a mock MCP server. Methods without a handler are answered
+// with a method-not-found error.
+func newMockMCPServer(t *testing.T, methods map[string]mockMethod) *mockMCPServer {
+	t.Helper()
+
+	m := &mockMCPServer{calls: make(map[string][]json.RawMessage)}
+	m.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			JSONRPC string          `json:"jsonrpc"`
+			ID      interface{}     `json:"id"`
+			Method  string          `json:"method"`
+			Params  json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("mock MCP server: invalid request body: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if req.JSONRPC != "2.0" {
+			t.Errorf("mock MCP server: jsonrpc = %q, want 2.0", req.JSONRPC)
+		}
+
+		m.mu.Lock()
+		m.calls[req.Method] = append(m.calls[req.Method], req.Params)
+		m.mu.Unlock()
+
+		resp := MCPResponse{JSONRPC: "2.0", ID: req.ID}
+		if handler, ok := methods[req.Method]; ok {
+			resp.Result, resp.Error = handler(t, req.Params)
+		} else {
+			resp.Error = &MCPError{Code: -32601, Message: "method not found"}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(m.Close)
+
+	return m
+}
+
+// Calls returns the params of every request received for method
+func (m *mockMCPServer) Calls(method string) []json.RawMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]json.RawMessage(nil), m.calls[method]...)
+}
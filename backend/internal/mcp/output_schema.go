@@ -0,0 +1,158 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/google/uuid"
+)
+
+// ResultSchema returns the tool's output schema, read from outputSchema or the older returns field
+func (t MCPTool) ResultSchema() map[string]interface{} {
+	if len(t.OutputSchema) > 0 {
+		return t.OutputSchema
+	}
+	return t.Returns
+}
+
+// ValidateOutput checks a tool result against the tool's output schema. Tools without an
+// output schema accept any result.
+func (tm *ToolManager) ValidateOutput(toolID uuid.UUID, result interface{}) error {
+	tool, err := tm.GetTool(nil, toolID)
+	if err != nil {
+		return fmt.Errorf("tool not found: %w", err)
+	}
+
+	return validateToolOutput(tool, result)
+}
+
+// validateToolOutput checks a tool result against its output schema. MCP servers return
+// structured output in structuredContent; otherwise the whole result is validated.
+func validateToolOutput(tool *ManagedTool, result interface{}) error {
+	if len(tool.OutputSchema) == 0 {
+		return nil
+	}
+
+	value := result
+	if fields, ok := result.(map[string]interface{}); ok {
+		if structured, exists := fields["structuredContent"]; exists {
+			value = structured
+		}
+	}
+
+	return validateSchemaValue(value, tool.OutputSchema, "result")
+}
+
+// validateSchemaValue validates a decoded JSON value against a subset of JSON Schema:
+// type, enum, properties, required, additionalProperties and items
+func validateSchemaValue(value interface{}, schema map[string]interface{}, path string) error {
+	if schemaType, ok := schema["type"]; ok {
+		if !matchesSchemaType(value, schemaType) {
+			return fmt.Errorf("%s: expected type %v", path, schemaType)
+		}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, allowed := range enum {
+			if jsonEqual(value, allowed) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: value is not one of the allowed values", path)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, name := range required {
+				if field, ok := name.(string); ok {
+					if _, exists := v[field]; !exists {
+						return fmt.Errorf("%s: missing required field %s", path, field)
+					}
+				}
+			}
+		}
+
+		for field, fieldValue := range v {
+			fieldSchema, ok := properties[field].(map[string]interface{})
+			if !ok {
+				if additional, ok := schema["additionalProperties"].(bool); ok && !additional {
+					return fmt.Errorf("%s: unexpected field %s", path, field)
+				}
+				continue
+			}
+			if err := validateSchemaValue(fieldValue, fieldSchema, path+"."+field); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				if err := validateSchemaValue(item, items, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// matchesSchemaType reports whether value has the JSON Schema type, which may be a name or a list of names
+func matchesSchemaType(value interface{}, schemaType interface{}) bool {
+	switch t := schemaType.(type) {
+	case string:
+		return matchesTypeName(value, t)
+	case []interface{}:
+		for _, name := range t {
+			if s, ok := name.(string); ok && matchesTypeName(value, s) {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// matchesTypeName reports whether value has a single JSON Schema type
+func matchesTypeName(value interface{}, name string) bool {
+	switch name {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	default:
+		return true
+	}
+}
+
+// jsonEqual compares two decoded JSON values
+func jsonEqual(a, b interface{}) bool {
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(aJSON) == string(bJSON)
+}
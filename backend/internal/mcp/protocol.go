@@ -4,18 +4,62 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
+	apperrors "github.com/radhi1991/aran-mcp-sentinel/internal/errors"
 	"go.uber.org/zap"
 )
 
 // MCPProtocol implements the Model Context Protocol specification
 type MCPProtocol struct {
 	logger *zap.Logger
-	client *http.Client
+	pool   *ConnectionPool
+
+	// MaxPages limits how many pages are fetched from paginated list methods
+	MaxPages int
+
+	baselines HealthBaselineStore
+
+	// transport is how tool calls are sent; see TransportHTTP and TransportSSE
+	transport string
+
+	// SupportedProtocolVersions are the MCP protocol versions the client accepts, most
+	// preferred first. The first is the version requested during initialization.
+	SupportedProtocolVersions []string
+
+	// serverTLS holds the TLS files configured for each server URL
+	serverTLS sync.Map // string -> ServerTLSFiles
+}
+
+// DefaultMaxPages is the default page limit for paginated list methods
+const DefaultMaxPages = 100
+
+// ErrPageLimitReached is returned by paginated list methods when the server still
+// reports a next cursor after MaxPages pages
+var ErrPageLimitReached = errors.New("page limit reached")
+
+// DefaultProtocolVersions are the MCP protocol versions supported by default
+var DefaultProtocolVersions = []string{"2024-11-05", "2025-03-26", "2025-06-18"}
+
+// VersionMismatchError is returned by Initialize when a server answers with a protocol
+// version the client does not support
+type VersionMismatchError struct {
+	ServerURL         string   `json:"server_url"`
+	ServerVersion     string   `json:"server_version"`
+	SupportedVersions []string `json:"supported_versions"`
+}
+
+func (e *VersionMismatchError) Error() string {
+	return fmt.Sprintf("MCP server %s uses unsupported protocol version %q (supported: %s)",
+		e.ServerURL, e.ServerVersion, strings.Join(e.SupportedVersions, ", "))
 }
 
 // MCPRequest represents a standard MCP request
@@ -26,12 +70,15 @@ type MCPRequest struct {
 	Params  interface{} `json:"params,omitempty"`
 }
 
-// MCPResponse represents a standard MCP response
+// MCPResponse represents a standard MCP response. Messages streamed over SSE may also
+// be notifications, which have a method and params instead of a result.
 type MCPResponse struct {
 	JSONRPC string      `json:"jsonrpc"`
 	ID      interface{} `json:"id"`
 	Result  interface{} `json:"result,omitempty"`
 	Error   *MCPError   `json:"error,omitempty"`
+	Method  string      `json:"method,omitempty"`
+	Params  interface{} `json:"params,omitempty"`
 }
 
 // MCPError represents an MCP error
@@ -41,6 +88,11 @@ type MCPError struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
+// Err returns the JSON-RPC error as a ProtocolError
+func (e *MCPError) Err() error {
+	return &apperrors.ProtocolError{Code: e.Code, Message: e.Message}
+}
+
 // MCPServerInfo represents server information
 type MCPServerInfo struct {
 	Name         string            `json:"name"`
@@ -48,6 +100,9 @@ type MCPServerInfo struct {
 	Description  string            `json:"description,omitempty"`
 	Capabilities MCPCapabilities   `json:"capabilities"`
 	Metadata     map[string]string `json:"metadata,omitempty"`
+
+	// ProtocolVersion is the MCP protocol version negotiated during initialization
+	ProtocolVersion string `json:"protocolVersion"`
 }
 
 // MCPCapabilities represents server capabilities
@@ -84,6 +139,10 @@ type MCPTool struct {
 	Name        string                 `json:"name"`
 	Description string                 `json:"description,omitempty"`
 	InputSchema map[string]interface{} `json:"inputSchema"`
+
+	// Output schema of the tool's result; older servers use returns
+	OutputSchema map[string]interface{} `json:"outputSchema,omitempty"`
+	Returns      map[string]interface{} `json:"returns,omitempty"`
 }
 
 // MCPResource represents an MCP resource
@@ -94,6 +153,23 @@ type MCPResource struct {
 	MimeType    string `json:"mimeType,omitempty"`
 }
 
+// ResourceContent represents the contents of a resource read from an MCP server.
+// Text resources set Text and binary resources set Blob, which is base64 encoded on the wire.
+type ResourceContent struct {
+	URI      string `json:"uri"`
+	MIMEType string `json:"mimeType,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Blob     []byte `json:"blob,omitempty"`
+
+	// Structured holds the parsed Text of JSON resources
+	Structured interface{} `json:"structured,omitempty"`
+}
+
+// IsBinary reports whether the content is a binary resource
+func (rc *ResourceContent) IsBinary() bool {
+	return rc.Blob != nil
+}
+
 // MCPPrompt represents an MCP prompt
 type MCPPrompt struct {
 	Name        string                   `json:"name"`
@@ -108,24 +184,92 @@ type MCPPromptArgument struct {
 	Required    bool   `json:"required,omitempty"`
 }
 
+// MCPPromptResult is a prompt rendered by an MCP server
+type MCPPromptResult struct {
+	Description string       `json:"description,omitempty"`
+	Messages    []MCPMessage `json:"messages"`
+}
+
+// MCPMessage is one message of a rendered prompt
+type MCPMessage struct {
+	Role    string     `json:"role"` // user or assistant
+	Content MCPContent `json:"content"`
+}
+
+// MCPContent is the content of a prompt message. Text content sets Text, image and audio
+// content set Data (base64 encoded) and MimeType, and embedded resources set Resource.
+type MCPContent struct {
+	Type     string           `json:"type"`
+	Text     string           `json:"text,omitempty"`
+	Data     string           `json:"data,omitempty"`
+	MimeType string           `json:"mimeType,omitempty"`
+	Resource *ResourceContent `json:"resource,omitempty"`
+}
+
 // NewMCPProtocol creates a new MCP protocol client
 func NewMCPProtocol(logger *zap.Logger) *MCPProtocol {
 	return &MCPProtocol{
 		logger: logger,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		pool:   NewConnectionPool(MaxConnsPerMCPServer, 30*time.Second),
+
+		MaxPages:  DefaultMaxPages,
+		transport: TransportHTTP,
+
+		SupportedProtocolVersions: DefaultProtocolVersions,
+	}
+}
+
+// NewMCPProtocolWithTransport creates a new MCP protocol client that keeps up to
+// maxIdleConnsPerHost keep-alive connections open per server and fails requests whose
+// response headers take longer than responseHeaderTimeout seconds. Zero uses the
+// defaults of NewMCPProtocol.
+func NewMCPProtocolWithTransport(logger *zap.Logger, maxIdleConnsPerHost, responseHeaderTimeout int) *MCPProtocol {
+	m := NewMCPProtocol(logger)
+	m.pool = NewConnectionPoolWithTransport(MaxConnsPerMCPServer, 30*time.Second, TransportConfig{
+		MaxIdleConnsPerHost:   maxIdleConnsPerHost,
+		ResponseHeaderTimeout: time.Duration(responseHeaderTimeout) * time.Second,
+	})
+	return m
+}
+
+// NewMCPProtocolSSE creates an MCP protocol client that calls tools over the SSE
+// streaming transport
+func NewMCPProtocolSSE(logger *zap.Logger) *MCPProtocol {
+	m := NewMCPProtocol(logger)
+	m.transport = TransportSSE
+	return m
+}
+
+// WarmConnections pre-establishes keep-alive connections to an MCP server
+func (m *MCPProtocol) WarmConnections(ctx context.Context, serverURL string, count int) error {
+	return m.pool.Warm(ctx, serverURL, count)
+}
+
+// ConnectionPoolStats returns per-server connection pool statistics
+func (m *MCPProtocol) ConnectionPoolStats() []ConnectionPoolStats {
+	return m.pool.Stats()
+}
+
+// supportedProtocolVersions returns the configured versions, or the defaults if none are set
+func (m *MCPProtocol) supportedProtocolVersions() []string {
+	if len(m.SupportedProtocolVersions) == 0 {
+		return DefaultProtocolVersions
 	}
+	return m.SupportedProtocolVersions
 }
 
-// Initialize performs MCP server initialization handshake
+// Initialize performs MCP server initialization handshake. It requests the most preferred
+// supported protocol version and returns a *VersionMismatchError if the server answers
+// with a version the client does not support.
 func (m *MCPProtocol) Initialize(ctx context.Context, serverURL string) (*MCPServerInfo, error) {
+	supported := m.supportedProtocolVersions()
+
 	request := MCPRequest{
 		JSONRPC: "2.0",
 		ID:      1,
 		Method:  "initialize",
 		Params: map[string]interface{}{
-			"protocolVersion": "2024-11-05",
+			"protocolVersion": supported[0],
 			"capabilities": map[string]interface{}{
 				"roots": map[string]interface{}{
 					"listChanged": true,
@@ -145,14 +289,27 @@ func (m *MCPProtocol) Initialize(ctx context.Context, serverURL string) (*MCPSer
 	}
 
 	if response.Error != nil {
-		return nil, fmt.Errorf("MCP server returned error: %s", response.Error.Message)
+		return nil, response.Error.Err()
 	}
 
 	var serverInfo MCPServerInfo
-	if err := json.Unmarshal([]byte(fmt.Sprintf("%v", response.Result)), &serverInfo); err != nil {
+	resultBytes, _ := json.Marshal(response.Result)
+	if err := json.Unmarshal(resultBytes, &serverInfo); err != nil {
 		return nil, fmt.Errorf("failed to parse server info: %w", err)
 	}
 
+	// Servers predating version negotiation do not report a version; assume the requested one
+	if serverInfo.ProtocolVersion == "" {
+		serverInfo.ProtocolVersion = supported[0]
+	}
+	if !slices.Contains(supported, serverInfo.ProtocolVersion) {
+		return nil, &VersionMismatchError{
+			ServerURL:         serverURL,
+			ServerVersion:     serverInfo.ProtocolVersion,
+			SupportedVersions: supported,
+		}
+	}
+
 	// Send initialized notification
 	notification := MCPRequest{
 		JSONRPC: "2.0",
@@ -167,33 +324,57 @@ func (m *MCPProtocol) Initialize(ctx context.Context, serverURL string) (*MCPSer
 	return &serverInfo, nil
 }
 
-// ListTools retrieves available tools from MCP server
+// ListTools retrieves available tools from MCP server, following nextCursor across pages
 func (m *MCPProtocol) ListTools(ctx context.Context, serverURL string) ([]MCPTool, error) {
-	request := MCPRequest{
-		JSONRPC: "2.0",
-		ID:      2,
-		Method:  "tools/list",
-	}
-
-	response, err := m.sendRequest(ctx, serverURL, request)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list tools: %w", err)
-	}
-
-	if response.Error != nil {
-		return nil, fmt.Errorf("MCP server returned error: %s", response.Error.Message)
-	}
-
-	var result struct {
-		Tools []MCPTool `json:"tools"`
-	}
-
-	resultBytes, _ := json.Marshal(response.Result)
-	if err := json.Unmarshal(resultBytes, &result); err != nil {
-		return nil, fmt.Errorf("failed to parse tools: %w", err)
-	}
-
-	return result.Tools, nil
+	maxPages := m.MaxPages
+	if maxPages <= 0 {
+		maxPages = DefaultMaxPages
+	}
+
+	var tools []MCPTool
+	cursor := ""
+
+	for page := 0; page < maxPages; page++ {
+		request := MCPRequest{
+			JSONRPC: "2.0",
+			ID:      2,
+			Method:  "tools/list",
+		}
+		if cursor != "" {
+			request.Params = map[string]interface{}{
+				"cursor": cursor,
+			}
+		}
+
+		response, err := m.sendRequest(ctx, serverURL, request)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tools: %w", err)
+		}
+
+		if response.Error != nil {
+			return nil, response.Error.Err()
+		}
+
+		var result struct {
+			Tools      []MCPTool `json:"tools"`
+			NextCursor string    `json:"nextCursor,omitempty"`
+		}
+
+		resultBytes, _ := json.Marshal(response.Result)
+		if err := json.Unmarshal(resultBytes, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse tools: %w", err)
+		}
+
+		tools = append(tools, result.Tools...)
+
+		if result.NextCursor == "" {
+			return tools, nil
+		}
+		cursor = result.NextCursor
+	}
+
+	// Returning the pages fetched so far would let callers sync a partial tool list
+	return nil, fmt.Errorf("%w: tools/list still had more tools after %d pages", ErrPageLimitReached, maxPages)
 }
 
 // ListResources retrieves available resources from MCP server
@@ -210,7 +391,7 @@ func (m *MCPProtocol) ListResources(ctx context.Context, serverURL string) ([]MC
 	}
 
 	if response.Error != nil {
-		return nil, fmt.Errorf("MCP server returned error: %s", response.Error.Message)
+		return nil, response.Error.Err()
 	}
 
 	var result struct {
@@ -239,7 +420,7 @@ func (m *MCPProtocol) ListPrompts(ctx context.Context, serverURL string) ([]MCPP
 	}
 
 	if response.Error != nil {
-		return nil, fmt.Errorf("MCP server returned error: %s", response.Error.Message)
+		return nil, response.Error.Err()
 	}
 
 	var result struct {
@@ -254,16 +435,46 @@ func (m *MCPProtocol) ListPrompts(ctx context.Context, serverURL string) ([]MCPP
 	return result.Prompts, nil
 }
 
-// CallTool executes a tool on the MCP server
-func (m *MCPProtocol) CallTool(ctx context.Context, serverURL, toolName string, arguments map[string]interface{}) (interface{}, error) {
+// GetPrompt renders a prompt on the MCP server with the given argument values
+func (m *MCPProtocol) GetPrompt(ctx context.Context, serverURL, promptName string, arguments map[string]string) (*MCPPromptResult, error) {
+	params := map[string]interface{}{
+		"name": promptName,
+	}
+	if len(arguments) > 0 {
+		params["arguments"] = arguments
+	}
+
 	request := MCPRequest{
 		JSONRPC: "2.0",
-		ID:      5,
-		Method:  "tools/call",
-		Params: map[string]interface{}{
-			"name":      toolName,
-			"arguments": arguments,
-		},
+		ID:      7,
+		Method:  "prompts/get",
+		Params:  params,
+	}
+
+	response, err := m.sendRequest(ctx, serverURL, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt: %w", err)
+	}
+
+	if response.Error != nil {
+		return nil, response.Error.Err()
+	}
+
+	var result MCPPromptResult
+	resultBytes, _ := json.Marshal(response.Result)
+	if err := json.Unmarshal(resultBytes, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse prompt: %w", err)
+	}
+
+	return &result, nil
+}
+
+// CallTool executes a tool on the MCP server
+func (m *MCPProtocol) CallTool(ctx context.Context, serverURL, toolName string, arguments map[string]interface{}) (interface{}, error) {
+	request := toolCallRequest(toolName, arguments)
+
+	if m.transport == TransportSSE {
+		return m.callToolStreamed(ctx, serverURL, request)
 	}
 
 	response, err := m.sendRequest(ctx, serverURL, request)
@@ -272,14 +483,27 @@ func (m *MCPProtocol) CallTool(ctx context.Context, serverURL, toolName string,
 	}
 
 	if response.Error != nil {
-		return nil, fmt.Errorf("tool execution failed: %s", response.Error.Message)
+		return nil, fmt.Errorf("tool execution failed: %w", response.Error.Err())
 	}
 
 	return response.Result, nil
 }
 
+// toolCallRequest builds a tools/call request
+func toolCallRequest(toolName string, arguments map[string]interface{}) MCPRequest {
+	return MCPRequest{
+		JSONRPC: "2.0",
+		ID:      5,
+		Method:  "tools/call",
+		Params: map[string]interface{}{
+			"name":      toolName,
+			"arguments": arguments,
+		},
+	}
+}
+
 // ReadResource reads a resource from the MCP server
-func (m *MCPProtocol) ReadResource(ctx context.Context, serverURL, resourceURI string) (interface{}, error) {
+func (m *MCPProtocol) ReadResource(ctx context.Context, serverURL, resourceURI string) ([]ResourceContent, error) {
 	request := MCPRequest{
 		JSONRPC: "2.0",
 		ID:      6,
@@ -295,10 +519,42 @@ func (m *MCPProtocol) ReadResource(ctx context.Context, serverURL, resourceURI s
 	}
 
 	if response.Error != nil {
-		return nil, fmt.Errorf("resource read failed: %s", response.Error.Message)
+		return nil, fmt.Errorf("resource read failed: %w", response.Error.Err())
 	}
 
-	return response.Result, nil
+	var result struct {
+		Contents []ResourceContent `json:"contents"`
+	}
+
+	resultBytes, _ := json.Marshal(response.Result)
+	if err := json.Unmarshal(resultBytes, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse resource contents: %w", err)
+	}
+
+	for i := range result.Contents {
+		content := &result.Contents[i]
+		if content.IsBinary() || !isJSONMIMEType(content.MIMEType) {
+			continue
+		}
+		if err := json.Unmarshal([]byte(content.Text), &content.Structured); err != nil {
+			m.logger.Warn("Failed to parse JSON resource",
+				zap.String("uri", content.URI),
+				zap.Error(err),
+			)
+		}
+	}
+
+	return result.Contents, nil
+}
+
+// isJSONMIMEType reports whether a MIME type describes JSON content
+func isJSONMIMEType(mimeType string) bool {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
 }
 
 // Ping checks if MCP server is responsive
@@ -315,12 +571,83 @@ func (m *MCPProtocol) Ping(ctx context.Context, serverURL string) error {
 	}
 
 	if response.Error != nil {
-		return fmt.Errorf("ping returned error: %s", response.Error.Message)
+		return fmt.Errorf("ping returned error: %w", response.Error.Err())
 	}
 
 	return nil
 }
 
+// BatchRequest sends several requests to the MCP server in a single JSON-RPC batch
+// and returns the responses in request order. Servers that do not support
+// batching are sent the requests one at a time instead.
+func (m *MCPProtocol) BatchRequest(ctx context.Context, serverURL string, requests []MCPRequest) ([]MCPResponse, error) {
+	if len(requests) == 0 {
+		return nil, nil
+	}
+
+	requestBody, err := json.Marshal(requests)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal batch request: %w", err)
+	}
+
+	m.logger.Debug("Sending MCP batch request",
+		zap.String("url", serverURL),
+		zap.Int("requests", len(requests)),
+	)
+
+	responseBody, err := m.post(ctx, serverURL, requestBody)
+	if err != nil {
+		return nil, err
+	}
+
+	var batchResponses []MCPResponse
+	if err := json.Unmarshal(responseBody, &batchResponses); err != nil {
+		m.logger.Debug("MCP server does not support batching, sending requests sequentially",
+			zap.String("url", serverURL),
+		)
+		return m.sendSequential(ctx, serverURL, requests)
+	}
+
+	byID := make(map[string]MCPResponse, len(batchResponses))
+	for _, response := range batchResponses {
+		byID[requestIDKey(response.ID)] = response
+	}
+
+	responses := make([]MCPResponse, len(requests))
+	for i, request := range requests {
+		response, exists := byID[requestIDKey(request.ID)]
+		if !exists {
+			return nil, fmt.Errorf("missing batch response for request %v (%s)", request.ID, request.Method)
+		}
+		responses[i] = response
+	}
+
+	return responses, nil
+}
+
+// sendSequential sends requests one at a time and collects their responses
+func (m *MCPProtocol) sendSequential(ctx context.Context, serverURL string, requests []MCPRequest) ([]MCPResponse, error) {
+	responses := make([]MCPResponse, 0, len(requests))
+	for _, request := range requests {
+		response, err := m.sendRequest(ctx, serverURL, request)
+		if err != nil {
+			return nil, fmt.Errorf("%s request failed: %w", request.Method, err)
+		}
+		responses = append(responses, *response)
+	}
+
+	return responses, nil
+}
+
+// requestIDKey normalizes a JSON-RPC id so request and response ids can be compared
+func requestIDKey(id interface{}) string {
+	key, err := json.Marshal(id)
+	if err != nil {
+		return fmt.Sprint(id)
+	}
+	return string(key)
+}
+
 // sendRequest sends an HTTP request to the MCP server
 func (m *MCPProtocol) sendRequest(ctx context.Context, serverURL string, request MCPRequest) (*MCPResponse, error) {
 	requestBody, err := json.Marshal(request)
@@ -334,7 +661,22 @@ func (m *MCPProtocol) sendRequest(ctx context.Context, serverURL string, request
 		zap.ByteString("body", requestBody),
 	)
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", serverURL, bytes.NewBuffer(requestBody))
+	responseBody, err := m.post(ctx, serverURL, requestBody)
+	if err != nil {
+		return nil, err
+	}
+
+	var mcpResponse MCPResponse
+	if err := json.Unmarshal(responseBody, &mcpResponse); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &mcpResponse, nil
+}
+
+// post sends a JSON body to the MCP server and returns the raw response body
+func (m *MCPProtocol) post(ctx context.Context, serverURL string, body []byte) ([]byte, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", serverURL, bytes.NewBuffer(body))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
@@ -342,14 +684,14 @@ func (m *MCPProtocol) sendRequest(ctx context.Context, serverURL string, request
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("User-Agent", "Aran-MCP-Sentinel/1.0.0")
 
-	resp, err := m.client.Do(httpReq)
+	resp, err := m.pool.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("HTTP request failed: %w", err)
+		return nil, &apperrors.ConnectionError{URL: serverURL, Cause: err}
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP request failed with status: %d", resp.StatusCode)
+		return nil, &apperrors.ConnectionError{URL: serverURL, Cause: fmt.Errorf("HTTP request failed with status: %d", resp.StatusCode)}
 	}
 
 	responseBody, err := io.ReadAll(resp.Body)
@@ -363,10 +705,5 @@ func (m *MCPProtocol) sendRequest(ctx context.Context, serverURL string, request
 		zap.ByteString("body", responseBody),
 	)
 
-	var mcpResponse MCPResponse
-	if err := json.Unmarshal(responseBody, &mcpResponse); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-	}
-
-	return &mcpResponse, nil
-}
\ No newline at end of file
+	return responseBody, nil
+}
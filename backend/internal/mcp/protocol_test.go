@@ -0,0 +1,195 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	apperrors "github.com/radhi1991/aran-mcp-sentinel/internal/errors"
+	"go.uber.org/zap"
+)
+
+// pagedToolsList serves tools/list as pages of two tools linked by cursors page-1, page-2, ...
+func pagedToolsList(pages int) mockMethod {
+	return func(t *testing.T, params json.RawMessage) (interface{}, *MCPError) {
+		var p struct {
+			Cursor string `json:"cursor"`
+		}
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &p); err != nil {
+				t.Errorf("tools/list: invalid params: %v", err)
+			}
+		}
+
+		page := 0
+		if p.Cursor != "" {
+			if _, err := fmt.Sscanf(p.Cursor, "page-%d", &page); err != nil {
+				return nil, &MCPError{Code: -32602, Message: "invalid cursor"}
+			}
+		}
+
+		result := map[string]interface{}{
+			"tools": []MCPTool{
+				{Name: fmt.Sprintf("tool-%d-a", page)},
+				{Name: fmt.Sprintf("tool-%d-b", page)},
+			},
+		}
+		if page+1 < pages {
+			result["nextCursor"] = fmt.Sprintf("page-%d", page+1)
+		}
+		return result, nil
+	}
+}
+
+func TestListToolsFollowsCursors(t *testing.T) {
+	server := newMockMCPServer(t, map[string]mockMethod{"tools/list": pagedToolsList(3)})
+	protocol := NewMCPProtocol(zap.NewNop())
+
+	tools, err := protocol.ListTools(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("ListTools() error = %v", err)
+	}
+
+	want := []string{"tool-0-a", "tool-0-b", "tool-1-a", "tool-1-b", "tool-2-a", "tool-2-b"}
+	if len(tools) != len(want) {
+		t.Fatalf("ListTools() returned %d tools, want %d", len(tools), len(want))
+	}
+	for i, tool := range tools {
+		if tool.Name != want[i] {
+			t.Errorf("tools[%d] = %q, want %q", i, tool.Name, want[i])
+		}
+	}
+
+	calls := server.Calls("tools/list")
+	if len(calls) != 3 {
+		t.Fatalf("server received %d tools/list requests, want 3", len(calls))
+	}
+	if len(calls[0]) != 0 {
+		t.Errorf("first request params = %s, want none", calls[0])
+	}
+	if string(calls[2]) != `{"cursor":"page-2"}` {
+		t.Errorf("third request params = %s, want the page-2 cursor", calls[2])
+	}
+}
+
+func TestListToolsFailsAtPageLimit(t *testing.T) {
+	server := newMockMCPServer(t, map[string]mockMethod{"tools/list": pagedToolsList(3)})
+	protocol := NewMCPProtocol(zap.NewNop())
+	protocol.MaxPages = 2
+
+	tools, err := protocol.ListTools(context.Background(), server.URL)
+	if !errors.Is(err, ErrPageLimitReached) {
+		t.Fatalf("ListTools() error = %v, want ErrPageLimitReached", err)
+	}
+	if tools != nil {
+		t.Errorf("ListTools() returned %d tools with the error, want none", len(tools))
+	}
+}
+
+func TestGetPrompt(t *testing.T) {
+	server := newMockMCPServer(t, map[string]mockMethod{
+		"prompts/get": func(t *testing.T, params json.RawMessage) (interface{}, *MCPError) {
+			var p struct {
+				Name      string            `json:"name"`
+				Arguments map[string]string `json:"arguments"`
+			}
+			if err := json.Unmarshal(params, &p); err != nil {
+				t.Errorf("prompts/get: invalid params: %v", err)
+			}
+			if p.Name != "code_review" {
+				return nil, &MCPError{Code: -32602, Message: "unknown prompt " + p.Name}
+			}
+			return map[string]interface{}{
+				"description": "Review a change",
+				"messages": []map[string]interface{}{
+					{"role": "user", "content": map[string]interface{}{
+						"type": "text",
+						"text": "Review this " + p.Arguments["language"] + " code",
+					}},
+					{"role": "user", "content": map[string]interface{}{
+						"type":     "resource",
+						"resource": map[string]interface{}{"uri": "file:///main.go", "mimeType": "text/x-go", "text": "package main"},
+					}},
+					{"role": "assistant", "content": map[string]interface{}{
+						"type": "image", "data": "iVBORw0KGgo=", "mimeType": "image/png",
+					}},
+				},
+			}, nil
+		},
+	})
+	protocol := NewMCPProtocol(zap.NewNop())
+
+	prompt, err := protocol.GetPrompt(context.Background(), server.URL, "code_review", map[string]string{"language": "Go"})
+	if err != nil {
+		t.Fatalf("GetPrompt() error = %v", err)
+	}
+
+	if prompt.Description != "Review a change" {
+		t.Errorf("description = %q, want %q", prompt.Description, "Review a change")
+	}
+	if len(prompt.Messages) != 3 {
+		t.Fatalf("GetPrompt() returned %d messages, want 3", len(prompt.Messages))
+	}
+	if got := prompt.Messages[0]; got.Role != "user" || got.Content.Type != "text" || got.Content.Text != "Review this Go code" {
+		t.Errorf("messages[0] = %+v, want the rendered text", got)
+	}
+	if got := prompt.Messages[1].Content.Resource; got == nil || got.URI != "file:///main.go" || got.Text != "package main" {
+		t.Errorf("messages[1] resource = %+v, want file:///main.go", got)
+	}
+	if got := prompt.Messages[2]; got.Role != "assistant" || got.Content.Data != "iVBORw0KGgo=" || got.Content.MimeType != "image/png" {
+		t.Errorf("messages[2] = %+v, want the image", got)
+	}
+
+	calls := server.Calls("prompts/get")
+	if len(calls) != 1 {
+		t.Fatalf("server received %d prompts/get calls, want 1", len(calls))
+	}
+	var sent map[string]interface{}
+	if err := json.Unmarshal(calls[0], &sent); err != nil {
+		t.Fatalf("invalid prompts/get params: %v", err)
+	}
+	if sent["name"] != "code_review" || sent["arguments"].(map[string]interface{})["language"] != "Go" {
+		t.Errorf("prompts/get params = %s, want the name and arguments", calls[0])
+	}
+}
+
+func TestGetPromptWithoutArguments(t *testing.T) {
+	server := newMockMCPServer(t, map[string]mockMethod{
+		"prompts/get": func(t *testing.T, params json.RawMessage) (interface{}, *MCPError) {
+			return map[string]interface{}{"messages": []interface{}{}}, nil
+		},
+	})
+	protocol := NewMCPProtocol(zap.NewNop())
+
+	if _, err := protocol.GetPrompt(context.Background(), server.URL, "greeting", nil); err != nil {
+		t.Fatalf("GetPrompt() error = %v", err)
+	}
+
+	var sent map[string]interface{}
+	if err := json.Unmarshal(server.Calls("prompts/get")[0], &sent); err != nil {
+		t.Fatalf("invalid prompts/get params: %v", err)
+	}
+	if _, ok := sent["arguments"]; ok {
+		t.Errorf("prompts/get params = %v, want no arguments", sent)
+	}
+}
+
+func TestGetPromptError(t *testing.T) {
+	server := newMockMCPServer(t, map[string]mockMethod{
+		"prompts/get": func(t *testing.T, params json.RawMessage) (interface{}, *MCPError) {
+			return nil, &MCPError{Code: -32602, Message: "unknown prompt"}
+		},
+	})
+	protocol := NewMCPProtocol(zap.NewNop())
+
+	prompt, err := protocol.GetPrompt(context.Background(), server.URL, "missing", nil)
+	var protocolErr *apperrors.ProtocolError
+	if !errors.As(err, &protocolErr) || protocolErr.Code != -32602 {
+		t.Fatalf("GetPrompt() error = %v, want protocol error -32602", err)
+	}
+	if prompt != nil {
+		t.Errorf("GetPrompt() returned %+v with the error, want nil", prompt)
+	}
+}
@@ -0,0 +1,214 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"sync"
+	"time"
+
+	apperrors "github.com/radhi1991/aran-mcp-sentinel/internal/errors"
+	"go.uber.org/zap"
+)
+
+// resourceUpdatedMethod is the notification a server sends when a subscribed resource changes
+const resourceUpdatedMethod = "notifications/resources/updated"
+
+// unsubscribeTimeout bounds the resources/unsubscribe request sent when a subscription ends
+const unsubscribeTimeout = 10 * time.Second
+
+// MCPResourceUpdate is a change to a subscribed resource reported by its server
+type MCPResourceUpdate struct {
+	URI        string    `json:"uri"`
+	Title      string    `json:"title,omitempty"`
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+// SubscribeResource subscribes to changes of a resource on a server that supports
+// resources/subscribe, calling onChange for each notifications/resources/updated the
+// server sends for it. Notifications are read from the event stream answering the
+// subscribe request, or from the server's GET event stream if it answers with plain JSON.
+// ctx bounds only the subscribe request; the subscription lasts until unsubscribe is
+// called, which also sends resources/unsubscribe.
+func (m *MCPProtocol) SubscribeResource(ctx context.Context, serverURL, uri string, onChange func(MCPResourceUpdate)) (unsubscribe func(), err error) {
+	request := MCPRequest{
+		JSONRPC: "2.0",
+		ID:      10,
+		Method:  "resources/subscribe",
+		Params: map[string]interface{}{
+			"uri": uri,
+		},
+	}
+
+	subCtx, cancel := context.WithCancel(context.Background())
+	messages := make(chan MCPResponse)
+	streamErr := make(chan error, 1)
+	go func() {
+		streamErr <- m.sendStreamingRequest(subCtx, serverURL, request, messages)
+	}()
+
+	// Wait for the subscribe response, passing on notifications that arrive before it
+	subscribed := false
+	for !subscribed {
+		select {
+		case message, ok := <-messages:
+			if !ok {
+				err := <-streamErr
+				cancel()
+				if err == nil {
+					err = fmt.Errorf("stream ended without a response")
+				}
+				return nil, fmt.Errorf("failed to subscribe to resource: %w", err)
+			}
+			if message.Method != "" {
+				m.handleResourceNotification(message, uri, onChange)
+				continue
+			}
+			if requestIDKey(message.ID) != requestIDKey(request.ID) {
+				continue
+			}
+			if message.Error != nil {
+				cancel()
+				drain(messages)
+				return nil, fmt.Errorf("resource subscription failed: %w", message.Error.Err())
+			}
+			subscribed = true
+		case <-ctx.Done():
+			cancel()
+			drain(messages)
+			return nil, fmt.Errorf("failed to subscribe to resource: %w", ctx.Err())
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for message := range messages {
+			m.handleResourceNotification(message, uri, onChange)
+		}
+
+		// A server answering with plain JSON sends its notifications on its GET stream
+		if err := <-streamErr; err == nil && subCtx.Err() == nil {
+			err = m.listenServerEvents(subCtx, serverURL, func(message MCPResponse) {
+				m.handleResourceNotification(message, uri, onChange)
+			})
+			if err != nil && subCtx.Err() == nil {
+				m.logger.Warn("Resource subscription stream failed",
+					zap.String("url", serverURL),
+					zap.String("uri", uri),
+					zap.Error(err),
+				)
+			}
+		}
+	}()
+
+	m.logger.Info("Subscribed to resource", zap.String("url", serverURL), zap.String("uri", uri))
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			cancel()
+			<-done
+
+			unsubscribeCtx, unsubscribeCancel := context.WithTimeout(context.Background(), unsubscribeTimeout)
+			defer unsubscribeCancel()
+			if err := m.unsubscribeResource(unsubscribeCtx, serverURL, uri); err != nil {
+				m.logger.Warn("Failed to unsubscribe from resource",
+					zap.String("url", serverURL),
+					zap.String("uri", uri),
+					zap.Error(err),
+				)
+			}
+		})
+	}, nil
+}
+
+// unsubscribeResource tells a server to stop sending updates of a resource
+func (m *MCPProtocol) unsubscribeResource(ctx context.Context, serverURL, uri string) error {
+	request := MCPRequest{
+		JSONRPC: "2.0",
+		ID:      11,
+		Method:  "resources/unsubscribe",
+		Params: map[string]interface{}{
+			"uri": uri,
+		},
+	}
+
+	response, err := m.sendRequest(ctx, serverURL, request)
+	if err != nil {
+		return fmt.Errorf("failed to unsubscribe from resource: %w", err)
+	}
+	if response.Error != nil {
+		return fmt.Errorf("resource unsubscribe failed: %w", response.Error.Err())
+	}
+	return nil
+}
+
+// handleResourceNotification calls onChange if message reports an update of uri
+func (m *MCPProtocol) handleResourceNotification(message MCPResponse, uri string, onChange func(MCPResourceUpdate)) {
+	if message.Method != resourceUpdatedMethod {
+		return
+	}
+
+	var update MCPResourceUpdate
+	params, _ := json.Marshal(message.Params)
+	if err := json.Unmarshal(params, &update); err != nil {
+		m.logger.Warn("Failed to parse resource update", zap.Error(err))
+		return
+	}
+	if update.URI != uri {
+		return
+	}
+
+	update.ReceivedAt = time.Now()
+	onChange(update)
+}
+
+// listenServerEvents opens the server's GET event stream and passes each JSON-RPC
+// message on it to handle until the server ends the stream or ctx is done
+func (m *MCPProtocol) listenServerEvents(ctx context.Context, serverURL string, handle func(MCPResponse)) error {
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", serverURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	httpReq.Header.Set("Accept", "text/event-stream")
+	httpReq.Header.Set("User-Agent", "Aran-MCP-Sentinel/1.0.0")
+
+	resp, err := m.pool.DoStream(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return &apperrors.ConnectionError{URL: serverURL, Cause: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &apperrors.ConnectionError{URL: serverURL, Cause: fmt.Errorf("event stream request failed with status: %d", resp.StatusCode)}
+	}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "text/event-stream" {
+		return fmt.Errorf("server does not offer an event stream")
+	}
+
+	err = readServerSentEvents(resp.Body, func(data []byte) error {
+		var message MCPResponse
+		if err := json.Unmarshal(data, &message); err != nil {
+			return fmt.Errorf("failed to unmarshal streamed message: %w", err)
+		}
+		handle(message)
+		return nil
+	})
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// drain discards the remaining messages of a cancelled stream so its sender can finish
+func drain(messages <-chan MCPResponse) {
+	for range messages {
+	}
+}
@@ -0,0 +1,193 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	apperrors "github.com/radhi1991/aran-mcp-sentinel/internal/errors"
+	"go.uber.org/zap"
+)
+
+// Tool call transports
+const (
+	// TransportHTTP sends each request as an HTTP POST and reads a single JSON-RPC response
+	TransportHTTP = "http"
+	// TransportSSE sends requests as an HTTP POST and reads the response as a stream of
+	// Server-Sent Events, each carrying one JSON-RPC message
+	TransportSSE = "sse"
+)
+
+// TransportMetadataKey is the server metadata key holding its preferred transport
+const TransportMetadataKey = "transport"
+
+// CallToolStream executes a tool over the SSE transport, sending each JSON-RPC message the
+// server streams to chunks: progress notifications first, then the tool's result. chunks
+// is closed when the stream ends, whether the server finished or ctx was cancelled.
+func (m *MCPProtocol) CallToolStream(ctx context.Context, serverURL, toolName string, arguments map[string]interface{}, chunks chan<- MCPResponse) error {
+	if err := m.sendStreamingRequest(ctx, serverURL, toolCallRequest(toolName, arguments), chunks); err != nil {
+		return fmt.Errorf("failed to call tool: %w", err)
+	}
+	return nil
+}
+
+// callToolStreamed executes a tool over the SSE transport and returns its final result
+func (m *MCPProtocol) callToolStreamed(ctx context.Context, serverURL string, request MCPRequest) (interface{}, error) {
+	chunks := make(chan MCPResponse)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- m.sendStreamingRequest(ctx, serverURL, request, chunks)
+	}()
+
+	var final *MCPResponse
+	for chunk := range chunks {
+		if chunk.Method == "" && requestIDKey(chunk.ID) == requestIDKey(request.ID) {
+			response := chunk
+			final = &response
+		}
+	}
+
+	if err := <-errCh; err != nil {
+		return nil, fmt.Errorf("failed to call tool: %w", err)
+	}
+	if final == nil {
+		return nil, fmt.Errorf("failed to call tool: stream ended without a response")
+	}
+	if final.Error != nil {
+		return nil, fmt.Errorf("tool execution failed: %w", final.Error.Err())
+	}
+
+	return final.Result, nil
+}
+
+// sendStreamingRequest posts a request asking for an event stream and sends each JSON-RPC
+// message the server streams back to responses, closing it when done. It returns nil when
+// the server ends the stream and ctx.Err() if ctx is cancelled first. Servers that answer
+// with plain JSON instead of a stream have their single response sent.
+func (m *MCPProtocol) sendStreamingRequest(ctx context.Context, serverURL string, request MCPRequest, responses chan<- MCPResponse) error {
+	defer close(responses)
+
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	m.logger.Debug("Sending MCP streaming request",
+		zap.String("url", serverURL),
+		zap.String("method", request.Method),
+		zap.ByteString("body", requestBody),
+	)
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", serverURL, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json, text/event-stream")
+	httpReq.Header.Set("User-Agent", "Aran-MCP-Sentinel/1.0.0")
+
+	resp, err := m.pool.DoStream(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return &apperrors.ConnectionError{URL: serverURL, Cause: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &apperrors.ConnectionError{URL: serverURL, Cause: fmt.Errorf("HTTP request failed with status: %d", resp.StatusCode)}
+	}
+
+	emit := func(data []byte) error {
+		var message MCPResponse
+		if err := json.Unmarshal(data, &message); err != nil {
+			return fmt.Errorf("failed to unmarshal streamed message: %w", err)
+		}
+		select {
+		case responses <- message:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/event-stream" {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response body: %w", err)
+		}
+		return emit(body)
+	}
+
+	err = readServerSentEvents(resp.Body, emit)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		return err
+	}
+
+	m.logger.Debug("MCP stream ended", zap.String("url", serverURL))
+	return nil
+}
+
+// readServerSentEvents reads an event stream until EOF, passing the data of each message
+// event to handle. Comments, other event types and an unterminated final event are skipped.
+func readServerSentEvents(body io.Reader, handle func(data []byte) error) error {
+	reader := bufio.NewReader(body)
+
+	var data bytes.Buffer
+	hasData := false
+	event := ""
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to read event stream: %w", err)
+		}
+		if errors.Is(err, io.EOF) && line == "" {
+			return nil
+		}
+		terminated := err == nil
+		line = strings.TrimRight(line, "\r\n")
+
+		if line == "" {
+			if hasData && (event == "" || event == "message") {
+				if err := handle(data.Bytes()); err != nil {
+					return err
+				}
+			}
+			data.Reset()
+			hasData = false
+			event = ""
+		} else if !strings.HasPrefix(line, ":") {
+			field, value, _ := strings.Cut(line, ":")
+			value = strings.TrimPrefix(value, " ")
+
+			switch field {
+			case "data":
+				if hasData {
+					data.WriteByte('\n')
+				}
+				data.WriteString(value)
+				hasData = true
+			case "event":
+				event = value
+			}
+		}
+
+		if !terminated {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DefaultMaxConcurrentExecutions limits how many tools of a batch run at once when no
+// limit is configured
+const DefaultMaxConcurrentExecutions = 10
+
+// MaxBatchExecutions is the most tool executions one batch may contain
+const MaxBatchExecutions = 100
+
+// BatchRequest is one tool execution of a batch
+type BatchRequest struct {
+	ToolID    uuid.UUID              `json:"tool_id"`
+	Arguments map[string]interface{} `json:"arguments"`
+}
+
+// BatchResult is the outcome of one tool execution of a batch. Status is the
+// execution's status (completed or failed) when the tool ran; otherwise it is
+// policy_denied, quota_exceeded, timeout or rejected.
+type BatchResult struct {
+	ToolID      uuid.UUID   `json:"tool_id"`
+	ExecutionID *uuid.UUID  `json:"execution_id,omitempty"`
+	Status      string      `json:"status"`
+	Result      interface{} `json:"result,omitempty"`
+	Error       string      `json:"error,omitempty"`
+	DurationMs  int64       `json:"duration_ms"`
+}
+
+// SetMaxConcurrentExecutions limits how many tools of a batch run at once
+func (tm *ToolManager) SetMaxConcurrentExecutions(limit int) {
+	tm.maxConcurrentExecutions = limit
+}
+
+// BatchExecute runs the requested tools concurrently, at most
+// SetMaxConcurrentExecutions at a time, and returns one result per request in request
+// order. A failed execution does not stop the others; executions still waiting for a
+// slot when ctx is done are reported as timed out. orgID, if set, must own every tool.
+func (tm *ToolManager) BatchExecute(ctx context.Context, orgID *uuid.UUID, reqs []BatchRequest, userID *uuid.UUID) []BatchResult {
+	limit := tm.maxConcurrentExecutions
+	if limit <= 0 {
+		limit = DefaultMaxConcurrentExecutions
+	}
+
+	results := make([]BatchResult, len(reqs))
+	semaphore := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+
+	for i, req := range reqs {
+		wg.Add(1)
+		go func(i int, req BatchRequest) {
+			defer wg.Done()
+			start := time.Now()
+
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				results[i] = batchResult(req.ToolID, nil, ctx.Err(), start)
+				return
+			}
+			// select picks either case when both are ready
+			if err := ctx.Err(); err != nil {
+				results[i] = batchResult(req.ToolID, nil, err, start)
+				return
+			}
+
+			execution, err := tm.ExecuteTool(ctx, orgID, req.ToolID, req.Arguments, userID)
+			results[i] = batchResult(req.ToolID, execution, err, start)
+		}(i, req)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, result := range results {
+		if result.Status != "completed" {
+			failed++
+		}
+	}
+	tm.logger.Info("Batch tool execution finished",
+		zap.Int("executions", len(reqs)),
+		zap.Int("failed", failed),
+	)
+
+	return results
+}
+
+// batchResult describes an execution, or the error that kept the tool from running
+func batchResult(toolID uuid.UUID, execution *ToolExecution, err error, start time.Time) BatchResult {
+	result := BatchResult{
+		ToolID:     toolID,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+
+	if execution != nil {
+		result.ExecutionID = &execution.ID
+		result.Status = execution.Status
+		result.Result = execution.Result
+		result.Error = execution.Error
+		return result
+	}
+
+	result.Error = err.Error()
+	switch {
+	case errors.Is(err, ErrToolDenied):
+		result.Status = "policy_denied"
+	case errors.Is(err, ErrQuotaExceeded), errors.Is(err, ErrDailyLimitExceeded):
+		result.Status = "quota_exceeded"
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		result.Status = "timeout"
+	default:
+		result.Status = "rejected"
+	}
+	return result
+}
@@ -0,0 +1,122 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// MaxBulkToolIDs is the most tools that can be updated in one bulk request
+const MaxBulkToolIDs = 1000
+
+// BulkResult reports the outcome of a bulk tool update
+type BulkResult struct {
+	Updated  int64       `json:"updated"`
+	NotFound []uuid.UUID `json:"not_found"`
+}
+
+// ToolsModifiedError is returned by a bulk update when tools changed after the
+// caller's updated_before timestamp. Nothing is updated.
+type ToolsModifiedError struct {
+	ToolIDs []uuid.UUID
+}
+
+func (e *ToolsModifiedError) Error() string {
+	return fmt.Sprintf("%d tools were modified after the given timestamp", len(e.ToolIDs))
+}
+
+// BulkSetEnabled enables or disables tools in one statement. If updatedBefore is set,
+// the update is rejected with a *ToolsModifiedError when any of the tools was updated
+// after it. IDs that match no tool, or no tool of orgID's servers when it is set, are
+// returned in BulkResult.NotFound.
+func (tm *ToolManager) BulkSetEnabled(ctx context.Context, orgID *uuid.UUID, ids []uuid.UUID, enabled bool, updatedBefore *time.Time) (*BulkResult, error) {
+	idStrings := make([]string, len(ids))
+	for i, id := range ids {
+		idStrings[i] = id.String()
+	}
+
+	tx, err := tm.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin bulk tool update: %w", err)
+	}
+	defer tx.Rollback()
+
+	if updatedBefore != nil {
+		// Lock the rows so none can change between the check and the update
+		rows, err := tx.QueryContext(ctx, `
+			SELECT id FROM mcp_tools
+			WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL AND updated_at > $2
+			  AND ($3::uuid IS NULL OR server_id IN (SELECT id FROM mcp_servers WHERE organization_id = $3))
+			FOR UPDATE
+		`, pq.Array(idStrings), *updatedBefore, orgID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check tool modifications: %w", err)
+		}
+		modified, err := scanToolIDs(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check tool modifications: %w", err)
+		}
+		if len(modified) > 0 {
+			return nil, &ToolsModifiedError{ToolIDs: modified}
+		}
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		UPDATE mcp_tools SET is_enabled = $1, updated_at = NOW()
+		WHERE id = ANY($2::uuid[]) AND deleted_at IS NULL
+		  AND ($3::uuid IS NULL OR server_id IN (SELECT id FROM mcp_servers WHERE organization_id = $3))
+		RETURNING id
+	`, enabled, pq.Array(idStrings), orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update tools: %w", err)
+	}
+	updated, err := scanToolIDs(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update tools: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit bulk tool update: %w", err)
+	}
+	tm.cache.invalidate(updated...)
+
+	found := make(map[uuid.UUID]bool, len(updated))
+	for _, id := range updated {
+		found[id] = true
+	}
+	result := &BulkResult{Updated: int64(len(updated)), NotFound: []uuid.UUID{}}
+	for _, id := range ids {
+		if !found[id] {
+			result.NotFound = append(result.NotFound, id)
+			found[id] = true
+		}
+	}
+
+	tm.logger.Info("Bulk updated tools",
+		zap.Bool("enabled", enabled),
+		zap.Int64("updated", result.Updated),
+		zap.Int("not_found", len(result.NotFound)),
+	)
+
+	return result, nil
+}
+
+// scanToolIDs reads a single id column from rows and closes them
+func scanToolIDs(rows *sql.Rows) ([]uuid.UUID, error) {
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,183 @@
+package mcp
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultToolCacheTTL is how long tools and their usage stats are cached
+const DefaultToolCacheTTL = 60 * time.Second
+
+// cachedTool is a tool and when it stops being served from the cache
+type cachedTool struct {
+	tool    *ManagedTool
+	expires time.Time
+}
+
+// cachedUsageStats are a tool's usage stats and when they stop being served from the cache
+type cachedUsageStats struct {
+	stats   *ToolUsageStats
+	expires time.Time
+}
+
+// ToolCacheStats reports how often the tool cache served a lookup
+type ToolCacheStats struct {
+	Hits    int64   `json:"hits"`
+	Misses  int64   `json:"misses"`
+	HitRate float64 `json:"hit_rate"`
+	Entries int     `json:"entries"`
+	TTL     string  `json:"ttl"`
+}
+
+// ToolCache caches tools and their usage stats in memory for GetTool and
+// GetToolUsageStats. Writes to mcp_tools update or invalidate the entries they touch.
+// Cached tools are copied in and out so callers cannot change them.
+type ToolCache struct {
+	ttl time.Duration
+
+	mu    sync.Mutex
+	tools map[uuid.UUID]cachedTool
+	stats map[uuid.UUID]cachedUsageStats
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// NewToolCache creates an empty tool cache. A ttl of zero or less uses DefaultToolCacheTTL.
+func NewToolCache(ttl time.Duration) *ToolCache {
+	if ttl <= 0 {
+		ttl = DefaultToolCacheTTL
+	}
+	return &ToolCache{
+		ttl:   ttl,
+		tools: make(map[uuid.UUID]cachedTool),
+		stats: make(map[uuid.UUID]cachedUsageStats),
+	}
+}
+
+// getTool returns a copy of a cached tool if it has not expired
+func (c *ToolCache) getTool(toolID uuid.UUID) (*ManagedTool, bool) {
+	c.mu.Lock()
+	entry, ok := c.tools[toolID]
+	if ok && !time.Now().Before(entry.expires) {
+		delete(c.tools, toolID)
+		ok = false
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		c.misses.Add(1)
+		return nil, false
+	}
+	tool, err := cloneManagedTool(entry.tool)
+	if err != nil {
+		c.misses.Add(1)
+		return nil, false
+	}
+	c.hits.Add(1)
+	return tool, true
+}
+
+// setTool caches a copy of a tool
+func (c *ToolCache) setTool(tool *ManagedTool) {
+	cached, err := cloneManagedTool(tool)
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tools[tool.ID] = cachedTool{tool: cached, expires: time.Now().Add(c.ttl)}
+}
+
+// updateTool applies update to a cached tool, if there is one, without extending its TTL
+func (c *ToolCache) updateTool(toolID uuid.UUID, update func(tool *ManagedTool)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.tools[toolID]; ok {
+		update(entry.tool)
+	}
+}
+
+// getUsageStats returns a copy of a tool's cached usage stats if they have not expired
+func (c *ToolCache) getUsageStats(toolID uuid.UUID) (*ToolUsageStats, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.stats[toolID]
+	if ok && !time.Now().Before(entry.expires) {
+		delete(c.stats, toolID)
+		ok = false
+	}
+	if !ok {
+		c.misses.Add(1)
+		return nil, false
+	}
+	c.hits.Add(1)
+	stats := *entry.stats
+	return &stats, true
+}
+
+// setUsageStats caches a copy of a tool's usage stats
+func (c *ToolCache) setUsageStats(stats *ToolUsageStats) {
+	cached := *stats
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats[stats.ToolID] = cachedUsageStats{stats: &cached, expires: time.Now().Add(c.ttl)}
+}
+
+// invalidate drops the cached tools and usage stats of the given tools
+func (c *ToolCache) invalidate(toolIDs ...uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, toolID := range toolIDs {
+		delete(c.tools, toolID)
+		delete(c.stats, toolID)
+	}
+}
+
+// invalidateUsageStats drops a tool's cached usage stats, keeping the tool
+func (c *ToolCache) invalidateUsageStats(toolID uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.stats, toolID)
+}
+
+// invalidateServer drops the cached tools of a server, optionally only the one with the
+// given name. Tools written by server and name rather than ID are invalidated this way.
+func (c *ToolCache) invalidateServer(serverID uuid.UUID, name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for toolID, entry := range c.tools {
+		if entry.tool.ServerID == serverID && (name == "" || entry.tool.Name == name) {
+			delete(c.tools, toolID)
+			delete(c.stats, toolID)
+		}
+	}
+}
+
+// Stats returns the cache's hit and miss counts since it was created
+func (c *ToolCache) Stats() ToolCacheStats {
+	c.mu.Lock()
+	entries := len(c.tools) + len(c.stats)
+	c.mu.Unlock()
+
+	stats := ToolCacheStats{
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+		Entries: entries,
+		TTL:     c.ttl.String(),
+	}
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(total)
+	}
+	return stats
+}
@@ -0,0 +1,201 @@
+package mcp
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	apperrors "github.com/radhi1991/aran-mcp-sentinel/internal/errors"
+	"go.uber.org/zap"
+)
+
+// newCachedTool returns a tool of a server owned by orgID
+func newCachedTool(serverID, orgID uuid.UUID, name string) *ManagedTool {
+	tool := &ManagedTool{
+		ID:       uuid.New(),
+		ServerID: serverID,
+		Tags:     []string{"files"},
+	}
+	tool.Name = name
+	tool.InputSchema = map[string]interface{}{"type": "object"}
+	tool.organizationID = orgID
+	return tool
+}
+
+func TestToolCacheHitsAndMisses(t *testing.T) {
+	c := NewToolCache(time.Minute)
+	tool := newCachedTool(uuid.New(), uuid.New(), "read_file")
+
+	if _, ok := c.getTool(tool.ID); ok {
+		t.Fatal("getTool() of an empty cache hit")
+	}
+	c.setTool(tool)
+	for i := 0; i < 3; i++ {
+		cached, ok := c.getTool(tool.ID)
+		if !ok || cached.Name != "read_file" {
+			t.Fatalf("getTool() = %v, %v, want the cached tool", cached, ok)
+		}
+	}
+
+	stats := c.Stats()
+	if stats.Hits != 3 || stats.Misses != 1 || stats.HitRate != 0.75 || stats.Entries != 1 || stats.TTL != "1m0s" {
+		t.Errorf("Stats() = %+v, want 3 hits, 1 miss, rate 0.75, 1 entry, TTL 1m0s", stats)
+	}
+}
+
+func TestToolCacheCopies(t *testing.T) {
+	c := NewToolCache(time.Minute)
+	tool := newCachedTool(uuid.New(), uuid.New(), "read_file")
+	c.setTool(tool)
+
+	// Neither the stored tool nor a returned one changes the cached entry
+	tool.Tags[0] = "changed"
+	tool.InputSchema["type"] = "changed"
+	cached, _ := c.getTool(tool.ID)
+	cached.Tags = append(cached.Tags, "extra")
+	cached.InputSchema["type"] = "changed"
+
+	again, _ := c.getTool(tool.ID)
+	if len(again.Tags) != 1 || again.Tags[0] != "files" || again.InputSchema["type"] != "object" {
+		t.Errorf("cached tool = tags %v, schema %v, want the tool as stored", again.Tags, again.InputSchema)
+	}
+}
+
+func TestToolCacheTTL(t *testing.T) {
+	c := NewToolCache(time.Minute)
+	tool := newCachedTool(uuid.New(), uuid.New(), "read_file")
+	c.setTool(tool)
+	c.setUsageStats(&ToolUsageStats{ToolID: tool.ID, TotalExecutions: 4})
+
+	// Age both entries past their TTL
+	c.mu.Lock()
+	entry := c.tools[tool.ID]
+	entry.expires = time.Now().Add(-time.Second)
+	c.tools[tool.ID] = entry
+	stats := c.stats[tool.ID]
+	stats.expires = time.Now().Add(-time.Second)
+	c.stats[tool.ID] = stats
+	c.mu.Unlock()
+
+	if _, ok := c.getTool(tool.ID); ok {
+		t.Error("getTool() served an expired tool")
+	}
+	if _, ok := c.getUsageStats(tool.ID); ok {
+		t.Error("getUsageStats() served expired stats")
+	}
+	if entries := c.Stats().Entries; entries != 0 {
+		t.Errorf("cache holds %d entries after they expired, want 0", entries)
+	}
+
+	if got := NewToolCache(0).Stats().TTL; got != DefaultToolCacheTTL.String() {
+		t.Errorf("TTL of NewToolCache(0) = %s, want %s", got, DefaultToolCacheTTL)
+	}
+}
+
+func TestToolCacheExpiresAfterTTL(t *testing.T) {
+	c := NewToolCache(20 * time.Millisecond)
+	tool := newCachedTool(uuid.New(), uuid.New(), "read_file")
+	c.setTool(tool)
+
+	if _, ok := c.getTool(tool.ID); !ok {
+		t.Fatal("getTool() missed a fresh tool")
+	}
+	time.Sleep(40 * time.Millisecond)
+	if _, ok := c.getTool(tool.ID); ok {
+		t.Error("getTool() served a tool past its TTL")
+	}
+}
+
+func TestToolCacheUsageStats(t *testing.T) {
+	c := NewToolCache(time.Minute)
+	toolID := uuid.New()
+	c.setUsageStats(&ToolUsageStats{ToolID: toolID, TotalExecutions: 4})
+
+	stats, ok := c.getUsageStats(toolID)
+	if !ok || stats.TotalExecutions != 4 {
+		t.Fatalf("getUsageStats() = %v, %v, want the cached stats", stats, ok)
+	}
+	stats.TotalExecutions = 100
+	if again, _ := c.getUsageStats(toolID); again.TotalExecutions != 4 {
+		t.Errorf("cached stats changed through a returned copy to %d executions", again.TotalExecutions)
+	}
+
+	c.invalidateUsageStats(toolID)
+	if _, ok := c.getUsageStats(toolID); ok {
+		t.Error("getUsageStats() hit after invalidateUsageStats()")
+	}
+}
+
+func TestToolCacheUpdateAndInvalidate(t *testing.T) {
+	serverID, otherServerID, orgID := uuid.New(), uuid.New(), uuid.New()
+	readFile := newCachedTool(serverID, orgID, "read_file")
+	writeFile := newCachedTool(serverID, orgID, "write_file")
+	other := newCachedTool(otherServerID, orgID, "read_file")
+
+	c := NewToolCache(time.Minute)
+	for _, tool := range []*ManagedTool{readFile, writeFile, other} {
+		c.setTool(tool)
+	}
+
+	c.updateTool(readFile.ID, func(tool *ManagedTool) { tool.UsageCount = 7 })
+	c.updateTool(uuid.New(), func(tool *ManagedTool) { t.Error("updateTool() called update for an uncached tool") })
+	if cached, _ := c.getTool(readFile.ID); cached.UsageCount != 7 {
+		t.Errorf("usage count after updateTool() = %d, want 7", cached.UsageCount)
+	}
+
+	c.invalidateServer(serverID, "read_file")
+	if _, ok := c.getTool(readFile.ID); ok {
+		t.Error("tool invalidated by server and name is still cached")
+	}
+	if _, ok := c.getTool(writeFile.ID); !ok {
+		t.Error("tool with another name was invalidated with its server")
+	}
+	if _, ok := c.getTool(other.ID); !ok {
+		t.Error("tool of another server was invalidated")
+	}
+
+	c.invalidateServer(serverID, "")
+	if _, ok := c.getTool(writeFile.ID); ok {
+		t.Error("tool of an invalidated server is still cached")
+	}
+
+	c.setUsageStats(&ToolUsageStats{ToolID: other.ID})
+	c.invalidate(other.ID)
+	if _, ok := c.getTool(other.ID); ok {
+		t.Error("invalidated tool is still cached")
+	}
+	if _, ok := c.getUsageStats(other.ID); ok {
+		t.Error("usage stats of an invalidated tool are still cached")
+	}
+}
+
+func TestGetToolServedFromCache(t *testing.T) {
+	// Without a database, every lookup below must come from the cache
+	tm := NewToolManager(nil, zap.NewNop())
+	orgID, otherOrgID := uuid.New(), uuid.New()
+	tool := newCachedTool(uuid.New(), orgID, "read_file")
+	tm.cache.setTool(tool)
+	tm.cache.setUsageStats(&ToolUsageStats{ToolID: tool.ID, TotalExecutions: 2})
+
+	got, err := tm.GetTool(&orgID, tool.ID)
+	if err != nil || got.Name != "read_file" {
+		t.Fatalf("GetTool() = %v, %v, want the cached tool", got, err)
+	}
+	stats, err := tm.GetToolUsageStats(&orgID, tool.ID)
+	if err != nil || stats.TotalExecutions != 2 {
+		t.Fatalf("GetToolUsageStats() = %v, %v, want the cached stats", stats, err)
+	}
+
+	var notFound *apperrors.NotFoundError
+	if _, err := tm.GetTool(&otherOrgID, tool.ID); !errors.As(err, &notFound) {
+		t.Errorf("GetTool() by another organization error = %v, want NotFoundError", err)
+	}
+	if _, err := tm.GetToolUsageStats(&otherOrgID, tool.ID); !errors.As(err, &notFound) {
+		t.Errorf("GetToolUsageStats() by another organization error = %v, want NotFoundError", err)
+	}
+
+	if stats := tm.ToolCacheStats(); stats.Hits != 5 || stats.Misses != 0 {
+		t.Errorf("ToolCacheStats() = %+v, want 5 hits and no misses", stats)
+	}
+}
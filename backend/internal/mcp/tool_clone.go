@@ -0,0 +1,190 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ErrToolNameConflict is returned when the target server already has a tool with the
+// name of the tool being cloned
+var ErrToolNameConflict = errors.New("target server already has a tool with this name")
+
+// CloneTool copies a tool definition to another server of the same organization. The
+// clone gets a new ID and starts with no usage. sql.ErrNoRows is returned if the source
+// tool or the target server does not exist, or orgID is set and does not own them.
+func (tm *ToolManager) CloneTool(ctx context.Context, orgID *uuid.UUID, sourceToolID, targetServerID uuid.UUID) (*ManagedTool, error) {
+	source, err := tm.GetTool(orgID, sourceToolID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source tool: %w", err)
+	}
+	var targetURL string
+	err = tm.db.QueryRowContext(ctx, `
+		SELECT t.url
+		FROM mcp_servers t
+		JOIN mcp_servers s ON s.organization_id = t.organization_id
+		WHERE t.id = $1 AND s.id = $2 AND t.deleted_at IS NULL
+		  AND ($3::uuid IS NULL OR t.organization_id = $3)
+	`, targetServerID, source.ServerID, orgID).Scan(&targetURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target server: %w", err)
+	}
+
+	// storeTool upserts on (server_id, name), so check first rather than overwrite.
+	// This also rejects cloning a tool onto its own server.
+	var exists bool
+	err = tm.db.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM mcp_tools WHERE server_id = $1 AND name = $2)
+	`, targetServerID, source.Name).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for existing tool: %w", err)
+	}
+	if exists {
+		return nil, ErrToolNameConflict
+	}
+
+	clone, err := cloneManagedTool(source)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	clone.ID = uuid.New()
+	clone.ServerID = targetServerID
+	clone.ServerURL = targetURL
+	clone.UsageCount = 0
+	clone.LastUsed = nil
+	clone.CreatedAt = now
+	clone.UpdatedAt = now
+
+	if err := tm.storeTool(clone); err != nil {
+		return nil, fmt.Errorf("failed to store cloned tool: %w", err)
+	}
+
+	tm.logger.Info("Cloned tool",
+		zap.String("source_tool_id", source.ID.String()),
+		zap.String("tool_id", clone.ID.String()),
+		zap.String("server_id", targetServerID.String()),
+	)
+
+	return clone, nil
+}
+
+// cloneManagedTool returns a copy of tool whose schemas, tags and dependencies share no
+// memory with it
+func cloneManagedTool(tool *ManagedTool) (*ManagedTool, error) {
+	clone := *tool
+	clone.Tags = append([]string(nil), tool.Tags...)
+	clone.Dependencies = append([]string(nil), tool.Dependencies...)
+
+	var err error
+	if clone.InputSchema, err = copySchema(tool.InputSchema); err != nil {
+		return nil, fmt.Errorf("failed to copy input schema: %w", err)
+	}
+	if clone.OutputSchema, err = copySchema(tool.OutputSchema); err != nil {
+		return nil, fmt.Errorf("failed to copy output schema: %w", err)
+	}
+
+	return &clone, nil
+}
+
+// copySchema deep-copies a JSON schema by round-tripping it through JSON
+func copySchema(schema map[string]interface{}) (map[string]interface{}, error) {
+	if schema == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	var copied map[string]interface{}
+	if err := json.Unmarshal(data, &copied); err != nil {
+		return nil, err
+	}
+	return copied, nil
+}
+
+// CloneMetadata copies the category, tags and risk level of one tool to another.
+// The input schema is left untouched because it is owned by the target server.
+// userID is recorded as the actor and may be nil. orgID, if set, must own both tools' servers.
+func (tm *ToolManager) CloneMetadata(ctx context.Context, orgID *uuid.UUID, sourceToolID, targetToolID uuid.UUID, userID *uuid.UUID) error {
+	if sourceToolID == targetToolID {
+		return fmt.Errorf("source and target tool must differ")
+	}
+
+	source, err := tm.GetTool(orgID, sourceToolID)
+	if err != nil {
+		return fmt.Errorf("failed to get source tool: %w", err)
+	}
+	target, err := tm.GetTool(orgID, targetToolID)
+	if err != nil {
+		return fmt.Errorf("failed to get target tool: %w", err)
+	}
+
+	tagsJSON, _ := json.Marshal(source.Tags)
+	now := time.Now()
+
+	tx, err := tm.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE mcp_tools SET category = $1, risk_level = $2, updated_at = $3
+		WHERE id = $4
+	`, source.Category, source.RiskLevel, now, target.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update target tool: %w", err)
+	}
+	if err := setToolTags(ctx, tx, target.ID, source.Tags); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO tool_metadata_clones (id, source_tool_id, target_tool_id, category, tags, risk_level, cloned_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, uuid.New(), source.ID, target.ID, source.Category, tagsJSON, source.RiskLevel, userID, now)
+	if err != nil {
+		return fmt.Errorf("failed to record metadata clone: %w", err)
+	}
+
+	details, _ := json.Marshal(map[string]interface{}{
+		"source_tool_id":      source.ID,
+		"source_server_id":    source.ServerID,
+		"previous_category":   target.Category,
+		"previous_tags":       target.Tags,
+		"previous_risk_level": target.RiskLevel,
+		"category":            source.Category,
+		"tags":                source.Tags,
+		"risk_level":          source.RiskLevel,
+	})
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO audit_logs (id, organization_id, user_id, action, resource_type, resource_id, details, created_at)
+		SELECT $1, s.organization_id, $2, 'tool.metadata_cloned', 'tool', $3, $4, $5
+		FROM mcp_servers s
+		WHERE s.id = $6
+	`, uuid.New(), userID, target.ID, details, now, target.ServerID)
+	if err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit metadata clone: %w", err)
+	}
+	tm.cache.invalidate(target.ID)
+
+	tm.logger.Info("Cloned tool metadata",
+		zap.String("source_tool_id", source.ID.String()),
+		zap.String("target_tool_id", target.ID.String()),
+	)
+
+	return nil
+}
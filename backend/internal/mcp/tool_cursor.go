@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// Tool list page sizes
+const (
+	DefaultToolPageSize = 50
+	MaxToolPageSize     = 200
+)
+
+// ErrInvalidToolCursor is returned when a tool list cursor cannot be decoded
+var ErrInvalidToolCursor = errors.New("invalid tool cursor")
+
+// ToolFilter narrows the tools returned by ListToolsPage
+type ToolFilter struct {
+	ServerID  *uuid.UUID
+	Category  string
+	RiskLevel string
+	Enabled   *bool
+	Tags      []string // tools must have all of them
+}
+
+// ToolCursor marks the last tool of a page in the (usage_count DESC, id ASC) ordering
+type ToolCursor struct {
+	UsageCount int64     `json:"u"`
+	ID         uuid.UUID `json:"i"`
+}
+
+// Encode returns the cursor as an opaque URL-safe string
+func (c *ToolCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeToolCursor parses a cursor produced by ToolCursor.Encode
+func DecodeToolCursor(encoded string) (*ToolCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidToolCursor
+	}
+
+	var cursor ToolCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == uuid.Nil {
+		return nil, ErrInvalidToolCursor
+	}
+	return &cursor, nil
+}
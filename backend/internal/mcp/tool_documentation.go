@@ -0,0 +1,350 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ParamDoc documents one tool parameter. Nested object properties are listed with
+// dotted names, e.g. options.recursive.
+type ParamDoc struct {
+	Name        string        `json:"name"`
+	Type        string        `json:"type"`
+	Description string        `json:"description,omitempty"`
+	Required    bool          `json:"required"`
+	Default     interface{}   `json:"default,omitempty"`
+	Enum        []interface{} `json:"enum,omitempty"`
+}
+
+// ExampleCall is an example set of arguments for a tool
+type ExampleCall struct {
+	Description string                 `json:"description"`
+	Arguments   map[string]interface{} `json:"arguments"`
+}
+
+// ToolDocumentation is structured documentation generated from a tool's input schema
+type ToolDocumentation struct {
+	ToolID      uuid.UUID     `json:"tool_id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Category    string        `json:"category"`
+	RiskLevel   string        `json:"risk_level"`
+	Parameters  []ParamDoc    `json:"parameters"`
+	Examples    []ExampleCall `json:"examples"`
+	Warnings    []string      `json:"warnings"`
+}
+
+// schemaTypeNames maps JSON Schema types to the names shown in documentation
+var schemaTypeNames = map[string]string{
+	"string":  "text",
+	"integer": "whole number",
+	"number":  "number",
+	"boolean": "true/false",
+	"object":  "object",
+	"array":   "list",
+	"null":    "null",
+}
+
+// schemaFormatNames maps JSON Schema string formats to the names shown in documentation
+var schemaFormatNames = map[string]string{
+	"date-time": "date and time",
+	"date":      "date",
+	"time":      "time",
+	"email":     "email address",
+	"uri":       "URL",
+	"url":       "URL",
+	"uuid":      "UUID",
+	"ipv4":      "IPv4 address",
+	"ipv6":      "IPv6 address",
+	"hostname":  "hostname",
+}
+
+// categoryWarnings are the notes added for tools in each category
+var categoryWarnings = map[string]string{
+	"database": "This tool accesses a database",
+	"network":  "This tool makes network requests to external services",
+	"system":   "This tool runs commands on the host system",
+	"security": "This tool handles credentials or other secrets",
+}
+
+// writeKeywords mark tool names that modify data rather than only read it
+var writeKeywords = []string{"write", "create", "delete", "remove", "modify", "update", "edit", "move", "rename", "insert", "drop", "save"}
+
+// ToolDocumentationGenerator builds documentation for tools from their input schemas
+type ToolDocumentationGenerator struct{}
+
+// NewToolDocumentationGenerator creates a tool documentation generator
+func NewToolDocumentationGenerator() *ToolDocumentationGenerator {
+	return &ToolDocumentationGenerator{}
+}
+
+// Generate documents a tool's parameters, builds example calls and adds warnings based on
+// the tool's category, risk level and gaps in its schema
+func (g *ToolDocumentationGenerator) Generate(tool *ManagedTool) *ToolDocumentation {
+	doc := &ToolDocumentation{
+		ToolID:      tool.ID,
+		Name:        tool.Name,
+		Description: tool.Description,
+		Category:    tool.Category,
+		RiskLevel:   tool.RiskLevel,
+		Parameters:  []ParamDoc{},
+		Examples:    []ExampleCall{},
+		Warnings:    []string{},
+	}
+
+	g.collectParams(tool.InputSchema, "", true, &doc.Parameters)
+	doc.Examples = g.examples(tool.InputSchema)
+	doc.Warnings = g.warnings(tool, doc.Parameters)
+
+	return doc
+}
+
+// collectParams appends the documentation of a schema's properties, sorted with required
+// parameters first. parentRequired is false inside optional objects, whose properties are
+// only required when the object is given.
+func (g *ToolDocumentationGenerator) collectParams(schema map[string]interface{}, prefix string, parentRequired bool, params *[]ParamDoc) {
+	properties, _ := schema["properties"].(map[string]interface{})
+	required := schemaRequired(schema)
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if required[names[i]] != required[names[j]] {
+			return required[names[i]]
+		}
+		return names[i] < names[j]
+	})
+
+	for _, name := range names {
+		property, ok := properties[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		param := ParamDoc{
+			Name:     prefix + name,
+			Type:     schemaTypeName(property),
+			Required: parentRequired && required[name],
+			Default:  property["default"],
+		}
+		if description, ok := property["description"].(string); ok {
+			param.Description = strings.TrimSpace(description)
+		}
+		if enum, ok := property["enum"].([]interface{}); ok {
+			param.Enum = enum
+		}
+		*params = append(*params, param)
+
+		if _, hasProperties := property["properties"]; hasProperties {
+			g.collectParams(property, param.Name+".", param.Required, params)
+		}
+	}
+}
+
+// examples builds a call with only the required arguments and, if the tool has optional
+// parameters, a call with every argument
+func (g *ToolDocumentationGenerator) examples(schema map[string]interface{}) []ExampleCall {
+	properties, _ := schema["properties"].(map[string]interface{})
+	if len(properties) == 0 {
+		return []ExampleCall{{Description: "Call without arguments", Arguments: map[string]interface{}{}}}
+	}
+
+	minimal := exampleObject(schema, false)
+	examples := []ExampleCall{{Description: "Call with required arguments only", Arguments: minimal}}
+
+	full := exampleObject(schema, true)
+	if len(full) > len(minimal) {
+		examples = append(examples, ExampleCall{Description: "Call with all arguments", Arguments: full})
+	}
+
+	return examples
+}
+
+// warnings returns notes about what the tool does and where its schema is incomplete
+func (g *ToolDocumentationGenerator) warnings(tool *ManagedTool, params []ParamDoc) []string {
+	warnings := []string{}
+
+	writes := false
+	name := strings.ToLower(tool.Name)
+	for _, keyword := range writeKeywords {
+		if strings.Contains(name, keyword) {
+			writes = true
+			break
+		}
+	}
+
+	switch tool.Category {
+	case "filesystem":
+		if writes {
+			warnings = append(warnings, "This tool writes to the filesystem")
+		} else {
+			warnings = append(warnings, "This tool reads from the filesystem")
+		}
+	case "database":
+		if writes {
+			warnings = append(warnings, "This tool modifies database records")
+		} else {
+			warnings = append(warnings, categoryWarnings[tool.Category])
+		}
+	default:
+		if warning, ok := categoryWarnings[tool.Category]; ok {
+			warnings = append(warnings, warning)
+		}
+	}
+
+	if tool.RiskLevel == "high" {
+		warnings = append(warnings, "This tool is high risk; review its arguments before running it")
+	}
+
+	if len(tool.InputSchema) == 0 {
+		warnings = append(warnings, "This tool has no input schema, so its parameters are undocumented")
+	}
+
+	var undocumented []string
+	for _, param := range params {
+		if param.Description == "" {
+			undocumented = append(undocumented, param.Name)
+		}
+	}
+	if len(undocumented) > 0 {
+		warnings = append(warnings, fmt.Sprintf("Parameters without a description: %s", strings.Join(undocumented, ", ")))
+	}
+
+	return warnings
+}
+
+// schemaRequired returns the set of required property names of an object schema
+func schemaRequired(schema map[string]interface{}) map[string]bool {
+	required := make(map[string]bool)
+	if names, ok := schema["required"].([]interface{}); ok {
+		for _, name := range names {
+			if field, ok := name.(string); ok {
+				required[field] = true
+			}
+		}
+	}
+	return required
+}
+
+// schemaTypeName returns a human-readable name for a property's type, e.g. "list of text"
+// or "URL". Properties allowing several types are joined with "or".
+func schemaTypeName(property map[string]interface{}) string {
+	var types []string
+	switch t := property["type"].(type) {
+	case string:
+		types = []string{t}
+	case []interface{}:
+		for _, item := range t {
+			if name, ok := item.(string); ok {
+				types = append(types, name)
+			}
+		}
+	}
+
+	if len(types) == 0 {
+		if _, ok := property["enum"]; ok {
+			return "one of the listed values"
+		}
+		return "any"
+	}
+
+	names := make([]string, 0, len(types))
+	for _, schemaType := range types {
+		name, ok := schemaTypeNames[schemaType]
+		if !ok {
+			name = schemaType
+		}
+
+		switch schemaType {
+		case "string":
+			if format, ok := property["format"].(string); ok {
+				if formatName, ok := schemaFormatNames[format]; ok {
+					name = formatName
+				}
+			}
+		case "array":
+			if items, ok := property["items"].(map[string]interface{}); ok {
+				name = "list of " + schemaTypeName(items)
+			}
+		}
+
+		names = append(names, name)
+	}
+
+	return strings.Join(names, " or ")
+}
+
+// exampleObject builds example arguments for an object schema, including optional
+// properties when all is set
+func exampleObject(schema map[string]interface{}, all bool) map[string]interface{} {
+	properties, _ := schema["properties"].(map[string]interface{})
+	required := schemaRequired(schema)
+
+	arguments := make(map[string]interface{})
+	for name, value := range properties {
+		if !all && !required[name] {
+			continue
+		}
+		if property, ok := value.(map[string]interface{}); ok {
+			arguments[name] = exampleValue(name, property, all)
+		}
+	}
+
+	return arguments
+}
+
+// exampleValue picks an example value for a property: its default, first example or first
+// enum value if it has one, otherwise a placeholder for its type
+func exampleValue(name string, property map[string]interface{}, all bool) interface{} {
+	if value, ok := property["default"]; ok {
+		return value
+	}
+	if examples, ok := property["examples"].([]interface{}); ok && len(examples) > 0 {
+		return examples[0]
+	}
+	if enum, ok := property["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[0]
+	}
+
+	schemaType, _ := property["type"].(string)
+	if types, ok := property["type"].([]interface{}); ok && len(types) > 0 {
+		schemaType, _ = types[0].(string)
+	}
+
+	switch schemaType {
+	case "integer", "number":
+		return 1
+	case "boolean":
+		return true
+	case "object":
+		return exampleObject(property, all)
+	case "array":
+		if items, ok := property["items"].(map[string]interface{}); ok {
+			return []interface{}{exampleValue(name, items, all)}
+		}
+		return []interface{}{}
+	case "null":
+		return nil
+	default:
+		if format, ok := property["format"].(string); ok {
+			switch format {
+			case "date-time":
+				return "2024-01-01T00:00:00Z"
+			case "date":
+				return "2024-01-01"
+			case "email":
+				return "user@example.com"
+			case "uri", "url":
+				return "https://example.com"
+			case "uuid":
+				return "00000000-0000-0000-0000-000000000000"
+			}
+		}
+		return "<" + name + ">"
+	}
+}
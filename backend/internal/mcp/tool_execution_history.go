@@ -0,0 +1,257 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// agentProfilePath is the API path of an agent's behavioral profile
+const agentProfilePath = "/api/v1/security/agent/profile/"
+
+// ExecutionFilter holds filters for a single tool's execution history
+type ExecutionFilter struct {
+	Status string
+	UserID *uuid.UUID
+	Start  *time.Time
+	End    *time.Time
+	Limit  int
+	Cursor *ExecutionCursor // continue after this execution
+}
+
+// ExecutionCursor marks the last execution of a page in the (executed_at DESC, id ASC)
+// ordering
+type ExecutionCursor struct {
+	ExecutedAt time.Time `json:"t"`
+	ID         uuid.UUID `json:"i"`
+}
+
+// ErrInvalidExecutionCursor is returned when an execution cursor cannot be decoded
+var ErrInvalidExecutionCursor = errors.New("invalid execution cursor")
+
+// Encode returns the cursor as an opaque URL-safe string
+func (c *ExecutionCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeExecutionCursor parses a cursor produced by ExecutionCursor.Encode
+func DecodeExecutionCursor(encoded string) (*ExecutionCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidExecutionCursor
+	}
+
+	var cursor ExecutionCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == uuid.Nil {
+		return nil, ErrInvalidExecutionCursor
+	}
+	return &cursor, nil
+}
+
+// DurationPercentiles summarizes the distribution of execution durations in milliseconds
+type DurationPercentiles struct {
+	P50 float64 `json:"p50_ms"`
+	P95 float64 `json:"p95_ms"`
+	P99 float64 `json:"p99_ms"`
+}
+
+// executionAgentID returns the behavioral analysis agent ID for an execution's caller
+func executionAgentID(userID *uuid.UUID) string {
+	if userID == nil {
+		return "anonymous"
+	}
+	return userID.String()
+}
+
+// executionConditions returns the WHERE conditions and arguments selecting a tool's
+// executions that match the filter, ignoring its cursor. orgID, if set, must own the
+// servers the executions ran on.
+func executionConditions(orgID *uuid.UUID, toolID uuid.UUID, filter ExecutionFilter) ([]string, []interface{}) {
+	// Failed and running executions are found through the partial (tool_id, status) index;
+	// everything else through the (tool_id, executed_at) index, which skips rows with no
+	// execution time
+	conditions := []string{"tool_id = $1", "executed_at IS NOT NULL"}
+	args := []interface{}{toolID}
+
+	if orgID != nil {
+		args = append(args, *orgID)
+		conditions = append(conditions, fmt.Sprintf("server_id IN (SELECT id FROM mcp_servers WHERE organization_id = $%d)", len(args)))
+	}
+
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	if filter.UserID != nil {
+		args = append(args, *filter.UserID)
+		conditions = append(conditions, fmt.Sprintf("user_id = $%d", len(args)))
+	}
+
+	if filter.Start != nil {
+		args = append(args, *filter.Start)
+		conditions = append(conditions, fmt.Sprintf("executed_at >= $%d", len(args)))
+	}
+
+	if filter.End != nil {
+		args = append(args, *filter.End)
+		conditions = append(conditions, fmt.Sprintf("executed_at < $%d", len(args)))
+	}
+
+	return conditions, args
+}
+
+// ListExecutions returns a page of a tool's executions, newest first, and the cursor of
+// the next page, which is nil on the last page. Executions carry their arguments so they
+// can be replayed, and link to the behavioral profile of the agent that ran them.
+// orgID, if set, limits them to that organization's servers.
+func (tm *ToolManager) ListExecutions(ctx context.Context, orgID *uuid.UUID, toolID uuid.UUID, filter ExecutionFilter) ([]*ToolExecution, *ExecutionCursor, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	conditions, args := executionConditions(orgID, toolID, filter)
+	if filter.Cursor != nil {
+		args = append(args, filter.Cursor.ExecutedAt, filter.Cursor.ID)
+		conditions = append(conditions, fmt.Sprintf(
+			"(executed_at < $%[1]d OR (executed_at = $%[1]d AND id > $%[2]d))", len(args)-1, len(args)))
+	}
+
+	// One extra row tells whether there is a next page
+	query := fmt.Sprintf(`
+		SELECT id, tool_id, server_id, user_id, arguments, result, COALESCE(error, ''),
+		       COALESCE(EXTRACT(EPOCH FROM duration) * 1000, 0), status,
+		       result_schema_invalid, executed_at
+		FROM tool_executions
+		WHERE %s
+		ORDER BY executed_at DESC, id ASC
+		LIMIT $%d
+	`, strings.Join(conditions, " AND "), len(args)+1)
+	args = append(args, limit+1)
+
+	rows, err := tm.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query tool execution history: %w", err)
+	}
+	defer rows.Close()
+
+	executions := []*ToolExecution{}
+	for rows.Next() {
+		execution := &ToolExecution{}
+		var userID uuid.NullUUID
+		var argumentsJSON, resultJSON []byte
+		var durationMs float64
+
+		if err := rows.Scan(
+			&execution.ID,
+			&execution.ToolID,
+			&execution.ServerID,
+			&userID,
+			&argumentsJSON,
+			&resultJSON,
+			&execution.Error,
+			&durationMs,
+			&execution.Status,
+			&execution.ResultSchemaInvalid,
+			&execution.ExecutedAt,
+		); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan tool execution: %w", err)
+		}
+
+		if userID.Valid {
+			execution.UserID = &userID.UUID
+		}
+		if len(argumentsJSON) > 0 {
+			json.Unmarshal(argumentsJSON, &execution.Arguments)
+		}
+		if len(resultJSON) > 0 {
+			json.Unmarshal(resultJSON, &execution.Result)
+		}
+		execution.Duration = time.Duration(durationMs * float64(time.Millisecond))
+		execution.AgentID = executionAgentID(execution.UserID)
+		execution.AgentProfileURL = agentProfilePath + execution.AgentID
+
+		executions = append(executions, execution)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read tool execution history: %w", err)
+	}
+
+	var next *ExecutionCursor
+	if len(executions) > limit {
+		executions = executions[:limit]
+		last := executions[limit-1]
+		next = &ExecutionCursor{ExecutedAt: last.ExecutedAt, ID: last.ID}
+	}
+
+	return executions, next, nil
+}
+
+// ExecutionDurationPercentiles returns the p50, p95 and p99 durations of a tool's
+// executions matching the filter, or nil if none has a recorded duration. orgID, if set,
+// limits them to that organization's servers.
+func (tm *ToolManager) ExecutionDurationPercentiles(ctx context.Context, orgID *uuid.UUID, toolID uuid.UUID, filter ExecutionFilter) (*DurationPercentiles, error) {
+	conditions, args := executionConditions(orgID, toolID, filter)
+	conditions = append(conditions, "duration IS NOT NULL")
+
+	query := `
+		SELECT percentile_cont(ARRAY[0.5, 0.95, 0.99]) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM duration) * 1000)
+		FROM tool_executions
+		WHERE ` + strings.Join(conditions, " AND ")
+
+	var percentiles pq.Float64Array
+	if err := tm.db.QueryRowContext(ctx, query, args...).Scan(&percentiles); err != nil {
+		return nil, fmt.Errorf("failed to compute execution duration percentiles: %w", err)
+	}
+	if len(percentiles) != 3 {
+		return nil, nil
+	}
+
+	return &DurationPercentiles{
+		P50: percentiles[0],
+		P95: percentiles[1],
+		P99: percentiles[2],
+	}, nil
+}
+
+// ReplayToolExecution runs a past execution's tool again with the same arguments. The
+// replay is a new execution attributed to userID, subject to the usual quota and
+// behavioral checks. It returns sql.ErrNoRows if the execution does not exist, or orgID
+// is set and does not own the server it ran on.
+func (tm *ToolManager) ReplayToolExecution(ctx context.Context, orgID *uuid.UUID, executionID uuid.UUID, userID *uuid.UUID) (*ToolExecution, error) {
+	var toolID uuid.UUID
+	var argumentsJSON []byte
+
+	err := tm.db.QueryRowContext(ctx, `
+		SELECT e.tool_id, e.arguments
+		FROM tool_executions e
+		JOIN mcp_servers s ON s.id = e.server_id
+		WHERE e.id = $1 AND ($2::uuid IS NULL OR s.organization_id = $2)
+	`, executionID, orgID).Scan(&toolID, &argumentsJSON)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tool execution: %w", err)
+	}
+
+	var arguments map[string]interface{}
+	if len(argumentsJSON) > 0 {
+		if err := json.Unmarshal(argumentsJSON, &arguments); err != nil {
+			return nil, fmt.Errorf("failed to parse execution arguments: %w", err)
+		}
+	}
+
+	return tm.ExecuteTool(ctx, orgID, toolID, arguments, userID)
+}
@@ -0,0 +1,272 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// executionBatchSize is the number of rows fetched per query when streaming executions
+const executionBatchSize = 500
+
+// ToolExecutionFilter holds filters for querying tool execution history
+type ToolExecutionFilter struct {
+	ToolID   *uuid.UUID
+	ServerID *uuid.UUID
+	UserID   *uuid.UUID
+	Status   string
+	Start    *time.Time
+	End      *time.Time
+	Limit    int
+	Offset   int
+}
+
+// ToolExecutionRecord represents a tool execution joined with tool and server names
+type ToolExecutionRecord struct {
+	ID         uuid.UUID  `json:"id"`
+	ToolID     uuid.UUID  `json:"tool_id"`
+	ToolName   string     `json:"tool_name"`
+	ServerID   uuid.UUID  `json:"server_id"`
+	ServerName string     `json:"server_name"`
+	UserID     *uuid.UUID `json:"user_id,omitempty"`
+	Status     string     `json:"status"`
+	Error      string     `json:"error,omitempty"`
+	DurationMs float64    `json:"duration_ms"`
+	ExecutedAt time.Time  `json:"executed_at"`
+}
+
+// ListToolExecutions returns a page of tool execution history. orgID, if set, limits it
+// to that organization's servers.
+func (tm *ToolManager) ListToolExecutions(ctx context.Context, orgID *uuid.UUID, filter ToolExecutionFilter) ([]*ToolExecutionRecord, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	return tm.queryToolExecutions(ctx, orgID, filter, nil, limit, filter.Offset)
+}
+
+// StreamToolExecutions calls fn for every execution matching the filter. Rows are
+// fetched in short keyset-paginated batches so no single query or transaction
+// stays open for the whole export. orgID, if set, limits them to that organization's servers.
+func (tm *ToolManager) StreamToolExecutions(ctx context.Context, orgID *uuid.UUID, filter ToolExecutionFilter, fn func(*ToolExecutionRecord) error) error {
+	remaining := filter.Limit
+	offset := filter.Offset
+	var cursor *ToolExecutionRecord
+
+	for {
+		batchSize := executionBatchSize
+		if filter.Limit > 0 && remaining < batchSize {
+			batchSize = remaining
+		}
+		if batchSize <= 0 {
+			return nil
+		}
+
+		records, err := tm.queryToolExecutions(ctx, orgID, filter, cursor, batchSize, offset)
+		if err != nil {
+			return err
+		}
+
+		for _, record := range records {
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+
+		if len(records) < batchSize {
+			return nil
+		}
+
+		cursor = records[len(records)-1]
+		offset = 0
+		remaining -= len(records)
+	}
+}
+
+// queryToolExecutions runs a single filtered execution query, optionally continuing after cursor
+func (tm *ToolManager) queryToolExecutions(ctx context.Context, orgID *uuid.UUID, filter ToolExecutionFilter, cursor *ToolExecutionRecord, limit, offset int) ([]*ToolExecutionRecord, error) {
+	query := `
+		SELECT e.id, e.tool_id, COALESCE(t.name, ''), e.server_id, COALESCE(s.name, ''),
+		       e.user_id, e.status, COALESCE(e.error, ''),
+		       COALESCE(EXTRACT(EPOCH FROM e.duration) * 1000, 0), e.executed_at
+		FROM tool_executions e
+		LEFT JOIN mcp_tools t ON t.id = e.tool_id
+		LEFT JOIN mcp_servers s ON s.id = e.server_id
+		WHERE 1=1
+	`
+
+	args := []interface{}{}
+	argCount := 0
+
+	if orgID != nil {
+		argCount++
+		query += fmt.Sprintf(" AND s.organization_id = $%d", argCount)
+		args = append(args, *orgID)
+	}
+
+	if filter.ToolID != nil {
+		argCount++
+		query += fmt.Sprintf(" AND e.tool_id = $%d", argCount)
+		args = append(args, *filter.ToolID)
+	}
+
+	if filter.ServerID != nil {
+		argCount++
+		query += fmt.Sprintf(" AND e.server_id = $%d", argCount)
+		args = append(args, *filter.ServerID)
+	}
+
+	if filter.UserID != nil {
+		argCount++
+		query += fmt.Sprintf(" AND e.user_id = $%d", argCount)
+		args = append(args, *filter.UserID)
+	}
+
+	if filter.Status != "" {
+		argCount++
+		query += fmt.Sprintf(" AND e.status = $%d", argCount)
+		args = append(args, filter.Status)
+	}
+
+	if filter.Start != nil {
+		argCount++
+		query += fmt.Sprintf(" AND e.executed_at >= $%d", argCount)
+		args = append(args, *filter.Start)
+	}
+
+	if filter.End != nil {
+		argCount++
+		query += fmt.Sprintf(" AND e.executed_at < $%d", argCount)
+		args = append(args, *filter.End)
+	}
+
+	if cursor != nil {
+		query += fmt.Sprintf(" AND (e.executed_at, e.id) < ($%d, $%d)", argCount+1, argCount+2)
+		argCount += 2
+		args = append(args, cursor.ExecutedAt, cursor.ID)
+	}
+
+	query += fmt.Sprintf(" ORDER BY e.executed_at DESC, e.id DESC LIMIT $%d OFFSET $%d", argCount+1, argCount+2)
+	args = append(args, limit, offset)
+
+	rows, err := tm.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tool executions: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*ToolExecutionRecord
+	for rows.Next() {
+		record := &ToolExecutionRecord{}
+		var userID uuid.NullUUID
+
+		if err := rows.Scan(
+			&record.ID,
+			&record.ToolID,
+			&record.ToolName,
+			&record.ServerID,
+			&record.ServerName,
+			&userID,
+			&record.Status,
+			&record.Error,
+			&record.DurationMs,
+			&record.ExecutedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan tool execution: %w", err)
+		}
+
+		if userID.Valid {
+			record.UserID = &userID.UUID
+		}
+
+		records = append(records, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tool executions: %w", err)
+	}
+
+	return records, nil
+}
+
+// ToolExecutionAlert represents an alert raised by a tool execution
+type ToolExecutionAlert struct {
+	ID          uuid.UUID  `json:"id"`
+	ExecutionID uuid.UUID  `json:"execution_id"`
+	ServerID    *uuid.UUID `json:"server_id,omitempty"`
+	Type        string     `json:"type"`
+	Severity    string     `json:"severity"`
+	Title       string     `json:"title"`
+	Message     string     `json:"message"`
+	Resolved    bool       `json:"resolved"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// ListExecutionAlerts returns the alerts raised by a tool execution. orgID, if set, must
+// own the server it ran on; sql.ErrNoRows is returned if it does not.
+func (tm *ToolManager) ListExecutionAlerts(ctx context.Context, orgID *uuid.UUID, executionID uuid.UUID) ([]*ToolExecutionAlert, error) {
+	var exists bool
+	err := tm.db.QueryRowContext(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM tool_executions e
+			JOIN mcp_servers s ON s.id = e.server_id
+			WHERE e.id = $1 AND ($2::uuid IS NULL OR s.organization_id = $2)
+		)
+	`, executionID, orgID).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tool execution: %w", err)
+	}
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+
+	query := `
+		SELECT id, tool_execution_id, server_id, type, severity, title, message,
+		       resolved_at IS NOT NULL, created_at
+		FROM alerts
+		WHERE tool_execution_id = $1 AND ($2::uuid IS NULL OR organization_id = $2)
+		ORDER BY created_at DESC
+	`
+
+	rows, err := tm.db.QueryContext(ctx, query, executionID, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query execution alerts: %w", err)
+	}
+	defer rows.Close()
+
+	alerts := []*ToolExecutionAlert{}
+	for rows.Next() {
+		alert := &ToolExecutionAlert{}
+		var serverID uuid.NullUUID
+
+		if err := rows.Scan(
+			&alert.ID,
+			&alert.ExecutionID,
+			&serverID,
+			&alert.Type,
+			&alert.Severity,
+			&alert.Title,
+			&alert.Message,
+			&alert.Resolved,
+			&alert.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan execution alert: %w", err)
+		}
+
+		if serverID.Valid {
+			alert.ServerID = &serverID.UUID
+		}
+
+		alerts = append(alerts, alert)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read execution alerts: %w", err)
+	}
+
+	return alerts, nil
+}
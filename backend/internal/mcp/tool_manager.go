@@ -4,10 +4,17 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	apperrors "github.com/radhi1991/aran-mcp-sentinel/internal/errors"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/security"
 	"go.uber.org/zap"
 )
 
@@ -16,24 +23,45 @@ type ToolManager struct {
 	db       *sql.DB
 	logger   *zap.Logger
 	protocol *MCPProtocol
+	analyzer *security.BehavioralAnalyzer
+	scanner  *security.CredentialScanner
+
+	quotaCounter       QuotaCounter
+	repo               *database.Repository
+	defaultHourlyQuota int
+
+	maxConcurrentExecutions int
+
+	policyMu sync.RWMutex
+	policy   ToolPolicy
+
+	preferences *preferencesCache
+	schemas     *SchemaCache
+	cache       *ToolCache
 }
 
 // ManagedTool represents a tool managed by the system
 type ManagedTool struct {
-	ID          uuid.UUID              `json:"id"`
-	ServerID    uuid.UUID              `json:"server_id"`
-	ServerURL   string                 `json:"server_url"`
-	Name        string                 `json:"name"`
-	Description string                 `json:"description"`
-	InputSchema map[string]interface{} `json:"input_schema"`
-	Category    string                 `json:"category"`
-	Tags        []string               `json:"tags"`
-	RiskLevel   string                 `json:"risk_level"`
-	IsEnabled   bool                   `json:"is_enabled"`
-	UsageCount  int64                  `json:"usage_count"`
-	LastUsed    *time.Time             `json:"last_used,omitempty"`
-	CreatedAt   time.Time              `json:"created_at"`
-	UpdatedAt   time.Time              `json:"updated_at"`
+	ID           uuid.UUID              `json:"id"`
+	ServerID     uuid.UUID              `json:"server_id"`
+	ServerURL    string                 `json:"server_url"`
+	Name         string                 `json:"name"`
+	Description  string                 `json:"description"`
+	InputSchema  map[string]interface{} `json:"input_schema"`
+	OutputSchema map[string]interface{} `json:"output_schema,omitempty"`
+	Category     string                 `json:"category"`
+	Tags         []string               `json:"tags"`
+	Dependencies []string               `json:"dependencies"`
+	RiskLevel    string                 `json:"risk_level"`
+	IsEnabled    bool                   `json:"is_enabled"`
+	UsageCount   int64                  `json:"usage_count"`
+	LastUsed     *time.Time             `json:"last_used,omitempty"`
+	CreatedAt    time.Time              `json:"created_at"`
+	UpdatedAt    time.Time              `json:"updated_at"`
+
+	// organizationID owns the tool's server. It is set by GetTool, so cached tools
+	// can be checked against the caller's organization.
+	organizationID uuid.UUID
 }
 
 // ToolExecution represents a tool execution record
@@ -48,6 +76,16 @@ type ToolExecution struct {
 	Duration   time.Duration          `json:"duration"`
 	Status     string                 `json:"status"`
 	ExecutedAt time.Time              `json:"executed_at"`
+
+	// ResultSchemaInvalid is set when the result does not match the tool's output schema
+	ResultSchemaInvalid bool `json:"result_schema_invalid"`
+
+	// DeprecationWarning is set when the tool's server has been deprecated
+	DeprecationWarning string `json:"deprecation_warning,omitempty"`
+
+	// AgentID identifies the behavioral profile of the caller, which AgentProfileURL points to
+	AgentID         string `json:"agent_id,omitempty"`
+	AgentProfileURL string `json:"agent_profile_url,omitempty"`
 }
 
 // ToolCategory represents a tool category
@@ -69,15 +107,48 @@ type ToolUsageStats struct {
 	PopularityScore  float64   `json:"popularity_score"`
 }
 
+// ToolRiskSummary counts tools by risk level and enabled state
+type ToolRiskSummary struct {
+	High     int64 `json:"high"`
+	Medium   int64 `json:"medium"`
+	Low      int64 `json:"low"`
+	Total    int64 `json:"total"`
+	Enabled  int64 `json:"enabled"`
+	Disabled int64 `json:"disabled"`
+}
+
 // NewToolManager creates a new tool manager
 func NewToolManager(db *sql.DB, logger *zap.Logger) *ToolManager {
 	return &ToolManager{
 		db:       db,
 		logger:   logger,
 		protocol: NewMCPProtocol(logger),
+		analyzer: security.NewBehavioralAnalyzer(),
+		scanner:  security.NewCredentialScanner(),
+
+		preferences: newPreferencesCache(),
+		schemas:     NewSchemaCache(),
+		cache:       NewToolCache(DefaultToolCacheTTL),
 	}
 }
 
+// SetToolCacheTTL replaces the tool cache with an empty one whose entries live for ttl.
+// It is meant to be called during setup, before the manager serves requests.
+func (tm *ToolManager) SetToolCacheTTL(ttl time.Duration) {
+	tm.cache = NewToolCache(ttl)
+}
+
+// SetProtocol replaces the client used to reach MCP servers. It is meant to be called
+// during setup, before the manager serves requests.
+func (tm *ToolManager) SetProtocol(protocol *MCPProtocol) {
+	tm.protocol = protocol
+}
+
+// ToolCacheStats returns the hit and miss counts of the tool cache
+func (tm *ToolManager) ToolCacheStats() ToolCacheStats {
+	return tm.cache.Stats()
+}
+
 // DiscoverTools discovers and catalogs tools from an MCP server
 func (tm *ToolManager) DiscoverTools(ctx context.Context, serverID uuid.UUID, serverURL string) ([]*ManagedTool, error) {
 	tm.logger.Info("Discovering tools from MCP server",
@@ -85,6 +156,10 @@ func (tm *ToolManager) DiscoverTools(ctx context.Context, serverID uuid.UUID, se
 		zap.String("url", serverURL),
 	)
 
+	if err := tm.configureServerTLS(ctx, serverID, serverURL); err != nil {
+		return nil, err
+	}
+
 	// Initialize connection to server
 	_, err := tm.protocol.Initialize(ctx, serverURL)
 	if err != nil {
@@ -97,24 +172,41 @@ func (tm *ToolManager) DiscoverTools(ctx context.Context, serverID uuid.UUID, se
 		return nil, fmt.Errorf("failed to list tools: %w", err)
 	}
 
+	// Warm up pooled connections for subsequent tool calls
+	if err := tm.protocol.WarmConnections(ctx, serverURL, 2); err != nil {
+		tm.logger.Debug("Failed to warm MCP connections", zap.String("url", serverURL), zap.Error(err))
+	}
+
+	return tm.RecordTools(serverID, serverURL, mcpTools), nil
+}
+
+// RecordTools categorizes a server's tools and upserts them into mcp_tools, returning
+// the tools that were stored
+func (tm *ToolManager) RecordTools(serverID uuid.UUID, serverURL string, mcpTools []MCPTool) []*ManagedTool {
 	var managedTools []*ManagedTool
 
+	// Cache input schemas first so $refs between tools resolve
+	for _, mcpTool := range mcpTools {
+		tm.schemas.Add(mcpTool.InputSchema)
+	}
+
 	// Process each discovered tool
 	for _, mcpTool := range mcpTools {
 		managedTool := &ManagedTool{
-			ID:          uuid.New(),
-			ServerID:    serverID,
-			ServerURL:   serverURL,
-			Name:        mcpTool.Name,
-			Description: mcpTool.Description,
-			InputSchema: mcpTool.InputSchema,
-			Category:    tm.categorizeTool(mcpTool.Name, mcpTool.Description),
-			Tags:        tm.extractTags(mcpTool.Name, mcpTool.Description),
-			RiskLevel:   tm.assessRiskLevel(mcpTool.Name, mcpTool.InputSchema),
-			IsEnabled:   true,
-			UsageCount:  0,
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
+			ID:           uuid.New(),
+			ServerID:     serverID,
+			ServerURL:    serverURL,
+			Name:         mcpTool.Name,
+			Description:  mcpTool.Description,
+			InputSchema:  mcpTool.InputSchema,
+			OutputSchema: mcpTool.ResultSchema(),
+			Category:     tm.categorizeTool(mcpTool.Name, mcpTool.Description),
+			Tags:         tm.extractTags(mcpTool.Name, mcpTool.Description),
+			RiskLevel:    tm.assessRiskLevel(mcpTool.Name, mcpTool.InputSchema),
+			IsEnabled:    true,
+			UsageCount:   0,
+			CreatedAt:    time.Now(),
+			UpdatedAt:    time.Now(),
 		}
 
 		// Store in database
@@ -136,19 +228,24 @@ func (tm *ToolManager) DiscoverTools(ctx context.Context, serverID uuid.UUID, se
 		)
 	}
 
-	return managedTools, nil
+	return managedTools
 }
 
-// ExecuteTool executes a tool on its MCP server
-func (tm *ToolManager) ExecuteTool(ctx context.Context, toolID uuid.UUID, arguments map[string]interface{}, userID *uuid.UUID) (*ToolExecution, error) {
+// ExecuteTool executes a tool on its MCP server. orgID, if set, must own the tool's server.
+func (tm *ToolManager) ExecuteTool(ctx context.Context, orgID *uuid.UUID, toolID uuid.UUID, arguments map[string]interface{}, userID *uuid.UUID) (*ToolExecution, error) {
 	// Get tool information
-	tool, err := tm.GetTool(toolID)
+	tool, err := tm.GetTool(orgID, toolID)
 	if err != nil {
 		return nil, fmt.Errorf("tool not found: %w", err)
 	}
 
 	if !tool.IsEnabled {
-		return nil, fmt.Errorf("tool is disabled: %s", tool.Name)
+		return nil, &apperrors.ValidationError{Field: "tool_id", Message: fmt.Sprintf("tool %s is disabled", tool.Name)}
+	}
+
+	if err := tm.ToolPolicy().Evaluate(tool.Name); err != nil {
+		tm.recordPolicyDenied(tool, arguments, userID, err)
+		return nil, err
 	}
 
 	// Validate arguments against schema
@@ -156,6 +253,26 @@ func (tm *ToolManager) ExecuteTool(ctx context.Context, toolID uuid.UUID, argume
 		return nil, fmt.Errorf("invalid arguments: %w", err)
 	}
 
+	// Apply the organization's tool settings
+	settings := tm.orgSettings(ctx, tool.ServerID)
+	if !settings.AllowsToolCategory(tool.Category) {
+		return nil, fmt.Errorf("tool category %s is not allowed for this organization", tool.Category)
+	}
+
+	if err := tm.checkToolQuota(ctx, toolID); err != nil {
+		if errors.Is(err, ErrQuotaExceeded) {
+			tm.recordQuotaExceeded(tool, arguments, userID, err)
+		}
+		return nil, err
+	}
+
+	if err := tm.checkDailyLimit(ctx, tool.ServerID); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, tm.executionTimeout(ctx, settings, userID))
+	defer cancel()
+
 	// Create execution record
 	execution := &ToolExecution{
 		ID:         uuid.New(),
@@ -165,14 +282,42 @@ func (tm *ToolManager) ExecuteTool(ctx context.Context, toolID uuid.UUID, argume
 		Arguments:  arguments,
 		Status:     "running",
 		ExecutedAt: time.Now(),
+		AgentID:    executionAgentID(userID),
+	}
+	execution.AgentProfileURL = agentProfilePath + execution.AgentID
+
+	if deprecation, err := tm.ServerDeprecation(ctx, nil, tool.ServerID); err != nil {
+		tm.logger.Warn("Failed to check server deprecation", zap.Error(err))
+	} else if deprecation != nil {
+		execution.DeprecationWarning = deprecation.Warning()
+	}
+
+	// Check the caller's behavior before running the tool
+	var analysis *security.BehavioralAnalysisResult
+	if settings.EnableBehavioralAnalysis {
+		analysis = tm.analyzer.AnalyzeAgentBehavior(execution.AgentID, tool.Name, arguments)
 	}
 
 	start := time.Now()
 
 	// Execute tool on MCP server
-	result, err := tm.protocol.CallTool(ctx, tool.ServerURL, tool.Name, arguments)
+	result, err := tm.callTool(ctx, tool, arguments)
 	execution.Duration = time.Since(start)
 
+	if err == nil {
+		if schemaErr := validateToolOutput(tool, result); schemaErr != nil {
+			execution.ResultSchemaInvalid = true
+			tm.logger.Warn("Tool result does not match output schema",
+				zap.String("tool_name", tool.Name),
+				zap.Error(schemaErr),
+			)
+			if settings.StrictOutputValidation {
+				execution.Result = result
+				err = fmt.Errorf("tool result does not match output schema: %w", schemaErr)
+			}
+		}
+	}
+
 	if err != nil {
 		execution.Status = "failed"
 		execution.Error = err.Error()
@@ -189,32 +334,167 @@ func (tm *ToolManager) ExecuteTool(ctx context.Context, toolID uuid.UUID, argume
 		)
 	}
 
+	// Keep credentials returned by the tool out of the stored and returned result
+	if execution.Result != nil {
+		var found int
+		if execution.Result, found = tm.scanner.MaskValue(execution.Result); found > 0 {
+			tm.logger.Warn("Masked credentials in tool result",
+				zap.String("tool_name", tool.Name),
+				zap.Int("credentials", found),
+			)
+		}
+	}
+
 	// Store execution record
 	if storeErr := tm.storeExecution(execution); storeErr != nil {
 		tm.logger.Error("Failed to store execution record", zap.Error(storeErr))
+	} else if analysis != nil && analysis.IsAnomalous {
+		tm.generateAlert(execution, tool, analysis)
 	}
+	tm.cache.invalidateUsageStats(toolID)
 
 	// Update tool usage statistics
 	if updateErr := tm.updateToolUsage(toolID); updateErr != nil {
 		tm.logger.Error("Failed to update tool usage", zap.Error(updateErr))
+		tm.cache.invalidate(toolID)
 	}
 
 	return execution, err
 }
 
-// GetTool retrieves a tool by ID
-func (tm *ToolManager) GetTool(toolID uuid.UUID) (*ManagedTool, error) {
+// callTool calls a tool over the transport its server's metadata prefers
+func (tm *ToolManager) callTool(ctx context.Context, tool *ManagedTool, arguments map[string]interface{}) (interface{}, error) {
+	if tm.serverTransport(ctx, tool.ServerID) == TransportSSE {
+		return tm.protocol.callToolStreamed(ctx, tool.ServerURL, toolCallRequest(tool.Name, arguments))
+	}
+	return tm.protocol.CallTool(ctx, tool.ServerURL, tool.Name, arguments)
+}
+
+// configureServerTLS applies the mutual TLS files set in a server's metadata
+func (tm *ToolManager) configureServerTLS(ctx context.Context, serverID uuid.UUID, serverURL string) error {
+	files, err := LoadServerTLSFiles(ctx, tm.db, serverID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := tm.protocol.ConfigureServerTLS(serverURL, files); err != nil {
+		return fmt.Errorf("failed to configure TLS for MCP server: %w", err)
+	}
+	return nil
+}
+
+// serverTransport returns the transport set in a server's metadata, or an empty string
+// if it has none
+func (tm *ToolManager) serverTransport(ctx context.Context, serverID uuid.UUID) string {
+	var transport string
+	query := `SELECT COALESCE(metadata->>$2, '') FROM mcp_servers WHERE id = $1`
+	if err := tm.db.QueryRowContext(ctx, query, serverID, TransportMetadataKey).Scan(&transport); err != nil {
+		if err != sql.ErrNoRows {
+			tm.logger.Warn("Failed to get server transport", zap.Error(err))
+		}
+		return ""
+	}
+	return transport
+}
+
+// ServerDeprecation describes a deprecated server
+type ServerDeprecation struct {
+	DeprecatedAt        *time.Time `json:"deprecated_at,omitempty"`
+	Message             string     `json:"message,omitempty"`
+	ReplacementServerID *uuid.UUID `json:"replacement_server_id,omitempty"`
+}
+
+// Warning returns a human-readable deprecation warning
+func (d *ServerDeprecation) Warning() string {
+	warning := "This server is deprecated."
+	if d.Message != "" {
+		warning += " " + d.Message
+	}
+	if d.ReplacementServerID != nil {
+		warning += fmt.Sprintf(" Use server %s instead.", d.ReplacementServerID)
+	}
+	return warning
+}
+
+// ServerDeprecation returns the deprecation details of a server, or nil if it is not
+// deprecated. orgID, if set, must own the server; sql.ErrNoRows is returned if it does not.
+func (tm *ToolManager) ServerDeprecation(ctx context.Context, orgID *uuid.UUID, serverID uuid.UUID) (*ServerDeprecation, error) {
 	query := `
-		SELECT id, server_id, server_url, name, description, input_schema, category, 
-		       tags, risk_level, is_enabled, usage_count, last_used, created_at, updated_at
-		FROM mcp_tools 
-		WHERE id = $1 AND deleted_at IS NULL
+		SELECT is_deprecated, deprecated_at, deprecation_message, replacement_server_id
+		FROM mcp_servers
+		WHERE id = $1 AND ($2::uuid IS NULL OR organization_id = $2)
 	`
 
-	row := tm.db.QueryRow(query, toolID)
-	
+	var deprecated bool
+	var deprecatedAt sql.NullTime
+	var replacementID uuid.NullUUID
+	deprecation := &ServerDeprecation{}
+
+	err := tm.db.QueryRowContext(ctx, query, serverID, orgID).Scan(&deprecated, &deprecatedAt, &deprecation.Message, &replacementID)
+	if err != nil {
+		return nil, err
+	}
+	if !deprecated {
+		return nil, nil
+	}
+
+	if deprecatedAt.Valid {
+		deprecation.DeprecatedAt = &deprecatedAt.Time
+	}
+	if replacementID.Valid {
+		deprecation.ReplacementServerID = &replacementID.UUID
+	}
+
+	return deprecation, nil
+}
+
+// orgSettings returns the settings of the organization owning a server, falling back to defaults
+func (tm *ToolManager) orgSettings(ctx context.Context, serverID uuid.UUID) database.OrgSettings {
+	query := `
+		SELECT o.settings
+		FROM organizations o
+		JOIN mcp_servers s ON s.organization_id = o.id
+		WHERE s.id = $1
+	`
+
+	var settings database.JSONB
+	if err := tm.db.QueryRowContext(ctx, query, serverID).Scan(&settings); err != nil {
+		tm.logger.Warn("Failed to load organization settings, using defaults",
+			zap.String("server_id", serverID.String()),
+			zap.Error(err),
+		)
+		return database.DefaultOrgSettings()
+	}
+
+	return database.OrgSettingsFromJSONB(settings)
+}
+
+// GetTool retrieves a tool by ID, from the tool cache when it holds it. orgID, if set,
+// must own the tool's server; a NotFoundError is returned if it does not.
+func (tm *ToolManager) GetTool(orgID *uuid.UUID, toolID uuid.UUID) (*ManagedTool, error) {
+	if tool, ok := tm.cache.getTool(toolID); ok {
+		if orgID != nil && tool.organizationID != *orgID {
+			return nil, &apperrors.NotFoundError{Resource: "tool", ID: toolID.String()}
+		}
+		return tool, nil
+	}
+
+	query := `
+		SELECT t.id, t.server_id, t.server_url, t.name, t.description, t.input_schema, t.output_schema, t.category,
+		       t.tags, t.dependencies, t.risk_level, t.is_enabled, t.usage_count, t.last_used, t.created_at, t.updated_at,
+		       s.organization_id
+		FROM mcp_tools t
+		JOIN mcp_servers s ON s.id = t.server_id
+		WHERE t.id = $1 AND t.deleted_at IS NULL AND ($2::uuid IS NULL OR s.organization_id = $2)
+	`
+
+	row := tm.db.QueryRow(query, toolID, orgID)
+
 	tool := &ManagedTool{}
-	var inputSchemaJSON, tagsJSON []byte
+	var inputSchemaJSON, outputSchemaJSON, tagsJSON, dependenciesJSON []byte
 	var lastUsed sql.NullTime
 
 	err := row.Scan(
@@ -224,16 +504,22 @@ func (tm *ToolManager) GetTool(toolID uuid.UUID) (*ManagedTool, error) {
 		&tool.Name,
 		&tool.Description,
 		&inputSchemaJSON,
+		&outputSchemaJSON,
 		&tool.Category,
 		&tagsJSON,
+		&dependenciesJSON,
 		&tool.RiskLevel,
 		&tool.IsEnabled,
 		&tool.UsageCount,
 		&lastUsed,
 		&tool.CreatedAt,
 		&tool.UpdatedAt,
+		&tool.organizationID,
 	)
 
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, &apperrors.NotFoundError{Resource: "tool", ID: toolID.String()}
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -243,65 +529,116 @@ func (tm *ToolManager) GetTool(toolID uuid.UUID) (*ManagedTool, error) {
 		tm.logger.Warn("Failed to parse input schema", zap.Error(err))
 	}
 
+	if len(outputSchemaJSON) > 0 {
+		if err := json.Unmarshal(outputSchemaJSON, &tool.OutputSchema); err != nil {
+			tm.logger.Warn("Failed to parse output schema", zap.Error(err))
+		}
+	}
+
 	if err := json.Unmarshal(tagsJSON, &tool.Tags); err != nil {
 		tm.logger.Warn("Failed to parse tags", zap.Error(err))
 	}
 
+	if err := json.Unmarshal(dependenciesJSON, &tool.Dependencies); err != nil {
+		tm.logger.Warn("Failed to parse dependencies", zap.Error(err))
+	}
+
 	if lastUsed.Valid {
 		tool.LastUsed = &lastUsed.Time
 	}
 
+	tm.cache.setTool(tool)
 	return tool, nil
 }
 
-// ListTools returns all tools with optional filtering
-func (tm *ToolManager) ListTools(serverID *uuid.UUID, category, riskLevel string, enabled *bool) ([]*ManagedTool, error) {
+// ListToolsPage lists managed tools, most used first, a page at a time. Pass the
+// returned cursor to get the next page; it is nil on the last page. orgID, if set,
+// limits the tools to that organization's servers.
+func (tm *ToolManager) ListToolsPage(ctx context.Context, orgID *uuid.UUID, filter ToolFilter, cursor *ToolCursor, limit int) ([]*ManagedTool, *ToolCursor, error) {
+	if limit <= 0 {
+		limit = DefaultToolPageSize
+	}
+	if limit > MaxToolPageSize {
+		limit = MaxToolPageSize
+	}
+
 	query := `
-		SELECT id, server_id, server_url, name, description, input_schema, category, 
-		       tags, risk_level, is_enabled, usage_count, last_used, created_at, updated_at
-		FROM mcp_tools 
+		SELECT id, server_id, server_url, name, description, input_schema, output_schema, category,
+		       tags, dependencies, risk_level, is_enabled, usage_count, last_used, created_at, updated_at
+		FROM mcp_tools
 		WHERE deleted_at IS NULL
 	`
-	
+
 	args := []interface{}{}
 	argCount := 0
 
-	if serverID != nil {
+	if orgID != nil {
+		argCount++
+		query += fmt.Sprintf(" AND server_id IN (SELECT id FROM mcp_servers WHERE organization_id = $%d)", argCount)
+		args = append(args, *orgID)
+	}
+
+	if filter.ServerID != nil {
 		argCount++
 		query += fmt.Sprintf(" AND server_id = $%d", argCount)
-		args = append(args, *serverID)
+		args = append(args, *filter.ServerID)
 	}
 
-	if category != "" {
+	if filter.Category != "" {
 		argCount++
 		query += fmt.Sprintf(" AND category = $%d", argCount)
-		args = append(args, category)
+		args = append(args, filter.Category)
 	}
 
-	if riskLevel != "" {
+	if filter.RiskLevel != "" {
 		argCount++
 		query += fmt.Sprintf(" AND risk_level = $%d", argCount)
-		args = append(args, riskLevel)
+		args = append(args, filter.RiskLevel)
 	}
 
-	if enabled != nil {
+	if filter.Enabled != nil {
 		argCount++
 		query += fmt.Sprintf(" AND is_enabled = $%d", argCount)
-		args = append(args, *enabled)
+		args = append(args, *filter.Enabled)
+	}
+
+	if tags := normalizeTags(filter.Tags); len(tags) > 0 {
+		query += fmt.Sprintf(` AND id IN (
+			SELECT tool_id FROM tool_tags WHERE tag = ANY($%d)
+			GROUP BY tool_id HAVING COUNT(*) = $%d
+		)`, argCount+1, argCount+2)
+		args = append(args, pq.Array(tags), len(tags))
+		argCount += 2
+	}
+
+	if cursor != nil {
+		query += fmt.Sprintf(" AND (usage_count < $%d OR (usage_count = $%d AND id > $%d))", argCount+1, argCount+1, argCount+2)
+		args = append(args, cursor.UsageCount, cursor.ID)
+		argCount += 2
 	}
 
-	query += " ORDER BY usage_count DESC, name ASC"
+	// One extra row tells whether there is a next page
+	argCount++
+	query += fmt.Sprintf(" ORDER BY usage_count DESC, id ASC LIMIT $%d", argCount)
+	args = append(args, limit+1)
 
-	rows, err := tm.db.Query(query, args...)
+	rows, err := tm.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer rows.Close()
 
 	var tools []*ManagedTool
+	var last ToolCursor
+	fetched := 0
 	for rows.Next() {
+		fetched++
+		if fetched > limit {
+			break
+		}
+
 		tool := &ManagedTool{}
-		var inputSchemaJSON, tagsJSON []byte
+		var inputSchemaJSON, outputSchemaJSON, tagsJSON, dependenciesJSON []byte
 		var lastUsed sql.NullTime
 
 		err := rows.Scan(
@@ -311,8 +648,10 @@ func (tm *ToolManager) ListTools(serverID *uuid.UUID, category, riskLevel string
 			&tool.Name,
 			&tool.Description,
 			&inputSchemaJSON,
+			&outputSchemaJSON,
 			&tool.Category,
 			&tagsJSON,
+			&dependenciesJSON,
 			&tool.RiskLevel,
 			&tool.IsEnabled,
 			&tool.UsageCount,
@@ -324,10 +663,15 @@ func (tm *ToolManager) ListTools(serverID *uuid.UUID, category, riskLevel string
 		if err != nil {
 			continue
 		}
+		last = ToolCursor{UsageCount: tool.UsageCount, ID: tool.ID}
 
 		// Parse JSON fields
 		json.Unmarshal(inputSchemaJSON, &tool.InputSchema)
+		if len(outputSchemaJSON) > 0 {
+			json.Unmarshal(outputSchemaJSON, &tool.OutputSchema)
+		}
 		json.Unmarshal(tagsJSON, &tool.Tags)
+		json.Unmarshal(dependenciesJSON, &tool.Dependencies)
 
 		if lastUsed.Valid {
 			tool.LastUsed = &lastUsed.Time
@@ -335,12 +679,31 @@ func (tm *ToolManager) ListTools(serverID *uuid.UUID, category, riskLevel string
 
 		tools = append(tools, tool)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
 
-	return tools, nil
+	var next *ToolCursor
+	if fetched > limit && last.ID != uuid.Nil {
+		next = &last
+	}
+
+	return tools, next, nil
 }
 
-// GetToolUsageStats returns usage statistics for a tool
-func (tm *ToolManager) GetToolUsageStats(toolID uuid.UUID) (*ToolUsageStats, error) {
+// GetToolUsageStats returns usage statistics for a tool. orgID, if set, must own the
+// tool's server; a NotFoundError is returned if it does not.
+func (tm *ToolManager) GetToolUsageStats(orgID *uuid.UUID, toolID uuid.UUID) (*ToolUsageStats, error) {
+	if orgID != nil {
+		if _, err := tm.GetTool(orgID, toolID); err != nil {
+			return nil, err
+		}
+	}
+
+	if stats, ok := tm.cache.getUsageStats(toolID); ok {
+		return stats, nil
+	}
+
 	query := `
 		SELECT 
 			COUNT(*) as total_executions,
@@ -389,11 +752,68 @@ func (tm *ToolManager) GetToolUsageStats(toolID uuid.UUID) (*ToolUsageStats, err
 		stats.PopularityScore = float64(stats.TotalExecutions) * successRate * recencyFactor
 	}
 
+	tm.cache.setUsageStats(stats)
 	return stats, nil
 }
 
-// categorizeTools automatically categorizes a tool based on its name and description
-func (tm *ToolManager) categorizeTools(name, description string) string {
+// GetToolRiskSummary counts tools by risk level. A nil orgID counts tools across all organizations.
+func (tm *ToolManager) GetToolRiskSummary(ctx context.Context, orgID *uuid.UUID) (*ToolRiskSummary, error) {
+	query := `
+		SELECT t.risk_level, t.is_enabled, COUNT(*)
+		FROM mcp_tools t
+		JOIN mcp_servers s ON s.id = t.server_id
+		WHERE s.deleted_at IS NULL AND ($1::uuid IS NULL OR s.organization_id = $1)
+		GROUP BY t.risk_level, t.is_enabled
+	`
+
+	rows, err := tm.db.QueryContext(ctx, query, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tools by risk level: %w", err)
+	}
+	defer rows.Close()
+
+	summary := &ToolRiskSummary{}
+	for rows.Next() {
+		var riskLevel sql.NullString
+		var enabled sql.NullBool
+		var count int64
+		if err := rows.Scan(&riskLevel, &enabled, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan tool risk count: %w", err)
+		}
+
+		switch riskLevel.String {
+		case "high":
+			summary.High += count
+		case "medium":
+			summary.Medium += count
+		default:
+			summary.Low += count
+		}
+
+		// is_enabled defaults to true
+		if enabled.Valid && !enabled.Bool {
+			summary.Disabled += count
+		} else {
+			summary.Enabled += count
+		}
+		summary.Total += count
+	}
+
+	return summary, rows.Err()
+}
+
+// BehavioralAnalyzer returns the analyzer that checks callers before tools run
+func (tm *ToolManager) BehavioralAnalyzer() *security.BehavioralAnalyzer {
+	return tm.analyzer
+}
+
+// ConnectionPoolStats returns connection pool statistics for tool calls
+func (tm *ToolManager) ConnectionPoolStats() []ConnectionPoolStats {
+	return tm.protocol.ConnectionPoolStats()
+}
+
+// categorizeTool automatically categorizes a tool based on its name and description
+func (tm *ToolManager) categorizeTool(name, description string) string {
 	name = strings.ToLower(name)
 	description = strings.ToLower(description)
 
@@ -496,74 +916,99 @@ func (tm *ToolManager) extractTags(name, description string) []string {
 	return tags
 }
 
-// validateArguments validates tool arguments against schema
+// validateArguments validates tool arguments against the tool's input schema, returning
+// ValidationErrors listing every violation. Top-level arguments the schema does not
+// declare are rejected unless it sets additionalProperties.
 func (tm *ToolManager) validateArguments(arguments map[string]interface{}, schema map[string]interface{}) error {
-	// Basic validation - in production you'd want JSON Schema validation
-	if properties, ok := schema["properties"].(map[string]interface{}); ok {
-		if required, ok := schema["required"].([]interface{}); ok {
-			for _, req := range required {
-				if reqStr, ok := req.(string); ok {
-					if _, exists := arguments[reqStr]; !exists {
-						return fmt.Errorf("required parameter missing: %s", reqStr)
-					}
-				}
+	if _, declared := schema["additionalProperties"]; !declared {
+		if _, hasProperties := schema["properties"]; hasProperties {
+			strict := make(map[string]interface{}, len(schema)+1)
+			for key, value := range schema {
+				strict[key] = value
 			}
+			strict["additionalProperties"] = false
+			schema = strict
 		}
+	}
 
-		// Check for unknown parameters
-		for arg := range arguments {
-			if _, exists := properties[arg]; !exists {
-				return fmt.Errorf("unknown parameter: %s", arg)
-			}
-		}
+	if violations := ValidateArguments(arguments, schema, tm.schemas); len(violations) > 0 {
+		return violations
 	}
 
 	return nil
 }
 
-// storeTool stores a tool in the database
+// storeTool stores a tool and its tags in the database. Rediscovering a tool keeps its
+// dependencies.
 func (tm *ToolManager) storeTool(tool *ManagedTool) error {
 	query := `
-		INSERT INTO mcp_tools (id, server_id, server_url, name, description, input_schema, 
-		                      category, tags, risk_level, is_enabled, usage_count, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		INSERT INTO mcp_tools (id, server_id, server_url, name, description, input_schema, output_schema,
+		                      category, tags, dependencies, risk_level, is_enabled, usage_count, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 		ON CONFLICT (server_id, name) DO UPDATE SET
 			description = EXCLUDED.description,
 			input_schema = EXCLUDED.input_schema,
+			output_schema = EXCLUDED.output_schema,
 			category = EXCLUDED.category,
-			tags = EXCLUDED.tags,
 			risk_level = EXCLUDED.risk_level,
 			updated_at = EXCLUDED.updated_at
+		RETURNING id
 	`
 
 	inputSchemaJSON, _ := json.Marshal(tool.InputSchema)
-	tagsJSON, _ := json.Marshal(tool.Tags)
+	tagsJSON, _ := json.Marshal(normalizeTags(tool.Tags))
+	dependenciesJSON, _ := json.Marshal(dependencyNames(tool.Dependencies))
 
-	_, err := tm.db.Exec(query,
+	var outputSchemaJSON []byte
+	if len(tool.OutputSchema) > 0 {
+		outputSchemaJSON, _ = json.Marshal(tool.OutputSchema)
+	}
+
+	defer tm.cache.invalidateServer(tool.ServerID, tool.Name)
+
+	ctx := context.Background()
+	tx, err := tm.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// An existing tool keeps its ID, which its tags must use
+	var toolID uuid.UUID
+	err = tx.QueryRowContext(ctx, query,
 		tool.ID,
 		tool.ServerID,
 		tool.ServerURL,
 		tool.Name,
 		tool.Description,
 		inputSchemaJSON,
+		outputSchemaJSON,
 		tool.Category,
 		tagsJSON,
+		dependenciesJSON,
 		tool.RiskLevel,
 		tool.IsEnabled,
 		tool.UsageCount,
 		tool.CreatedAt,
 		tool.UpdatedAt,
-	)
+	).Scan(&toolID)
+	if err != nil {
+		return err
+	}
 
-	return err
+	if err := setToolTags(ctx, tx, toolID, tool.Tags); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 // storeExecution stores a tool execution record
 func (tm *ToolManager) storeExecution(execution *ToolExecution) error {
 	query := `
 		INSERT INTO tool_executions (id, tool_id, server_id, user_id, arguments, result, 
-		                           error, duration, status, executed_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		                           error, duration, status, result_schema_invalid, executed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	`
 
 	argumentsJSON, _ := json.Marshal(execution.Arguments)
@@ -579,20 +1024,76 @@ func (tm *ToolManager) storeExecution(execution *ToolExecution) error {
 		execution.Error,
 		execution.Duration,
 		execution.Status,
+		execution.ResultSchemaInvalid,
 		execution.ExecutedAt,
 	)
 
 	return err
 }
 
-// updateToolUsage updates tool usage statistics
+// generateAlert stores a security alert linked to the execution that triggered it
+func (tm *ToolManager) generateAlert(execution *ToolExecution, tool *ManagedTool, analysis *security.BehavioralAnalysisResult) {
+	query := `
+		INSERT INTO alerts (id, organization_id, server_id, tool_execution_id, type, severity, title, message, metadata, created_at)
+		SELECT $1, s.organization_id, s.id, $2, 'security', $3, $4, $5, $6, $7
+		FROM mcp_servers s
+		WHERE s.id = $8
+	`
+
+	descriptions := make([]string, 0, len(analysis.Anomalies))
+	for _, anomaly := range analysis.Anomalies {
+		descriptions = append(descriptions, anomaly.Description)
+	}
+
+	metadata := map[string]interface{}{
+		"anomaly_type":    analysis.AnomalyType,
+		"anomalies":       analysis.Anomalies,
+		"trust_score":     analysis.TrustScore,
+		"recommendations": analysis.Recommendations,
+	}
+	metadataJSON, _ := json.Marshal(metadata)
+
+	_, err := tm.db.Exec(query,
+		uuid.New(),
+		execution.ID,
+		analysis.Severity,
+		fmt.Sprintf("Anomalous execution of %s", tool.Name),
+		strings.Join(descriptions, "; "),
+		metadataJSON,
+		time.Now(),
+		execution.ServerID,
+	)
+	if err != nil {
+		tm.logger.Error("Failed to store execution alert", zap.Error(err))
+		return
+	}
+
+	tm.logger.Warn("Anomalous tool execution detected",
+		zap.String("execution_id", execution.ID.String()),
+		zap.String("tool_name", tool.Name),
+		zap.String("severity", analysis.Severity),
+	)
+}
+
+// updateToolUsage updates tool usage statistics, writing them through to the tool cache
 func (tm *ToolManager) updateToolUsage(toolID uuid.UUID) error {
 	query := `
-		UPDATE mcp_tools 
+		UPDATE mcp_tools
 		SET usage_count = usage_count + 1, last_used = NOW(), updated_at = NOW()
 		WHERE id = $1
+		RETURNING usage_count, last_used, updated_at
 	`
 
-	_, err := tm.db.Exec(query, toolID)
-	return err
+	var usageCount int64
+	var lastUsed, updatedAt time.Time
+	if err := tm.db.QueryRow(query, toolID).Scan(&usageCount, &lastUsed, &updatedAt); err != nil {
+		return err
+	}
+
+	tm.cache.updateTool(toolID, func(tool *ManagedTool) {
+		tool.UsageCount = usageCount
+		tool.LastUsed = &lastUsed
+		tool.UpdatedAt = updatedAt
+	})
+	return nil
 }
\ No newline at end of file
@@ -0,0 +1,91 @@
+//go:build integration
+
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database/dbtest"
+	"go.uber.org/zap"
+)
+
+// TestToolManagerOrganizationIsolation checks that a tool of one organization and its
+// executions cannot be read or changed through another organization
+func TestToolManagerOrganizationIsolation(t *testing.T) {
+	ctx := context.Background()
+	conn := dbtest.Connect(t)
+	repo := dbtest.Repository(t)
+
+	owner := dbtest.CreateOrganization(t, repo)
+	other := dbtest.CreateOrganization(t, repo)
+	user := dbtest.CreateUser(t, repo, owner.ID, "admin")
+	server := dbtest.CreateServer(t, repo, owner.ID, user.ID, "isolation")
+
+	toolID, executionID := uuid.New(), uuid.New()
+	if _, err := conn.DB.ExecContext(ctx,
+		`INSERT INTO mcp_tools (id, server_id, server_url, name) VALUES ($1, $2, $3, 'read_file')`,
+		toolID, server.ID, server.URL); err != nil {
+		t.Fatalf("failed to insert tool: %v", err)
+	}
+	if _, err := conn.DB.ExecContext(ctx,
+		`INSERT INTO tool_executions (id, tool_id, server_id, status) VALUES ($1, $2, $3, 'completed')`,
+		executionID, toolID, server.ID); err != nil {
+		t.Fatalf("failed to insert tool execution: %v", err)
+	}
+
+	tm := NewToolManager(conn.DB.DB, zap.NewNop())
+	ownerID, otherID := owner.ID, other.ID
+
+	if _, err := tm.GetTool(&ownerID, toolID); err != nil {
+		t.Fatalf("GetTool() by owner error = %v", err)
+	}
+	// The owner's lookup cached the tool; the cache must not serve it to others
+	if _, err := tm.GetTool(&otherID, toolID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetTool() by other organization error = %v, want not found", err)
+	}
+
+	tools, _, err := tm.ListToolsPage(ctx, &otherID, ToolFilter{}, nil, 100)
+	if err != nil {
+		t.Fatalf("ListToolsPage() error = %v", err)
+	}
+	if len(tools) != 0 {
+		t.Errorf("ListToolsPage() by other organization returned %d tools, want 0", len(tools))
+	}
+
+	executions, err := tm.ListToolExecutions(ctx, &otherID, ToolExecutionFilter{})
+	if err != nil {
+		t.Fatalf("ListToolExecutions() error = %v", err)
+	}
+	if len(executions) != 0 {
+		t.Errorf("ListToolExecutions() by other organization returned %d executions, want 0", len(executions))
+	}
+	executions, err = tm.ListToolExecutions(ctx, &ownerID, ToolExecutionFilter{})
+	if err != nil {
+		t.Fatalf("ListToolExecutions() error = %v", err)
+	}
+	if len(executions) != 1 || executions[0].ID != executionID {
+		t.Errorf("ListToolExecutions() by owner = %d executions, want execution %s", len(executions), executionID)
+	}
+
+	if _, err := tm.ListExecutionAlerts(ctx, &otherID, executionID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("ListExecutionAlerts() by other organization error = %v, want sql.ErrNoRows", err)
+	}
+	if _, err := tm.AddTagsToTool(ctx, &otherID, toolID, []string{"stolen"}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("AddTagsToTool() by other organization error = %v, want not found", err)
+	}
+	if _, err := tm.SetToolQuota(ctx, &otherID, toolID, nil, nil); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("SetToolQuota() by other organization error = %v, want sql.ErrNoRows", err)
+	}
+
+	result, err := tm.BulkSetEnabled(ctx, &otherID, []uuid.UUID{toolID}, false, nil)
+	if err != nil {
+		t.Fatalf("BulkSetEnabled() error = %v", err)
+	}
+	if result.Updated != 0 {
+		t.Errorf("BulkSetEnabled() by other organization updated %d tools, want 0", result.Updated)
+	}
+}
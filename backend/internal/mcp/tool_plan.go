@@ -0,0 +1,200 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// ErrCyclicDependency is returned when tool dependencies form a cycle
+var ErrCyclicDependency = errors.New("cyclic tool dependency")
+
+// ErrUnknownTool is returned when a plan names a tool, or a tool depends on one, that
+// the server does not have
+var ErrUnknownTool = errors.New("unknown tool")
+
+// BuildExecutionPlan orders the named tools of a server, together with the tools they
+// depend on, into execution stages. Every tool comes after all of its dependencies, so
+// the tools of one stage can run in parallel once the previous stages have succeeded.
+// Tools within a stage are sorted by name. orgID, if set, must own the server;
+// sql.ErrNoRows is returned if it does not exist.
+func (tm *ToolManager) BuildExecutionPlan(ctx context.Context, orgID *uuid.UUID, serverID uuid.UUID, toolNames []string) ([][]string, error) {
+	var exists bool
+	err := tm.db.QueryRowContext(ctx, `
+		SELECT true FROM mcp_servers
+		WHERE id = $1 AND deleted_at IS NULL AND ($2::uuid IS NULL OR organization_id = $2)
+	`, serverID, orgID).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server: %w", err)
+	}
+
+	rows, err := tm.db.QueryContext(ctx, `
+		SELECT name, dependencies FROM mcp_tools WHERE server_id = $1 AND deleted_at IS NULL
+	`, serverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tool dependencies: %w", err)
+	}
+	defer rows.Close()
+
+	graph := make(map[string][]string)
+	for rows.Next() {
+		var name string
+		var dependenciesJSON []byte
+		if err := rows.Scan(&name, &dependenciesJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan tool dependencies: %w", err)
+		}
+		var dependencies []string
+		if err := json.Unmarshal(dependenciesJSON, &dependencies); err != nil {
+			tm.logger.Warn("Failed to parse dependencies", zap.String("tool", name), zap.Error(err))
+		}
+		graph[name] = dependencies
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get tool dependencies: %w", err)
+	}
+
+	return planStages(graph, toolNames)
+}
+
+// planStages topologically sorts the requested tools and their transitive dependencies
+// with Kahn's algorithm. graph maps each known tool to the tools it depends on.
+func planStages(graph map[string][]string, toolNames []string) ([][]string, error) {
+	// Collect the requested tools and everything they need
+	needed := make(map[string]bool)
+	pending := append([]string(nil), toolNames...)
+	for len(pending) > 0 {
+		name := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if needed[name] {
+			continue
+		}
+		dependencies, ok := graph[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownTool, name)
+		}
+		needed[name] = true
+		pending = append(pending, dependencies...)
+	}
+
+	inDegree := make(map[string]int, len(needed))
+	dependents := make(map[string][]string, len(needed))
+	for name := range needed {
+		seen := make(map[string]bool)
+		for _, dependency := range graph[name] {
+			if seen[dependency] {
+				continue
+			}
+			seen[dependency] = true
+			inDegree[name]++
+			dependents[dependency] = append(dependents[dependency], name)
+		}
+	}
+
+	var stage []string
+	for name := range needed {
+		if inDegree[name] == 0 {
+			stage = append(stage, name)
+		}
+	}
+
+	stages := [][]string{}
+	planned := 0
+	for len(stage) > 0 {
+		sort.Strings(stage)
+		stages = append(stages, stage)
+		planned += len(stage)
+
+		var next []string
+		for _, name := range stage {
+			for _, dependent := range dependents[name] {
+				inDegree[dependent]--
+				if inDegree[dependent] == 0 {
+					next = append(next, dependent)
+				}
+			}
+		}
+		stage = next
+	}
+
+	if planned < len(needed) {
+		var cyclic []string
+		for name := range needed {
+			if inDegree[name] > 0 {
+				cyclic = append(cyclic, name)
+			}
+		}
+		sort.Strings(cyclic)
+		return nil, fmt.Errorf("%w involving %s", ErrCyclicDependency, strings.Join(cyclic, ", "))
+	}
+
+	return stages, nil
+}
+
+// SetToolDependencies replaces the names of the tools a tool depends on. The
+// dependencies must be other tools of the same server. sql.ErrNoRows is returned if the
+// tool does not exist, or orgID is set and does not own its server.
+func (tm *ToolManager) SetToolDependencies(ctx context.Context, orgID *uuid.UUID, toolID uuid.UUID, dependencies []string) (*ManagedTool, error) {
+	tool, err := tm.GetTool(orgID, toolID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tool: %w", err)
+	}
+
+	dependencies = dependencyNames(dependencies)
+	for _, dependency := range dependencies {
+		if dependency == tool.Name {
+			return nil, fmt.Errorf("%w: %s depends on itself", ErrCyclicDependency, tool.Name)
+		}
+	}
+
+	if len(dependencies) > 0 {
+		var found int
+		err = tm.db.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM mcp_tools
+			WHERE server_id = $1 AND name = ANY($2) AND deleted_at IS NULL
+		`, tool.ServerID, pq.Array(dependencies)).Scan(&found)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check dependencies: %w", err)
+		}
+		if found != len(dependencies) {
+			return nil, fmt.Errorf("%w: a dependency is not a tool of this server", ErrUnknownTool)
+		}
+	}
+
+	dependenciesJSON, _ := json.Marshal(dependencies)
+	now := time.Now()
+	_, err = tm.db.ExecContext(ctx, `
+		UPDATE mcp_tools SET dependencies = $1, updated_at = $2 WHERE id = $3
+	`, dependenciesJSON, now, toolID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update dependencies: %w", err)
+	}
+	tm.cache.invalidate(toolID)
+
+	tool.Dependencies = dependencies
+	tool.UpdatedAt = now
+	return tool, nil
+}
+
+// dependencyNames returns the non-empty, distinct dependency names in their original
+// order, and an empty rather than nil slice so they are stored as a JSON array
+func dependencyNames(dependencies []string) []string {
+	names := make([]string, 0, len(dependencies))
+	seen := make(map[string]bool, len(dependencies))
+	for _, name := range dependencies {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
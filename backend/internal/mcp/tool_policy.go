@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ErrToolDenied is returned when the tool policy blocks a tool from being executed
+var ErrToolDenied = errors.New("tool denied by policy")
+
+// ToolPolicy allows or denies tools by name with glob patterns (path.Match syntax).
+// Deny patterns win over allow patterns; an empty allow list allows every tool that is
+// not denied.
+type ToolPolicy struct {
+	AllowedPatterns []string `json:"allowed_patterns"`
+	DeniedPatterns  []string `json:"denied_patterns"`
+}
+
+// Validate checks that every pattern is a valid glob
+func (p ToolPolicy) Validate() error {
+	for _, patterns := range [][]string{p.AllowedPatterns, p.DeniedPatterns} {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid tool pattern %q: %w", pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Evaluate returns ErrToolDenied if the policy blocks the named tool
+func (p ToolPolicy) Evaluate(toolName string) error {
+	for _, pattern := range p.DeniedPatterns {
+		if matched, _ := path.Match(pattern, toolName); matched {
+			return fmt.Errorf("%w: %s matches denied pattern %q", ErrToolDenied, toolName, pattern)
+		}
+	}
+
+	if len(p.AllowedPatterns) == 0 {
+		return nil
+	}
+	for _, pattern := range p.AllowedPatterns {
+		if matched, _ := path.Match(pattern, toolName); matched {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s matches no allowed pattern", ErrToolDenied, toolName)
+}
+
+// ToolPolicy returns the policy applied to tool executions
+func (tm *ToolManager) ToolPolicy() ToolPolicy {
+	tm.policyMu.RLock()
+	defer tm.policyMu.RUnlock()
+	return tm.policy
+}
+
+// SetToolPolicy replaces the policy applied to tool executions
+func (tm *ToolManager) SetToolPolicy(policy ToolPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	if policy.AllowedPatterns == nil {
+		policy.AllowedPatterns = []string{}
+	}
+	if policy.DeniedPatterns == nil {
+		policy.DeniedPatterns = []string{}
+	}
+
+	tm.policyMu.Lock()
+	tm.policy = policy
+	tm.policyMu.Unlock()
+
+	tm.logger.Info("Tool policy updated",
+		zap.Strings("allowed_patterns", policy.AllowedPatterns),
+		zap.Strings("denied_patterns", policy.DeniedPatterns),
+	)
+	return nil
+}
+
+// recordPolicyDenied stores an execution blocked by the tool policy
+func (tm *ToolManager) recordPolicyDenied(tool *ManagedTool, arguments map[string]interface{}, userID *uuid.UUID, policyErr error) {
+	tm.logger.Warn("Tool execution denied by policy",
+		zap.String("tool_name", tool.Name),
+		zap.Error(policyErr),
+	)
+	tm.recordRejectedExecution(tool, arguments, userID, "policy_denied", policyErr)
+}
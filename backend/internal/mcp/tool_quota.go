@@ -0,0 +1,177 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	apperrors "github.com/radhi1991/aran-mcp-sentinel/internal/errors"
+	"go.uber.org/zap"
+)
+
+// ErrQuotaExceeded matches the QuotaError returned when a tool has used up its hourly or
+// daily executions
+var ErrQuotaExceeded = apperrors.ErrQuotaExceeded
+
+// ToolQuota holds a tool's hard execution limits. A nil hourly limit uses the default
+// hourly quota; zero, or a nil daily limit, is unlimited.
+type ToolQuota struct {
+	ToolID               uuid.UUID `json:"tool_id"`
+	MaxExecutionsPerHour *int      `json:"max_executions_per_hour"`
+	MaxExecutionsPerDay  *int      `json:"max_executions_per_day"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+// SetDefaultToolQuota limits the executions per hour of tools without their own hourly
+// quota. Zero, the default, is unlimited.
+func (tm *ToolManager) SetDefaultToolQuota(hourly int) {
+	tm.defaultHourlyQuota = hourly
+}
+
+// GetToolQuota returns a tool's quota, or sql.ErrNoRows if it has none
+func (tm *ToolManager) GetToolQuota(ctx context.Context, toolID uuid.UUID) (*ToolQuota, error) {
+	query := `
+		SELECT tool_id, max_executions_per_hour, max_executions_per_day, updated_at
+		FROM tool_quotas
+		WHERE tool_id = $1
+	`
+
+	quota := &ToolQuota{}
+	var hourly, daily sql.NullInt64
+	err := tm.db.QueryRowContext(ctx, query, toolID).Scan(&quota.ToolID, &hourly, &daily, &quota.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tool quota: %w", err)
+	}
+	quota.MaxExecutionsPerHour = nullIntPtr(hourly)
+	quota.MaxExecutionsPerDay = nullIntPtr(daily)
+
+	return quota, nil
+}
+
+// SetToolQuota replaces a tool's quota. sql.ErrNoRows is returned if the tool does not
+// exist, or orgID is set and does not own its server.
+func (tm *ToolManager) SetToolQuota(ctx context.Context, orgID *uuid.UUID, toolID uuid.UUID, hourly, daily *int) (*ToolQuota, error) {
+	query := `
+		INSERT INTO tool_quotas (tool_id, max_executions_per_hour, max_executions_per_day, created_at, updated_at)
+		SELECT t.id, $2, $3, NOW(), NOW()
+		FROM mcp_tools t
+		JOIN mcp_servers s ON s.id = t.server_id
+		WHERE t.id = $1 AND t.deleted_at IS NULL AND ($4::uuid IS NULL OR s.organization_id = $4)
+		ON CONFLICT (tool_id) DO UPDATE SET
+			max_executions_per_hour = EXCLUDED.max_executions_per_hour,
+			max_executions_per_day = EXCLUDED.max_executions_per_day,
+			updated_at = NOW()
+		RETURNING updated_at
+	`
+
+	quota := &ToolQuota{
+		ToolID:               toolID,
+		MaxExecutionsPerHour: hourly,
+		MaxExecutionsPerDay:  daily,
+	}
+	if err := tm.db.QueryRowContext(ctx, query, toolID, hourly, daily, orgID).Scan(&quota.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("failed to set tool quota: %w", err)
+	}
+
+	return quota, nil
+}
+
+// checkToolQuota counts a tool's executions in the last hour and day against its quota.
+// Executions rejected by the quota or the tool policy do not count.
+func (tm *ToolManager) checkToolQuota(ctx context.Context, toolID uuid.UUID) error {
+	hourly := tm.defaultHourlyQuota
+	daily := 0
+
+	quota, err := tm.GetToolQuota(ctx, toolID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if quota != nil {
+		if quota.MaxExecutionsPerHour != nil {
+			hourly = *quota.MaxExecutionsPerHour
+		}
+		if quota.MaxExecutionsPerDay != nil {
+			daily = *quota.MaxExecutionsPerDay
+		}
+	}
+
+	now := time.Now()
+	limits := []struct {
+		limit  int
+		window time.Duration
+		name   string
+	}{
+		{hourly, time.Hour, "hour"},
+		{daily, 24 * time.Hour, "day"},
+	}
+	for _, l := range limits {
+		if l.limit <= 0 {
+			continue
+		}
+		count, err := tm.countToolExecutions(ctx, toolID, now.Add(-l.window))
+		if err != nil {
+			return err
+		}
+		if count >= int64(l.limit) {
+			return &apperrors.QuotaError{ToolID: toolID, Limit: l.limit, Window: l.name}
+		}
+	}
+
+	return nil
+}
+
+// countToolExecutions counts a tool's executions since a time, leaving out those
+// rejected by its quota or the tool policy
+func (tm *ToolManager) countToolExecutions(ctx context.Context, toolID uuid.UUID, since time.Time) (int64, error) {
+	query := `
+		SELECT COUNT(*) FROM tool_executions
+		WHERE tool_id = $1 AND executed_at > $2 AND status NOT IN ('quota_exceeded', 'policy_denied')
+	`
+
+	var count int64
+	if err := tm.db.QueryRowContext(ctx, query, toolID, since).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count tool executions: %w", err)
+	}
+	return count, nil
+}
+
+// recordQuotaExceeded stores an execution rejected by the tool's quota
+func (tm *ToolManager) recordQuotaExceeded(tool *ManagedTool, arguments map[string]interface{}, userID *uuid.UUID, quotaErr error) {
+	tm.logger.Warn("Tool execution quota exceeded",
+		zap.String("tool_name", tool.Name),
+		zap.Error(quotaErr),
+	)
+	tm.recordRejectedExecution(tool, arguments, userID, "quota_exceeded", quotaErr)
+}
+
+// recordRejectedExecution stores an execution that was refused before reaching the
+// server, with the given status
+func (tm *ToolManager) recordRejectedExecution(tool *ManagedTool, arguments map[string]interface{}, userID *uuid.UUID, status string, cause error) {
+	execution := &ToolExecution{
+		ID:         uuid.New(),
+		ToolID:     tool.ID,
+		ServerID:   tool.ServerID,
+		UserID:     userID,
+		Arguments:  arguments,
+		Error:      cause.Error(),
+		Status:     status,
+		ExecutedAt: time.Now(),
+	}
+
+	if err := tm.storeExecution(execution); err != nil {
+		tm.logger.Error("Failed to store execution record", zap.Error(err))
+	}
+	tm.cache.invalidateUsageStats(tool.ID)
+}
+
+// nullIntPtr returns a nullable integer column as a pointer
+func nullIntPtr(value sql.NullInt64) *int {
+	if !value.Valid {
+		return nil
+	}
+	v := int(value.Int64)
+	return &v
+}
@@ -0,0 +1,125 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	apperrors "github.com/radhi1991/aran-mcp-sentinel/internal/errors"
+)
+
+// MaxToolTagLength is the longest tag the tool_tags table holds
+const MaxToolTagLength = 100
+
+// sqlExecer is satisfied by both *sql.DB and *sql.Tx
+type sqlExecer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// normalizeTags trims tags and drops empty and repeated ones, keeping their order
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// validateTags normalizes tags given by a caller, rejecting ones too long to store
+func validateTags(tags []string) ([]string, error) {
+	tags = normalizeTags(tags)
+	if len(tags) == 0 {
+		return nil, &apperrors.ValidationError{Field: "tags", Message: "at least one tag is required"}
+	}
+	for _, tag := range tags {
+		if utf8.RuneCountInString(tag) > MaxToolTagLength {
+			return nil, &apperrors.ValidationError{Field: "tags", Message: fmt.Sprintf("tag %q is longer than %d characters", tag, MaxToolTagLength)}
+		}
+	}
+	return tags, nil
+}
+
+// setToolTags replaces a tool's rows in tool_tags with tags, cutting overlong ones
+// short. The tool's tags column is updated from them by trigger.
+func setToolTags(ctx context.Context, exec sqlExecer, toolID uuid.UUID, tags []string) error {
+	tags = normalizeTags(tags)
+	for i, tag := range tags {
+		if runes := []rune(tag); len(runes) > MaxToolTagLength {
+			tags[i] = string(runes[:MaxToolTagLength])
+		}
+	}
+
+	_, err := exec.ExecContext(ctx, `
+		DELETE FROM tool_tags WHERE tool_id = $1 AND NOT (tag = ANY($2))
+	`, toolID, pq.Array(tags))
+	if err != nil {
+		return fmt.Errorf("failed to remove tool tags: %w", err)
+	}
+
+	_, err = exec.ExecContext(ctx, `
+		INSERT INTO tool_tags (tool_id, tag)
+		SELECT $1, unnest($2::text[])
+		ON CONFLICT DO NOTHING
+	`, toolID, pq.Array(tags))
+	if err != nil {
+		return fmt.Errorf("failed to add tool tags: %w", err)
+	}
+
+	return nil
+}
+
+// AddTagsToTool adds tags to a tool, keeping its existing ones, and returns the tool.
+// orgID, if set, must own the tool's server.
+func (tm *ToolManager) AddTagsToTool(ctx context.Context, orgID *uuid.UUID, toolID uuid.UUID, tags []string) (*ManagedTool, error) {
+	tags, err := validateTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tm.GetTool(orgID, toolID); err != nil {
+		return nil, err
+	}
+
+	_, err = tm.db.ExecContext(ctx, `
+		INSERT INTO tool_tags (tool_id, tag)
+		SELECT $1, unnest($2::text[])
+		ON CONFLICT DO NOTHING
+	`, toolID, pq.Array(tags))
+	if err != nil {
+		return nil, fmt.Errorf("failed to add tool tags: %w", err)
+	}
+	tm.cache.invalidate(toolID)
+
+	return tm.GetTool(orgID, toolID)
+}
+
+// RemoveTagsFromTool removes tags from a tool and returns the tool. Tags the tool does
+// not have are ignored. orgID, if set, must own the tool's server.
+func (tm *ToolManager) RemoveTagsFromTool(ctx context.Context, orgID *uuid.UUID, toolID uuid.UUID, tags []string) (*ManagedTool, error) {
+	tags, err := validateTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tm.GetTool(orgID, toolID); err != nil {
+		return nil, err
+	}
+
+	_, err = tm.db.ExecContext(ctx, `
+		DELETE FROM tool_tags WHERE tool_id = $1 AND tag = ANY($2)
+	`, toolID, pq.Array(tags))
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove tool tags: %w", err)
+	}
+	tm.cache.invalidate(toolID)
+
+	return tm.GetTool(orgID, toolID)
+}
@@ -0,0 +1,310 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ImportMode decides what an import does with tools that already exist on the target server
+type ImportMode string
+
+const (
+	// MergeSkipExisting creates new tools and leaves existing ones untouched
+	MergeSkipExisting ImportMode = "merge_skip_existing"
+	// MergeOverwrite creates new tools and overwrites existing ones
+	MergeOverwrite ImportMode = "merge_overwrite"
+	// ReplaceAll makes the imported tools the server's only tools, deleting the rest
+	ReplaceAll ImportMode = "replace_all"
+)
+
+// ErrInvalidImportMode is returned for an unknown ImportMode
+var ErrInvalidImportMode = errors.New("invalid import mode")
+
+// ErrInvalidToolExport is returned when import data is not a JSON array of tools
+var ErrInvalidToolExport = errors.New("invalid tool export")
+
+// ExportedTool is a tool definition without its server, IDs or usage, so it can be
+// imported into another server or environment
+type ExportedTool struct {
+	Name         string                 `json:"name"`
+	Description  string                 `json:"description"`
+	InputSchema  map[string]interface{} `json:"input_schema"`
+	OutputSchema map[string]interface{} `json:"output_schema,omitempty"`
+	Category     string                 `json:"category"`
+	Tags         []string               `json:"tags"`
+	Dependencies []string               `json:"dependencies,omitempty"`
+	RiskLevel    string                 `json:"risk_level"`
+	IsEnabled    bool                   `json:"is_enabled"`
+}
+
+// ImportResult reports the outcome of a tool import
+type ImportResult struct {
+	Created int               `json:"created"`
+	Updated int               `json:"updated"`
+	Skipped int               `json:"skipped"`
+	Deleted int               `json:"deleted"`
+	Errored int               `json:"errored"`
+	Errors  []ToolImportError `json:"errors"`
+}
+
+// ToolImportError describes an imported tool that was rejected
+type ToolImportError struct {
+	Index int    `json:"index"`
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
+
+// validRiskLevels are the risk levels assessRiskLevel assigns
+var validRiskLevels = map[string]bool{"low": true, "medium": true, "high": true}
+
+// ExportTools serializes tools to a JSON array of ExportedTool ordered by server and
+// name. serverID limits the export to one server and orgID to one organization; either
+// may be nil.
+func (tm *ToolManager) ExportTools(ctx context.Context, orgID, serverID *uuid.UUID) ([]byte, error) {
+	rows, err := tm.db.QueryContext(ctx, `
+		SELECT t.name, t.description, t.input_schema, t.output_schema, t.category, t.tags,
+		       t.dependencies, t.risk_level, t.is_enabled
+		FROM mcp_tools t
+		JOIN mcp_servers s ON s.id = t.server_id
+		WHERE t.deleted_at IS NULL AND s.deleted_at IS NULL
+		  AND ($1::uuid IS NULL OR s.organization_id = $1)
+		  AND ($2::uuid IS NULL OR t.server_id = $2)
+		ORDER BY t.server_id, t.name
+	`, orgID, serverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tools: %w", err)
+	}
+	defer rows.Close()
+
+	tools := []ExportedTool{}
+	for rows.Next() {
+		var tool ExportedTool
+		var description sql.NullString
+		var inputSchemaJSON, outputSchemaJSON, tagsJSON, dependenciesJSON []byte
+		if err := rows.Scan(&tool.Name, &description, &inputSchemaJSON, &outputSchemaJSON,
+			&tool.Category, &tagsJSON, &dependenciesJSON, &tool.RiskLevel, &tool.IsEnabled); err != nil {
+			return nil, fmt.Errorf("failed to scan tool: %w", err)
+		}
+		tool.Description = description.String
+
+		json.Unmarshal(inputSchemaJSON, &tool.InputSchema)
+		if len(outputSchemaJSON) > 0 {
+			json.Unmarshal(outputSchemaJSON, &tool.OutputSchema)
+		}
+		json.Unmarshal(tagsJSON, &tool.Tags)
+		json.Unmarshal(dependenciesJSON, &tool.Dependencies)
+
+		tools = append(tools, tool)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query tools: %w", err)
+	}
+
+	data, err := json.Marshal(tools)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode tools: %w", err)
+	}
+	return data, nil
+}
+
+// ImportTools stores the tools of an ExportTools JSON array on targetServerID in one
+// transaction. Tools without a name or with an unknown risk level are counted as
+// errored and skipped; a missing category or risk level is assessed as on discovery.
+// orgID, if set, must own the target server. sql.ErrNoRows is returned if it does not
+// exist.
+func (tm *ToolManager) ImportTools(ctx context.Context, data []byte, orgID *uuid.UUID, targetServerID uuid.UUID, mode ImportMode) (*ImportResult, error) {
+	switch mode {
+	case MergeSkipExisting, MergeOverwrite, ReplaceAll:
+	default:
+		return nil, ErrInvalidImportMode
+	}
+
+	var tools []ExportedTool
+	if err := json.Unmarshal(data, &tools); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToolExport, err)
+	}
+
+	tx, err := tm.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin tool import: %w", err)
+	}
+	defer tx.Rollback()
+
+	var serverURL string
+	err = tx.QueryRowContext(ctx, `
+		SELECT url FROM mcp_servers
+		WHERE id = $1 AND deleted_at IS NULL AND ($2::uuid IS NULL OR organization_id = $2)
+	`, targetServerID, orgID).Scan(&serverURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target server: %w", err)
+	}
+
+	// Soft-deleted tools still hold their name, so they are revived rather than inserted
+	rows, err := tx.QueryContext(ctx, `
+		SELECT name, deleted_at IS NOT NULL FROM mcp_tools WHERE server_id = $1 FOR UPDATE
+	`, targetServerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get existing tools: %w", err)
+	}
+	existing := make(map[string]bool) // name -> deleted
+	for rows.Next() {
+		var name string
+		var deleted bool
+		if err := rows.Scan(&name, &deleted); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan existing tool: %w", err)
+		}
+		existing[name] = deleted
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get existing tools: %w", err)
+	}
+
+	result := &ImportResult{Errors: []ToolImportError{}}
+	imported := make(map[string]bool, len(tools))
+	now := time.Now()
+
+	for i := range tools {
+		tool := &tools[i]
+		if err := tm.normalizeImportedTool(tool); err != nil {
+			result.Errored++
+			result.Errors = append(result.Errors, ToolImportError{Index: i, Name: tool.Name, Error: err.Error()})
+			continue
+		}
+		if imported[tool.Name] {
+			result.Errored++
+			result.Errors = append(result.Errors, ToolImportError{Index: i, Name: tool.Name, Error: "duplicate tool name"})
+			continue
+		}
+		imported[tool.Name] = true
+
+		deleted, exists := existing[tool.Name]
+		if exists && !deleted && mode == MergeSkipExisting {
+			result.Skipped++
+			continue
+		}
+
+		inputSchemaJSON, _ := json.Marshal(tool.InputSchema)
+		tagsJSON, _ := json.Marshal(normalizeTags(tool.Tags))
+		dependenciesJSON, _ := json.Marshal(tool.Dependencies)
+		var outputSchemaJSON []byte
+		if len(tool.OutputSchema) > 0 {
+			outputSchemaJSON, _ = json.Marshal(tool.OutputSchema)
+		}
+
+		if !exists {
+			toolID := uuid.New()
+			_, err = tx.ExecContext(ctx, `
+				INSERT INTO mcp_tools (id, server_id, server_url, name, description, input_schema, output_schema,
+				                      category, tags, dependencies, risk_level, is_enabled, usage_count, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, 0, $13, $13)
+			`, toolID, targetServerID, serverURL, tool.Name, tool.Description, inputSchemaJSON, outputSchemaJSON,
+				tool.Category, tagsJSON, dependenciesJSON, tool.RiskLevel, tool.IsEnabled, now)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create tool %q: %w", tool.Name, err)
+			}
+			if err := setToolTags(ctx, tx, toolID, tool.Tags); err != nil {
+				return nil, fmt.Errorf("failed to tag tool %q: %w", tool.Name, err)
+			}
+			result.Created++
+			continue
+		}
+
+		// A revived tool starts with no usage, like a new one
+		var toolID uuid.UUID
+		err = tx.QueryRowContext(ctx, `
+			UPDATE mcp_tools SET server_url = $1, description = $2, input_schema = $3, output_schema = $4,
+			       category = $5, dependencies = $6, risk_level = $7, is_enabled = $8, updated_at = $9,
+			       usage_count = CASE WHEN deleted_at IS NULL THEN usage_count ELSE 0 END,
+			       last_used = CASE WHEN deleted_at IS NULL THEN last_used END,
+			       deleted_at = NULL
+			WHERE server_id = $10 AND name = $11
+			RETURNING id
+		`, serverURL, tool.Description, inputSchemaJSON, outputSchemaJSON, tool.Category,
+			dependenciesJSON, tool.RiskLevel, tool.IsEnabled, now, targetServerID, tool.Name).Scan(&toolID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update tool %q: %w", tool.Name, err)
+		}
+		if err := setToolTags(ctx, tx, toolID, tool.Tags); err != nil {
+			return nil, fmt.Errorf("failed to tag tool %q: %w", tool.Name, err)
+		}
+		if deleted {
+			result.Created++
+		} else {
+			result.Updated++
+		}
+	}
+
+	if mode == ReplaceAll {
+		for name, deleted := range existing {
+			if deleted || imported[name] {
+				continue
+			}
+			_, err = tx.ExecContext(ctx, `
+				UPDATE mcp_tools SET deleted_at = $1, updated_at = $1 WHERE server_id = $2 AND name = $3
+			`, now, targetServerID, name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to delete tool %q: %w", name, err)
+			}
+			result.Deleted++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit tool import: %w", err)
+	}
+	tm.cache.invalidateServer(targetServerID, "")
+
+	tm.logger.Info("Imported tools",
+		zap.String("server_id", targetServerID.String()),
+		zap.String("mode", string(mode)),
+		zap.Int("created", result.Created),
+		zap.Int("updated", result.Updated),
+		zap.Int("skipped", result.Skipped),
+		zap.Int("deleted", result.Deleted),
+		zap.Int("errored", result.Errored),
+	)
+
+	return result, nil
+}
+
+// normalizeImportedTool validates an imported tool and fills in a missing category,
+// risk level and schema
+func (tm *ToolManager) normalizeImportedTool(tool *ExportedTool) error {
+	if tool.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(tool.Name) > 255 {
+		return fmt.Errorf("name is longer than 255 characters")
+	}
+
+	if tool.InputSchema == nil {
+		tool.InputSchema = map[string]interface{}{}
+	}
+	if tool.Tags == nil {
+		tool.Tags = []string{}
+	}
+	tool.Dependencies = dependencyNames(tool.Dependencies)
+	if tool.Category == "" {
+		tool.Category = tm.categorizeTool(tool.Name, tool.Description)
+	}
+	if len(tool.Category) > 50 {
+		return fmt.Errorf("category is longer than 50 characters")
+	}
+	if tool.RiskLevel == "" {
+		tool.RiskLevel = tm.assessRiskLevel(tool.Name, tool.InputSchema)
+	}
+	if !validRiskLevels[tool.RiskLevel] {
+		return fmt.Errorf("unknown risk level %q", tool.RiskLevel)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package mcp
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"go.uber.org/zap"
+)
+
+// userPreferencesTTL is how long a user's preferences are cached
+const userPreferencesTTL = 5 * time.Minute
+
+// cachedPreferences is a user's preferences and when they were loaded
+type cachedPreferences struct {
+	preferences database.UserPreferences
+	loadedAt    time.Time
+}
+
+// preferencesCache caches user preferences in memory
+type preferencesCache struct {
+	mu      sync.Mutex
+	entries map[uuid.UUID]cachedPreferences
+}
+
+// newPreferencesCache creates an empty preferences cache
+func newPreferencesCache() *preferencesCache {
+	return &preferencesCache{
+		entries: make(map[uuid.UUID]cachedPreferences),
+	}
+}
+
+// get returns a user's cached preferences if they have not expired
+func (c *preferencesCache) get(userID uuid.UUID) (database.UserPreferences, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[userID]
+	if !ok {
+		return database.UserPreferences{}, false
+	}
+	if time.Since(entry.loadedAt) > userPreferencesTTL {
+		delete(c.entries, userID)
+		return database.UserPreferences{}, false
+	}
+
+	return entry.preferences, true
+}
+
+// set caches a user's preferences
+func (c *preferencesCache) set(userID uuid.UUID, preferences database.UserPreferences) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[userID] = cachedPreferences{preferences: preferences, loadedAt: time.Now()}
+}
+
+// userPreferences returns a user's preferences, falling back to defaults
+func (tm *ToolManager) userPreferences(ctx context.Context, userID uuid.UUID) database.UserPreferences {
+	if preferences, ok := tm.preferences.get(userID); ok {
+		return preferences
+	}
+
+	var raw database.JSONB
+	query := `SELECT preferences FROM users WHERE id = $1 AND deleted_at IS NULL`
+	if err := tm.db.QueryRowContext(ctx, query, userID).Scan(&raw); err != nil {
+		tm.logger.Warn("Failed to load user preferences, using defaults",
+			zap.String("user_id", userID.String()),
+			zap.Error(err),
+		)
+		return database.DefaultUserPreferences()
+	}
+
+	preferences := database.UserPreferencesFromJSONB(raw)
+	tm.preferences.set(userID, preferences)
+	return preferences
+}
+
+// executionTimeout returns the timeout for a tool execution. A user's default tool
+// timeout overrides the organization's, but never exceeds it.
+func (tm *ToolManager) executionTimeout(ctx context.Context, settings database.OrgSettings, userID *uuid.UUID) time.Duration {
+	timeout := settings.MaxToolExecTimeout
+	if userID != nil {
+		preferences := tm.userPreferences(ctx, *userID)
+		if preferences.DefaultToolTimeout > 0 && preferences.DefaultToolTimeout < timeout {
+			timeout = preferences.DefaultToolTimeout
+		}
+	}
+
+	return time.Duration(timeout) * time.Second
+}
@@ -0,0 +1,2378 @@
+package mcpapi
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/auth"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/discovery"
+	apperrors "github.com/radhi1991/aran-mcp-sentinel/internal/errors"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/mcp"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/metrics"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/middleware"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/monitoring"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/registry"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/security"
+	"go.uber.org/zap"
+)
+
+// executionCSVFlushEvery controls how often CSV exports are flushed to the client
+const executionCSVFlushEvery = 100
+
+// EnhancedHandler provides real MCP functionality
+type EnhancedHandler struct {
+	db           *sql.DB
+	logger       *zap.Logger
+	protocol     *mcp.MCPProtocol
+	discovery    *discovery.MCPDiscoveryService
+	monitor      *monitoring.MCPMonitor
+	toolManager  *mcp.ToolManager
+	docGenerator *mcp.ToolDocumentationGenerator
+	shutdown     *middleware.ShutdownCoordinator
+
+	healthChecker *monitoring.HealthChecker
+
+	// Active resource subscriptions, keyed by subscription ID
+	subscriptionsMu sync.Mutex
+	subscriptions   map[uuid.UUID]*ResourceSubscription
+}
+
+// ResourceSubscription is an active subscription to changes of a server's resource
+type ResourceSubscription struct {
+	ID          uuid.UUID              `json:"id"`
+	ServerID    uuid.UUID              `json:"server_id"`
+	ResourceURI string                 `json:"resource_uri"`
+	CreatedAt   time.Time              `json:"created_at"`
+	Updates     int                    `json:"updates"`
+	LastUpdate  *mcp.MCPResourceUpdate `json:"last_update,omitempty"`
+
+	organizationID uuid.UUID
+	unsubscribe    func()
+}
+
+// NewEnhancedHandler creates a new enhanced MCP handler. breaker configures when health
+// checks of servers that stay down are paused.
+func NewEnhancedHandler(db *sql.DB, logger *zap.Logger, breaker monitoring.CircuitBreakerConfig) *EnhancedHandler {
+	protocol := mcp.NewMCPProtocol(logger)
+	protocol.SetHealthBaselineStore(mcp.NewDBHealthBaselineStore(db))
+
+	h := &EnhancedHandler{
+		db:           db,
+		logger:       logger,
+		protocol:     protocol,
+		discovery:    discovery.NewMCPDiscoveryService(logger),
+		monitor:      monitoring.NewMCPMonitor(db, logger, breaker),
+		toolManager:  mcp.NewToolManager(db, logger),
+		docGenerator: mcp.NewToolDocumentationGenerator(),
+
+		subscriptions: make(map[uuid.UUID]*ResourceSubscription),
+	}
+	h.discovery.OnIdentityChange(h.recordIdentityChange)
+	h.discovery.OnCapabilityChange(h.recordCapabilityChange)
+	h.discovery.SetPersistence(db, h.toolManager)
+
+	return h
+}
+
+// discoveryContext returns the request context, carrying the caller's organization so
+// discovered servers are persisted for it
+func (h *EnhancedHandler) discoveryContext(c *gin.Context) context.Context {
+	ctx := c.Request.Context()
+	if orgID, exists := c.Get("organization_id"); exists {
+		if orgUUID, ok := orgID.(uuid.UUID); ok {
+			ctx = discovery.WithOrganization(ctx, orgUUID)
+		}
+	}
+	return ctx
+}
+
+// recordIdentityChange persists a discovered server's new fingerprint and alerts on it
+func (h *EnhancedHandler) recordIdentityChange(ctx context.Context, server *discovery.DiscoveredServer) {
+	if err := h.monitor.RecordServerIdentityChange(ctx, server.URL, server.PreviousFingerprint, server.Fingerprint); err != nil {
+		h.logger.Error("Failed to record server identity change", zap.String("url", server.URL), zap.Error(err))
+	}
+}
+
+// recordCapabilityChange alerts on a server whose tools changed since they were stored
+func (h *EnhancedHandler) recordCapabilityChange(ctx context.Context, serverID uuid.UUID, diff *discovery.CapabilityDiff) {
+	var added, removed, modified []string
+	for _, tool := range diff.Added {
+		added = append(added, tool.Name)
+	}
+	for _, tool := range diff.Removed {
+		removed = append(removed, tool.Name)
+	}
+	for _, tool := range diff.Modified {
+		modified = append(modified, tool.Name)
+	}
+	h.monitor.AlertCapabilityChange(serverID, added, removed, modified)
+}
+
+// SetExecutionQuota enables per-organization daily tool execution limits
+func (h *EnhancedHandler) SetExecutionQuota(counter mcp.QuotaCounter, repo *database.Repository) {
+	h.toolManager.SetExecutionQuota(counter, repo)
+}
+
+// SetMetrics reports monitoring metrics to registry
+func (h *EnhancedHandler) SetMetrics(registry *metrics.Registry) {
+	h.monitor.SetMetrics(registry)
+}
+
+// SetHealthScoreBuffer records the health score of every health check in buffer
+func (h *EnhancedHandler) SetHealthScoreBuffer(buffer *monitoring.HealthScoreBuffer) {
+	h.monitor.SetHealthScoreBuffer(buffer)
+}
+
+// SetHealthScoreCache keeps the registry's health score cache up to date with every
+// monitoring check
+func (h *EnhancedHandler) SetHealthScoreCache(cache *registry.HealthScoreCache) {
+	h.monitor.SetHealthScoreCache(cache)
+}
+
+// SetAlertDedupWindow sets how long repeats of an unresolved monitoring alert are
+// coalesced into it
+func (h *EnhancedHandler) SetAlertDedupWindow(window time.Duration) {
+	h.monitor.SetAlertDedupWindow(window)
+}
+
+// SetShutdownCoordinator lets in-flight tool executions and health check probes finish
+// during shutdown
+func (h *EnhancedHandler) SetShutdownCoordinator(shutdown *middleware.ShutdownCoordinator) {
+	h.shutdown = shutdown
+	h.monitor.SetShutdownCoordinator(shutdown)
+}
+
+// SetProtocol makes the handler and its tool manager reach MCP servers through
+// protocol, such as one built with mcp.NewMCPProtocolWithTransport
+func (h *EnhancedHandler) SetProtocol(protocol *mcp.MCPProtocol) {
+	protocol.SetHealthBaselineStore(mcp.NewDBHealthBaselineStore(h.db))
+	h.protocol = protocol
+	h.toolManager.SetProtocol(protocol)
+}
+
+// SetToolCacheTTL sets how long tools and their usage stats are cached
+func (h *EnhancedHandler) SetToolCacheTTL(ttl time.Duration) {
+	h.toolManager.SetToolCacheTTL(ttl)
+}
+
+// SetHealthChecker lets per-server check intervals be changed on the running periodic
+// health checks
+func (h *EnhancedHandler) SetHealthChecker(checker *monitoring.HealthChecker) {
+	h.healthChecker = checker
+}
+
+// SetDefaultToolQuota limits the executions per hour of tools without their own hourly quota
+func (h *EnhancedHandler) SetDefaultToolQuota(hourly int) {
+	h.toolManager.SetDefaultToolQuota(hourly)
+}
+
+// EnableMDNSDiscovery adds mDNS browsing to discovery scans and runs a first browse in
+// the background
+func (h *EnhancedHandler) EnableMDNSDiscovery() {
+	h.discovery.EnableMDNS()
+
+	go func() {
+		servers, err := h.discovery.DiscoverFromDNSSD(context.Background())
+		if err != nil {
+			h.logger.Warn("mDNS discovery failed", zap.Error(err))
+			return
+		}
+		h.logger.Info("mDNS discovery completed", zap.Int("servers_found", len(servers)))
+	}()
+}
+
+// SetMaxConcurrentExecutions limits how many tools of a batch run at once
+func (h *EnhancedHandler) SetMaxConcurrentExecutions(limit int) {
+	h.toolManager.SetMaxConcurrentExecutions(limit)
+}
+
+// SetToolPolicy sets the allow and deny patterns applied to tool executions
+func (h *EnhancedHandler) SetToolPolicy(policy mcp.ToolPolicy) error {
+	return h.toolManager.SetToolPolicy(policy)
+}
+
+// ToolCacheStats returns the hit and miss counts of the tool cache
+func (h *EnhancedHandler) ToolCacheStats() mcp.ToolCacheStats {
+	return h.toolManager.ToolCacheStats()
+}
+
+// Monitor returns the MCP server monitor
+func (h *EnhancedHandler) Monitor() *monitoring.MCPMonitor {
+	return h.monitor
+}
+
+// BehavioralAnalyzer returns the analyzer applied to tool executions
+func (h *EnhancedHandler) BehavioralAnalyzer() *security.BehavioralAnalyzer {
+	return h.toolManager.BehavioralAnalyzer()
+}
+
+// RegisterEnhancedRoutes registers enhanced MCP API routes
+func (h *EnhancedHandler) RegisterEnhancedRoutes(router *gin.RouterGroup) {
+	// Discovery endpoints
+	discoveryGroup := router.Group("/discovery")
+	{
+		discoveryGroup.POST("/scan", h.DiscoverServers)
+		discoveryGroup.GET("/servers", h.GetDiscoveredServers)
+		discoveryGroup.POST("/servers/:url/refresh", h.RefreshServer)
+		discoveryGroup.GET("/servers/:id/diff", h.DiffServerCapabilities)
+		discoveryGroup.POST("/ansible", h.DiscoverFromAnsibleInventory)
+	}
+
+	// Real MCP protocol endpoints
+	protocolGroup := router.Group("/protocol")
+	{
+		protocolGroup.POST("/initialize", h.InitializeServer)
+		protocolGroup.POST("/ping", h.PingServer)
+		protocolGroup.POST("/prompts/get", h.GetPrompt)
+		protocolGroup.GET("/servers/:id/capabilities", h.GetServerCapabilities)
+	}
+
+	// Tool management endpoints
+	toolsGroup := router.Group("/tools")
+	{
+		toolsGroup.GET("", h.ListTools)
+		toolsGroup.GET("/executions", h.ListToolExecutions)
+		toolsGroup.GET("/executions/:id/alerts", h.GetExecutionAlerts)
+		toolsGroup.POST("/executions/:id/replay", h.ReplayToolExecution)
+		toolsGroup.GET("/risk-summary", h.GetToolRiskSummary)
+		toolsGroup.GET("/export", h.ExportTools)
+		toolsGroup.POST("/import", auth.RBACMiddleware(auth.RoleAdmin, h.logger), h.ImportTools)
+		toolsGroup.PUT("/bulk", auth.RBACMiddleware(auth.RoleAdmin, h.logger), h.BulkSetToolsEnabled)
+		toolsGroup.POST("/plan", h.PlanTools)
+		toolsGroup.GET("/:id", h.GetTool)
+		toolsGroup.POST("/:id/execute", h.ExecuteTool)
+		toolsGroup.GET("/:id/stats", h.GetToolStats)
+		toolsGroup.GET("/:id/documentation", h.GetToolDocumentation)
+		toolsGroup.GET("/:id/executions", h.GetToolExecutions)
+		toolsGroup.POST("/:id/clone", h.CloneTool)
+		toolsGroup.POST("/:id/clone-metadata", h.CloneToolMetadata)
+		toolsGroup.PUT("/:id/dependencies", auth.RBACMiddleware(auth.RoleAdmin, h.logger), h.SetToolDependencies)
+		toolsGroup.PUT("/:id/quota", auth.RBACMiddleware(auth.RoleAdmin, h.logger), h.SetToolQuota)
+		toolsGroup.POST("/:id/tags", auth.RBACMiddleware(auth.RoleAdmin, h.logger), h.AddToolTags)
+		toolsGroup.DELETE("/:id/tags", auth.RBACMiddleware(auth.RoleAdmin, h.logger), h.RemoveToolTags)
+		toolsGroup.POST("/discover/:server_id", h.DiscoverTools)
+	}
+
+	// Resource management endpoints
+	resourcesGroup := router.Group("/resources")
+	{
+		resourcesGroup.GET("/servers/:server_id", h.ListResources)
+		resourcesGroup.POST("/read", h.ReadResource)
+		resourcesGroup.GET("/preview", h.PreviewResource)
+		resourcesGroup.POST("/subscribe", h.SubscribeResource)
+		resourcesGroup.DELETE("/unsubscribe", h.UnsubscribeResource)
+	}
+
+	// Monitoring endpoints
+	monitoringGroup := router.Group("/monitoring")
+	{
+		monitoringGroup.POST("/start/:server_id", auth.RBACMiddleware(auth.RoleUser, h.logger), h.StartMonitoring)
+		monitoringGroup.POST("/stop/:server_id", auth.RBACMiddleware(auth.RoleUser, h.logger), h.StopMonitoring)
+		monitoringGroup.PUT("/servers/:server_id/health-check", h.UpdateHealthCheckConfig)
+		monitoringGroup.GET("/servers/:server_id/deep-health", h.DeepHealthCheck)
+		monitoringGroup.GET("/servers/:server_id/history", h.GetServerHistory)
+		monitoringGroup.GET("/status", h.GetMonitoringStatus)
+		monitoringGroup.GET("/alerts", h.GetAlerts)
+	}
+
+	// Per-server health check intervals, applied without restarting the checks
+	router.PUT("/servers/:id/check-interval", auth.RBACMiddleware(auth.RoleUser, h.logger), h.SetServerCheckInterval)
+}
+
+// RegisterToolRoutes registers the tool list, batch execution and tool policy routes
+// under /tools, outside the other tool routes under /mcp
+func (h *EnhancedHandler) RegisterToolRoutes(router gin.IRoutes) {
+	router.GET("/tools", h.ListTools)
+	router.POST("/tools/batch-execute", h.BatchExecuteTools)
+	router.GET("/tools/policy", h.GetToolPolicy)
+	router.PUT("/tools/policy", auth.RBACMiddleware(auth.RoleAdmin, h.logger), h.UpdateToolPolicy)
+}
+
+// DiscoverServers performs MCP server discovery
+func (h *EnhancedHandler) DiscoverServers(c *gin.Context) {
+	var req struct {
+		PortRanges    []discovery.PortRange `json:"port_ranges"`
+		NetworkRanges []string              `json:"network_ranges"`
+		KnownPorts    []int                 `json:"known_ports"`
+		Timeout       int                   `json:"timeout_seconds"`
+		MaxConcurrent int                   `json:"max_concurrent"`
+
+		BlacklistedCIDRs []string `json:"blacklisted_cidrs"`
+		BlacklistedPorts []int    `json:"blacklisted_ports"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	// Set defaults
+	if req.Timeout == 0 {
+		req.Timeout = 10
+	}
+	if req.MaxConcurrent == 0 {
+		req.MaxConcurrent = 50
+	}
+	if len(req.KnownPorts) == 0 {
+		req.KnownPorts = []int{3000, 3001, 3002, 8000, 8080}
+	}
+
+	config := discovery.DiscoveryConfig{
+		PortRanges:    req.PortRanges,
+		NetworkRanges: req.NetworkRanges,
+		KnownPorts:    req.KnownPorts,
+		Timeout:       time.Duration(req.Timeout) * time.Second,
+		MaxConcurrent: req.MaxConcurrent,
+
+		BlacklistedCIDRs: req.BlacklistedCIDRs,
+		BlacklistedPorts: req.BlacklistedPorts,
+	}
+
+	if _, err := discovery.NewBlacklistWithDefaults(req.BlacklistedCIDRs, req.BlacklistedPorts); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(h.discoveryContext(c), 5*time.Minute)
+	defer cancel()
+
+	servers, err := h.discovery.DiscoverServers(ctx, config)
+	if err != nil {
+		h.logger.Error("Discovery failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Discovery failed"})
+		return
+	}
+
+	h.logger.Info("Discovery completed", zap.Int("servers_found", len(servers)))
+
+	c.JSON(http.StatusOK, gin.H{
+		"servers_found": len(servers),
+		"servers":       servers,
+	})
+}
+
+// maxInventoryUploadSize caps the size of uploaded Ansible inventories
+const maxInventoryUploadSize = 1 << 20
+
+// DiscoverFromAnsibleInventory probes the MCP hosts of an uploaded Ansible inventory,
+// sent as the "inventory" file of a multipart form in INI or YAML format
+func (h *EnhancedHandler) DiscoverFromAnsibleInventory(c *gin.Context) {
+	fileHeader, err := c.FormFile("inventory")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Inventory file required"})
+		return
+	}
+	if fileHeader.Size > maxInventoryUploadSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Inventory file too large"})
+		return
+	}
+
+	inventoryPath, err := saveUploadedInventory(fileHeader)
+	if err != nil {
+		h.logger.Error("Failed to save uploaded inventory", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read inventory file"})
+		return
+	}
+	defer os.Remove(inventoryPath)
+
+	ctx, cancel := context.WithTimeout(h.discoveryContext(c), 5*time.Minute)
+	defer cancel()
+
+	servers, err := discovery.NewAnsibleInventoryDiscovery(h.discovery).Discover(ctx, inventoryPath)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.logger.Info("Ansible inventory discovery completed",
+		zap.String("inventory", fileHeader.Filename),
+		zap.Int("servers_found", len(servers)))
+
+	c.JSON(http.StatusOK, gin.H{
+		"servers_found": len(servers),
+		"servers":       servers,
+	})
+}
+
+// saveUploadedInventory copies an uploaded inventory to a temporary file and returns its path
+func saveUploadedInventory(fileHeader *multipart.FileHeader) (string, error) {
+	src, err := fileHeader.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to open upload: %w", err)
+	}
+	defer src.Close()
+
+	dst, err := os.CreateTemp("", "ansible-inventory-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, io.LimitReader(src, maxInventoryUploadSize)); err != nil {
+		os.Remove(dst.Name())
+		return "", fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	return dst.Name(), nil
+}
+
+// GetDiscoveredServers returns all discovered servers
+func (h *EnhancedHandler) GetDiscoveredServers(c *gin.Context) {
+	servers := h.discovery.GetDiscoveredServers()
+	c.JSON(http.StatusOK, gin.H{
+		"servers": servers,
+	})
+}
+
+// RefreshServer refreshes information for a specific server
+func (h *EnhancedHandler) RefreshServer(c *gin.Context) {
+	serverURL := c.Param("url")
+	if serverURL == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Server URL required"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	server, err := h.discovery.RefreshServer(ctx, serverURL)
+	if err != nil {
+		h.logger.Error("Failed to refresh server", zap.String("url", serverURL), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh server"})
+		return
+	}
+
+	c.JSON(http.StatusOK, server)
+}
+
+// DiffServerCapabilities compares the tools a registered server offers now with the
+// tools stored for it, and raises a warning alert if they differ
+func (h *EnhancedHandler) DiffServerCapabilities(c *gin.Context) {
+	serverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid server ID"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(h.discoveryContext(c), 30*time.Second)
+	defer cancel()
+
+	diff, err := h.discovery.DiffCapabilities(ctx, serverID)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Server not found"})
+		case errors.Is(err, discovery.ErrServerUnavailable):
+			h.logger.Warn("Failed to get server capabilities", zap.String("server_id", serverID.String()), zap.Error(err))
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to get server capabilities"})
+		default:
+			h.logger.Error("Failed to diff server capabilities", zap.String("server_id", serverID.String()), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to diff server capabilities"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    diff,
+		"changed": !diff.Empty(),
+	})
+}
+
+// InitializeServer initializes connection to an MCP server
+func (h *EnhancedHandler) InitializeServer(c *gin.Context) {
+	var req struct {
+		URL string `json:"url" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	serverInfo, err := h.protocol.Initialize(ctx, req.URL)
+	if err != nil {
+		h.logger.Error("Failed to initialize server", zap.String("url", req.URL), zap.Error(err))
+		var mismatch *mcp.VersionMismatchError
+		if errors.As(err, &mismatch) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Unsupported MCP protocol version",
+				"details": mismatch,
+			})
+			return
+		}
+		c.JSON(apperrors.StatusCode(err, http.StatusBadRequest), gin.H{"error": "Failed to initialize MCP server"})
+		return
+	}
+
+	c.JSON(http.StatusOK, serverInfo)
+}
+
+// PingServer pings an MCP server
+func (h *EnhancedHandler) PingServer(c *gin.Context) {
+	var req struct {
+		URL string `json:"url" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	err := h.protocol.Ping(ctx, req.URL)
+	responseTime := time.Since(start)
+
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":        "offline",
+			"error":         err.Error(),
+			"response_time": responseTime.Milliseconds(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":        "online",
+		"response_time": responseTime.Milliseconds(),
+	})
+}
+
+// GetPrompt renders a prompt on one of the caller's organization's servers
+func (h *EnhancedHandler) GetPrompt(c *gin.Context) {
+	var req struct {
+		ServerID  uuid.UUID         `json:"server_id" binding:"required"`
+		Name      string            `json:"name" binding:"required"`
+		Arguments map[string]string `json:"arguments"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	serverURL, ok := h.serverURL(c, orgID, req.ServerID)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	prompt, err := h.protocol.GetPrompt(ctx, serverURL, req.Name, req.Arguments)
+	if err != nil {
+		h.logger.Error("Failed to get prompt", zap.String("prompt", req.Name), zap.Error(err))
+		c.JSON(apperrors.StatusCode(err, http.StatusBadGateway), gin.H{"error": "Failed to get prompt from server"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"prompt": prompt,
+	})
+}
+
+// serverURL returns the URL of one of the organization's servers. It writes 404 if the
+// server does not exist or belongs to another organization.
+func (h *EnhancedHandler) serverURL(c *gin.Context, orgID, serverID uuid.UUID) (string, bool) {
+	var serverURL string
+	err := h.db.QueryRowContext(c.Request.Context(),
+		"SELECT url FROM mcp_servers WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL",
+		serverID, orgID).Scan(&serverURL)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			h.logger.Error("Failed to look up server", zap.String("server_id", serverID.String()), zap.Error(err))
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": "Server not found"})
+		return "", false
+	}
+	return serverURL, true
+}
+
+// GetServerCapabilities returns detailed server capabilities
+func (h *EnhancedHandler) GetServerCapabilities(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	serverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid server ID"})
+		return
+	}
+
+	serverURL, ok := h.serverURL(c, orgID, serverID)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	// Initialize and get capabilities
+	serverInfo, err := h.protocol.Initialize(ctx, serverURL)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Server unavailable"})
+		return
+	}
+
+	// Get tools, resources, and prompts
+	capabilities := map[string]interface{}{
+		"server_info": serverInfo,
+	}
+
+	if serverInfo.Capabilities.Tools != nil {
+		tools, err := h.protocol.ListTools(ctx, serverURL)
+		if err == nil {
+			capabilities["tools"] = tools
+		}
+	}
+
+	if serverInfo.Capabilities.Resources != nil {
+		resources, err := h.protocol.ListResources(ctx, serverURL)
+		if err == nil {
+			capabilities["resources"] = resources
+		}
+	}
+
+	if serverInfo.Capabilities.Prompts != nil {
+		prompts, err := h.protocol.ListPrompts(ctx, serverURL)
+		if err == nil {
+			capabilities["prompts"] = prompts
+		}
+	}
+
+	c.JSON(http.StatusOK, capabilities)
+}
+
+// DiscoverTools discovers tools from a server
+func (h *EnhancedHandler) DiscoverTools(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	serverID, err := uuid.Parse(c.Param("server_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid server ID"})
+		return
+	}
+
+	serverURL, ok := h.serverURL(c, orgID, serverID)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
+
+	tools, err := h.toolManager.DiscoverTools(ctx, serverID, serverURL)
+	if err != nil {
+		h.logger.Error("Failed to discover tools", zap.Error(err))
+		c.JSON(apperrors.StatusCode(err, http.StatusInternalServerError), gin.H{"error": "Failed to discover tools"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tools_discovered": len(tools),
+		"tools":            tools,
+	})
+}
+
+// ListTools lists managed tools a page at a time. The response's next_cursor, when set,
+// is passed back as cursor to get the following page.
+func (h *EnhancedHandler) ListTools(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	var serverID *uuid.UUID
+	if serverIDStr := c.Query("server_id"); serverIDStr != "" {
+		if id, err := uuid.Parse(serverIDStr); err == nil {
+			serverID = &id
+		}
+	}
+
+	category := c.Query("category")
+	riskLevel := c.Query("risk_level")
+
+	var enabled *bool
+	if enabledStr := c.Query("enabled"); enabledStr != "" {
+		if e, err := strconv.ParseBool(enabledStr); err == nil {
+			enabled = &e
+		}
+	}
+
+	var cursor *mcp.ToolCursor
+	if cursorStr := c.Query("cursor"); cursorStr != "" {
+		decoded, err := mcp.DecodeToolCursor(cursorStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return
+		}
+		cursor = decoded
+	}
+
+	limit := mcp.DefaultToolPageSize
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+
+	var tags []string
+	if tagsStr := c.Query("tags"); tagsStr != "" {
+		tags = strings.Split(tagsStr, ",")
+	}
+
+	filter := mcp.ToolFilter{
+		ServerID:  serverID,
+		Category:  category,
+		RiskLevel: riskLevel,
+		Enabled:   enabled,
+		Tags:      tags,
+	}
+
+	tools, next, err := h.toolManager.ListToolsPage(c.Request.Context(), &orgID, filter, cursor, limit)
+	if err != nil {
+		h.logger.Error("Failed to list tools", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list tools"})
+		return
+	}
+
+	if serverID != nil {
+		deprecation, err := h.toolManager.ServerDeprecation(c.Request.Context(), &orgID, *serverID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			h.logger.Warn("Failed to check server deprecation", zap.Error(err))
+		} else if deprecation != nil {
+			c.Header("Deprecated", "true")
+		}
+	}
+
+	var nextCursor *string
+	if next != nil {
+		encoded := next.Encode()
+		nextCursor = &encoded
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tools":       tools,
+		"next_cursor": nextCursor,
+	})
+}
+
+// GetTool gets a specific tool
+func (h *EnhancedHandler) GetTool(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	toolID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tool ID"})
+		return
+	}
+
+	tool, err := h.toolManager.GetTool(&orgID, toolID)
+	if err != nil {
+		writeToolLookupError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tool)
+}
+
+// writeToolLookupError responds to a failed tool lookup with the status for its error type
+func writeToolLookupError(c *gin.Context, logger *zap.Logger, err error) {
+	status := apperrors.StatusCode(err, http.StatusInternalServerError)
+	if status == http.StatusNotFound {
+		c.JSON(status, gin.H{"error": "Tool not found"})
+		return
+	}
+	logger.Error("Failed to get tool", zap.Error(err))
+	c.JSON(status, gin.H{"error": "Failed to get tool"})
+}
+
+// ExecuteTool executes a tool
+func (h *EnhancedHandler) ExecuteTool(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	toolID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tool ID"})
+		return
+	}
+
+	var req struct {
+		Arguments map[string]interface{} `json:"arguments"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if h.shutdown != nil {
+		if !h.shutdown.Begin() {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Server is shutting down"})
+			return
+		}
+		defer h.shutdown.Done()
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
+
+	var userID *uuid.UUID
+	if value, exists := c.Get("user_id"); exists {
+		if id, ok := value.(uuid.UUID); ok {
+			userID = &id
+		}
+	}
+
+	execution, err := h.toolManager.ExecuteTool(ctx, &orgID, toolID, req.Arguments, userID)
+	if errors.Is(err, mcp.ErrDailyLimitExceeded) {
+		c.Header("X-Daily-Limit-Exceeded", "true")
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Daily tool execution limit exceeded"})
+		return
+	}
+	if errors.Is(err, mcp.ErrToolDenied) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	var violations mcp.ValidationErrors
+	if errors.As(err, &violations) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Invalid tool arguments", "validation_errors": violations})
+		return
+	}
+	if status, ok := apperrors.HTTPStatus(err); ok {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Tool execution failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, execution)
+}
+
+// BatchExecuteTools runs several tools in parallel and answers 207 with the result of
+// each, whether or not it succeeded
+func (h *EnhancedHandler) BatchExecuteTools(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	var req struct {
+		Executions     []mcp.BatchRequest `json:"executions" binding:"required"`
+		TimeoutSeconds int                `json:"timeout_seconds"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if len(req.Executions) == 0 || len(req.Executions) > mcp.MaxBatchExecutions {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("A batch must contain between 1 and %d executions", mcp.MaxBatchExecutions)})
+		return
+	}
+
+	timeout := 30 * time.Second
+	if req.TimeoutSeconds > 0 {
+		timeout = time.Duration(req.TimeoutSeconds) * time.Second
+	}
+	if timeout > 5*time.Minute {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "timeout_seconds must not exceed 300"})
+		return
+	}
+
+	if h.shutdown != nil {
+		if !h.shutdown.Begin() {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Server is shutting down"})
+			return
+		}
+		defer h.shutdown.Done()
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	defer cancel()
+
+	var userID *uuid.UUID
+	if value, exists := c.Get("user_id"); exists {
+		if id, ok := value.(uuid.UUID); ok {
+			userID = &id
+		}
+	}
+
+	results := h.toolManager.BatchExecute(ctx, &orgID, req.Executions, userID)
+	c.JSON(http.StatusMultiStatus, gin.H{"results": results})
+}
+
+// CloneToolMetadata copies a tool's category, tags and risk level to another tool
+func (h *EnhancedHandler) CloneToolMetadata(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	sourceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tool ID"})
+		return
+	}
+
+	var req struct {
+		TargetToolID string `json:"target_tool_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	targetID, err := uuid.Parse(req.TargetToolID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid target tool ID"})
+		return
+	}
+	if targetID == sourceID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Target tool must differ from source tool"})
+		return
+	}
+
+	var userID *uuid.UUID
+	if value, exists := c.Get("user_id"); exists {
+		if id, ok := value.(uuid.UUID); ok {
+			userID = &id
+		}
+	}
+
+	if err := h.toolManager.CloneMetadata(c.Request.Context(), &orgID, sourceID, targetID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Tool not found"})
+			return
+		}
+		h.logger.Error("Failed to clone tool metadata", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to clone tool metadata"})
+		return
+	}
+
+	tool, err := h.toolManager.GetTool(&orgID, targetID)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{"message": "Tool metadata cloned"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tool)
+}
+
+// CloneTool copies a tool definition to another server
+func (h *EnhancedHandler) CloneTool(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	sourceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tool ID"})
+		return
+	}
+
+	var req struct {
+		TargetServerID string `json:"target_server_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	targetServerID, err := uuid.Parse(req.TargetServerID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid target server ID"})
+		return
+	}
+
+	tool, err := h.toolManager.CloneTool(c.Request.Context(), &orgID, sourceID, targetServerID)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Tool or target server not found"})
+		case errors.Is(err, mcp.ErrToolNameConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": "Target server already has a tool with this name"})
+		default:
+			h.logger.Error("Failed to clone tool", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to clone tool"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, tool)
+}
+
+// PlanTools orders tools of one of the caller's organization's servers, and the tools
+// they depend on, into stages that run one after another. Tools in a stage can run in
+// parallel.
+func (h *EnhancedHandler) PlanTools(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	var req struct {
+		ServerID  uuid.UUID `json:"server_id" binding:"required"`
+		ToolNames []string  `json:"tool_names" binding:"required,min=1"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	stages, err := h.toolManager.BuildExecutionPlan(c.Request.Context(), &orgID, req.ServerID, req.ToolNames)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Server not found"})
+		case errors.Is(err, mcp.ErrUnknownTool):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, mcp.ErrCyclicDependency):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("Failed to build tool execution plan", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build execution plan"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"server_id": req.ServerID,
+			"stages":    stages,
+		},
+	})
+}
+
+// SetToolDependencies replaces the names of the tools that must succeed before a tool runs
+func (h *EnhancedHandler) SetToolDependencies(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	toolID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tool ID"})
+		return
+	}
+
+	var req struct {
+		Dependencies []string `json:"dependencies" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	tool, err := h.toolManager.SetToolDependencies(c.Request.Context(), &orgID, toolID, req.Dependencies)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Tool not found"})
+		case errors.Is(err, mcp.ErrUnknownTool), errors.Is(err, mcp.ErrCyclicDependency):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("Failed to set tool dependencies", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set tool dependencies"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, tool)
+}
+
+// SetToolQuota sets a tool's hourly and daily execution limits. A null limit clears it.
+func (h *EnhancedHandler) SetToolQuota(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	toolID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tool ID"})
+		return
+	}
+
+	var req struct {
+		MaxExecutionsPerHour *int `json:"max_executions_per_hour"`
+		MaxExecutionsPerDay  *int `json:"max_executions_per_day"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if (req.MaxExecutionsPerHour != nil && *req.MaxExecutionsPerHour < 0) ||
+		(req.MaxExecutionsPerDay != nil && *req.MaxExecutionsPerDay < 0) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Quota limits must not be negative"})
+		return
+	}
+
+	quota, err := h.toolManager.SetToolQuota(c.Request.Context(), &orgID, toolID, req.MaxExecutionsPerHour, req.MaxExecutionsPerDay)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Tool not found"})
+			return
+		}
+		h.logger.Error("Failed to set tool quota", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set tool quota"})
+		return
+	}
+
+	c.JSON(http.StatusOK, quota)
+}
+
+// AddToolTags adds tags to a tool
+func (h *EnhancedHandler) AddToolTags(c *gin.Context) {
+	h.updateToolTags(c, h.toolManager.AddTagsToTool)
+}
+
+// RemoveToolTags removes tags from a tool
+func (h *EnhancedHandler) RemoveToolTags(c *gin.Context) {
+	h.updateToolTags(c, h.toolManager.RemoveTagsFromTool)
+}
+
+// updateToolTags applies a tag change given as {"tags": [...]} and returns the tool
+func (h *EnhancedHandler) updateToolTags(c *gin.Context, update func(context.Context, *uuid.UUID, uuid.UUID, []string) (*mcp.ManagedTool, error)) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	toolID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tool ID"})
+		return
+	}
+
+	var req struct {
+		Tags []string `json:"tags" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	tool, err := update(c.Request.Context(), &orgID, toolID, req.Tags)
+	if err != nil {
+		if status, ok := apperrors.HTTPStatus(err); ok {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to update tool tags", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update tool tags"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tool)
+}
+
+// GetToolPolicy returns the allow and deny patterns applied to tool executions
+func (h *EnhancedHandler) GetToolPolicy(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": h.toolManager.ToolPolicy()})
+}
+
+// UpdateToolPolicy replaces the allow and deny patterns applied to tool executions. The
+// change lasts until the next restart, which loads the policy from config again.
+func (h *EnhancedHandler) UpdateToolPolicy(c *gin.Context) {
+	var policy mcp.ToolPolicy
+	if err := c.ShouldBindJSON(&policy); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.toolManager.SetToolPolicy(policy); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": h.toolManager.ToolPolicy()})
+}
+
+// BulkSetToolsEnabled enables or disables a batch of tools. When updated_before is
+// given, the update is rejected with 409 if any of the tools changed after it.
+func (h *EnhancedHandler) BulkSetToolsEnabled(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	var req struct {
+		ToolIDs       []uuid.UUID `json:"tool_ids" binding:"required"`
+		Enabled       *bool       `json:"enabled" binding:"required"`
+		UpdatedBefore *time.Time  `json:"updated_before"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if len(req.ToolIDs) == 0 || len(req.ToolIDs) > mcp.MaxBulkToolIDs {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("tool_ids must contain between 1 and %d IDs", mcp.MaxBulkToolIDs)})
+		return
+	}
+
+	result, err := h.toolManager.BulkSetEnabled(c.Request.Context(), &orgID, req.ToolIDs, *req.Enabled, req.UpdatedBefore)
+	var modified *mcp.ToolsModifiedError
+	if errors.As(err, &modified) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":          "Tools were modified after updated_before",
+			"modified_tools": modified.ToolIDs,
+		})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to bulk update tools", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update tools"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// ExportTools downloads the caller's organization's tool definitions as a JSON array,
+// optionally limited to the server_id query parameter
+func (h *EnhancedHandler) ExportTools(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	var serverID *uuid.UUID
+	if value := c.Query("server_id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid server ID"})
+			return
+		}
+		serverID = &id
+	}
+
+	data, err := h.toolManager.ExportTools(c.Request.Context(), &orgID, serverID)
+	if err != nil {
+		h.logger.Error("Failed to export tools", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export tools"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=tools-%s.json", time.Now().UTC().Format("20060102-150405")))
+	c.Data(http.StatusOK, "application/json", data)
+}
+
+// ImportTools stores exported tool definitions on one of the caller's organization's
+// servers. mode is merge_skip_existing (the default), merge_overwrite or replace_all.
+func (h *EnhancedHandler) ImportTools(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	var req struct {
+		TargetServerID uuid.UUID       `json:"target_server_id" binding:"required"`
+		Mode           mcp.ImportMode  `json:"mode"`
+		Tools          json.RawMessage `json:"tools" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if req.Mode == "" {
+		req.Mode = mcp.MergeSkipExisting
+	}
+
+	result, err := h.toolManager.ImportTools(c.Request.Context(), req.Tools, &orgID, req.TargetServerID, req.Mode)
+	if err != nil {
+		switch {
+		case errors.Is(err, mcp.ErrInvalidImportMode):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "mode must be merge_skip_existing, merge_overwrite or replace_all"})
+		case errors.Is(err, mcp.ErrInvalidToolExport):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "tools must be an array of exported tools"})
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Target server not found"})
+		default:
+			h.logger.Error("Failed to import tools", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import tools"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// GetToolRiskSummary counts the caller's organization's tools by risk level
+func (h *EnhancedHandler) GetToolRiskSummary(c *gin.Context) {
+	var orgID *uuid.UUID
+	if value, exists := c.Get("organization_id"); exists {
+		if id, ok := value.(uuid.UUID); ok {
+			orgID = &id
+		}
+	}
+
+	summary, err := h.toolManager.GetToolRiskSummary(c.Request.Context(), orgID)
+	if err != nil {
+		h.logger.Error("Failed to get tool risk summary", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get tool risk summary"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    summary,
+	})
+}
+
+// GetToolStats gets tool usage statistics
+func (h *EnhancedHandler) GetToolStats(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	toolID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tool ID"})
+		return
+	}
+
+	stats, err := h.toolManager.GetToolUsageStats(&orgID, toolID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tool not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetToolDocumentation returns documentation generated from a tool's input schema
+func (h *EnhancedHandler) GetToolDocumentation(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	toolID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tool ID"})
+		return
+	}
+
+	tool, err := h.toolManager.GetTool(&orgID, toolID)
+	if err != nil {
+		writeToolLookupError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, h.docGenerator.Generate(tool))
+}
+
+// ListToolExecutions lists tool execution history, optionally streamed as CSV
+func (h *EnhancedHandler) ListToolExecutions(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	var filter mcp.ToolExecutionFilter
+
+	for param, target := range map[string]**uuid.UUID{
+		"tool_id":   &filter.ToolID,
+		"server_id": &filter.ServerID,
+		"user_id":   &filter.UserID,
+	} {
+		if value := c.Query(param); value != "" {
+			id, err := uuid.Parse(value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param})
+				return
+			}
+			*target = &id
+		}
+	}
+
+	filter.Status = c.Query("status")
+
+	for param, target := range map[string]**time.Time{
+		"start": &filter.Start,
+		"end":   &filter.End,
+	} {
+		if value := c.Query(param); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param + " time, expected RFC3339"})
+				return
+			}
+			*target = &t
+		}
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			filter.Limit = l
+		}
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			filter.Offset = o
+		}
+	}
+
+	if c.Query("format") == "csv" {
+		h.streamToolExecutionsCSV(c, orgID, filter)
+		return
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = 50
+	}
+
+	executions, err := h.toolManager.ListToolExecutions(c.Request.Context(), &orgID, filter)
+	if err != nil {
+		h.logger.Error("Failed to list tool executions", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list tool executions"})
+		return
+	}
+
+	middleware.SetPage(c, middleware.NewPage(executions, filter.Limit, filter.Offset))
+
+	c.JSON(http.StatusOK, gin.H{
+		"executions": executions,
+		"limit":      filter.Limit,
+		"offset":     filter.Offset,
+	})
+}
+
+// GetToolExecutions returns a page of a tool's executions, filtered by status, user and
+// time, with the duration percentiles of all matching executions. The response's
+// next_cursor, when set, is passed back as cursor to get the following page.
+func (h *EnhancedHandler) GetToolExecutions(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	toolID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tool ID"})
+		return
+	}
+
+	filter := mcp.ExecutionFilter{
+		Status: c.Query("status"),
+		Limit:  50,
+	}
+	switch filter.Status {
+	case "", "completed", "failed", "running":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status, expected completed, failed or running"})
+		return
+	}
+
+	if value := c.Query("user_id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id"})
+			return
+		}
+		filter.UserID = &id
+	}
+
+	// start and end are the older names of from and to
+	for _, param := range []struct {
+		names  []string
+		target **time.Time
+	}{
+		{[]string{"from", "start"}, &filter.Start},
+		{[]string{"to", "end"}, &filter.End},
+	} {
+		for _, name := range param.names {
+			value := c.Query(name)
+			if value == "" {
+				continue
+			}
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + name + " time, expected RFC3339"})
+				return
+			}
+			*param.target = &t
+			break
+		}
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			filter.Limit = l
+		}
+	}
+	if filter.Limit > 200 {
+		filter.Limit = 200
+	}
+
+	if cursorStr := c.Query("cursor"); cursorStr != "" {
+		cursor, err := mcp.DecodeExecutionCursor(cursorStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return
+		}
+		filter.Cursor = cursor
+	}
+
+	ctx := c.Request.Context()
+
+	executions, next, err := h.toolManager.ListExecutions(ctx, &orgID, toolID, filter)
+	if err != nil {
+		h.logger.Error("Failed to get tool execution history", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get tool execution history"})
+		return
+	}
+
+	percentiles, err := h.toolManager.ExecutionDurationPercentiles(ctx, &orgID, toolID, filter)
+	if err != nil {
+		h.logger.Error("Failed to get tool execution durations", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get tool execution history"})
+		return
+	}
+
+	var nextCursor *string
+	if next != nil {
+		encoded := next.Encode()
+		nextCursor = &encoded
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tool_id":     toolID,
+		"executions":  executions,
+		"durations":   percentiles,
+		"next_cursor": nextCursor,
+	})
+}
+
+// ReplayToolExecution runs a past tool execution again with its original arguments
+func (h *EnhancedHandler) ReplayToolExecution(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	executionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid execution ID"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
+
+	var userID *uuid.UUID
+	if value, exists := c.Get("user_id"); exists {
+		if id, ok := value.(uuid.UUID); ok {
+			userID = &id
+		}
+	}
+
+	execution, err := h.toolManager.ReplayToolExecution(ctx, &orgID, executionID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tool execution not found"})
+		return
+	}
+	if errors.Is(err, mcp.ErrDailyLimitExceeded) {
+		c.Header("X-Daily-Limit-Exceeded", "true")
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Daily tool execution limit exceeded"})
+		return
+	}
+	if errors.Is(err, mcp.ErrToolDenied) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	var violations mcp.ValidationErrors
+	if errors.As(err, &violations) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Invalid tool arguments", "validation_errors": violations})
+		return
+	}
+	if status, ok := apperrors.HTTPStatus(err); ok {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Tool execution replay failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"replayed_execution_id": executionID,
+		"execution":             execution,
+	})
+}
+
+// GetExecutionAlerts returns the alerts raised by a tool execution
+func (h *EnhancedHandler) GetExecutionAlerts(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	executionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid execution ID"})
+		return
+	}
+
+	alerts, err := h.toolManager.ListExecutionAlerts(c.Request.Context(), &orgID, executionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Tool execution not found"})
+			return
+		}
+		h.logger.Error("Failed to list execution alerts", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list execution alerts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"execution_id": executionID,
+		"alerts":       alerts,
+	})
+}
+
+// streamToolExecutionsCSV writes matching tool executions to the response as CSV
+func (h *EnhancedHandler) streamToolExecutionsCSV(c *gin.Context, orgID uuid.UUID, filter mcp.ToolExecutionFilter) {
+	filename := fmt.Sprintf("executions-%s.csv", time.Now().Format("2006-01-02"))
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	writer.Write([]string{"execution_id", "tool_name", "server_name", "user_id", "status", "duration_ms", "executed_at"})
+
+	rowCount := 0
+	err := h.toolManager.StreamToolExecutions(c.Request.Context(), &orgID, filter, func(record *mcp.ToolExecutionRecord) error {
+		userID := ""
+		if record.UserID != nil {
+			userID = record.UserID.String()
+		}
+
+		if err := writer.Write([]string{
+			record.ID.String(),
+			record.ToolName,
+			record.ServerName,
+			userID,
+			record.Status,
+			strconv.FormatFloat(record.DurationMs, 'f', 2, 64),
+			record.ExecutedAt.UTC().Format(time.RFC3339),
+		}); err != nil {
+			return err
+		}
+
+		// Flush periodically so rows reach the client as they are read
+		rowCount++
+		if rowCount%executionCSVFlushEvery == 0 {
+			writer.Flush()
+			c.Writer.Flush()
+		}
+		return writer.Error()
+	})
+
+	writer.Flush()
+	if err != nil {
+		// Headers are already sent, so the best we can do is log and stop
+		h.logger.Error("Failed to stream tool executions", zap.Int("rows_written", rowCount), zap.Error(err))
+	}
+}
+
+// ListResources lists resources from a server
+func (h *EnhancedHandler) ListResources(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	serverID, err := uuid.Parse(c.Param("server_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid server ID"})
+		return
+	}
+
+	serverURL, ok := h.serverURL(c, orgID, serverID)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	resources, err := h.protocol.ListResources(ctx, serverURL)
+	if err != nil {
+		h.logger.Error("Failed to list resources", zap.Error(err))
+		c.JSON(apperrors.StatusCode(err, http.StatusInternalServerError), gin.H{"error": "Failed to list resources"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"resources": resources,
+	})
+}
+
+// ReadResource reads a resource from a server
+func (h *EnhancedHandler) ReadResource(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	var req struct {
+		ServerID    uuid.UUID `json:"server_id" binding:"required"`
+		ResourceURI string    `json:"resource_uri" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	serverURL, ok := h.serverURL(c, orgID, req.ServerID)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	contents, err := h.protocol.ReadResource(ctx, serverURL, req.ResourceURI)
+	if err != nil {
+		h.logger.Error("Failed to read resource", zap.Error(err))
+		c.JSON(apperrors.StatusCode(err, http.StatusInternalServerError), gin.H{"error": "Failed to read resource"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"resource": gin.H{
+			"contents": contents,
+		},
+	})
+}
+
+// PreviewResource reads a resource and returns its raw content with the resource's content type
+func (h *EnhancedHandler) PreviewResource(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	serverID, err := uuid.Parse(c.Query("server_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid server ID"})
+		return
+	}
+
+	resourceURI := c.Query("uri")
+	if resourceURI == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "uri is required"})
+		return
+	}
+
+	serverURL, ok := h.serverURL(c, orgID, serverID)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	contents, err := h.protocol.ReadResource(ctx, serverURL, resourceURI)
+	if err != nil {
+		h.logger.Error("Failed to read resource", zap.Error(err))
+		c.JSON(apperrors.StatusCode(err, http.StatusInternalServerError), gin.H{"error": "Failed to read resource"})
+		return
+	}
+
+	if len(contents) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Resource has no content"})
+		return
+	}
+
+	// Prefer the content matching the requested URI
+	content := &contents[0]
+	for i := range contents {
+		if contents[i].URI == resourceURI {
+			content = &contents[i]
+			break
+		}
+	}
+
+	contentType := content.MIMEType
+	if contentType == "" {
+		contentType = "text/plain; charset=utf-8"
+		if content.IsBinary() {
+			contentType = "application/octet-stream"
+		}
+	}
+
+	// Resource content comes from an untrusted server, so never let the browser
+	// sniff it or run scripts in our origin
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Security-Policy", "sandbox")
+
+	if content.IsBinary() {
+		c.Data(http.StatusOK, contentType, content.Blob)
+		return
+	}
+	c.Data(http.StatusOK, contentType, []byte(content.Text))
+}
+
+// SubscribeResource subscribes to changes of a resource on a server that supports
+// resource subscriptions
+func (h *EnhancedHandler) SubscribeResource(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	var req struct {
+		ServerID    uuid.UUID `json:"server_id" binding:"required"`
+		ResourceURI string    `json:"resource_uri" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	serverURL, ok := h.serverURL(c, orgID, req.ServerID)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	serverInfo, err := h.protocol.Initialize(ctx, serverURL)
+	if err != nil {
+		h.logger.Error("Failed to initialize server", zap.Error(err))
+		c.JSON(apperrors.StatusCode(err, http.StatusInternalServerError), gin.H{"error": "Failed to initialize server"})
+		return
+	}
+	if serverInfo.Capabilities.Resources == nil || !serverInfo.Capabilities.Resources.Subscribe {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Server does not support resource subscriptions"})
+		return
+	}
+
+	subscription := &ResourceSubscription{
+		ID:          uuid.New(),
+		ServerID:    req.ServerID,
+		ResourceURI: req.ResourceURI,
+		CreatedAt:   time.Now(),
+
+		organizationID: orgID,
+	}
+
+	unsubscribe, err := h.protocol.SubscribeResource(ctx, serverURL, req.ResourceURI, func(update mcp.MCPResourceUpdate) {
+		h.recordResourceUpdate(subscription, update)
+	})
+	if err != nil {
+		h.logger.Error("Failed to subscribe to resource", zap.Error(err))
+		c.JSON(apperrors.StatusCode(err, http.StatusInternalServerError), gin.H{"error": "Failed to subscribe to resource"})
+		return
+	}
+
+	h.subscriptionsMu.Lock()
+	subscription.unsubscribe = unsubscribe
+	h.subscriptions[subscription.ID] = subscription
+	view := *subscription
+	h.subscriptionsMu.Unlock()
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    view,
+	})
+}
+
+// recordResourceUpdate notes a change reported for a subscribed resource
+func (h *EnhancedHandler) recordResourceUpdate(subscription *ResourceSubscription, update mcp.MCPResourceUpdate) {
+	h.subscriptionsMu.Lock()
+	subscription.Updates++
+	subscription.LastUpdate = &update
+	h.subscriptionsMu.Unlock()
+
+	h.logger.Info("Subscribed resource changed",
+		zap.String("subscription_id", subscription.ID.String()),
+		zap.String("server_id", subscription.ServerID.String()),
+		zap.String("uri", update.URI),
+	)
+}
+
+// UnsubscribeResource ends a resource subscription
+func (h *EnhancedHandler) UnsubscribeResource(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	var req struct {
+		SubscriptionID uuid.UUID `json:"subscription_id" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	h.subscriptionsMu.Lock()
+	subscription, exists := h.subscriptions[req.SubscriptionID]
+	exists = exists && subscription.organizationID == orgID
+	if exists {
+		delete(h.subscriptions, req.SubscriptionID)
+	}
+	h.subscriptionsMu.Unlock()
+
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Subscription not found"})
+		return
+	}
+
+	subscription.unsubscribe()
+
+	h.subscriptionsMu.Lock()
+	view := *subscription
+	h.subscriptionsMu.Unlock()
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    view,
+	})
+}
+
+// CloseResourceSubscriptions ends every active resource subscription
+func (h *EnhancedHandler) CloseResourceSubscriptions() {
+	h.subscriptionsMu.Lock()
+	subscriptions := h.subscriptions
+	h.subscriptions = make(map[uuid.UUID]*ResourceSubscription)
+	h.subscriptionsMu.Unlock()
+
+	for _, subscription := range subscriptions {
+		subscription.unsubscribe()
+	}
+}
+
+// StartMonitoring starts monitoring a server
+func (h *EnhancedHandler) StartMonitoring(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	serverID, err := uuid.Parse(c.Param("server_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid server ID"})
+		return
+	}
+
+	var req struct {
+		IntervalSeconds int `json:"interval_seconds"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil || req.IntervalSeconds == 0 {
+		req.IntervalSeconds = 30 // Default to 30 seconds
+	}
+
+	// Get server details
+	var serverURL, serverName string
+	var healthCheckJSON []byte
+	err = h.db.QueryRow("SELECT url, name, health_check_config FROM mcp_servers WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL", serverID, orgID).
+		Scan(&serverURL, &serverName, &healthCheckJSON)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Server not found"})
+		return
+	}
+
+	var healthCheck mcp.HealthCheckConfig
+	if len(healthCheckJSON) > 0 {
+		if err := json.Unmarshal(healthCheckJSON, &healthCheck); err != nil {
+			h.logger.Warn("Invalid health check config, using JSON-RPC ping",
+				zap.String("server_id", serverID.String()), zap.Error(err))
+			healthCheck = mcp.HealthCheckConfig{}
+		}
+	}
+
+	interval := time.Duration(req.IntervalSeconds) * time.Second
+	err = h.monitor.StartMonitoring(serverID, serverURL, serverName, interval, healthCheck, monitoring.DefaultBackoffPolicy(interval))
+	if err != nil {
+		h.logger.Error("Failed to start monitoring", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start monitoring"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Monitoring started",
+		"interval": req.IntervalSeconds,
+	})
+}
+
+// DeepHealthCheck runs a deep health check against a server
+func (h *EnhancedHandler) DeepHealthCheck(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	serverID, err := uuid.Parse(c.Param("server_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid server ID"})
+		return
+	}
+
+	serverURL, ok := h.serverURL(c, orgID, serverID)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
+
+	result, err := h.protocol.DeepHealthCheck(ctx, serverURL)
+	if err != nil {
+		h.logger.Error("Deep health check failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Deep health check failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetServerHistory returns response time, uptime and error rate statistics computed
+// from a server's status history, with a series for charting. The window query
+// parameter defaults to 24h and accepts durations up to 7d.
+func (h *EnhancedHandler) GetServerHistory(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	serverID, err := uuid.Parse(c.Param("server_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid server ID"})
+		return
+	}
+
+	window, err := monitoring.ParseHistoryWindow(c.DefaultQuery("window", "24h"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid window, expected a duration such as 1h, 24h or 7d"})
+		return
+	}
+
+	metrics, err := h.monitor.GetHistoricalMetrics(c.Request.Context(), orgID, serverID, window)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Server not found"})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get server history", zap.String("server_id", serverID.String()), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get server history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    metrics,
+	})
+}
+
+// UpdateHealthCheckConfig sets how a server's health is checked. Takes effect the
+// next time monitoring is started for the server.
+func (h *EnhancedHandler) UpdateHealthCheckConfig(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	serverID, err := uuid.Parse(c.Param("server_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid server ID"})
+		return
+	}
+
+	var req mcp.HealthCheckConfig
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if req.Method == "" {
+		req.Method = mcp.HealthCheckJSONRPCPing
+	}
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	configJSON, err := json.Marshal(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid health check config"})
+		return
+	}
+
+	result, err := h.db.Exec("UPDATE mcp_servers SET health_check_config = $1, updated_at = NOW() WHERE id = $2 AND organization_id = $3 AND deleted_at IS NULL",
+		configJSON, serverID, orgID)
+	if err != nil {
+		h.logger.Error("Failed to update health check config", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update health check config"})
+		return
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Server not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"server_id":    serverID,
+		"health_check": req,
+	})
+}
+
+// SetServerCheckInterval sets how often a server's health is checked. Zero seconds
+// restores the default interval.
+func (h *EnhancedHandler) SetServerCheckInterval(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	serverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid server ID"})
+		return
+	}
+
+	var req struct {
+		Seconds *int `json:"seconds" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if h.healthChecker == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Periodic health checks are not running"})
+		return
+	}
+
+	interval := time.Duration(*req.Seconds) * time.Second
+	if err := h.healthChecker.SetServerCheckInterval(c.Request.Context(), orgID, serverID, interval); err != nil {
+		switch {
+		case errors.Is(err, monitoring.ErrInvalidCheckInterval):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Server not found"})
+		default:
+			h.logger.Error("Failed to set server check interval", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set server check interval"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"server_id":              serverID,
+		"check_interval_seconds": *req.Seconds,
+	})
+}
+
+// StopMonitoring stops monitoring a server
+func (h *EnhancedHandler) StopMonitoring(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	serverID, err := uuid.Parse(c.Param("server_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid server ID"})
+		return
+	}
+
+	serverURL, ok := h.serverURL(c, orgID, serverID)
+	if !ok {
+		return
+	}
+
+	h.monitor.StopMonitoring(serverURL)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Monitoring stopped",
+	})
+}
+
+// GetMonitoringStatus gets monitoring status for all servers
+func (h *EnhancedHandler) GetMonitoringStatus(c *gin.Context) {
+	statuses := h.monitor.GetAllStatuses()
+	c.JSON(http.StatusOK, gin.H{
+		"statuses": statuses,
+		"connection_pools": gin.H{
+			"protocol": h.protocol.ConnectionPoolStats(),
+			"tools":    h.toolManager.ConnectionPoolStats(),
+		},
+	})
+}
+
+// GetAlerts gets recent monitoring alerts
+func (h *EnhancedHandler) GetAlerts(c *gin.Context) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	query := monitoring.AlertQuery{OrganizationID: &orgID, Limit: 50}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			query.Limit = l
+		}
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			query.Offset = o
+		}
+	}
+
+	if serverIDStr := c.Query("server_id"); serverIDStr != "" {
+		serverID, err := uuid.Parse(serverIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid server_id"})
+			return
+		}
+		query.ServerID = &serverID
+	}
+
+	if levelStr := c.Query("level"); levelStr != "" {
+		level := monitoring.AlertLevel(levelStr)
+		switch level {
+		case monitoring.AlertLevelInfo, monitoring.AlertLevelWarning, monitoring.AlertLevelCritical:
+			query.Level = &level
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid level"})
+			return
+		}
+	}
+
+	if resolvedStr := c.Query("resolved"); resolvedStr != "" {
+		resolved, err := strconv.ParseBool(resolvedStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resolved value"})
+			return
+		}
+		query.Resolved = &resolved
+	}
+
+	for param, target := range map[string]**time.Time{
+		"since": &query.Since,
+		"until": &query.Until,
+	} {
+		if value := c.Query(param); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param + " time, expected RFC3339"})
+				return
+			}
+			*target = &t
+		}
+	}
+
+	alerts, total, err := h.monitor.QueryAlerts(query)
+	if err != nil {
+		h.logger.Error("Failed to get alerts", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get alerts"})
+		return
+	}
+
+	page := middleware.NewPage(alerts, query.Limit, query.Offset)
+	page.Total = total
+	middleware.SetPage(c, page)
+
+	c.Header("X-Total-Count", strconv.Itoa(total))
+	c.JSON(http.StatusOK, gin.H{
+		"alerts": alerts,
+		"total":  total,
+		"limit":  query.Limit,
+		"offset": query.Offset,
+	})
+}
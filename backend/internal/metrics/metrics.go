@@ -0,0 +1,182 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestKey identifies a request counter series
+type requestKey struct {
+	method string
+	status string
+}
+
+// requestStats holds counters for a single request series
+type requestStats struct {
+	count           uint64
+	durationSeconds float64
+}
+
+// maxCORSViolationOrigins bounds the number of origin label values; further origins are
+// counted under "other" so a client cannot grow the registry without limit
+const maxCORSViolationOrigins = 500
+
+// Registry collects HTTP request metrics and renders them in the Prometheus text format
+type Registry struct {
+	mu             sync.Mutex
+	requests       map[requestKey]*requestStats
+	corsViolations map[string]uint64
+	inFlight       int64
+	startTime      time.Time
+
+	monitor monitorMetrics
+}
+
+// NewRegistry creates a new metrics registry
+func NewRegistry() *Registry {
+	return &Registry{
+		requests:       make(map[requestKey]*requestStats),
+		corsViolations: make(map[string]uint64),
+		startTime:      time.Now(),
+	}
+}
+
+// IncCORSViolation counts a cross-origin request from an origin that is not allowed
+func (r *Registry) IncCORSViolation(origin string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.corsViolations[origin]; !exists && len(r.corsViolations) >= maxCORSViolationOrigins {
+		origin = "other"
+	}
+	r.corsViolations[origin]++
+}
+
+// Middleware records request counts and durations for every request
+func (r *Registry) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		r.mu.Lock()
+		r.inFlight++
+		r.mu.Unlock()
+
+		c.Next()
+
+		key := requestKey{method: c.Request.Method, status: strconv.Itoa(c.Writer.Status())}
+		duration := time.Since(start).Seconds()
+
+		r.mu.Lock()
+		r.inFlight--
+		stats, exists := r.requests[key]
+		if !exists {
+			stats = &requestStats{}
+			r.requests[key] = stats
+		}
+		stats.count++
+		stats.durationSeconds += duration
+		r.mu.Unlock()
+	}
+}
+
+// Handler serves the collected metrics
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.write(w)
+	})
+}
+
+// write renders all metrics in the Prometheus text exposition format
+func (r *Registry) write(w http.ResponseWriter) {
+	r.mu.Lock()
+	keys := make([]requestKey, 0, len(r.requests))
+	for key := range r.requests {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].method != keys[j].method {
+			return keys[i].method < keys[j].method
+		}
+		return keys[i].status < keys[j].status
+	})
+	snapshot := make([]requestStats, len(keys))
+	for i, key := range keys {
+		snapshot[i] = *r.requests[key]
+	}
+	inFlight := r.inFlight
+	origins := make([]string, 0, len(r.corsViolations))
+	for origin := range r.corsViolations {
+		origins = append(origins, origin)
+	}
+	sort.Strings(origins)
+	violations := make([]uint64, len(origins))
+	for i, origin := range origins {
+		violations[i] = r.corsViolations[origin]
+	}
+	r.mu.Unlock()
+
+	fmt.Fprintln(w, "# HELP http_requests_total Total number of HTTP requests.")
+	fmt.Fprintln(w, "# TYPE http_requests_total counter")
+	for i, key := range keys {
+		fmt.Fprintf(w, "http_requests_total{method=%q,status=%q} %d\n", key.method, key.status, snapshot[i].count)
+	}
+
+	fmt.Fprintln(w, "# HELP http_request_duration_seconds_sum Total time spent serving HTTP requests.")
+	fmt.Fprintln(w, "# TYPE http_request_duration_seconds_sum counter")
+	for i, key := range keys {
+		fmt.Fprintf(w, "http_request_duration_seconds_sum{method=%q,status=%q} %g\n", key.method, key.status, snapshot[i].durationSeconds)
+	}
+
+	fmt.Fprintln(w, "# HELP http_requests_in_flight Number of HTTP requests currently being served.")
+	fmt.Fprintln(w, "# TYPE http_requests_in_flight gauge")
+	fmt.Fprintf(w, "http_requests_in_flight %d\n", inFlight)
+
+	fmt.Fprintln(w, "# HELP cors_violations_total Cross-origin requests from origins that are not allowed.")
+	fmt.Fprintln(w, "# TYPE cors_violations_total counter")
+	for i, origin := range origins {
+		fmt.Fprintf(w, "cors_violations_total{origin=%q} %d\n", origin, violations[i])
+	}
+
+	r.monitor.write(w)
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	fmt.Fprintln(w, "# HELP go_goroutines Number of goroutines that currently exist.")
+	fmt.Fprintln(w, "# TYPE go_goroutines gauge")
+	fmt.Fprintf(w, "go_goroutines %d\n", runtime.NumGoroutine())
+
+	fmt.Fprintln(w, "# HELP go_memstats_alloc_bytes Number of bytes allocated and still in use.")
+	fmt.Fprintln(w, "# TYPE go_memstats_alloc_bytes gauge")
+	fmt.Fprintf(w, "go_memstats_alloc_bytes %d\n", mem.Alloc)
+
+	fmt.Fprintln(w, "# HELP process_uptime_seconds Time since the process started.")
+	fmt.Fprintln(w, "# TYPE process_uptime_seconds gauge")
+	fmt.Fprintf(w, "process_uptime_seconds %g\n", time.Since(r.startTime).Seconds())
+}
+
+// NewServer creates the metrics HTTP server, serving only /metrics and /healthz/live
+func NewServer(addr string, registry *Registry) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", registry.Handler())
+	mux.HandleFunc("GET /healthz/live", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"status":"ok"}`)
+	})
+
+	return &http.Server{
+		Addr:         addr,
+		Handler:      mux,
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 5 * time.Second,
+	}
+}
@@ -0,0 +1,167 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// HealthCheckBucketsMs are the upper bounds of the health check duration histogram
+var HealthCheckBucketsMs = []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+// healthCheckKey identifies a health check duration histogram series
+type healthCheckKey struct {
+	serverID string
+	status   string
+}
+
+// histogram counts observations in cumulative buckets
+type histogram struct {
+	counts []uint64 // per bucket in HealthCheckBucketsMs, not cumulative
+	count  uint64
+	sum    float64
+}
+
+// monitorMetrics holds the MCP server monitoring metrics
+type monitorMetrics struct {
+	mu             sync.Mutex
+	serverStatus   map[string]float64
+	healthChecks   map[healthCheckKey]*histogram
+	alerts         map[string]uint64
+	monitoredTotal int
+}
+
+// SetServerStatus records whether a monitored server is up
+func (r *Registry) SetServerStatus(serverID string, up bool) {
+	m := &r.monitor
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.serverStatus == nil {
+		m.serverStatus = make(map[string]float64)
+	}
+	value := 0.0
+	if up {
+		value = 1
+	}
+	m.serverStatus[serverID] = value
+}
+
+// RemoveServer drops the status of a server that is no longer monitored. Its health
+// check histograms are kept, as counters must not go backwards.
+func (r *Registry) RemoveServer(serverID string) {
+	m := &r.monitor
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.serverStatus, serverID)
+}
+
+// ObserveHealthCheck records the duration of a health check of a server
+func (r *Registry) ObserveHealthCheck(serverID, status string, duration time.Duration) {
+	m := &r.monitor
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.healthChecks == nil {
+		m.healthChecks = make(map[healthCheckKey]*histogram)
+	}
+	key := healthCheckKey{serverID: serverID, status: status}
+	h, exists := m.healthChecks[key]
+	if !exists {
+		h = &histogram{counts: make([]uint64, len(HealthCheckBucketsMs))}
+		m.healthChecks[key] = h
+	}
+
+	ms := float64(duration) / float64(time.Millisecond)
+	for i, bound := range HealthCheckBucketsMs {
+		if ms <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+	h.count++
+	h.sum += ms
+}
+
+// IncAlert counts a monitoring alert of the given level
+func (r *Registry) IncAlert(level string) {
+	m := &r.monitor
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.alerts == nil {
+		m.alerts = make(map[string]uint64)
+	}
+	m.alerts[level]++
+}
+
+// SetMonitoredServers records how many servers are being monitored
+func (r *Registry) SetMonitoredServers(count int) {
+	m := &r.monitor
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.monitoredTotal = count
+}
+
+// write renders the monitoring metrics in the Prometheus text exposition format
+func (m *monitorMetrics) write(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Fprintln(w, "# HELP server_status Whether a monitored MCP server is up (1) or down (0).")
+	fmt.Fprintln(w, "# TYPE server_status gauge")
+	for _, serverID := range sortedKeys(m.serverStatus) {
+		fmt.Fprintf(w, "server_status{server_id=%q} %g\n", serverID, m.serverStatus[serverID])
+	}
+
+	keys := make([]healthCheckKey, 0, len(m.healthChecks))
+	for key := range m.healthChecks {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].serverID != keys[j].serverID {
+			return keys[i].serverID < keys[j].serverID
+		}
+		return keys[i].status < keys[j].status
+	})
+
+	fmt.Fprintln(w, "# HELP health_check_duration_ms Duration of MCP server health checks in milliseconds.")
+	fmt.Fprintln(w, "# TYPE health_check_duration_ms histogram")
+	for _, key := range keys {
+		h := m.healthChecks[key]
+		var cumulative uint64
+		for i, bound := range HealthCheckBucketsMs {
+			cumulative += h.counts[i]
+			fmt.Fprintf(w, "health_check_duration_ms_bucket{server_id=%q,status=%q,le=%q} %d\n",
+				key.serverID, key.status, strconv.FormatFloat(bound, 'g', -1, 64), cumulative)
+		}
+		fmt.Fprintf(w, "health_check_duration_ms_bucket{server_id=%q,status=%q,le=\"+Inf\"} %d\n", key.serverID, key.status, h.count)
+		fmt.Fprintf(w, "health_check_duration_ms_sum{server_id=%q,status=%q} %g\n", key.serverID, key.status, h.sum)
+		fmt.Fprintf(w, "health_check_duration_ms_count{server_id=%q,status=%q} %d\n", key.serverID, key.status, h.count)
+	}
+
+	fmt.Fprintln(w, "# HELP alerts_total Monitoring alerts raised, by level.")
+	fmt.Fprintln(w, "# TYPE alerts_total counter")
+	for _, level := range sortedKeys(m.alerts) {
+		fmt.Fprintf(w, "alerts_total{level=%q} %d\n", level, m.alerts[level])
+	}
+
+	fmt.Fprintln(w, "# HELP monitored_servers_total Number of MCP servers being monitored.")
+	fmt.Fprintln(w, "# TYPE monitored_servers_total gauge")
+	fmt.Fprintf(w, "monitored_servers_total %d\n", m.monitoredTotal)
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// scrape returns the metrics served by the registry's handler
+func scrape(t *testing.T, r *Registry) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := io.ReadAll(rec.Body)
+	if err != nil {
+		t.Fatalf("failed to read metrics: %v", err)
+	}
+	return string(body)
+}
+
+func TestMonitorMetrics(t *testing.T) {
+	r := NewRegistry()
+
+	r.SetMonitoredServers(2)
+	r.SetServerStatus("srv-a", true)
+	r.SetServerStatus("srv-b", false)
+	r.ObserveHealthCheck("srv-a", "online", 3*time.Millisecond)
+	r.ObserveHealthCheck("srv-a", "online", 40*time.Millisecond)
+	r.ObserveHealthCheck("srv-a", "online", 20*time.Second)
+	r.IncAlert("critical")
+	r.IncAlert("critical")
+	r.IncAlert("warning")
+
+	body := scrape(t, r)
+
+	for _, want := range []string{
+		"# TYPE server_status gauge",
+		`server_status{server_id="srv-a"} 1`,
+		`server_status{server_id="srv-b"} 0`,
+		"# TYPE health_check_duration_ms histogram",
+		`health_check_duration_ms_bucket{server_id="srv-a",status="online",le="5"} 1`,
+		`health_check_duration_ms_bucket{server_id="srv-a",status="online",le="25"} 1`,
+		`health_check_duration_ms_bucket{server_id="srv-a",status="online",le="50"} 2`,
+		`health_check_duration_ms_bucket{server_id="srv-a",status="online",le="10000"} 2`,
+		`health_check_duration_ms_bucket{server_id="srv-a",status="online",le="+Inf"} 3`,
+		`health_check_duration_ms_sum{server_id="srv-a",status="online"} 20043`,
+		`health_check_duration_ms_count{server_id="srv-a",status="online"} 3`,
+		"# TYPE alerts_total counter",
+		`alerts_total{level="critical"} 2`,
+		`alerts_total{level="warning"} 1`,
+		"monitored_servers_total 2",
+	} {
+		if !strings.Contains(body, want+"\n") {
+			t.Errorf("metrics missing %q", want)
+		}
+	}
+}
+
+func TestMonitorMetricsRemoveServer(t *testing.T) {
+	r := NewRegistry()
+
+	r.SetServerStatus("srv-a", true)
+	r.ObserveHealthCheck("srv-a", "online", time.Millisecond)
+	r.RemoveServer("srv-a")
+
+	body := scrape(t, r)
+	if strings.Contains(body, `server_status{server_id="srv-a"}`) {
+		t.Error("status of a removed server is still reported")
+	}
+	if !strings.Contains(body, `health_check_duration_ms_count{server_id="srv-a",status="online"} 1`) {
+		t.Error("health check histogram of a removed server was dropped")
+	}
+}
+
+func TestMonitorMetricsEmpty(t *testing.T) {
+	body := scrape(t, NewRegistry())
+
+	if !strings.Contains(body, "monitored_servers_total 0\n") {
+		t.Error("metrics missing monitored_servers_total 0")
+	}
+	if strings.Contains(body, "server_status{") || strings.Contains(body, "alerts_total{") {
+		t.Error("metrics report series before anything was recorded")
+	}
+}
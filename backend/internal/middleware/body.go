@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultMaxBodyBytes is the request body size limit used when none is configured
+const DefaultMaxBodyBytes int64 = 1 << 20
+
+// BodySizeLimit rejects requests whose body is larger than maxBytes with 413. The body
+// is read through http.MaxBytesReader before the handler runs, so chunked requests
+// without a Content-Length are caught as well.
+func BodySizeLimit(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > maxBytes {
+			abortBodyTooLarge(c, maxBytes)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				abortBodyTooLarge(c, maxBytes)
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+			return
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+	}
+}
+
+// abortBodyTooLarge responds with 413 and the size limit
+func abortBodyTooLarge(c *gin.Context, maxBytes int64) {
+	c.Header("Connection", "close")
+	c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+		"error":     "Request body too large",
+		"code":      "REQUEST_BODY_TOO_LARGE",
+		"max_bytes": maxBytes,
+	})
+}
+
+// EnforceContentType rejects POST, PUT and PATCH requests with a body whose media type
+// is not one of allowed with 415. Parameters such as charset are ignored.
+func EnforceContentType(allowed ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+		default:
+			c.Next()
+			return
+		}
+		if c.Request.ContentLength == 0 && len(c.Request.TransferEncoding) == 0 {
+			c.Next()
+			return
+		}
+
+		mediaType, _, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+		if err == nil {
+			for _, contentType := range allowed {
+				if strings.EqualFold(mediaType, contentType) {
+					c.Next()
+					return
+				}
+			}
+		}
+
+		c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, gin.H{
+			"error":   "Unsupported Content-Type",
+			"code":    "UNSUPPORTED_CONTENT_TYPE",
+			"allowed": allowed,
+		})
+	}
+}
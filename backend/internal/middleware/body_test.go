@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestBodySizeLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const maxBytes = 16
+
+	tests := []struct {
+		name       string
+		body       string
+		chunked    bool
+		wantStatus int
+	}{
+		{"empty body", "", false, http.StatusOK},
+		{"small body", `{"a":1}`, false, http.StatusOK},
+		{"body at limit", strings.Repeat("x", maxBytes), false, http.StatusOK},
+		{"body over limit", strings.Repeat("x", maxBytes+1), false, http.StatusRequestEntityTooLarge},
+		{"chunked body at limit", strings.Repeat("x", maxBytes), true, http.StatusOK},
+		{"chunked body over limit", strings.Repeat("x", maxBytes+1), true, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received string
+			router := gin.New()
+			router.Use(BodySizeLimit(maxBytes))
+			router.POST("/api/v1/servers", func(c *gin.Context) {
+				body, _ := io.ReadAll(c.Request.Body)
+				received = string(body)
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/servers", strings.NewReader(tt.body))
+			if tt.chunked {
+				// Without a Content-Length the limit is enforced while reading
+				req.ContentLength = -1
+				req.TransferEncoding = []string{"chunked"}
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusOK && received != tt.body {
+				t.Errorf("handler read %q, want %q", received, tt.body)
+			}
+			if tt.wantStatus == http.StatusRequestEntityTooLarge {
+				if !strings.Contains(rec.Body.String(), `"code":"REQUEST_BODY_TOO_LARGE"`) {
+					t.Errorf("body = %s, want the REQUEST_BODY_TOO_LARGE code", rec.Body.String())
+				}
+				if got := rec.Header().Get("Connection"); got != "close" {
+					t.Errorf("Connection = %q, want close", got)
+				}
+			}
+		})
+	}
+}
+
+func TestEnforceContentType(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		method      string
+		contentType string
+		body        string
+		wantStatus  int
+	}{
+		{"json", http.MethodPost, "application/json", `{}`, http.StatusOK},
+		{"json with charset", http.MethodPut, "application/json; charset=utf-8", `{}`, http.StatusOK},
+		{"json in upper case", http.MethodPatch, "Application/JSON", `{}`, http.StatusOK},
+		{"second allowed type", http.MethodPost, "application/merge-patch+json", `{}`, http.StatusOK},
+		{"plain text", http.MethodPost, "text/plain", `{}`, http.StatusUnsupportedMediaType},
+		{"form", http.MethodPost, "application/x-www-form-urlencoded", `a=1`, http.StatusUnsupportedMediaType},
+		{"missing content type", http.MethodPost, "", `{}`, http.StatusUnsupportedMediaType},
+		{"malformed content type", http.MethodPost, "application/json; =", `{}`, http.StatusUnsupportedMediaType},
+		{"post without body", http.MethodPost, "", "", http.StatusOK},
+		{"get ignores content type", http.MethodGet, "text/plain", "", http.StatusOK},
+		{"delete ignores content type", http.MethodDelete, "text/plain", `{}`, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(EnforceContentType("application/json", "application/merge-patch+json"))
+			router.Handle(tt.method, "/api/v1/servers", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(tt.method, "/api/v1/servers", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusUnsupportedMediaType &&
+				!strings.Contains(rec.Body.String(), `"code":"UNSUPPORTED_CONTENT_TYPE"`) {
+				t.Errorf("body = %s, want the UNSUPPORTED_CONTENT_TYPE code", rec.Body.String())
+			}
+		})
+	}
+}
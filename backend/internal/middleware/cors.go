@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/config"
+)
+
+// CORSViolationRecorder records cross-origin requests from origins that are not allowed
+type CORSViolationRecorder interface {
+	RecordCORSViolation(c *gin.Context, origin string)
+}
+
+// CORSMiddleware sets CORS headers for allowed origins and answers preflight requests.
+// allowsOrigin decides which origins are allowed so the list can change at runtime;
+// cfg sets whether credentials are allowed and how long preflights are cached.
+// Requests from any other origin are rejected with 403 and reported to recorder, which
+// may be nil. Requests without an Origin header are not cross-origin and pass through.
+func CORSMiddleware(cfg config.CORSConfig, allowsOrigin func(origin string) bool, recorder CORSViolationRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		origin := c.Request.Header.Get("Origin")
+		c.Header("Vary", "Origin")
+
+		if origin != "" {
+			if !allowsOrigin(origin) {
+				if recorder != nil {
+					recorder.RecordCORSViolation(c, origin)
+				}
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Origin not allowed"})
+				return
+			}
+
+			c.Header("Access-Control-Allow-Origin", origin)
+			c.Header("Access-Control-Expose-Headers", CorrelationIDHeader)
+			if cfg.AllowCredentials {
+				c.Header("Access-Control-Allow-Credentials", "true")
+			}
+		}
+
+		if c.Request.Method == "OPTIONS" {
+			c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+			c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, "+CorrelationIDHeader)
+			if cfg.MaxAge > 0 {
+				c.Header("Access-Control-Max-Age", strconv.Itoa(cfg.MaxAge))
+			}
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/config"
+)
+
+// violationRecorder collects the origins of reported CORS violations
+type violationRecorder struct {
+	origins []string
+}
+
+func (r *violationRecorder) RecordCORSViolation(c *gin.Context, origin string) {
+	r.origins = append(r.origins, origin)
+}
+
+func TestCORSMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := config.CORSConfig{
+		AllowedOrigins:   []string{"https://app.example.com", "https://*.preview.example.com"},
+		AllowCredentials: true,
+		MaxAge:           600,
+	}
+	allowsOrigin := func(origin string) bool {
+		for _, allowed := range cfg.AllowedOrigins {
+			if config.MatchOrigin(allowed, origin) {
+				return true
+			}
+		}
+		return false
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		origin      string
+		wantStatus  int
+		wantOrigin  string
+		wantMaxAge  string
+		wantViolate bool
+	}{
+		{"allowed origin", http.MethodGet, "https://app.example.com", http.StatusOK, "https://app.example.com", "", false},
+		{"allowed subdomain", http.MethodGet, "https://pr-7.preview.example.com", http.StatusOK, "https://pr-7.preview.example.com", "", false},
+		{"rejected origin", http.MethodGet, "https://evil.test", http.StatusForbidden, "", "", true},
+		{"rejected bare domain", http.MethodGet, "https://preview.example.com", http.StatusForbidden, "", "", true},
+		{"no origin", http.MethodGet, "", http.StatusOK, "", "", false},
+		{"preflight", http.MethodOptions, "https://app.example.com", http.StatusNoContent, "https://app.example.com", "600", false},
+		{"rejected preflight", http.MethodOptions, "https://evil.test", http.StatusForbidden, "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &violationRecorder{}
+			router := gin.New()
+			router.Use(CORSMiddleware(cfg, allowsOrigin, recorder))
+			router.GET("/api/v1/servers", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(tt.method, "/api/v1/servers", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantOrigin)
+			}
+			wantCredentials := ""
+			if tt.wantOrigin != "" {
+				wantCredentials = "true"
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != wantCredentials {
+				t.Errorf("Access-Control-Allow-Credentials = %q, want %q", got, wantCredentials)
+			}
+			if got := rec.Header().Get("Access-Control-Max-Age"); got != tt.wantMaxAge {
+				t.Errorf("Access-Control-Max-Age = %q, want %q", got, tt.wantMaxAge)
+			}
+			if got := rec.Header().Get("Vary"); got != "Origin" {
+				t.Errorf("Vary = %q, want Origin", got)
+			}
+			if violated := len(recorder.origins) > 0; violated != tt.wantViolate {
+				t.Errorf("violation reported = %v, want %v", violated, tt.wantViolate)
+			}
+		})
+	}
+}
+
+func TestCORSMiddlewareWithoutCredentials(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(CORSMiddleware(config.CORSConfig{}, func(string) bool { return true }, nil))
+	router.GET("/api/v1/servers", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodOptions, "/api/v1/servers", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("Access-Control-Allow-Credentials = %q, want none", got)
+	}
+	if got := rec.Header().Get("Access-Control-Max-Age"); got != "" {
+		t.Errorf("Access-Control-Max-Age = %q, want none", got)
+	}
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Context keys shared by ETagMiddleware and SetETag
+const (
+	etagContextKey         = "etag"
+	cacheControlContextKey = "etag_cache_control"
+)
+
+// ETagMiddleware adds conditional GET support to routes returning a cacheable resource.
+// Handlers call SetETag before writing the body; when the client's If-None-Match matches,
+// the handler skips the body and the middleware answers 304 Not Modified.
+func ETagMiddleware(maxAge time.Duration) gin.HandlerFunc {
+	cacheControl := "private, max-age=" + strconv.Itoa(int(maxAge.Seconds()))
+
+	return func(c *gin.Context) {
+		c.Set(cacheControlContextKey, cacheControl)
+		c.Next()
+
+		value, exists := c.Get(etagContextKey)
+		if !exists || c.Writer.Written() {
+			return
+		}
+
+		if etag, _ := value.(string); etagMatches(c.GetHeader("If-None-Match"), etag) {
+			c.Status(http.StatusNotModified)
+			c.Writer.WriteHeaderNow()
+		}
+	}
+}
+
+// SetETag records the ETag of the resource being served and sets the ETag and
+// Cache-Control headers. It reports whether the client already has this version, in
+// which case the handler should return without writing a body.
+func SetETag(c *gin.Context, etag string) bool {
+	c.Set(etagContextKey, etag)
+
+	c.Header("ETag", etag)
+	if cacheControl := c.GetString(cacheControlContextKey); cacheControl != "" {
+		c.Header("Cache-Control", cacheControl)
+	}
+
+	return etagMatches(c.GetHeader("If-None-Match"), etag)
+}
+
+// ComputeETag returns a strong ETag for a resource: the hex SHA-256 of its JSON
+// encoding followed by its update time in nanoseconds
+func ComputeETag(resource interface{}, updatedAt time.Time) (string, error) {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal resource for ETag: %w", err)
+	}
+
+	hash := sha256.New()
+	hash.Write(data)
+	hash.Write([]byte(strconv.FormatInt(updatedAt.UnixNano(), 10)))
+
+	return `"` + hex.EncodeToString(hash.Sum(nil)) + `"`, nil
+}
+
+// etagMatches reports whether an If-None-Match header matches etag
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" || etag == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
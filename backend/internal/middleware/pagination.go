@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// pageContextKey is the context key holding the page served by a list handler
+const pageContextKey = "pagination_page"
+
+// Page is one page of results from a list endpoint. Total is the number of items across
+// all pages, or -1 when the endpoint does not count them.
+type Page[T any] struct {
+	Items  []T
+	Limit  int
+	Offset int
+	Total  int
+}
+
+// NewPage creates a page with an unknown total
+func NewPage[T any](items []T, limit, offset int) Page[T] {
+	return Page[T]{
+		Items:  items,
+		Limit:  limit,
+		Offset: offset,
+		Total:  -1,
+	}
+}
+
+// Meta returns the page's pagination details for the response body
+func (p Page[T]) Meta() gin.H {
+	meta := gin.H{
+		"limit":  p.Limit,
+		"offset": p.Offset,
+		"count":  len(p.Items),
+	}
+	if p.Total >= 0 {
+		meta["total"] = p.Total
+	}
+	return meta
+}
+
+// pageInfo is the type-independent part of a Page used to build Link headers
+type pageInfo struct {
+	limit  int
+	offset int
+	count  int
+	total  int
+}
+
+// SetPage records the page a list handler is about to return, so PaginationMiddleware
+// can add Link headers to the response
+func SetPage[T any](c *gin.Context, page Page[T]) {
+	c.Set(pageContextKey, pageInfo{
+		limit:  page.Limit,
+		offset: page.Offset,
+		count:  len(page.Items),
+		total:  page.Total,
+	})
+}
+
+// PaginationMiddleware adds RFC 5988 Link headers (first, prev, next and, when the total
+// is known, last) to responses from handlers that called SetPage. Links keep the request's
+// other query parameters.
+func PaginationMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer = &paginationWriter{ResponseWriter: c.Writer, c: c}
+		c.Next()
+	}
+}
+
+// paginationWriter adds the Link header just before the response headers are sent
+type paginationWriter struct {
+	gin.ResponseWriter
+	c     *gin.Context
+	added bool
+}
+
+func (w *paginationWriter) WriteHeaderNow() {
+	w.addLinks()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *paginationWriter) Write(data []byte) (int, error) {
+	w.addLinks()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *paginationWriter) WriteString(s string) (int, error) {
+	w.addLinks()
+	return w.ResponseWriter.WriteString(s)
+}
+
+// addLinks sets the Link header once, if the handler recorded a page
+func (w *paginationWriter) addLinks() {
+	if w.added || w.ResponseWriter.Written() {
+		return
+	}
+	w.added = true
+
+	value, exists := w.c.Get(pageContextKey)
+	if !exists {
+		return
+	}
+	page, ok := value.(pageInfo)
+	if !ok || page.limit <= 0 {
+		return
+	}
+
+	if links := paginationLinks(w.c, page); links != "" {
+		w.Header().Set("Link", links)
+	}
+}
+
+// paginationLinks builds the Link header value for a page
+func paginationLinks(c *gin.Context, page pageInfo) string {
+	var links []string
+	add := func(offset int, rel string) {
+		links = append(links, fmt.Sprintf("<%s>; rel=\"%s\"", pageURL(c, offset, page.limit), rel))
+	}
+
+	hasNext := page.count == page.limit
+	if page.total >= 0 {
+		hasNext = page.offset+page.limit < page.total
+	}
+	if hasNext {
+		add(page.offset+page.limit, "next")
+	}
+
+	if page.offset > 0 {
+		prev := page.offset - page.limit
+		if prev < 0 {
+			prev = 0
+		}
+		add(prev, "prev")
+	}
+
+	add(0, "first")
+
+	if page.total > 0 {
+		add((page.total-1)/page.limit*page.limit, "last")
+	}
+
+	return strings.Join(links, ", ")
+}
+
+// pageURL returns the absolute URL of the request with its offset and limit replaced
+func pageURL(c *gin.Context, offset, limit int) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+
+	query := c.Request.URL.Query()
+	query.Set("offset", strconv.Itoa(offset))
+	query.Set("limit", strconv.Itoa(limit))
+
+	u := url.URL{
+		Scheme:   scheme,
+		Host:     c.Request.Host,
+		Path:     c.Request.URL.Path,
+		RawQuery: query.Encode(),
+	}
+	return u.String()
+}
@@ -3,20 +3,27 @@ package middleware
 import (
 	"context"
 	"crypto/subtle"
-	"encoding/json"
-	"fmt"
 	"net/http"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/apikeys"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	apperrors "github.com/radhi1991/aran-mcp-sentinel/internal/errors"
 	"go.uber.org/zap"
 )
 
+// APIKeyVerifier checks a presented API key against the stored key hashes
+type APIKeyVerifier interface {
+	VerifyAPIKey(ctx context.Context, key string) (*database.APIKey, error)
+}
+
 // ProductionMiddleware provides production-ready middleware
 type ProductionMiddleware struct {
 	logger *zap.Logger
+	keys   APIKeyVerifier
 }
 
 // NewProductionMiddleware creates production middleware
@@ -26,6 +33,12 @@ func NewProductionMiddleware(logger *zap.Logger) *ProductionMiddleware {
 	}
 }
 
+// SetAPIKeyVerifier makes APIKeyAuth validate keys against the database instead of
+// the API_KEY environment variable
+func (pm *ProductionMiddleware) SetAPIKeyVerifier(keys APIKeyVerifier) {
+	pm.keys = keys
+}
+
 // APIKeyAuth provides API key authentication for production
 func (pm *ProductionMiddleware) APIKeyAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -58,7 +71,37 @@ func (pm *ProductionMiddleware) APIKeyAuth() gin.HandlerFunc {
 			return
 		}
 
-		// Validate API key (in production, check against database)
+		if pm.keys != nil {
+			key, err := pm.keys.VerifyAPIKey(c.Request.Context(), apiKey)
+			if err != nil {
+				pm.logger.Warn("Invalid API key",
+					zap.String("ip", c.ClientIP()),
+					zap.String("path", c.Request.URL.Path),
+					zap.Error(err),
+				)
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"error": "Invalid API key",
+					"code":  "INVALID_API_KEY",
+				})
+				c.Abort()
+				return
+			}
+
+			c.Set("organization_id", key.OrganizationID)
+			c.Set("api_key_id", key.ID)
+			if key.UserID != nil {
+				c.Set("user_id", *key.UserID)
+			}
+			// The key's permissions decide what it may do on role-gated routes
+			c.Set("user_role", apikeys.Role(key.Permissions))
+			c.Set("api_key_permissions", key.Permissions)
+			c.Set("api_key_used", true)
+			c.Set("authenticated", true)
+			c.Next()
+			return
+		}
+
+		// Without a verifier, fall back to the single key from the environment
 		validKey := os.Getenv("API_KEY")
 		if validKey == "" {
 			validKey = "mcp-sentinel-api-key-change-in-production"
@@ -84,6 +127,19 @@ func (pm *ProductionMiddleware) APIKeyAuth() gin.HandlerFunc {
 	}
 }
 
+// APIKeyOr authenticates requests carrying an X-API-Key header with APIKeyAuth and
+// hands all other requests to fallback, so API keys work alongside bearer tokens
+func (pm *ProductionMiddleware) APIKeyOr(fallback gin.HandlerFunc) gin.HandlerFunc {
+	apiKeyAuth := pm.APIKeyAuth()
+	return func(c *gin.Context) {
+		if c.GetHeader("X-API-Key") != "" {
+			apiKeyAuth(c)
+			return
+		}
+		fallback(c)
+	}
+}
+
 // RequestTimeout adds request timeout
 func (pm *ProductionMiddleware) RequestTimeout(timeout time.Duration) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -226,6 +282,14 @@ func (pm *ProductionMiddleware) HealthCheck() gin.HandlerFunc {
 	}
 }
 
+// typedErrorCodes are the response codes of the statuses apperrors.HTTPStatus returns
+var typedErrorCodes = map[int]string{
+	http.StatusNotFound:        "NOT_FOUND",
+	http.StatusBadRequest:      "VALIDATION_ERROR",
+	http.StatusTooManyRequests: "QUOTA_EXCEEDED",
+	http.StatusBadGateway:      "UPSTREAM_ERROR",
+}
+
 // ErrorHandler provides consistent error responses
 func (pm *ProductionMiddleware) ErrorHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -251,7 +315,12 @@ func (pm *ProductionMiddleware) ErrorHandler() gin.HandlerFunc {
 			message := "An internal error occurred"
 
 			// Customize based on error type
+			typedStatus, typed := apperrors.HTTPStatus(err.Err)
 			switch {
+			case typed:
+				statusCode = typedStatus
+				errorCode = typedErrorCodes[typedStatus]
+				message = err.Err.Error()
 			case strings.Contains(err.Error(), "timeout"):
 				statusCode = http.StatusRequestTimeout
 				errorCode = "TIMEOUT"
@@ -0,0 +1,183 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/apikeys"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/auth"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	apperrors "github.com/radhi1991/aran-mcp-sentinel/internal/errors"
+	"go.uber.org/zap"
+)
+
+// stubVerifier accepts a single key
+type stubVerifier struct {
+	key    string
+	apiKey *database.APIKey
+}
+
+func (v stubVerifier) VerifyAPIKey(ctx context.Context, key string) (*database.APIKey, error) {
+	if key != v.key {
+		return nil, apikeys.ErrInvalidKey
+	}
+	return v.apiKey, nil
+}
+
+func TestAPIKeyOr(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	orgID := uuid.New()
+	pm := NewProductionMiddleware(zap.NewNop())
+	pm.SetAPIKeyVerifier(stubVerifier{
+		key:    "valid-key",
+		apiKey: &database.APIKey{ID: uuid.New(), OrganizationID: orgID},
+	})
+
+	fallback := func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "Bearer token" {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		c.Set("organization_id", uuid.Nil)
+		c.Next()
+	}
+
+	router := gin.New()
+	router.Use(pm.APIKeyOr(fallback))
+	router.GET("/api/v1/servers", func(c *gin.Context) {
+		value, _ := c.Get("organization_id")
+		c.String(http.StatusOK, "%v", value)
+	})
+
+	tests := []struct {
+		name       string
+		headers    map[string]string
+		wantStatus int
+		wantOrg    uuid.UUID
+	}{
+		{"valid API key", map[string]string{"X-API-Key": "valid-key"}, http.StatusOK, orgID},
+		{"invalid API key", map[string]string{"X-API-Key": "other-key"}, http.StatusUnauthorized, uuid.Nil},
+		{"invalid API key with bearer token", map[string]string{"X-API-Key": "other-key", "Authorization": "Bearer token"}, http.StatusUnauthorized, uuid.Nil},
+		{"bearer token", map[string]string{"Authorization": "Bearer token"}, http.StatusOK, uuid.Nil},
+		{"no credentials", nil, http.StatusUnauthorized, uuid.Nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/servers", nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusOK && w.Body.String() != tt.wantOrg.String() {
+				t.Errorf("organization_id = %s, want %s", w.Body.String(), tt.wantOrg)
+			}
+		})
+	}
+}
+
+func TestAPIKeyAuthSetsRoleFromPermissions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		permissions  database.JSONB
+		requiredRole string
+		wantStatus   int
+	}{
+		{"default role on a user route", nil, auth.RoleUser, http.StatusOK},
+		{"default role on an admin route", nil, auth.RoleAdmin, http.StatusForbidden},
+		{"viewer key on a user route", database.JSONB{"role": "viewer"}, auth.RoleUser, http.StatusForbidden},
+		{"viewer key on a viewer route", database.JSONB{"role": "viewer"}, auth.RoleViewer, http.StatusOK},
+		{"admin key on an admin route", database.JSONB{"role": "admin"}, auth.RoleAdmin, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pm := NewProductionMiddleware(zap.NewNop())
+			pm.SetAPIKeyVerifier(stubVerifier{
+				key:    "valid-key",
+				apiKey: &database.APIKey{ID: uuid.New(), OrganizationID: uuid.New(), Permissions: tt.permissions},
+			})
+
+			var permissions interface{}
+			router := gin.New()
+			router.Use(pm.APIKeyAuth())
+			router.POST("/api/v1/servers", auth.RBACMiddleware(tt.requiredRole, zap.NewNop()), func(c *gin.Context) {
+				permissions, _ = c.Get("api_key_permissions")
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/servers", nil)
+			req.Header.Set("X-API-Key", "valid-key")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if rec.Code == http.StatusOK && fmt.Sprint(permissions) != fmt.Sprint(tt.permissions) {
+				t.Errorf("api_key_permissions = %v, want %v", permissions, tt.permissions)
+			}
+		})
+	}
+}
+
+func TestErrorHandlerStatusPerErrorType(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{"not found", &apperrors.NotFoundError{Resource: "tool", ID: "1"}, http.StatusNotFound, "NOT_FOUND"},
+		{"validation", &apperrors.ValidationError{Field: "url", Message: "must be absolute"}, http.StatusBadRequest, "VALIDATION_ERROR"},
+		{"quota", &apperrors.QuotaError{Limit: 10, Window: "hour"}, http.StatusTooManyRequests, "QUOTA_EXCEEDED"},
+		{"protocol", &apperrors.ProtocolError{Code: -32601, Message: "method not found"}, http.StatusBadGateway, "UPSTREAM_ERROR"},
+		{"connection", &apperrors.ConnectionError{URL: "http://mcp.invalid", Cause: errors.New("refused")}, http.StatusBadGateway, "UPSTREAM_ERROR"},
+		{"wrapped", fmt.Errorf("failed to call tool: %w", &apperrors.QuotaError{Limit: 1, Window: "day"}), http.StatusTooManyRequests, "QUOTA_EXCEEDED"},
+		{"untyped", errors.New("boom"), http.StatusInternalServerError, "INTERNAL_ERROR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pm := NewProductionMiddleware(zap.NewNop())
+			router := gin.New()
+			router.Use(pm.ErrorHandler())
+			router.GET("/api/v1/tools", func(c *gin.Context) {
+				c.Error(tt.err)
+			})
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/tools", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			var body struct {
+				Code string `json:"code"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("invalid JSON response %s: %v", rec.Body.String(), err)
+			}
+			if body.Code != tt.wantCode {
+				t.Errorf("code = %q, want %q", body.Code, tt.wantCode)
+			}
+		})
+	}
+}
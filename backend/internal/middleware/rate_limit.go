@@ -0,0 +1,271 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/log"
+	"go.uber.org/zap"
+)
+
+// limiterIdleTimeout is how long an unused limiter is kept before it is dropped
+const limiterIdleTimeout = 10 * time.Minute
+
+// rateWindow and burstWindow are the spans over which requestsPerMinute and burstSize
+// are counted
+const (
+	rateWindow  = time.Minute
+	burstWindow = time.Second
+)
+
+// slidingWindow counts requests in fixed windows and estimates the count over the last
+// window span by weighting the previous window by how much of it still overlaps
+type slidingWindow struct {
+	size     time.Duration
+	limit    int
+	start    time.Time // start of the current window
+	current  int
+	previous int
+}
+
+// advance moves the window forward to the one containing now
+func (w *slidingWindow) advance(now time.Time) {
+	if w.start.IsZero() {
+		w.start = now.Truncate(w.size)
+		return
+	}
+
+	elapsed := now.Sub(w.start)
+	if elapsed < w.size {
+		return
+	}
+	if elapsed < 2*w.size {
+		w.previous = w.current
+	} else {
+		w.previous = 0
+	}
+	w.current = 0
+	w.start = w.start.Add(elapsed.Truncate(w.size))
+}
+
+// count estimates the requests made in the window span ending at now
+func (w *slidingWindow) count(now time.Time) float64 {
+	overlap := 1 - float64(now.Sub(w.start))/float64(w.size)
+	return float64(w.previous)*overlap + float64(w.current)
+}
+
+// wait returns how long until one more request fits in the window, or 0 if it fits now
+func (w *slidingWindow) wait(now time.Time) time.Duration {
+	if w.count(now)+1 <= float64(w.limit) {
+		return 0
+	}
+
+	elapsed := now.Sub(w.start)
+	if w.current+1 > w.limit {
+		// The current window alone is full: wait for it to end, then for enough of it
+		// to slide out of the span
+		untilNext := w.size - elapsed
+		slide := float64(w.size) * float64(w.current-w.limit+1) / float64(w.current)
+		return untilNext + time.Duration(math.Ceil(slide))
+	}
+
+	// Enough of the previous window has to slide out of the span
+	slide := float64(w.size)*float64(w.previous-w.limit+1+w.current)/float64(w.previous) - float64(elapsed)
+	return time.Duration(math.Max(math.Ceil(slide), 0))
+}
+
+// remaining returns how many more requests fit in the window at now
+func (w *slidingWindow) remaining(now time.Time) int {
+	return int(math.Max(0, math.Floor(float64(w.limit)-w.count(now))))
+}
+
+// limiterEntry is one client's rate and burst windows together with the last time it
+// was used
+type limiterEntry struct {
+	mu       sync.Mutex
+	rate     slidingWindow
+	burst    slidingWindow
+	lastSeen atomic.Int64 // unix nanoseconds
+}
+
+// allow records a request at now if both windows have room. Otherwise it returns how
+// long the client has to wait.
+func (e *limiterEntry) allow(now time.Time) (bool, time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.rate.advance(now)
+	e.burst.advance(now)
+
+	if wait := time.Duration(math.Max(float64(e.rate.wait(now)), float64(e.burst.wait(now)))); wait > 0 {
+		return false, wait
+	}
+
+	e.rate.current++
+	e.burst.current++
+	return true, 0
+}
+
+// status returns the requests left in the rate window and when that window ends
+func (e *limiterEntry) status(now time.Time) (int, time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.rate.remaining(now), e.rate.start.Add(e.rate.size)
+}
+
+// limiterStore keeps one limiter per key. Lookups of existing keys take no lock, so
+// clients do not contend with each other; idle limiters are evicted in the background.
+type limiterStore struct {
+	limiters          sync.Map // string -> *limiterEntry
+	requestsPerMinute int
+	burstSize         int
+}
+
+// newLimiterStore creates a store that hands out limiters allowing requestsPerMinute
+// requests in any minute and burstSize in any second, and starts evicting limiters idle
+// for longer than limiterIdleTimeout. A burstSize below 1 leaves only the minute limit.
+func newLimiterStore(requestsPerMinute, burstSize int) *limiterStore {
+	if requestsPerMinute < 1 {
+		requestsPerMinute = 1
+	}
+	if burstSize < 1 || burstSize > requestsPerMinute {
+		burstSize = requestsPerMinute
+	}
+
+	s := &limiterStore{
+		requestsPerMinute: requestsPerMinute,
+		burstSize:         burstSize,
+	}
+	go s.evictIdle(limiterIdleTimeout)
+
+	return s
+}
+
+// get returns the limiter for key, creating it if needed
+func (s *limiterStore) get(key string, now time.Time) *limiterEntry {
+	value, exists := s.limiters.Load(key)
+	if !exists {
+		value, _ = s.limiters.LoadOrStore(key, &limiterEntry{
+			rate:  slidingWindow{size: rateWindow, limit: s.requestsPerMinute},
+			burst: slidingWindow{size: burstWindow, limit: s.burstSize},
+		})
+	}
+
+	entry := value.(*limiterEntry)
+	entry.lastSeen.Store(now.UnixNano())
+
+	return entry
+}
+
+// evictIdle periodically drops limiters that have not been used within idleTimeout. It
+// runs for the life of the process, like the middleware that owns the store.
+func (s *limiterStore) evictIdle(idleTimeout time.Duration) {
+	ticker := time.NewTicker(idleTimeout)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		s.evictIdleAt(now, idleTimeout)
+	}
+}
+
+// evictIdleAt drops limiters last used more than idleTimeout before now
+func (s *limiterStore) evictIdleAt(now time.Time, idleTimeout time.Duration) {
+	cutoff := now.Add(-idleTimeout).UnixNano()
+	s.limiters.Range(func(key, value interface{}) bool {
+		if value.(*limiterEntry).lastSeen.Load() < cutoff {
+			s.limiters.Delete(key)
+		}
+		return true
+	})
+}
+
+// RateLimiter limits requests per client IP to requestsPerMinute in any sliding minute,
+// of which at most burstSize may arrive within one second
+func RateLimiter(requestsPerMinute int, burstSize int) gin.HandlerFunc {
+	store := newLimiterStore(requestsPerMinute, burstSize)
+
+	return rateLimitHandler(store, func(c *gin.Context) string {
+		return c.ClientIP()
+	})
+}
+
+// APIKeyRateLimiter limits requests per authenticated client in the same way as
+// RateLimiter. It keys on the verified API key, or the user for other credentials, so
+// it belongs after authentication; unauthenticated requests are left to RateLimiter.
+func APIKeyRateLimiter(requestsPerMinute int, burstSize int) gin.HandlerFunc {
+	store := newLimiterStore(requestsPerMinute, burstSize)
+
+	return rateLimitHandler(store, authenticatedClient)
+}
+
+// rateLimitHandler applies the limiter selected by keyFunc and sets the rate limit headers
+func rateLimitHandler(store *limiterStore, keyFunc func(*gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if isRateLimitExempt(c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		key := keyFunc(c)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		now := time.Now()
+		limiter := store.get(key, now)
+		allowed, wait := limiter.allow(now)
+		remaining, reset := limiter.status(now)
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(store.requestsPerMinute))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+
+		if !allowed {
+			retryAfter := int(math.Ceil(wait.Seconds()))
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+
+			log.FromCtx(c.Request.Context()).Warn("Rate limit exceeded",
+				zap.String("ip", c.ClientIP()),
+				zap.String("path", c.Request.URL.Path),
+			)
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":       "Rate limit exceeded",
+				"retry_after": retryAfter,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// authenticatedClient returns the verified API key ID or, for other credentials, the
+// user ID set by the authentication middleware
+func authenticatedClient(c *gin.Context) string {
+	if keyID, exists := c.Get("api_key_id"); exists {
+		return fmt.Sprintf("key:%v", keyID)
+	}
+	if userID, exists := c.Get("user_id"); exists {
+		return fmt.Sprintf("user:%v", userID)
+	}
+
+	return ""
+}
+
+// isRateLimitExempt reports whether a path bypasses rate limiting
+func isRateLimitExempt(path string) bool {
+	return path == "/health" || path == "/healthz" || strings.HasPrefix(path, "/healthz/")
+}
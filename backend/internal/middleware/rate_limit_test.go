@@ -0,0 +1,209 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSlidingWindowWait(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		previous int
+		current  int
+		at       time.Duration // since the start of the current window
+		want     time.Duration
+	}{
+		{"room left", 0, 9, 0, 0},
+		{"current window full", 0, 10, 30 * time.Second, 30*time.Second + 6*time.Second},
+		{"previous window still counts", 10, 0, 0, 6 * time.Second},
+		{"previous window slid out enough", 10, 0, 6 * time.Second, 0},
+		{"both windows", 8, 5, 15 * time.Second, 15 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := slidingWindow{size: time.Minute, limit: 10, start: start, previous: tt.previous, current: tt.current}
+			if got := w.wait(start.Add(tt.at)); got != tt.want {
+				t.Errorf("wait() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlidingWindowAdvance(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		at           time.Duration
+		wantStart    time.Duration
+		wantPrevious int
+	}{
+		{"same window", 59 * time.Second, 0, 3},
+		{"next window", 90 * time.Second, time.Minute, 7},
+		{"idle for windows", 5 * time.Minute, 5 * time.Minute, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := slidingWindow{size: time.Minute, limit: 10, start: start, previous: 3, current: 7}
+			w.advance(start.Add(tt.at))
+			if !w.start.Equal(start.Add(tt.wantStart)) {
+				t.Errorf("start = %v, want %v", w.start, start.Add(tt.wantStart))
+			}
+			if w.previous != tt.wantPrevious {
+				t.Errorf("previous = %d, want %d", w.previous, tt.wantPrevious)
+			}
+		})
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name              string
+		requestsPerMinute int
+		burstSize         int
+		wantAllowed       int
+	}{
+		{"minute limit", 5, 0, 5},
+		{"burst limit", 100, 3, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(RateLimiter(tt.requestsPerMinute, tt.burstSize))
+			router.GET("/api/v1/servers", func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			allowed := 0
+			var last *httptest.ResponseRecorder
+			for i := 0; i < tt.wantAllowed+2; i++ {
+				req := httptest.NewRequest(http.MethodGet, "/api/v1/servers", nil)
+				last = httptest.NewRecorder()
+				router.ServeHTTP(last, req)
+				if last.Code == http.StatusOK {
+					allowed++
+				}
+			}
+
+			if allowed != tt.wantAllowed {
+				t.Errorf("allowed %d requests, want %d", allowed, tt.wantAllowed)
+			}
+			if last.Code != http.StatusTooManyRequests {
+				t.Fatalf("status = %d, want %d", last.Code, http.StatusTooManyRequests)
+			}
+			if last.Header().Get("Retry-After") == "" {
+				t.Error("Retry-After header missing")
+			}
+		})
+	}
+}
+
+// TestAPIKeyRateLimiterKeysOnVerifiedClient checks that only authenticated requests get
+// a limiter, so unverified keys cannot grow the store
+func TestAPIKeyRateLimiterKeysOnVerifiedClient(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]interface{}
+		wantKey string
+	}{
+		{"api key", map[string]interface{}{"api_key_id": "k1", "user_id": "u1"}, "key:k1"},
+		{"user", map[string]interface{}{"user_id": "u1"}, "user:u1"},
+		{"unauthenticated", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/servers", nil)
+			c.Request.Header.Set("X-API-Key", "unverified")
+			for k, v := range tt.values {
+				c.Set(k, v)
+			}
+
+			if got := authenticatedClient(c); got != tt.wantKey {
+				t.Errorf("authenticatedClient() = %q, want %q", got, tt.wantKey)
+			}
+		})
+	}
+}
+
+// BenchmarkRateLimiterConcurrentClients sends requests from 1000 concurrent clients, each
+// with its own IP or API key and so its own limiter, through a router using the limiters.
+// The rate is high enough that no request is limited, so the benchmark measures the
+// limiter lookup.
+func BenchmarkRateLimiterConcurrentClients(b *testing.B) {
+	gin.SetMode(gin.TestMode)
+
+	const clients = 1000
+
+	limiters := []struct {
+		name    string
+		handler gin.HandlerFunc
+		header  string
+	}{
+		{"by ip", RateLimiter(1e9, 0), ""},
+		{"by api key", APIKeyRateLimiter(1e9, 0), "X-API-Key"},
+	}
+
+	for _, l := range limiters {
+		b.Run(l.name, func(b *testing.B) {
+			router := gin.New()
+			// Stand in for APIKeyAuth, which sets the verified key ID
+			router.Use(func(c *gin.Context) {
+				if key := c.GetHeader("X-API-Key"); key != "" {
+					c.Set("api_key_id", key)
+				}
+			})
+			router.Use(l.handler)
+			router.GET("/api/v1/servers", func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			requests := make([]*http.Request, clients)
+			for i := range requests {
+				req := httptest.NewRequest(http.MethodGet, "/api/v1/servers", nil)
+				req.RemoteAddr = fmt.Sprintf("10.0.%d.%d:1234", i/256, i%256)
+				if l.header != "" {
+					req.Header.Set(l.header, fmt.Sprintf("key-%d", i))
+				}
+				requests[i] = req
+			}
+
+			// At least one goroutine per client
+			b.SetParallelism((clients + runtime.GOMAXPROCS(0) - 1) / runtime.GOMAXPROCS(0))
+
+			var next atomic.Int32
+			var limited atomic.Int64
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				req := requests[int(next.Add(1)-1)%clients]
+				for pb.Next() {
+					rec := httptest.NewRecorder()
+					router.ServeHTTP(rec, req)
+					if rec.Code != http.StatusOK {
+						limited.Add(1)
+					}
+				}
+			})
+			b.StopTimer()
+
+			if n := limited.Load(); n > 0 {
+				b.Errorf("%d requests were limited, want none", n)
+			}
+		})
+	}
+}
@@ -2,13 +2,12 @@ package middleware
 
 import (
 	"net/http"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/log"
 	"go.uber.org/zap"
-	"golang.org/x/time/rate"
 )
 
 // SecurityHeaders adds security headers to all responses
@@ -38,88 +37,40 @@ func SecurityHeaders() gin.HandlerFunc {
 	}
 }
 
-// RateLimiter implements rate limiting per IP
-func RateLimiter(requestsPerMinute int, logger *zap.Logger) gin.HandlerFunc {
-	// Create a map to store rate limiters for each IP
-	limiters := make(map[string]*rate.Limiter)
-	
-	return func(c *gin.Context) {
-		ip := c.ClientIP()
-		
-		// Get or create limiter for this IP
-		limiter, exists := limiters[ip]
-		if !exists {
-			limiter = rate.NewLimiter(rate.Every(time.Minute/time.Duration(requestsPerMinute)), requestsPerMinute)
-			limiters[ip] = limiter
-		}
-		
-		// Check if request is allowed
-		if !limiter.Allow() {
-			logger.Warn("Rate limit exceeded", 
-				zap.String("ip", ip),
-				zap.String("path", c.Request.URL.Path),
-			)
-			
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error": "Rate limit exceeded. Please try again later.",
-				"retry_after": "60s",
-			})
-			c.Abort()
-			return
-		}
-		
-		c.Next()
-	}
-}
-
-// RequestValidator validates common request parameters
-func RequestValidator() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// Validate Content-Type for POST/PUT requests
-		if c.Request.Method == "POST" || c.Request.Method == "PUT" {
-			contentType := c.GetHeader("Content-Type")
-			if !strings.Contains(contentType, "application/json") && 
-			   !strings.Contains(contentType, "multipart/form-data") {
-				c.JSON(http.StatusBadRequest, gin.H{
-					"error": "Invalid Content-Type. Expected application/json",
-				})
-				c.Abort()
-				return
-			}
-		}
-		
-		// Validate request size (max 10MB)
-		if c.Request.ContentLength > 10*1024*1024 {
-			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
-				"error": "Request body too large. Maximum size is 10MB",
-			})
-			c.Abort()
-			return
-		}
-		
-		c.Next()
-	}
-}
+// CorrelationIDHeader carries a request's correlation ID. A UUID sent by the client is
+// kept so its requests can be traced across services; otherwise one is generated.
+const CorrelationIDHeader = "X-Correlation-ID"
 
-// RequestLogger logs all incoming requests
+// RequestLogger gives every request a correlation ID, stored as correlation_id in the
+// gin context and returned in the X-Correlation-ID header, and a logger carrying it in
+// the request context for log.FromCtx. It logs each request and its response.
 func RequestLogger(logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
-		
-		// Process request
-		c.Next()
-		
-		// Log request details
-		duration := time.Since(start)
-		
-		logger.Info("HTTP Request",
+
+		correlationID := c.GetHeader(CorrelationIDHeader)
+		if _, err := uuid.Parse(correlationID); err != nil {
+			correlationID = uuid.New().String()
+		}
+		c.Set("correlation_id", correlationID)
+		c.Header(CorrelationIDHeader, correlationID)
+
+		requestLogger := logger.With(zap.String("correlation_id", correlationID))
+		c.Request = c.Request.WithContext(log.WithLogger(c.Request.Context(), requestLogger))
+
+		requestLogger.Info("HTTP request",
 			zap.String("method", c.Request.Method),
 			zap.String("path", c.Request.URL.Path),
 			zap.String("query", c.Request.URL.RawQuery),
 			zap.String("ip", c.ClientIP()),
 			zap.String("user_agent", c.Request.UserAgent()),
+		)
+
+		c.Next()
+
+		requestLogger.Info("HTTP response",
 			zap.Int("status", c.Writer.Status()),
-			zap.Duration("duration", duration),
+			zap.Int64("latency_ms", time.Since(start).Milliseconds()),
 			zap.Int("response_size", c.Writer.Size()),
 		)
 	}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrShutdownTimeout is returned when in-flight work outlasts the graceful shutdown timeout
+var ErrShutdownTimeout = errors.New("in-flight work did not finish before the shutdown timeout")
+
+// ShutdownCoordinator lets critical sections such as tool executions and health check
+// probes finish during shutdown. Request contexts derive from its root context, which is
+// cancelled if the work outlasts the shutdown timeout.
+type ShutdownCoordinator struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu       sync.RWMutex
+	draining bool
+	wg       sync.WaitGroup
+}
+
+// NewShutdownCoordinator creates a shutdown coordinator
+func NewShutdownCoordinator() *ShutdownCoordinator {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &ShutdownCoordinator{
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Context returns the root context, cancelled once the shutdown timeout has passed
+func (s *ShutdownCoordinator) Context() context.Context {
+	return s.ctx
+}
+
+// Begin starts a critical section, which must be ended with Done. It returns false
+// once shutdown has begun, in which case the work should not be started.
+func (s *ShutdownCoordinator) Begin() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.draining {
+		return false
+	}
+	s.wg.Add(1)
+	return true
+}
+
+// Done ends a critical section started with Begin
+func (s *ShutdownCoordinator) Done() {
+	s.wg.Done()
+}
+
+// Shutdown stops new critical sections from starting and waits up to timeout for the
+// running ones. If they do not finish in time, the root context is cancelled so they
+// abort, and ErrShutdownTimeout is returned.
+func (s *ShutdownCoordinator) Shutdown(timeout time.Duration) error {
+	s.mu.Lock()
+	s.draining = true
+	s.mu.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(finished)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-finished:
+		s.cancel()
+		return nil
+	case <-timer.C:
+		s.cancel()
+		return ErrShutdownTimeout
+	}
+}
+
+// Middleware rejects requests once shutdown has begun and cancels the context of every
+// request when the root context is cancelled
+func (s *ShutdownCoordinator) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		s.mu.RLock()
+		draining := s.draining
+		s.mu.RUnlock()
+
+		if draining {
+			c.Header("Connection", "close")
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "Server is shutting down"})
+			return
+		}
+
+		ctx, cancel := context.WithCancel(c.Request.Context())
+		stop := context.AfterFunc(s.ctx, cancel)
+		defer stop()
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
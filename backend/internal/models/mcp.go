@@ -21,6 +21,7 @@ type MCPServer struct {
 	IsActive         bool                   `json:"is_active" db:"is_active"`
 	LastChecked      time.Time              `json:"last_checked" db:"last_checked"`
 	ResponseTime     int64                  `json:"response_time" db:"response_time"` // in milliseconds
+	TCPLatencyMs     int64                  `json:"tcp_latency_ms" db:"tcp_latency_ms"`
 	UptimePercentage float64                `json:"uptime_percentage" db:"uptime_percentage"`
 	LastCheckedAt    time.Time              `json:"last_checked_at" db:"last_checked_at"`
 	CreatedAt        time.Time              `json:"created_at" db:"created_at"`
@@ -0,0 +1,106 @@
+package monitoring
+
+import (
+	"sync"
+)
+
+// MaxAlertBuffer is how many alerts may queue for one stream subscriber before it is
+// dropped as too slow, and for the hub before new alerts are discarded
+var MaxAlertBuffer = 64
+
+// AlertHub fans alerts out to live subscribers. Publishing never blocks: a subscriber
+// that falls MaxAlertBuffer alerts behind is disconnected instead of stalling the monitor.
+type AlertHub struct {
+	broadcast chan *Alert
+
+	mu          sync.Mutex
+	subscribers map[*AlertSubscription]struct{}
+}
+
+// AlertSubscription receives published alerts on Alerts, which is closed when the
+// subscription ends, either through Unsubscribe or because the subscriber fell behind
+type AlertSubscription struct {
+	Alerts <-chan *Alert
+	alerts chan *Alert
+}
+
+var (
+	alertHub     *AlertHub
+	alertHubOnce sync.Once
+)
+
+// DefaultAlertHub returns the process-wide alert hub, starting it on first use
+func DefaultAlertHub() *AlertHub {
+	alertHubOnce.Do(func() {
+		alertHub = NewAlertHub()
+		go alertHub.run()
+	})
+	return alertHub
+}
+
+// NewAlertHub creates an alert hub. Its broadcast loop must be started with run.
+func NewAlertHub() *AlertHub {
+	return &AlertHub{
+		broadcast:   make(chan *Alert, MaxAlertBuffer),
+		subscribers: make(map[*AlertSubscription]struct{}),
+	}
+}
+
+// Publish queues an alert for every subscriber. It reports false if the hub's buffer is
+// full and the alert was discarded.
+func (h *AlertHub) Publish(alert *Alert) bool {
+	select {
+	case h.broadcast <- alert:
+		return true
+	default:
+		return false
+	}
+}
+
+// Subscribe registers a new subscriber
+func (h *AlertHub) Subscribe() *AlertSubscription {
+	alerts := make(chan *Alert, MaxAlertBuffer)
+	subscription := &AlertSubscription{Alerts: alerts, alerts: alerts}
+
+	h.mu.Lock()
+	h.subscribers[subscription] = struct{}{}
+	h.mu.Unlock()
+
+	return subscription
+}
+
+// Unsubscribe removes a subscriber and closes its channel. It is safe to call after the
+// hub has already dropped the subscriber.
+func (h *AlertHub) Unsubscribe(subscription *AlertSubscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, exists := h.subscribers[subscription]; exists {
+		delete(h.subscribers, subscription)
+		close(subscription.alerts)
+	}
+}
+
+// Subscribers returns the number of live subscribers
+func (h *AlertHub) Subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.subscribers)
+}
+
+// run delivers each published alert to every subscriber, dropping those whose buffer is full
+func (h *AlertHub) run() {
+	for alert := range h.broadcast {
+		h.mu.Lock()
+		for subscription := range h.subscribers {
+			select {
+			case subscription.alerts <- alert:
+			default:
+				delete(h.subscribers, subscription)
+				close(subscription.alerts)
+			}
+		}
+		h.mu.Unlock()
+	}
+}
@@ -0,0 +1,128 @@
+package monitoring
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+)
+
+// Alert stream keepalive settings
+const (
+	alertStreamPingInterval = 30 * time.Second
+	alertStreamWriteTimeout = 10 * time.Second
+)
+
+// StreamAlerts upgrades the request to a WebSocket and pushes the organization's alerts
+// as JSON as soon as the monitor raises them. The connection is pinged to keep it alive
+// and closed if the client stops reading.
+func (h *Handler) StreamAlerts(c *gin.Context) {
+	orgID, exists := c.Get("organization_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	orgUUID, ok := orgID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID type"})
+		return
+	}
+
+	server := websocket.Server{
+		Handler: func(ws *websocket.Conn) {
+			h.streamAlerts(ws, orgUUID)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// streamAlerts writes the organization's alerts to the connection until the client
+// disconnects or falls behind
+func (h *Handler) streamAlerts(ws *websocket.Conn, orgID uuid.UUID) {
+	defer ws.Close()
+
+	subscription := h.alerts.Subscribe()
+	defer h.alerts.Unsubscribe(subscription)
+
+	h.logger.Info("Alert stream opened", zap.String("organization_id", orgID.String()))
+	defer h.logger.Info("Alert stream closed", zap.String("organization_id", orgID.String()))
+
+	// Reading answers the client's pings and notices when it disconnects
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		var discard []byte
+		for {
+			if err := websocket.Message.Receive(ws, &discard); err != nil {
+				return
+			}
+		}
+	}()
+
+	ctx := ws.Request().Context()
+	owned := make(map[uuid.UUID]bool)
+
+	ping := time.NewTicker(alertStreamPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case <-ping.C:
+			if err := h.writeAlertFrame(ws, websocket.PingFrame, nil); err != nil {
+				return
+			}
+		case alert, ok := <-subscription.Alerts:
+			if !ok {
+				h.logger.Warn("Dropped slow alert stream client", zap.String("organization_id", orgID.String()))
+				return
+			}
+			if !h.alertBelongsTo(ctx, alert, orgID, owned) {
+				continue
+			}
+			if err := ws.SetWriteDeadline(time.Now().Add(alertStreamWriteTimeout)); err != nil {
+				return
+			}
+			if err := websocket.JSON.Send(ws, alert); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeAlertFrame writes a control frame with the write timeout
+func (h *Handler) writeAlertFrame(ws *websocket.Conn, frameType byte, payload []byte) error {
+	if err := ws.SetWriteDeadline(time.Now().Add(alertStreamWriteTimeout)); err != nil {
+		return err
+	}
+
+	previous := ws.PayloadType
+	ws.PayloadType = frameType
+	defer func() { ws.PayloadType = previous }()
+
+	_, err := ws.Write(payload)
+	return err
+}
+
+// alertBelongsTo reports whether an alert's server is in the organization, caching the
+// answer for each server for the life of the stream
+func (h *Handler) alertBelongsTo(ctx context.Context, alert *Alert, orgID uuid.UUID, owned map[uuid.UUID]bool) bool {
+	belongs, cached := owned[alert.ServerID]
+	if !cached {
+		_, err := h.repo.GetMCPServerByID(ctx, orgID, alert.ServerID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return false
+		}
+		belongs = err == nil
+		owned[alert.ServerID] = belongs
+	}
+	return belongs
+}
@@ -0,0 +1,55 @@
+package monitoring
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// backoffJitter is the largest random fraction added to a backoff delay, so servers that
+// went down together are not all rechecked at the same moment
+const backoffJitter = 0.2
+
+// BackoffPolicy spaces out health checks of a server that keeps failing them. The zero
+// value disables backoff.
+type BackoffPolicy struct {
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	Multiplier      float64
+}
+
+// DefaultBackoffPolicy backs off from the check interval, doubling up to ten minutes
+func DefaultBackoffPolicy(interval time.Duration) BackoffPolicy {
+	maxInterval := 10 * time.Minute
+	if interval > maxInterval {
+		maxInterval = interval
+	}
+
+	return BackoffPolicy{
+		InitialInterval: interval,
+		MaxInterval:     maxInterval,
+		Multiplier:      2,
+	}
+}
+
+// Delay returns how long to wait before checking a server again after the given number of
+// consecutive failures: InitialInterval * Multiplier^failures plus jitter, capped at
+// MaxInterval
+func (p BackoffPolicy) Delay(failures int) time.Duration {
+	if failures <= 0 || p.InitialInterval <= 0 {
+		return 0
+	}
+
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(p.InitialInterval) * math.Pow(multiplier, float64(failures))
+	delay += delay * backoffJitter * rand.Float64()
+
+	if p.MaxInterval > 0 && delay > float64(p.MaxInterval) {
+		return p.MaxInterval
+	}
+	return time.Duration(delay)
+}
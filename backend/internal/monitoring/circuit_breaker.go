@@ -0,0 +1,122 @@
+package monitoring
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a health check circuit breaker
+type CircuitState string
+
+// Circuit breaker states
+const (
+	CircuitClosed   CircuitState = "closed"    // checks run normally
+	CircuitOpen     CircuitState = "open"      // checks are skipped
+	CircuitHalfOpen CircuitState = "half_open" // a single probe decides whether to close
+)
+
+// CircuitBreakerConfig configures when health checks of a failing server are stopped.
+// A FailureThreshold of zero disables the breaker.
+type CircuitBreakerConfig struct {
+	FailureThreshold int           // consecutive failures that open the breaker
+	OpenDuration     time.Duration // how long checks are skipped once open
+}
+
+// DefaultCircuitBreakerConfig opens after five consecutive failures for five minutes
+func DefaultCircuitBreakerConfig() CircuitBreakerConfig {
+	return CircuitBreakerConfig{
+		FailureThreshold: 5,
+		OpenDuration:     5 * time.Minute,
+	}
+}
+
+// CircuitBreaker stops health checks of a server that keeps failing them. After
+// FailureThreshold consecutive failures it opens and checks are skipped for
+// OpenDuration; then it is half-open and the next check is a probe that closes it on
+// success or reopens it on failure.
+type CircuitBreaker struct {
+	config CircuitBreakerConfig
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+}
+
+// NewCircuitBreaker creates a closed circuit breaker
+func NewCircuitBreaker(config CircuitBreakerConfig) *CircuitBreaker {
+	return &CircuitBreaker{
+		config: config,
+		state:  CircuitClosed,
+	}
+}
+
+// Allow reports whether a health check should run at now, moving an open breaker to
+// half-open once OpenDuration has passed. A server's checks run one at a time, so a
+// half-open breaker admits a single probe before its result is recorded.
+func (b *CircuitBreaker) Allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen {
+		if now.Sub(b.openedAt) < b.config.OpenDuration {
+			return false
+		}
+		b.state = CircuitHalfOpen
+	}
+	return true
+}
+
+// RecordSuccess closes the breaker
+func (b *CircuitBreaker) RecordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = CircuitClosed
+	b.failures = 0
+}
+
+// RecordFailure counts a failed check at now and reports whether it opened the breaker
+func (b *CircuitBreaker) RecordFailure(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.config.FailureThreshold <= 0 || b.state == CircuitOpen {
+		return false
+	}
+	if b.state == CircuitHalfOpen || b.failures >= b.config.FailureThreshold {
+		b.state = CircuitOpen
+		b.openedAt = now
+		return true
+	}
+	return false
+}
+
+// State returns the breaker's current state
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// MarshalJSON reports the breaker's state in monitoring statuses
+func (b *CircuitBreaker) MarshalJSON() ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := struct {
+		State               CircuitState `json:"state"`
+		ConsecutiveFailures int          `json:"consecutive_failures"`
+		OpenedAt            *time.Time   `json:"opened_at,omitempty"`
+	}{
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+	}
+	if b.state != CircuitClosed {
+		status.OpenedAt = &b.openedAt
+	}
+	return json.Marshal(status)
+}
@@ -1,12 +1,17 @@
 package monitoring
 
 import (
+	"database/sql"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/auth"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/registry"
 	"go.uber.org/zap"
 )
 
@@ -53,8 +58,14 @@ func (h *ComprehensiveHealthHandler) GetComprehensiveHealth(c *gin.Context) {
 		return
 	}
 
+	orgUUID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
 	// Get server details
-	server, err := h.healthChecker.repo.GetMCPServer(c.Request.Context(), id.String())
+	server, err := h.healthChecker.repo.GetMCPServer(c.Request.Context(), orgUUID, id.String())
 	if err != nil {
 		h.logger.Error("Failed to get server", zap.String("server_id", serverID), zap.Error(err))
 		c.JSON(http.StatusNotFound, gin.H{"error": "Server not found"})
@@ -101,8 +112,14 @@ func (h *ComprehensiveHealthHandler) PerformComprehensiveCheck(c *gin.Context) {
 		return
 	}
 
+	orgUUID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
 	// Get server details
-	server, err := h.healthChecker.repo.GetMCPServer(c.Request.Context(), id.String())
+	server, err := h.healthChecker.repo.GetMCPServer(c.Request.Context(), orgUUID, id.String())
 	if err != nil {
 		h.logger.Error("Failed to get server", zap.String("server_id", serverID), zap.Error(err))
 		c.JSON(http.StatusNotFound, gin.H{"error": "Server not found"})
@@ -148,8 +165,14 @@ func (h *ComprehensiveHealthHandler) GetHealthMetrics(c *gin.Context) {
 		return
 	}
 
+	orgUUID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
 	// Get server details
-	server, err := h.healthChecker.repo.GetMCPServer(c.Request.Context(), id.String())
+	server, err := h.healthChecker.repo.GetMCPServer(c.Request.Context(), orgUUID, id.String())
 	if err != nil {
 		h.logger.Error("Failed to get server", zap.String("server_id", serverID), zap.Error(err))
 		c.JSON(http.StatusNotFound, gin.H{"error": "Server not found"})
@@ -236,7 +259,60 @@ func (h *ComprehensiveHealthHandler) GetHealthAlerts(c *gin.Context) {
 func (h *ComprehensiveHealthHandler) ResolveHealthAlert(c *gin.Context) {
 	alertID := c.Param("alert_id")
 
-	// In production, you'd update the alert in the database
+	// Parse alert ID
+	id, err := uuid.Parse(alertID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid alert ID"})
+		return
+	}
+
+	repo := h.healthChecker.repo
+
+	orgUUID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	userUUID, ok := auth.AuthenticatedUserID(c, repo)
+	if !ok {
+		return
+	}
+
+	if _, err := repo.GetAlertByID(c.Request.Context(), orgUUID, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Alert not found"})
+			return
+		}
+		h.logger.Error("Failed to get alert", zap.String("alert_id", alertID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get alert"})
+		return
+	}
+
+	if err := repo.ResolveAlert(c.Request.Context(), orgUUID, alertID, userUUID.String()); err != nil {
+		h.logger.Error("Failed to resolve alert", zap.String("alert_id", alertID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve alert"})
+		return
+	}
+
+	// Create audit log
+	ipAddress := c.ClientIP()
+	userAgent := c.GetHeader("User-Agent")
+	auditLog := &database.AuditLog{
+		OrganizationID: orgUUID,
+		UserID:         &userUUID,
+		Action:         "alert.resolved",
+		ResourceType:   "alert",
+		ResourceID:     &id,
+		Details:        database.JSONB{},
+		IPAddress:      &ipAddress,
+		UserAgent:      &userAgent,
+	}
+	if err := repo.CreateAuditLog(c.Request.Context(), auditLog); err != nil {
+		h.logger.Error("Failed to create audit log", zap.Error(err))
+		// Don't fail the request for this
+	}
+
 	h.logger.Info("Health alert resolved", zap.String("alert_id", alertID))
 
 	c.JSON(http.StatusOK, gin.H{
@@ -275,9 +351,58 @@ func (h *ComprehensiveHealthHandler) GetHealthDashboard(c *gin.Context) {
 		},
 	}
 
+	if orgID, ok := dashboardOrganizationID(c); ok {
+		// Show each server's status, telling regressions apart from servers that never worked
+		servers, err := h.healthChecker.repo.ListMCPServers(c.Request.Context(), orgID, dashboardServerLimit, 0)
+		if err != nil {
+			h.logger.Error("Failed to load servers", zap.Error(err))
+		}
+
+		serverHealth := make([]gin.H, 0, len(servers))
+		for _, server := range servers {
+			displayStatus := ServerDisplayStatus(server.Status, server.LastSuccessfulInitAt)
+			serverHealth = append(serverHealth, gin.H{
+				"server_id":               server.ID,
+				"name":                    server.Name,
+				"status":                  server.Status,
+				"display_status":          displayStatus,
+				"color":                   DisplayStatusColor(displayStatus),
+				"last_checked_at":         server.LastCheckedAt,
+				"last_successful_init_at": server.LastSuccessfulInitAt,
+			})
+		}
+		dashboard["servers"] = serverHealth
+
+		// Roll up the health of the organization's server groups
+		groups, err := registry.LoadServerGroups(c.Request.Context(), h.healthChecker.repo, orgID)
+		if err != nil {
+			h.logger.Error("Failed to load server groups", zap.Error(err))
+		}
+
+		rollups := make([]*registry.GroupHealthRollup, 0, len(groups))
+		for _, group := range groups {
+			rollup, err := group.ComputeHealthRollup(c.Request.Context())
+			if err != nil {
+				h.logger.Error("Failed to compute group health", zap.String("group_id", group.ID.String()), zap.Error(err))
+				continue
+			}
+			rollups = append(rollups, rollup)
+		}
+		dashboard["group_health"] = rollups
+	}
+
 	c.JSON(http.StatusOK, dashboard)
 }
 
+// dashboardServerLimit caps the number of servers listed on the health dashboard
+const dashboardServerLimit = 500
+
+// dashboardOrganizationID returns the caller's organization. An organization_id query
+// parameter naming any other organization is rejected by auth.OrgContextMiddleware.
+func dashboardOrganizationID(c *gin.Context) (uuid.UUID, bool) {
+	return auth.AuthorizedOrganizationID(c)
+}
+
 // GetHealthTrends returns health trends for a server
 func (h *ComprehensiveHealthHandler) GetHealthTrends(c *gin.Context) {
 	serverID := c.Param("server_id")
@@ -0,0 +1,153 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/metrics"
+	"go.uber.org/zap"
+)
+
+// CORS violation alerting thresholds
+const (
+	corsViolationWindow    = time.Minute
+	corsViolationThreshold = 10 // violations per window before an origin is alerted on
+
+	// maxCORSViolationOrigins bounds the origins counted per window; further origins are
+	// counted under "other" so a client cannot grow the counts without limit
+	maxCORSViolationOrigins = 500
+)
+
+// corsOriginCount counts violations from one origin within the current window, keeping
+// the first request as a sample for the audit log
+type corsOriginCount struct {
+	count     int
+	method    string
+	path      string
+	ipAddress string
+	userAgent string
+}
+
+// CORSViolationLogger records blocked cross-origin requests in the audit log and metrics,
+// and raises a warning alert when an origin keeps probing, which may indicate an attempt
+// to enumerate the allowed origins. Violations are counted in memory and written once
+// per origin and window by Start, so request volume does not turn into database writes.
+type CORSViolationLogger struct {
+	repo    *database.Repository
+	metrics *metrics.Registry
+	logger  *zap.Logger
+
+	mu     sync.Mutex
+	counts map[string]*corsOriginCount
+}
+
+// NewCORSViolationLogger creates a CORS violation logger. metrics may be nil.
+func NewCORSViolationLogger(repo *database.Repository, metricsRegistry *metrics.Registry, logger *zap.Logger) *CORSViolationLogger {
+	return &CORSViolationLogger{
+		repo:    repo,
+		metrics: metricsRegistry,
+		logger:  logger,
+		counts:  make(map[string]*corsOriginCount),
+	}
+}
+
+// RecordCORSViolation counts a request from an origin that is not allowed
+func (l *CORSViolationLogger) RecordCORSViolation(c *gin.Context, origin string) {
+	if l.metrics != nil {
+		l.metrics.IncCORSViolation(origin)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, exists := l.counts[origin]; !exists && len(l.counts) >= maxCORSViolationOrigins {
+		origin = "other"
+	}
+
+	entry, exists := l.counts[origin]
+	if !exists {
+		entry = &corsOriginCount{
+			method:    c.Request.Method,
+			path:      c.Request.URL.Path,
+			ipAddress: c.ClientIP(),
+			userAgent: c.GetHeader("User-Agent"),
+		}
+		l.counts[origin] = entry
+	}
+	entry.count++
+}
+
+// Start writes the counted violations once a minute until the context is cancelled
+func (l *CORSViolationLogger) Start(ctx context.Context) {
+	ticker := time.NewTicker(corsViolationWindow)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			l.Flush(ctx)
+		}
+	}
+}
+
+// Flush writes one audit log entry per origin seen since the last flush, and an alert
+// for every origin with more than corsViolationThreshold violations
+func (l *CORSViolationLogger) Flush(ctx context.Context) {
+	for origin, entry := range l.drain() {
+		ipAddress := entry.ipAddress
+		userAgent := entry.userAgent
+		auditLog := &database.AuditLog{
+			Action:       "cors_violation",
+			ResourceType: "http_request",
+			Details: database.JSONB{
+				"origin":         origin,
+				"method":         entry.method,
+				"path":           entry.path,
+				"count":          entry.count,
+				"window_seconds": int(corsViolationWindow.Seconds()),
+			},
+			IPAddress: &ipAddress,
+			UserAgent: &userAgent,
+		}
+		if err := l.repo.CreateSystemAuditLog(ctx, auditLog); err != nil {
+			l.logger.Error("Failed to record CORS violations", zap.String("origin", origin), zap.Error(err))
+		}
+
+		if entry.count > corsViolationThreshold {
+			l.raiseAlert(ctx, origin, entry.count)
+		}
+	}
+}
+
+// drain returns the counts of the current window and starts a new one
+func (l *CORSViolationLogger) drain() map[string]*corsOriginCount {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	counts := l.counts
+	l.counts = make(map[string]*corsOriginCount)
+	return counts
+}
+
+// raiseAlert creates a warning alert for an origin that exceeded the violation threshold
+func (l *CORSViolationLogger) raiseAlert(ctx context.Context, origin string, count int) {
+	l.logger.Warn("Repeated CORS violations", zap.String("origin", origin), zap.Int("count", count))
+
+	alert := &database.Alert{
+		Type:     "cors_violation",
+		Severity: string(AlertLevelWarning),
+		Title:    "Repeated CORS violations",
+		Message: fmt.Sprintf("Origin %s made %d cross-origin requests in a minute without being allowed. This may be an attempt to enumerate allowed origins.",
+			origin, count),
+		Metadata: database.JSONB{"origin": origin, "count": count},
+	}
+	if err := l.repo.CreateSystemAlert(ctx, alert); err != nil {
+		l.logger.Error("Failed to create CORS violation alert", zap.String("origin", origin), zap.Error(err))
+	}
+}
@@ -0,0 +1,62 @@
+package monitoring
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func TestCORSViolationLoggerCountsPerOrigin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	l := NewCORSViolationLogger(nil, nil, zap.NewNop())
+
+	record := func(origin, path string) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, path, nil)
+		l.RecordCORSViolation(c, origin)
+	}
+
+	for i := 0; i < 12; i++ {
+		record("https://probe.example", fmt.Sprintf("/api/v1/servers/%d", i))
+	}
+	record("https://other.example", "/api/v1/tools")
+
+	counts := l.drain()
+	if len(counts) != 2 {
+		t.Fatalf("got %d origins, want 2", len(counts))
+	}
+	probe := counts["https://probe.example"]
+	if probe.count != 12 || probe.path != "/api/v1/servers/0" {
+		t.Errorf("probe origin = %d violations sampled at %s, want 12 at /api/v1/servers/0", probe.count, probe.path)
+	}
+	if counts["https://other.example"].count != 1 {
+		t.Errorf("other origin = %d violations, want 1", counts["https://other.example"].count)
+	}
+
+	if remaining := l.drain(); len(remaining) != 0 {
+		t.Errorf("drain did not start a new window: %d origins left", len(remaining))
+	}
+}
+
+func TestCORSViolationLoggerBoundsOrigins(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	l := NewCORSViolationLogger(nil, nil, zap.NewNop())
+
+	for i := 0; i < maxCORSViolationOrigins+50; i++ {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		l.RecordCORSViolation(c, fmt.Sprintf("https://%d.example", i))
+	}
+
+	counts := l.drain()
+	if len(counts) != maxCORSViolationOrigins+1 {
+		t.Fatalf("got %d origins, want %d", len(counts), maxCORSViolationOrigins+1)
+	}
+	if counts["other"].count != 50 {
+		t.Errorf("other = %d violations, want 50", counts["other"].count)
+	}
+}
@@ -0,0 +1,172 @@
+package monitoring
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/security"
+	"go.uber.org/zap"
+)
+
+// Dashboard query limits
+const (
+	dashboardServerCount = 50
+	dashboardAlertCount  = 10
+	dashboardToolPeriod  = 24 * time.Hour
+	dashboardTimeout     = 10 * time.Second
+)
+
+// DashboardResponse holds everything the frontend dashboard shows. Sections whose query
+// failed are left empty and PartialFailure is set.
+type DashboardResponse struct {
+	Servers            []*database.MCPServer         `json:"servers"`
+	HealthSummary      *database.ServerHealthSummary `json:"health_summary"`
+	RecentAlerts       []*database.Alert             `json:"recent_alerts"`
+	ToolStats          *database.ToolExecutionStats  `json:"tool_stats"`
+	SecurityScores     []*database.SecurityScore     `json:"security_scores"`
+	SecurityScorecards []*security.SecurityScorecard `json:"security_scorecards,omitempty"`
+	GeneratedAt        time.Time                     `json:"generated_at"`
+	PartialFailure     bool                          `json:"partial_failure"`
+}
+
+// Dashboard aggregates the data behind the frontend dashboard
+type Dashboard struct {
+	repo       *database.Repository
+	scorecards *security.SecurityScorecardService
+	logger     *zap.Logger
+}
+
+// NewDashboard creates a dashboard aggregator
+func NewDashboard(repo *database.Repository, logger *zap.Logger) *Dashboard {
+	return &Dashboard{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Build runs the dashboard queries concurrently and merges their results. A failing
+// query does not cancel the others; its section is left empty instead.
+func (d *Dashboard) Build(ctx context.Context, orgID uuid.UUID) *DashboardResponse {
+	ctx, cancel := context.WithTimeout(ctx, dashboardTimeout)
+	defer cancel()
+
+	response := &DashboardResponse{GeneratedAt: time.Now()}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed bool
+	)
+	run := func(name string, query func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := query(); err != nil {
+				d.logger.Error("Dashboard query failed",
+					zap.String("query", name),
+					zap.String("organization_id", orgID.String()),
+					zap.Error(err))
+				mu.Lock()
+				failed = true
+				mu.Unlock()
+			}
+		}()
+	}
+
+	// Each query writes only its own field, so no locking is needed for the results
+	run("servers", func() (err error) {
+		response.Servers, err = d.repo.ListMCPServers(ctx, orgID, dashboardServerCount, 0)
+		return err
+	})
+	run("health_summary", func() (err error) {
+		response.HealthSummary, err = d.repo.GetServerHealthSummary(ctx, orgID)
+		return err
+	})
+	run("recent_alerts", func() (err error) {
+		response.RecentAlerts, err = d.repo.ListAlerts(ctx, orgID, dashboardAlertCount, 0)
+		return err
+	})
+	run("tool_stats", func() (err error) {
+		response.ToolStats, err = d.repo.GetToolExecutionStats(ctx, orgID, response.GeneratedAt.Add(-dashboardToolPeriod))
+		return err
+	})
+	run("security_scores", func() (err error) {
+		response.SecurityScores, err = d.repo.ListLatestSecurityScores(ctx, orgID)
+		return err
+	})
+	if d.scorecards != nil {
+		run("security_scorecards", func() (err error) {
+			response.SecurityScorecards, err = d.securityScorecards(ctx, orgID)
+			return err
+		})
+	}
+
+	wg.Wait()
+	response.PartialFailure = failed
+
+	return response
+}
+
+// securityScorecards computes the scorecards of the organization's dashboard servers
+// concurrently. Scorecards that fail are left out and the first error is returned.
+func (d *Dashboard) securityScorecards(ctx context.Context, orgID uuid.UUID) ([]*security.SecurityScorecard, error) {
+	servers, err := d.repo.ListMCPServers(ctx, orgID, dashboardServerCount, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	scorecards := make([]*security.SecurityScorecard, len(servers))
+	errs := make([]error, len(servers))
+	var wg sync.WaitGroup
+	for i, server := range servers {
+		wg.Add(1)
+		go func(i int, serverID uuid.UUID) {
+			defer wg.Done()
+			scorecards[i], errs[i] = d.scorecards.Compute(ctx, orgID, serverID)
+		}(i, server.ID)
+	}
+	wg.Wait()
+
+	var firstErr error
+	computed := make([]*security.SecurityScorecard, 0, len(servers))
+	for i, scorecard := range scorecards {
+		if errs[i] != nil {
+			if firstErr == nil {
+				firstErr = errs[i]
+			}
+			continue
+		}
+		computed = append(computed, scorecard)
+	}
+
+	return computed, firstErr
+}
+
+// GetDashboard returns the organization's dashboard data in a single response
+func (h *Handler) GetDashboard(c *gin.Context) {
+	orgID, exists := c.Get("organization_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	orgUUID, ok := orgID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID type"})
+		return
+	}
+
+	response := h.dashboard.Build(c.Request.Context(), orgUUID)
+
+	// Dashboard data may be slightly stale
+	c.Header("Cache-Control", "max-age=30")
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    response,
+	})
+}
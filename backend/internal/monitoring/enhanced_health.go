@@ -18,20 +18,22 @@ type EnhancedHealthMonitor struct {
 
 // HealthMetrics represents comprehensive health metrics
 type HealthMetrics struct {
-	ServerID      string                 `json:"server_id"`
-	Status        string                 `json:"status"`
-	ResponseTime  int64                  `json:"response_time_ms"`
-	LastChecked   time.Time              `json:"last_checked"`
-	Uptime        float64                `json:"uptime_percentage"`
-	ErrorRate     float64                `json:"error_rate"`
-	MemoryUsage   *MemoryUsage           `json:"memory_usage,omitempty"`
-	CPUUsage      *CPUUsage              `json:"cpu_usage,omitempty"`
-	NetworkStats  *NetworkStats          `json:"network_stats,omitempty"`
-	Version       string                 `json:"version,omitempty"`
-	Capabilities  []string               `json:"capabilities,omitempty"`
-	HealthScore   int                    `json:"health_score"` // 0-100
-	Alerts        []HealthAlert          `json:"alerts,omitempty"`
-	CustomMetrics map[string]interface{} `json:"custom_metrics,omitempty"`
+	ServerID             string                 `json:"server_id"`
+	Status               string                 `json:"status"`
+	ResponseTime         int64                  `json:"response_time_ms"`
+	NetworkLatencyMs     int64                  `json:"network_latency_ms"`     // TCP connection time
+	ApplicationLatencyMs int64                  `json:"application_latency_ms"` // ResponseTime - NetworkLatencyMs
+	LastChecked          time.Time              `json:"last_checked"`
+	Uptime               float64                `json:"uptime_percentage"`
+	ErrorRate            float64                `json:"error_rate"`
+	MemoryUsage          *MemoryUsage           `json:"memory_usage,omitempty"`
+	CPUUsage             *CPUUsage              `json:"cpu_usage,omitempty"`
+	NetworkStats         *NetworkStats          `json:"network_stats,omitempty"`
+	Version              string                 `json:"version,omitempty"`
+	Capabilities         []string               `json:"capabilities,omitempty"`
+	HealthScore          int                    `json:"health_score"` // 0-100
+	Alerts               []HealthAlert          `json:"alerts,omitempty"`
+	CustomMetrics        map[string]interface{} `json:"custom_metrics,omitempty"`
 }
 
 type MemoryUsage struct {
@@ -107,6 +109,9 @@ func (ehm *EnhancedHealthMonitor) PerformComprehensiveHealthCheck(ctx context.Co
 	// Check performance metrics
 	ehm.checkPerformanceMetrics(ctx, serverURL, metrics)
 
+	// Split the response time into network and application latency
+	ehm.checkNetworkLatency(serverURL, metrics)
+
 	// Calculate health score
 	ehm.calculateHealthScore(metrics)
 
@@ -316,16 +321,45 @@ func (ehm *EnhancedHealthMonitor) checkPerformanceMetrics(ctx context.Context, s
 	}
 }
 
+// checkNetworkLatency measures raw TCP latency to the server and attributes the rest of
+// the response time to the application
+func (ehm *EnhancedHealthMonitor) checkNetworkLatency(serverURL string, metrics *HealthMetrics) {
+	latency, err := probeServerURL(serverURL, tcpProbeTimeout)
+	if err != nil {
+		ehm.logger.Debug("TCP probe failed", zap.String("url", serverURL), zap.Error(err))
+		metrics.ApplicationLatencyMs = metrics.ResponseTime
+		return
+	}
+
+	metrics.NetworkLatencyMs = latency.Milliseconds()
+	metrics.ApplicationLatencyMs = metrics.ResponseTime - metrics.NetworkLatencyMs
+	if metrics.ApplicationLatencyMs < 0 {
+		metrics.ApplicationLatencyMs = 0
+	}
+}
+
 // calculateHealthScore calculates overall health score
 func (ehm *EnhancedHealthMonitor) calculateHealthScore(metrics *HealthMetrics) {
+	metrics.HealthScore = scoreHealth(metrics)
+}
+
+// scoreHealth scores health metrics from 0 to 100
+func scoreHealth(metrics *HealthMetrics) int {
 	score := 100
 
-	// Deduct points for high response time
-	if metrics.ResponseTime > 5000 { // > 5 seconds
+	// Deduct points for a slow server, excluding the time spent on the network
+	if metrics.ApplicationLatencyMs > 5000 { // > 5 seconds
 		score -= 30
-	} else if metrics.ResponseTime > 2000 { // > 2 seconds
+	} else if metrics.ApplicationLatencyMs > 2000 { // > 2 seconds
 		score -= 15
-	} else if metrics.ResponseTime > 1000 { // > 1 second
+	} else if metrics.ApplicationLatencyMs > 1000 { // > 1 second
+		score -= 5
+	}
+
+	// Deduct fewer points for a slow network, which the server cannot fix
+	if metrics.NetworkLatencyMs > 1000 {
+		score -= 10
+	} else if metrics.NetworkLatencyMs > 300 {
 		score -= 5
 	}
 
@@ -367,17 +401,24 @@ func (ehm *EnhancedHealthMonitor) calculateHealthScore(metrics *HealthMetrics) {
 		score = 100
 	}
 
-	metrics.HealthScore = score
+	return score
 }
 
 // checkForAlerts checks for various alert conditions
 func (ehm *EnhancedHealthMonitor) checkForAlerts(metrics *HealthMetrics) {
-	// High response time alert
-	if metrics.ResponseTime > 5000 {
+	// High response time alert, blamed on the server or the network
+	if metrics.ApplicationLatencyMs > 5000 {
 		metrics.Alerts = append(metrics.Alerts, HealthAlert{
 			Type:      "performance",
 			Severity:  "high",
-			Message:   fmt.Sprintf("High response time: %dms", metrics.ResponseTime),
+			Message:   fmt.Sprintf("High response time: %dms (application %dms)", metrics.ResponseTime, metrics.ApplicationLatencyMs),
+			Timestamp: time.Now(),
+		})
+	} else if metrics.ResponseTime > 5000 {
+		metrics.Alerts = append(metrics.Alerts, HealthAlert{
+			Type:      "network",
+			Severity:  "medium",
+			Message:   fmt.Sprintf("High response time: %dms (network %dms)", metrics.ResponseTime, metrics.NetworkLatencyMs),
 			Timestamp: time.Now(),
 		})
 	}
@@ -0,0 +1,201 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"go.uber.org/zap"
+)
+
+// Escalation contact schemes
+const (
+	ContactSchemeSMS       = "sms:"       // sms:+15551234567, sent through Twilio
+	ContactSchemePagerDuty = "pagerduty:" // pagerduty:<routing key>, sent to the Events API
+)
+
+// escalationCheckInterval is how often unresolved critical alerts are checked
+const escalationCheckInterval = 5 * time.Minute
+
+// EscalationPolicy decides who is paged while a critical alert stays unresolved.
+// Level 1 is paged Level1Delay after the alert is raised, level 2 Level2Delay after
+// level 1, and level 3 another Level2Delay after level 2.
+type EscalationPolicy struct {
+	Level1Delay    time.Duration
+	Level1Contacts []string
+	Level2Delay    time.Duration
+	Level2Contacts []string
+	Level3Contacts []string
+}
+
+// EscalationPolicyFromRecord converts a stored escalation policy
+func EscalationPolicyFromRecord(record *database.EscalationPolicy) EscalationPolicy {
+	return EscalationPolicy{
+		Level1Delay:    time.Duration(record.Level1DelaySeconds) * time.Second,
+		Level1Contacts: record.Level1Contacts,
+		Level2Delay:    time.Duration(record.Level2DelaySeconds) * time.Second,
+		Level2Contacts: record.Level2Contacts,
+		Level3Contacts: record.Level3Contacts,
+	}
+}
+
+// Validate checks the delays and that every contact uses a supported scheme
+func (p EscalationPolicy) Validate() error {
+	if p.Level1Delay <= 0 {
+		return fmt.Errorf("level 1 delay must be positive")
+	}
+	if len(p.Level1Contacts) == 0 {
+		return fmt.Errorf("level 1 requires at least one contact")
+	}
+	if (len(p.Level2Contacts) > 0 || len(p.Level3Contacts) > 0) && p.Level2Delay <= 0 {
+		return fmt.Errorf("level 2 delay must be positive when later levels have contacts")
+	}
+
+	for _, contacts := range [][]string{p.Level1Contacts, p.Level2Contacts, p.Level3Contacts} {
+		for _, contact := range contacts {
+			if err := validateEscalationContact(contact); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// MaxLevel returns the last level that has contacts
+func (p EscalationPolicy) MaxLevel() int {
+	switch {
+	case len(p.Level3Contacts) > 0:
+		return 3
+	case len(p.Level2Contacts) > 0:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// contacts returns the contacts paged at a level
+func (p EscalationPolicy) contacts(level int) []string {
+	switch level {
+	case 1:
+		return p.Level1Contacts
+	case 2:
+		return p.Level2Contacts
+	case 3:
+		return p.Level3Contacts
+	default:
+		return nil
+	}
+}
+
+// dueAt returns when an alert at its current escalation level moves to the next level
+func (p EscalationPolicy) dueAt(pending *database.PendingEscalation) time.Time {
+	if pending.EscalationLevel == 0 || pending.LastEscalatedAt == nil {
+		return pending.CreatedAt.Add(p.Level1Delay)
+	}
+	return pending.LastEscalatedAt.Add(p.Level2Delay)
+}
+
+// validateEscalationContact checks that a contact uses a supported scheme
+func validateEscalationContact(contact string) error {
+	for _, scheme := range []string{ContactSchemeSMS, ContactSchemePagerDuty} {
+		if strings.HasPrefix(contact, scheme) && len(contact) > len(scheme) {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported escalation contact %q: use sms:<number> or pagerduty:<routing key>", contact)
+}
+
+// Pager pages an on-call contact about an alert
+type Pager interface {
+	Page(ctx context.Context, contact string, alert *database.Alert) error
+}
+
+// EscalationJob pages on-call contacts for critical alerts that stay unresolved
+type EscalationJob struct {
+	repo   *database.Repository
+	pager  Pager
+	logger *zap.Logger
+}
+
+// NewEscalationJob creates an alert escalation job
+func NewEscalationJob(repo *database.Repository, pager Pager, logger *zap.Logger) *EscalationJob {
+	return &EscalationJob{
+		repo:   repo,
+		pager:  pager,
+		logger: logger,
+	}
+}
+
+// Start checks for alerts to escalate every 5 minutes until the context is cancelled
+func (j *EscalationJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(escalationCheckInterval)
+	defer ticker.Stop()
+
+	j.logger.Info("Started alert escalation", zap.Duration("interval", escalationCheckInterval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Stopping alert escalation")
+			return
+		case now := <-ticker.C:
+			j.run(ctx, now)
+		}
+	}
+}
+
+// run escalates every overdue alert in organizations with an escalation policy
+func (j *EscalationJob) run(ctx context.Context, now time.Time) {
+	records, err := j.repo.ListEscalationPolicies(ctx)
+	if err != nil {
+		j.logger.Error("Failed to list escalation policies", zap.Error(err))
+		return
+	}
+
+	for _, record := range records {
+		policy := EscalationPolicyFromRecord(record)
+
+		pending, err := j.repo.ListPendingEscalations(ctx, record.OrganizationID, policy.MaxLevel())
+		if err != nil {
+			j.logger.Error("Failed to list pending escalations",
+				zap.String("organization_id", record.OrganizationID.String()),
+				zap.Error(err))
+			continue
+		}
+
+		for _, alert := range pending {
+			if now.Before(policy.dueAt(alert)) {
+				continue
+			}
+			j.escalate(ctx, policy, alert, now)
+		}
+	}
+}
+
+// escalate moves an alert to its next level and pages that level's contacts
+func (j *EscalationJob) escalate(ctx context.Context, policy EscalationPolicy, pending *database.PendingEscalation, now time.Time) {
+	level := pending.EscalationLevel + 1
+
+	// Record the level first so a failing pager does not page the same level every run
+	if err := j.repo.RecordAlertEscalation(ctx, pending.ID, pending.OrganizationID, level, now); err != nil {
+		j.logger.Error("Failed to record alert escalation", zap.String("alert_id", pending.ID.String()), zap.Error(err))
+		return
+	}
+
+	for _, contact := range policy.contacts(level) {
+		if err := j.pager.Page(ctx, contact, &pending.Alert); err != nil {
+			j.logger.Error("Failed to page on-call contact",
+				zap.String("alert_id", pending.ID.String()),
+				zap.Int("level", level),
+				zap.Error(err))
+		}
+	}
+
+	j.logger.Info("Escalated alert",
+		zap.String("alert_id", pending.ID.String()),
+		zap.String("organization_id", pending.OrganizationID.String()),
+		zap.Int("level", level))
+}
@@ -1,20 +1,30 @@
 package monitoring
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/auth"
 	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/log"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/middleware"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/security"
 	"go.uber.org/zap"
 )
 
 // Handler handles monitoring-related HTTP requests
 type Handler struct {
 	healthChecker *HealthChecker
+	dashboard     *Dashboard
 	repo          *database.Repository
 	logger        *zap.Logger
+	alerts        *AlertHub
 }
 
 // NewHandler creates a new monitoring handler
@@ -22,19 +32,30 @@ func NewHandler(repo *database.Repository, logger *zap.Logger) *Handler {
 	healthChecker := NewHealthChecker(repo, logger)
 	return &Handler{
 		healthChecker: healthChecker,
+		dashboard:     NewDashboard(repo, logger),
 		repo:          repo,
 		logger:        logger,
+		alerts:        DefaultAlertHub(),
 	}
 }
 
+// SetScorecardService adds security scorecards to the dashboard
+func (h *Handler) SetScorecardService(scorecards *security.SecurityScorecardService) {
+	h.dashboard.scorecards = scorecards
+}
+
 // RegisterRoutes registers monitoring routes
 func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/dashboard", h.GetDashboard)
+
 	monitoring := rg.Group("/monitoring")
 	{
 		monitoring.GET("/health/:server_id", h.CheckServerHealth)
 		monitoring.POST("/health/check-all", h.CheckAllServers)
 		monitoring.GET("/servers", h.ListServers)
+		monitoring.GET("/servers/:id/score-history", h.GetHealthScoreHistory)
 		monitoring.GET("/alerts", h.ListAlerts)
+		monitoring.GET("/alerts/stream", h.StreamAlerts)
 		monitoring.POST("/alerts/:id/resolve", h.ResolveAlert)
 	}
 }
@@ -47,9 +68,19 @@ func (h *Handler) CheckServerHealth(c *gin.Context) {
 		return
 	}
 
-	status, err := h.healthChecker.CheckServerHealth(c.Request.Context(), serverID)
+	orgUUID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+	if _, err := h.repo.GetMCPServer(c.Request.Context(), orgUUID, serverID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Server not found"})
+		return
+	}
+
+	status, err := h.healthChecker.CheckServerHealth(c.Request.Context(), orgUUID, serverID)
 	if err != nil {
-		h.logger.Error("Failed to check server health", 
+		log.FromCtx(c.Request.Context()).Error("Failed to check server health", 
 			zap.String("server_id", serverID),
 			zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check server health"})
@@ -66,7 +97,7 @@ func (h *Handler) CheckServerHealth(c *gin.Context) {
 func (h *Handler) CheckAllServers(c *gin.Context) {
 	err := h.healthChecker.CheckAllServers(c.Request.Context())
 	if err != nil {
-		h.logger.Error("Failed to check all servers", zap.Error(err))
+		log.FromCtx(c.Request.Context()).Error("Failed to check all servers", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check all servers"})
 		return
 	}
@@ -108,18 +139,66 @@ func (h *Handler) ListServers(c *gin.Context) {
 
 	servers, err := h.repo.ListMCPServers(c.Request.Context(), orgUUID, limit, offset)
 	if err != nil {
-		h.logger.Error("Failed to list servers", zap.Error(err))
+		log.FromCtx(c.Request.Context()).Error("Failed to list servers", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list servers"})
 		return
 	}
 
+	page := middleware.NewPage(servers, limit, offset)
+	if total, err := h.repo.CountMCPServers(c.Request.Context(), orgUUID); err == nil {
+		page.Total = total
+	}
+	middleware.SetPage(c, page)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       page.Items,
+		"pagination": page.Meta(),
+	})
+}
+
+// maxScoreHistoryHours is the longest health score history that can be requested
+const maxScoreHistoryHours = 720
+
+// GetHealthScoreHistory returns a server's health scores over the last hours hours
+// (24 by default). Scores still buffered in memory are not included.
+func (h *Handler) GetHealthScoreHistory(c *gin.Context) {
+	serverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid server ID"})
+		return
+	}
+
+	hours, err := strconv.Atoi(c.DefaultQuery("hours", "24"))
+	if err != nil || hours <= 0 || hours > maxScoreHistoryHours {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("hours must be between 1 and %d", maxScoreHistoryHours)})
+		return
+	}
+
+	orgUUID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+	if _, err := h.repo.GetMCPServer(c.Request.Context(), orgUUID, serverID.String()); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Server not found"})
+		return
+	}
+
+	since := time.Now().Add(-time.Duration(hours) * time.Hour)
+	samples, err := h.repo.GetHealthScoreHistory(c.Request.Context(), serverID, since)
+	if err != nil {
+		log.FromCtx(c.Request.Context()).Error("Failed to get health score history", zap.String("server_id", serverID.String()), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get health score history"})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"data":    servers,
-		"pagination": gin.H{
-			"limit":  limit,
-			"offset": offset,
-			"count":  len(servers),
+		"data": gin.H{
+			"server_id": serverID,
+			"hours":     hours,
+			"scores":    samples,
 		},
 	})
 }
@@ -155,19 +234,18 @@ func (h *Handler) ListAlerts(c *gin.Context) {
 
 	alerts, err := h.repo.ListAlerts(c.Request.Context(), orgUUID, limit, offset)
 	if err != nil {
-		h.logger.Error("Failed to list alerts", zap.Error(err))
+		log.FromCtx(c.Request.Context()).Error("Failed to list alerts", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list alerts"})
 		return
 	}
 
+	page := middleware.NewPage(alerts, limit, offset)
+	middleware.SetPage(c, page)
+
 	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    alerts,
-		"pagination": gin.H{
-			"limit":  limit,
-			"offset": offset,
-			"count":  len(alerts),
-		},
+		"success":    true,
+		"data":       page.Items,
+		"pagination": page.Meta(),
 	})
 }
 
@@ -186,9 +264,19 @@ func (h *Handler) ResolveAlert(c *gin.Context) {
 		return
 	}
 
-	err := h.repo.ResolveAlert(c.Request.Context(), alertID, userID.(string))
+	orgUUID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	err := h.repo.ResolveAlert(c.Request.Context(), orgUUID, alertID, fmt.Sprint(userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Alert not found"})
+		return
+	}
 	if err != nil {
-		h.logger.Error("Failed to resolve alert", 
+		log.FromCtx(c.Request.Context()).Error("Failed to resolve alert", 
 			zap.String("alert_id", alertID),
 			zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve alert"})
@@ -2,12 +2,15 @@ package monitoring
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/registry"
 	"go.uber.org/zap"
 )
 
@@ -16,19 +19,39 @@ type HealthChecker struct {
 	repo   *database.Repository
 	logger *zap.Logger
 	client *http.Client
+
+	// Periodic checks run on a ticker per server, keyed by server ID
+	mu              sync.Mutex
+	defaultInterval time.Duration
+	tickers         map[uuid.UUID]*time.Ticker
+	intervals       map[uuid.UUID]time.Duration
+	stops           map[uuid.UUID]chan struct{}
+
+	// scores, if set, receives the health score of every check
+	scores *registry.HealthScoreCache
 }
 
+// Bounds of a server's health check interval
+const (
+	MinCheckInterval = 5 * time.Second
+	MaxCheckInterval = 24 * time.Hour
+)
+
+// ErrInvalidCheckInterval is returned for a check interval outside its bounds
+var ErrInvalidCheckInterval = errors.New("check interval must be between 5 seconds and 24 hours")
+
 // HealthStatus represents the health status of an MCP server
 type HealthStatus struct {
-	ServerID      string    `json:"server_id"`
-	Status        string    `json:"status"`        // online, offline, error, unknown
-	ResponseTime  int64     `json:"response_time"` // milliseconds
-	LastChecked   time.Time `json:"last_checked"`
-	ErrorMessage  string    `json:"error_message,omitempty"`
-	Uptime        string    `json:"uptime,omitempty"`
-	MemoryUsage   string    `json:"memory_usage,omitempty"`
-	Version       string    `json:"version,omitempty"`
-	Capabilities  []string  `json:"capabilities,omitempty"`
+	ServerID       string    `json:"server_id"`
+	Status         string    `json:"status"`        // online, offline, error, unknown
+	ResponseTime   int64     `json:"response_time"` // milliseconds
+	NetworkLatency int64     `json:"network_latency_ms,omitempty"`
+	LastChecked    time.Time `json:"last_checked"`
+	ErrorMessage   string    `json:"error_message,omitempty"`
+	Uptime         string    `json:"uptime,omitempty"`
+	MemoryUsage    string    `json:"memory_usage,omitempty"`
+	Version        string    `json:"version,omitempty"`
+	Capabilities   []string  `json:"capabilities,omitempty"`
 }
 
 // NewHealthChecker creates a new health checker instance
@@ -42,10 +65,15 @@ func NewHealthChecker(repo *database.Repository, logger *zap.Logger) *HealthChec
 	}
 }
 
+// SetHealthScoreCache records the health score of every check in cache
+func (hc *HealthChecker) SetHealthScoreCache(cache *registry.HealthScoreCache) {
+	hc.scores = cache
+}
+
 // CheckServerHealth performs a health check on a specific MCP server
-func (hc *HealthChecker) CheckServerHealth(ctx context.Context, serverID string) (*HealthStatus, error) {
+func (hc *HealthChecker) CheckServerHealth(ctx context.Context, organizationID uuid.UUID, serverID string) (*HealthStatus, error) {
 	// Get server details from database
-	server, err := hc.repo.GetMCPServer(ctx, serverID)
+	server, err := hc.repo.GetMCPServer(ctx, organizationID, serverID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get server: %w", err)
 	}
@@ -82,6 +110,14 @@ func (hc *HealthChecker) CheckServerHealth(ctx context.Context, serverID string)
 			zap.Int64("response_time", status.ResponseTime))
 	}
 
+	// Measure raw TCP latency to tell a slow network from a slow server
+	var tcpLatencyMs *int
+	if latency, probeErr := probeServerURL(server.URL, tcpProbeTimeout); probeErr == nil {
+		status.NetworkLatency = latency.Milliseconds()
+		ms := int(status.NetworkLatency)
+		tcpLatencyMs = &ms
+	}
+
 	// Update server status in database
 	serverUUID, err := uuid.Parse(serverID)
 	if err != nil {
@@ -93,12 +129,16 @@ func (hc *HealthChecker) CheckServerHealth(ctx context.Context, serverID string)
 			errorMsg = &status.ErrorMessage
 		}
 		
-		updateErr := hc.repo.UpdateMCPServerStatus(ctx, serverUUID, status.Status, &responseTimeMs, errorMsg)
+		updateErr := hc.repo.UpdateMCPServerStatus(ctx, organizationID, serverUUID, status.Status, &responseTimeMs, tcpLatencyMs, errorMsg, nil)
 		if updateErr != nil {
 			hc.logger.Error("Failed to update server status", 
 				zap.String("server_id", serverID),
 				zap.Error(updateErr))
 		}
+
+		if hc.scores != nil {
+			hc.scores.Set(serverUUID, statusHealthScore(server, status))
+		}
 	}
 
 	// Log status change if needed
@@ -244,7 +284,7 @@ func (hc *HealthChecker) CheckAllServers(ctx context.Context) error {
 		zap.Int("server_count", len(servers)))
 
 	for _, server := range servers {
-		_, err := hc.CheckServerHealth(ctx, server.ID.String())
+		_, err := hc.CheckServerHealth(ctx, server.OrganizationID, server.ID.String())
 		if err != nil {
 			hc.logger.Error("Health check failed for server",
 				zap.String("server_id", server.ID.String()),
@@ -256,22 +296,152 @@ func (hc *HealthChecker) CheckAllServers(ctx context.Context) error {
 	return nil
 }
 
-// StartPeriodicHealthChecks starts a background routine for periodic health checks
+// StartPeriodicHealthChecks checks every active server on its own ticker until ctx is
+// done. Servers use their configured check interval, or interval if they have none. The
+// server list is reloaded every interval to pick up added and removed servers.
 func (hc *HealthChecker) StartPeriodicHealthChecks(ctx context.Context, interval time.Duration) {
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
+	hc.mu.Lock()
+	hc.defaultInterval = interval
+	hc.tickers = make(map[uuid.UUID]*time.Ticker)
+	hc.intervals = make(map[uuid.UUID]time.Duration)
+	hc.stops = make(map[uuid.UUID]chan struct{})
+	hc.mu.Unlock()
+	defer hc.stopServerChecks()
 
 	hc.logger.Info("Starting periodic health checks",
-		zap.Duration("interval", interval))
+		zap.Duration("default_interval", interval))
+
+	hc.syncServerChecks(ctx)
+
+	reload := time.NewTicker(interval)
+	defer reload.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			hc.logger.Info("Stopping periodic health checks")
 			return
+		case <-reload.C:
+			hc.syncServerChecks(ctx)
+		}
+	}
+}
+
+// SetServerCheckInterval stores how often a server's health is checked and applies it
+// to the running checks. An interval of zero restores the default. sql.ErrNoRows is
+// returned if the server does not exist or belongs to another organization.
+func (hc *HealthChecker) SetServerCheckInterval(ctx context.Context, organizationID, serverID uuid.UUID, interval time.Duration) error {
+	if interval != 0 && (interval < MinCheckInterval || interval > MaxCheckInterval) {
+		return ErrInvalidCheckInterval
+	}
+
+	if err := hc.repo.SetMCPServerCheckInterval(ctx, organizationID, serverID, interval); err != nil {
+		return err
+	}
+
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+
+	// Servers without a ticker yet get one on the next reload
+	if _, ok := hc.tickers[serverID]; ok {
+		if interval == 0 {
+			interval = hc.defaultInterval
+		}
+		hc.scheduleServerLocked(ctx, organizationID, serverID, interval)
+	}
+
+	return nil
+}
+
+// syncServerChecks starts checks of new servers, applies changed intervals and stops
+// checks of servers that were removed
+func (hc *HealthChecker) syncServerChecks(ctx context.Context) {
+	servers, err := hc.repo.ListActiveMCPServers(ctx)
+	if err != nil {
+		hc.logger.Error("Failed to load servers for health checks", zap.Error(err))
+		return
+	}
+
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+
+	active := make(map[uuid.UUID]bool, len(servers))
+	for _, server := range servers {
+		active[server.ID] = true
+
+		interval := hc.defaultInterval
+		if server.CheckInterval != nil {
+			interval = *server.CheckInterval
+		}
+		hc.scheduleServerLocked(ctx, server.OrganizationID, server.ID, interval)
+	}
+
+	for serverID := range hc.tickers {
+		if !active[serverID] {
+			hc.unscheduleServerLocked(serverID)
+		}
+	}
+}
+
+// scheduleServerLocked starts a server's ticker, or resets it if its interval changed.
+// hc.mu must be held.
+func (hc *HealthChecker) scheduleServerLocked(ctx context.Context, organizationID, serverID uuid.UUID, interval time.Duration) {
+	if interval < MinCheckInterval {
+		interval = MinCheckInterval
+	}
+
+	if ticker, ok := hc.tickers[serverID]; ok {
+		if hc.intervals[serverID] != interval {
+			ticker.Reset(interval)
+			hc.intervals[serverID] = interval
+			hc.logger.Info("Changed server health check interval",
+				zap.String("server_id", serverID.String()),
+				zap.Duration("interval", interval))
+		}
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	stop := make(chan struct{})
+	hc.tickers[serverID] = ticker
+	hc.intervals[serverID] = interval
+	hc.stops[serverID] = stop
+
+	go hc.runServerChecks(ctx, organizationID, serverID, ticker, stop)
+}
+
+// unscheduleServerLocked stops a server's checks. hc.mu must be held.
+func (hc *HealthChecker) unscheduleServerLocked(serverID uuid.UUID) {
+	hc.tickers[serverID].Stop()
+	close(hc.stops[serverID])
+	delete(hc.tickers, serverID)
+	delete(hc.intervals, serverID)
+	delete(hc.stops, serverID)
+}
+
+// stopServerChecks stops the checks of every server
+func (hc *HealthChecker) stopServerChecks() {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+
+	for serverID := range hc.tickers {
+		hc.unscheduleServerLocked(serverID)
+	}
+}
+
+// runServerChecks checks a server's health on every tick until stopped
+func (hc *HealthChecker) runServerChecks(ctx context.Context, organizationID, serverID uuid.UUID, ticker *time.Ticker, stop <-chan struct{}) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-stop:
+			return
 		case <-ticker.C:
-			if err := hc.CheckAllServers(ctx); err != nil {
-				hc.logger.Error("Periodic health check failed", zap.Error(err))
+			if _, err := hc.CheckServerHealth(ctx, organizationID, serverID.String()); err != nil {
+				hc.logger.Error("Health check failed for server",
+					zap.String("server_id", serverID.String()),
+					zap.Error(err))
 			}
 		}
 	}
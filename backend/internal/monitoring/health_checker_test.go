@@ -0,0 +1,88 @@
+package monitoring
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestHealthCheckerWithMockTransport(t *testing.T) {
+	mock := NewDeterministicHealthMock().
+		On(`^http://up\.invalid/`, HealthCheckResult{Status: "online"}).
+		On(`^http://degraded\.invalid/`, HealthCheckResult{Status: "error", Error: "overloaded"}).
+		On(`^http://down\.invalid/`, HealthCheckResult{Status: "offline"})
+	hc := NewHealthChecker(nil, zap.NewNop()).WithMockTransport(mock.Transport())
+
+	type check func(hc *HealthChecker, ctx context.Context, url string, status *HealthStatus) error
+
+	tests := []struct {
+		name      string
+		check     check
+		url       string
+		wantError string
+	}{
+		{"http online", (*HealthChecker).checkHTTPServer, "http://up.invalid", ""},
+		{"http unhealthy", (*HealthChecker).checkHTTPServer, "http://degraded.invalid", "server returned status 503"},
+		{"http unreachable", (*HealthChecker).checkHTTPServer, "http://down.invalid", "connection refused"},
+		{"filesystem online", (*HealthChecker).checkFilesystemServer, "http://up.invalid", ""},
+		{"filesystem unhealthy", (*HealthChecker).checkFilesystemServer, "http://degraded.invalid", "filesystem server returned status 503"},
+		{"generic online", (*HealthChecker).checkGenericServer, "http://up.invalid/", ""},
+		{"generic unreachable", (*HealthChecker).checkGenericServer, "http://down.invalid/", "server not responding"},
+		{"unmatched url", (*HealthChecker).checkHTTPServer, "http://unknown.invalid", "no health mock result"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := &HealthStatus{}
+			err := tt.check(hc, context.Background(), tt.url, status)
+
+			if tt.wantError == "" {
+				if err != nil {
+					t.Fatalf("check error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+				t.Errorf("check error = %v, want one containing %q", err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestDeterministicHealthMockSequence(t *testing.T) {
+	mock := NewDeterministicHealthMock().On(`flaky\.invalid`,
+		HealthCheckResult{Status: "online"},
+		HealthCheckResult{Status: "offline"},
+		HealthCheckResult{Status: "online", ResponseTime: 20 * time.Millisecond},
+	)
+	hc := NewHealthChecker(nil, zap.NewNop()).WithMockTransport(mock.Transport())
+
+	// The last result repeats once the sequence is exhausted
+	wantOnline := []bool{true, false, true, true}
+	for i, want := range wantOnline {
+		status := &HealthStatus{}
+		err := hc.checkHTTPServer(context.Background(), "http://flaky.invalid", status)
+		if online := err == nil; online != want {
+			t.Fatalf("check %d online = %v (error %v), want %v", i, online, err, want)
+		}
+		if i >= 2 && status.ResponseTime < 20 {
+			t.Errorf("check %d response time = %dms, want at least the mocked 20ms", i, status.ResponseTime)
+		}
+	}
+}
+
+func TestDeterministicHealthMockHonorsContext(t *testing.T) {
+	mock := NewDeterministicHealthMock().On(`slow\.invalid`, HealthCheckResult{Status: "online", ResponseTime: time.Minute})
+	hc := NewHealthChecker(nil, zap.NewNop()).WithMockTransport(mock.Transport())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := hc.checkHTTPServer(ctx, "http://slow.invalid", &HealthStatus{})
+	if err == nil || !strings.Contains(err.Error(), "context deadline exceeded") {
+		t.Errorf("check of a slow server error = %v, want the context deadline", err)
+	}
+}
@@ -0,0 +1,140 @@
+package monitoring
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RoundTripperFunc adapts a function to an http.RoundTripper
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req)
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// WithMockTransport replaces the health checker's HTTP transport, so checks never reach
+// real servers. It is intended for tests.
+func (hc *HealthChecker) WithMockTransport(transport RoundTripperFunc) *HealthChecker {
+	hc.client = &http.Client{
+		Timeout:   hc.client.Timeout,
+		Transport: transport,
+	}
+	return hc
+}
+
+// healthMockRoute is a URL pattern and the results it returns in order
+type healthMockRoute struct {
+	pattern *regexp.Regexp
+	results []HealthCheckResult
+	next    int
+}
+
+// DeterministicHealthMock answers health check requests with pre-configured results.
+// Each URL pattern returns its results in order and repeats the last one once the
+// sequence is exhausted. Results are interpreted by status: "online" answers 200,
+// "offline" fails the request as if the server were unreachable, and anything else
+// answers 503 with the result's error as the body. A non-zero ResponseTime delays
+// the response by that long.
+type DeterministicHealthMock struct {
+	mu     sync.Mutex
+	routes []*healthMockRoute
+}
+
+// NewDeterministicHealthMock creates a mock with no routes
+func NewDeterministicHealthMock() *DeterministicHealthMock {
+	return &DeterministicHealthMock{}
+}
+
+// On registers the results returned for request URLs matching pattern, a regular
+// expression. Patterns are tried in the order they were registered. It panics if the
+// pattern does not compile or no results are given.
+func (m *DeterministicHealthMock) On(pattern string, results ...HealthCheckResult) *DeterministicHealthMock {
+	if len(results) == 0 {
+		panic("health mock route needs at least one result")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.routes = append(m.routes, &healthMockRoute{
+		pattern: regexp.MustCompile(pattern),
+		results: results,
+	})
+	return m
+}
+
+// Transport returns the mock as a transport for HealthChecker.WithMockTransport
+func (m *DeterministicHealthMock) Transport() RoundTripperFunc {
+	return m.RoundTrip
+}
+
+// RoundTrip answers a request with the next result for the first matching pattern
+func (m *DeterministicHealthMock) RoundTrip(req *http.Request) (*http.Response, error) {
+	result, ok := m.nextResult(req.URL.String())
+	if !ok {
+		return nil, fmt.Errorf("no health mock result for %s", req.URL)
+	}
+
+	if result.ResponseTime > 0 {
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(result.ResponseTime):
+		}
+	}
+
+	switch result.Status {
+	case "online":
+		return mockHealthResponse(req, http.StatusOK, ""), nil
+	case "offline":
+		message := result.Error
+		if message == "" {
+			message = "connection refused"
+		}
+		return nil, errors.New(message)
+	default:
+		return mockHealthResponse(req, http.StatusServiceUnavailable, result.Error), nil
+	}
+}
+
+// nextResult advances the sequence of the first route matching url
+func (m *DeterministicHealthMock) nextResult(url string) (HealthCheckResult, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, route := range m.routes {
+		if !route.pattern.MatchString(url) {
+			continue
+		}
+
+		result := route.results[route.next]
+		if route.next < len(route.results)-1 {
+			route.next++
+		}
+		return result, true
+	}
+
+	return HealthCheckResult{}, false
+}
+
+// mockHealthResponse builds a response for a mocked health check
+func mockHealthResponse(req *http.Request, statusCode int, body string) *http.Response {
+	return &http.Response{
+		StatusCode:    statusCode,
+		Status:        fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        make(http.Header),
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,198 @@
+package monitoring
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"go.uber.org/zap"
+)
+
+// DefaultHealthScoreBufferCapacity holds a week of one-minute samples
+const DefaultHealthScoreBufferCapacity = 10080
+
+// healthScoreFlushInterval is how often buffered health scores are written to the database
+const healthScoreFlushInterval = 5 * time.Minute
+
+// HealthScorePoint is a server's health score at one health check
+type HealthScorePoint struct {
+	ServerID  uuid.UUID `json:"server_id"`
+	Score     int       `json:"score"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// HealthScoreBuffer is a fixed-capacity ring buffer of health scores waiting to be
+// flushed. Once full, each new point overwrites the oldest.
+type HealthScoreBuffer struct {
+	mu      sync.Mutex
+	points  []HealthScorePoint
+	start   int // index of the oldest point
+	count   int
+	dropped uint64
+}
+
+// NewHealthScoreBuffer creates a health score buffer holding up to capacity points,
+// DefaultHealthScoreBufferCapacity if capacity is not positive
+func NewHealthScoreBuffer(capacity int) *HealthScoreBuffer {
+	if capacity <= 0 {
+		capacity = DefaultHealthScoreBufferCapacity
+	}
+	return &HealthScoreBuffer{points: make([]HealthScorePoint, capacity)}
+}
+
+// Push adds a point, overwriting the oldest one if the buffer is full
+func (b *HealthScoreBuffer) Push(point HealthScorePoint) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	capacity := len(b.points)
+	if b.count == capacity {
+		b.points[b.start] = point
+		b.start = (b.start + 1) % capacity
+		b.dropped++
+		return
+	}
+	b.points[(b.start+b.count)%capacity] = point
+	b.count++
+}
+
+// Drain removes and returns all buffered points, oldest first
+func (b *HealthScoreBuffer) Drain() []HealthScorePoint {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	drained := make([]HealthScorePoint, b.count)
+	for i := range drained {
+		drained[i] = b.points[(b.start+i)%len(b.points)]
+	}
+	b.start = 0
+	b.count = 0
+	return drained
+}
+
+// Len returns the number of buffered points
+func (b *HealthScoreBuffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.count
+}
+
+// Dropped returns how many points were overwritten before they could be flushed
+func (b *HealthScoreBuffer) Dropped() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.dropped
+}
+
+// HealthScoreFlushJob periodically writes buffered health scores to health_score_history
+type HealthScoreFlushJob struct {
+	buffer *HealthScoreBuffer
+	repo   *database.Repository
+	logger *zap.Logger
+}
+
+// NewHealthScoreFlushJob creates a health score flush job
+func NewHealthScoreFlushJob(buffer *HealthScoreBuffer, repo *database.Repository, logger *zap.Logger) *HealthScoreFlushJob {
+	return &HealthScoreFlushJob{
+		buffer: buffer,
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Start flushes the buffer every 5 minutes until the context is cancelled
+func (j *HealthScoreFlushJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(healthScoreFlushInterval)
+	defer ticker.Stop()
+
+	j.logger.Info("Started health score flush", zap.Duration("interval", healthScoreFlushInterval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Stopping health score flush")
+			return
+		case <-ticker.C:
+			j.Flush(ctx)
+		}
+	}
+}
+
+// Flush writes the buffered points in one insert. If the insert fails they are put
+// back to be retried on the next flush.
+func (j *HealthScoreFlushJob) Flush(ctx context.Context) {
+	points := j.buffer.Drain()
+	if len(points) == 0 {
+		return
+	}
+
+	samples := make([]database.HealthScoreSample, len(points))
+	for i, point := range points {
+		samples[i] = database.HealthScoreSample{
+			ServerID:   point.ServerID,
+			Score:      point.Score,
+			RecordedAt: point.Timestamp,
+		}
+	}
+
+	if err := j.repo.InsertHealthScores(ctx, samples); err != nil {
+		j.logger.Error("Failed to flush health scores", zap.Int("points", len(points)), zap.Error(err))
+		for _, point := range points {
+			j.buffer.Push(point)
+		}
+		return
+	}
+
+	j.logger.Debug("Flushed health scores", zap.Int("points", len(points)))
+}
+
+// healthScore scores a monitored server's latest health check like the comprehensive
+// health check does; an offline server scores 0
+func healthScore(monitor *ServerMonitor, result *HealthCheckResult) int {
+	if result.Status != "online" {
+		return 0
+	}
+
+	metrics := &HealthMetrics{
+		ResponseTime:     result.ResponseTime.Milliseconds(),
+		NetworkLatencyMs: result.NetworkLatency.Milliseconds(),
+		Uptime:           monitor.Metrics.UptimePercentage,
+	}
+	metrics.ApplicationLatencyMs = metrics.ResponseTime - metrics.NetworkLatencyMs
+	if metrics.ApplicationLatencyMs < 0 {
+		metrics.ApplicationLatencyMs = 0
+	}
+	if monitor.Metrics.TotalRequests > 0 {
+		metrics.ErrorRate = float64(monitor.Metrics.FailedRequests) / float64(monitor.Metrics.TotalRequests) * 100
+	}
+
+	return scoreHealth(metrics)
+}
+
+// statusHealthScore scores a health checker result like healthScore, using the uptime
+// and error rate stored for the server; an offline server scores 0
+func statusHealthScore(server *database.MCPServer, status *HealthStatus) int {
+	if status.Status != "online" {
+		return 0
+	}
+
+	metrics := &HealthMetrics{
+		ResponseTime:     status.ResponseTime,
+		NetworkLatencyMs: status.NetworkLatency,
+		Uptime:           100,
+	}
+	metrics.ApplicationLatencyMs = metrics.ResponseTime - metrics.NetworkLatencyMs
+	if metrics.ApplicationLatencyMs < 0 {
+		metrics.ApplicationLatencyMs = 0
+	}
+	if server.UptimePercentage != nil {
+		metrics.Uptime = *server.UptimePercentage
+	}
+	if server.ErrorRate != nil {
+		metrics.ErrorRate = *server.ErrorRate
+	}
+
+	return scoreHealth(metrics)
+}
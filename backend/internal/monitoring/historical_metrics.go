@@ -0,0 +1,183 @@
+package monitoring
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// MaxHistoryWindow is the longest window GetHistoricalMetrics covers
+const MaxHistoryWindow = 7 * 24 * time.Hour
+
+// ErrInvalidHistoryWindow is returned for a window that is not positive or exceeds MaxHistoryWindow
+var ErrInvalidHistoryWindow = errors.New("invalid history window")
+
+// HistoricalMetrics summarizes a server's recorded health checks over a window
+type HistoricalMetrics struct {
+	ServerID   uuid.UUID `json:"server_id"`
+	Window     string    `json:"window"`
+	BucketSize string    `json:"bucket_size"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	Checks     int64     `json:"checks"`
+
+	AvgResponseTimeMs float64 `json:"avg_response_time_ms"`
+	P50ResponseTimeMs float64 `json:"p50_response_time_ms"`
+	P95ResponseTimeMs float64 `json:"p95_response_time_ms"`
+	P99ResponseTimeMs float64 `json:"p99_response_time_ms"`
+
+	// UptimePercent is the percentage of checks that found the server online
+	UptimePercent float64 `json:"uptime_percent"`
+	// ErrorRate is the fraction of checks that recorded an error, from 0 to 1
+	ErrorRate float64 `json:"error_rate"`
+
+	// Series holds one point per bucket with checks, oldest first
+	Series []TimePoint `json:"series"`
+}
+
+// TimePoint aggregates the health checks in one bucket of a HistoricalMetrics series
+type TimePoint struct {
+	Timestamp         time.Time `json:"timestamp"`
+	Checks            int64     `json:"checks"`
+	AvgResponseTimeMs float64   `json:"avg_response_time_ms"`
+	UptimePercent     float64   `json:"uptime_percent"`
+	ErrorRate         float64   `json:"error_rate"`
+}
+
+// HistoryBucketSize returns the series bucket size for a window: one minute up to an
+// hour, 15 minutes up to a day, and an hour beyond that
+func HistoryBucketSize(window time.Duration) time.Duration {
+	switch {
+	case window <= time.Hour:
+		return time.Minute
+	case window <= 24*time.Hour:
+		return 15 * time.Minute
+	default:
+		return time.Hour
+	}
+}
+
+// ParseHistoryWindow parses a window such as "1h", "24h" or "7d". Besides the units
+// accepted by time.ParseDuration, a whole number of days may be given with "d".
+func ParseHistoryWindow(s string) (time.Duration, error) {
+	var window time.Duration
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, ErrInvalidHistoryWindow
+		}
+		window = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, ErrInvalidHistoryWindow
+		}
+		window = d
+	}
+
+	if window <= 0 || window > MaxHistoryWindow {
+		return 0, ErrInvalidHistoryWindow
+	}
+	return window, nil
+}
+
+// GetHistoricalMetrics computes response time percentiles, uptime and error rate from
+// the server's status history over the window ending now, along with a time series
+// bucketed by HistoryBucketSize. sql.ErrNoRows is returned if the server does not exist
+// or belongs to another organization.
+func (m *MCPMonitor) GetHistoricalMetrics(ctx context.Context, organizationID, serverID uuid.UUID, window time.Duration) (*HistoricalMetrics, error) {
+	if window <= 0 || window > MaxHistoryWindow {
+		return nil, ErrInvalidHistoryWindow
+	}
+
+	var exists bool
+	err := m.db.QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM mcp_servers WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL)",
+		serverID, organizationID).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check server: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("failed to get server history: %w", sql.ErrNoRows)
+	}
+
+	end := time.Now()
+	start := end.Add(-window)
+	bucketSize := HistoryBucketSize(window)
+
+	metrics := &HistoricalMetrics{
+		ServerID:   serverID,
+		Window:     window.String(),
+		BucketSize: bucketSize.String(),
+		Start:      start,
+		End:        end,
+		Series:     []TimePoint{},
+	}
+
+	summaryQuery := `
+		SELECT
+			COUNT(*),
+			COALESCE(AVG(response_time_ms), 0),
+			percentile_cont(ARRAY[0.5, 0.95, 0.99]) WITHIN GROUP (ORDER BY response_time_ms),
+			COALESCE(AVG(CASE WHEN status = 'online' THEN 1.0 ELSE 0.0 END) * 100, 0),
+			COALESCE(AVG(CASE WHEN error_message IS NOT NULL THEN 1.0 ELSE 0.0 END), 0)
+		FROM server_status_history
+		WHERE server_id = $1 AND checked_at >= $2 AND checked_at < $3
+	`
+
+	var percentiles pq.Float64Array
+	err = m.db.QueryRowContext(ctx, summaryQuery, serverID, start, end).Scan(
+		&metrics.Checks,
+		&metrics.AvgResponseTimeMs,
+		&percentiles,
+		&metrics.UptimePercent,
+		&metrics.ErrorRate,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize server status history: %w", err)
+	}
+	if len(percentiles) == 3 {
+		metrics.P50ResponseTimeMs = percentiles[0]
+		metrics.P95ResponseTimeMs = percentiles[1]
+		metrics.P99ResponseTimeMs = percentiles[2]
+	}
+
+	seriesQuery := `
+		SELECT
+			to_timestamp(floor(extract(epoch FROM checked_at) / $4) * $4) AS bucket,
+			COUNT(*),
+			COALESCE(AVG(response_time_ms), 0),
+			AVG(CASE WHEN status = 'online' THEN 1.0 ELSE 0.0 END) * 100,
+			AVG(CASE WHEN error_message IS NOT NULL THEN 1.0 ELSE 0.0 END)
+		FROM server_status_history
+		WHERE server_id = $1 AND checked_at >= $2 AND checked_at < $3
+		GROUP BY bucket
+		ORDER BY bucket ASC
+	`
+
+	rows, err := m.db.QueryContext(ctx, seriesQuery, serverID, start, end, bucketSize.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query server status series: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var point TimePoint
+		if err := rows.Scan(&point.Timestamp, &point.Checks, &point.AvgResponseTimeMs, &point.UptimePercent, &point.ErrorRate); err != nil {
+			return nil, fmt.Errorf("failed to scan server status series: %w", err)
+		}
+		metrics.Series = append(metrics.Series, point)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read server status series: %w", err)
+	}
+
+	return metrics, nil
+}
@@ -4,12 +4,17 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/radhi1991/aran-mcp-sentinel/internal/mcp"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/metrics"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/middleware"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/registry"
 	"go.uber.org/zap"
 )
 
@@ -20,20 +25,83 @@ type MCPMonitor struct {
 	protocol *mcp.MCPProtocol
 	monitors map[string]*ServerMonitor
 	mu       sync.RWMutex
+
+	breakerConfig CircuitBreakerConfig
+	metrics       *metrics.Registry
+	shutdown      *middleware.ShutdownCoordinator
+
+	// Repeats of an unresolved alert within dedupWindow are coalesced into it
+	dedupWindow time.Duration
+
+	// healthScores, if set, receives the health score of every check
+	healthScores *HealthScoreBuffer
+
+	// scoreCache, if set, is kept up to date with the health score of every check
+	scoreCache *registry.HealthScoreCache
 }
 
+// DefaultAlertDedupWindow is how long repeats of an unresolved alert are coalesced
+const DefaultAlertDedupWindow = 5 * time.Minute
+
 // ServerMonitor tracks monitoring state for a single server
 type ServerMonitor struct {
-	ServerID     uuid.UUID
-	URL          string
-	Name         string
-	Status       string
-	LastCheck    time.Time
-	ResponseTime time.Duration
-	ErrorCount   int
-	UptimeStart  time.Time
-	Metrics      *ServerMetrics
-	cancel       context.CancelFunc
+	ServerID             uuid.UUID
+	URL                  string
+	Name                 string
+	Status               string
+	LastCheck            time.Time
+	LastSuccessfulInitAt *time.Time
+	ResponseTime         time.Duration
+	ErrorCount           int
+	UptimeStart          time.Time
+	Metrics              *ServerMetrics
+	HealthCheck          mcp.HealthCheckConfig
+	cancel               context.CancelFunc
+
+	// Breaker stops checks of a server that has been down for a while
+	Breaker *CircuitBreaker
+
+	// Checks of a failing server are skipped until nextCheck
+	backoff             BackoffPolicy
+	consecutiveFailures int
+	nextCheck           time.Time
+}
+
+// Server display statuses for the health dashboard
+const (
+	DisplayStatusOnline       = "online"
+	DisplayStatusDegraded     = "degraded"     // initialized successfully before, offline now
+	DisplayStatusUnconfigured = "unconfigured" // never initialized successfully
+)
+
+// displayStatusColors maps display statuses to the dashboard colors
+var displayStatusColors = map[string]string{
+	DisplayStatusOnline:       "green",
+	DisplayStatusDegraded:     "orange",
+	DisplayStatusUnconfigured: "grey",
+}
+
+// ServerDisplayStatus tells apart an offline server that used to work, which suggests a
+// regression, from one that has never initialized, which is likely misconfigured
+func ServerDisplayStatus(status string, lastSuccessfulInitAt *time.Time) string {
+	switch {
+	case status == "online":
+		return DisplayStatusOnline
+	case lastSuccessfulInitAt == nil:
+		return DisplayStatusUnconfigured
+	case status == "offline" || status == "error":
+		return DisplayStatusDegraded
+	default:
+		return status
+	}
+}
+
+// DisplayStatusColor returns the dashboard color for a display status
+func DisplayStatusColor(displayStatus string) string {
+	if color, ok := displayStatusColors[displayStatus]; ok {
+		return color
+	}
+	return "grey"
 }
 
 // ServerMetrics holds detailed metrics for a server
@@ -51,13 +119,14 @@ type ServerMetrics struct {
 
 // HealthCheckResult represents the result of a health check
 type HealthCheckResult struct {
-	ServerID     uuid.UUID     `json:"server_id"`
-	URL          string        `json:"url"`
-	Status       string        `json:"status"`
-	ResponseTime time.Duration `json:"response_time"`
-	Error        string        `json:"error,omitempty"`
-	Timestamp    time.Time     `json:"timestamp"`
-	Details      interface{}   `json:"details,omitempty"`
+	ServerID       uuid.UUID     `json:"server_id"`
+	URL            string        `json:"url"`
+	Status         string        `json:"status"`
+	ResponseTime   time.Duration `json:"response_time"`
+	NetworkLatency time.Duration `json:"network_latency,omitempty"`
+	Error          string        `json:"error,omitempty"`
+	Timestamp      time.Time     `json:"timestamp"`
+	Details        interface{}   `json:"details,omitempty"`
 }
 
 // AlertLevel represents the severity of an alert
@@ -71,27 +140,60 @@ const (
 
 // Alert represents a monitoring alert
 type Alert struct {
-	ID        uuid.UUID  `json:"id"`
-	ServerID  uuid.UUID  `json:"server_id"`
-	Level     AlertLevel `json:"level"`
-	Message   string     `json:"message"`
-	Details   string     `json:"details"`
-	Timestamp time.Time  `json:"timestamp"`
-	Resolved  bool       `json:"resolved"`
+	ID          uuid.UUID  `json:"id"`
+	ServerID    uuid.UUID  `json:"server_id"`
+	ExecutionID *uuid.UUID `json:"execution_id,omitempty"`
+	Level       AlertLevel `json:"level"`
+	Message     string     `json:"message"`
+	Details     string     `json:"details"`
+	Timestamp   time.Time  `json:"timestamp"`
+	Resolved    bool       `json:"resolved"`
+	Occurrences int        `json:"occurrences"`
 }
 
-// NewMCPMonitor creates a new MCP monitor
-func NewMCPMonitor(db *sql.DB, logger *zap.Logger) *MCPMonitor {
+// NewMCPMonitor creates a new MCP monitor. Each monitored server gets a circuit
+// breaker configured by breaker.
+func NewMCPMonitor(db *sql.DB, logger *zap.Logger, breaker CircuitBreakerConfig) *MCPMonitor {
 	return &MCPMonitor{
 		db:       db,
 		logger:   logger,
 		protocol: mcp.NewMCPProtocol(logger),
 		monitors: make(map[string]*ServerMonitor),
+
+		breakerConfig: breaker,
+		dedupWindow:   DefaultAlertDedupWindow,
 	}
 }
 
-// StartMonitoring begins monitoring an MCP server
-func (m *MCPMonitor) StartMonitoring(serverID uuid.UUID, url, name string, interval time.Duration) error {
+// SetHealthScoreCache keeps cache up to date with the health score of every check
+func (m *MCPMonitor) SetHealthScoreCache(cache *registry.HealthScoreCache) {
+	m.scoreCache = cache
+}
+
+// SetHealthScoreBuffer records the health score of every check in buffer
+func (m *MCPMonitor) SetHealthScoreBuffer(buffer *HealthScoreBuffer) {
+	m.healthScores = buffer
+}
+
+// SetAlertDedupWindow sets how long repeats of an unresolved alert are coalesced into
+// it; 0 stores every alert
+func (m *MCPMonitor) SetAlertDedupWindow(window time.Duration) {
+	m.dedupWindow = window
+}
+
+// SetShutdownCoordinator lets in-flight health check probes finish during shutdown. It
+// must be called before monitoring starts.
+func (m *MCPMonitor) SetShutdownCoordinator(shutdown *middleware.ShutdownCoordinator) {
+	m.shutdown = shutdown
+}
+
+// StartMonitoring begins monitoring an MCP server. While the server keeps failing health
+// checks, they are spaced out according to backoff.
+func (m *MCPMonitor) StartMonitoring(serverID uuid.UUID, url, name string, interval time.Duration, healthCheck mcp.HealthCheckConfig, backoff BackoffPolicy) error {
+	if err := m.configureServerTLS(serverID, url); err != nil {
+		return err
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -100,8 +202,12 @@ func (m *MCPMonitor) StartMonitoring(serverID uuid.UUID, url, name string, inter
 		existing.cancel()
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	
+	root := context.Background()
+	if m.shutdown != nil {
+		root = m.shutdown.Context()
+	}
+	ctx, cancel := context.WithCancel(root)
+
 	monitor := &ServerMonitor{
 		ServerID:    serverID,
 		URL:         url,
@@ -109,10 +215,14 @@ func (m *MCPMonitor) StartMonitoring(serverID uuid.UUID, url, name string, inter
 		Status:      "unknown",
 		UptimeStart: time.Now(),
 		Metrics:     &ServerMetrics{},
+		HealthCheck: healthCheck,
+		Breaker:     NewCircuitBreaker(m.breakerConfig),
 		cancel:      cancel,
+		backoff:     backoff,
 	}
 
 	m.monitors[url] = monitor
+	m.recordMonitoredServers()
 
 	// Start monitoring goroutine
 	go m.monitorServer(ctx, monitor, interval)
@@ -127,6 +237,25 @@ func (m *MCPMonitor) StartMonitoring(serverID uuid.UUID, url, name string, inter
 	return nil
 }
 
+// configureServerTLS applies the mutual TLS files set in a server's metadata
+func (m *MCPMonitor) configureServerTLS(serverID uuid.UUID, url string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	files, err := mcp.LoadServerTLSFiles(ctx, m.db, serverID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := m.protocol.ConfigureServerTLS(url, files); err != nil {
+		return fmt.Errorf("failed to configure TLS for MCP server: %w", err)
+	}
+	return nil
+}
+
 // StopMonitoring stops monitoring an MCP server
 func (m *MCPMonitor) StopMonitoring(url string) {
 	m.mu.Lock()
@@ -135,6 +264,10 @@ func (m *MCPMonitor) StopMonitoring(url string) {
 	if monitor, exists := m.monitors[url]; exists {
 		monitor.cancel()
 		delete(m.monitors, url)
+		m.recordMonitoredServers()
+		if m.metrics != nil {
+			m.metrics.RemoveServer(monitor.ServerID.String())
+		}
 		
 		m.logger.Info("Stopped monitoring MCP server",
 			zap.String("url", url),
@@ -177,6 +310,12 @@ func (m *MCPMonitor) monitorServer(ctx context.Context, monitor *ServerMonitor,
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
+			if time.Now().Before(monitor.nextCheck) {
+				continue // backing off a failing server
+			}
+			if !monitor.Breaker.Allow(time.Now()) {
+				continue // circuit open; the server has been down for a while
+			}
 			m.performHealthCheck(ctx, monitor)
 		}
 	}
@@ -184,6 +323,13 @@ func (m *MCPMonitor) monitorServer(ctx context.Context, monitor *ServerMonitor,
 
 // performHealthCheck executes a comprehensive health check
 func (m *MCPMonitor) performHealthCheck(ctx context.Context, monitor *ServerMonitor) {
+	if m.shutdown != nil {
+		if !m.shutdown.Begin() {
+			return // shutting down; do not start new probes
+		}
+		defer m.shutdown.Done()
+	}
+
 	checkCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
@@ -197,8 +343,8 @@ func (m *MCPMonitor) performHealthCheck(ctx context.Context, monitor *ServerMoni
 	// Update request count
 	monitor.Metrics.TotalRequests++
 
-	// Perform MCP ping
-	err := m.protocol.Ping(checkCtx, monitor.URL)
+	// Perform the configured health check (JSON-RPC ping by default)
+	err := m.protocol.HealthCheck(checkCtx, monitor.URL, monitor.HealthCheck)
 	responseTime := time.Since(start)
 	
 	if err != nil {
@@ -207,6 +353,8 @@ func (m *MCPMonitor) performHealthCheck(ctx context.Context, monitor *ServerMoni
 		monitor.ErrorCount++
 		monitor.Metrics.FailedRequests++
 		monitor.Metrics.LastError = err.Error()
+		monitor.consecutiveFailures++
+		monitor.nextCheck = time.Now().Add(monitor.backoff.Delay(monitor.consecutiveFailures))
 		
 		result.Status = "offline"
 		result.Error = err.Error()
@@ -215,15 +363,29 @@ func (m *MCPMonitor) performHealthCheck(ctx context.Context, monitor *ServerMoni
 			zap.String("url", monitor.URL),
 			zap.Error(err),
 			zap.Duration("response_time", responseTime),
+			zap.Int("consecutive_failures", monitor.consecutiveFailures),
 		)
 
 		// Generate alert for server down
 		m.generateAlert(monitor, AlertLevelCritical, "Server offline", err.Error())
+
+		if monitor.Breaker.RecordFailure(time.Now()) {
+			m.logger.Warn("Circuit breaker opened; pausing health checks",
+				zap.String("url", monitor.URL),
+				zap.Duration("open_duration", m.breakerConfig.OpenDuration),
+			)
+			m.generateAlert(monitor, AlertLevelCritical, "Circuit breaker opened",
+				fmt.Sprintf("Health checks paused for %s after %d consecutive failures",
+					m.breakerConfig.OpenDuration, monitor.consecutiveFailures))
+		}
 	} else {
 		// Server is responsive
 		previousStatus := monitor.Status
 		monitor.Status = "online"
 		monitor.ErrorCount = 0
+		monitor.consecutiveFailures = 0
+		monitor.nextCheck = time.Time{}
+		monitor.Breaker.RecordSuccess()
 		monitor.Metrics.SuccessfulReqs++
 		
 		result.Status = "online"
@@ -242,13 +404,18 @@ func (m *MCPMonitor) performHealthCheck(ctx context.Context, monitor *ServerMoni
 		}
 	}
 
+	// Measure raw TCP latency to tell a slow network from a slow server
+	if latency, err := probeServerURL(monitor.URL, tcpProbeTimeout); err == nil {
+		result.NetworkLatency = latency
+	}
+
 	// Update metrics
 	monitor.LastCheck = time.Now()
 	monitor.ResponseTime = responseTime
 	result.ResponseTime = responseTime
+	m.recordHealthCheck(monitor, result.Status, responseTime)
 
 	// Calculate uptime percentage
-	totalTime := time.Since(monitor.UptimeStart)
 	if monitor.Metrics.TotalRequests > 0 {
 		monitor.Metrics.UptimePercentage = float64(monitor.Metrics.SuccessfulReqs) / float64(monitor.Metrics.TotalRequests) * 100
 	}
@@ -262,6 +429,20 @@ func (m *MCPMonitor) performHealthCheck(ctx context.Context, monitor *ServerMoni
 	// Store result in database
 	m.storeHealthCheckResult(result)
 
+	if m.healthScores != nil || m.scoreCache != nil {
+		score := healthScore(monitor, result)
+		if m.healthScores != nil {
+			m.healthScores.Push(HealthScorePoint{
+				ServerID:  monitor.ServerID,
+				Score:     score,
+				Timestamp: result.Timestamp,
+			})
+		}
+		if m.scoreCache != nil {
+			m.scoreCache.Set(monitor.ServerID, score)
+		}
+	}
+
 	// Check for performance alerts
 	m.checkPerformanceAlerts(monitor)
 }
@@ -278,6 +459,10 @@ func (m *MCPMonitor) performDetailedCheck(ctx context.Context, monitor *ServerMo
 
 	details["server_info"] = serverInfo
 
+	initializedAt := time.Now()
+	monitor.LastSuccessfulInitAt = &initializedAt
+	m.storeSuccessfulInit(monitor.ServerID, initializedAt)
+
 	// Check tools
 	if serverInfo.Capabilities.Tools != nil {
 		tools, err := m.protocol.ListTools(ctx, monitor.URL)
@@ -341,20 +526,40 @@ func (m *MCPMonitor) checkPerformanceAlerts(monitor *ServerMonitor) {
 // generateAlert creates and stores an alert
 func (m *MCPMonitor) generateAlert(monitor *ServerMonitor, level AlertLevel, message, details string) {
 	alert := &Alert{
-		ID:        uuid.New(),
-		ServerID:  monitor.ServerID,
-		Level:     level,
-		Message:   message,
-		Details:   details,
-		Timestamp: time.Now(),
-		Resolved:  false,
+		ID:          uuid.New(),
+		ServerID:    monitor.ServerID,
+		Level:       level,
+		Message:     message,
+		Details:     details,
+		Timestamp:   time.Now(),
+		Resolved:    false,
+		Occurrences: 1,
 	}
 
-	err := m.storeAlert(alert)
+	m.recordAlert(level)
+
+	coalesced, err := m.coalesceAlert(alert)
+	if err != nil {
+		m.logger.Error("Failed to coalesce alert", zap.Error(err))
+	}
+	if coalesced {
+		m.logger.Debug("Coalesced repeated alert",
+			zap.String("server_id", monitor.ServerID.String()),
+			zap.String("alert_id", alert.ID.String()),
+			zap.Int("occurrences", alert.Occurrences),
+		)
+		return
+	}
+
+	err = m.storeAlert(alert)
 	if err != nil {
 		m.logger.Error("Failed to store alert", zap.Error(err))
 	}
 
+	if !DefaultAlertHub().Publish(alert) {
+		m.logger.Warn("Alert stream buffer full, alert not streamed", zap.String("alert_id", alert.ID.String()))
+	}
+
 	m.logger.Info("Generated alert",
 		zap.String("server_id", monitor.ServerID.String()),
 		zap.String("level", string(level)),
@@ -362,11 +567,35 @@ func (m *MCPMonitor) generateAlert(monitor *ServerMonitor, level AlertLevel, mes
 	)
 }
 
+// AlertOWASPScore raises a warning that a server's average OWASP MCP Top 10 score is
+// below the threshold
+func (m *MCPMonitor) AlertOWASPScore(serverID uuid.UUID, score, threshold int) {
+	m.generateAlert(&ServerMonitor{ServerID: serverID}, AlertLevelWarning, "Low OWASP MCP Top 10 score",
+		fmt.Sprintf("Average score %d is below the threshold of %d", score, threshold))
+}
+
+// AlertCapabilityChange raises a warning that a server's tools no longer match the tools
+// stored for it
+func (m *MCPMonitor) AlertCapabilityChange(serverID uuid.UUID, added, removed, modified []string) {
+	var changes []string
+	if len(added) > 0 {
+		changes = append(changes, fmt.Sprintf("added: %s", strings.Join(added, ", ")))
+	}
+	if len(removed) > 0 {
+		changes = append(changes, fmt.Sprintf("removed: %s", strings.Join(removed, ", ")))
+	}
+	if len(modified) > 0 {
+		changes = append(changes, fmt.Sprintf("input schema changed: %s", strings.Join(modified, ", ")))
+	}
+	m.generateAlert(&ServerMonitor{ServerID: serverID}, AlertLevelWarning, "Server tools changed",
+		fmt.Sprintf("Tools %s", strings.Join(changes, "; ")))
+}
+
 // storeHealthCheckResult stores a health check result in the database
 func (m *MCPMonitor) storeHealthCheckResult(result *HealthCheckResult) error {
 	query := `
-		INSERT INTO server_status_history (server_id, status, response_time_ms, error_message, checked_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO server_status_history (server_id, status, response_time_ms, tcp_latency_ms, error_message, checked_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
 	`
 
 	var responseTimeMs *int
@@ -375,12 +604,18 @@ func (m *MCPMonitor) storeHealthCheckResult(result *HealthCheckResult) error {
 		responseTimeMs = &ms
 	}
 
+	var tcpLatencyMs *int
+	if result.NetworkLatency > 0 {
+		ms := int(result.NetworkLatency.Milliseconds())
+		tcpLatencyMs = &ms
+	}
+
 	var errorMsg *string
 	if result.Error != "" {
 		errorMsg = &result.Error
 	}
 
-	_, err := m.db.Exec(query, result.ServerID, result.Status, responseTimeMs, errorMsg, result.Timestamp)
+	_, err := m.db.Exec(query, result.ServerID, result.Status, responseTimeMs, tcpLatencyMs, errorMsg, result.Timestamp)
 	if err != nil {
 		m.logger.Error("Failed to store health check result", zap.Error(err))
 		return err
@@ -389,11 +624,11 @@ func (m *MCPMonitor) storeHealthCheckResult(result *HealthCheckResult) error {
 	// Update server status in main table
 	updateQuery := `
 		UPDATE mcp_servers 
-		SET status = $1, last_checked_at = $2, response_time_ms = $3
-		WHERE id = $4
+		SET status = $1, last_checked_at = $2, response_time_ms = $3, tcp_latency_ms = $4
+		WHERE id = $5
 	`
 
-	_, err = m.db.Exec(updateQuery, result.Status, result.Timestamp, responseTimeMs, result.ServerID)
+	_, err = m.db.Exec(updateQuery, result.Status, result.Timestamp, responseTimeMs, tcpLatencyMs, result.ServerID)
 	if err != nil {
 		m.logger.Error("Failed to update server status", zap.Error(err))
 	}
@@ -401,11 +636,57 @@ func (m *MCPMonitor) storeHealthCheckResult(result *HealthCheckResult) error {
 	return err
 }
 
+// storeSuccessfulInit records when a server last initialized successfully
+func (m *MCPMonitor) storeSuccessfulInit(serverID uuid.UUID, initializedAt time.Time) {
+	query := `UPDATE mcp_servers SET last_successful_init_at = $1 WHERE id = $2`
+
+	if _, err := m.db.Exec(query, initializedAt, serverID); err != nil {
+		m.logger.Error("Failed to record successful server initialization", zap.Error(err))
+	}
+}
+
+// coalesceAlert counts alert as a repeat of an unresolved monitoring alert of the same
+// server, level and message raised within the dedup window. If there is one, alert takes
+// its ID and occurrence count and true is returned.
+func (m *MCPMonitor) coalesceAlert(alert *Alert) (bool, error) {
+	if m.dedupWindow <= 0 {
+		return false, nil
+	}
+
+	query := `
+		UPDATE alerts SET occurrences = occurrences + 1, updated_at = $1
+		WHERE id = (
+			SELECT id FROM alerts
+			WHERE server_id = $2 AND type = 'monitoring' AND severity = $3 AND title = $4
+			  AND resolved_at IS NULL AND created_at >= $5
+			ORDER BY created_at DESC
+			LIMIT 1
+		)
+		RETURNING id, occurrences
+	`
+
+	err := m.db.QueryRow(query,
+		alert.Timestamp,
+		alert.ServerID,
+		string(alert.Level),
+		alert.Message,
+		alert.Timestamp.Add(-m.dedupWindow),
+	).Scan(&alert.ID, &alert.Occurrences)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
 // storeAlert stores an alert in the database
 func (m *MCPMonitor) storeAlert(alert *Alert) error {
 	query := `
-		INSERT INTO alerts (id, server_id, type, severity, title, message, metadata, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO alerts (id, server_id, tool_execution_id, type, severity, title, message, metadata, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
 
 	metadata := map[string]interface{}{
@@ -416,6 +697,7 @@ func (m *MCPMonitor) storeAlert(alert *Alert) error {
 	_, err := m.db.Exec(query, 
 		alert.ID, 
 		alert.ServerID, 
+		alert.ExecutionID,
 		"monitoring", 
 		string(alert.Level), 
 		alert.Message, 
@@ -437,39 +719,115 @@ func (m *MCPMonitor) GetServerMetrics(url string) (*ServerMetrics, error) {
 	return monitor.Metrics, nil
 }
 
+// AlertQuery holds filters and pagination for querying alerts
+type AlertQuery struct {
+	// OrganizationID limits the alerts to one organization. Nil matches every organization.
+	OrganizationID *uuid.UUID
+	ServerID       *uuid.UUID
+	Level    *AlertLevel
+	Resolved *bool
+	Since    *time.Time
+	Until    *time.Time
+	Limit    int
+	Offset   int
+}
+
 // GetRecentAlerts returns recent alerts for all servers
 func (m *MCPMonitor) GetRecentAlerts(limit int) ([]*Alert, error) {
-	query := `
-		SELECT id, server_id, severity, title, message, created_at, resolved_at IS NOT NULL as resolved
-		FROM alerts 
-		WHERE type = 'monitoring'
-		ORDER BY created_at DESC 
-		LIMIT $1
-	`
+	alerts, _, err := m.QueryAlerts(AlertQuery{Limit: limit})
+	return alerts, err
+}
+
+// QueryAlerts returns a page of monitoring alerts matching the query and the total number of matches
+func (m *MCPMonitor) QueryAlerts(q AlertQuery) ([]*Alert, int, error) {
+	where := " WHERE type = 'monitoring'"
+	args := []interface{}{}
+	argCount := 0
+
+	if q.OrganizationID != nil {
+		argCount++
+		where += fmt.Sprintf(" AND organization_id = $%d", argCount)
+		args = append(args, *q.OrganizationID)
+	}
+
+	if q.ServerID != nil {
+		argCount++
+		where += fmt.Sprintf(" AND server_id = $%d", argCount)
+		args = append(args, *q.ServerID)
+	}
+
+	if q.Level != nil {
+		argCount++
+		where += fmt.Sprintf(" AND severity = $%d", argCount)
+		args = append(args, string(*q.Level))
+	}
+
+	if q.Resolved != nil {
+		argCount++
+		where += fmt.Sprintf(" AND (resolved_at IS NOT NULL) = $%d", argCount)
+		args = append(args, *q.Resolved)
+	}
+
+	if q.Since != nil {
+		argCount++
+		where += fmt.Sprintf(" AND created_at >= $%d", argCount)
+		args = append(args, *q.Since)
+	}
+
+	if q.Until != nil {
+		argCount++
+		where += fmt.Sprintf(" AND created_at < $%d", argCount)
+		args = append(args, *q.Until)
+	}
 
-	rows, err := m.db.Query(query, limit)
+	var total int
+	if err := m.db.QueryRow("SELECT COUNT(*) FROM alerts"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	query := `
+		SELECT id, server_id, tool_execution_id, severity, title, message, created_at, resolved_at IS NOT NULL as resolved,
+		       occurrences
+		FROM alerts` + where + fmt.Sprintf(`
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, argCount+1, argCount+2)
+	args = append(args, limit, q.Offset)
+
+	rows, err := m.db.Query(query, args...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
 	var alerts []*Alert
 	for rows.Next() {
 		alert := &Alert{}
+		var executionID uuid.NullUUID
 		err := rows.Scan(
 			&alert.ID,
 			&alert.ServerID,
+			&executionID,
 			&alert.Level,
 			&alert.Message,
 			&alert.Details,
 			&alert.Timestamp,
 			&alert.Resolved,
+			&alert.Occurrences,
 		)
 		if err != nil {
 			continue
 		}
+		if executionID.Valid {
+			alert.ExecutionID = &executionID.UUID
+		}
 		alerts = append(alerts, alert)
 	}
 
-	return alerts, nil
-}
\ No newline at end of file
+	return alerts, total, nil
+}
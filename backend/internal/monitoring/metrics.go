@@ -0,0 +1,37 @@
+package monitoring
+
+import (
+	"time"
+
+	"github.com/radhi1991/aran-mcp-sentinel/internal/metrics"
+)
+
+// SetMetrics sets the registry the monitor reports server status, health check durations
+// and alerts to. It must be called before monitoring starts; a nil registry disables them.
+func (m *MCPMonitor) SetMetrics(registry *metrics.Registry) {
+	m.metrics = registry
+}
+
+// recordHealthCheck reports the outcome of a health check of a monitored server
+func (m *MCPMonitor) recordHealthCheck(monitor *ServerMonitor, status string, duration time.Duration) {
+	if m.metrics == nil {
+		return
+	}
+	serverID := monitor.ServerID.String()
+	m.metrics.SetServerStatus(serverID, status == "online")
+	m.metrics.ObserveHealthCheck(serverID, status, duration)
+}
+
+// recordAlert counts a generated alert
+func (m *MCPMonitor) recordAlert(level AlertLevel) {
+	if m.metrics != nil {
+		m.metrics.IncAlert(string(level))
+	}
+}
+
+// recordMonitoredServers reports the number of monitored servers. The caller must hold m.mu.
+func (m *MCPMonitor) recordMonitoredServers() {
+	if m.metrics != nil {
+		m.metrics.SetMonitoredServers(len(m.monitors))
+	}
+}
@@ -0,0 +1,68 @@
+package monitoring
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/metrics"
+	"go.uber.org/zap"
+)
+
+func TestMCPMonitorReportsMetrics(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMCPMonitor(nil, zap.NewNop(), CircuitBreakerConfig{})
+	m.SetMetrics(registry)
+
+	serverID := uuid.New()
+	monitor := &ServerMonitor{ServerID: serverID, URL: "http://mcp.invalid", cancel: func() {}}
+	m.mu.Lock()
+	m.monitors[monitor.URL] = monitor
+	m.recordMonitoredServers()
+	m.mu.Unlock()
+
+	m.recordHealthCheck(monitor, "online", 30*time.Millisecond)
+	m.recordHealthCheck(monitor, "offline", 2*time.Second)
+	m.recordAlert(AlertLevelCritical)
+
+	scrape := func() string {
+		rec := httptest.NewRecorder()
+		registry.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+		return rec.Body.String()
+	}
+
+	body := scrape()
+	for _, want := range []string{
+		`server_status{server_id="` + serverID.String() + `"} 0`,
+		`health_check_duration_ms_count{server_id="` + serverID.String() + `",status="online"} 1`,
+		`health_check_duration_ms_count{server_id="` + serverID.String() + `",status="offline"} 1`,
+		`alerts_total{level="` + string(AlertLevelCritical) + `"} 1`,
+		"monitored_servers_total 1",
+	} {
+		if !strings.Contains(body, want+"\n") {
+			t.Errorf("metrics missing %q", want)
+		}
+	}
+
+	m.StopMonitoring(monitor.URL)
+
+	body = scrape()
+	if strings.Contains(body, `server_status{server_id="`+serverID.String()+`"}`) {
+		t.Error("status of a server no longer monitored is still reported")
+	}
+	if !strings.Contains(body, "monitored_servers_total 0\n") {
+		t.Error("metrics missing monitored_servers_total 0 after StopMonitoring")
+	}
+}
+
+func TestMCPMonitorWithoutMetrics(t *testing.T) {
+	m := NewMCPMonitor(nil, zap.NewNop(), CircuitBreakerConfig{})
+	monitor := &ServerMonitor{ServerID: uuid.New(), URL: "http://mcp.invalid", cancel: func() {}}
+
+	// Without a registry the hooks do nothing
+	m.recordHealthCheck(monitor, "online", time.Millisecond)
+	m.recordAlert(AlertLevelWarning)
+	m.recordMonitoredServers()
+}
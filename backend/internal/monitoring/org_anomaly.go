@@ -0,0 +1,253 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"go.uber.org/zap"
+)
+
+// Organization anomaly detection thresholds
+const (
+	orgAnomalyInterval       = 15 * time.Minute
+	orgAnomalyBaselinePeriod = 7 * 24 * time.Hour
+	orgAnomalyZScore         = 3.0
+	orgAnomalySpikeRatio     = 10.0
+	orgAnomalyQueryTimeout   = 30 * time.Second
+)
+
+// ToolRateAnomaly describes a tool whose execution rate departs from its baseline
+type ToolRateAnomaly struct {
+	ToolID         uuid.UUID `json:"tool_id"`
+	ToolName       string    `json:"tool_name"`
+	Executions     int64     `json:"executions"`
+	BaselineMean   float64   `json:"baseline_mean"`
+	BaselineStdDev float64   `json:"baseline_stddev"`
+	ZScore         float64   `json:"z_score"`
+	SpikeRatio     float64   `json:"spike_ratio"`
+	Spike          bool      `json:"spike"`
+}
+
+// OrgAnomalyReport is the result of analyzing an organization's tool usage over one window
+type OrgAnomalyReport struct {
+	OrganizationID uuid.UUID          `json:"organization_id"`
+	Window         time.Duration      `json:"window"`
+	GeneratedAt    time.Time          `json:"generated_at"`
+	ToolsAnalyzed  int                `json:"tools_analyzed"`
+	Anomalies      []*ToolRateAnomaly `json:"anomalies"`
+}
+
+// HasSpike reports whether any tool's rate spiked more than tenfold
+func (r *OrgAnomalyReport) HasSpike() bool {
+	for _, anomaly := range r.Anomalies {
+		if anomaly.Spike {
+			return true
+		}
+	}
+	return false
+}
+
+// OrgAnomalyDetector finds tool usage anomalies across all agents of an organization,
+// which per-agent behavioral analysis misses when a compromised key spreads its calls
+// over many agents
+type OrgAnomalyDetector struct {
+	repo   *database.Repository
+	logger *zap.Logger
+}
+
+// NewOrgAnomalyDetector creates an organization anomaly detector
+func NewOrgAnomalyDetector(repo *database.Repository, logger *zap.Logger) *OrgAnomalyDetector {
+	return &OrgAnomalyDetector{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Analyze compares each tool's executions in the organization over the last window with
+// its executions in windows of the same length over the previous week. Tools with a
+// z-score above 3 are anomalous, and tools running more than 10 times their baseline rate
+// are spikes. It returns nil if the executions cannot be loaded.
+func (d *OrgAnomalyDetector) Analyze(orgID uuid.UUID, window time.Duration) *OrgAnomalyReport {
+	ctx, cancel := context.WithTimeout(context.Background(), orgAnomalyQueryTimeout)
+	defer cancel()
+
+	now := time.Now()
+	baselineBuckets := int(orgAnomalyBaselinePeriod / window)
+	if baselineBuckets < 1 {
+		baselineBuckets = 1
+	}
+
+	buckets, err := d.repo.ListToolExecutionBuckets(ctx, orgID, now, window, baselineBuckets+1)
+	if err != nil {
+		d.logger.Error("Failed to load tool executions for anomaly detection",
+			zap.String("organization_id", orgID.String()),
+			zap.Error(err))
+		return nil
+	}
+
+	// Bucket 0 is the current window, the rest form the baseline
+	type toolCounts struct {
+		name     string
+		current  int64
+		baseline []int64
+	}
+	tools := make(map[uuid.UUID]*toolCounts)
+	for _, bucket := range buckets {
+		counts, exists := tools[bucket.ToolID]
+		if !exists {
+			counts = &toolCounts{name: bucket.ToolName, baseline: make([]int64, baselineBuckets)}
+			tools[bucket.ToolID] = counts
+		}
+		if bucket.Bucket == 0 {
+			counts.current = bucket.Count
+		} else if bucket.Bucket <= baselineBuckets {
+			counts.baseline[bucket.Bucket-1] = bucket.Count
+		}
+	}
+
+	report := &OrgAnomalyReport{
+		OrganizationID: orgID,
+		Window:         window,
+		GeneratedAt:    now,
+		ToolsAnalyzed:  len(tools),
+		Anomalies:      []*ToolRateAnomaly{},
+	}
+
+	for toolID, counts := range tools {
+		anomaly := scoreToolRate(counts.current, counts.baseline)
+		if anomaly == nil {
+			continue
+		}
+		anomaly.ToolID = toolID
+		anomaly.ToolName = counts.name
+		report.Anomalies = append(report.Anomalies, anomaly)
+	}
+
+	sort.Slice(report.Anomalies, func(i, j int) bool {
+		return report.Anomalies[i].ZScore > report.Anomalies[j].ZScore
+	})
+
+	return report
+}
+
+// scoreToolRate compares a tool's current executions with its baseline and returns the
+// anomaly, or nil if the rate is normal. The standard deviation and the mean used for the
+// spike ratio are floored at one execution per window, so tools that were never or always
+// equally used need a real increase to be flagged.
+func scoreToolRate(current int64, baseline []int64) *ToolRateAnomaly {
+	var sum float64
+	for _, count := range baseline {
+		sum += float64(count)
+	}
+	mean := sum / float64(len(baseline))
+
+	var variance float64
+	for _, count := range baseline {
+		variance += (float64(count) - mean) * (float64(count) - mean)
+	}
+	stdDev := math.Sqrt(variance / float64(len(baseline)))
+
+	zScore := (float64(current) - mean) / math.Max(stdDev, 1)
+	spikeRatio := float64(current) / math.Max(mean, 1)
+	spike := spikeRatio > orgAnomalySpikeRatio
+
+	if zScore <= orgAnomalyZScore && !spike {
+		return nil
+	}
+
+	return &ToolRateAnomaly{
+		Executions:     current,
+		BaselineMean:   mean,
+		BaselineStdDev: stdDev,
+		ZScore:         zScore,
+		SpikeRatio:     spikeRatio,
+		Spike:          spike,
+	}
+}
+
+// Start analyzes every organization each 15 minutes until the context is cancelled
+func (d *OrgAnomalyDetector) Start(ctx context.Context) {
+	ticker := time.NewTicker(orgAnomalyInterval)
+	defer ticker.Stop()
+
+	d.logger.Info("Started organization anomaly detection", zap.Duration("interval", orgAnomalyInterval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			d.logger.Info("Stopping organization anomaly detection")
+			return
+		case <-ticker.C:
+			d.run(ctx)
+		}
+	}
+}
+
+// run analyzes the last interval of every organization and alerts on spikes
+func (d *OrgAnomalyDetector) run(ctx context.Context) {
+	orgs, err := d.repo.ListOrganizations(ctx)
+	if err != nil {
+		d.logger.Error("Failed to list organizations for anomaly detection", zap.Error(err))
+		return
+	}
+
+	for _, org := range orgs {
+		report := d.Analyze(org.ID, orgAnomalyInterval)
+		if report == nil || len(report.Anomalies) == 0 {
+			continue
+		}
+
+		for _, anomaly := range report.Anomalies {
+			d.logger.Warn("Tool usage anomaly",
+				zap.String("organization_id", org.ID.String()),
+				zap.String("tool", anomaly.ToolName),
+				zap.Int64("executions", anomaly.Executions),
+				zap.Float64("z_score", anomaly.ZScore),
+				zap.Float64("spike_ratio", anomaly.SpikeRatio))
+		}
+
+		if report.HasSpike() {
+			d.raiseSpikeAlert(ctx, report)
+		}
+	}
+}
+
+// raiseSpikeAlert creates a critical alert listing the tools whose rate spiked
+func (d *OrgAnomalyDetector) raiseSpikeAlert(ctx context.Context, report *OrgAnomalyReport) {
+	var spikes []string
+	metadata := make([]map[string]interface{}, 0, len(report.Anomalies))
+	for _, anomaly := range report.Anomalies {
+		if !anomaly.Spike {
+			continue
+		}
+		spikes = append(spikes, fmt.Sprintf("%s (%d executions, %.0fx baseline)", anomaly.ToolName, anomaly.Executions, anomaly.SpikeRatio))
+		metadata = append(metadata, map[string]interface{}{
+			"tool_id":     anomaly.ToolID,
+			"tool_name":   anomaly.ToolName,
+			"executions":  anomaly.Executions,
+			"z_score":     anomaly.ZScore,
+			"spike_ratio": anomaly.SpikeRatio,
+		})
+	}
+
+	alert := &database.Alert{
+		OrganizationID: report.OrganizationID,
+		Type:           "tool_usage_spike",
+		Severity:       string(AlertLevelCritical),
+		Title:          "Tool usage spike",
+		Message: fmt.Sprintf("Tool execution rates across the organization spiked in the last %s: %s. This may indicate a compromised API key.",
+			report.Window, strings.Join(spikes, ", ")),
+		Metadata: database.JSONB{"tools": metadata},
+	}
+	if err := d.repo.CreateAlert(ctx, alert); err != nil {
+		d.logger.Error("Failed to create tool usage spike alert",
+			zap.String("organization_id", report.OrganizationID.String()),
+			zap.Error(err))
+	}
+}
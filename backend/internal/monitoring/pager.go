@@ -0,0 +1,123 @@
+package monitoring
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+)
+
+// pagerDutyEventsURL is the PagerDuty Events API v2 endpoint
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// OnCallPagerConfig holds the credentials used to page on-call contacts
+type OnCallPagerConfig struct {
+	TwilioAccountSID string
+	TwilioAuthToken  string
+	TwilioFromNumber string
+}
+
+// OnCallPager pages contacts by SMS through Twilio or through PagerDuty
+type OnCallPager struct {
+	config OnCallPagerConfig
+	client *http.Client
+}
+
+// NewOnCallPager creates an on-call pager
+func NewOnCallPager(config OnCallPagerConfig) *OnCallPager {
+	return &OnCallPager{
+		config: config,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Page sends an alert to a contact according to its scheme
+func (p *OnCallPager) Page(ctx context.Context, contact string, alert *database.Alert) error {
+	switch {
+	case strings.HasPrefix(contact, ContactSchemeSMS):
+		return p.sendSMS(ctx, strings.TrimPrefix(contact, ContactSchemeSMS), alert)
+	case strings.HasPrefix(contact, ContactSchemePagerDuty):
+		return p.triggerPagerDuty(ctx, strings.TrimPrefix(contact, ContactSchemePagerDuty), alert)
+	default:
+		return fmt.Errorf("unsupported escalation contact: %s", contact)
+	}
+}
+
+// sendSMS sends the alert as a text message through Twilio
+func (p *OnCallPager) sendSMS(ctx context.Context, to string, alert *database.Alert) error {
+	if p.config.TwilioAccountSID == "" || p.config.TwilioAuthToken == "" {
+		return fmt.Errorf("twilio is not configured")
+	}
+
+	form := url.Values{}
+	form.Set("To", to)
+	form.Set("From", p.config.TwilioFromNumber)
+	form.Set("Body", fmt.Sprintf("[Aran MCP Sentinel] CRITICAL: %s - %s", alert.Title, alert.Message))
+
+	endpoint := fmt.Sprintf("https://api.twilio.com/2010-04-01/Accounts/%s/Messages.json", p.config.TwilioAccountSID)
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create twilio request: %w", err)
+	}
+	req.SetBasicAuth(p.config.TwilioAccountSID, p.config.TwilioAuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return p.do(req)
+}
+
+// triggerPagerDuty triggers a PagerDuty incident for the alert. The alert ID is the
+// dedup key so later levels add to the same incident.
+func (p *OnCallPager) triggerPagerDuty(ctx context.Context, routingKey string, alert *database.Alert) error {
+	event := map[string]interface{}{
+		"routing_key":  routingKey,
+		"event_action": "trigger",
+		"dedup_key":    alert.ID.String(),
+		"payload": map[string]interface{}{
+			"summary":   alert.Title,
+			"source":    "aran-mcp-sentinel",
+			"severity":  "critical",
+			"timestamp": alert.CreatedAt.Format(time.RFC3339),
+			"custom_details": map[string]interface{}{
+				"message":         alert.Message,
+				"alert_type":      alert.Type,
+				"organization_id": alert.OrganizationID.String(),
+			},
+		},
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pagerduty event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", pagerDutyEventsURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create pagerduty request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return p.do(req)
+}
+
+// do sends a request and treats any non-2xx status as an error
+func (p *OnCallPager) do(req *http.Request) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("page rejected with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ServerIdentityChangedAlertType is the alert type raised when a server's fingerprint changes
+const ServerIdentityChangedAlertType = "server_identity_changed"
+
+// RecordServerIdentityChange stores a server's new fingerprint on every registered server
+// with the given URL, keeping the old one as previous_fingerprint until the change is
+// acknowledged, and raises a warning alert for each of them
+func (m *MCPMonitor) RecordServerIdentityChange(ctx context.Context, serverURL, previousFingerprint, fingerprint string) error {
+	rows, err := m.db.QueryContext(ctx, `
+		UPDATE mcp_servers
+		SET previous_fingerprint = $2, fingerprint = $3,
+			identity_changed_at = NOW(), updated_at = NOW()
+		WHERE url = $1 AND deleted_at IS NULL
+		RETURNING id, organization_id, name
+	`, serverURL, previousFingerprint, fingerprint)
+	if err != nil {
+		return fmt.Errorf("failed to record server fingerprint: %w", err)
+	}
+
+	type changedServer struct {
+		id    uuid.UUID
+		orgID uuid.UUID
+		name  string
+	}
+	var servers []changedServer
+	for rows.Next() {
+		var server changedServer
+		if err := rows.Scan(&server.id, &server.orgID, &server.name); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan server: %w", err)
+		}
+		servers = append(servers, server)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to record server fingerprint: %w", err)
+	}
+
+	metadataJSON, _ := json.Marshal(map[string]interface{}{
+		"url":                  serverURL,
+		"fingerprint":          fingerprint,
+		"previous_fingerprint": previousFingerprint,
+	})
+
+	for _, server := range servers {
+		_, err := m.db.ExecContext(ctx, `
+			INSERT INTO alerts (id, organization_id, server_id, type, severity, title, message, metadata, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+		`,
+			uuid.New(),
+			server.orgID,
+			server.id,
+			ServerIdentityChangedAlertType,
+			string(AlertLevelWarning),
+			"Server identity changed",
+			fmt.Sprintf("Server %s now reports a different name, version, tool set or capabilities", server.name),
+			metadataJSON,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to store identity change alert: %w", err)
+		}
+
+		m.logger.Warn("Raised server identity change alert",
+			zap.String("server_id", server.id.String()),
+			zap.String("fingerprint", fingerprint))
+	}
+
+	return nil
+}
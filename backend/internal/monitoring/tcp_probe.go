@@ -0,0 +1,61 @@
+package monitoring
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// tcpProbeTimeout bounds a TCP probe made alongside a health check
+const tcpProbeTimeout = 5 * time.Second
+
+// TCPProbe measures how long it takes to open a TCP connection to host:port. It has no
+// HTTP or MCP overhead, so it reflects network latency rather than server processing time.
+func TCPProbe(host string, port int, timeout time.Duration) (time.Duration, error) {
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+
+	start := time.Now()
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return 0, fmt.Errorf("tcp probe to %s failed: %w", address, err)
+	}
+	latency := time.Since(start)
+	conn.Close()
+
+	return latency, nil
+}
+
+// probeServerURL runs a TCP probe against the host and port of a server URL, using the
+// scheme's default port when the URL has none
+func probeServerURL(serverURL string, timeout time.Duration) (time.Duration, error) {
+	parsed, err := url.Parse(serverURL)
+	if err != nil {
+		return 0, fmt.Errorf("invalid server URL: %w", err)
+	}
+
+	host := parsed.Hostname()
+	if host == "" {
+		return 0, fmt.Errorf("server URL %q has no host", serverURL)
+	}
+
+	port := 0
+	if p := parsed.Port(); p != "" {
+		port, err = strconv.Atoi(p)
+		if err != nil {
+			return 0, fmt.Errorf("invalid port in server URL: %w", err)
+		}
+	} else {
+		switch parsed.Scheme {
+		case "http", "ws":
+			port = 80
+		case "https", "wss":
+			port = 443
+		default:
+			return 0, fmt.Errorf("no default port for scheme %q", parsed.Scheme)
+		}
+	}
+
+	return TCPProbe(host, port, timeout)
+}
@@ -0,0 +1,203 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"go.uber.org/zap"
+)
+
+// QuietHours represents a daily window during which notifications are suppressed
+type QuietHours struct {
+	Start    string `json:"start"`    // HH:MM
+	End      string `json:"end"`      // HH:MM
+	Timezone string `json:"timezone"` // IANA name, defaults to UTC
+}
+
+// DigestJob periodically sends alert digest emails to each organization
+type DigestJob struct {
+	repo       *database.Repository
+	channel    *SMTPNotificationChannel
+	logger     *zap.Logger
+	digestHour int
+
+	mu       sync.Mutex
+	lastSent map[uuid.UUID]time.Time
+}
+
+// NewDigestJob creates a digest job that sends at digestHour in each organization's local time
+func NewDigestJob(repo *database.Repository, channel *SMTPNotificationChannel, digestHour int, logger *zap.Logger) *DigestJob {
+	return &DigestJob{
+		repo:       repo,
+		channel:    channel,
+		logger:     logger,
+		digestHour: digestHour,
+		lastSent:   make(map[uuid.UUID]time.Time),
+	}
+}
+
+// Start runs the digest job until the context is cancelled
+func (j *DigestJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(15 * time.Minute)
+	defer ticker.Stop()
+
+	j.logger.Info("Started alert digest job", zap.Int("digest_hour", j.digestHour))
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Stopping alert digest job")
+			return
+		case now := <-ticker.C:
+			j.run(ctx, now)
+		}
+	}
+}
+
+// run sends digests to every organization that is due
+func (j *DigestJob) run(ctx context.Context, now time.Time) {
+	orgs, err := j.repo.ListOrganizations(ctx)
+	if err != nil {
+		j.logger.Error("Failed to list organizations for digest", zap.Error(err))
+		return
+	}
+
+	for _, org := range orgs {
+		quietHours := quietHoursFromSettings(org.Settings)
+		loc := quietHours.location()
+		local := now.In(loc)
+
+		if local.Hour() != j.digestHour || !j.due(org.ID, now) {
+			continue
+		}
+
+		if quietHours.Contains(local) {
+			j.logger.Debug("Skipping digest during quiet hours", zap.String("organization_id", org.ID.String()))
+			continue
+		}
+
+		if err := j.sendDigest(ctx, org, now); err != nil {
+			j.logger.Error("Failed to send alert digest",
+				zap.String("organization_id", org.ID.String()),
+				zap.Error(err))
+		}
+	}
+}
+
+// due reports whether an organization has not received a digest in the last day
+func (j *DigestJob) due(orgID uuid.UUID, now time.Time) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	last, exists := j.lastSent[orgID]
+	return !exists || now.Sub(last) >= 23*time.Hour
+}
+
+// sendDigest builds and sends the digest for a single organization
+func (j *DigestJob) sendDigest(ctx context.Context, org *database.Organization, now time.Time) error {
+	j.mu.Lock()
+	since, exists := j.lastSent[org.ID]
+	j.mu.Unlock()
+	if !exists {
+		since = now.Add(-24 * time.Hour)
+	}
+
+	digest, err := j.repo.GetAggregatedAlerts(ctx, org.ID, since)
+	if err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	j.lastSent[org.ID] = now
+	j.mu.Unlock()
+
+	if digest.TotalAlerts == 0 && len(digest.UnresolvedCritical) == 0 {
+		return nil
+	}
+
+	if err := j.channel.SendDigest([]string{org.Email}, org.Name, digest); err != nil {
+		return err
+	}
+
+	j.logger.Info("Sent alert digest",
+		zap.String("organization_id", org.ID.String()),
+		zap.Int("total_alerts", digest.TotalAlerts))
+
+	return nil
+}
+
+// Contains reports whether t falls within the quiet hours window
+func (q *QuietHours) Contains(t time.Time) bool {
+	if q == nil {
+		return false
+	}
+
+	start, err := parseClock(q.Start)
+	if err != nil {
+		return false
+	}
+	end, err := parseClock(q.End)
+	if err != nil {
+		return false
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	if start <= end {
+		return minute >= start && minute < end
+	}
+	// Window wraps past midnight, e.g. 22:00-08:00
+	return minute >= start || minute < end
+}
+
+// location returns the quiet hours timezone, falling back to UTC
+func (q *QuietHours) location() *time.Location {
+	if q == nil || q.Timezone == "" {
+		return time.UTC
+	}
+
+	loc, err := time.LoadLocation(q.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// quietHoursFromSettings reads the quiet_hours entry from organization settings
+func quietHoursFromSettings(settings database.JSONB) *QuietHours {
+	raw, ok := settings["quiet_hours"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	quietHours := &QuietHours{}
+	quietHours.Start, _ = raw["start"].(string)
+	quietHours.End, _ = raw["end"].(string)
+	quietHours.Timezone, _ = raw["timezone"].(string)
+
+	return quietHours
+}
+
+// parseClock parses an HH:MM string into minutes past midnight
+func parseClock(value string) (int, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("invalid time of day: %s", value)
+	}
+
+	hour, err := strconv.Atoi(parts[0])
+	if err != nil || hour < 0 || hour > 23 {
+		return 0, fmt.Errorf("invalid hour: %s", value)
+	}
+	minute, err := strconv.Atoi(parts[1])
+	if err != nil || minute < 0 || minute > 59 {
+		return 0, fmt.Errorf("invalid minute: %s", value)
+	}
+
+	return hour*60 + minute, nil
+}
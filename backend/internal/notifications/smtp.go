@@ -0,0 +1,112 @@
+package notifications
+
+import (
+	"bytes"
+	"fmt"
+	"net/smtp"
+	"sort"
+	"strings"
+
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+)
+
+// SMTPConfig holds SMTP server settings
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPNotificationChannel delivers notifications by email
+type SMTPNotificationChannel struct {
+	config SMTPConfig
+}
+
+// NewSMTPNotificationChannel creates a new SMTP notification channel
+func NewSMTPNotificationChannel(config SMTPConfig) *SMTPNotificationChannel {
+	return &SMTPNotificationChannel{
+		config: config,
+	}
+}
+
+// Send sends a plain-text email to the given recipients
+func (s *SMTPNotificationChannel) Send(to []string, subject, body string) error {
+	if len(to) == 0 {
+		return fmt.Errorf("no recipients")
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", s.config.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", subject)
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n\r\n")
+	msg.WriteString(body)
+
+	var auth smtp.Auth
+	if s.config.Username != "" {
+		auth = smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+	}
+
+	addr := fmt.Sprintf("%s:%d", s.config.Host, s.config.Port)
+	if err := smtp.SendMail(addr, auth, s.config.From, to, msg.Bytes()); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+// SendDigest emails an alert digest to the given recipients
+func (s *SMTPNotificationChannel) SendDigest(to []string, orgName string, digest *database.AlertDigest) error {
+	subject := fmt.Sprintf("[Aran MCP Sentinel] %d alerts for %s since %s",
+		digest.TotalAlerts, orgName, digest.Since.Format("Jan 2 15:04"))
+
+	return s.Send(to, subject, formatDigest(orgName, digest))
+}
+
+// formatDigest renders an alert digest as plain text
+func formatDigest(orgName string, digest *database.AlertDigest) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Alert digest for %s\n", orgName)
+	fmt.Fprintf(&b, "Since: %s\n", digest.Since.Format("2006-01-02 15:04 MST"))
+	fmt.Fprintf(&b, "Total alerts: %d\n\n", digest.TotalAlerts)
+
+	b.WriteString("By severity:\n")
+	for _, key := range sortedKeys(digest.BySeverity) {
+		fmt.Fprintf(&b, "  %-10s %d\n", key, digest.BySeverity[key])
+	}
+
+	b.WriteString("\nBy type:\n")
+	for _, key := range sortedKeys(digest.ByType) {
+		fmt.Fprintf(&b, "  %-10s %d\n", key, digest.ByType[key])
+	}
+
+	if len(digest.TopServers) > 0 {
+		b.WriteString("\nMost impacted servers:\n")
+		for _, server := range digest.TopServers {
+			fmt.Fprintf(&b, "  %s (%d alerts)\n", server.ServerName, server.AlertCount)
+		}
+	}
+
+	if len(digest.UnresolvedCritical) > 0 {
+		b.WriteString("\nUnresolved critical alerts:\n")
+		for _, alert := range digest.UnresolvedCritical {
+			fmt.Fprintf(&b, "  [%s] %s - %s\n", alert.CreatedAt.Format("2006-01-02 15:04"), alert.Title, alert.Message)
+		}
+	}
+
+	return b.String()
+}
+
+// sortedKeys returns the keys of a count map in sorted order
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,234 @@
+package onboarding
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/auth"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/discovery"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/models"
+	"go.uber.org/zap"
+)
+
+// Handler handles organization onboarding endpoints
+type Handler struct {
+	repo      *database.Repository
+	discovery *discovery.MCPDiscoveryService
+	logger    *zap.Logger
+}
+
+// NewHandler creates a new onboarding handler
+func NewHandler(repo *database.Repository, logger *zap.Logger) *Handler {
+	return &Handler{
+		repo:      repo,
+		discovery: discovery.NewMCPDiscoveryService(logger),
+		logger:    logger,
+	}
+}
+
+// RegisterRoutes registers onboarding routes
+func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
+	onboarding := router.Group("/onboarding")
+	{
+		onboarding.GET("/status", h.GetStatus)
+		onboarding.POST("/complete", h.Complete)
+	}
+}
+
+// CompleteRequest represents a request to complete onboarding
+type CompleteRequest struct {
+	PresetIDs []string `json:"preset_ids" binding:"required,min=1"`
+}
+
+// OnboardingResult represents the outcome of completing onboarding
+type OnboardingResult struct {
+	ServersCreated []*database.MCPServer `json:"servers_created"`
+	Errors         []string              `json:"errors"`
+}
+
+// GetStatus reports whether the organization has completed onboarding
+func (h *Handler) GetStatus(c *gin.Context) {
+	orgID, ok := organizationID(c)
+	if !ok {
+		return
+	}
+
+	count, err := h.repo.CountMCPServers(c.Request.Context(), orgID)
+	if err != nil {
+		h.logger.Error("Failed to count servers", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get onboarding status"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"completed":    count > 0,
+			"server_count": count,
+		},
+	})
+}
+
+// Complete creates servers from the selected presets and triggers initial discovery
+func (h *Handler) Complete(c *gin.Context) {
+	orgID, ok := organizationID(c)
+	if !ok {
+		return
+	}
+
+	userUUID, ok := auth.AuthenticatedUserID(c, h.repo)
+	if !ok {
+		return
+	}
+
+	var req CompleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	result := &OnboardingResult{
+		ServersCreated: []*database.MCPServer{},
+		Errors:         []string{},
+	}
+
+	for _, presetID := range req.PresetIDs {
+		preset := models.GetPresetByID(presetID)
+		if preset == nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("preset not found: %s", presetID))
+			continue
+		}
+
+		server, err := h.createServer(c, orgID, userUUID, preset)
+		if err != nil {
+			h.logger.Error("Failed to create server from preset",
+				zap.String("preset_id", presetID),
+				zap.Error(err))
+			result.Errors = append(result.Errors, fmt.Sprintf("failed to create server for preset %s", presetID))
+			continue
+		}
+
+		result.ServersCreated = append(result.ServersCreated, server)
+
+		go h.runInitialDiscovery(server.OrganizationID, server.ID, server.URL)
+	}
+
+	h.logger.Info("Onboarding completed",
+		zap.String("organization_id", orgID.String()),
+		zap.Int("servers_created", len(result.ServersCreated)),
+		zap.Int("errors", len(result.Errors)))
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// createServer creates a server from a preset together with its audit log entry
+func (h *Handler) createServer(c *gin.Context, orgID, userID uuid.UUID, preset *models.MCPServerPreset) (*database.MCPServer, error) {
+	ctx := c.Request.Context()
+	description := preset.Description
+	ipAddress := c.ClientIP()
+	userAgent := c.GetHeader("User-Agent")
+
+	var server *database.MCPServer
+	err := h.repo.WithTransaction(ctx, func(tx *database.Repository) error {
+		var err error
+		server, err = tx.CreateMCPServer(ctx, &database.CreateMCPServerRequest{
+			OrganizationID: orgID,
+			Name:           preset.Name,
+			URL:            preset.DefaultURL,
+			Description:    &description,
+			Type:           presetServerType(preset),
+			Metadata: database.JSONB{
+				"preset_id": preset.ID,
+				"category":  preset.Category,
+				"config":    preset.ConfigTemplate,
+			},
+			CreatedBy: userID,
+		})
+		if err != nil {
+			return err
+		}
+
+		return tx.CreateAuditLog(ctx, &database.AuditLog{
+			OrganizationID: orgID,
+			UserID:         &userID,
+			Action:         "create",
+			ResourceType:   "mcp_server",
+			ResourceID:     &server.ID,
+			Details:        database.JSONB{"preset_id": preset.ID, "source": "onboarding"},
+			IPAddress:      &ipAddress,
+			UserAgent:      &userAgent,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return server, nil
+}
+
+// runInitialDiscovery probes a newly created server and records its first status
+func (h *Handler) runInitialDiscovery(orgID, serverID uuid.UUID, serverURL string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	discovered, err := h.discovery.RefreshServer(ctx, serverURL)
+	if err != nil {
+		h.logger.Warn("Initial discovery failed",
+			zap.String("server_id", serverID.String()),
+			zap.String("url", serverURL),
+			zap.Error(err))
+		errMsg := err.Error()
+		if updateErr := h.repo.UpdateMCPServerStatus(ctx, orgID, serverID, "offline", nil, nil, &errMsg, nil); updateErr != nil {
+			h.logger.Error("Failed to update server status", zap.Error(updateErr))
+		}
+		return
+	}
+
+	responseTimeMs := int(discovered.ResponseTime.Milliseconds())
+	var protocolVersion *string
+	if discovered.ProtocolVersion != "" {
+		protocolVersion = &discovered.ProtocolVersion
+	}
+	if err := h.repo.UpdateMCPServerStatus(ctx, orgID, serverID, "online", &responseTimeMs, nil, nil, protocolVersion); err != nil {
+		h.logger.Error("Failed to update server status", zap.Error(err))
+	}
+}
+
+// organizationID extracts the organization ID from the request context
+func organizationID(c *gin.Context) (uuid.UUID, bool) {
+	orgID, exists := c.Get("organization_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return uuid.Nil, false
+	}
+
+	orgUUID, ok := orgID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID type"})
+		return uuid.Nil, false
+	}
+
+	return orgUUID, true
+}
+
+// presetServerType maps a preset to one of the supported server types
+func presetServerType(preset *models.MCPServerPreset) string {
+	switch {
+	case preset.ID == "filesystem":
+		return "filesystem"
+	case preset.Category == "Database":
+		return "database"
+	case preset.ID == "custom-http":
+		return "custom"
+	default:
+		return "api"
+	}
+}
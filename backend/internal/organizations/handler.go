@@ -0,0 +1,187 @@
+package organizations
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/monitoring"
+	"go.uber.org/zap"
+)
+
+// Handler handles organization endpoints
+type Handler struct {
+	repo   *database.Repository
+	logger *zap.Logger
+}
+
+// NewHandler creates a new organizations handler
+func NewHandler(repo *database.Repository, logger *zap.Logger) *Handler {
+	return &Handler{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// RegisterRoutes registers organization routes
+func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
+	organizations := router.Group("/organizations")
+	{
+		organizations.GET("/:id/settings", h.GetSettings)
+		organizations.PUT("/:id/settings", h.UpdateSettings)
+		organizations.PUT("/:id/escalation-policy", h.UpdateEscalationPolicy)
+	}
+}
+
+// GetSettings returns an organization's feature settings
+func (h *Handler) GetSettings(c *gin.Context) {
+	orgID, ok := h.authorizedOrganization(c)
+	if !ok {
+		return
+	}
+
+	settings, err := h.repo.GetOrgSettings(c.Request.Context(), orgID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Organization not found"})
+			return
+		}
+		h.logger.Error("Failed to get organization settings", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get organization settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    settings,
+	})
+}
+
+// UpdateSettings replaces an organization's feature settings
+func (h *Handler) UpdateSettings(c *gin.Context) {
+	orgID, ok := h.authorizedOrganization(c)
+	if !ok {
+		return
+	}
+
+	settings := database.DefaultOrgSettings()
+	if err := c.ShouldBindJSON(&settings); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := settings.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.UpdateOrgSettings(c.Request.Context(), orgID, settings); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Organization not found"})
+			return
+		}
+		h.logger.Error("Failed to update organization settings", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update organization settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    settings,
+	})
+}
+
+// EscalationPolicyRequest represents a request to set an organization's escalation policy
+type EscalationPolicyRequest struct {
+	Level1DelaySeconds int      `json:"level1_delay_seconds"`
+	Level1Contacts     []string `json:"level1_contacts"`
+	Level2DelaySeconds int      `json:"level2_delay_seconds"`
+	Level2Contacts     []string `json:"level2_contacts"`
+	Level3Contacts     []string `json:"level3_contacts"`
+}
+
+// UpdateEscalationPolicy creates or replaces an organization's alert escalation policy
+func (h *Handler) UpdateEscalationPolicy(c *gin.Context) {
+	orgID, ok := h.authorizedOrganization(c)
+	if !ok {
+		return
+	}
+
+	var req EscalationPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	policy := monitoring.EscalationPolicy{
+		Level1Delay:    time.Duration(req.Level1DelaySeconds) * time.Second,
+		Level1Contacts: req.Level1Contacts,
+		Level2Delay:    time.Duration(req.Level2DelaySeconds) * time.Second,
+		Level2Contacts: req.Level2Contacts,
+		Level3Contacts: req.Level3Contacts,
+	}
+	if err := policy.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	record := &database.EscalationPolicy{
+		OrganizationID:     orgID,
+		Level1DelaySeconds: req.Level1DelaySeconds,
+		Level1Contacts:     nonNilContacts(req.Level1Contacts),
+		Level2DelaySeconds: req.Level2DelaySeconds,
+		Level2Contacts:     nonNilContacts(req.Level2Contacts),
+		Level3Contacts:     nonNilContacts(req.Level3Contacts),
+	}
+	if err := h.repo.UpsertEscalationPolicy(c.Request.Context(), record); err != nil {
+		h.logger.Error("Failed to update escalation policy", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update escalation policy"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    record,
+	})
+}
+
+// nonNilContacts returns an empty list for nil so the stored array is never NULL
+func nonNilContacts(contacts []string) []string {
+	if contacts == nil {
+		return []string{}
+	}
+	return contacts
+}
+
+// authorizedOrganization parses the organization ID from the path and checks that it
+// matches the caller's organization
+func (h *Handler) authorizedOrganization(c *gin.Context) (uuid.UUID, bool) {
+	orgID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+		return uuid.Nil, false
+	}
+
+	callerOrgID, exists := c.Get("organization_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return uuid.Nil, false
+	}
+
+	callerOrgUUID, ok := callerOrgID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID type"})
+		return uuid.Nil, false
+	}
+
+	if callerOrgUUID != orgID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return uuid.Nil, false
+	}
+
+	return orgID, true
+}
@@ -0,0 +1,275 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	apperrors "github.com/radhi1991/aran-mcp-sentinel/internal/errors"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/models"
+	"go.uber.org/zap"
+)
+
+// catalogURLMetadataKey marks servers created by a catalog sync with the catalog's URL.
+// Only servers carrying it are pruned, so manually registered servers are never removed.
+const catalogURLMetadataKey = "catalog_url"
+
+// Catalog fetch limits
+const (
+	catalogFetchTimeout = 30 * time.Second
+	catalogMaxSize      = 10 << 20
+)
+
+// SyncResult summarizes a catalog sync
+type SyncResult struct {
+	CatalogURL     string    `json:"catalog_url"`
+	OrganizationID uuid.UUID `json:"organization_id"`
+	Created        int       `json:"created"`
+	Updated        int       `json:"updated"`
+	Unchanged      int       `json:"unchanged"`
+	Removed        int       `json:"removed"`
+	Errors         []string  `json:"errors"`
+	SyncedAt       time.Time `json:"synced_at"`
+}
+
+// catalogClient fetches external catalogs
+var catalogClient = &http.Client{Timeout: catalogFetchTimeout}
+
+// SyncFromExternalCatalog fetches a JSON array of server definitions from catalogURL and
+// makes the organization's registry match it. Servers are matched by URL: new ones are
+// created and changed ones updated. If PruneAbsent is set, servers previously created
+// from the same catalog that it no longer lists are removed. Invalid entries are reported
+// in the result rather than failing the sync.
+func (sr *ServerRegistry) SyncFromExternalCatalog(ctx context.Context, catalogURL string, orgID uuid.UUID) (*SyncResult, error) {
+	return sr.syncFromExternalCatalog(ctx, catalogURL, orgID, sr.PruneAbsent)
+}
+
+// syncFromExternalCatalog syncs from a catalog, pruning absent servers if pruneAbsent is set
+func (sr *ServerRegistry) syncFromExternalCatalog(ctx context.Context, catalogURL string, orgID uuid.UUID, pruneAbsent bool) (*SyncResult, error) {
+	entries, err := fetchCatalog(ctx, catalogURL)
+	if err != nil {
+		return nil, err
+	}
+
+	servers, err := sr.repo.GetAllServers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list registry servers: %w", err)
+	}
+
+	existing := make(map[string]*models.MCPServer)
+	for _, server := range servers {
+		if server.OrganizationID == orgID && server.DeletedAt == nil {
+			existing[server.URL] = server
+		}
+	}
+
+	result := &SyncResult{
+		CatalogURL:     catalogURL,
+		OrganizationID: orgID,
+		Errors:         []string{},
+		SyncedAt:       time.Now(),
+	}
+
+	listed := make(map[string]bool)
+	for i, entry := range entries {
+		if entry.Name == "" || entry.URL == "" || entry.Type == "" {
+			result.Errors = append(result.Errors, fmt.Sprintf("entry %d: name, url and type are required", i))
+			continue
+		}
+		if entry.OrganizationID != "" && entry.OrganizationID != orgID.String() {
+			result.Errors = append(result.Errors, fmt.Sprintf("entry %d (%s): belongs to organization %s", i, entry.Name, entry.OrganizationID))
+			continue
+		}
+		if listed[entry.URL] {
+			result.Errors = append(result.Errors, fmt.Sprintf("entry %d (%s): duplicate url %s", i, entry.Name, entry.URL))
+			continue
+		}
+		listed[entry.URL] = true
+
+		server := catalogServer(entry, orgID, catalogURL)
+
+		current, exists := existing[entry.URL]
+		if !exists {
+			server.ID = uuid.New()
+			server.Status = "unknown"
+			if err := sr.RegisterServer(ctx, server); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("entry %d (%s): failed to create: %v", i, entry.Name, err))
+				continue
+			}
+			result.Created++
+			continue
+		}
+
+		if catalogFieldsEqual(current, server) {
+			result.Unchanged++
+			continue
+		}
+
+		server.ID = current.ID
+		server.Status = current.Status
+		server.CreatedAt = current.CreatedAt
+		if err := sr.RegisterServer(ctx, server); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("entry %d (%s): failed to update: %v", i, entry.Name, err))
+			continue
+		}
+		result.Updated++
+	}
+
+	if pruneAbsent {
+		for serverURL, server := range existing {
+			if listed[serverURL] || server.Metadata[catalogURLMetadataKey] != catalogURL {
+				continue
+			}
+			if err := sr.UnregisterServer(ctx, server.ID); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("failed to remove %s: %v", server.Name, err))
+				continue
+			}
+			result.Removed++
+		}
+	}
+
+	sr.logger.Info("Synced registry from external catalog",
+		zap.String("catalog_url", catalogURL),
+		zap.String("organization_id", orgID.String()),
+		zap.Int("created", result.Created),
+		zap.Int("updated", result.Updated),
+		zap.Int("removed", result.Removed),
+		zap.Int("errors", len(result.Errors)))
+
+	return result, nil
+}
+
+// fetchCatalog downloads and decodes a catalog
+func fetchCatalog(ctx context.Context, catalogURL string) ([]RegisterServerRequest, error) {
+	parsed, err := url.Parse(catalogURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, &apperrors.ValidationError{Field: "catalog_url", Message: fmt.Sprintf("%q is not an http or https URL", catalogURL)}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, catalogURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create catalog request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := catalogClient.Do(req)
+	if err != nil {
+		return nil, &apperrors.ConnectionError{URL: catalogURL, Cause: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &apperrors.ConnectionError{URL: catalogURL, Cause: fmt.Errorf("catalog returned status %d", resp.StatusCode)}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, catalogMaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read catalog: %w", err)
+	}
+	if len(body) > catalogMaxSize {
+		return nil, fmt.Errorf("catalog exceeds %d bytes", catalogMaxSize)
+	}
+
+	var entries []RegisterServerRequest
+	if err := json.Unmarshal(body, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse catalog: %w", err)
+	}
+
+	return entries, nil
+}
+
+// catalogServer builds the server a catalog entry describes
+func catalogServer(entry RegisterServerRequest, orgID uuid.UUID, catalogURL string) *models.MCPServer {
+	metadata := make(map[string]interface{}, len(entry.Metadata)+2)
+	for key, value := range entry.Metadata {
+		metadata[key] = value
+	}
+	if len(entry.Tags) > 0 {
+		metadata["tags"] = entry.Tags
+	}
+	metadata[catalogURLMetadataKey] = catalogURL
+
+	return &models.MCPServer{
+		OrganizationID: orgID,
+		Name:           entry.Name,
+		URL:            entry.URL,
+		Description:    entry.Description,
+		Type:           entry.Type,
+		Capabilities:   entry.Capabilities,
+		Metadata:       metadata,
+		IsActive:       true,
+	}
+}
+
+// catalogFieldsEqual reports whether a registered server already matches the fields a
+// catalog entry sets. Fields are compared as JSON so decoded metadata, whose lists are
+// []interface{}, equals freshly built metadata.
+func catalogFieldsEqual(current, desired *models.MCPServer) bool {
+	fields := func(server *models.MCPServer) []byte {
+		data, _ := json.Marshal(struct {
+			Name         string                 `json:"name"`
+			Description  string                 `json:"description"`
+			Type         string                 `json:"type"`
+			Capabilities []string               `json:"capabilities"`
+			Metadata     map[string]interface{} `json:"metadata"`
+		}{server.Name, server.Description, server.Type, server.Capabilities, server.Metadata})
+		return data
+	}
+	return bytes.Equal(fields(current), fields(desired))
+}
+
+// CatalogSyncJob periodically syncs an organization's registry from an external catalog
+type CatalogSyncJob struct {
+	registry   *ServerRegistry
+	catalogURL string
+	orgID      uuid.UUID
+	interval   time.Duration
+	logger     *zap.Logger
+}
+
+// NewCatalogSyncJob creates a catalog sync job
+func NewCatalogSyncJob(registry *ServerRegistry, catalogURL string, orgID uuid.UUID, interval time.Duration, logger *zap.Logger) *CatalogSyncJob {
+	return &CatalogSyncJob{
+		registry:   registry,
+		catalogURL: catalogURL,
+		orgID:      orgID,
+		interval:   interval,
+		logger:     logger,
+	}
+}
+
+// Start syncs the catalog immediately and then every interval until the context is cancelled
+func (j *CatalogSyncJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	j.logger.Info("Started registry catalog sync",
+		zap.String("catalog_url", j.catalogURL),
+		zap.Duration("interval", j.interval))
+
+	j.run(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Stopping registry catalog sync")
+			return
+		case <-ticker.C:
+			j.run(ctx)
+		}
+	}
+}
+
+// run performs one sync, logging failures
+func (j *CatalogSyncJob) run(ctx context.Context) {
+	if _, err := j.registry.SyncFromExternalCatalog(ctx, j.catalogURL, j.orgID); err != nil {
+		j.logger.Error("Registry catalog sync failed",
+			zap.String("catalog_url", j.catalogURL),
+			zap.Error(err))
+	}
+}
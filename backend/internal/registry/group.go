@@ -0,0 +1,183 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+)
+
+// Group health statuses
+const (
+	GroupStatusHealthy  = "healthy"
+	GroupStatusDegraded = "degraded"
+	GroupStatusCritical = "critical"
+)
+
+// Offline ratios above which a group is degraded or critical
+const (
+	groupDegradedOfflineRatio = 0.2
+	groupCriticalOfflineRatio = 0.5
+)
+
+// ServerGroup is a named collection of servers whose health is rolled up together
+type ServerGroup struct {
+	ID             uuid.UUID `json:"id"`
+	OrganizationID uuid.UUID `json:"organization_id"`
+	Name           string    `json:"name"`
+
+	repo *database.Repository
+}
+
+// GroupHealthRollup summarizes the health of a server group
+type GroupHealthRollup struct {
+	GroupID          uuid.UUID      `json:"group_id"`
+	Name             string         `json:"name"`
+	Status           string         `json:"status"`
+	GroupHealthScore int            `json:"group_health_score"`
+	TotalServers     int            `json:"total_servers"`
+	OnlineServers    int            `json:"online_servers"`
+	OfflineServers   int            `json:"offline_servers"`
+	Members          []MemberHealth `json:"members"`
+	ComputedAt       time.Time      `json:"computed_at"`
+}
+
+// MemberHealth is the health of a single server in a group
+type MemberHealth struct {
+	ServerID    uuid.UUID `json:"server_id"`
+	Name        string    `json:"name"`
+	Status      string    `json:"status"`
+	HealthScore int       `json:"health_score"`
+}
+
+// LoadServerGroup loads one of an organization's server groups by ID
+func LoadServerGroup(ctx context.Context, repo *database.Repository, organizationID, groupID uuid.UUID) (*ServerGroup, error) {
+	group, err := repo.GetServerGroup(ctx, organizationID, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newServerGroup(group, repo), nil
+}
+
+// LoadServerGroups loads all server groups in an organization
+func LoadServerGroups(ctx context.Context, repo *database.Repository, organizationID uuid.UUID) ([]*ServerGroup, error) {
+	groups, err := repo.ListServerGroups(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	serverGroups := make([]*ServerGroup, len(groups))
+	for i, group := range groups {
+		serverGroups[i] = newServerGroup(group, repo)
+	}
+
+	return serverGroups, nil
+}
+
+// newServerGroup wraps a stored server group
+func newServerGroup(group *database.ServerGroup, repo *database.Repository) *ServerGroup {
+	return &ServerGroup{
+		ID:             group.ID,
+		OrganizationID: group.OrganizationID,
+		Name:           group.Name,
+		repo:           repo,
+	}
+}
+
+// ComputeHealthRollup averages the health scores of the group's servers and derives the
+// group status from the share of servers that are offline
+func (g *ServerGroup) ComputeHealthRollup(ctx context.Context) (*GroupHealthRollup, error) {
+	servers, err := g.repo.ListServerGroupMembers(ctx, g.OrganizationID, g.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load group members: %w", err)
+	}
+
+	rollup := &GroupHealthRollup{
+		GroupID:      g.ID,
+		Name:         g.Name,
+		Status:       GroupStatusHealthy,
+		TotalServers: len(servers),
+		Members:      make([]MemberHealth, 0, len(servers)),
+		ComputedAt:   time.Now(),
+	}
+
+	totalHealth := 0
+	for _, server := range servers {
+		member := MemberHealth{
+			ServerID:    server.ID,
+			Name:        server.Name,
+			Status:      server.Status,
+			HealthScore: serverHealthScore(server),
+		}
+		rollup.Members = append(rollup.Members, member)
+
+		totalHealth += member.HealthScore
+		if server.Status == "online" {
+			rollup.OnlineServers++
+		} else {
+			rollup.OfflineServers++
+		}
+	}
+
+	if rollup.TotalServers == 0 {
+		return rollup, nil
+	}
+
+	rollup.GroupHealthScore = totalHealth / rollup.TotalServers
+
+	offlineRatio := float64(rollup.OfflineServers) / float64(rollup.TotalServers)
+	if offlineRatio > groupCriticalOfflineRatio {
+		rollup.Status = GroupStatusCritical
+	} else if offlineRatio > groupDegradedOfflineRatio {
+		rollup.Status = GroupStatusDegraded
+	}
+
+	return rollup, nil
+}
+
+// serverHealthScore scores a server from 0 to 100 using its last recorded check,
+// applying the same deductions as the enhanced health monitor
+func serverHealthScore(server *database.MCPServer) int {
+	if server.Status != "online" {
+		return 0
+	}
+
+	score := 100
+
+	if server.ResponseTimeMs != nil {
+		if *server.ResponseTimeMs > 5000 {
+			score -= 30
+		} else if *server.ResponseTimeMs > 2000 {
+			score -= 15
+		} else if *server.ResponseTimeMs > 1000 {
+			score -= 5
+		}
+	}
+
+	if server.UptimePercentage != nil {
+		if *server.UptimePercentage < 95 {
+			score -= 25
+		} else if *server.UptimePercentage < 99 {
+			score -= 10
+		}
+	}
+
+	if server.ErrorRate != nil {
+		if *server.ErrorRate > 10 {
+			score -= 40
+		} else if *server.ErrorRate > 5 {
+			score -= 20
+		} else if *server.ErrorRate > 1 {
+			score -= 5
+		}
+	}
+
+	if score < 0 {
+		score = 0
+	}
+
+	return score
+}
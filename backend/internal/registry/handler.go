@@ -1,6 +1,8 @@
 package registry
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -8,6 +10,10 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/auth"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	apperrors "github.com/radhi1991/aran-mcp-sentinel/internal/errors"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/middleware"
 	"github.com/radhi1991/aran-mcp-sentinel/internal/models"
 	"github.com/radhi1991/aran-mcp-sentinel/internal/repository"
 	"go.uber.org/zap"
@@ -15,33 +21,55 @@ import (
 
 // RegistryHandler provides registry API endpoints
 type RegistryHandler struct {
-	logger   *zap.Logger
-	registry *ServerRegistry
+	logger      *zap.Logger
+	registry    *ServerRegistry
+	serverRepo  *database.Repository
+	metricsRepo *database.Repository
+
+	// probe checks registered servers; see SetServerProbe
+	probe              ServerProbe
+	validateOnRegister bool
 }
 
-// NewRegistryHandler creates a new registry handler
-func NewRegistryHandler(logger *zap.Logger, repo *repository.MCPServerRepository) *RegistryHandler {
+// NewRegistryHandler creates a new registry handler. serverRepo is used for writes to
+// mcp_servers and for server searches. metricsRepo serves the analytics queries and should point at a read
+// replica when one is configured.
+func NewRegistryHandler(logger *zap.Logger, repo *repository.MCPServerRepository, serverRepo, metricsRepo *database.Repository) *RegistryHandler {
 	registry := NewServerRegistry(logger, repo)
+	if serverRepo != nil {
+		registry.SetSearchRepository(serverRepo)
+	}
 	return &RegistryHandler{
-		logger:   logger,
-		registry: registry,
+		logger:      logger,
+		registry:    registry,
+		serverRepo:  serverRepo,
+		metricsRepo: metricsRepo,
 	}
 }
 
+// SetHealthScoreCache makes registry entries carry the servers' latest health scores
+func (h *RegistryHandler) SetHealthScoreCache(cache *HealthScoreCache) {
+	h.registry.SetHealthScoreCache(cache)
+}
+
 // RegisterRoutes registers all registry API routes
 func (h *RegistryHandler) RegisterRoutes(router *gin.RouterGroup) {
 	registryGroup := router.Group("/registry")
 	{
 		// Server management
-		registryGroup.POST("/servers", h.RegisterServer)
+		registryGroup.POST("/servers", auth.RBACMiddleware(auth.RoleUser, h.logger), h.RegisterServer)
 		registryGroup.GET("/servers", h.SearchServers)
 		registryGroup.GET("/servers/:id", h.GetServer)
-		registryGroup.PUT("/servers/:id", h.UpdateServer)
-		registryGroup.DELETE("/servers/:id", h.UnregisterServer)
+		registryGroup.PUT("/servers/:id", auth.RBACMiddleware(auth.RoleUser, h.logger), h.UpdateServer)
+		registryGroup.DELETE("/servers/:id", auth.RBACMiddleware(auth.RoleAdmin, h.logger), h.UnregisterServer)
+		registryGroup.POST("/servers/:id/deprecate", auth.RBACMiddleware(auth.RoleUser, h.logger), h.DeprecateServer)
+		registryGroup.POST("/servers/:id/acknowledge-change", auth.RBACMiddleware(auth.RoleUser, h.logger), h.AcknowledgeIdentityChange)
+		registryGroup.POST("/sync", auth.RBACMiddleware(auth.RoleAdmin, h.logger), h.SyncCatalog)
 
 		// Registry information
 		registryGroup.GET("/stats", h.GetRegistryStats)
 		registryGroup.GET("/capabilities", h.GetCapabilities)
+		registryGroup.GET("/capabilities-matrix", h.GetCapabilitiesMatrix)
 		registryGroup.GET("/types", h.GetServerTypes)
 
 		// Filtered searches
@@ -50,8 +78,33 @@ func (h *RegistryHandler) RegisterRoutes(router *gin.RouterGroup) {
 		registryGroup.GET("/servers/capability/:capability", h.GetServersByCapability)
 
 		// Health updates
-		registryGroup.PUT("/servers/:id/health", h.UpdateServerHealth)
+		registryGroup.PUT("/servers/:id/health", auth.RBACMiddleware(auth.RoleUser, h.logger), h.UpdateServerHealth)
+
+		// Performance history
+		registryGroup.GET("/servers/:id/metrics", h.GetServerMetrics)
+
+		// Server groups
+		registryGroup.GET("/groups/:id/health", h.GetGroupHealth)
+	}
+}
+
+// authorizeServer checks that a server belongs to the caller's organization and returns
+// the organization. It writes 404 for servers of other organizations, so their existence
+// is not revealed.
+func (h *RegistryHandler) authorizeServer(c *gin.Context, id uuid.UUID) (uuid.UUID, bool) {
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return uuid.Nil, false
 	}
+
+	server, err := h.registry.GetServer(c.Request.Context(), id)
+	if err != nil || server.OrganizationID != orgID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Server not found"})
+		return uuid.Nil, false
+	}
+
+	return orgID, true
 }
 
 // RegisterServerRequest represents the request to register a server
@@ -64,6 +117,10 @@ type RegisterServerRequest struct {
 	Tags           []string               `json:"tags"`
 	OrganizationID string                 `json:"organization_id" binding:"required"`
 	Metadata       map[string]interface{} `json:"metadata"`
+
+	// DryRun checks that the server answers a ping and an initialize request before it
+	// is stored. Registration fails with 422 if it does not.
+	DryRun bool `json:"dry_run"`
 }
 
 // RegisterServer registers a new server in the registry
@@ -75,11 +132,9 @@ func (h *RegistryHandler) RegisterServer(c *gin.Context) {
 		return
 	}
 
-	// Parse organization ID
-	orgID, err := uuid.Parse(req.OrganizationID)
-	if err != nil {
-		h.logger.Error("Invalid organization ID", zap.String("org_id", req.OrganizationID), zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+	// Servers can only be registered in the caller's organization
+	orgID, ok := auth.AuthorizeOrganization(c, req.OrganizationID)
+	if !ok {
 		return
 	}
 
@@ -104,6 +159,29 @@ func (h *RegistryHandler) RegisterServer(c *gin.Context) {
 		server.Metadata["tags"] = req.Tags
 	}
 
+	checkLater := false
+	switch {
+	case req.DryRun:
+		if h.probe == nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Server connectivity checks are not configured"})
+			return
+		}
+		responseTime, err := h.probeServer(c.Request.Context(), server.URL)
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "Server is not reachable",
+				"details": err.Error(),
+			})
+			return
+		}
+		server.Status = "online"
+		server.ResponseTime = responseTime.Milliseconds()
+		server.LastCheckedAt = time.Now()
+	case h.probe != nil && h.validateOnRegister:
+		server.Status = ReachabilityPendingStatus
+		checkLater = true
+	}
+
 	// Register server
 	if err := h.registry.RegisterServer(c.Request.Context(), server); err != nil {
 		h.logger.Error("Failed to register server", zap.Error(err))
@@ -111,6 +189,10 @@ func (h *RegistryHandler) RegisterServer(c *gin.Context) {
 		return
 	}
 
+	if checkLater {
+		go h.checkReachability(server.ID, server.URL)
+	}
+
 	h.logger.Info("Server registered successfully",
 		zap.String("server_id", server.ID.String()),
 		zap.String("server_name", server.Name))
@@ -118,21 +200,30 @@ func (h *RegistryHandler) RegisterServer(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Server registered successfully",
 		"server": gin.H{
-			"id":   server.ID,
-			"name": server.Name,
-			"url":  server.URL,
+			"id":     server.ID,
+			"name":   server.Name,
+			"url":    server.URL,
+			"status": server.Status,
 		},
 	})
 }
 
 // SearchServers searches for servers in the registry
 func (h *RegistryHandler) SearchServers(c *gin.Context) {
+	// Searches are limited to the caller's organization
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
 	// Parse query parameters
 	options := RegistrySearchOptions{
 		Query:          c.Query("query"),
+		TextQuery:      c.Query("text_query"),
 		Type:           c.Query("type"),
 		Status:         c.Query("status"),
-		OrganizationID: c.Query("organization_id"),
+		OrganizationID: orgID.String(),
 		SortBy:         c.Query("sort_by"),
 		SortOrder:      c.Query("sort_order"),
 	}
@@ -181,19 +272,21 @@ func (h *RegistryHandler) SearchServers(c *gin.Context) {
 	}
 
 	// Search servers
-	servers, err := h.registry.SearchServers(c.Request.Context(), options)
+	result, err := h.registry.SearchServers(c.Request.Context(), options)
 	if err != nil {
 		h.logger.Error("Failed to search servers", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search servers"})
 		return
 	}
 
-	h.logger.Info("Server search completed", zap.Int("count", len(servers)))
-	c.JSON(http.StatusOK, gin.H{
-		"servers": servers,
-		"count":   len(servers),
-		"options": options,
-	})
+	page := middleware.NewPage(result.Entries, result.Limit, result.Offset)
+	page.Total = result.TotalCount
+	middleware.SetPage(c, page)
+
+	h.logger.Info("Server search completed",
+		zap.Int("count", len(result.Entries)),
+		zap.Int("total_count", result.TotalCount))
+	c.JSON(http.StatusOK, result)
 }
 
 // GetServer retrieves a specific server from the registry
@@ -208,11 +301,14 @@ func (h *RegistryHandler) GetServer(c *gin.Context) {
 		return
 	}
 
+	if _, ok := h.authorizeServer(c, id); !ok {
+		return
+	}
+
 	// Get server
 	server, err := h.registry.GetServer(c.Request.Context(), id)
 	if err != nil {
-		h.logger.Error("Failed to get server", zap.String("server_id", serverID), zap.Error(err))
-		c.JSON(http.StatusNotFound, gin.H{"error": "Server not found"})
+		h.writeServerLookupError(c, serverID, err)
 		return
 	}
 
@@ -221,6 +317,18 @@ func (h *RegistryHandler) GetServer(c *gin.Context) {
 	})
 }
 
+// writeServerLookupError responds to a failed server lookup with the status for its
+// error type
+func (h *RegistryHandler) writeServerLookupError(c *gin.Context, serverID string, err error) {
+	status := apperrors.StatusCode(err, http.StatusInternalServerError)
+	if status == http.StatusNotFound {
+		c.JSON(status, gin.H{"error": "Server not found"})
+		return
+	}
+	h.logger.Error("Failed to get server", zap.String("server_id", serverID), zap.Error(err))
+	c.JSON(status, gin.H{"error": "Failed to get server"})
+}
+
 // UpdateServer updates a server in the registry
 func (h *RegistryHandler) UpdateServer(c *gin.Context) {
 	serverID := c.Param("id")
@@ -233,6 +341,10 @@ func (h *RegistryHandler) UpdateServer(c *gin.Context) {
 		return
 	}
 
+	if _, ok := h.authorizeServer(c, id); !ok {
+		return
+	}
+
 	// Parse request body
 	var updateReq struct {
 		Name         string                 `json:"name"`
@@ -253,8 +365,7 @@ func (h *RegistryHandler) UpdateServer(c *gin.Context) {
 	// Get existing server
 	existingServer, err := h.registry.GetServer(c.Request.Context(), id)
 	if err != nil {
-		h.logger.Error("Failed to get existing server", zap.String("server_id", serverID), zap.Error(err))
-		c.JSON(http.StatusNotFound, gin.H{"error": "Server not found"})
+		h.writeServerLookupError(c, serverID, err)
 		return
 	}
 
@@ -304,6 +415,10 @@ func (h *RegistryHandler) UnregisterServer(c *gin.Context) {
 		return
 	}
 
+	if _, ok := h.authorizeServer(c, id); !ok {
+		return
+	}
+
 	// Unregister server
 	if err := h.registry.UnregisterServer(c.Request.Context(), id); err != nil {
 		h.logger.Error("Failed to unregister server", zap.String("server_id", serverID), zap.Error(err))
@@ -318,6 +433,185 @@ func (h *RegistryHandler) UnregisterServer(c *gin.Context) {
 	})
 }
 
+// SyncCatalogRequest represents the request to sync the registry from an external catalog
+type SyncCatalogRequest struct {
+	CatalogURL     string `json:"catalog_url" binding:"required"`
+	OrganizationID string `json:"organization_id" binding:"required"`
+	PruneAbsent    bool   `json:"prune_absent"`
+}
+
+// SyncCatalog syncs an organization's servers from an external catalog
+func (h *RegistryHandler) SyncCatalog(c *gin.Context) {
+	var req SyncCatalogRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	orgID, ok := auth.AuthorizeOrganization(c, req.OrganizationID)
+	if !ok {
+		return
+	}
+
+	result, err := h.registry.syncFromExternalCatalog(c.Request.Context(), req.CatalogURL, orgID, req.PruneAbsent)
+	if err != nil {
+		h.logger.Error("Failed to sync registry from catalog", zap.String("catalog_url", req.CatalogURL), zap.Error(err))
+		c.JSON(apperrors.StatusCode(err, http.StatusInternalServerError), gin.H{"error": fmt.Sprintf("Failed to sync catalog: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// DeprecateServerRequest represents the request to deprecate a server
+type DeprecateServerRequest struct {
+	Message             string `json:"message"`
+	ReplacementServerID string `json:"replacement_server_id"`
+}
+
+// deprecationNoticeWindow is how far back tool executions are considered when notifying users
+const deprecationNoticeWindow = 30 * 24 * time.Hour
+
+// DeprecateServer marks a server as deprecated and alerts users who recently executed its tools
+func (h *RegistryHandler) DeprecateServer(c *gin.Context) {
+	serverID := c.Param("id")
+
+	// Parse server ID
+	id, err := uuid.Parse(serverID)
+	if err != nil {
+		h.logger.Error("Invalid server ID", zap.String("server_id", serverID), zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid server ID"})
+		return
+	}
+
+	orgID, ok := h.authorizeServer(c, id)
+	if !ok {
+		return
+	}
+
+	var req DeprecateServerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	server, err := h.serverRepo.GetMCPServerByID(ctx, orgID, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Server not found"})
+		return
+	}
+
+	var replacementID *uuid.UUID
+	if req.ReplacementServerID != "" {
+		parsed, err := uuid.Parse(req.ReplacementServerID)
+		if err != nil || parsed == id {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid replacement server ID"})
+			return
+		}
+		if _, err := h.serverRepo.GetMCPServerByID(ctx, orgID, parsed); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Replacement server not found"})
+			return
+		}
+		replacementID = &parsed
+	}
+
+	if err := h.serverRepo.DeprecateMCPServer(ctx, orgID, id, req.Message, replacementID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Server not found"})
+			return
+		}
+		h.logger.Error("Failed to deprecate server", zap.String("server_id", serverID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to deprecate server"})
+		return
+	}
+
+	// Alert every user who executed this server's tools recently
+	userIDs, err := h.serverRepo.ListRecentToolUsers(ctx, orgID, id, time.Now().Add(-deprecationNoticeWindow))
+	if err != nil {
+		h.logger.Error("Failed to list recent tool users", zap.String("server_id", serverID), zap.Error(err))
+	}
+
+	message := fmt.Sprintf("Server %s has been deprecated.", server.Name)
+	if req.Message != "" {
+		message += " " + req.Message
+	}
+
+	notified := 0
+	for _, userID := range userIDs {
+		metadata := database.JSONB{"user_id": userID.String()}
+		if replacementID != nil {
+			metadata["replacement_server_id"] = replacementID.String()
+		}
+
+		alert := &database.Alert{
+			OrganizationID: server.OrganizationID,
+			ServerID:       &id,
+			Type:           "deprecation",
+			Severity:       "medium",
+			Title:          fmt.Sprintf("Server %s is deprecated", server.Name),
+			Message:        message,
+			Metadata:       metadata,
+		}
+		if err := h.serverRepo.CreateAlert(ctx, alert); err != nil {
+			h.logger.Error("Failed to create deprecation alert", zap.String("user_id", userID.String()), zap.Error(err))
+			continue
+		}
+		notified++
+	}
+
+	h.logger.Info("Server deprecated", zap.String("server_id", serverID), zap.Int("users_notified", notified))
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Server deprecated successfully",
+		"server_id":      serverID,
+		"users_notified": notified,
+	})
+}
+
+// AcknowledgeIdentityChange accepts a server's new fingerprint after its name, version,
+// tools or capabilities changed, resolving the identity change alerts
+func (h *RegistryHandler) AcknowledgeIdentityChange(c *gin.Context) {
+	serverID := c.Param("id")
+
+	id, err := uuid.Parse(serverID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid server ID"})
+		return
+	}
+
+	orgID, ok := h.authorizeServer(c, id)
+	if !ok {
+		return
+	}
+
+	var userID *uuid.UUID
+	if value, exists := c.Get("user_id"); exists {
+		if parsed, err := uuid.Parse(fmt.Sprint(value)); err == nil {
+			userID = &parsed
+		}
+	}
+
+	if err := h.serverRepo.AcknowledgeServerIdentityChange(c.Request.Context(), orgID, id, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Server has no pending identity change"})
+			return
+		}
+		h.logger.Error("Failed to acknowledge identity change", zap.String("server_id", serverID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to acknowledge identity change"})
+		return
+	}
+
+	h.logger.Info("Server identity change acknowledged", zap.String("server_id", serverID))
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Identity change acknowledged",
+		"server_id": serverID,
+	})
+}
+
 // GetRegistryStats returns registry statistics
 func (h *RegistryHandler) GetRegistryStats(c *gin.Context) {
 	stats, err := h.registry.GetRegistryStats(c.Request.Context())
@@ -364,7 +658,13 @@ func (h *RegistryHandler) GetServerTypes(c *gin.Context) {
 func (h *RegistryHandler) GetServersByType(c *gin.Context) {
 	serverType := c.Param("type")
 
-	servers, err := h.registry.GetServersByType(c.Request.Context(), serverType)
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	servers, err := h.registry.GetServersByType(c.Request.Context(), orgID, serverType)
 	if err != nil {
 		h.logger.Error("Failed to get servers by type", zap.String("type", serverType), zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get servers by type"})
@@ -408,7 +708,13 @@ func (h *RegistryHandler) GetServersByOrganization(c *gin.Context) {
 func (h *RegistryHandler) GetServersByCapability(c *gin.Context) {
 	capability := c.Param("capability")
 
-	servers, err := h.registry.GetServersByCapability(c.Request.Context(), capability)
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	servers, err := h.registry.GetServersByCapability(c.Request.Context(), orgID, capability)
 	if err != nil {
 		h.logger.Error("Failed to get servers by capability", zap.String("capability", capability), zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get servers by capability"})
@@ -443,6 +749,10 @@ func (h *RegistryHandler) UpdateServerHealth(c *gin.Context) {
 		return
 	}
 
+	if _, ok := h.authorizeServer(c, id); !ok {
+		return
+	}
+
 	// Parse request body
 	var req UpdateServerHealthRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -488,6 +798,164 @@ func (h *RegistryHandler) UpdateServerHealth(c *gin.Context) {
 	})
 }
 
+// GetCapabilitiesMatrix returns which capabilities each requested server supports
+func (h *RegistryHandler) GetCapabilitiesMatrix(c *gin.Context) {
+	serversParam := c.Query("servers")
+	if serversParam == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "servers parameter is required"})
+		return
+	}
+
+	var serverIDs []uuid.UUID
+	for _, idStr := range splitString(serversParam, ",") {
+		if idStr == "" {
+			continue
+		}
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid server ID", "server_id": idStr})
+			return
+		}
+		serverIDs = append(serverIDs, id)
+	}
+
+	var orgID uuid.UUID
+	for _, id := range serverIDs {
+		authorized, ok := h.authorizeServer(c, id)
+		if !ok {
+			return
+		}
+		orgID = authorized
+	}
+
+	// Discovered tools are optional; fall back to declared capabilities only
+	var toolsByServer map[uuid.UUID][]string
+	if h.metricsRepo != nil {
+		tools, err := h.metricsRepo.ListToolNamesByServer(c.Request.Context(), orgID, serverIDs)
+		if err != nil {
+			h.logger.Warn("Failed to load discovered tools for capabilities matrix", zap.Error(err))
+		} else {
+			toolsByServer = tools
+		}
+	}
+
+	matrix, err := h.registry.GetCapabilitiesMatrix(c.Request.Context(), serverIDs, toolsByServer)
+	if err != nil {
+		h.logger.Error("Failed to build capabilities matrix", zap.Error(err))
+		c.JSON(apperrors.StatusCode(err, http.StatusInternalServerError), gin.H{"error": "Failed to build capabilities matrix"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"matrix": matrix,
+	})
+}
+
+// GetServerMetrics returns bucketed response time and availability history for a server
+func (h *RegistryHandler) GetServerMetrics(c *gin.Context) {
+	serverID := c.Param("id")
+
+	// Parse server ID
+	id, err := uuid.Parse(serverID)
+	if err != nil {
+		h.logger.Error("Invalid server ID", zap.String("server_id", serverID), zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid server ID"})
+		return
+	}
+
+	orgID, ok := h.authorizeServer(c, id)
+	if !ok {
+		return
+	}
+
+	resolution := c.DefaultQuery("resolution", "1h")
+	switch resolution {
+	case "1m", "5m", "1h", "1d":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resolution, expected one of 1m, 5m, 1h, 1d"})
+		return
+	}
+
+	// Default to the last 24 hours
+	end := time.Now()
+	start := end.Add(-24 * time.Hour)
+	if startStr := c.Query("start"); startStr != "" {
+		if start, err = time.Parse(time.RFC3339, startStr); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start time, expected RFC3339"})
+			return
+		}
+	}
+	if endStr := c.Query("end"); endStr != "" {
+		if end, err = time.Parse(time.RFC3339, endStr); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end time, expected RFC3339"})
+			return
+		}
+	}
+	if !start.Before(end) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start must be before end"})
+		return
+	}
+
+	if h.metricsRepo == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Metrics storage not configured"})
+		return
+	}
+
+	series, err := h.metricsRepo.GetServerMetricsSeries(c.Request.Context(), orgID, id, resolution, start, end)
+	if err != nil {
+		h.logger.Error("Failed to get server metrics", zap.String("server_id", serverID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get server metrics"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"metrics": series,
+	})
+}
+
+// GetGroupHealth returns the health rollup of a server group
+func (h *RegistryHandler) GetGroupHealth(c *gin.Context) {
+	groupID := c.Param("id")
+
+	// Parse group ID
+	id, err := uuid.Parse(groupID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+
+	if h.metricsRepo == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Metrics storage not configured"})
+		return
+	}
+
+	orgID, ok := auth.AuthorizedOrganizationID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	group, err := LoadServerGroup(c.Request.Context(), h.metricsRepo, orgID, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Server group not found"})
+			return
+		}
+		h.logger.Error("Failed to get server group", zap.String("group_id", groupID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get server group"})
+		return
+	}
+
+	rollup, err := group.ComputeHealthRollup(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to compute group health", zap.String("group_id", groupID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute group health"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rollup)
+}
+
 // Helper functions
 
 // splitString splits a string by delimiter
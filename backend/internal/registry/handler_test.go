@@ -0,0 +1,52 @@
+package registry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func TestRegistryRoutesRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const serverPath = "/api/v1/registry/servers/6f1c1a8e-5a3e-4c55-9f59-1d1f7b7c0a11"
+
+	tests := []struct {
+		name       string
+		role       string
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{"register without a role", "", http.MethodPost, "/api/v1/registry/servers", http.StatusUnauthorized},
+		{"register as viewer", "viewer", http.MethodPost, "/api/v1/registry/servers", http.StatusForbidden},
+		{"update as viewer", "viewer", http.MethodPut, serverPath, http.StatusForbidden},
+		{"deprecate as viewer", "viewer", http.MethodPost, serverPath + "/deprecate", http.StatusForbidden},
+		{"report health as viewer", "viewer", http.MethodPut, serverPath + "/health", http.StatusForbidden},
+		{"unregister as user", "user", http.MethodDelete, serverPath, http.StatusForbidden},
+		{"sync as user", "user", http.MethodPost, "/api/v1/registry/sync", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			api := router.Group("/api/v1", func(c *gin.Context) {
+				if tt.role != "" {
+					c.Set("user_role", tt.role)
+				}
+				c.Next()
+			})
+			NewRegistryHandler(zap.NewNop(), nil, nil, nil).RegisterRoutes(api)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("%s %s as %q status = %d, want %d", tt.method, tt.path, tt.role, rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
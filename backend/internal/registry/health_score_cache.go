@@ -0,0 +1,115 @@
+package registry
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// HealthScoreCacheTTL is how long a server's health score is used before it is loaded
+// again
+const HealthScoreCacheTTL = 15 * time.Minute
+
+// HealthScoreSource loads the latest recorded health score of servers. It is
+// implemented by database.Repository.
+type HealthScoreSource interface {
+	LatestHealthScores(ctx context.Context, serverIDs []uuid.UUID) (map[uuid.UUID]int, error)
+}
+
+// HealthScoreCache holds the latest health score of each server so registry listings do
+// not query the score history per server. Scores are loaded lazily, in bulk where
+// possible, and replaced by health checks as they run.
+type HealthScoreCache struct {
+	source HealthScoreSource
+	logger *zap.Logger
+	ttl    time.Duration
+
+	mu     sync.RWMutex
+	scores map[uuid.UUID]cachedHealthScore
+}
+
+// cachedHealthScore is a server's score, or the absence of one, until it expires
+type cachedHealthScore struct {
+	score   int
+	found   bool
+	expires time.Time
+}
+
+// NewHealthScoreCache creates a health score cache loading scores from source
+func NewHealthScoreCache(source HealthScoreSource, logger *zap.Logger) *HealthScoreCache {
+	return &HealthScoreCache{
+		source: source,
+		logger: logger,
+		ttl:    HealthScoreCacheTTL,
+		scores: make(map[uuid.UUID]cachedHealthScore),
+	}
+}
+
+// Set records a server's score from a health check
+func (c *HealthScoreCache) Set(serverID uuid.UUID, score int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.scores[serverID] = cachedHealthScore{score: score, found: true, expires: time.Now().Add(c.ttl)}
+}
+
+// Get returns a server's latest health score, loading it if the cached one is missing
+// or stale. The bool is false if the server has never been scored.
+func (c *HealthScoreCache) Get(ctx context.Context, serverID uuid.UUID) (int, bool) {
+	if cached, ok := c.fresh(serverID); ok {
+		return cached.score, cached.found
+	}
+
+	c.Load(ctx, []uuid.UUID{serverID})
+
+	cached, _ := c.fresh(serverID)
+	return cached.score, cached.found
+}
+
+// Load loads the scores of the given servers that are missing or stale in one query.
+// Listings call it first so that Get does not query per server.
+func (c *HealthScoreCache) Load(ctx context.Context, serverIDs []uuid.UUID) {
+	var missing []uuid.UUID
+	for _, serverID := range serverIDs {
+		if _, ok := c.fresh(serverID); !ok {
+			missing = append(missing, serverID)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	scores, err := c.source.LatestHealthScores(ctx, missing)
+	if err != nil {
+		// Leave the servers uncached so the next call retries
+		c.logger.Warn("Failed to load health scores", zap.Int("servers", len(missing)), zap.Error(err))
+		return
+	}
+
+	expires := time.Now().Add(c.ttl)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, serverID := range missing {
+		// A score set by a health check meanwhile is newer than the stored one
+		if cached, ok := c.scores[serverID]; ok && time.Now().Before(cached.expires) {
+			continue
+		}
+		score, found := scores[serverID]
+		c.scores[serverID] = cachedHealthScore{score: score, found: found, expires: expires}
+	}
+}
+
+// fresh returns a server's cached score if it has not expired
+func (c *HealthScoreCache) fresh(serverID uuid.UUID) (cachedHealthScore, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	cached, ok := c.scores[serverID]
+	if !ok || time.Now().After(cached.expires) {
+		return cachedHealthScore{}, false
+	}
+	return cached, true
+}
@@ -0,0 +1,120 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/models"
+	"go.uber.org/zap"
+)
+
+// countingScoreSource scores every server it is asked about except the unscored ones,
+// and counts the queries made
+type countingScoreSource struct {
+	queries  atomic.Int32
+	unscored map[uuid.UUID]bool
+}
+
+func (s *countingScoreSource) LatestHealthScores(ctx context.Context, serverIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	s.queries.Add(1)
+
+	scores := make(map[uuid.UUID]int, len(serverIDs))
+	for i, serverID := range serverIDs {
+		if !s.unscored[serverID] {
+			scores[serverID] = 50 + i%50
+		}
+	}
+	return scores, nil
+}
+
+// listServers builds the registry entries of servers as SearchServers does
+func listServers(ctx context.Context, sr *ServerRegistry, servers []*models.MCPServer) []*RegistryEntry {
+	serverIDs := make([]uuid.UUID, len(servers))
+	for i, server := range servers {
+		serverIDs[i] = server.ID
+	}
+	sr.loadHealthScores(ctx, serverIDs)
+
+	entries := make([]*RegistryEntry, len(servers))
+	for i, server := range servers {
+		entries[i] = sr.convertToRegistryEntry(ctx, server)
+	}
+	return entries
+}
+
+// newTestServers returns n servers with distinct IDs
+func newTestServers(n int) []*models.MCPServer {
+	servers := make([]*models.MCPServer, n)
+	for i := range servers {
+		servers[i] = &models.MCPServer{
+			ID:     uuid.New(),
+			Name:   fmt.Sprintf("server-%d", i),
+			URL:    fmt.Sprintf("http://server-%d.invalid", i),
+			Status: "online",
+		}
+	}
+	return servers
+}
+
+func TestHealthScoreCacheLoadsListingsInOneQuery(t *testing.T) {
+	ctx := context.Background()
+	servers := newTestServers(100)
+	unscored := servers[3].ID
+	source := &countingScoreSource{unscored: map[uuid.UUID]bool{unscored: true}}
+
+	sr := &ServerRegistry{logger: zap.NewNop()}
+	sr.SetHealthScoreCache(NewHealthScoreCache(source, zap.NewNop()))
+
+	entries := listServers(ctx, sr, servers)
+	if queries := source.queries.Load(); queries != 1 {
+		t.Errorf("listing 100 uncached servers made %d queries, want 1", queries)
+	}
+	if entries[0].HealthScore != 50 || entries[3].HealthScore != defaultHealthScore {
+		t.Errorf("health scores = %d, %d, want 50 and the default %d", entries[0].HealthScore, entries[3].HealthScore, defaultHealthScore)
+	}
+
+	listServers(ctx, sr, servers)
+	if queries := source.queries.Load(); queries != 1 {
+		t.Errorf("listing 100 cached servers made %d more queries, want 0", queries-1)
+	}
+
+	// A score from a health check replaces the loaded one
+	sr.scores.Set(servers[0].ID, 12)
+	if score := sr.healthScore(ctx, servers[0].ID); score != 12 {
+		t.Errorf("health score after Set() = %d, want 12", score)
+	}
+}
+
+// BenchmarkListHealthScores builds the entries of 100 servers with their health scores,
+// from an empty cache and from a warm one
+func BenchmarkListHealthScores(b *testing.B) {
+	ctx := context.Background()
+	servers := newTestServers(100)
+
+	b.Run("cold", func(b *testing.B) {
+		source := &countingScoreSource{}
+		sr := &ServerRegistry{logger: zap.NewNop()}
+		for i := 0; i < b.N; i++ {
+			sr.SetHealthScoreCache(NewHealthScoreCache(source, zap.NewNop()))
+			listServers(ctx, sr, servers)
+		}
+		b.ReportMetric(float64(source.queries.Load())/float64(b.N), "queries/op")
+	})
+
+	b.Run("warm", func(b *testing.B) {
+		source := &countingScoreSource{}
+		sr := &ServerRegistry{logger: zap.NewNop()}
+		sr.SetHealthScoreCache(NewHealthScoreCache(source, zap.NewNop()))
+		listServers(ctx, sr, servers)
+		source.queries.Store(0)
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			listServers(ctx, sr, servers)
+		}
+		b.ReportMetric(float64(source.queries.Load())/float64(b.N), "queries/op")
+	})
+}
@@ -0,0 +1,67 @@
+package registry
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ReachabilityPendingStatus is the status of a registered server whose connectivity
+// check has not finished yet
+const ReachabilityPendingStatus = "reachability_pending"
+
+// reachabilityTimeout bounds the connectivity check of a server being registered
+const reachabilityTimeout = 10 * time.Second
+
+// ServerProbe checks that an MCP server answers at serverURL, returning the reason when
+// it does not
+type ServerProbe func(ctx context.Context, serverURL string) error
+
+// SetServerProbe enables connectivity checks of registered servers. Registrations with
+// dry_run are checked before they are stored; others are stored as
+// reachability_pending and checked in the background when validateOnRegister is set.
+func (h *RegistryHandler) SetServerProbe(probe ServerProbe, validateOnRegister bool) {
+	h.probe = probe
+	h.validateOnRegister = validateOnRegister
+}
+
+// probeServer runs the connectivity check with the registration timeout
+func (h *RegistryHandler) probeServer(ctx context.Context, serverURL string) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, reachabilityTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := h.probe(ctx, serverURL)
+	return time.Since(start), err
+}
+
+// checkReachability probes a newly registered server and replaces its pending status
+// with online or error
+func (h *RegistryHandler) checkReachability(serverID uuid.UUID, serverURL string) {
+	responseTime, err := h.probeServer(context.Background(), serverURL)
+
+	status := "online"
+	if err != nil {
+		status = "error"
+		h.logger.Warn("Registered server is not reachable",
+			zap.String("server_id", serverID.String()),
+			zap.String("url", serverURL),
+			zap.Error(err))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), reachabilityTimeout)
+	defer cancel()
+
+	err = h.registry.UpdateServerHealth(ctx, serverID, map[string]interface{}{
+		"status":        status,
+		"response_time": responseTime.Milliseconds(),
+		"last_checked":  time.Now(),
+	})
+	if err != nil {
+		h.logger.Error("Failed to store server reachability",
+			zap.String("server_id", serverID.String()),
+			zap.Error(err))
+	}
+}
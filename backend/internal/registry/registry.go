@@ -4,18 +4,33 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
 	"github.com/radhi1991/aran-mcp-sentinel/internal/models"
 	"github.com/radhi1991/aran-mcp-sentinel/internal/repository"
 	"go.uber.org/zap"
 )
 
+// defaultHealthScore is the health score of servers that have not been scored yet
+const defaultHealthScore = 85
+
 // ServerRegistry provides centralized MCP server registry functionality
 type ServerRegistry struct {
 	logger *zap.Logger
 	repo   *repository.MCPServerRepository
+
+	// PruneAbsent makes catalog syncs remove servers the catalog no longer lists
+	PruneAbsent bool
+
+	// search runs searches as database queries; without it servers are filtered in memory
+	search *database.Repository
+
+	// scores holds the servers' latest health scores; without it every server has the
+	// default score
+	scores *HealthScoreCache
 }
 
 // RegistryEntry represents a server in the registry
@@ -33,6 +48,7 @@ type RegistryEntry struct {
 	LastSeen       time.Time              `json:"last_seen"`
 	HealthScore    int                    `json:"health_score"`
 	ResponseTime   int64                  `json:"response_time_ms"`
+	NetworkLatency int64                  `json:"network_latency_ms"`
 	Uptime         float64                `json:"uptime_percentage"`
 	Metadata       map[string]interface{} `json:"metadata"`
 	CreatedAt      time.Time              `json:"created_at"`
@@ -53,6 +69,7 @@ type RegistryStats struct {
 // RegistrySearchOptions represents search options for the registry
 type RegistrySearchOptions struct {
 	Query          string   `json:"query,omitempty"`
+	TextQuery      string   `json:"text_query,omitempty"` // full-text search over name, description and type
 	Type           string   `json:"type,omitempty"`
 	Status         string   `json:"status,omitempty"`
 	OrganizationID string   `json:"organization_id,omitempty"`
@@ -65,6 +82,37 @@ type RegistrySearchOptions struct {
 	Offset         int      `json:"offset,omitempty"`
 }
 
+// defaultSearchLimit is the page size of searches that do not set a limit
+const defaultSearchLimit = 50
+
+// SearchResult is one page of registry search results
+type SearchResult struct {
+	Entries    []*RegistryEntry `json:"entries"`
+	TotalCount int              `json:"total_count"` // matches across all pages
+	Limit      int              `json:"limit"`
+	Offset     int              `json:"offset"`
+	HasMore    bool             `json:"has_more"`
+}
+
+// newSearchResult builds the page of entries starting at the options' offset
+func newSearchResult(entries []*RegistryEntry, totalCount int, options RegistrySearchOptions) *SearchResult {
+	return &SearchResult{
+		Entries:    entries,
+		TotalCount: totalCount,
+		Limit:      options.Limit,
+		Offset:     options.Offset,
+		HasMore:    options.Offset+len(entries) < totalCount,
+	}
+}
+
+// CapabilitiesMatrix shows which capabilities each server supports.
+// Matrix[i][j] is true if Servers[i] has Capabilities[j].
+type CapabilitiesMatrix struct {
+	Servers      []string `json:"servers"`
+	Capabilities []string `json:"capabilities"`
+	Matrix       [][]bool `json:"matrix"`
+}
+
 // NewServerRegistry creates a new server registry
 func NewServerRegistry(logger *zap.Logger, repo *repository.MCPServerRepository) *ServerRegistry {
 	return &ServerRegistry{
@@ -73,6 +121,36 @@ func NewServerRegistry(logger *zap.Logger, repo *repository.MCPServerRepository)
 	}
 }
 
+// SetHealthScoreCache makes registry entries carry the servers' latest health scores
+func (sr *ServerRegistry) SetHealthScoreCache(cache *HealthScoreCache) {
+	sr.scores = cache
+}
+
+// healthScore returns a server's latest health score, or the default if it has none
+func (sr *ServerRegistry) healthScore(ctx context.Context, serverID uuid.UUID) int {
+	if sr.scores == nil {
+		return defaultHealthScore
+	}
+	if score, ok := sr.scores.Get(ctx, serverID); ok {
+		return score
+	}
+	return defaultHealthScore
+}
+
+// loadHealthScores loads the health scores of the servers in one query so that
+// building their entries does not query per server
+func (sr *ServerRegistry) loadHealthScores(ctx context.Context, serverIDs []uuid.UUID) {
+	if sr.scores != nil {
+		sr.scores.Load(ctx, serverIDs)
+	}
+}
+
+// SetSearchRepository makes searches run as database queries instead of filtering every
+// server in memory
+func (sr *ServerRegistry) SetSearchRepository(repo *database.Repository) {
+	sr.search = repo
+}
+
 // RegisterServer registers a server in the registry
 func (sr *ServerRegistry) RegisterServer(ctx context.Context, server *models.MCPServer) error {
 	// Check if server already exists
@@ -101,20 +179,38 @@ func (sr *ServerRegistry) GetServer(ctx context.Context, serverID uuid.UUID) (*R
 		return nil, err
 	}
 
-	return sr.convertToRegistryEntry(server), nil
+	return sr.convertToRegistryEntry(ctx, server), nil
 }
 
-// SearchServers searches for servers in the registry
-func (sr *ServerRegistry) SearchServers(ctx context.Context, options RegistrySearchOptions) ([]*RegistryEntry, error) {
-	// Get all servers (in production, you'd implement proper database queries)
+// SearchServers returns a page of the servers in the registry matching the options,
+// with the total number of matches
+func (sr *ServerRegistry) SearchServers(ctx context.Context, options RegistrySearchOptions) (*SearchResult, error) {
+	if options.Limit <= 0 {
+		options.Limit = defaultSearchLimit
+	}
+	if options.Offset < 0 {
+		options.Offset = 0
+	}
+
+	if sr.search != nil {
+		return sr.searchDatabase(ctx, options)
+	}
+
+	// Without a database, filter every server in memory
 	servers, err := sr.repo.GetAllServers(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	serverIDs := make([]uuid.UUID, len(servers))
+	for i, server := range servers {
+		serverIDs[i] = server.ID
+	}
+	sr.loadHealthScores(ctx, serverIDs)
+
 	var entries []*RegistryEntry
 	for _, server := range servers {
-		entry := sr.convertToRegistryEntry(server)
+		entry := sr.convertToRegistryEntry(ctx, server)
 		entries = append(entries, entry)
 	}
 
@@ -123,11 +219,64 @@ func (sr *ServerRegistry) SearchServers(ctx context.Context, options RegistrySea
 
 	// Apply sorting
 	entries = sr.applySorting(entries, options)
+	totalCount := len(entries)
 
 	// Apply pagination
 	entries = sr.applyPagination(entries, options)
 
-	return entries, nil
+	return newSearchResult(entries, totalCount, options), nil
+}
+
+// searchDatabase runs a search as a database query for the page and another for the
+// total count
+func (sr *ServerRegistry) searchDatabase(ctx context.Context, options RegistrySearchOptions) (*SearchResult, error) {
+	entries := []*RegistryEntry{}
+
+	search := database.MCPServerSearch{
+		TextQuery:    options.TextQuery,
+		Query:        options.Query,
+		Type:         options.Type,
+		Status:       options.Status,
+		Capabilities: options.Capabilities,
+		Tags:         options.Tags,
+		SortBy:       options.SortBy,
+		SortOrder:    options.SortOrder,
+		Limit:        options.Limit,
+		Offset:       options.Offset,
+
+		MinHealthScore:     options.MinHealthScore,
+		DefaultHealthScore: defaultHealthScore,
+	}
+	if options.OrganizationID != "" {
+		orgID, err := uuid.Parse(options.OrganizationID)
+		if err != nil {
+			return newSearchResult(entries, 0, options), nil
+		}
+		search.OrganizationID = &orgID
+	}
+
+	servers, err := sr.search.SearchMCPServers(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+	totalCount, err := sr.search.CountMCPServerSearch(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+
+	serverIDs := make([]uuid.UUID, len(servers))
+	for i, server := range servers {
+		serverIDs[i] = server.ID
+	}
+	sr.loadHealthScores(ctx, serverIDs)
+
+	for _, server := range servers {
+		entry := databaseRegistryEntry(server)
+		entry.HealthScore = sr.healthScore(ctx, server.ID)
+		entries = append(entries, entry)
+	}
+
+	return newSearchResult(entries, totalCount, options), nil
 }
 
 // GetRegistryStats returns registry statistics
@@ -146,6 +295,12 @@ func (sr *ServerRegistry) GetRegistryStats(ctx context.Context) (*RegistryStats,
 	var totalHealth int
 	onlineCount := 0
 
+	serverIDs := make([]uuid.UUID, len(servers))
+	for i, server := range servers {
+		serverIDs[i] = server.ID
+	}
+	sr.loadHealthScores(ctx, serverIDs)
+
 	for _, server := range servers {
 		stats.TotalServers++
 
@@ -166,9 +321,7 @@ func (sr *ServerRegistry) GetRegistryStats(ctx context.Context) (*RegistryStats,
 		orgID := server.OrganizationID.String()
 		stats.Organizations[orgID]++
 
-		// Calculate average health (mock for now)
-		healthScore := 85 // In production, get from health monitoring
-		totalHealth += healthScore
+		totalHealth += sr.healthScore(ctx, server.ID)
 	}
 
 	stats.OnlineServers = onlineCount
@@ -180,12 +333,17 @@ func (sr *ServerRegistry) GetRegistryStats(ctx context.Context) (*RegistryStats,
 	return stats, nil
 }
 
-// GetServersByType returns servers filtered by type
-func (sr *ServerRegistry) GetServersByType(ctx context.Context, serverType string) ([]*RegistryEntry, error) {
+// GetServersByType returns an organization's servers filtered by type
+func (sr *ServerRegistry) GetServersByType(ctx context.Context, organizationID uuid.UUID, serverType string) ([]*RegistryEntry, error) {
 	options := RegistrySearchOptions{
-		Type: serverType,
+		Type:           serverType,
+		OrganizationID: organizationID.String(),
 	}
-	return sr.SearchServers(ctx, options)
+	result, err := sr.SearchServers(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	return result.Entries, nil
 }
 
 // GetServersByOrganization returns servers for a specific organization
@@ -193,15 +351,24 @@ func (sr *ServerRegistry) GetServersByOrganization(ctx context.Context, organiza
 	options := RegistrySearchOptions{
 		OrganizationID: organizationID.String(),
 	}
-	return sr.SearchServers(ctx, options)
+	result, err := sr.SearchServers(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	return result.Entries, nil
 }
 
-// GetServersByCapability returns servers that have a specific capability
-func (sr *ServerRegistry) GetServersByCapability(ctx context.Context, capability string) ([]*RegistryEntry, error) {
+// GetServersByCapability returns an organization's servers that have a specific capability
+func (sr *ServerRegistry) GetServersByCapability(ctx context.Context, organizationID uuid.UUID, capability string) ([]*RegistryEntry, error) {
 	options := RegistrySearchOptions{
-		Capabilities: []string{capability},
+		Capabilities:   []string{capability},
+		OrganizationID: organizationID.String(),
+	}
+	result, err := sr.SearchServers(ctx, options)
+	if err != nil {
+		return nil, err
 	}
-	return sr.SearchServers(ctx, options)
+	return result.Entries, nil
 }
 
 // UpdateServerHealth updates server health information
@@ -252,6 +419,56 @@ func (sr *ServerRegistry) GetServerCapabilities(ctx context.Context) ([]string,
 	return capabilities, nil
 }
 
+// GetCapabilitiesMatrix builds a capabilities matrix for the given servers. Declared
+// capabilities are combined with discovered tools, which appear as "tool:<name>".
+func (sr *ServerRegistry) GetCapabilitiesMatrix(ctx context.Context, serverIDs []uuid.UUID, toolsByServer map[uuid.UUID][]string) (*CapabilitiesMatrix, error) {
+	matrix := &CapabilitiesMatrix{
+		Servers:      make([]string, 0, len(serverIDs)),
+		Capabilities: []string{},
+		Matrix:       make([][]bool, 0, len(serverIDs)),
+	}
+
+	serverCapabilities := make([]map[string]bool, 0, len(serverIDs))
+	capabilitySet := make(map[string]bool)
+
+	for _, serverID := range serverIDs {
+		server, err := sr.repo.GetServerByID(serverID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get server %s: %w", serverID, err)
+		}
+
+		capabilities := make(map[string]bool)
+		for _, capability := range server.Capabilities {
+			capabilities[capability] = true
+		}
+		for _, tool := range toolsByServer[serverID] {
+			capabilities["tool:"+tool] = true
+		}
+
+		for capability := range capabilities {
+			capabilitySet[capability] = true
+		}
+
+		matrix.Servers = append(matrix.Servers, serverID.String())
+		serverCapabilities = append(serverCapabilities, capabilities)
+	}
+
+	for capability := range capabilitySet {
+		matrix.Capabilities = append(matrix.Capabilities, capability)
+	}
+	sort.Strings(matrix.Capabilities)
+
+	for _, capabilities := range serverCapabilities {
+		row := make([]bool, len(matrix.Capabilities))
+		for j, capability := range matrix.Capabilities {
+			row[j] = capabilities[capability]
+		}
+		matrix.Matrix = append(matrix.Matrix, row)
+	}
+
+	return matrix, nil
+}
+
 // GetServerTypes returns all unique server types in the registry
 func (sr *ServerRegistry) GetServerTypes(ctx context.Context) ([]string, error) {
 	servers, err := sr.repo.GetAllServers(ctx)
@@ -273,8 +490,58 @@ func (sr *ServerRegistry) GetServerTypes(ctx context.Context) ([]string, error)
 	return types, nil
 }
 
+// databaseRegistryEntry converts a server row to a registry entry
+func databaseRegistryEntry(server *database.MCPServer) *RegistryEntry {
+	entry := &RegistryEntry{
+		ID:             server.ID,
+		Name:           server.Name,
+		URL:            server.URL,
+		Type:           server.Type,
+		Status:         server.Status,
+		Capabilities:   []string{},
+		OrganizationID: server.OrganizationID,
+		HealthScore:    defaultHealthScore,
+		Metadata:       server.Metadata,
+		CreatedAt:      server.CreatedAt,
+		UpdatedAt:      server.UpdatedAt,
+	}
+
+	if server.Description != nil {
+		entry.Description = *server.Description
+	}
+	if server.Version != nil {
+		entry.Version = *server.Version
+	}
+	for _, capability := range server.Capabilities {
+		if name, ok := capability.(string); ok {
+			entry.Capabilities = append(entry.Capabilities, name)
+		}
+	}
+	if server.ResponseTimeMs != nil {
+		entry.ResponseTime = int64(*server.ResponseTimeMs)
+	}
+	if server.TCPLatencyMs != nil {
+		entry.NetworkLatency = int64(*server.TCPLatencyMs)
+	}
+	if server.UptimePercentage != nil {
+		entry.Uptime = *server.UptimePercentage
+	}
+	if server.LastCheckedAt != nil {
+		entry.LastSeen = *server.LastCheckedAt
+	}
+	if tags, ok := server.Metadata["tags"].([]interface{}); ok {
+		for _, tag := range tags {
+			if tagStr, ok := tag.(string); ok {
+				entry.Tags = append(entry.Tags, tagStr)
+			}
+		}
+	}
+
+	return entry
+}
+
 // convertToRegistryEntry converts a database server model to a registry entry
-func (sr *ServerRegistry) convertToRegistryEntry(server *models.MCPServer) *RegistryEntry {
+func (sr *ServerRegistry) convertToRegistryEntry(ctx context.Context, server *models.MCPServer) *RegistryEntry {
 	entry := &RegistryEntry{
 		ID:             server.ID,
 		Name:           server.Name,
@@ -290,9 +557,10 @@ func (sr *ServerRegistry) convertToRegistryEntry(server *models.MCPServer) *Regi
 		Metadata:       server.Metadata,
 	}
 
-	// Set health-related fields (mock for now)
-	entry.HealthScore = 85
+	// Set health-related fields
+	entry.HealthScore = sr.healthScore(ctx, server.ID)
 	entry.ResponseTime = server.ResponseTime
+	entry.NetworkLatency = server.TCPLatencyMs
 	entry.Uptime = server.UptimePercentage
 	entry.LastSeen = server.LastCheckedAt
 
@@ -321,6 +589,11 @@ func (sr *ServerRegistry) applyFilters(entries []*RegistryEntry, options Registr
 			}
 		}
 
+		// Apply full-text filter
+		if options.TextQuery != "" && !matchesTextQuery(entry, options.TextQuery) {
+			continue
+		}
+
 		// Apply type filter
 		if options.Type != "" && entry.Type != options.Type {
 			continue
@@ -420,15 +693,27 @@ func (sr *ServerRegistry) applyPagination(entries []*RegistryEntry, options Regi
 	return entries[start:end]
 }
 
-// matchesQuery checks if an entry matches a search query
+// matchesQuery checks if an entry's name, description, URL or type contains a search
+// query, ignoring case
 func (sr *ServerRegistry) matchesQuery(entry *RegistryEntry, query string) bool {
-	query = fmt.Sprintf("%s", query)
 	return contains(entry.Name, query) ||
 		contains(entry.Description, query) ||
 		contains(entry.URL, query) ||
 		contains(entry.Type, query)
 }
 
+// matchesTextQuery checks if an entry's name, description and type contain every word
+// of a full-text query, ignoring case
+func matchesTextQuery(entry *RegistryEntry, textQuery string) bool {
+	text := strings.ToLower(entry.Name + " " + entry.Description + " " + entry.Type)
+	for _, word := range strings.Fields(strings.ToLower(textQuery)) {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
+
 // hasAnyCapability checks if an entry has any of the specified capabilities
 func (sr *ServerRegistry) hasAnyCapability(entry *RegistryEntry, capabilities []string) bool {
 	for _, capability := range capabilities {
@@ -455,20 +740,5 @@ func (sr *ServerRegistry) hasAnyTag(entry *RegistryEntry, tags []string) bool {
 
 // contains checks if a string contains a substring (case-insensitive)
 func contains(s, substr string) bool {
-	return len(s) >= len(substr) &&
-		(s == substr ||
-			(len(s) > len(substr) &&
-				(s[:len(substr)] == substr ||
-					s[len(s)-len(substr):] == substr ||
-					containsSubstring(s, substr))))
-}
-
-// containsSubstring checks if a string contains a substring
-func containsSubstring(s, substr string) bool {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return true
-		}
-	}
-	return false
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
 }
@@ -0,0 +1,174 @@
+package registry
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestMatchesQuery(t *testing.T) {
+	sr := &ServerRegistry{}
+	entry := &RegistryEntry{
+		Name:        "filesystem",
+		Description: "Read and write local files",
+		URL:         "https://mcp.example.com/fs",
+		Type:        "stdio",
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  bool
+	}{
+		{"name", "files", true},
+		{"upper case query", "FILE", true},
+		{"mixed case description", "Local Files", true},
+		{"url", "example.com", true},
+		{"type", "STDIO", true},
+		{"empty query", "", true},
+		{"no match", "database", false},
+		{"across fields", "filesystem read", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sr.matchesQuery(entry, tt.query); got != tt.want {
+				t.Errorf("matchesQuery(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+// FuzzMatchesQuery checks that every part of an entry's name is found, whatever its
+// case, that a query never matches when none of the fields contain it, and that any
+// query the previous byte-wise matcher accepted still matches
+func FuzzMatchesQuery(f *testing.F) {
+	f.Add("filesystem", "Read local files", "https://mcp.example.com/fs", "stdio", "FILE", 0, 4)
+	f.Add("GitHub", "Issues and pull requests", "https://api.github.com", "http", "pull", 2, 6)
+	f.Add("Résumé parser", "Extrait les données", "", "", "données", 1, 5)
+	f.Add("", "", "", "", "", 0, 0)
+
+	sr := &ServerRegistry{}
+	f.Fuzz(func(t *testing.T, name, description, url, typ, query string, start, end int) {
+		entry := &RegistryEntry{Name: name, Description: description, URL: url, Type: typ}
+
+		// The old matcher compared bytes, so it also matched fragments of multi-byte
+		// characters; only whole-character queries must keep matching
+		if utf8.ValidString(query) && oldMatchesQuery(entry, query) && !sr.matchesQuery(entry, query) {
+			t.Errorf("matchesQuery(%q) = false, old matcher = true for entry %+v", query, entry)
+		}
+
+		if !utf8.ValidString(name) || !utf8.ValidString(description) {
+			t.Skip()
+		}
+
+		runes := []rune(name)
+		if start < 0 || end < start || end > len(runes) {
+			start, end = 0, len(runes)
+		}
+		part := string(runes[start:end])
+
+		if !sr.matchesQuery(entry, part) {
+			t.Errorf("matchesQuery(%q) of name %q = false, want true", part, name)
+		}
+		if oldMatchesQuery(entry, part) && !sr.matchesQuery(entry, part) {
+			t.Errorf("matchesQuery(%q) = false, old matcher = true for entry %+v", part, entry)
+		}
+		if isASCII(part) {
+			for _, variant := range []string{strings.ToUpper(part), strings.ToLower(part)} {
+				if !sr.matchesQuery(entry, variant) {
+					t.Errorf("matchesQuery(%q) of name %q = false, want true", variant, name)
+				}
+			}
+		}
+
+		// A query no field contains cannot match
+		missing := name + description + url + typ + "\x00"
+		if sr.matchesQuery(entry, missing) {
+			t.Errorf("matchesQuery(%q) of entry %+v = true, want false", missing, entry)
+		}
+	})
+}
+
+// oldMatchesQuery is matchesQuery as it was before it ignored case, kept as an oracle:
+// every query it matched must still match
+func oldMatchesQuery(entry *RegistryEntry, query string) bool {
+	return oldContains(entry.Name, query) ||
+		oldContains(entry.Description, query) ||
+		oldContains(entry.URL, query) ||
+		oldContains(entry.Type, query)
+}
+
+// oldContains is the previous contains, which compared bytes exactly
+func oldContains(s, substr string) bool {
+	return len(s) >= len(substr) &&
+		(s == substr ||
+			(len(s) > len(substr) &&
+				(s[:len(substr)] == substr ||
+					s[len(s)-len(substr):] == substr ||
+					oldContainsSubstring(s, substr))))
+}
+
+// oldContainsSubstring is the previous containsSubstring
+func oldContainsSubstring(s, substr string) bool {
+	for i := 0; i <= len(s)-len(substr); i++ {
+		if s[i:i+len(substr)] == substr {
+			return true
+		}
+	}
+	return false
+}
+
+// isASCII reports whether s has only ASCII characters, whose case mapping is reversible
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// BenchmarkSearchInMemory filters, sorts and pages 10,000 entries as SearchServers does
+// without a search repository. The database search is benchmarked in
+// internal/database with the integration tag.
+func BenchmarkSearchInMemory(b *testing.B) {
+	entries := make([]*RegistryEntry, 10000)
+	for i := range entries {
+		entries[i] = &RegistryEntry{
+			Name:        fmt.Sprintf("service-%d", i),
+			Description: "Answers questions about tickets",
+			URL:         fmt.Sprintf("http://server-%d.invalid", i),
+			Type:        "custom",
+			Status:      "online",
+		}
+		if i%10 == 0 {
+			entries[i].Name = fmt.Sprintf("filesystem-%d", i)
+			entries[i].Description = "Reads and writes local files"
+		}
+	}
+
+	sr := &ServerRegistry{}
+	searches := []struct {
+		name    string
+		options RegistrySearchOptions
+	}{
+		{"text query", RegistrySearchOptions{TextQuery: "local files"}},
+		{"substring query", RegistrySearchOptions{Query: "FILESYSTEM-"}},
+		{"sorted by name", RegistrySearchOptions{Status: "online", SortBy: "name"}},
+	}
+
+	for _, s := range searches {
+		options := s.options
+		options.Limit = 50
+
+		b.Run(s.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				filtered := sr.applyFilters(entries, options)
+				filtered = sr.applySorting(filtered, options)
+				sr.applyPagination(filtered, options)
+			}
+		})
+	}
+}
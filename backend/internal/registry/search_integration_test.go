@@ -0,0 +1,114 @@
+//go:build integration
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database/dbtest"
+	"go.uber.org/zap"
+)
+
+// TestSearchServersPagination pages through 200 servers and checks that every server is
+// returned exactly once with the total count and paging details of each page
+func TestSearchServersPagination(t *testing.T) {
+	ctx := context.Background()
+	repo := dbtest.Repository(t)
+
+	org := dbtest.CreateOrganization(t, repo)
+	user := dbtest.CreateUser(t, repo, org.ID, "admin")
+
+	const serverCount = 200
+	for i := 0; i < serverCount; i++ {
+		name := fmt.Sprintf("odd-server-%03d", i)
+		if i%2 == 0 {
+			name = fmt.Sprintf("even-server-%03d", i)
+		}
+		dbtest.CreateServer(t, repo, org.ID, user.ID, name)
+	}
+
+	sr := NewServerRegistry(zap.NewNop(), nil)
+	sr.SetSearchRepository(repo)
+
+	tests := []struct {
+		name  string
+		query string
+		limit int
+		want  int
+	}{
+		{"all servers", "", 50, serverCount},
+		{"uneven last page", "", 30, serverCount},
+		{"filtered", "even-server", 40, serverCount / 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen := make(map[uuid.UUID]bool)
+			previous := ""
+
+			for offset := 0; ; offset += tt.limit {
+				result, err := sr.SearchServers(ctx, RegistrySearchOptions{
+					Query:          tt.query,
+					OrganizationID: org.ID.String(),
+					SortBy:         "name",
+					SortOrder:      "asc",
+					Limit:          tt.limit,
+					Offset:         offset,
+				})
+				if err != nil {
+					t.Fatalf("SearchServers(offset %d) error = %v", offset, err)
+				}
+
+				if result.TotalCount != tt.want {
+					t.Errorf("SearchServers(offset %d) total count = %d, want %d", offset, result.TotalCount, tt.want)
+				}
+				if result.Limit != tt.limit || result.Offset != offset {
+					t.Errorf("SearchServers(offset %d) limit, offset = %d, %d, want %d, %d",
+						offset, result.Limit, result.Offset, tt.limit, offset)
+				}
+				if wantHasMore := offset+tt.limit < tt.want; result.HasMore != wantHasMore {
+					t.Errorf("SearchServers(offset %d) has more = %v, want %v", offset, result.HasMore, wantHasMore)
+				}
+
+				for _, entry := range result.Entries {
+					if seen[entry.ID] {
+						t.Errorf("server %s returned on more than one page", entry.Name)
+					}
+					seen[entry.ID] = true
+					if entry.Name < previous {
+						t.Errorf("server %s returned after %s, want ascending names", entry.Name, previous)
+					}
+					previous = entry.Name
+				}
+
+				if !result.HasMore {
+					break
+				}
+				if len(result.Entries) != tt.limit {
+					t.Fatalf("SearchServers(offset %d) returned %d entries before the last page, want %d",
+						offset, len(result.Entries), tt.limit)
+				}
+			}
+
+			if len(seen) != tt.want {
+				t.Errorf("pages returned %d servers, want %d", len(seen), tt.want)
+			}
+		})
+	}
+
+	result, err := sr.SearchServers(ctx, RegistrySearchOptions{
+		OrganizationID: org.ID.String(),
+		Limit:          50,
+		Offset:         serverCount,
+	})
+	if err != nil {
+		t.Fatalf("SearchServers() past the end error = %v", err)
+	}
+	if len(result.Entries) != 0 || result.HasMore || result.TotalCount != serverCount {
+		t.Errorf("SearchServers() past the end = %d entries, has more %v, total %d, want 0, false, %d",
+			len(result.Entries), result.HasMore, result.TotalCount, serverCount)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	apperrors "github.com/radhi1991/aran-mcp-sentinel/internal/errors"
 	"github.com/radhi1991/aran-mcp-sentinel/internal/models"
 	"github.com/radhi1991/aran-mcp-sentinel/internal/supabase"
 )
@@ -65,7 +66,7 @@ func (r *MCPServerRepository) GetServer(ctx context.Context, id uuid.UUID) (*mod
 	}
 
 	if len(result) == 0 {
-		return nil, errors.New("server not found")
+		return nil, &apperrors.NotFoundError{Resource: "server", ID: idStr}
 	}
 
 	return &result[0], nil
@@ -0,0 +1,169 @@
+// Package scheduler runs background jobs on cron schedules
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrStopTimeout is returned by Stop when running jobs outlast its context
+var ErrStopTimeout = errors.New("scheduled jobs did not finish before the stop deadline")
+
+// cronJob is a job registered with AddCronJob
+type cronJob struct {
+	name     string
+	expr     string
+	schedule *cronSchedule
+	fn       func(context.Context)
+}
+
+// Scheduler runs named jobs on cron schedules. A job does not overlap with itself: a
+// run that is due while the previous one is still going is skipped.
+type Scheduler struct {
+	logger *zap.Logger
+
+	mu       sync.Mutex
+	jobs     []*cronJob
+	started  bool
+	stopping bool
+
+	stopScheduling context.CancelFunc // stops starting new runs
+	cancelRuns     context.CancelFunc // cancels the runs in progress
+	wg             sync.WaitGroup
+}
+
+// New creates a scheduler
+func New(logger *zap.Logger) *Scheduler {
+	return &Scheduler{logger: logger}
+}
+
+// AddCronJob registers fn to run on a standard five-field cron expression in local time.
+// Jobs must be added before Start.
+func (s *Scheduler) AddCronJob(name, cronExpr string, fn func(context.Context)) error {
+	schedule, err := parseCron(cronExpr)
+	if err != nil {
+		return fmt.Errorf("invalid schedule for job %s: %w", name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return fmt.Errorf("cannot add job %s to a running scheduler", name)
+	}
+	for _, job := range s.jobs {
+		if job.name == name {
+			return fmt.Errorf("job %s already exists", name)
+		}
+	}
+
+	s.jobs = append(s.jobs, &cronJob{name: name, expr: cronExpr, schedule: schedule, fn: fn})
+	return nil
+}
+
+// Start runs the registered jobs on their schedules until Stop is called or the context
+// is cancelled. Jobs get a context that is cancelled only if they outlast Stop.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return
+	}
+	s.started = true
+
+	scheduleCtx, stopScheduling := context.WithCancel(ctx)
+	runCtx, cancelRuns := context.WithCancel(context.Background())
+	s.stopScheduling = stopScheduling
+	s.cancelRuns = cancelRuns
+
+	for _, job := range s.jobs {
+		s.logger.Info("Scheduled job", zap.String("job", job.name), zap.String("schedule", job.expr))
+		go s.loop(scheduleCtx, runCtx, job)
+	}
+}
+
+// Stop stops starting new runs and waits for the running ones. If they do not finish
+// before ctx is done, they are cancelled and ErrStopTimeout is returned.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	started := s.started
+	s.stopping = true
+	s.mu.Unlock()
+	if !started {
+		return nil
+	}
+
+	s.stopScheduling()
+
+	finished := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		s.cancelRuns()
+		return nil
+	case <-ctx.Done():
+		s.cancelRuns()
+		return ErrStopTimeout
+	}
+}
+
+// loop waits for each scheduled time of a job and runs it
+func (s *Scheduler) loop(scheduleCtx, runCtx context.Context, job *cronJob) {
+	for {
+		next, err := job.schedule.next(time.Now())
+		if err != nil {
+			s.logger.Error("Job will not run again", zap.String("job", job.name), zap.Error(err))
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-scheduleCtx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if !s.beginRun() {
+			return
+		}
+		s.run(runCtx, job)
+	}
+}
+
+// beginRun registers a run for Stop to wait for, unless Stop has been called
+func (s *Scheduler) beginRun() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopping {
+		return false
+	}
+	s.wg.Add(1)
+	return true
+}
+
+// run runs a job once, recovering from panics so the job keeps its schedule
+func (s *Scheduler) run(ctx context.Context, job *cronJob) {
+	defer s.wg.Done()
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			s.logger.Error("Scheduled job panicked", zap.String("job", job.name), zap.Any("panic", recovered))
+		}
+	}()
+
+	start := time.Now()
+	s.logger.Info("Running scheduled job", zap.String("job", job.name))
+	job.fn(ctx)
+	s.logger.Info("Finished scheduled job", zap.String("job", job.name), zap.Duration("duration", time.Since(start)))
+}
@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/log"
+	"go.uber.org/zap"
 )
 
 // AnalyzePromptRequest represents a prompt injection analysis request
@@ -22,7 +24,20 @@ func (h *Handler) AnalyzePrompt(c *gin.Context) {
 		return
 	}
 
-	result := h.promptDetector.AnalyzePrompt(req.Prompt)
+	result := h.promptDetector.Analyze(req.Prompt)
+
+	if h.promptDetector.IsInjection(result) {
+		log.FromCtx(c.Request.Context()).Warn("Prompt injection detected",
+			zap.Float64("score", result.Score),
+			zap.Strings("patterns", result.Patterns),
+		)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"success": false,
+			"error":   "Prompt injection detected",
+			"data":    result,
+		})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -58,8 +73,9 @@ func (h *Handler) AnalyzeBehavior(c *gin.Context) {
 
 // ScanCredentialsRequest represents a credential scan request
 type ScanCredentialsRequest struct {
-	Text   string                 `json:"text"`
-	Params map[string]interface{} `json:"params"`
+	Content string                 `json:"content"`
+	Text    string                 `json:"text"`
+	Params  map[string]interface{} `json:"params"`
 }
 
 // ScanCredentials scans for exposed credentials
@@ -73,6 +89,18 @@ func (h *Handler) ScanCredentials(c *gin.Context) {
 		return
 	}
 
+	if req.Content != "" {
+		masked, findings := h.credentialScanner.MaskCredentials(req.Content)
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"data": gin.H{
+				"findings":       findings,
+				"masked_content": masked,
+			},
+		})
+		return
+	}
+
 	var result *ScanResult
 	if req.Text != "" {
 		result = h.credentialScanner.ScanText(req.Text)
@@ -81,7 +109,7 @@ func (h *Handler) ScanCredentials(c *gin.Context) {
 	} else {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
-			"error":   "either text or params must be provided",
+			"error":   "content, text or params must be provided",
 		})
 		return
 	}
@@ -1,8 +1,11 @@
 package security
 
 import (
+	"strings"
 	"sync"
 	"time"
+
+	"go.uber.org/zap"
 )
 
 // BehavioralAnalyzer detects anomalous MCP agent behavior
@@ -10,6 +13,12 @@ type BehavioralAnalyzer struct {
 	mu               sync.RWMutex
 	agentProfiles    map[string]*AgentProfile
 	anomalyThreshold float64
+
+	// RetentionPeriod removes profiles not seen for longer than this during cleanup; 0 keeps them
+	RetentionPeriod time.Duration
+
+	store  ProfileStore
+	logger *zap.Logger
 }
 
 // AgentProfile tracks agent behavior patterns
@@ -52,8 +61,20 @@ func NewBehavioralAnalyzer() *BehavioralAnalyzer {
 	}
 }
 
-// AnalyzeAgentBehavior analyzes agent behavior for anomalies
+// AnalyzeAgentBehavior analyzes agent behavior for anomalies. With a profile store set,
+// the profile is loaded on first use and written back after every analysis.
 func (ba *BehavioralAnalyzer) AnalyzeAgentBehavior(agentID string, toolName string, params map[string]interface{}) *BehavioralAnalysisResult {
+	ba.loadProfile(agentID)
+
+	result, snapshot := ba.analyze(agentID, toolName, params)
+
+	ba.saveProfile(snapshot)
+
+	return result
+}
+
+// analyze updates an agent's profile and returns the result with a copy of the profile
+func (ba *BehavioralAnalyzer) analyze(agentID string, toolName string, params map[string]interface{}) (*BehavioralAnalysisResult, *AgentProfile) {
 	ba.mu.Lock()
 	defer ba.mu.Unlock()
 
@@ -131,7 +152,7 @@ func (ba *BehavioralAnalyzer) AnalyzeAgentBehavior(agentID string, toolName stri
 	profile.Anomalies = append(profile.Anomalies, anomalies...)
 	profile.SuspiciousActions += len(anomalies)
 
-	return result
+	return result, profile.clone()
 }
 
 // detectAnomalies detects various types of behavioral anomalies
@@ -295,11 +316,9 @@ func (ba *BehavioralAnalyzer) GetAllProfiles() []*AgentProfile {
 	return profiles
 }
 
-// Helper function
+// contains reports whether substr is within str, ignoring case
 func contains(str, substr string) bool {
-	return len(str) > 0 && len(substr) > 0 &&
-		(str == substr ||
-			time.Now().Unix() > 0) // Placeholder for actual substring check
+	return strings.Contains(strings.ToLower(str), strings.ToLower(substr))
 }
 
 
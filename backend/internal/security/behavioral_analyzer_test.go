@@ -0,0 +1,149 @@
+package security
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIsPrivilegeEscalation(t *testing.T) {
+	ba := NewBehavioralAnalyzer()
+
+	tests := []struct {
+		name     string
+		toolName string
+		params   map[string]interface{}
+		want     bool
+	}{
+		{"admin tool", "admin_panel", nil, true},
+		{"uppercase keyword", "SUDO_EXEC", nil, true},
+		{"mixed case keyword", "ElevateUser", nil, true},
+		{"keyword in parameter", "run_command", map[string]interface{}{"cmd": "sudo rm -rf /"}, true},
+		{"uppercase keyword in parameter", "set_user", map[string]interface{}{"user": "ROOT"}, true},
+		{"plain tool", "read_file", nil, false},
+		{"plain parameters", "read_file", map[string]interface{}{"path": "/tmp/notes.txt"}, false},
+		{"non-string parameter", "read_file", map[string]interface{}{"privilege": 3}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ba.isPrivilegeEscalation(tt.toolName, tt.params); got != tt.want {
+				t.Errorf("isPrivilegeEscalation(%q, %v) = %v, want %v", tt.toolName, tt.params, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsDataExfiltration(t *testing.T) {
+	ba := NewBehavioralAnalyzer()
+
+	tests := []struct {
+		name     string
+		toolName string
+		params   map[string]interface{}
+		want     bool
+	}{
+		{"dump tool", "db_dump", nil, true},
+		{"uppercase keyword", "EXPORT_USERS", nil, true},
+		{"mixed case keyword", "DownloadFile", nil, true},
+		{"backup tool", "create_backup", nil, true},
+		{"plain tool", "list_files", nil, false},
+		{"keyword only in parameter", "read_file", map[string]interface{}{"path": "dump.sql"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ba.isDataExfiltration(tt.toolName, tt.params); got != tt.want {
+				t.Errorf("isDataExfiltration(%q) = %v, want %v", tt.toolName, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectAnomalies(t *testing.T) {
+	ba := NewBehavioralAnalyzer()
+
+	tests := []struct {
+		name     string
+		profile  *AgentProfile
+		toolName string
+		params   map[string]interface{}
+		want     []string
+	}{
+		{
+			name:     "new agent using a plain tool",
+			profile:  newTestProfile(time.Hour, 1, map[string]int{"read_file": 1}),
+			toolName: "read_file",
+			want:     nil,
+		},
+		{
+			name:     "established agent using a familiar tool",
+			profile:  newTestProfile(time.Hour, 50, map[string]int{"read_file": 50}),
+			toolName: "read_file",
+			want:     nil,
+		},
+		{
+			name:     "established agent using a new sensitive tool",
+			profile:  newTestProfile(time.Hour, 50, map[string]int{"read_file": 49, "Database_Query": 1}),
+			toolName: "Database_Query",
+			want:     []string{"unusual_tool_access"},
+		},
+		{
+			name:     "burst of requests in the first minute",
+			profile:  newTestProfile(10*time.Second, 25, map[string]int{"read_file": 25}),
+			toolName: "read_file",
+			want:     []string{"rapid_request_rate"},
+		},
+		{
+			name:     "privilege escalation through parameters",
+			profile:  newTestProfile(time.Hour, 1, map[string]int{"run_command": 1}),
+			toolName: "run_command",
+			params:   map[string]interface{}{"cmd": "SUDO su"},
+			want:     []string{"privilege_escalation"},
+		},
+		{
+			name:     "data exfiltration tool",
+			profile:  newTestProfile(time.Hour, 1, map[string]int{"Export_Table": 1}),
+			toolName: "Export_Table",
+			want:     []string{"data_exfiltration"},
+		},
+		{
+			name: "many tools in few requests",
+			profile: newTestProfile(time.Hour, 6, map[string]int{
+				"a": 1, "b": 1, "c": 1, "d": 1, "e": 1, "f": 1,
+			}),
+			toolName: "f",
+			want:     []string{"tool_chain_abuse"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			anomalies := ba.detectAnomalies(tt.profile, tt.toolName, tt.params)
+
+			var got []string
+			for _, anomaly := range anomalies {
+				got = append(got, anomaly.Type)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("detectAnomalies() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("detectAnomalies() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// newTestProfile builds a profile first seen age ago with the given request counts
+func newTestProfile(age time.Duration, totalRequests int, usage map[string]int) *AgentProfile {
+	return &AgentProfile{
+		AgentID:          "agent-1",
+		FirstSeen:        time.Now().Add(-age),
+		LastSeen:         time.Now(),
+		TotalRequests:    totalRequests,
+		ToolUsagePattern: usage,
+		TrustScore:       100.0,
+	}
+}
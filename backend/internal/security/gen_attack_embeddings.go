@@ -0,0 +1,25 @@
+//go:build ignore
+
+// gen_attack_embeddings writes data/attack_embeddings.bin from data/attack_phrases.txt
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/radhi1991/aran-mcp-sentinel/internal/security"
+)
+
+func main() {
+	phrases := security.AttackPhrases()
+
+	vectors := make([][]float32, len(phrases))
+	for i, phrase := range phrases {
+		vectors[i] = security.PromptEmbedding(phrase)
+	}
+
+	if err := os.WriteFile("data/attack_embeddings.bin", security.EncodeEmbeddings(vectors), 0o644); err != nil {
+		log.Fatalf("failed to write attack embeddings: %v", err)
+	}
+	log.Printf("wrote embeddings for %d attack phrases", len(phrases))
+}
@@ -6,12 +6,16 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/auth"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/log"
 	"go.uber.org/zap"
 )
 
 // Handler handles security testing endpoints
 type Handler struct {
 	logger             *zap.Logger
+	repo               *database.Repository
 	securityTester     *SecurityTester
 	owaspManager       *OWASPMCPTop10Manager
 	promptDetector     *PromptInjectionDetector
@@ -31,21 +35,42 @@ func NewHandler(logger *zap.Logger) *Handler {
 	}
 }
 
+// LoadOWASPPlugins loads third-party OWASP MCP Top 10 test plugins from a directory
+func (h *Handler) LoadOWASPPlugins(dir string) error {
+	return h.owaspManager.LoadPlugins(dir)
+}
+
+// SetRepository enables storing OWASP MCP Top 10 runs and their score history
+func (h *Handler) SetRepository(repo *database.Repository) {
+	h.repo = repo
+}
+
+// PromptDetector returns the prompt injection detector used by AnalyzePrompt
+func (h *Handler) PromptDetector() *PromptInjectionDetector {
+	return h.promptDetector
+}
+
+// OWASPManager returns the OWASP MCP Top 10 manager, including loaded plugins
+func (h *Handler) OWASPManager() *OWASPMCPTop10Manager {
+	return h.owaspManager
+}
+
 // RegisterRoutes registers security testing routes
 func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
 	security := r.Group("/security")
 	{
 		security.GET("/tests", h.ListTestTypes)
-		security.POST("/tests/run", h.RunSecurityTest)
+		security.POST("/tests/run", auth.RBACMiddleware(auth.RoleUser, h.logger), h.RunSecurityTest)
 		security.GET("/tests/:id", h.GetTestResult)
 		security.GET("/tests/server/:serverId", h.GetServerTests)
 
 		// OWASP MCP Top 10 endpoints
 		security.GET("/owasp/categories", h.GetOWASPMCPCategories)
 		security.GET("/owasp/tests", h.GetOWASPMCPTests)
-		security.POST("/owasp/tests/run", h.RunOWASPMCPTest)
-		security.POST("/owasp/tests/run-all", h.RunAllOWASPMCPTests)
+		security.POST("/owasp/tests/run", auth.RBACMiddleware(auth.RoleUser, h.logger), h.RunOWASPMCPTest)
+		security.POST("/owasp/tests/run-all", auth.RBACMiddleware(auth.RoleUser, h.logger), h.RunAllOWASPMCPTests)
 		security.GET("/owasp/results/:serverId", h.GetOWASPMCPResults)
+		security.GET("/owasp/history/:serverId", h.GetOWASPMCPHistory)
 
 		// 2025 Security Innovation Features
 		security.POST("/analyze/prompt", h.AnalyzePrompt)
@@ -94,7 +119,7 @@ func (h *Handler) RunSecurityTest(c *gin.Context) {
 	// Run the security test
 	test, err := h.securityTester.RunSecurityTest(c.Request.Context(), req.ServerURL, req.TestType)
 	if err != nil {
-		h.logger.Error("Failed to run security test", zap.Error(err))
+		log.FromCtx(c.Request.Context()).Error("Failed to run security test", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to run security test",
 		})
@@ -249,7 +274,7 @@ func (h *Handler) RunOWASPMCPTest(c *gin.Context) {
 
 	result, err := h.owaspManager.RunSecurityTest(c.Request.Context(), req.TestID, req.ServerID)
 	if err != nil {
-		h.logger.Error("Failed to run OWASP MCP Top 10 test", zap.Error(err))
+		log.FromCtx(c.Request.Context()).Error("Failed to run OWASP MCP Top 10 test", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to run security test",
 		})
@@ -280,13 +305,15 @@ func (h *Handler) RunAllOWASPMCPTests(c *gin.Context) {
 
 	results, err := h.owaspManager.RunAllTests(c.Request.Context(), req.ServerID)
 	if err != nil {
-		h.logger.Error("Failed to run all OWASP MCP Top 10 tests", zap.Error(err))
+		log.FromCtx(c.Request.Context()).Error("Failed to run all OWASP MCP Top 10 tests", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to run security tests",
 		})
 		return
 	}
 
+	h.storeOWASPResults(c, req.ServerID, results)
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data":    results,
@@ -0,0 +1,101 @@
+package security
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"go.uber.org/zap"
+)
+
+// DefaultOWASPScoreThreshold is the average OWASP MCP Top 10 score below which the
+// nightly scan raises an alert
+const DefaultOWASPScoreThreshold = 70
+
+// OWASPScoreAlerter raises an alert for a server whose OWASP MCP Top 10 score is below
+// the threshold
+type OWASPScoreAlerter interface {
+	AlertOWASPScore(serverID uuid.UUID, score, threshold int)
+}
+
+// NightlyOWASPScan runs the OWASP MCP Top 10 tests against every registered server,
+// stores each server's results as a run and alerts on servers scoring below Threshold
+type NightlyOWASPScan struct {
+	manager   *OWASPMCPTop10Manager
+	repo      *database.Repository
+	alerter   OWASPScoreAlerter
+	logger    *zap.Logger
+	Threshold int
+}
+
+// NewNightlyOWASPScan creates a nightly OWASP MCP Top 10 scan. alerter may be nil to
+// disable alerts.
+func NewNightlyOWASPScan(manager *OWASPMCPTop10Manager, repo *database.Repository, alerter OWASPScoreAlerter, logger *zap.Logger) *NightlyOWASPScan {
+	return &NightlyOWASPScan{
+		manager:   manager,
+		repo:      repo,
+		alerter:   alerter,
+		logger:    logger,
+		Threshold: DefaultOWASPScoreThreshold,
+	}
+}
+
+// Run scans every registered server once, stopping early if the context is cancelled
+func (n *NightlyOWASPScan) Run(ctx context.Context) {
+	servers, err := n.repo.ListActiveMCPServers(ctx)
+	if err != nil {
+		n.logger.Error("Failed to list servers for nightly OWASP scan", zap.Error(err))
+		return
+	}
+
+	scanned, belowThreshold := 0, 0
+	for _, server := range servers {
+		if ctx.Err() != nil {
+			n.logger.Warn("Nightly OWASP scan interrupted", zap.Int("scanned", scanned), zap.Int("servers", len(servers)))
+			return
+		}
+
+		score, ok := n.scan(ctx, server)
+		if !ok {
+			continue
+		}
+		scanned++
+
+		if score < n.Threshold {
+			belowThreshold++
+			if n.alerter != nil {
+				n.alerter.AlertOWASPScore(server.ID, score, n.Threshold)
+			}
+		}
+	}
+
+	n.logger.Info("Finished nightly OWASP scan",
+		zap.Int("servers", len(servers)),
+		zap.Int("scanned", scanned),
+		zap.Int("below_threshold", belowThreshold),
+		zap.Int("threshold", n.Threshold),
+	)
+}
+
+// scan runs the tests against one server, stores the run and returns its average score
+func (n *NightlyOWASPScan) scan(ctx context.Context, server *database.MCPServer) (int, bool) {
+	results, err := n.manager.RunAllTests(ctx, server.ID.String())
+	if err != nil {
+		n.logger.Error("Nightly OWASP scan failed", zap.String("server_id", server.ID.String()), zap.Error(err))
+		return 0, false
+	}
+	if len(results) == 0 {
+		return 0, false
+	}
+
+	records := n.manager.resultRecords(uuid.New(), server.ID, results)
+	if err := n.repo.StoreOWASPResults(ctx, records); err != nil {
+		n.logger.Error("Failed to store nightly OWASP scan results", zap.String("server_id", server.ID.String()), zap.Error(err))
+	}
+
+	total := 0
+	for _, result := range results {
+		total += result.Score
+	}
+	return total / len(results), true
+}
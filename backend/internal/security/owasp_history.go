@@ -0,0 +1,137 @@
+package security
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/log"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultOWASPHistoryRuns is the number of runs returned when none are requested
+	defaultOWASPHistoryRuns = 10
+	// maxOWASPHistoryRuns bounds the number of runs returned in one request
+	maxOWASPHistoryRuns = 100
+)
+
+// storeOWASPResults stores the results of running all OWASP MCP Top 10 tests against a
+// server in the caller's organization as one run. Results for servers that are not
+// registered, or when no repository is set, are not stored.
+func (h *Handler) storeOWASPResults(c *gin.Context, serverID string, results []*OWASPMCPTop10Result) {
+	if h.repo == nil || len(results) == 0 {
+		return
+	}
+
+	serverUUID, ok := h.organizationServer(c, serverID)
+	if !ok {
+		return
+	}
+
+	records := h.owaspManager.resultRecords(uuid.New(), serverUUID, results)
+
+	if err := h.repo.StoreOWASPResults(c.Request.Context(), records); err != nil {
+		log.FromCtx(c.Request.Context()).Error("Failed to store OWASP MCP Top 10 results",
+			zap.String("server_id", serverID),
+			zap.Error(err))
+	}
+}
+
+// resultRecords converts the results of one OWASP MCP Top 10 run into database records
+func (m *OWASPMCPTop10Manager) resultRecords(runID, serverID uuid.UUID, results []*OWASPMCPTop10Result) []*database.OWASPTestResult {
+	records := make([]*database.OWASPTestResult, 0, len(results))
+	for _, result := range results {
+		category := ""
+		if test := m.findTestByID(result.TestID); test != nil {
+			category = test.Category
+		}
+
+		details := database.JSONB{}
+		for key, value := range result.Details {
+			details[key] = value
+		}
+		if len(result.Vulnerabilities) > 0 {
+			details["vulnerabilities"] = result.Vulnerabilities
+		}
+
+		records = append(records, &database.OWASPTestResult{
+			RunID:     runID,
+			ServerID:  serverID,
+			TestID:    result.TestID,
+			Category:  category,
+			Status:    result.Status,
+			Score:     result.Score,
+			Details:   details,
+			CreatedAt: result.CreatedAt,
+		})
+	}
+	return records
+}
+
+// organizationServer returns the ID of a server if it belongs to the caller's organization
+func (h *Handler) organizationServer(c *gin.Context, serverID string) (uuid.UUID, bool) {
+	serverUUID, err := uuid.Parse(serverID)
+	if err != nil {
+		return uuid.Nil, false
+	}
+
+	orgID, exists := c.Get("organization_id")
+	if !exists {
+		return uuid.Nil, false
+	}
+	orgUUID, ok := orgID.(uuid.UUID)
+	if !ok {
+		return uuid.Nil, false
+	}
+
+	if _, err := h.repo.GetMCPServerByID(c.Request.Context(), orgUUID, serverUUID); err != nil {
+		return uuid.Nil, false
+	}
+
+	return serverUUID, true
+}
+
+// GetOWASPMCPHistory returns a server's last OWASP MCP Top 10 runs, oldest first, with
+// the overall and per-category score of each
+func (h *Handler) GetOWASPMCPHistory(c *gin.Context) {
+	if h.repo == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "OWASP result history is not available"})
+		return
+	}
+
+	if _, err := uuid.Parse(c.Param("serverId")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid server ID"})
+		return
+	}
+
+	runs := defaultOWASPHistoryRuns
+	if value := c.Query("runs"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxOWASPHistoryRuns {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "runs must be between 1 and " + strconv.Itoa(maxOWASPHistoryRuns)})
+			return
+		}
+		runs = parsed
+	}
+
+	serverID, ok := h.organizationServer(c, c.Param("serverId"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Server not found"})
+		return
+	}
+
+	history, err := h.repo.GetOWASPResultHistory(c.Request.Context(), serverID, runs)
+	if err != nil {
+		log.FromCtx(c.Request.Context()).Error("Failed to get OWASP MCP Top 10 history", zap.String("server_id", serverID.String()), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get OWASP result history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    history,
+	})
+}
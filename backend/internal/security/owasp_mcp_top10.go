@@ -3,6 +3,7 @@ package security
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
@@ -56,16 +57,52 @@ type Vulnerability struct {
 
 // OWASPMCPTop10Manager manages OWASP MCP Top 10 security tests
 type OWASPMCPTop10Manager struct {
-	logger *zap.Logger
-	tests  []OWASPMCPTop10Test
+	logger  *zap.Logger
+	mu      sync.RWMutex
+	tests   []OWASPMCPTop10Test
+	plugins map[string]SecurityTestPlugin
 }
 
-// NewOWASPMCPTop10Manager creates a new OWASP MCP Top 10 manager
+// NewOWASPMCPTop10Manager creates a new OWASP MCP Top 10 manager with the built-in tests registered
 func NewOWASPMCPTop10Manager(logger *zap.Logger) *OWASPMCPTop10Manager {
-	return &OWASPMCPTop10Manager{
-		logger: logger,
-		tests:  getDefaultOWASPMCPTop10Tests(),
+	m := &OWASPMCPTop10Manager{
+		logger:  logger,
+		tests:   getDefaultOWASPMCPTop10Tests(),
+		plugins: make(map[string]SecurityTestPlugin),
 	}
+
+	for _, plugin := range m.builtinPlugins() {
+		m.Register(plugin)
+	}
+
+	return m
+}
+
+// Register adds a security test plugin, replacing any plugin with the same ID.
+// Plugins without a matching default test are listed as new automated tests.
+func (m *OWASPMCPTop10Manager) Register(plugin SecurityTestPlugin) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.plugins[plugin.ID()] = plugin
+
+	for _, test := range m.tests {
+		if test.ID == plugin.ID() {
+			return
+		}
+	}
+
+	m.tests = append(m.tests, OWASPMCPTop10Test{
+		ID:       plugin.ID(),
+		Category: plugin.Category(),
+		Name:     plugin.ID(),
+		TestType: "plugin",
+		Enabled:  true,
+	})
+
+	m.logger.Info("Registered security test plugin",
+		zap.String("test_id", plugin.ID()),
+		zap.String("category", plugin.Category()))
 }
 
 // GetOWASPMCPTop10Categories returns the OWASP MCP Top 10 categories
@@ -146,7 +183,12 @@ func (m *OWASPMCPTop10Manager) GetOWASPMCPTop10Categories() []OWASPMCPTop10 {
 
 // GetAvailableTests returns all available OWASP MCP Top 10 tests
 func (m *OWASPMCPTop10Manager) GetAvailableTests() []OWASPMCPTop10Test {
-	return m.tests
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tests := make([]OWASPMCPTop10Test, len(m.tests))
+	copy(tests, m.tests)
+	return tests
 }
 
 // RunSecurityTest runs a specific OWASP MCP Top 10 test
@@ -161,40 +203,38 @@ func (m *OWASPMCPTop10Manager) RunSecurityTest(ctx context.Context, testID, serv
 		zap.String("server_id", serverID),
 		zap.String("category", test.Category))
 
-	result := &OWASPMCPTop10Result{
-		ID:        generateID(),
-		TestID:    testID,
-		ServerID:  serverID,
-		CreatedAt: time.Now(),
+	m.mu.RLock()
+	plugin, exists := m.plugins[testID]
+	m.mu.RUnlock()
+
+	if !exists {
+		return &OWASPMCPTop10Result{
+			ID:        generateID(),
+			TestID:    testID,
+			ServerID:  serverID,
+			Status:    "ERROR",
+			CreatedAt: time.Now(),
+			Details: map[string]interface{}{
+				"error": "No plugin registered for test",
+			},
+		}, nil
 	}
 
-	// Run the specific test based on category
-	switch test.Category {
-	case "Access Control":
-		result = m.runAccessControlTest(ctx, test, serverID)
-	case "Cryptography":
-		result = m.runCryptographyTest(ctx, test, serverID)
-	case "Injection":
-		result = m.runInjectionTest(ctx, test, serverID)
-	case "Design":
-		result = m.runDesignTest(ctx, test, serverID)
-	case "Configuration":
-		result = m.runConfigurationTest(ctx, test, serverID)
-	case "Dependencies":
-		result = m.runDependenciesTest(ctx, test, serverID)
-	case "Authentication":
-		result = m.runAuthenticationTest(ctx, test, serverID)
-	case "Integrity":
-		result = m.runIntegrityTest(ctx, test, serverID)
-	case "Logging":
-		result = m.runLoggingTest(ctx, test, serverID)
-	case "SSRF":
-		result = m.runSSRFTest(ctx, test, serverID)
-	default:
-		result.Status = "ERROR"
-		result.Details = map[string]interface{}{
-			"error": "Unknown test category",
-		}
+	result := plugin.Run(ctx, serverID)
+	if result == nil {
+		return nil, fmt.Errorf("plugin %s returned no result", testID)
+	}
+	if result.ID == "" {
+		result.ID = generateID()
+	}
+	if result.TestID == "" {
+		result.TestID = testID
+	}
+	if result.ServerID == "" {
+		result.ServerID = serverID
+	}
+	if result.CreatedAt.IsZero() {
+		result.CreatedAt = time.Now()
 	}
 
 	return result, nil
@@ -204,7 +244,7 @@ func (m *OWASPMCPTop10Manager) RunSecurityTest(ctx context.Context, testID, serv
 func (m *OWASPMCPTop10Manager) RunAllTests(ctx context.Context, serverID string) ([]*OWASPMCPTop10Result, error) {
 	var results []*OWASPMCPTop10Result
 
-	for _, test := range m.tests {
+	for _, test := range m.GetAvailableTests() {
 		if test.Enabled {
 			result, err := m.RunSecurityTest(ctx, test.ID, serverID)
 			if err != nil {
@@ -382,6 +422,9 @@ func (m *OWASPMCPTop10Manager) runSSRFTest(ctx context.Context, test *OWASPMCPTo
 
 // Helper functions
 func (m *OWASPMCPTop10Manager) findTestByID(testID string) *OWASPMCPTop10Test {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	for _, test := range m.tests {
 		if test.ID == testID {
 			return &test
@@ -0,0 +1,118 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"plugin"
+)
+
+// SecurityTestPlugin implements a single OWASP MCP Top 10 security check
+type SecurityTestPlugin interface {
+	ID() string
+	Category() string
+	Run(ctx context.Context, serverURL string) *OWASPMCPTop10Result
+}
+
+// PluginSymbol is the symbol a shared object must export to provide a plugin.
+// It may be a SecurityTestPlugin value or a func() SecurityTestPlugin.
+const PluginSymbol = "SecurityTestPlugin"
+
+// builtinPlugin wraps one of the bundled test implementations
+type builtinPlugin struct {
+	test OWASPMCPTop10Test
+	run  func(ctx context.Context, test *OWASPMCPTop10Test, serverURL string) *OWASPMCPTop10Result
+}
+
+// ID returns the test ID
+func (p *builtinPlugin) ID() string {
+	return p.test.ID
+}
+
+// Category returns the OWASP MCP Top 10 category
+func (p *builtinPlugin) Category() string {
+	return p.test.Category
+}
+
+// Run executes the check against a server
+func (p *builtinPlugin) Run(ctx context.Context, serverURL string) *OWASPMCPTop10Result {
+	return p.run(ctx, &p.test, serverURL)
+}
+
+// builtinPlugins returns the bundled plugins for the default tests
+func (m *OWASPMCPTop10Manager) builtinPlugins() []SecurityTestPlugin {
+	runners := map[string]func(context.Context, *OWASPMCPTop10Test, string) *OWASPMCPTop10Result{
+		"Access Control": m.runAccessControlTest,
+		"Cryptography":   m.runCryptographyTest,
+		"Injection":      m.runInjectionTest,
+		"Design":         m.runDesignTest,
+		"Configuration":  m.runConfigurationTest,
+		"Dependencies":   m.runDependenciesTest,
+		"Authentication": m.runAuthenticationTest,
+		"Integrity":      m.runIntegrityTest,
+		"Logging":        m.runLoggingTest,
+		"SSRF":           m.runSSRFTest,
+	}
+
+	var plugins []SecurityTestPlugin
+	for _, test := range getDefaultOWASPMCPTop10Tests() {
+		if run, ok := runners[test.Category]; ok {
+			plugins = append(plugins, &builtinPlugin{test: test, run: run})
+		}
+	}
+
+	return plugins
+}
+
+// LoadPlugin opens a Go plugin shared object and registers the security test it exports
+func (m *OWASPMCPTop10Manager) LoadPlugin(path string) error {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open plugin %s: %w", path, err)
+	}
+
+	sym, err := p.Lookup(PluginSymbol)
+	if err != nil {
+		return fmt.Errorf("plugin %s does not export %s: %w", path, PluginSymbol, err)
+	}
+
+	var testPlugin SecurityTestPlugin
+	switch v := sym.(type) {
+	case SecurityTestPlugin:
+		testPlugin = v
+	case *SecurityTestPlugin:
+		testPlugin = *v
+	case func() SecurityTestPlugin:
+		testPlugin = v()
+	default:
+		return fmt.Errorf("plugin %s: %s has unsupported type %T", path, PluginSymbol, sym)
+	}
+
+	if testPlugin == nil {
+		return fmt.Errorf("plugin %s: %s is nil", path, PluginSymbol)
+	}
+
+	m.Register(testPlugin)
+	return nil
+}
+
+// LoadPlugins loads every .so file in dir
+func (m *OWASPMCPTop10Manager) LoadPlugins(dir string) error {
+	if _, err := os.Stat(dir); err != nil {
+		return fmt.Errorf("failed to read plugin directory: %w", err)
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.so"))
+	if err != nil {
+		return fmt.Errorf("failed to list plugins: %w", err)
+	}
+
+	for _, path := range paths {
+		if err := m.LoadPlugin(path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package security
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ProfileStore persists agent profiles across restarts
+type ProfileStore interface {
+	// Load returns the stored profile, or nil if the agent has none
+	Load(agentID string) (*AgentProfile, error)
+	Save(profile *AgentProfile) error
+}
+
+// ExpiringProfileStore is a profile store that can remove inactive profiles
+type ExpiringProfileStore interface {
+	ProfileStore
+	DeleteNotSeenSince(before time.Time) (int64, error)
+}
+
+// profileStoreTimeout bounds each profile store call
+const profileStoreTimeout = 5 * time.Second
+
+// SetProfileStore makes the analyzer load profiles lazily from store and write them
+// through after every analysis
+func (ba *BehavioralAnalyzer) SetProfileStore(store ProfileStore, logger *zap.Logger) {
+	ba.mu.Lock()
+	defer ba.mu.Unlock()
+
+	ba.store = store
+	ba.logger = logger
+}
+
+// loadProfile loads an agent's profile from the store if it is not in memory
+func (ba *BehavioralAnalyzer) loadProfile(agentID string) {
+	ba.mu.RLock()
+	store := ba.store
+	_, cached := ba.agentProfiles[agentID]
+	ba.mu.RUnlock()
+
+	if store == nil || cached {
+		return
+	}
+
+	profile, err := store.Load(agentID)
+	if err != nil {
+		ba.logger.Warn("Failed to load agent profile", zap.String("agent_id", agentID), zap.Error(err))
+		return
+	}
+	if profile == nil {
+		return
+	}
+	if profile.ToolUsagePattern == nil {
+		profile.ToolUsagePattern = make(map[string]int)
+	}
+
+	ba.mu.Lock()
+	defer ba.mu.Unlock()
+
+	// Another request may have created the profile while it was loading
+	if _, exists := ba.agentProfiles[agentID]; !exists {
+		ba.agentProfiles[agentID] = profile
+	}
+}
+
+// saveProfile writes a profile to the store
+func (ba *BehavioralAnalyzer) saveProfile(profile *AgentProfile) {
+	ba.mu.RLock()
+	store := ba.store
+	ba.mu.RUnlock()
+
+	if store == nil {
+		return
+	}
+
+	if err := store.Save(profile); err != nil {
+		ba.logger.Warn("Failed to save agent profile", zap.String("agent_id", profile.AgentID), zap.Error(err))
+	}
+}
+
+// StartCleanup removes profiles not seen within RetentionPeriod, from memory and from the
+// store when it supports expiry, every interval until the context is cancelled
+func (ba *BehavioralAnalyzer) StartCleanup(ctx context.Context, interval time.Duration) {
+	if ba.RetentionPeriod <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			ba.cleanup(now.Add(-ba.RetentionPeriod))
+		}
+	}
+}
+
+// cleanup removes profiles last seen before cutoff
+func (ba *BehavioralAnalyzer) cleanup(cutoff time.Time) {
+	ba.mu.Lock()
+	for agentID, profile := range ba.agentProfiles {
+		if profile.LastSeen.Before(cutoff) {
+			delete(ba.agentProfiles, agentID)
+		}
+	}
+	store, logger := ba.store, ba.logger
+	ba.mu.Unlock()
+
+	expiring, ok := store.(ExpiringProfileStore)
+	if !ok {
+		return
+	}
+
+	deleted, err := expiring.DeleteNotSeenSince(cutoff)
+	if err != nil {
+		logger.Error("Failed to delete inactive agent profiles", zap.Error(err))
+		return
+	}
+	if deleted > 0 {
+		logger.Info("Deleted inactive agent profiles", zap.Int64("count", deleted))
+	}
+}
+
+// clone returns a copy of the profile that can be used without holding the analyzer lock
+func (p *AgentProfile) clone() *AgentProfile {
+	c := *p
+	c.ToolUsagePattern = make(map[string]int, len(p.ToolUsagePattern))
+	for tool, count := range p.ToolUsagePattern {
+		c.ToolUsagePattern[tool] = count
+	}
+	c.Anomalies = append([]*BehavioralAnomaly(nil), p.Anomalies...)
+	return &c
+}
+
+// PostgresProfileStore keeps agent profiles in the agent_profiles table
+type PostgresProfileStore struct {
+	db *sql.DB
+}
+
+// NewPostgresProfileStore creates a profile store backed by PostgreSQL
+func NewPostgresProfileStore(db *sql.DB) *PostgresProfileStore {
+	return &PostgresProfileStore{db: db}
+}
+
+// Load returns an agent's stored profile, or nil if there is none
+func (s *PostgresProfileStore) Load(agentID string) (*AgentProfile, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), profileStoreTimeout)
+	defer cancel()
+
+	var data []byte
+	err := s.db.QueryRowContext(ctx, `SELECT profile FROM agent_profiles WHERE agent_id = $1`, agentID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load agent profile: %w", err)
+	}
+
+	var profile AgentProfile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("failed to decode agent profile: %w", err)
+	}
+
+	return &profile, nil
+}
+
+// Save upserts an agent's profile
+func (s *PostgresProfileStore) Save(profile *AgentProfile) error {
+	ctx, cancel := context.WithTimeout(context.Background(), profileStoreTimeout)
+	defer cancel()
+
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return fmt.Errorf("failed to encode agent profile: %w", err)
+	}
+
+	query := `
+		INSERT INTO agent_profiles (agent_id, profile, last_seen, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (agent_id) DO UPDATE
+		SET profile = EXCLUDED.profile, last_seen = EXCLUDED.last_seen, updated_at = NOW()
+	`
+	if _, err := s.db.ExecContext(ctx, query, profile.AgentID, string(data), profile.LastSeen); err != nil {
+		return fmt.Errorf("failed to save agent profile: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteNotSeenSince deletes profiles last seen before the given time
+func (s *PostgresProfileStore) DeleteNotSeenSince(before time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), profileStoreTimeout)
+	defer cancel()
+
+	result, err := s.db.ExecContext(ctx, `DELETE FROM agent_profiles WHERE last_seen < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete inactive agent profiles: %w", err)
+	}
+
+	return result.RowsAffected()
+}
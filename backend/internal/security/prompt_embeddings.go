@@ -0,0 +1,135 @@
+package security
+
+//go:generate go run gen_attack_embeddings.go
+
+import (
+	_ "embed"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// EmbeddingDimensions is the length of the vectors produced by PromptEmbedding
+const EmbeddingDimensions = 256
+
+// attackPhrases are known prompt injection phrases, one per line
+//
+//go:embed data/attack_phrases.txt
+var attackPhrasesFile string
+
+// attackEmbeddings holds the PromptEmbedding of each attack phrase as little-endian
+// float32s, in the same order. Regenerate it with go generate after editing the phrases.
+//
+//go:embed data/attack_embeddings.bin
+var attackEmbeddingsFile []byte
+
+// embeddingStopWords carry no signal about intent and are left out of embeddings
+var embeddingStopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "or": true, "of": true,
+	"to": true, "in": true, "on": true, "for": true, "with": true, "is": true,
+	"are": true, "be": true, "it": true, "this": true, "that": true, "me": true,
+	"i": true, "please": true, "can": true, "at": true, "as": true, "by": true,
+}
+
+// AttackPhrases returns the known prompt injection phrases
+func AttackPhrases() []string {
+	var phrases []string
+	for _, line := range strings.Split(attackPhrasesFile, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			phrases = append(phrases, line)
+		}
+	}
+	return phrases
+}
+
+// PromptEmbedding maps text to a unit vector by hashing its words, word pairs and
+// character trigrams, so texts sharing phrasing have a high cosine similarity
+func PromptEmbedding(text string) []float32 {
+	vector := make([]float64, EmbeddingDimensions)
+	add := func(feature string, weight float64) {
+		h := fnv.New32a()
+		h.Write([]byte(feature))
+		sum := h.Sum32()
+		if sum&(1<<31) != 0 {
+			weight = -weight
+		}
+		vector[sum%EmbeddingDimensions] += weight
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var previous string
+	for _, word := range words {
+		if embeddingStopWords[word] {
+			continue
+		}
+		add("w:"+word, 1)
+		if previous != "" {
+			add("b:"+previous+" "+word, 1)
+		}
+		previous = word
+
+		padded := " " + word + " "
+		for i := 0; i+3 <= len(padded); i++ {
+			add("c:"+padded[i:i+3], 0.25)
+		}
+	}
+
+	var norm float64
+	for _, v := range vector {
+		norm += v * v
+	}
+	norm = math.Sqrt(norm)
+
+	embedding := make([]float32, EmbeddingDimensions)
+	if norm == 0 {
+		return embedding
+	}
+	for i, v := range vector {
+		embedding[i] = float32(v / norm)
+	}
+	return embedding
+}
+
+// cosineSimilarity returns the cosine similarity of two unit vectors
+func cosineSimilarity(a, b []float32) float64 {
+	var dot float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+	}
+	return dot
+}
+
+// loadAttackEmbeddings decodes the embedded attack phrase vectors
+func loadAttackEmbeddings() ([][]float32, error) {
+	phrases := AttackPhrases()
+	if len(attackEmbeddingsFile) != len(phrases)*EmbeddingDimensions*4 {
+		return nil, fmt.Errorf("attack embeddings hold %d bytes, want %d for %d phrases; run go generate",
+			len(attackEmbeddingsFile), len(phrases)*EmbeddingDimensions*4, len(phrases))
+	}
+
+	embeddings := make([][]float32, len(phrases))
+	for i := range embeddings {
+		embeddings[i] = make([]float32, EmbeddingDimensions)
+		for j := range embeddings[i] {
+			offset := (i*EmbeddingDimensions + j) * 4
+			embeddings[i][j] = math.Float32frombits(binary.LittleEndian.Uint32(attackEmbeddingsFile[offset:]))
+		}
+	}
+	return embeddings, nil
+}
+
+// EncodeEmbeddings serializes vectors in the format of the embedded attack embeddings
+func EncodeEmbeddings(vectors [][]float32) []byte {
+	data := make([]byte, 0, len(vectors)*EmbeddingDimensions*4)
+	for _, vector := range vectors {
+		for _, v := range vector {
+			data = binary.LittleEndian.AppendUint32(data, math.Float32bits(v))
+		}
+	}
+	return data
+}
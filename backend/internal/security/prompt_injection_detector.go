@@ -5,138 +5,146 @@ import (
 	"strings"
 )
 
-// PromptInjectionDetector detects potential prompt injection attacks
+// DefaultPromptInjectionThreshold is the score above which input is treated as an injection
+const DefaultPromptInjectionThreshold = 0.7
+
+// minReportedSimilarity is the lowest similarity to a known attack phrase that is
+// reported in DetectionResult.Patterns
+const minReportedSimilarity = 0.4
+
+// PromptInjectionDetector detects potential prompt injection attacks. It combines a
+// library of weighted patterns with the cosine similarity of the input to known attack
+// phrases, so rephrased attacks are caught as well as verbatim ones.
 type PromptInjectionDetector struct {
-	suspiciousPatterns []*regexp.Regexp
-	highRiskKeywords   []string
+	// Threshold is the score above which input is treated as an injection
+	Threshold float64
+
+	patterns         []injectionPattern
+	attackPhrases    []string
+	attackEmbeddings [][]float32
+}
+
+// injectionPattern is a named pattern and how strongly a match indicates an injection
+type injectionPattern struct {
+	name   string
+	regex  *regexp.Regexp
+	weight float64
 }
 
-// PromptInjectionResult represents the result of prompt injection analysis
-type PromptInjectionResult struct {
-	IsDetected      bool     `json:"is_detected"`
-	RiskLevel       string   `json:"risk_level"` // "low", "medium", "high", "critical"
-	MatchedPatterns []string `json:"matched_patterns"`
-	Score           int      `json:"score"`
-	Recommendations []string `json:"recommendations"`
+// DetectionResult represents the result of prompt injection analysis
+type DetectionResult struct {
+	Score    float64  `json:"score"`    // 0 to 1
+	Patterns []string `json:"patterns"` // matched patterns and similar attack phrases
+	Severity string   `json:"severity"` // "none", "low", "medium", "high", "critical"
 }
 
 // NewPromptInjectionDetector creates a new prompt injection detector
 func NewPromptInjectionDetector() *PromptInjectionDetector {
-	detector := &PromptInjectionDetector{
-		suspiciousPatterns: []*regexp.Regexp{
+	embeddings, err := loadAttackEmbeddings()
+	if err != nil {
+		panic(err)
+	}
+
+	return &PromptInjectionDetector{
+		Threshold: DefaultPromptInjectionThreshold,
+		patterns: []injectionPattern{
+			// Chat template and delimiter smuggling
+			{"chat_template_token", regexp.MustCompile(`(?i)<\|(im_start|im_end|im_sep|endoftext|system|user|assistant)\|>`), 0.8},
+			{"instruction_tags", regexp.MustCompile(`(?i)\[/?INST\]|<</?SYS>>`), 0.75},
+			{"role_header", regexp.MustCompile(`(?im)^\s*#{2,}\s*(system|instruction|assistant)s?\b`), 0.5},
+			{"role_prefix", regexp.MustCompile(`(?im)^\s*(system|assistant)\s*:`), 0.35},
+			{"delimiter_context_switch", regexp.MustCompile(`(?i)(-{3,}|={3,}|\*{3,})\s*(end|begin|new|system)\s*(of\s+)?(context|conversation|session|prompt|instructions?)`), 0.6},
+			{"delimiter_line", regexp.MustCompile(`(?m)^\s*(-{3,}|={3,})\s*$`), 0.1},
+
 			// System prompt manipulation
-			regexp.MustCompile(`(?i)(ignore|disregard|forget)\s+(previous|all|your|earlier)\s+(instructions?|prompts?|commands?|rules?)`),
-			regexp.MustCompile(`(?i)system\s*:\s*.{0,50}(ignore|bypass|override)`),
+			{"ignore_instructions", regexp.MustCompile(`(?i)(ignore|disregard|forget|override)\s+(all\s+)?(of\s+)?(the\s+|your\s+)?((previous|prior|above|earlier|your|all)\s+(instructions?|prompts?|commands?|rules?|guidelines?)|(instructions?|prompts?|rules?|guidelines?)\s+above)`), 0.85},
+			{"system_override", regexp.MustCompile(`(?i)system\s*:\s*.{0,50}(ignore|bypass|override)`), 0.6},
+			{"new_instructions", regexp.MustCompile(`(?i)(new|updated|real)\s+instructions?\s*(:|from|follow)`), 0.4},
 
 			// Role hijacking
-			regexp.MustCompile(`(?i)(you\s+are\s+now|act\s+as|pretend\s+to\s+be|assume\s+the\s+role)\s+(a|an)?\s*(admin|root|developer|system)`),
-			regexp.MustCompile(`(?i)from\s+now\s+on.*?(admin|unrestricted|unlimited|god\s+mode)`),
-
-			// Command injection
-			regexp.MustCompile(`(?i)(execute|run|eval|system|shell|cmd|bash|powershell)\s*\(`),
-			regexp.MustCompile(`(?i)(<script|javascript:|onerror=|onclick=)`),
+			{"role_hijack", regexp.MustCompile(`(?i)(you\s+are\s+now|act\s+as|pretend\s+(to\s+be|you\s+are)|assume\s+the\s+role\s+of)\s+(a|an)?\s*(admin|root|developer|system|unrestricted|unfiltered)`), 0.75},
+			{"persistent_mode_switch", regexp.MustCompile(`(?i)from\s+now\s+on.{0,40}(admin|unrestricted|unlimited|no\s+(rules|restrictions|limits)|god\s+mode)`), 0.6},
+			{"jailbreak_persona", regexp.MustCompile(`\bDAN\b|(?i)do\s+anything\s+now|jailbreak`), 0.6},
 
 			// Data extraction attempts
-			regexp.MustCompile(`(?i)(show|reveal|display|tell\s+me|give\s+me)\s+(the|all|your)?\s*(secrets?|passwords?|keys?|tokens?|credentials?)`),
-			regexp.MustCompile(`(?i)(dump|export|extract)\s+(database|data|config|env)`),
+			{"reveal_system_prompt", regexp.MustCompile(`(?i)(show|reveal|print|repeat|output|display)\s+(me\s+)?(your|the)\s+(system\s+prompt|hidden\s+instructions|initial\s+instructions)`), 0.75},
+			{"secret_extraction", regexp.MustCompile(`(?i)(show|reveal|display|tell\s+me|give\s+me)\s+(the|all|your)?\s*(secrets?|passwords?|api\s+keys?|tokens?|credentials?)`), 0.45},
+			{"exfiltration_url", regexp.MustCompile(`(?i)(send|post|upload|forward|leak)\s+.{0,60}\bto\s+(https?://|ftp://)`), 0.5},
+			{"data_dump", regexp.MustCompile(`(?i)(dump|export|extract|exfiltrate)\s+(the\s+)?(database|data|config|env|environment)`), 0.45},
 
-			// Context poisoning
-			regexp.MustCompile(`(?i)---\s*new\s+(context|conversation|session)`),
-			regexp.MustCompile(`(?i)reset\s+(memory|context|conversation)`),
-
-			// Jailbreak attempts
-			regexp.MustCompile(`(?i)(DAN|Do\s+Anything\s+Now|Developer\s+Mode)`),
-			regexp.MustCompile(`(?i)hypothetically|in\s+a\s+fictional\s+world|for\s+educational\s+purposes\s+only.*?(hack|exploit|bypass)`),
-		},
-		highRiskKeywords: []string{
-			"ignore instructions",
-			"bypass security",
-			"admin access",
-			"root privileges",
-			"execute code",
-			"system command",
-			"reveal secrets",
-			"dump database",
-			"jailbreak",
-			"sudo",
-			"eval(",
-			"exec(",
-			"__import__",
+			// Command injection
+			{"code_execution", regexp.MustCompile(`(?i)\b(eval|exec|system|popen|__import__)\s*\(`), 0.5},
+			{"script_injection", regexp.MustCompile(`(?i)(<script|javascript:|onerror\s*=|onload\s*=)`), 0.5},
 		},
+		attackPhrases:    AttackPhrases(),
+		attackEmbeddings: embeddings,
 	}
-
-	return detector
 }
 
-// AnalyzePrompt analyzes a prompt for potential injection attacks
-func (d *PromptInjectionDetector) AnalyzePrompt(prompt string) *PromptInjectionResult {
-	result := &PromptInjectionResult{
-		IsDetected:      false,
-		RiskLevel:       "low",
-		MatchedPatterns: []string{},
-		Score:           0,
-		Recommendations: []string{},
+// Analyze scores input for prompt injection. Each matched pattern and the similarity to
+// the closest known attack phrase are combined as independent evidence, so the score
+// rises with every indicator but never exceeds 1.
+func (d *PromptInjectionDetector) Analyze(input string) DetectionResult {
+	result := DetectionResult{
+		Patterns: []string{},
+		Severity: "none",
 	}
 
-	promptLower := strings.ToLower(prompt)
-
-	// Check for suspicious patterns
-	for _, pattern := range d.suspiciousPatterns {
-		if pattern.MatchString(prompt) {
-			result.IsDetected = true
-			result.MatchedPatterns = append(result.MatchedPatterns, pattern.String())
-			result.Score += 20
+	clean := 1.0
+	for _, pattern := range d.patterns {
+		if pattern.regex.MatchString(input) {
+			result.Patterns = append(result.Patterns, pattern.name)
+			clean *= 1 - pattern.weight
 		}
 	}
 
-	// Check for high-risk keywords
-	for _, keyword := range d.highRiskKeywords {
-		if strings.Contains(promptLower, keyword) {
-			result.IsDetected = true
-			result.MatchedPatterns = append(result.MatchedPatterns, keyword)
-			result.Score += 10
-		}
+	if phrase, similarity := d.closestAttackPhrase(input); similarity >= minReportedSimilarity {
+		result.Patterns = append(result.Patterns, "similar_to: "+phrase)
+		clean *= 1 - similarity
 	}
 
-	// Check for multiple suspicious indicators
-	suspiciousIndicators := 0
-	indicators := []string{
-		"password", "secret", "token", "key", "credential",
-		"admin", "root", "sudo", "execute", "eval",
-		"bypass", "ignore", "override", "hack",
-	}
+	result.Score = 1 - clean
+	result.Severity = injectionSeverity(result.Score)
 
-	for _, indicator := range indicators {
-		if strings.Contains(promptLower, indicator) {
-			suspiciousIndicators++
-		}
+	return result
+}
+
+// IsInjection reports whether a result's score exceeds the detector's threshold
+func (d *PromptInjectionDetector) IsInjection(result DetectionResult) bool {
+	return result.Score > d.Threshold
+}
+
+// closestAttackPhrase returns the known attack phrase most similar to input
+func (d *PromptInjectionDetector) closestAttackPhrase(input string) (string, float64) {
+	if strings.TrimSpace(input) == "" {
+		return "", 0
 	}
 
-	if suspiciousIndicators >= 3 {
-		result.Score += 15
-		result.MatchedPatterns = append(result.MatchedPatterns, "Multiple suspicious indicators detected")
+	embedding := PromptEmbedding(input)
+
+	closest, best := "", 0.0
+	for i, attack := range d.attackEmbeddings {
+		if similarity := cosineSimilarity(embedding, attack); similarity > best {
+			closest, best = d.attackPhrases[i], similarity
+		}
 	}
+	return closest, best
+}
 
-	// Determine risk level based on score
+// injectionSeverity maps a detection score to a severity
+func injectionSeverity(score float64) string {
 	switch {
-	case result.Score >= 50:
-		result.RiskLevel = "critical"
-		result.Recommendations = append(result.Recommendations, "BLOCK: High-confidence prompt injection attempt detected")
-		result.Recommendations = append(result.Recommendations, "Log incident and alert security team")
-	case result.Score >= 30:
-		result.RiskLevel = "high"
-		result.Recommendations = append(result.Recommendations, "WARN: Potential prompt injection detected")
-		result.Recommendations = append(result.Recommendations, "Apply strict input sanitization")
-	case result.Score >= 15:
-		result.RiskLevel = "medium"
-		result.Recommendations = append(result.Recommendations, "CAUTION: Suspicious patterns detected")
-		result.Recommendations = append(result.Recommendations, "Monitor for additional indicators")
-	case result.Score > 0:
-		result.RiskLevel = "low"
-		result.Recommendations = append(result.Recommendations, "INFO: Minor suspicious patterns detected")
+	case score >= 0.9:
+		return "critical"
+	case score >= 0.7:
+		return "high"
+	case score >= 0.4:
+		return "medium"
+	case score > 0:
+		return "low"
+	default:
+		return "none"
 	}
-
-	return result
 }
 
 // ValidateToolAccess validates if a tool access request is legitimate
@@ -156,8 +164,7 @@ func (d *PromptInjectionDetector) ValidateToolAccess(toolName string, params map
 	// Check parameters for injection attempts
 	for _, value := range params {
 		if str, ok := value.(string); ok {
-			result := d.AnalyzePrompt(str)
-			if result.RiskLevel == "critical" || result.RiskLevel == "high" {
+			if d.IsInjection(d.Analyze(str)) {
 				return false
 			}
 		}
@@ -165,5 +172,3 @@ func (d *PromptInjectionDetector) ValidateToolAccess(toolName string, params map
 
 	return true
 }
-
-
@@ -0,0 +1,186 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/scheduler"
+	"go.uber.org/zap"
+)
+
+// ScanNotifier delivers scheduled scan failure notifications
+type ScanNotifier interface {
+	Send(to []string, subject, body string) error
+}
+
+// ScanScheduler runs scheduled security scans when they are due
+type ScanScheduler struct {
+	repo     *database.Repository
+	tester   *SecurityTester
+	notifier ScanNotifier
+	logger   *zap.Logger
+}
+
+// NewScanScheduler creates a scan scheduler. notifier may be nil to disable notifications.
+func NewScanScheduler(repo *database.Repository, notifier ScanNotifier, logger *zap.Logger) *ScanScheduler {
+	return &ScanScheduler{
+		repo:     repo,
+		tester:   NewSecurityTester(logger),
+		notifier: notifier,
+		logger:   logger,
+	}
+}
+
+// Start checks for due scans every minute until the context is cancelled
+func (s *ScanScheduler) Start(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	s.logger.Info("Started scheduled security scans")
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Stopping scheduled security scans")
+			return
+		case now := <-ticker.C:
+			s.runDue(ctx, now)
+		}
+	}
+}
+
+// runDue runs every scan that is due at now
+func (s *ScanScheduler) runDue(ctx context.Context, now time.Time) {
+	scans, err := s.repo.ListDueScheduledScans(ctx, now)
+	if err != nil {
+		s.logger.Error("Failed to list due scheduled scans", zap.Error(err))
+		return
+	}
+
+	for _, scan := range scans {
+		// Schedule the next run first so a failing scan is not retried every minute
+		nextRunAt, err := scheduler.NextCronRun(scan.CronExpression, now)
+		if err != nil {
+			s.logger.Error("Invalid cron expression on scheduled scan",
+				zap.String("scan_id", scan.ID.String()),
+				zap.Error(err),
+			)
+			continue
+		}
+		if err := s.repo.MarkScheduledScanRun(ctx, scan.ID, now, nextRunAt); err != nil {
+			s.logger.Error("Failed to update scheduled scan", zap.String("scan_id", scan.ID.String()), zap.Error(err))
+			continue
+		}
+
+		if err := s.run(ctx, scan); err != nil {
+			s.logger.Error("Scheduled scan failed", zap.String("scan_id", scan.ID.String()), zap.Error(err))
+		}
+	}
+}
+
+// run executes a scheduled scan, stores its results and notifies on failure
+func (s *ScanScheduler) run(ctx context.Context, scan *database.ScheduledScan) error {
+	server, err := s.repo.GetMCPServerByID(ctx, scan.OrganizationID, scan.ServerID)
+	if err != nil {
+		return err
+	}
+
+	var tests []*SecurityTest
+	if len(scan.TestTypes) == 0 {
+		tests, err = s.tester.RunAllTests(ctx, server.URL)
+	} else {
+		tests, err = s.tester.RunTests(ctx, server.URL, scan.TestTypes)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to run security tests: %w", err)
+	}
+
+	var failed []*SecurityTest
+	for _, test := range tests {
+		if err := s.store(ctx, scan, test); err != nil {
+			s.logger.Error("Failed to store security test result", zap.Error(err))
+		}
+		if test.Status == "failed" || test.Result == "fail" {
+			failed = append(failed, test)
+		}
+	}
+
+	s.logger.Info("Completed scheduled scan",
+		zap.String("scan_id", scan.ID.String()),
+		zap.String("server_id", scan.ServerID.String()),
+		zap.Int("tests", len(tests)),
+		zap.Int("failed", len(failed)),
+	)
+
+	if len(failed) > 0 && scan.NotifyOnFailure {
+		s.notify(ctx, scan, server, failed)
+	}
+
+	return nil
+}
+
+// store saves a scheduled test result
+func (s *ScanScheduler) store(ctx context.Context, scan *database.ScheduledScan, test *SecurityTest) error {
+	serverID := scan.ServerID
+	result := test.Result
+	startedAt := test.CreatedAt
+
+	record := &database.SecurityTest{
+		OrganizationID: scan.OrganizationID,
+		ServerID:       &serverID,
+		Name:           TestTypes[test.TestType].Name,
+		Type:           test.TestType,
+		Status:         test.Status,
+		Result:         &result,
+		Details: database.JSONB{
+			"details":           test.Details,
+			"severity":          test.Severity,
+			"scheduled_scan_id": scan.ID.String(),
+		},
+		StartedAt:   &startedAt,
+		CompletedAt: test.CompletedAt,
+		CreatedBy:   scan.CreatedBy,
+	}
+
+	return s.repo.CreateSecurityTest(ctx, record)
+}
+
+// notify emails the organization about failed tests
+func (s *ScanScheduler) notify(ctx context.Context, scan *database.ScheduledScan, server *database.MCPServer, failed []*SecurityTest) {
+	if s.notifier == nil {
+		return
+	}
+
+	org, err := s.repo.GetOrganizationByID(ctx, scan.OrganizationID)
+	if err != nil {
+		s.logger.Error("Failed to get organization for scan notification", zap.Error(err))
+		return
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "%d security test(s) failed in the scheduled scan of %s (%s).\n\n", len(failed), server.Name, server.URL)
+	for _, test := range failed {
+		fmt.Fprintf(&body, "- %s: %s\n", test.TestType, test.Details)
+	}
+
+	subject := fmt.Sprintf("[Aran MCP Sentinel] Security scan failed for %s", server.Name)
+	if err := s.notifier.Send([]string{org.Email}, subject, body.String()); err != nil {
+		s.logger.Error("Failed to send scan notification",
+			zap.String("scan_id", scan.ID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+// validateScanTestTypes checks that every test type is known
+func validateScanTestTypes(testTypes []string) error {
+	for _, testType := range testTypes {
+		if _, exists := TestTypes[testType]; !exists {
+			return fmt.Errorf("unknown test type: %s", testType)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,268 @@
+package security
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/scheduler"
+	"go.uber.org/zap"
+)
+
+// ScheduledScanHandler handles scheduled security scan endpoints
+type ScheduledScanHandler struct {
+	repo   *database.Repository
+	logger *zap.Logger
+}
+
+// NewScheduledScanHandler creates a new scheduled scan handler
+func NewScheduledScanHandler(repo *database.Repository, logger *zap.Logger) *ScheduledScanHandler {
+	return &ScheduledScanHandler{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// RegisterRoutes registers scheduled scan routes
+func (h *ScheduledScanHandler) RegisterRoutes(r *gin.RouterGroup) {
+	scans := r.Group("/security/scheduled-scans")
+	{
+		scans.POST("", h.CreateScheduledScan)
+		scans.GET("", h.ListScheduledScans)
+		scans.GET("/:id", h.GetScheduledScan)
+		scans.PUT("/:id", h.UpdateScheduledScan)
+		scans.DELETE("/:id", h.DeleteScheduledScan)
+	}
+}
+
+// ScheduledScanRequest represents a request to create or update a scheduled scan
+type ScheduledScanRequest struct {
+	ServerID        string   `json:"server_id"`
+	TestTypes       []string `json:"test_types"` // empty runs every test type
+	CronExpression  string   `json:"cron_expression" binding:"required"`
+	NotifyOnFailure bool     `json:"notify_on_failure"`
+}
+
+// CreateScheduledScan creates a new scheduled scan
+func (h *ScheduledScanHandler) CreateScheduledScan(c *gin.Context) {
+	orgID, ok := scanOrganizationID(c)
+	if !ok {
+		return
+	}
+
+	var req ScheduledScanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	serverID, err := uuid.Parse(req.ServerID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid server ID"})
+		return
+	}
+
+	if _, err := h.repo.GetMCPServerByID(c.Request.Context(), orgID, serverID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Server not found"})
+		return
+	}
+
+	nextRunAt, ok := validateScheduledScanRequest(c, &req)
+	if !ok {
+		return
+	}
+
+	scan := &database.ScheduledScan{
+		OrganizationID:  orgID,
+		ServerID:        serverID,
+		TestTypes:       req.TestTypes,
+		CronExpression:  req.CronExpression,
+		NotifyOnFailure: req.NotifyOnFailure,
+		NextRunAt:       nextRunAt,
+	}
+	if userID, exists := c.Get("user_id"); exists {
+		if id, ok := userID.(uuid.UUID); ok {
+			scan.CreatedBy = &id
+		}
+	}
+	if scan.TestTypes == nil {
+		scan.TestTypes = []string{}
+	}
+
+	if err := h.repo.CreateScheduledScan(c.Request.Context(), scan); err != nil {
+		h.logger.Error("Failed to create scheduled scan", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create scheduled scan"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    scan,
+	})
+}
+
+// ListScheduledScans lists the organization's scheduled scans
+func (h *ScheduledScanHandler) ListScheduledScans(c *gin.Context) {
+	orgID, ok := scanOrganizationID(c)
+	if !ok {
+		return
+	}
+
+	scans, err := h.repo.ListScheduledScans(c.Request.Context(), orgID)
+	if err != nil {
+		h.logger.Error("Failed to list scheduled scans", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list scheduled scans"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    scans,
+	})
+}
+
+// GetScheduledScan returns a scheduled scan
+func (h *ScheduledScanHandler) GetScheduledScan(c *gin.Context) {
+	orgID, ok := scanOrganizationID(c)
+	if !ok {
+		return
+	}
+
+	scanID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scan ID"})
+		return
+	}
+
+	scan, err := h.repo.GetScheduledScan(c.Request.Context(), orgID, scanID)
+	if err != nil {
+		h.respondScanError(c, "Failed to get scheduled scan", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    scan,
+	})
+}
+
+// UpdateScheduledScan updates a scheduled scan's schedule and options
+func (h *ScheduledScanHandler) UpdateScheduledScan(c *gin.Context) {
+	orgID, ok := scanOrganizationID(c)
+	if !ok {
+		return
+	}
+
+	scanID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scan ID"})
+		return
+	}
+
+	var req ScheduledScanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	nextRunAt, ok := validateScheduledScanRequest(c, &req)
+	if !ok {
+		return
+	}
+
+	scan, err := h.repo.GetScheduledScan(c.Request.Context(), orgID, scanID)
+	if err != nil {
+		h.respondScanError(c, "Failed to get scheduled scan", err)
+		return
+	}
+
+	scan.TestTypes = req.TestTypes
+	if scan.TestTypes == nil {
+		scan.TestTypes = []string{}
+	}
+	scan.CronExpression = req.CronExpression
+	scan.NotifyOnFailure = req.NotifyOnFailure
+	scan.NextRunAt = nextRunAt
+
+	if err := h.repo.UpdateScheduledScan(c.Request.Context(), scan); err != nil {
+		h.respondScanError(c, "Failed to update scheduled scan", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    scan,
+	})
+}
+
+// DeleteScheduledScan deletes a scheduled scan
+func (h *ScheduledScanHandler) DeleteScheduledScan(c *gin.Context) {
+	orgID, ok := scanOrganizationID(c)
+	if !ok {
+		return
+	}
+
+	scanID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scan ID"})
+		return
+	}
+
+	if err := h.repo.DeleteScheduledScan(c.Request.Context(), orgID, scanID); err != nil {
+		h.respondScanError(c, "Failed to delete scheduled scan", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Scheduled scan deleted",
+	})
+}
+
+// respondScanError writes a 404 for missing scans and a 500 otherwise
+func (h *ScheduledScanHandler) respondScanError(c *gin.Context, message string, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scheduled scan not found"})
+		return
+	}
+	h.logger.Error(message, zap.Error(err))
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
+
+// validateScheduledScanRequest validates the test types and cron expression and
+// returns the first run time
+func validateScheduledScanRequest(c *gin.Context, req *ScheduledScanRequest) (time.Time, bool) {
+	if err := validateScanTestTypes(req.TestTypes); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return time.Time{}, false
+	}
+
+	nextRunAt, err := scheduler.NextCronRun(req.CronExpression, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cron expression: " + err.Error()})
+		return time.Time{}, false
+	}
+
+	return nextRunAt, true
+}
+
+// scanOrganizationID returns the caller's organization
+func scanOrganizationID(c *gin.Context) (uuid.UUID, bool) {
+	orgID, exists := c.Get("organization_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return uuid.Nil, false
+	}
+
+	orgUUID, ok := orgID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID type"})
+		return uuid.Nil, false
+	}
+
+	return orgUUID, true
+}
@@ -0,0 +1,303 @@
+package security
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"go.uber.org/zap"
+)
+
+// Scorecard signal weights
+const (
+	owaspSignalWeight      = 0.4
+	behavioralSignalWeight = 0.2
+	tlsSignalWeight        = 0.2
+	credentialSignalWeight = 0.2
+)
+
+// Scorecard settings
+const (
+	scorecardCacheTTL        = time.Hour
+	scorecardBehaviorWindow  = 7 * 24 * time.Hour
+	scorecardAnomalyPenalty  = 15
+	scorecardTLSTimeout      = 5 * time.Second
+	scorecardCertExpiryAlarm = 14 * 24 * time.Hour
+)
+
+// ScorecardSignal is one weighted input to a security scorecard. Score is nil when the
+// signal could not be measured; its weight is then spread over the other signals.
+type ScorecardSignal struct {
+	Name    string  `json:"name"`
+	Weight  float64 `json:"weight"`
+	Score   *int    `json:"score,omitempty"`
+	Details string  `json:"details"`
+}
+
+// SecurityScorecard aggregates a server's security signals into a 0-100 score and grade
+type SecurityScorecard struct {
+	ServerID       uuid.UUID          `json:"server_id"`
+	ServerName     string             `json:"server_name"`
+	OrganizationID uuid.UUID          `json:"organization_id"`
+	Score          int                `json:"score"`
+	Grade          string             `json:"grade"`
+	Signals        []*ScorecardSignal `json:"signals"`
+	ComputedAt     time.Time          `json:"computed_at"`
+}
+
+// SecurityScorecardService computes security scorecards and caches them for an hour
+type SecurityScorecardService struct {
+	repo    *database.Repository
+	scanner *CredentialScanner
+	logger  *zap.Logger
+
+	mu    sync.Mutex
+	cache map[uuid.UUID]*SecurityScorecard
+}
+
+// NewSecurityScorecardService creates a security scorecard service
+func NewSecurityScorecardService(repo *database.Repository, logger *zap.Logger) *SecurityScorecardService {
+	return &SecurityScorecardService{
+		repo:    repo,
+		scanner: NewCredentialScanner(),
+		logger:  logger,
+		cache:   make(map[uuid.UUID]*SecurityScorecard),
+	}
+}
+
+// Compute returns a server's scorecard, computing it if the cached one is missing or
+// older than an hour. Security tests count for 40% of the score, behavioral anomalies,
+// TLS and credential exposure in tool definitions for 20% each. sql.ErrNoRows is
+// returned if the server belongs to another organization.
+func (s *SecurityScorecardService) Compute(ctx context.Context, organizationID, serverID uuid.UUID) (*SecurityScorecard, error) {
+	s.mu.Lock()
+	cached, exists := s.cache[serverID]
+	s.mu.Unlock()
+	if exists && cached.OrganizationID == organizationID && time.Since(cached.ComputedAt) < scorecardCacheTTL {
+		return cached, nil
+	}
+
+	server, err := s.repo.GetMCPServerByID(ctx, organizationID, serverID)
+	if err != nil {
+		return nil, err
+	}
+
+	owasp, err := s.owaspSignal(ctx, organizationID, serverID)
+	if err != nil {
+		return nil, err
+	}
+	behavioral, err := s.behavioralSignal(ctx, organizationID, serverID)
+	if err != nil {
+		return nil, err
+	}
+	credentials, err := s.credentialSignal(ctx, organizationID, serverID)
+	if err != nil {
+		return nil, err
+	}
+
+	scorecard := &SecurityScorecard{
+		ServerID:       server.ID,
+		ServerName:     server.Name,
+		OrganizationID: server.OrganizationID,
+		Signals:        []*ScorecardSignal{owasp, behavioral, tlsSignal(ctx, server.URL), credentials},
+		ComputedAt:     time.Now(),
+	}
+	scorecard.Score, scorecard.Grade = scoreSignals(scorecard.Signals)
+
+	s.logger.Debug("Computed security scorecard",
+		zap.String("server_id", serverID.String()),
+		zap.Int("score", scorecard.Score),
+		zap.String("grade", scorecard.Grade))
+
+	s.mu.Lock()
+	s.cache[serverID] = scorecard
+	s.mu.Unlock()
+
+	return scorecard, nil
+}
+
+// owaspSignal scores the latest result of each security test type run against the server
+func (s *SecurityScorecardService) owaspSignal(ctx context.Context, organizationID, serverID uuid.UUID) (*ScorecardSignal, error) {
+	signal := &ScorecardSignal{Name: "owasp", Weight: owaspSignalWeight}
+
+	tests, err := s.repo.ListLatestServerSecurityTests(ctx, organizationID, serverID)
+	if err != nil {
+		return nil, err
+	}
+	if len(tests) == 0 {
+		signal.Details = "No completed security tests"
+		return signal, nil
+	}
+
+	total, passed := 0, 0
+	for _, test := range tests {
+		score := 0
+		switch {
+		case test.Score != nil:
+			score = *test.Score
+		case test.Result != nil && *test.Result == "pass":
+			score = 100
+		case test.Result != nil && *test.Result == "warning":
+			score = 50
+		}
+		if test.Result != nil && *test.Result == "pass" {
+			passed++
+		}
+		total += score
+	}
+
+	score := total / len(tests)
+	signal.Score = &score
+	signal.Details = fmt.Sprintf("%d of %d security tests passed", passed, len(tests))
+	return signal, nil
+}
+
+// behavioralSignal deducts points for each behavioral anomaly alert raised for the server
+// in the last week
+func (s *SecurityScorecardService) behavioralSignal(ctx context.Context, organizationID, serverID uuid.UUID) (*ScorecardSignal, error) {
+	count, err := s.repo.CountServerAlertsSince(ctx, organizationID, serverID, "security", time.Now().Add(-scorecardBehaviorWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	score := 100 - count*scorecardAnomalyPenalty
+	if score < 0 {
+		score = 0
+	}
+
+	return &ScorecardSignal{
+		Name:    "behavioral",
+		Weight:  behavioralSignalWeight,
+		Score:   &score,
+		Details: fmt.Sprintf("%d behavioral anomaly alerts in the last 7 days", count),
+	}, nil
+}
+
+// credentialSignal scans the server's tool names, descriptions and input schemas for
+// exposed credentials
+func (s *SecurityScorecardService) credentialSignal(ctx context.Context, organizationID, serverID uuid.UUID) (*ScorecardSignal, error) {
+	signal := &ScorecardSignal{Name: "credential_scan", Weight: credentialSignalWeight}
+
+	tools, err := s.repo.ListServerToolDefinitions(ctx, organizationID, serverID)
+	if err != nil {
+		return nil, err
+	}
+	if len(tools) == 0 {
+		signal.Details = "No tool definitions to scan"
+		return signal, nil
+	}
+
+	exposures, risk := 0, 0
+	for _, tool := range tools {
+		text := tool.Name
+		if tool.Description != nil {
+			text += " " + *tool.Description
+		}
+		if schema, err := json.Marshal(tool.InputSchema); err == nil {
+			text += " " + string(schema)
+		}
+
+		result := s.scanner.ScanText(text)
+		exposures += len(result.Exposures)
+		risk += result.RiskScore
+	}
+
+	score := 100 - risk
+	if score < 0 {
+		score = 0
+	}
+	signal.Score = &score
+	signal.Details = fmt.Sprintf("%d potential credential exposures in %d tool definitions", exposures, len(tools))
+	return signal, nil
+}
+
+// tlsSignal checks that the server is served over TLS with a valid certificate that is not
+// about to expire. Servers without a network URL are not scored.
+func tlsSignal(ctx context.Context, serverURL string) *ScorecardSignal {
+	signal := &ScorecardSignal{Name: "tls", Weight: tlsSignalWeight}
+	score := func(value int, details string) *ScorecardSignal {
+		signal.Score = &value
+		signal.Details = details
+		return signal
+	}
+
+	parsed, err := url.Parse(serverURL)
+	if err != nil || parsed.Hostname() == "" {
+		signal.Details = "Server has no network URL"
+		return signal
+	}
+
+	switch parsed.Scheme {
+	case "http", "ws":
+		return score(0, "Server does not use TLS")
+	case "https", "wss":
+	default:
+		signal.Details = fmt.Sprintf("TLS not checked for scheme %q", parsed.Scheme)
+		return signal
+	}
+
+	port := parsed.Port()
+	if port == "" {
+		port = "443"
+	}
+
+	dialer := &tls.Dialer{NetDialer: &net.Dialer{Timeout: scorecardTLSTimeout}}
+	dialCtx, cancel := context.WithTimeout(ctx, scorecardTLSTimeout)
+	defer cancel()
+
+	conn, err := dialer.DialContext(dialCtx, "tcp", net.JoinHostPort(parsed.Hostname(), port))
+	if err != nil {
+		return score(0, fmt.Sprintf("TLS handshake failed: %v", err))
+	}
+	defer conn.Close()
+
+	certs := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return score(0, "Server presented no certificate")
+	}
+
+	remaining := time.Until(certs[0].NotAfter)
+	if remaining < scorecardCertExpiryAlarm {
+		return score(60, fmt.Sprintf("Certificate expires in %d days", int(remaining.Hours()/24)))
+	}
+
+	return score(100, fmt.Sprintf("Valid certificate until %s", certs[0].NotAfter.Format("2006-01-02")))
+}
+
+// scoreSignals combines the measured signals by weight, spreading the weight of
+// unmeasured ones proportionally, and returns the score and its letter grade
+func scoreSignals(signals []*ScorecardSignal) (int, string) {
+	var weighted, weights float64
+	for _, signal := range signals {
+		if signal.Score == nil {
+			continue
+		}
+		weighted += float64(*signal.Score) * signal.Weight
+		weights += signal.Weight
+	}
+	if weights == 0 {
+		return 0, "N/A"
+	}
+
+	score := int(math.Round(weighted / weights))
+	switch {
+	case score >= 90:
+		return score, "A"
+	case score >= 80:
+		return score, "B"
+	case score >= 70:
+		return score, "C"
+	case score >= 60:
+		return score, "D"
+	default:
+		return score, "F"
+	}
+}
@@ -0,0 +1,60 @@
+package security
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ScorecardHandler serves security scorecards
+type ScorecardHandler struct {
+	scorecards *SecurityScorecardService
+	logger     *zap.Logger
+}
+
+// NewScorecardHandler creates a security scorecard handler
+func NewScorecardHandler(scorecards *SecurityScorecardService, logger *zap.Logger) *ScorecardHandler {
+	return &ScorecardHandler{
+		scorecards: scorecards,
+		logger:     logger,
+	}
+}
+
+// RegisterRoutes registers security scorecard routes
+func (h *ScorecardHandler) RegisterRoutes(r *gin.RouterGroup) {
+	r.GET("/security/scorecard/:serverID", h.GetScorecard)
+}
+
+// GetScorecard returns the security scorecard of one of the organization's servers
+func (h *ScorecardHandler) GetScorecard(c *gin.Context) {
+	orgID, ok := scanOrganizationID(c)
+	if !ok {
+		return
+	}
+
+	serverID, err := uuid.Parse(c.Param("serverID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid server ID"})
+		return
+	}
+
+	scorecard, err := h.scorecards.Compute(c.Request.Context(), orgID, serverID)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Server not found"})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to compute security scorecard", zap.String("server_id", serverID.String()), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute security scorecard"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    scorecard,
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
@@ -147,6 +148,31 @@ func (st *SecurityTester) RunSecurityTest(ctx context.Context, serverURL, testTy
 	return test, nil
 }
 
+// RunAllTests runs every security test type against an MCP server
+func (st *SecurityTester) RunAllTests(ctx context.Context, serverURL string) ([]*SecurityTest, error) {
+	testTypes := make([]string, 0, len(TestTypes))
+	for testType := range TestTypes {
+		testTypes = append(testTypes, testType)
+	}
+	sort.Strings(testTypes)
+
+	return st.RunTests(ctx, serverURL, testTypes)
+}
+
+// RunTests runs the given security test types against an MCP server
+func (st *SecurityTester) RunTests(ctx context.Context, serverURL string, testTypes []string) ([]*SecurityTest, error) {
+	tests := make([]*SecurityTest, 0, len(testTypes))
+	for _, testType := range testTypes {
+		test, err := st.RunSecurityTest(ctx, serverURL, testType)
+		if err != nil {
+			return tests, err
+		}
+		tests = append(tests, test)
+	}
+
+	return tests, nil
+}
+
 // testInjection tests for injection vulnerabilities
 func (st *SecurityTester) testInjection(ctx context.Context, serverURL string, test *SecurityTest) error {
 	// Test SQL injection patterns
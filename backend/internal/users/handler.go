@@ -0,0 +1,93 @@
+package users
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/auth"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"go.uber.org/zap"
+)
+
+// Handler handles user endpoints
+type Handler struct {
+	repo   *database.Repository
+	logger *zap.Logger
+}
+
+// NewHandler creates a new users handler
+func NewHandler(repo *database.Repository, logger *zap.Logger) *Handler {
+	return &Handler{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// RegisterRoutes registers user routes
+func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
+	users := router.Group("/users")
+	{
+		users.GET("/me/preferences", h.GetPreferences)
+		users.PUT("/me/preferences", h.UpdatePreferences)
+	}
+}
+
+// GetPreferences returns the caller's preferences
+func (h *Handler) GetPreferences(c *gin.Context) {
+	userID, ok := auth.AuthenticatedUserID(c, h.repo)
+	if !ok {
+		return
+	}
+
+	preferences, err := h.repo.GetUserPreferences(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		h.logger.Error("Failed to get user preferences", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    preferences,
+	})
+}
+
+// UpdatePreferences replaces the caller's preferences
+func (h *Handler) UpdatePreferences(c *gin.Context) {
+	userID, ok := auth.AuthenticatedUserID(c, h.repo)
+	if !ok {
+		return
+	}
+
+	preferences := database.DefaultUserPreferences()
+	if err := c.ShouldBindJSON(&preferences); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := preferences.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.UpdateUserPreferences(c.Request.Context(), userID, preferences); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		h.logger.Error("Failed to update user preferences", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    preferences,
+	})
+}
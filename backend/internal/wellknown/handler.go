@@ -0,0 +1,268 @@
+package wellknown
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"go.uber.org/zap"
+)
+
+// ServersCacheTTL is how long the list of servers in the discovery document is reused
+const ServersCacheTTL = 30 * time.Second
+
+// activityStreamsContext is the JSON-LD context of the discovery document, as used by
+// ActivityPub servers
+const activityStreamsContext = "https://www.w3.org/ns/activitystreams"
+
+// Link is a WebFinger (RFC 7033) link
+type Link struct {
+	Rel  string `json:"rel"`
+	Type string `json:"type,omitempty"`
+	Href string `json:"href"`
+}
+
+// ServerDocument describes one online MCP server in the discovery document
+type ServerDocument struct {
+	ID           string        `json:"id"` // the server's registry entry
+	Type         string        `json:"type"`
+	Name         string        `json:"name"`
+	URL          string        `json:"url"`
+	ServerType   string        `json:"server_type"`
+	Capabilities []interface{} `json:"capabilities"`
+	Links        []Link        `json:"links"`
+}
+
+// ServersDocument is the /.well-known/mcp-servers discovery document, a WebFinger
+// resource descriptor in JSON-LD listing the sentinel's online MCP servers
+type ServersDocument struct {
+	Context     string           `json:"@context"`
+	Subject     string           `json:"subject"`
+	Links       []Link           `json:"links"`
+	TotalItems  int              `json:"totalItems"`
+	Servers     []ServerDocument `json:"servers"`
+	GeneratedAt time.Time        `json:"generated_at"`
+}
+
+// OpenIDConfiguration is OIDC discovery metadata for the sentinel's auth endpoints.
+// Endpoints the sentinel does not have are omitted.
+type OpenIDConfiguration struct {
+	Issuer                           string   `json:"issuer"`
+	UserInfoEndpoint                 string   `json:"userinfo_endpoint"`
+	JWKSURI                          string   `json:"jwks_uri,omitempty"`
+	IntrospectionEndpoint            string   `json:"introspection_endpoint,omitempty"`
+	ResponseTypesSupported           []string `json:"response_types_supported"`
+	SubjectTypesSupported            []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported []string `json:"id_token_signing_alg_values_supported"`
+	IntrospectionAuthMethods         []string `json:"introspection_endpoint_auth_methods_supported,omitempty"`
+}
+
+// AuthEndpoints are the token validation endpoints the sentinel is configured with
+type AuthEndpoints struct {
+	JWKSURI               string
+	IntrospectionEndpoint string
+}
+
+// Handler serves the public /.well-known discovery documents
+type Handler struct {
+	repo   *database.Repository
+	logger *zap.Logger
+	auth   AuthEndpoints
+
+	// publishServers enables the server list; see SetPublishServers
+	publishServers bool
+	// trustedProxies may set the scheme of the request with X-Forwarded-Proto
+	trustedProxies []*net.IPNet
+
+	mu             sync.Mutex
+	servers        []*database.MCPServer
+	serversExpires time.Time
+	serversAt      time.Time
+}
+
+// NewHandler creates a new well-known discovery handler
+func NewHandler(repo *database.Repository, logger *zap.Logger) *Handler {
+	return &Handler{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// SetAuthEndpoints sets the token validation endpoints listed in the OIDC discovery metadata
+func (h *Handler) SetAuthEndpoints(endpoints AuthEndpoints) {
+	h.auth = endpoints
+}
+
+// SetPublishServers enables the /.well-known/mcp-servers document. It lists the online
+// servers of every organization, so it is meant for single-tenant deployments; while
+// disabled the document is not found.
+func (h *Handler) SetPublishServers(publish bool) {
+	h.publishServers = publish
+}
+
+// SetTrustedProxies sets the proxies, as IP addresses or CIDR ranges, whose
+// X-Forwarded-Proto header is used for the scheme of the document URLs. The header is
+// ignored on requests from any other address.
+func (h *Handler) SetTrustedProxies(proxies []string) error {
+	networks := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip != nil && ip.To4() != nil {
+				proxy += "/32"
+			} else {
+				proxy += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		networks = append(networks, network)
+	}
+
+	h.trustedProxies = networks
+	return nil
+}
+
+// RegisterRoutes registers the discovery routes. They must be on the root router,
+// outside any authentication.
+func (h *Handler) RegisterRoutes(router gin.IRoutes) {
+	router.GET("/.well-known/mcp-servers", h.GetMCPServers)
+	router.GET("/.well-known/openid-configuration", h.GetOpenIDConfiguration)
+}
+
+// GetMCPServers returns the discovery document listing every online MCP server, if
+// publishing them is enabled
+func (h *Handler) GetMCPServers(c *gin.Context) {
+	if !h.publishServers {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+		return
+	}
+
+	servers, generatedAt, err := h.onlineServers(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to list online servers", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list MCP servers"})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=30")
+	c.JSON(http.StatusOK, buildServersDocument(h.baseURL(c), servers, generatedAt))
+}
+
+// GetOpenIDConfiguration returns OIDC discovery metadata for the sentinel
+func (h *Handler) GetOpenIDConfiguration(c *gin.Context) {
+	c.JSON(http.StatusOK, buildOpenIDConfiguration(h.baseURL(c), h.auth))
+}
+
+// onlineServers returns the online servers, reusing the last list for ServersCacheTTL
+func (h *Handler) onlineServers(ctx context.Context) ([]*database.MCPServer, time.Time, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	if h.servers != nil && now.Before(h.serversExpires) {
+		return h.servers, h.serversAt, nil
+	}
+
+	servers, err := h.repo.ListOnlineMCPServers(ctx)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	h.servers = servers
+	h.serversAt = now
+	h.serversExpires = now.Add(ServersCacheTTL)
+	return servers, now, nil
+}
+
+// buildServersDocument builds the discovery document with links under base
+func buildServersDocument(base string, servers []*database.MCPServer, generatedAt time.Time) *ServersDocument {
+	subject := base + "/.well-known/mcp-servers"
+	doc := &ServersDocument{
+		Context: activityStreamsContext,
+		Subject: subject,
+		Links: []Link{
+			{Rel: "self", Type: "application/json", Href: subject},
+			{Rel: "registry", Type: "application/json", Href: base + "/api/v1/registry/servers"},
+		},
+		TotalItems:  len(servers),
+		Servers:     make([]ServerDocument, 0, len(servers)),
+		GeneratedAt: generatedAt.UTC(),
+	}
+
+	for _, server := range servers {
+		entry := base + "/api/v1/registry/servers/" + server.ID.String()
+		capabilities := []interface{}(server.Capabilities)
+		if capabilities == nil {
+			capabilities = []interface{}{}
+		}
+		doc.Servers = append(doc.Servers, ServerDocument{
+			ID:           entry,
+			Type:         "Service",
+			Name:         server.Name,
+			URL:          server.URL,
+			ServerType:   server.Type,
+			Capabilities: capabilities,
+			Links: []Link{
+				{Rel: "self", Type: "application/json", Href: entry},
+				{Rel: "service", Href: server.URL},
+			},
+		})
+	}
+
+	return doc
+}
+
+// buildOpenIDConfiguration builds the OIDC discovery metadata with endpoints under base
+func buildOpenIDConfiguration(base string, endpoints AuthEndpoints) *OpenIDConfiguration {
+	config := &OpenIDConfiguration{
+		Issuer:                           base,
+		UserInfoEndpoint:                 base + "/api/v1/auth/me",
+		JWKSURI:                          endpoints.JWKSURI,
+		IntrospectionEndpoint:            endpoints.IntrospectionEndpoint,
+		ResponseTypesSupported:           []string{"token"},
+		SubjectTypesSupported:            []string{"public"},
+		IDTokenSigningAlgValuesSupported: []string{"RS256"},
+	}
+	if endpoints.IntrospectionEndpoint != "" {
+		config.IntrospectionAuthMethods = []string{"client_secret_basic"}
+	}
+	return config
+}
+
+// baseURL returns the scheme and host the request was made to. X-Forwarded-Proto is
+// only used on requests from a trusted proxy.
+func (h *Handler) baseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); (proto == "http" || proto == "https") && h.fromTrustedProxy(c) {
+		scheme = proto
+	}
+
+	u := url.URL{Scheme: scheme, Host: c.Request.Host}
+	return u.String()
+}
+
+// fromTrustedProxy reports whether the request's peer address is a trusted proxy
+func (h *Handler) fromTrustedProxy(c *gin.Context) bool {
+	ip := net.ParseIP(c.RemoteIP())
+	if ip == nil {
+		return false
+	}
+	for _, network := range h.trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
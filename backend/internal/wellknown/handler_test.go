@@ -0,0 +1,249 @@
+package wellknown
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/radhi1991/aran-mcp-sentinel/internal/database"
+	"go.uber.org/zap"
+)
+
+// serve sends a GET request for path to the handler's routes and decodes the JSON response
+func serve(t *testing.T, h *Handler, path string, header http.Header) (*httptest.ResponseRecorder, map[string]interface{}) {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	h.RegisterRoutes(router)
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Host = "sentinel.example.com"
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON response %s: %v", rec.Body.String(), err)
+	}
+	return rec, body
+}
+
+// keys returns the sorted keys of a JSON object
+func keys(object map[string]interface{}) []string {
+	result := make([]string, 0, len(object))
+	for key := range object {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func TestGetMCPServersSchema(t *testing.T) {
+	server := &database.MCPServer{
+		ID:           uuid.New(),
+		Name:         "filesystem",
+		URL:          "https://fs.example.com/mcp",
+		Type:         "filesystem",
+		Capabilities: database.JSONBArray{"tools", "resources"},
+	}
+	bare := &database.MCPServer{ID: uuid.New(), Name: "bare", URL: "https://bare.example.com", Type: "custom"}
+
+	// Served from the cached server list, so no database is needed
+	h := NewHandler(nil, zap.NewNop())
+	h.SetPublishServers(true)
+	if err := h.SetTrustedProxies([]string{"192.0.2.0/24"}); err != nil {
+		t.Fatalf("SetTrustedProxies() error = %v", err)
+	}
+	h.servers = []*database.MCPServer{server, bare}
+	h.serversAt = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	h.serversExpires = time.Now().Add(time.Minute)
+
+	rec, body := serve(t, h, "/.well-known/mcp-servers", http.Header{"X-Forwarded-Proto": {"https"}})
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "public, max-age=30" {
+		t.Errorf("Cache-Control = %q, want public, max-age=30", got)
+	}
+
+	wantKeys := []string{"@context", "generated_at", "links", "servers", "subject", "totalItems"}
+	if got := keys(body); !reflect.DeepEqual(got, wantKeys) {
+		t.Fatalf("document keys = %v, want %v", got, wantKeys)
+	}
+	if body["@context"] != activityStreamsContext {
+		t.Errorf("@context = %v, want %s", body["@context"], activityStreamsContext)
+	}
+	if body["subject"] != "https://sentinel.example.com/.well-known/mcp-servers" {
+		t.Errorf("subject = %v, want the document URL", body["subject"])
+	}
+	if body["totalItems"] != float64(2) {
+		t.Errorf("totalItems = %v, want 2", body["totalItems"])
+	}
+	if body["generated_at"] != "2026-01-02T03:04:05Z" {
+		t.Errorf("generated_at = %v, want the time the list was loaded", body["generated_at"])
+	}
+
+	links := body["links"].([]interface{})
+	wantLinks := []interface{}{
+		map[string]interface{}{"rel": "self", "type": "application/json", "href": "https://sentinel.example.com/.well-known/mcp-servers"},
+		map[string]interface{}{"rel": "registry", "type": "application/json", "href": "https://sentinel.example.com/api/v1/registry/servers"},
+	}
+	if !reflect.DeepEqual(links, wantLinks) {
+		t.Errorf("links = %v, want %v", links, wantLinks)
+	}
+
+	servers := body["servers"].([]interface{})
+	if len(servers) != 2 {
+		t.Fatalf("servers has %d entries, want 2", len(servers))
+	}
+	entry := "https://sentinel.example.com/api/v1/registry/servers/" + server.ID.String()
+	want := map[string]interface{}{
+		"id":           entry,
+		"type":         "Service",
+		"name":         "filesystem",
+		"url":          "https://fs.example.com/mcp",
+		"server_type":  "filesystem",
+		"capabilities": []interface{}{"tools", "resources"},
+		"links": []interface{}{
+			map[string]interface{}{"rel": "self", "type": "application/json", "href": entry},
+			map[string]interface{}{"rel": "service", "href": "https://fs.example.com/mcp"},
+		},
+	}
+	if !reflect.DeepEqual(servers[0], want) {
+		t.Errorf("servers[0] = %v, want %v", servers[0], want)
+	}
+
+	// Servers without capabilities list an empty array rather than null
+	if capabilities := servers[1].(map[string]interface{})["capabilities"]; !reflect.DeepEqual(capabilities, []interface{}{}) {
+		t.Errorf("servers[1].capabilities = %v, want []", capabilities)
+	}
+}
+
+func TestGetMCPServersEmpty(t *testing.T) {
+	h := NewHandler(nil, zap.NewNop())
+	h.SetPublishServers(true)
+	h.servers = []*database.MCPServer{}
+	h.serversExpires = time.Now().Add(time.Minute)
+
+	_, body := serve(t, h, "/.well-known/mcp-servers", nil)
+
+	if body["totalItems"] != float64(0) || !reflect.DeepEqual(body["servers"], []interface{}{}) {
+		t.Errorf("document = %v, want no servers and an empty list", body)
+	}
+	if body["subject"] != "http://sentinel.example.com/.well-known/mcp-servers" {
+		t.Errorf("subject = %v, want an http URL without X-Forwarded-Proto", body["subject"])
+	}
+}
+
+func TestGetOpenIDConfigurationSchema(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoints AuthEndpoints
+		want      map[string]interface{}
+	}{
+		{
+			name: "without token endpoints",
+			want: map[string]interface{}{
+				"issuer":                                "https://sentinel.example.com",
+				"userinfo_endpoint":                     "https://sentinel.example.com/api/v1/auth/me",
+				"response_types_supported":              []interface{}{"token"},
+				"subject_types_supported":               []interface{}{"public"},
+				"id_token_signing_alg_values_supported": []interface{}{"RS256"},
+			},
+		},
+		{
+			name: "with token endpoints",
+			endpoints: AuthEndpoints{
+				JWKSURI:               "https://clerk.example.com/.well-known/jwks.json",
+				IntrospectionEndpoint: "https://idp.example.com/oauth2/introspect",
+			},
+			want: map[string]interface{}{
+				"issuer":                                        "https://sentinel.example.com",
+				"userinfo_endpoint":                             "https://sentinel.example.com/api/v1/auth/me",
+				"jwks_uri":                                      "https://clerk.example.com/.well-known/jwks.json",
+				"introspection_endpoint":                        "https://idp.example.com/oauth2/introspect",
+				"response_types_supported":                      []interface{}{"token"},
+				"subject_types_supported":                       []interface{}{"public"},
+				"id_token_signing_alg_values_supported":         []interface{}{"RS256"},
+				"introspection_endpoint_auth_methods_supported": []interface{}{"client_secret_basic"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(nil, zap.NewNop())
+			h.SetAuthEndpoints(tt.endpoints)
+			if err := h.SetTrustedProxies([]string{"192.0.2.1"}); err != nil {
+				t.Fatalf("SetTrustedProxies() error = %v", err)
+			}
+
+			rec, body := serve(t, h, "/.well-known/openid-configuration", http.Header{"X-Forwarded-Proto": {"https"}})
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", rec.Code)
+			}
+			if !reflect.DeepEqual(body, tt.want) {
+				t.Errorf("configuration = %v, want %v", body, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetMCPServersDisabled(t *testing.T) {
+	h := NewHandler(nil, zap.NewNop())
+	h.servers = []*database.MCPServer{{ID: uuid.New(), Name: "internal", URL: "http://10.0.0.5:3000"}}
+	h.serversExpires = time.Now().Add(time.Minute)
+
+	rec, _ := serve(t, h, "/.well-known/mcp-servers", nil)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404 unless publishing servers is enabled", rec.Code)
+	}
+}
+
+func TestForwardedProtoNeedsTrustedProxy(t *testing.T) {
+	tests := []struct {
+		name    string
+		proxies []string
+		proto   string
+		want    string
+	}{
+		{"no trusted proxies", nil, "https", "http://sentinel.example.com"},
+		{"other proxy", []string{"10.0.0.0/8"}, "https", "http://sentinel.example.com"},
+		{"trusted proxy", []string{"192.0.2.1"}, "https", "https://sentinel.example.com"},
+		{"trusted proxy range", []string{"192.0.2.0/24"}, "https", "https://sentinel.example.com"},
+		{"unknown scheme", []string{"192.0.2.1"}, "javascript", "http://sentinel.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(nil, zap.NewNop())
+			if err := h.SetTrustedProxies(tt.proxies); err != nil {
+				t.Fatalf("SetTrustedProxies() error = %v", err)
+			}
+
+			// httptest requests come from 192.0.2.1
+			_, body := serve(t, h, "/.well-known/openid-configuration", http.Header{"X-Forwarded-Proto": {tt.proto}})
+
+			if body["issuer"] != tt.want {
+				t.Errorf("issuer = %v, want %s", body["issuer"], tt.want)
+			}
+		})
+	}
+
+	if err := NewHandler(nil, zap.NewNop()).SetTrustedProxies([]string{"proxy.internal"}); err == nil {
+		t.Error("SetTrustedProxies() with a hostname = nil error, want an error")
+	}
+}
@@ -208,6 +208,9 @@ echo "====================="
 print_info "Running database migrations..."
 # In a real deployment, you'd run migrations here
 # docker exec aran-mcp-backend-prod /app/migrate up
+# A database whose schema was applied by hand before migrations were
+# versioned must first be marked as current, once:
+# docker exec aran-mcp-backend-prod /app/migrate force 31
 print_status "Database migrations completed"
 
 # Smoke tests
@@ -161,7 +161,6 @@ services:
       - "5432:5432"
     volumes:
       - postgres_data_prod:/var/lib/postgresql/data
-      - ./backend/migrations:/docker-entrypoint-initdb.d
     healthcheck:
       test: ["CMD-SHELL", "pg_isready -U ${POSTGRES_USER}"]
       interval: 10s
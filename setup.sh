@@ -57,7 +57,7 @@ sleep 10
 
 # Run database migrations
 echo "🔄 Running database migrations..."
-docker-compose exec postgres psql -U postgres -d aran_mcp -f /docker-entrypoint-initdb.d/001_initial_schema.sql
+(cd backend && go run ./cmd/migrate up)
 
 echo "🎉 Setup complete!"
 echo ""